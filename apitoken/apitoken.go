@@ -0,0 +1,116 @@
+// Package apitoken 提供 sidecar 和策略管理服务共用的 API 令牌鉴权
+//
+// 令牌按摘要进行常量时间比较识别调用方；作用域对应 engine 包中的方法分组，
+// 服务按路由声明需要的作用域，令牌缺少该作用域时拒绝调用（如只读令牌不能调用变更接口）。
+package apitoken
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Scope API 令牌的作用域，对应 engine 包中的方法分组
+type Scope string
+
+const (
+	// ScopePermissionChecker 权限检查（engine.PermissionChecker）
+	ScopePermissionChecker Scope = "PermissionChecker"
+	// ScopeUserPermissionRead 用户权限和角色查询（engine.UserPermissionAdmin 的只读方法）
+	ScopeUserPermissionRead Scope = "UserPermissionAdmin.read"
+	// ScopeUserPermissionWrite 用户授权、撤销和角色分配（engine.UserPermissionAdmin 的变更方法）
+	ScopeUserPermissionWrite Scope = "UserPermissionAdmin.write"
+	// ScopeRoleRead 角色查询（engine.RoleAdministrator 的只读方法）
+	ScopeRoleRead Scope = "RoleAdministrator.read"
+	// ScopeRoleWrite 角色创建、更新、删除和设置权限（engine.RoleAdministrator 的变更方法）
+	ScopeRoleWrite Scope = "RoleAdministrator.write"
+	// ScopePolicyExport 策略导出（engine.CasbinX 的流式导出方法）
+	ScopePolicyExport Scope = "PolicyExport"
+	// ScopeAuditRead 审计日志查询（engine.CasbinX 的审计方法）
+	ScopeAuditRead Scope = "AuditLog.read"
+	// ScopeAll 全部方法分组
+	ScopeAll Scope = "*"
+)
+
+// Credential 令牌配置
+type Credential struct {
+	Name    string  // 调用方名称，用于日志和错误信息
+	Token   string  // 令牌明文
+	Subject string  // 令牌代表的操作者（不需要操作者的服务为空）
+	Scopes  []Scope // 允许调用的方法分组
+}
+
+// Token 已配置的令牌，预先计算摘要，比较时长度固定，避免泄露令牌长度
+type Token struct {
+	Name    string // 调用方名称
+	Subject string // 令牌代表的操作者
+	digest  [sha256.Size]byte
+	scopes  map[Scope]bool
+}
+
+// Allows 检查令牌是否具有作用域，具有 ScopeAll 的令牌允许调用所有方法分组
+func (t *Token) Allows(scope Scope) bool {
+	return t.scopes[ScopeAll] || (scope != "" && t.scopes[scope])
+}
+
+// Set 令牌集合
+type Set struct {
+	tokens []Token
+}
+
+// NewSet 根据配置创建令牌集合
+func NewSet(credentials []Credential) *Set {
+	set := &Set{tokens: make([]Token, 0, len(credentials))}
+	for _, credential := range credentials {
+		token := Token{
+			Name:    credential.Name,
+			Subject: credential.Subject,
+			digest:  sha256.Sum256([]byte(credential.Token)),
+			scopes:  make(map[Scope]bool, len(credential.Scopes)),
+		}
+		for _, scope := range credential.Scopes {
+			token.scopes[scope] = true
+		}
+		set.tokens = append(set.tokens, token)
+	}
+	return set
+}
+
+// Len 已配置的令牌数
+func (s *Set) Len() int {
+	return len(s.tokens)
+}
+
+// Lookup 按 Authorization: Bearer <token> 查找令牌，逐个进行常量时间比较；缺少或无法识别时返回 nil
+func (s *Set) Lookup(r *http.Request) *Token {
+	scheme, credential, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	credential = strings.TrimSpace(credential)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || credential == "" {
+		return nil
+	}
+
+	digest := sha256.Sum256([]byte(credential))
+	var found *Token
+	for i := range s.tokens {
+		if subtle.ConstantTimeCompare(digest[:], s.tokens[i].digest[:]) == 1 {
+			found = &s.tokens[i]
+		}
+	}
+	return found
+}
+
+// ValidateScopes 校验令牌的作用域：至少一个，且都是服务提供的方法分组（ScopeAll 始终可用）
+func ValidateScopes(scopes []Scope, known ...Scope) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("至少需要一个作用域")
+	}
+	for _, scope := range scopes {
+		if scope != ScopeAll && !slices.Contains(known, scope) {
+			return fmt.Errorf("未知作用域: %s", scope)
+		}
+	}
+	return nil
+}
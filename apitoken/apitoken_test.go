@@ -0,0 +1,72 @@
+package apitoken
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLookupAndAllows(t *testing.T) {
+	set := NewSet([]Credential{
+		{Name: "reader", Token: "read-token", Scopes: []Scope{ScopeUserPermissionRead}},
+		{Name: "admin", Token: "admin-token", Subject: "admin", Scopes: []Scope{ScopeAll}},
+	})
+
+	tests := []struct {
+		name      string
+		header    string
+		scope     Scope
+		wantToken string
+		wantAllow bool
+	}{
+		{name: "作用域内", header: "Bearer read-token", scope: ScopeUserPermissionRead, wantToken: "reader", wantAllow: true},
+		{name: "只读令牌不能变更", header: "Bearer read-token", scope: ScopeUserPermissionWrite, wantToken: "reader"},
+		{name: "未声明作用域时拒绝", header: "Bearer read-token", scope: "", wantToken: "reader"},
+		{name: "全部作用域", header: "bearer admin-token", scope: ScopeRoleWrite, wantToken: "admin", wantAllow: true},
+		{name: "未知令牌", header: "Bearer other"},
+		{name: "缺少令牌", header: "Bearer  "},
+		{name: "其他认证方式", header: "Basic read-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", tt.header)
+
+			token := set.Lookup(r)
+			if tt.wantToken == "" {
+				if token != nil {
+					t.Fatalf("Lookup() = %s, want nil", token.Name)
+				}
+				return
+			}
+			if token == nil || token.Name != tt.wantToken {
+				t.Fatalf("Lookup() = %v, want %s", token, tt.wantToken)
+			}
+			if got := token.Allows(tt.scope); got != tt.wantAllow {
+				t.Fatalf("Allows(%q) = %v, want %v", tt.scope, got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	known := []Scope{ScopePermissionChecker, ScopeAuditRead}
+	tests := []struct {
+		name    string
+		scopes  []Scope
+		wantErr bool
+	}{
+		{name: "已知作用域", scopes: []Scope{ScopePermissionChecker}},
+		{name: "全部作用域", scopes: []Scope{ScopeAll}},
+		{name: "缺少作用域", wantErr: true},
+		{name: "未知作用域", scopes: []Scope{ScopeRoleWrite}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateScopes(tt.scopes, known...); (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateScopes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
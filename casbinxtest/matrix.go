@@ -0,0 +1,112 @@
+// Package casbinxtest 提供下游项目固定授权行为的测试辅助工具
+//
+// 使用方式：用 Seed 描述一组策略和角色分配，构造内存中的检查器，
+// 再用 AssertAccessMatrix 按 (用户, 租户, 权限, 期望) 表逐条校验，不匹配时输出易读的差异表。
+package casbinxtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/offline"
+)
+
+// Seed 测试种子数据
+type Seed struct {
+	Roles       map[string]RoleSeed   // 角色键 -> 角色定义
+	Assignments []core.GroupingPolicy // 用户角色分配
+	Grants      []core.Policy         // 用户直接授权
+}
+
+// RoleSeed 角色定义
+type RoleSeed struct {
+	TenantKey   string            // 角色归属租户，"*" 表示全局角色
+	Permissions []core.Permission // 角色权限
+}
+
+// Case 访问矩阵中的一条用例
+type Case struct {
+	UserKey    string          // 用户标识
+	TenantKey  string          // 租户标识
+	Permission core.Permission // 被检查的权限
+	Allowed    bool            // 期望的检查结果
+}
+
+// NewChecker 根据种子数据构造内存中的检查器
+func NewChecker(seed Seed) (*offline.Checker, error) {
+	var policies []core.Policy
+	for roleKey, role := range seed.Roles {
+		tenantKey := role.TenantKey
+		if tenantKey == "" {
+			tenantKey = "*"
+		}
+		for _, permission := range role.Permissions {
+			policies = append(policies, core.Policy{
+				Type:     core.PolicyTypePermission,
+				Subject:  roleKey,
+				Domain:   tenantKey,
+				Resource: permission.Resource,
+				Action:   permission.Action,
+			})
+		}
+	}
+	policies = append(policies, seed.Grants...)
+
+	return offline.NewChecker(policies, seed.Assignments)
+}
+
+// AssertAccessMatrix 用种子数据构造检查器并校验访问矩阵，不匹配时以差异表报告测试失败
+func AssertAccessMatrix(t testing.TB, seed Seed, cases []Case) {
+	t.Helper()
+
+	checker, err := NewChecker(seed)
+	if err != nil {
+		t.Fatalf("构造检查器失败: %v", err)
+	}
+
+	AssertCheckerMatrix(t, checker, cases)
+}
+
+// AssertCheckerMatrix 对已有检查器（如从策略快照加载的检查器）校验访问矩阵
+func AssertCheckerMatrix(t testing.TB, checker *offline.Checker, cases []Case) {
+	t.Helper()
+
+	expectations := make([]offline.Expectation, 0, len(cases))
+	for _, c := range cases {
+		expectations = append(expectations, offline.Expectation{
+			UserKey:    c.UserKey,
+			TenantKey:  c.TenantKey,
+			Permission: c.Permission,
+			Allowed:    c.Allowed,
+		})
+	}
+
+	mismatches, err := checker.Verify(expectations)
+	if err != nil {
+		t.Fatalf("校验访问矩阵失败: %v", err)
+	}
+	if len(mismatches) > 0 {
+		t.Error(FormatMismatches(mismatches, len(cases)))
+	}
+}
+
+// FormatMismatches 将不匹配结果格式化为易读的差异表
+func FormatMismatches(mismatches []offline.Mismatch, total int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "访问矩阵不匹配（%d/%d 条）:\n", len(mismatches), total)
+	for _, m := range mismatches {
+		fmt.Fprintf(&b, "  - %s @ %s  %s  期望 %s，实际 %s\n",
+			m.UserKey, m.TenantKey, m.Permission, decision(m.Allowed), decision(m.Actual))
+	}
+	return b.String()
+}
+
+// decision 决策的可读表示
+func decision(allowed bool) string {
+	if allowed {
+		return "ALLOW"
+	}
+	return "DENY"
+}
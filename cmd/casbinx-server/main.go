@@ -0,0 +1,70 @@
+// casbinx-server 以独立服务运行 CasbinX 引擎，提供策略管理 REST/JSON 接口
+//
+//	casbinx-server -config /etc/casbinx/server.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rezeropoint/casbinx/engine"
+	"github.com/rezeropoint/casbinx/server"
+)
+
+func main() {
+	configPath := flag.String("config", "etc/server.json", "配置文件路径")
+	listen := flag.String("listen", "", "监听地址，覆盖配置文件中的 listen")
+	flag.Parse()
+
+	config, err := server.LoadConfig(*configPath)
+	if err != nil {
+		fatal("加载配置失败", err)
+	}
+	if *listen != "" {
+		config.Listen = *listen
+	}
+
+	client, err := engine.NewCasbinx(config.Casbinx)
+	if err != nil {
+		fatal("创建引擎失败", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:    config.Listen,
+		Handler: server.NewHandler(client, config.Authenticator()),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("casbinx-server 开始监听", "listen", config.Listen)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("服务异常退出", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("优雅退出失败", "error", err)
+	}
+	// 退出前写入缓冲中的决策日志
+	if err := client.FlushDecisionLog(); err != nil {
+		slog.Error("写入决策日志失败", "error", err)
+	}
+}
+
+// fatal 记录错误日志后退出
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,70 @@
+// casbinx-sidecar 以 sidecar 形式运行 CasbinX 引擎，在本地提供权限检查 HTTP 接口
+//
+//	casbinx-sidecar -config /etc/casbinx/sidecar.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rezeropoint/casbinx/engine"
+	"github.com/rezeropoint/casbinx/sidecar"
+)
+
+func main() {
+	configPath := flag.String("config", "etc/sidecar.json", "配置文件路径")
+	listen := flag.String("listen", "", "监听地址，覆盖配置文件中的 listen")
+	flag.Parse()
+
+	config, err := sidecar.LoadConfig(*configPath)
+	if err != nil {
+		fatal("加载配置失败", err)
+	}
+	if *listen != "" {
+		config.Listen = *listen
+	}
+
+	client, err := engine.NewCasbinx(config.Casbinx)
+	if err != nil {
+		fatal("创建引擎失败", err)
+	}
+
+	server := &http.Server{
+		Addr:    config.Listen,
+		Handler: sidecar.NewHandler(client, config.Tokens),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("casbinx-sidecar 开始监听", "listen", config.Listen)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("服务异常退出", err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("优雅退出失败", "error", err)
+	}
+	// 退出前写入缓冲中的决策日志
+	if err := client.FlushDecisionLog(); err != nil {
+		slog.Error("写入决策日志失败", "error", err)
+	}
+}
+
+// fatal 记录错误日志后退出
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// runCheck 检查用户权限，允许时输出 allow，拒绝时输出 deny 并以退出码 1 结束
+func runCheck(env *env, args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	userKey := flags.String("user", "", "用户标识（必填）")
+	tenantKey := flags.String("tenant", "", "租户标识（必填）")
+	permissionText := flags.String("permission", "", "权限，格式为 resource:action（必填）")
+	objectID := flags.String("object", "", "对象ID（对象级权限）")
+	debug := flags.Bool("debug", false, "输出评估过程（JSON）")
+	_ = flags.Parse(args)
+
+	if err := core.FirstError(
+		core.RequireArg("user", *userKey),
+		core.RequireArg("tenant", *tenantKey),
+		core.RequireArg("permission", *permissionText),
+	); err != nil {
+		return err
+	}
+	permission, err := core.ParsePermission(*permissionText)
+	if err != nil {
+		return err
+	}
+	permission.ObjectID = *objectID
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	if *debug {
+		trace, err := client.CheckPermissionDebug(*userKey, *tenantKey, permission)
+		if err != nil {
+			return err
+		}
+		if err := printJSON(os.Stdout, trace); err != nil {
+			return err
+		}
+		allowed = trace.Allowed
+	} else {
+		allowed, err = client.CheckPermission(*userKey, *tenantKey, permission)
+		if err != nil {
+			return err
+		}
+		fmt.Println(decisionText(allowed))
+	}
+
+	if !allowed {
+		return deniedError{}
+	}
+	return nil
+}
+
+// runGrant 授予用户权限（多个权限在同一事务中写入）
+func runGrant(env *env, args []string) error {
+	return runPermissionChange(env, "grant", args)
+}
+
+// runRevoke 撤销用户权限（多个权限在同一事务中写入）
+func runRevoke(env *env, args []string) error {
+	return runPermissionChange(env, "revoke", args)
+}
+
+// runPermissionChange grant 和 revoke 的共同实现
+func runPermissionChange(env *env, name string, args []string) error {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	operatorKey := flags.String("operator", "", "操作者（必填，按其权限校验）")
+	userKey := flags.String("user", "", "用户标识（必填）")
+	tenantKey := flags.String("tenant", "", "租户标识（必填）")
+	objectID := flags.String("object", "", "对象ID（对象级权限，作用于所有 -permission）")
+	var permissions permissionFlag
+	flags.Var(&permissions, "permission", "权限，格式为 resource:action（必填，可重复）")
+	_ = flags.Parse(args)
+
+	if err := core.FirstError(
+		core.RequireArg("operator", *operatorKey),
+		core.RequireArg("user", *userKey),
+		core.RequireArg("tenant", *tenantKey),
+		core.RequireItems("permission", permissions),
+	); err != nil {
+		return err
+	}
+	for i := range permissions {
+		permissions[i].ObjectID = *objectID
+	}
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+	if name == "grant" {
+		err = client.GrantPermissions(*operatorKey, *userKey, *tenantKey, permissions)
+	} else {
+		err = client.RevokePermissions(*operatorKey, *userKey, *tenantKey, permissions)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s@%s: %s\n", name, *userKey, *tenantKey, permissions.String())
+	return nil
+}
+
+// runRole 角色管理子命令
+func runRole(env *env, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: casbinx role <create|list> [参数]")
+	}
+	switch args[0] {
+	case "create":
+		return runRoleCreate(env, args[1:])
+	case "list":
+		return runRoleList(env, args[1:])
+	default:
+		return fmt.Errorf("未知的 role 子命令: %s，仅支持 create、list", args[0])
+	}
+}
+
+// runRoleCreate 创建角色
+func runRoleCreate(env *env, args []string) error {
+	flags := flag.NewFlagSet("role create", flag.ExitOnError)
+	operatorKey := flags.String("operator", "", "操作者（必填，按其权限校验）")
+	roleKey := flags.String("key", "", "角色标识（必填）")
+	roleName := flags.String("name", "", "角色名称（必填）")
+	description := flags.String("description", "", "角色描述")
+	tenantKey := flags.String("tenant", "", "角色所属租户（必填）")
+	var permissions permissionFlag
+	flags.Var(&permissions, "permission", "角色权限，格式为 resource:action（可重复）")
+	_ = flags.Parse(args)
+
+	if err := core.FirstError(
+		core.RequireArg("operator", *operatorKey),
+		core.RequireArg("key", *roleKey),
+		core.RequireArg("name", *roleName),
+		core.RequireArg("tenant", *tenantKey),
+	); err != nil {
+		return err
+	}
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+	if err := client.CreateRole(*operatorKey, *roleKey, *roleName, *description, *tenantKey, permissions); err != nil {
+		return err
+	}
+	fmt.Printf("created role %s@%s\n", *roleKey, *tenantKey)
+	return nil
+}
+
+// runRoleList 列出租户内的角色
+func runRoleList(env *env, args []string) error {
+	flags := flag.NewFlagSet("role list", flag.ExitOnError)
+	tenantKey := flags.String("tenant", "", "租户标识（必填）")
+	asJSON := flags.Bool("json", false, "以 JSON 输出")
+	_ = flags.Parse(args)
+
+	if err := core.RequireArg("tenant", *tenantKey); err != nil {
+		return err
+	}
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+	roles, err := client.ListRoles(*tenantKey, nil)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(os.Stdout, roles)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tNAME\tTENANT\tPERMISSIONS")
+	for _, role := range roles {
+		permissions := make([]string, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, permission.String())
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", role.Key, role.Name, role.TenantKey, strings.Join(permissions, ","))
+	}
+	return table.Flush()
+}
+
+// runExport 导出策略：指定租户时导出租户快照（可用 import 导入），否则导出全部策略快照（供 offline 包离线校验）
+func runExport(env *env, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	tenantKey := flags.String("tenant", "", "租户标识，为空时导出全部策略")
+	output := flags.String("out", "", "输出文件，默认标准输出")
+	_ = flags.Parse(args)
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("创建输出文件失败: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *tenantKey == "" {
+		return client.ExportPolicies(w)
+	}
+	snapshot, err := client.ExportTenantPolicies(*tenantKey)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, snapshot)
+}
+
+// runImport 导入 export -tenant 生成的租户快照，输出变更结果
+func runImport(env *env, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	operatorKey := flags.String("operator", "", "操作者（必填，需要全局租户管理权限）")
+	input := flags.String("file", "", "快照文件（必填）")
+	mode := flags.String("mode", string(core.PolicyImportMerge), "导入方式：merge 或 replace")
+	tenantKey := flags.String("tenant", "", "目标租户，为空时导入到快照的租户")
+	dryRun := flags.Bool("dry-run", false, "只校验并输出将要执行的变更")
+	_ = flags.Parse(args)
+
+	if err := core.FirstError(
+		core.RequireArg("operator", *operatorKey),
+		core.RequireArg("file", *input),
+	); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		return fmt.Errorf("读取快照文件失败: %v", err)
+	}
+	var snapshot core.PolicySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("解析快照文件失败: %v", err)
+	}
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+	result, err := client.ImportTenantPolicies(*operatorKey, snapshot, core.PolicyImportOptions{
+		Mode:      core.PolicyImportMode(*mode),
+		TenantKey: *tenantKey,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(os.Stdout, result)
+}
+
+// runTenant 租户管理子命令
+func runTenant(env *env, args []string) error {
+	if len(args) == 0 || args[0] != "init" {
+		return fmt.Errorf("用法: casbinx tenant init [参数]")
+	}
+
+	flags := flag.NewFlagSet("tenant init", flag.ExitOnError)
+	tenantKey := flags.String("tenant", "", "租户标识（必填）")
+	adminUserKey := flags.String("admin", "", "租户管理员用户（必填）")
+	adminRoleKey := flags.String("role", "", "管理员角色标识（必填）")
+	_ = flags.Parse(args[1:])
+
+	if err := core.FirstError(
+		core.RequireArg("tenant", *tenantKey),
+		core.RequireArg("admin", *adminUserKey),
+		core.RequireArg("role", *adminRoleKey),
+	); err != nil {
+		return err
+	}
+
+	client, err := env.connect()
+	if err != nil {
+		return err
+	}
+	if err := client.InitializeTenant(*tenantKey, *adminUserKey, *adminRoleKey); err != nil {
+		return err
+	}
+	fmt.Printf("initialized tenant %s (admin %s, role %s)\n", *tenantKey, *adminUserKey, *adminRoleKey)
+	return nil
+}
+
+// decisionText 检查结果的文本
+func decisionText(allowed bool) string {
+	if allowed {
+		return "allow"
+	}
+	return "deny"
+}
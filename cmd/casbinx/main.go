@@ -0,0 +1,167 @@
+// casbinx 策略检查和管理命令行工具，使用与服务相同的配置直接连接策略数据库
+//
+//	casbinx -config etc/casbinx.json check -user alice -tenant tenant1 -permission document:read
+//	casbinx -config etc/casbinx.json grant -operator admin -user alice -tenant tenant1 -permission document:write
+//	casbinx -config etc/casbinx.json role list -tenant tenant1
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/engine"
+)
+
+// exitDenied check 结果为拒绝时的退出码，便于脚本判断
+const exitDenied = 1
+
+// exitError 执行失败时的退出码
+const exitError = 2
+
+// command 子命令
+type command struct {
+	name  string
+	usage string
+	run   func(env *env, args []string) error
+}
+
+// env 子命令的执行环境，解析完参数后再连接引擎，-h 等参数错误无需连接数据库
+type env struct {
+	configPath string
+}
+
+// connect 读取配置并创建引擎
+func (e *env) connect() (engine.CasbinX, error) {
+	config, err := loadConfig(e.configPath)
+	if err != nil {
+		return nil, err
+	}
+	client, err := engine.NewCasbinx(*config)
+	if err != nil {
+		return nil, fmt.Errorf("创建引擎失败: %w", err)
+	}
+	return client, nil
+}
+
+// commands 子命令列表，按帮助信息中的顺序排列
+var commands = []command{
+	{name: "check", usage: "检查用户权限", run: runCheck},
+	{name: "grant", usage: "授予用户权限", run: runGrant},
+	{name: "revoke", usage: "撤销用户权限", run: runRevoke},
+	{name: "role", usage: "角色管理（create、list）", run: runRole},
+	{name: "export", usage: "导出策略快照", run: runExport},
+	{name: "import", usage: "导入租户策略快照", run: runImport},
+	{name: "tenant", usage: "租户管理（init）", run: runTenant},
+}
+
+func main() {
+	configPath := flag.String("config", "etc/casbinx.json", "配置文件路径（core.Config，或 sidecar/server 配置文件中的 casbinx 部分）")
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(exitError)
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			selected = &commands[i]
+		}
+	}
+	if selected == nil {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", args[0])
+		usage()
+		os.Exit(exitError)
+	}
+
+	if err := selected.run(&env{configPath: *configPath}, args[1:]); err != nil {
+		var denied deniedError
+		if errors.As(err, &denied) {
+			os.Exit(exitDenied)
+		}
+		fatal(err)
+	}
+}
+
+// usage 输出帮助信息
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: casbinx [-config 配置文件] <命令> [参数]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "使用 casbinx <命令> -h 查看命令参数")
+}
+
+// loadConfig 读取配置文件，文件顶层包含 casbinx 字段时（sidecar、server 的配置文件）使用该字段
+func loadConfig(path string) (*core.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	var wrapper struct {
+		Casbinx *core.Config `json:"casbinx"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
+	if wrapper.Casbinx != nil {
+		return wrapper.Casbinx, nil
+	}
+
+	var config core.Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
+	return &config, nil
+}
+
+// deniedError check 结果为拒绝，以退出码 1 结束
+type deniedError struct{}
+
+func (deniedError) Error() string { return "denied" }
+
+// permissionFlag 可重复的权限参数，格式为 resource:action
+type permissionFlag []core.Permission
+
+func (f *permissionFlag) String() string {
+	parts := make([]string, 0, len(*f))
+	for _, permission := range *f {
+		parts = append(parts, permission.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *permissionFlag) Set(value string) error {
+	permission, err := core.ParsePermission(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, permission)
+	return nil
+}
+
+// printJSON 以缩进格式输出 JSON
+func printJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// fatal 输出错误后以退出码 2 结束
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "错误:", err)
+	os.Exit(exitError)
+}
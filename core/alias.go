@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// DomainAliasResolver 域别名解析器
+// 返回与给定域等价的所有域（规范域及其全部别名），用于租户改名后旧键下的授权继续生效
+type DomainAliasResolver func(domain string) []string
+
+// SetDomainAliasResolver 设置域别名解析器
+func (e *Enforcer) SetDomainAliasResolver(resolver DomainAliasResolver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.domainAliasResolver = resolver
+}
+
+// equivalentDomains 获取与给定域等价的所有域，第一个元素总是给定域本身
+func (e *Enforcer) equivalentDomains(domain string) []string {
+	e.mu.RLock()
+	resolver := e.domainAliasResolver
+	e.mu.RUnlock()
+
+	if resolver == nil || domain == "" || domain == "*" {
+		return []string{domain}
+	}
+
+	domains := []string{domain}
+	for _, equivalent := range resolver(domain) {
+		if equivalent != "" && equivalent != "*" && !containsDomain(domains, equivalent) {
+			domains = append(domains, equivalent)
+		}
+	}
+	return domains
+}
+
+// containsDomain 检查域是否在列表中
+func containsDomain(domains []string, domain string) bool {
+	for _, existing := range domains {
+		if existing == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// RewriteDomain 将旧域下的所有权限策略和角色分配改写到新域
+// 新域下已存在的相同策略不会重复写入，旧域中的重复项直接删除；返回改写数量和删除的重复数量
+func (e *Enforcer) RewriteDomain(ctx context.Context, oldDomain, newDomain string) (*DomainRewriteResult, error) {
+	defer e.invalidateCache()
+	if err := FirstError(
+		RequireTenantArg("oldDomain", oldDomain),
+		RequireTenantArg("newDomain", newDomain),
+		CheckArg(oldDomain != newDomain, "newDomain", ConstraintDistinct),
+	); err != nil {
+		return nil, err
+	}
+	if err := e.checkDomainWrite(newDomain); err != nil {
+		return nil, err
+	}
+
+	result := &DomainRewriteResult{}
+
+	allPolicies, err := e.enforcer.GetPolicy()
+	if err != nil {
+		return nil, err
+	}
+	var oldPolicies, newPolicies, duplicatePolicies [][]string
+	for _, policy := range allPolicies {
+		if len(policy) < 4 || policy[1] != oldDomain {
+			continue
+		}
+		rewritten := append([]string{}, policy...)
+		rewritten[1] = newDomain
+		exists, err := e.enforcer.HasPolicy(rewritten)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			duplicatePolicies = append(duplicatePolicies, policy)
+			continue
+		}
+		oldPolicies = append(oldPolicies, policy)
+		newPolicies = append(newPolicies, rewritten)
+	}
+
+	allGroupings, err := e.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return nil, err
+	}
+	var oldGroupings, newGroupings, duplicateGroupings [][]string
+	for _, grouping := range allGroupings {
+		if len(grouping) < 3 || grouping[2] != oldDomain {
+			continue
+		}
+		rewritten := append([]string{}, grouping...)
+		rewritten[2] = newDomain
+		exists, err := e.enforcer.HasGroupingPolicy(rewritten)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			duplicateGroupings = append(duplicateGroupings, grouping)
+			continue
+		}
+		oldGroupings = append(oldGroupings, grouping)
+		newGroupings = append(newGroupings, rewritten)
+	}
+
+	if len(oldPolicies) > 0 {
+		if err := e.replaceRules(ctx, PolicyTypePermission, oldPolicies, newPolicies); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(oldGroupings) > 0 {
+		if err := e.replaceRules(ctx, PolicyTypeGrouping, oldGroupings, newGroupings); err != nil {
+			if len(oldPolicies) > 0 {
+				if rollbackErr := e.replaceRules(ctx, PolicyTypePermission, newPolicies, oldPolicies); rollbackErr != nil {
+					return nil, fmt.Errorf("改写角色分配失败: %v，且回滚权限策略失败: %v", err, rollbackErr)
+				}
+			}
+			return nil, err
+		}
+	}
+
+	// 新域下已存在相同授权，旧域中的重复项直接删除
+	if len(duplicatePolicies) > 0 {
+		if err := e.deleteRules(ctx, PolicyTypePermission, duplicatePolicies); err != nil {
+			return nil, err
+		}
+	}
+	if len(duplicateGroupings) > 0 {
+		if err := e.deleteRules(ctx, PolicyTypeGrouping, duplicateGroupings); err != nil {
+			return nil, err
+		}
+	}
+
+	result.RewrittenPolicies = len(oldPolicies)
+	result.RewrittenGroupings = len(oldGroupings)
+	result.RemovedDuplicates = len(duplicatePolicies) + len(duplicateGroupings)
+	return result, nil
+}
+
+// RemoveDomain 移除域下的所有权限策略和角色分配，返回移除的权限策略数量和角色分配数量
+func (e *Enforcer) RemoveDomain(ctx context.Context, domain string) (int, int, error) {
+	defer e.invalidateCache()
+	// 空域在过滤查询中表示不限制，* 为全局域，均不允许整体移除
+	if err := RequireTenantArg("domain", domain); err != nil {
+		return 0, 0, err
+	}
+
+	policies, err := e.enforcer.GetFilteredPolicy(1, domain)
+	if err != nil {
+		return 0, 0, err
+	}
+	removed, err := e.removeRules(ctx, policies)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	groupings, err := e.enforcer.GetFilteredGroupingPolicy(2, domain)
+	if err != nil {
+		return len(removed), 0, err
+	}
+	if len(groupings) > 0 {
+		if err := e.deleteRules(ctx, PolicyTypeGrouping, groupings); err != nil {
+			return len(removed), 0, err
+		}
+	}
+
+	return len(removed), len(groupings), nil
+}
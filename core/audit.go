@@ -0,0 +1,34 @@
+package core
+
+import "time"
+
+// 审计日志记录的变更类型（PermissionChange.Action）
+var (
+	AuditActionGrant  = Action("grant")  // 授予用户权限
+	AuditActionRevoke = Action("revoke") // 撤销用户权限
+	AuditActionAssign = Action("assign") // 为用户分配角色
+	AuditActionRemove = Action("remove") // 移除用户角色
+	AuditActionCreate = Action("create") // 创建角色
+	AuditActionUpdate = Action("update") // 更新角色名称或描述
+	AuditActionDelete = Action("delete") // 删除角色
+
+	AuditActionRoleGrant  = Action("role_grant")  // 授予角色权限（Target 为 角色>权限）
+	AuditActionRoleRevoke = Action("role_revoke") // 撤销角色权限（Target 同 role_grant）
+
+	AuditActionInherit    = Action("inherit")    // 添加角色继承（Target 为 父角色>子角色）
+	AuditActionDisinherit = Action("disinherit") // 移除角色继承（Target 为 父角色>子角色）
+
+	AuditActionDeny   = Action("deny")   // 添加拒绝规则（Target 为权限；角色的拒绝规则 Target 为 角色>权限）
+	AuditActionUndeny = Action("undeny") // 移除拒绝规则（Target 同 deny）
+)
+
+// AuditFilter 审计日志查询条件，零值字段不参与过滤
+type AuditFilter struct {
+	UserKey     string    `json:"userKey"`     // 被操作的用户标识
+	TenantKey   string    `json:"tenantKey"`   // 租户标识，为空表示所有租户（需要全局权限）
+	OperatorKey string    `json:"operatorKey"` // 操作者用户标识
+	Action      Action    `json:"action"`      // 变更类型
+	Target      string    `json:"target"`      // 操作目标（权限字符串或角色标识）
+	Since       time.Time `json:"since"`       // 起始时间（包含）
+	Until       time.Time `json:"until"`       // 截止时间（不包含）
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"sync"
+)
+
+// DefaultDecisionCacheSize 默认权限检查缓存容量
+const DefaultDecisionCacheSize = 10000
+
+// DecisionCache 权限检查结果缓存
+// 键由主体、域和权限组成；任何策略写入、策略重新加载或附属状态同步都会整体清空缓存
+type DecisionCache interface {
+	Get(key string) (allowed bool, found bool) // 获取缓存的检查结果
+	Set(key string, allowed bool)              // 写入检查结果
+	Clear()                                    // 清空缓存
+}
+
+// memoryDecisionCache 内存权限检查缓存，达到容量上限时整体清空
+type memoryDecisionCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[string]bool
+}
+
+// NewMemoryDecisionCache 创建内存权限检查缓存，size 为 0 时使用默认容量
+func NewMemoryDecisionCache(size int) DecisionCache {
+	if size <= 0 {
+		size = DefaultDecisionCacheSize
+	}
+	return &memoryDecisionCache{
+		size:    size,
+		entries: make(map[string]bool),
+	}
+}
+
+// Get 获取缓存的检查结果
+func (c *memoryDecisionCache) Get(key string) (bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	allowed, found := c.entries[key]
+	return allowed, found
+}
+
+// Set 写入检查结果
+func (c *memoryDecisionCache) Set(key string, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.size {
+		c.entries = make(map[string]bool)
+	}
+	c.entries[key] = allowed
+}
+
+// Clear 清空缓存
+func (c *memoryDecisionCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]bool)
+}
+
+// SetDecisionCache 设置权限检查结果缓存，传入 nil 表示关闭缓存
+func (e *Enforcer) SetDecisionCache(cache DecisionCache) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.decisionCache = cache
+}
+
+// cachedDecision 获取缓存的检查结果
+func (e *Enforcer) cachedDecision(key string) (bool, bool) {
+	e.mu.RLock()
+	cache := e.decisionCache
+	e.mu.RUnlock()
+
+	if cache == nil {
+		return false, false
+	}
+	allowed, found := cache.Get(key)
+	e.Metrics().ObserveCache(CacheDecision, found)
+	return allowed, found
+}
+
+// cacheDecision 写入检查结果
+func (e *Enforcer) cacheDecision(key string, allowed bool) {
+	e.mu.RLock()
+	cache := e.decisionCache
+	e.mu.RUnlock()
+
+	if cache != nil {
+		cache.Set(key, allowed)
+	}
+}
+
+// invalidateCache 清空权限检查结果缓存和权限集合缓存（策略或附属状态变更后调用）
+func (e *Enforcer) invalidateCache() {
+	e.revision.Add(1)
+
+	e.mu.RLock()
+	cache := e.decisionCache
+	permissionCache := e.permissionCache
+	e.mu.RUnlock()
+
+	if cache != nil {
+		cache.Clear()
+	}
+	if permissionCache != nil {
+		permissionCache.Clear()
+	}
+}
+
+// decisionCacheKey 生成权限检查缓存键
+func decisionCacheKey(subject, domain string, permission Permission) string {
+	return subject + "\x00" + domain + "\x00" + string(permission.QualifiedResource()) + "\x00" + string(permission.Action)
+}
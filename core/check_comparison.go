@@ -0,0 +1,148 @@
+package core
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// CheckImplementation 权限检查的实现
+type CheckImplementation string
+
+const (
+	CheckImplementationCustom CheckImplementation = "custom" // 自定义实现：枚举隐式权限后比较（含跨域角色继承、通配符、写操作隐含、规则过滤），默认
+	CheckImplementationNative CheckImplementation = "native" // Casbin 原生 Enforce（按模型匹配器求值，自定义模型的通配符、匹配函数等生效）
+)
+
+// 影子比较结果（MetricsRecorder.ObserveCheckComparison 的 result 参数）
+const (
+	CheckComparisonMatch       = "match"        // 两种实现结果一致
+	CheckComparisonDiverged    = "diverged"     // 两种实现结果不一致
+	CheckComparisonShadowError = "shadow_error" // 影子实现返回错误
+)
+
+// CheckComparisonConfig 权限检查实现的比较配置
+// 用于从自定义实现切换到 Casbin 原生 Enforce 前评估行为差异：影子模式下两种实现都执行，
+// 返回 Primary 的结果，结果不一致时通过日志、指标和回调报告
+type CheckComparisonConfig struct {
+	// Primary 返回给调用方的实现，默认 custom
+	Primary CheckImplementation `json:"primary"`
+
+	// Shadow 是否同时执行另一种实现并比较结果（影子实现的错误不影响返回结果）
+	Shadow bool `json:"shadow"`
+
+	// SampleRate 影子比较的采样率，取值 (0, 1]，默认 1（每次检查都比较）
+	SampleRate float64 `json:"sampleRate"`
+}
+
+// Validate 校验比较配置
+func (c CheckComparisonConfig) Validate() error {
+	switch c.Primary {
+	case "", CheckImplementationCustom, CheckImplementationNative:
+	default:
+		return NewFieldError("checkComparison.primary", ConstraintOneOf)
+	}
+	return CheckArg(c.SampleRate >= 0 && c.SampleRate <= 1, "checkComparison.sampleRate", ConstraintRange)
+}
+
+// CheckDivergence 两种实现结果不一致（或影子实现出错）的一次检查
+type CheckDivergence struct {
+	UserKey        string              `json:"userKey"`               // 被检查的用户
+	TenantKey      string              `json:"tenantKey"`             // 被检查的租户
+	Permission     Permission          `json:"permission"`            // 被检查的权限
+	Primary        CheckImplementation `json:"primary"`               // 返回结果的实现
+	PrimaryAllowed bool                `json:"primaryAllowed"`        // 主实现的结果
+	ShadowAllowed  bool                `json:"shadowAllowed"`         // 影子实现的结果
+	ShadowError    string              `json:"shadowError,omitempty"` // 影子实现的错误
+	CheckedAt      time.Time           `json:"checkedAt"`             // 检查时间
+}
+
+// CheckDivergenceHook 检查结果不一致时的回调，在检查协程中同步调用，实现方不应阻塞
+type CheckDivergenceHook func(divergence CheckDivergence)
+
+// SetCheckComparison 设置权限检查实现和影子比较
+func (e *Enforcer) SetCheckComparison(config CheckComparisonConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if config.Primary == "" {
+		config.Primary = CheckImplementationCustom
+	}
+	if config.SampleRate == 0 {
+		config.SampleRate = 1
+	}
+	// 原生 Enforce 作为主实现时，模型匹配器必须可以求值，避免每次检查都返回错误
+	if config.Primary == CheckImplementationNative {
+		if err := e.ValidateNativeModel(); err != nil {
+			return err
+		}
+	}
+
+	defer e.invalidateCache()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.checkComparison = config
+	return nil
+}
+
+// AddCheckDivergenceHook 注册检查结果不一致时的回调
+func (e *Enforcer) AddCheckDivergenceHook(hook CheckDivergenceHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.divergenceHooks = append(e.divergenceHooks, hook)
+}
+
+// compareCheck 按配置执行主实现，影子模式下再执行另一种实现并报告差异
+func (e *Enforcer) compareCheck(userKey, domain string, permission Permission) (bool, error) {
+	e.mu.RLock()
+	config := e.checkComparison
+	hooks := e.divergenceHooks
+	e.mu.RUnlock()
+
+	primary, shadow := config.Primary, CheckImplementationNative
+	if primary == CheckImplementationNative {
+		shadow = CheckImplementationCustom
+	}
+
+	allowed, err := e.checkWith(primary, userKey, domain, permission)
+	if err != nil || !config.Shadow || (config.SampleRate < 1 && rand.Float64() >= config.SampleRate) {
+		return allowed, err
+	}
+
+	shadowAllowed, shadowErr := e.checkWith(shadow, userKey, domain, permission)
+	result := CheckComparisonMatch
+	switch {
+	case shadowErr != nil:
+		result = CheckComparisonShadowError
+	case shadowAllowed != allowed:
+		result = CheckComparisonDiverged
+	}
+	e.Metrics().ObserveCheckComparison(result)
+	if result == CheckComparisonMatch {
+		return allowed, nil
+	}
+
+	divergence := CheckDivergence{
+		UserKey:        userKey,
+		TenantKey:      domain,
+		Permission:     permission,
+		Primary:        primary,
+		PrimaryAllowed: allowed,
+		ShadowAllowed:  shadowAllowed,
+		CheckedAt:      time.Now(),
+	}
+	if shadowErr != nil {
+		divergence.ShadowError = shadowErr.Error()
+	}
+	for _, hook := range hooks {
+		hook(divergence)
+	}
+	return allowed, nil
+}
+
+// checkWith 使用指定实现检查权限
+func (e *Enforcer) checkWith(implementation CheckImplementation, userKey, domain string, permission Permission) (bool, error) {
+	if implementation == CheckImplementationNative {
+		return e.enforcer.Enforce(e.UserSubject(userKey), domain, string(permission.QualifiedResource()), string(permission.Action))
+	}
+	return e.customCheck(userKey, domain, permission)
+}
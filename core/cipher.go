@@ -0,0 +1,135 @@
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// encryptedFieldPrefix 加密字段的前缀，格式为 enc:v1:<密钥ID>:<base64(nonce+密文)>
+const encryptedFieldPrefix = "enc:v1:"
+
+// KeyProvider 字段加密密钥提供者（如 KMS 的封装）
+// 密钥为 32 字节的 AES-256 密钥；轮换密钥时 CurrentKey 返回新密钥，旧密钥仍需能通过 Key 取得以解密存量数据
+type KeyProvider interface {
+	CurrentKey() (keyID string, key []byte, err error) // 当前用于加密的密钥
+	Key(keyID string) ([]byte, error)                  // 按密钥ID获取解密密钥
+}
+
+// StaticKeyProvider 固定密钥提供者，适用于从配置或环境变量读取密钥的场景
+type StaticKeyProvider struct {
+	CurrentKeyID string            // 当前加密使用的密钥ID
+	Keys         map[string][]byte // 密钥ID -> 密钥
+}
+
+// CurrentKey 返回当前密钥
+func (p StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	key, err := p.Key(p.CurrentKeyID)
+	return p.CurrentKeyID, key, err
+}
+
+// Key 按密钥ID获取密钥
+func (p StaticKeyProvider) Key(keyID string) ([]byte, error) {
+	key, exists := p.Keys[keyID]
+	if !exists {
+		return nil, fmt.Errorf("%w: 密钥 '%s' 不存在", ErrEncryptionKeyUnavailable, keyID)
+	}
+	return key, nil
+}
+
+// FieldCipher 敏感字段加密器（AES-256-GCM）
+// 未配置密钥提供者时（包括 nil 接收者）原样读写；未带加密前缀的存量明文可以直接读取，
+// 因此启用加密后无需迁移，字段在下次写入时加密
+type FieldCipher struct {
+	provider KeyProvider
+}
+
+// NewFieldCipher 创建敏感字段加密器
+func NewFieldCipher(provider KeyProvider) *FieldCipher {
+	return &FieldCipher{provider: provider}
+}
+
+// Enabled 是否启用了字段加密
+func (c *FieldCipher) Enabled() bool {
+	return c != nil && c.provider != nil
+}
+
+// Encrypt 加密字段，空字符串保持为空
+func (c *FieldCipher) Encrypt(plaintext string) (string, error) {
+	if !c.Enabled() || plaintext == "" {
+		return plaintext, nil
+	}
+
+	keyID, key, err := c.provider.CurrentKey()
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(keyID, ":") {
+		return "", fmt.Errorf("%w: 密钥ID不能包含 ':'", ErrEncryptionKeyUnavailable)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %v", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(keyID))
+	return encryptedFieldPrefix + keyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密字段，未带加密前缀的值按明文返回
+func (c *FieldCipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedFieldPrefix) {
+		return value, nil
+	}
+	if !c.Enabled() {
+		return "", fmt.Errorf("%w: 字段已加密但未配置密钥提供者", ErrEncryptionKeyUnavailable)
+	}
+
+	keyID, encoded, found := strings.Cut(strings.TrimPrefix(value, encryptedFieldPrefix), ":")
+	if !found {
+		return "", fmt.Errorf("加密字段格式无效")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("加密字段格式无效: %v", err)
+	}
+
+	key, err := c.provider.Key(keyID)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("加密字段格式无效")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("解密字段失败: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// newAEAD 创建 AES-256-GCM 加密器
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w: 密钥长度必须为 32 字节", ErrEncryptionKeyUnavailable)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
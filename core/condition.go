@@ -0,0 +1,247 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/casbin/govaluate"
+)
+
+// ConditionAttrTime 条件表达式中的请求时间属性，调用方未提供时填充为当前时间
+const ConditionAttrTime = "time"
+
+// RuleConditionResolver 规则条件解析器，返回规则附加的条件表达式，未设置时 ok=false
+type RuleConditionResolver func(rule Policy) (expression string, ok bool)
+
+// conditionFunctions 条件表达式可用的内置函数
+//
+//	timeBetween(time, "09:00", "18:00")  时间是否在每日时间窗口内（支持跨零点，如 "22:00" 到 "06:00"）
+//	weekday(time)                        星期几，0 为星期日
+//	hour(time)                           小时（0-23）
+//	ipInRange(ip, "10.0.0.0/8", ...)     IP 是否在任一 CIDR 范围内
+var conditionFunctions = map[string]govaluate.ExpressionFunction{
+	"timeBetween": func(args ...any) (any, error) {
+		if len(args) != 3 {
+			return nil, fmt.Errorf("timeBetween 需要 3 个参数")
+		}
+		t, err := conditionTime(args[0])
+		if err != nil {
+			return nil, err
+		}
+		start, err := clockMinutes(args[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := clockMinutes(args[2])
+		if err != nil {
+			return nil, err
+		}
+		current := t.Hour()*60 + t.Minute()
+		if start <= end {
+			return current >= start && current < end, nil
+		}
+		return current >= start || current < end, nil
+	},
+	"weekday": func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("weekday 需要 1 个参数")
+		}
+		t, err := conditionTime(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return float64(t.Weekday()), nil
+	},
+	"hour": func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("hour 需要 1 个参数")
+		}
+		t, err := conditionTime(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return float64(t.Hour()), nil
+	},
+	"ipInRange": func(args ...any) (any, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("ipInRange 至少需要 2 个参数")
+		}
+		text, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("ipInRange 的 IP 参数必须是字符串")
+		}
+		ip := net.ParseIP(text)
+		if ip == nil {
+			return nil, fmt.Errorf("无效的 IP 地址: %s", text)
+		}
+		for _, arg := range args[1:] {
+			cidr, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("ipInRange 的 CIDR 参数必须是字符串")
+			}
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("无效的 CIDR: %s", cidr)
+			}
+			if network.Contains(ip) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+}
+
+// compiledConditions 已编译的条件表达式缓存（表达式 -> *govaluate.EvaluableExpression）
+var compiledConditions sync.Map
+
+// ValidateCondition 校验条件表达式的语法
+func ValidateCondition(expression string) error {
+	_, err := compileCondition(expression)
+	return err
+}
+
+// EvaluateCondition 使用请求属性计算条件表达式，表达式结果必须为布尔值
+// 表达式引用了请求中不存在的属性时返回错误
+func EvaluateCondition(expression string, attrs map[string]any) (bool, error) {
+	compiled, err := compileCondition(expression)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := compiled.Evaluate(attrs)
+	if err != nil {
+		return false, fmt.Errorf("计算条件表达式失败: %v", err)
+	}
+	satisfied, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("条件表达式结果不是布尔值: %v", result)
+	}
+	return satisfied, nil
+}
+
+// compileCondition 编译条件表达式（带缓存）
+func compileCondition(expression string) (*govaluate.EvaluableExpression, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("条件表达式不能为空")
+	}
+	if cached, ok := compiledConditions.Load(expression); ok {
+		return cached.(*govaluate.EvaluableExpression), nil
+	}
+
+	compiled, err := govaluate.NewEvaluableExpressionWithFunctions(expression, conditionFunctions)
+	if err != nil {
+		return nil, fmt.Errorf("条件表达式语法错误: %v", err)
+	}
+	compiledConditions.Store(expression, compiled)
+	return compiled, nil
+}
+
+// conditionTime 将属性值转换为时间，支持 time.Time 和 RFC3339 字符串
+func conditionTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("无效的时间: %s", v)
+		}
+		return t, nil
+	default:
+		return time.Time{}, fmt.Errorf("无效的时间类型: %T", value)
+	}
+}
+
+// clockMinutes 将 "HH:MM" 转换为当天的分钟数
+func clockMinutes(value any) (int, error) {
+	text, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("时间窗口参数必须是 HH:MM 格式的字符串")
+	}
+	clock, err := time.Parse("15:04", text)
+	if err != nil {
+		return 0, fmt.Errorf("无效的时间窗口: %s", text)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// SetRuleConditionResolver 设置规则条件解析器
+func (e *Enforcer) SetRuleConditionResolver(resolver RuleConditionResolver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ruleConditionResolver = resolver
+}
+
+// ruleCondition 获取规则附加的条件表达式，未设置时返回空字符串
+func (e *Enforcer) ruleCondition(rule Policy) string {
+	e.mu.RLock()
+	resolver := e.ruleConditionResolver
+	e.mu.RUnlock()
+
+	if resolver != nil {
+		if expression, ok := resolver(rule); ok {
+			return expression
+		}
+	}
+	return ""
+}
+
+// conditionHolds 判断规则的条件在请求属性下是否成立
+// 未附加条件的规则总是成立；附加了条件的规则在没有请求属性（普通权限检查）或计算失败时视为不成立
+func (e *Enforcer) conditionHolds(rule Policy, attrs map[string]any) bool {
+	if rule.Condition == "" {
+		return true
+	}
+	if attrs == nil {
+		return false
+	}
+	satisfied, err := EvaluateCondition(rule.Condition, attrs)
+	return err == nil && satisfied
+}
+
+// CheckPermissionWithAttributes 检查权限并评估规则条件
+// 附加了条件的规则只在条件成立时生效；属性中未提供 time 时使用当前时间。
+// 结果依赖请求属性，因此不写入决策缓存。
+func (e *Enforcer) CheckPermissionWithAttributes(ctx context.Context, subject, domain string, permission Permission, attrs map[string]any) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	allowed, err := e.checkPermissionWithAttributes(subject, domain, permission, attrs)
+	if err != nil {
+		return false, err
+	}
+
+	e.observeDecision(subject, domain, permission, allowed)
+	return allowed, nil
+}
+
+// checkPermissionWithAttributes 检查权限并评估规则条件（不通知决策观察者）
+func (e *Enforcer) checkPermissionWithAttributes(subject, domain string, permission Permission, attrs map[string]any) (bool, error) {
+	if !e.checkAllowed(subject, domain) || e.denied(subject, domain, permission) {
+		return false, nil
+	}
+
+	requestAttrs := make(map[string]any, len(attrs)+1)
+	for key, value := range attrs {
+		requestAttrs[key] = value
+	}
+	if _, ok := requestAttrs[ConditionAttrTime]; !ok {
+		requestAttrs[ConditionAttrTime] = time.Now()
+	}
+
+	rules, err := e.implicitRulesWithAttributes(subject, domain, requestAttrs)
+	if err != nil {
+		return false, err
+	}
+
+	for _, rule := range rules {
+		if e.covers(rule.Permission(), permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -1,13 +1,86 @@
 package core
 
+import "time"
+
 // Config CasbinX配置
 type Config struct {
-	Dsn           string         `json:"dsn"`           // 数据库连接字符串
-	PossiblePaths []string       `json:"possiblePaths"` // Casbin模型文件可能的路径
-	Security      SecurityConfig `json:"security"`      // 安全配置
-	Watcher       WatcherConfig  `json:"watcher"`       // Watcher配置（多副本同步）
+	Dsn           string            `json:"dsn"`           // 数据库连接字符串
+	PossiblePaths []string          `json:"possiblePaths"` // Casbin模型文件可能的路径
+	Security      SecurityConfig    `json:"security"`      // 安全配置
+	Watcher       WatcherConfig     `json:"watcher"`       // Watcher配置（多副本同步）
+	Idempotency   IdempotencyConfig `json:"idempotency"`   // 幂等键配置
+
+	// ModelText Casbin 模型内容（可选），设置后忽略 PossiblePaths
+	// 未设置时依次尝试 PossiblePaths 中的模型文件；未配置 PossiblePaths 且默认路径下都没有模型文件时使用内置模型 DefaultModelText
+	ModelText string `json:"modelText"`
+
+	// Environment 当前运行环境（如 dev、staging、prod）
+	// 设置后，标记了环境的授权只在匹配的环境中生效；为空时不做环境过滤
+	Environment string `json:"environment"`
+
+	// SubjectNamespaces 启用主体命名空间
+	// 启用后用户和角色在策略存储中分别以 "user:"、"role:" 前缀保存，同名的用户和角色互不冲突。
+	// 已有数据需在启用后调用 MigrateSubjectNamespaces 迁移一次
+	SubjectNamespaces bool `json:"subjectNamespaces"`
+
+	// StrictWriteActions 严格区分写操作
+	// false（默认）：write 授权同时满足 create 和 update 检查，已有授权无需迁移；
+	// true：create、update 只能由对应的授权满足，用于区分"可创建用户"和"可修改用户"之类的审计要求
+	StrictWriteActions bool `json:"strictWriteActions"`
+
+	// DecisionLog 权限检查决策日志（异步批量写入）
+	DecisionLog DecisionLogConfig `json:"decisionLog"`
+
+	// Residency 数据驻留（区域 -> 数据库），仅由 engine.NewResidencyRouter 使用
+	Residency ResidencyConfig `json:"residency"`
+
+	// KeyProvider 敏感字段加密密钥提供者（可选）
+	// 设置后角色描述、冻结原因等运维信息在写入数据库前加密，数据库转储不会泄露这些内容
+	KeyProvider KeyProvider `json:"-"`
+
+	// Recovery 公开方法的 panic 恢复，默认开启
+	Recovery RecoveryConfig `json:"recovery"`
+
+	// MutationQueue 变更串行化队列（同一主体的变更按顺序执行），默认关闭
+	MutationQueue MutationQueueConfig `json:"mutationQueue"`
+
+	// Digest 权限变更摘要（按周期汇总审计日志并通过回调投递），默认关闭
+	Digest DigestConfig `json:"digest"`
+
+	// PolicyLoad 策略加载配置（容错加载），默认关闭
+	PolicyLoad PolicyLoadConfig `json:"policyLoad"`
+
+	// Metrics Prometheus 指标（设置 Registerer 后采集检查、变更、缓存、重载等指标），默认关闭
+	Metrics MetricsConfig `json:"metrics"`
+
+	// CheckComparison 权限检查实现（自定义实现或 Casbin 原生 Enforce）与影子比较，默认只使用自定义实现
+	CheckComparison CheckComparisonConfig `json:"checkComparison"`
+
+	// Logger 日志输出（可选），如 core.NewSlogLogger、core.NewZapLogger；engine.WithLogger 优先，都未设置时使用 slog 默认 Logger
+	Logger Logger `json:"-"`
+
+	// CasbinLog 将 Casbin 内部的模型、策略和每次权限检查日志以 Debug 级别输出到 Logger，默认关闭（开销较大，仅用于排查问题）
+	CasbinLog bool `json:"casbinLog"`
+
+	// UserData 用户数据删除的保留规则（DeleteUserData）
+	UserData UserDataConfig `json:"userData"`
+
+	// Schedule 定时权限变更（SchedulePermissionChange）的执行配置
+	Schedule ScheduleConfig `json:"schedule"`
+
+	// Expiration 限时授权（GrantTemporaryPermission、AssignTemporaryRole）的过期清理配置
+	Expiration ExpirationConfig `json:"expiration"`
+}
+
+// IdempotencyConfig 幂等键配置
+type IdempotencyConfig struct {
+	// TTL 幂等键的保留时长，超过后同一个键可再次执行，默认 15 分钟
+	TTL time.Duration `json:"ttl"`
 }
 
+// DefaultIdempotencyTTL 默认幂等键保留时长
+const DefaultIdempotencyTTL = 15 * time.Minute
+
 // SecurityConfig 安全相关配置
 type SecurityConfig struct {
 	// PreventSelfElevation 防止自我提权
@@ -17,12 +90,71 @@ type SecurityConfig struct {
 
 	// SystemPermissions 系统权限列表（不可授予也不可撤销）
 	SystemPermissions []Permission `json:"systemPermissions"`
+
+	// RestrictRoleAssignmentToOwnPermissions 限制角色分配范围
+	// true: 操作者只能分配权限集合是自身有效权限子集的角色，防止低级管理员分配高权限自定义角色
+	// false: 只校验用户管理和角色管理权限（默认，兼容旧行为）
+	RestrictRoleAssignmentToOwnPermissions bool `json:"restrictRoleAssignmentToOwnPermissions"`
+
+	// SelfQuery 用户查询自身权限信息（权限、角色、邀请授权等）的策略，默认总是允许
+	SelfQuery SelfQueryConfig `json:"selfQuery"`
+}
+
+// SelfQueryConfig 用户查询自身权限信息的策略
+// 不允许自查时，用户查询自己与查询他人相同，需要在租户内拥有 user:read 权限；
+// 适用于不应看到自身权限细节的账号，如零信任场景下的自助终端账号
+type SelfQueryConfig struct {
+	// Deny 默认不允许自查
+	Deny bool `json:"deny"`
+
+	// Tenants 按租户覆盖默认值：true 允许自查，false 不允许
+	Tenants map[string]bool `json:"tenants"`
+
+	// Resolver 按用户和租户决定是否允许自查（如识别自助终端账号），decided 为 false 时按 Tenants 和 Deny 决定
+	Resolver func(userKey, tenantKey string) (allowed, decided bool) `json:"-"`
+}
+
+// Allowed 用户能否不经权限检查查询自己在租户内的权限信息
+func (c SelfQueryConfig) Allowed(userKey, tenantKey string) bool {
+	if c.Resolver != nil {
+		if allowed, decided := c.Resolver(userKey, tenantKey); decided {
+			return allowed
+		}
+	}
+	if allowed, ok := c.Tenants[tenantKey]; ok {
+		return allowed
+	}
+	return !c.Deny
 }
 
 // WatcherConfig Watcher配置
 type WatcherConfig struct {
-	// Redis 配置（CasbinX 强制使用 Redis Watcher）
+	// Disabled 不创建 Watcher，适用于单实例部署和单元测试，此时无需配置 Redis。
+	// 关闭后本实例的变更不会通知其他实例，其他实例也不会收到本实例的变更：
+	// 多实例部署时各实例的内存策略会逐渐不一致，直到调用 RefreshPolicy 或重启
+	Disabled bool `json:"disabled"`
+
+	// Type 内置 Watcher 类型：redis（默认）、postgres、nats，需配置对应的子配置
+	// 通过 engine.WithWatcher 指定 Watcher 时忽略
+	Type WatcherType `json:"type"`
+
+	// Redis 配置（Type 为 redis 时必须配置 Addr）
 	Redis RedisWatcherConfig `json:"redis"`
+
+	// Postgres 配置（Type 为 postgres 时使用）
+	Postgres PostgresWatcherConfig `json:"postgres"`
+
+	// NATS 配置（Type 为 nats 时必须配置 URL）
+	NATS NATSWatcherConfig `json:"nats"`
+
+	// Signing 同步通知签名（可选），防止伪造通知；通过 engine.WithWatcher 指定 Watcher 时忽略
+	Signing WatcherSigningConfig `json:"signing"`
+
+	// ReloadDebounce 同步通知的防抖窗口，窗口内的多次通知合并为一次策略重载，默认 100ms
+	ReloadDebounce time.Duration `json:"reloadDebounce"`
+
+	// ReloadMaxDelay 持续收到通知时重载的最大延迟，默认 1s
+	ReloadMaxDelay time.Duration `json:"reloadMaxDelay"`
 }
 
 // RedisWatcherConfig Redis Watcher配置
@@ -33,6 +165,27 @@ type RedisWatcherConfig struct {
 	DB         int    `json:"db"`         // DB Redis数据库编号
 	Channel    string `json:"channel"`    // Channel 用于通知的Redis频道
 	IgnoreSelf bool   `json:"ignoreSelf"` // IgnoreSelf 是否忽略自己发布的消息
+
+	// HealthCheckInterval 订阅连接空闲超过该时长时发送 PING，再过一个间隔仍无响应视为连接失效并重新订阅，默认 15s
+	// 用于发现 Redis 重启或网络中断后没有报错的半开连接
+	HealthCheckInterval time.Duration `json:"healthCheckInterval"`
+}
+
+// PostgresWatcherConfig PostgreSQL LISTEN/NOTIFY Watcher配置
+type PostgresWatcherConfig struct {
+	Dsn        string `json:"dsn"`        // Dsn 数据库连接字符串，为空时使用 Config.Dsn
+	Channel    string `json:"channel"`    // Channel NOTIFY 频道，默认 casbinx_sync
+	IgnoreSelf bool   `json:"ignoreSelf"` // IgnoreSelf 是否忽略自己发布的消息
+}
+
+// NATSWatcherConfig NATS Watcher配置
+type NATSWatcherConfig struct {
+	URL        string `json:"url"`        // URL 服务地址，格式：nats://[user:pass@]host:port，集群的多个地址以逗号分隔
+	Subject    string `json:"subject"`    // Subject 用于通知的主题，默认 casbinx_sync
+	Username   string `json:"username"`   // Username 用户名（可选，也可写在 URL 中）
+	Password   string `json:"password"`   // Password 密码（可选）
+	Token      string `json:"token"`      // Token 认证令牌（可选）
+	IgnoreSelf bool   `json:"ignoreSelf"` // IgnoreSelf 是否忽略自己发布的消息
 }
 
 // DefaultSecurityConfig 返回默认安全配置
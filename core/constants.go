@@ -15,10 +15,18 @@ func ParseAction(s string) (Action, error) {
 		return ActionWrite, nil
 	case "delete":
 		return ActionDelete, nil
+	case "assign":
+		return ActionAssign, nil
+	case "create":
+		return ActionCreate, nil
+	case "update":
+		return ActionUpdate, nil
 	case "_none":
 		return ActionNone, nil
+	case "*":
+		return ActionAll, nil
 	default:
-		return Action(s), fmt.Errorf("invalid action: %s, only 'read', 'write', 'delete', '_none' are supported", s)
+		return Action(s), fmt.Errorf("invalid action: %s, only 'read', 'write', 'delete', 'assign', 'create', 'update', '*', '_none' are supported", s)
 	}
 }
 
@@ -52,7 +60,11 @@ var (
 	ActionRead   = Action("read")   // 读取/查看权限
 	ActionWrite  = Action("write")  // 创建/更新/配置权限
 	ActionDelete = Action("delete") // 删除权限
+	ActionAssign = Action("assign") // 分配权限（仅用于角色资源：分配/移除已有角色，不能修改角色本身）
+	ActionCreate = Action("create") // 创建权限（可选的细分写操作，默认由 write 授权隐含）
+	ActionUpdate = Action("update") // 更新权限（可选的细分写操作，默认由 write 授权隐含）
 	ActionNone   = Action("_none")  // 占位符，用于角色标识
+	ActionAll    = Action("*")      // 通配操作：覆盖资源上的所有操作（含自定义操作），只能由全局权限管理者授予
 )
 
 // 基础资源常量（仅用于系统核心功能）
@@ -66,6 +78,7 @@ const (
 	ResourceTagUser      = Resource("tag_user")     // 用户标签资源
 	ResourceTagTenant    = Resource("tag_tenant")   // 租户标签资源
 	ResourcePlaceholder  = Resource("_placeholder") // 占位符，用于角色标识
+	ResourceAll          = Resource("*")            // 通配资源：覆盖所有资源（含系统资源和对象级资源），只能由全局权限管理者授予
 )
 
 // AllActions 基础权限操作列表 - 仅包含三种基本操作
@@ -77,14 +90,14 @@ var AllActions = []Action{
 
 // DefaultResourceActions 默认资源与可用操作的映射（仅用于系统核心资源）
 var DefaultResourceActions = map[Resource][]Action{
-	ResourceTenant:       {ActionRead, ActionWrite, ActionDelete},
-	ResourceSystem:       {ActionRead, ActionWrite, ActionDelete},
-	ResourceUser:         {ActionRead, ActionWrite, ActionDelete},
-	ResourcePermission:   {ActionRead, ActionWrite, ActionDelete},
-	ResourceRole:         {ActionRead, ActionWrite, ActionDelete},
-	ResourceOrganization: {ActionRead, ActionWrite, ActionDelete},
-	ResourceTagUser:      {ActionRead, ActionWrite, ActionDelete},
-	ResourceTagTenant:    {ActionRead, ActionWrite, ActionDelete},
+	ResourceTenant:       {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
+	ResourceSystem:       {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
+	ResourceUser:         {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
+	ResourcePermission:   {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
+	ResourceRole:         {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete, ActionAssign},
+	ResourceOrganization: {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
+	ResourceTagUser:      {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
+	ResourceTagTenant:    {ActionRead, ActionWrite, ActionCreate, ActionUpdate, ActionDelete},
 }
 
 // GetResourceActions 获取指定资源的可用操作列表
@@ -96,6 +109,11 @@ func GetResourceActions(resource Resource) []Action {
 	return AllActions
 }
 
+// IsWriteAction 是否为写操作（write 及其细分的 create、update）
+func IsWriteAction(action Action) bool {
+	return action == ActionWrite || action == ActionCreate || action == ActionUpdate
+}
+
 // HasManagePermission 检查是否有管理权限（read + write + delete）
 func HasManagePermission(checkFunc func(resource Resource, action Action) (bool, error), resource Resource) (bool, error) {
 	// 管理权限需要同时拥有读、写、删除权限
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// ContextUpdatableAdapter 支持 ctx 的批量更新适配器
+type ContextUpdatableAdapter interface {
+	UpdatePoliciesCtx(ctx context.Context, sec string, ptype string, oldRules, newRules [][]string) error
+}
+
+// ContextAdapter 上下文感知的策略适配器：执行器写入策略期间绑定调用方的 ctx，适配器写入遵循 ctx 的取消与超时
+// 原适配器实现 persist.ContextAdapter、persist.ContextBatchAdapter 或 ContextUpdatableAdapter 时调用对应的 Ctx 方法，
+// 否则在写入前检查 ctx 是否已结束；未绑定 ctx 时（如 Casbin 内部调用）使用 context.Background()
+type ContextAdapter struct {
+	adapter persist.Adapter
+
+	mu  sync.Mutex
+	ctx context.Context // 当前写入绑定的上下文
+}
+
+// NewContextAdapter 创建上下文感知的策略适配器，需通过 Enforcer.SetContextAdapter 注册到执行器
+func NewContextAdapter(adapter persist.Adapter) *ContextAdapter {
+	return &ContextAdapter{adapter: adapter}
+}
+
+// bind 绑定当前写入的 ctx，返回解除绑定的函数
+func (a *ContextAdapter) bind(ctx context.Context) func() {
+	a.mu.Lock()
+	a.ctx = ctx
+	a.mu.Unlock()
+	return func() {
+		a.mu.Lock()
+		a.ctx = nil
+		a.mu.Unlock()
+	}
+}
+
+// context 返回当前写入绑定的 ctx
+func (a *ContextAdapter) context() context.Context {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// LoadPolicy 加载所有策略
+func (a *ContextAdapter) LoadPolicy(m model.Model) error {
+	return a.adapter.LoadPolicy(m)
+}
+
+// SavePolicy 保存所有策略
+func (a *ContextAdapter) SavePolicy(m model.Model) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(persist.ContextAdapter); ok {
+		return adapter.SavePolicyCtx(ctx, m)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.adapter.SavePolicy(m)
+}
+
+// AddPolicy 添加策略
+func (a *ContextAdapter) AddPolicy(sec string, ptype string, rule []string) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(persist.ContextAdapter); ok {
+		return adapter.AddPolicyCtx(ctx, sec, ptype, rule)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.adapter.AddPolicy(sec, ptype, rule)
+}
+
+// RemovePolicy 删除策略
+func (a *ContextAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(persist.ContextAdapter); ok {
+		return adapter.RemovePolicyCtx(ctx, sec, ptype, rule)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.adapter.RemovePolicy(sec, ptype, rule)
+}
+
+// RemoveFilteredPolicy 按条件删除策略
+func (a *ContextAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(persist.ContextAdapter); ok {
+		return adapter.RemoveFilteredPolicyCtx(ctx, sec, ptype, fieldIndex, fieldValues...)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.adapter.RemoveFilteredPolicy(sec, ptype, fieldIndex, fieldValues...)
+}
+
+// AddPolicies 批量添加策略
+func (a *ContextAdapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(persist.ContextBatchAdapter); ok {
+		return adapter.AddPoliciesCtx(ctx, sec, ptype, rules)
+	}
+	batch, ok := a.adapter.(persist.BatchAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持批量写入")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return batch.AddPolicies(sec, ptype, rules)
+}
+
+// RemovePolicies 批量删除策略
+func (a *ContextAdapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(persist.ContextBatchAdapter); ok {
+		return adapter.RemovePoliciesCtx(ctx, sec, ptype, rules)
+	}
+	batch, ok := a.adapter.(persist.BatchAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持批量写入")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return batch.RemovePolicies(sec, ptype, rules)
+}
+
+// UpdatePolicy 更新策略
+func (a *ContextAdapter) UpdatePolicy(sec string, ptype string, oldRule, newRule []string) error {
+	return a.UpdatePolicies(sec, ptype, [][]string{oldRule}, [][]string{newRule})
+}
+
+// UpdatePolicies 批量更新策略
+func (a *ContextAdapter) UpdatePolicies(sec string, ptype string, oldRules, newRules [][]string) error {
+	ctx := a.context()
+	if adapter, ok := a.adapter.(ContextUpdatableAdapter); ok {
+		return adapter.UpdatePoliciesCtx(ctx, sec, ptype, oldRules, newRules)
+	}
+	updatable, ok := a.adapter.(persist.UpdatableAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持更新策略")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return updatable.UpdatePolicies(sec, ptype, oldRules, newRules)
+}
+
+// UpdateFilteredPolicies 按条件替换策略
+func (a *ContextAdapter) UpdateFilteredPolicies(sec string, ptype string, newRules [][]string, fieldIndex int, fieldValues ...string) ([][]string, error) {
+	updatable, ok := a.adapter.(persist.UpdatableAdapter)
+	if !ok {
+		return nil, fmt.Errorf("适配器不支持更新策略")
+	}
+	if err := a.context().Err(); err != nil {
+		return nil, err
+	}
+	return updatable.UpdateFilteredPolicies(sec, ptype, newRules, fieldIndex, fieldValues...)
+}
+
+// SetContextAdapter 注册上下文感知的策略适配器（应为 Casbin 执行器使用的适配器或其内层适配器）
+// 注册后策略写入串行执行，写入期间适配器使用调用方的 ctx
+func (e *Enforcer) SetContextAdapter(adapter *ContextAdapter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.contextAdapter = adapter
+}
+
+// persistWrite 在调用方的 ctx 下执行一次策略写入，ctx 已结束时不写入
+func (e *Enforcer) persistWrite(ctx context.Context, write func() (bool, error)) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	e.mu.RLock()
+	adapter := e.contextAdapter
+	e.mu.RUnlock()
+	if adapter == nil {
+		return write()
+	}
+
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+	defer adapter.bind(ctx)()
+	return write()
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+)
+
+// ctxKey 测试中标记 ctx 的键
+type ctxKey struct{}
+
+// recordingAdapter 记录每次写入收到的 ctx 标记
+type recordingAdapter struct {
+	writes []string
+}
+
+func (a *recordingAdapter) record(ctx context.Context, op string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	marker, _ := ctx.Value(ctxKey{}).(string)
+	a.writes = append(a.writes, op+":"+marker)
+	return nil
+}
+
+func (a *recordingAdapter) LoadPolicy(model.Model) error                { return nil }
+func (a *recordingAdapter) SavePolicy(model.Model) error                { return nil }
+func (a *recordingAdapter) AddPolicy(string, string, []string) error    { return nil }
+func (a *recordingAdapter) RemovePolicy(string, string, []string) error { return nil }
+func (a *recordingAdapter) RemoveFilteredPolicy(string, string, int, ...string) error {
+	return nil
+}
+func (a *recordingAdapter) LoadPolicyCtx(context.Context, model.Model) error { return nil }
+func (a *recordingAdapter) SavePolicyCtx(ctx context.Context, _ model.Model) error {
+	return a.record(ctx, "save")
+}
+func (a *recordingAdapter) AddPolicyCtx(ctx context.Context, _, _ string, _ []string) error {
+	return a.record(ctx, "add")
+}
+func (a *recordingAdapter) RemovePolicyCtx(ctx context.Context, _, _ string, _ []string) error {
+	return a.record(ctx, "remove")
+}
+func (a *recordingAdapter) RemoveFilteredPolicyCtx(ctx context.Context, _, _ string, _ int, _ ...string) error {
+	return a.record(ctx, "remove_filtered")
+}
+func (a *recordingAdapter) AddPoliciesCtx(ctx context.Context, _, _ string, _ [][]string) error {
+	return a.record(ctx, "add_batch")
+}
+func (a *recordingAdapter) RemovePoliciesCtx(ctx context.Context, _, _ string, _ [][]string) error {
+	return a.record(ctx, "remove_batch")
+}
+func (a *recordingAdapter) UpdatePoliciesCtx(ctx context.Context, _, _ string, _, _ [][]string) error {
+	return a.record(ctx, "update_batch")
+}
+
+// newContextTestEnforcer 创建使用上下文感知适配器的执行器
+func newContextTestEnforcer(t *testing.T) (*Enforcer, *recordingAdapter) {
+	t.Helper()
+	m, err := model.NewModelFromString(DefaultModelText)
+	if err != nil {
+		t.Fatalf("NewModelFromString() error = %v", err)
+	}
+	recorder := &recordingAdapter{}
+	adapter := NewContextAdapter(recorder)
+	casbinEnforcer, err := casbin.NewEnforcer(m, adapter)
+	if err != nil {
+		t.Fatalf("casbin.NewEnforcer() error = %v", err)
+	}
+	e, err := NewEnforcer(casbinEnforcer)
+	if err != nil {
+		t.Fatalf("NewEnforcer() error = %v", err)
+	}
+	e.SetContextAdapter(adapter)
+	return e, recorder
+}
+
+func TestContextAdapterWrites(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(ctx context.Context, e *Enforcer) error
+		write      func(ctx context.Context, e *Enforcer) error
+		wantWrites []string
+	}{
+		{
+			name: "批量授权使用调用方的 ctx",
+			write: func(ctx context.Context, e *Enforcer) error {
+				return e.AddPolicies(ctx, "alice", "t1", []Permission{userRead, userWrite})
+			},
+			wantWrites: []string{"add_batch:caller"},
+		},
+		{
+			name: "撤销使用调用方的 ctx",
+			setup: func(ctx context.Context, e *Enforcer) error {
+				return e.AddPolicy(ctx, "alice", "t1", userRead)
+			},
+			write: func(ctx context.Context, e *Enforcer) error {
+				return e.RemovePolicy(ctx, "alice", "t1", userRead)
+			},
+			wantWrites: []string{"remove_batch:caller"},
+		},
+		{
+			name: "改名使用调用方的 ctx",
+			setup: func(ctx context.Context, e *Enforcer) error {
+				return e.AddPolicy(ctx, "alice", "t1", userRead)
+			},
+			write: func(ctx context.Context, e *Enforcer) error {
+				_, _, err := e.RenameSubject(ctx, "alice", "bob")
+				return err
+			},
+			wantWrites: []string{"update_batch:caller"},
+		},
+		{
+			name: "角色分配使用调用方的 ctx",
+			write: func(ctx context.Context, e *Enforcer) error {
+				return e.AddGroupingPolicy(ctx, "alice", "admin", "t1")
+			},
+			wantWrites: []string{"add_batch:caller"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, recorder := newContextTestEnforcer(t)
+			if tt.setup != nil {
+				if err := tt.setup(context.Background(), e); err != nil {
+					t.Fatalf("setup error = %v", err)
+				}
+				recorder.writes = nil
+			}
+
+			ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+			if err := tt.write(ctx, e); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			if len(recorder.writes) != len(tt.wantWrites) {
+				t.Fatalf("writes = %v, want %v", recorder.writes, tt.wantWrites)
+			}
+			for i := range tt.wantWrites {
+				if recorder.writes[i] != tt.wantWrites[i] {
+					t.Fatalf("writes = %v, want %v", recorder.writes, tt.wantWrites)
+				}
+			}
+		})
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	e, recorder := newContextTestEnforcer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := e.AddPolicy(ctx, "alice", "t1", userRead); !errors.Is(err, context.Canceled) {
+		t.Fatalf("AddPolicy() error = %v, want %v", err, context.Canceled)
+	}
+	if exists, _ := e.HasPolicy("alice", "t1", userRead); exists {
+		t.Fatalf("policy written with canceled ctx")
+	}
+	if len(recorder.writes) != 0 {
+		t.Fatalf("adapter writes = %v, want none", recorder.writes)
+	}
+
+	if _, err := e.CheckPermission(ctx, "alice", "t1", userRead); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CheckPermission() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := e.CheckPermissionWithAttributes(ctx, "alice", "t1", userRead, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CheckPermissionWithAttributes() error = %v, want %v", err, context.Canceled)
+	}
+}
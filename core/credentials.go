@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DBEndpoint 数据库连接目标，由连接字符串解析得到，供凭据回调生成访问令牌
+type DBEndpoint struct {
+	Host     string // 主机名
+	Port     uint16 // 端口
+	User     string // 数据库用户
+	Database string // 数据库名
+}
+
+// Address 返回 "host:port" 形式的地址（RDS IAM 令牌按该地址签名）
+func (e DBEndpoint) Address() string {
+	return fmt.Sprintf("%s:%d", e.Host, e.Port)
+}
+
+// DBCredentialsProvider 数据库凭据回调，在建立每个新的物理连接前调用，返回该连接使用的密码
+// 用于 AWS RDS IAM 认证令牌、GCP CloudSQL IAM 访问令牌等短期凭据，连接字符串中无需包含密码
+type DBCredentialsProvider func(ctx context.Context, endpoint DBEndpoint) (password string, err error)
+
+// CachedDBCredentials 缓存凭据回调返回的令牌，ttl 内同一连接目标复用同一令牌，过期后重新获取
+// ttl 应短于令牌有效期（RDS IAM 令牌有效期 15 分钟，CloudSQL 访问令牌通常为 1 小时）
+func CachedDBCredentials(provider DBCredentialsProvider, ttl time.Duration) DBCredentialsProvider {
+	type cachedToken struct {
+		password  string
+		expiresAt time.Time
+	}
+
+	var mu sync.Mutex
+	tokens := make(map[DBEndpoint]cachedToken)
+
+	return func(ctx context.Context, endpoint DBEndpoint) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if token, ok := tokens[endpoint]; ok && time.Now().Before(token.expiresAt) {
+			return token.password, nil
+		}
+
+		password, err := provider(ctx, endpoint)
+		if err != nil {
+			return "", err
+		}
+		tokens[endpoint] = cachedToken{password: password, expiresAt: time.Now().Add(ttl)}
+		return password, nil
+	}
+}
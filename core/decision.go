@@ -0,0 +1,90 @@
+package core
+
+import "time"
+
+// 决策日志默认值
+const (
+	DefaultDecisionBufferSize    = 10000       // 默认缓冲区容量
+	DefaultDecisionBatchSize     = 500         // 默认单批写入条数
+	DefaultDecisionFlushInterval = time.Second // 默认刷新间隔
+)
+
+// DecisionBackpressure 决策日志缓冲区满时的处理策略
+type DecisionBackpressure string
+
+const (
+	DecisionDropOldest DecisionBackpressure = "drop_oldest" // 丢弃最旧的记录，保留最新决策（默认）
+	DecisionDropNewest DecisionBackpressure = "drop_newest" // 丢弃新到达的记录，保留已缓冲的决策
+)
+
+// Decision 权限检查决策记录
+type Decision struct {
+	UserKey    string     `json:"userKey"`    // 被检查的用户
+	TenantKey  string     `json:"tenantKey"`  // 被检查的租户
+	Permission Permission `json:"permission"` // 被检查的权限
+	Allowed    bool       `json:"allowed"`    // 检查结果
+	CheckedAt  time.Time  `json:"checkedAt"`  // 检查时间
+}
+
+// DecisionLogConfig 决策日志配置
+type DecisionLogConfig struct {
+	// Enabled 是否记录权限检查决策，默认关闭
+	Enabled bool `json:"enabled"`
+
+	// BufferSize 内存缓冲区容量，超过后按 Backpressure 丢弃记录，默认 10000
+	BufferSize int `json:"bufferSize"`
+
+	// BatchSize 单批写入数据库的最大条数，缓冲达到该数量时立即刷新，默认 500
+	BatchSize int `json:"batchSize"`
+
+	// FlushInterval 后台定时刷新间隔，默认 1s
+	FlushInterval time.Duration `json:"flushInterval"`
+
+	// Backpressure 缓冲区满时的处理策略，默认 drop_oldest
+	Backpressure DecisionBackpressure `json:"backpressure"`
+}
+
+// DecisionLogStats 决策日志缓冲统计
+type DecisionLogStats struct {
+	Pending       int       `json:"pending"`       // 缓冲区中等待写入的记录数
+	Recorded      int64     `json:"recorded"`      // 累计提交的记录数
+	Written       int64     `json:"written"`       // 累计成功写入的记录数
+	Dropped       int64     `json:"dropped"`       // 累计因缓冲区满而丢弃的记录数
+	FlushErrors   int64     `json:"flushErrors"`   // 累计写入失败次数（失败批次会重新放回缓冲区）
+	LastFlushAt   time.Time `json:"lastFlushAt"`   // 最近一次成功写入时间
+	LastFlushSize int       `json:"lastFlushSize"` // 最近一次成功写入的条数
+	LastError     string    `json:"lastError"`     // 最近一次写入错误（成功时为空）
+}
+
+// DecisionObserver 权限检查决策观察者
+// 在每次 CheckPermission 完成后同步调用，实现方不应阻塞
+type DecisionObserver func(decision Decision)
+
+// AddDecisionObserver 注册权限检查决策观察者
+func (e *Enforcer) AddDecisionObserver(observer DecisionObserver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.decisionObservers = append(e.decisionObservers, observer)
+}
+
+// observeDecision 通知所有决策观察者
+func (e *Enforcer) observeDecision(userKey, domain string, permission Permission, allowed bool) {
+	e.mu.RLock()
+	observers := e.decisionObservers
+	e.mu.RUnlock()
+
+	if len(observers) == 0 {
+		return
+	}
+
+	decision := Decision{
+		UserKey:    userKey,
+		TenantKey:  domain,
+		Permission: permission,
+		Allowed:    allowed,
+		CheckedAt:  time.Now(),
+	}
+	for _, observer := range observers {
+		observer(decision)
+	}
+}
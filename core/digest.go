@@ -0,0 +1,136 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// 变更摘要默认值
+const (
+	DefaultDigestInterval   = 24 * time.Hour   // 默认摘要周期
+	DefaultDigestMaxChanges = 100              // 默认每份摘要附带的变更明细条数
+	DefaultWebhookTimeout   = 10 * time.Second // Webhook 投递默认超时
+)
+
+// DigestConfig 权限变更摘要配置
+// 启用后按周期汇总审计日志中各租户的权限变更，通过 engine.WithDigestHook 注册的回调投递
+type DigestConfig struct {
+	// Enabled 是否定时生成变更摘要，默认关闭
+	Enabled bool `json:"enabled"`
+
+	// Interval 摘要周期，默认 24h；周期边界按 UTC 对齐（如每天 00:00 UTC），多实例部署时每个周期只由一个实例投递
+	Interval time.Duration `json:"interval"`
+
+	// Window 每份摘要覆盖的时间范围（截至周期边界），默认与 Interval 相同
+	Window time.Duration `json:"window"`
+
+	// MaxChanges 每份摘要附带的变更明细条数上限（按时间倒序），默认 100；统计数据不受影响
+	MaxChanges int `json:"maxChanges"`
+}
+
+// ChangeDigest 单个租户在时间范围内的权限变更摘要
+type ChangeDigest struct {
+	TenantKey     string             `json:"tenantKey"`     // 租户标识（全局域的变更为 "*"，未记录租户的变更为空）
+	Since         time.Time          `json:"since"`         // 起始时间（包含）
+	Until         time.Time          `json:"until"`         // 截止时间（不包含）
+	TotalChanges  int                `json:"totalChanges"`  // 变更总数
+	ByAction      map[Action]int     `json:"byAction"`      // 按变更类型统计
+	ByOperator    map[string]int     `json:"byOperator"`    // 按操作者统计
+	AffectedUsers []string           `json:"affectedUsers"` // 被操作的用户（已排序）
+	Changes       []PermissionChange `json:"changes"`       // 变更明细（按时间倒序，最多 MaxChanges 条）
+	Truncated     bool               `json:"truncated"`     // 变更明细是否被截断
+}
+
+// DigestHook 变更摘要投递回调，每个有变更的租户调用一次
+type DigestHook func(ctx context.Context, digest ChangeDigest) error
+
+// BuildChangeDigests 将变更记录按租户汇总为摘要（按租户键排序）
+// changes 需按时间倒序排列；maxChanges <= 0 时使用 DefaultDigestMaxChanges
+func BuildChangeDigests(changes []PermissionChange, since, until time.Time, maxChanges int) []ChangeDigest {
+	if maxChanges <= 0 {
+		maxChanges = DefaultDigestMaxChanges
+	}
+
+	digests := make(map[string]*ChangeDigest)
+	users := make(map[string]map[string]bool)
+	for _, change := range changes {
+		digest, exists := digests[change.TenantKey]
+		if !exists {
+			digest = &ChangeDigest{
+				TenantKey:  change.TenantKey,
+				Since:      since,
+				Until:      until,
+				ByAction:   make(map[Action]int),
+				ByOperator: make(map[string]int),
+				Changes:    []PermissionChange{},
+			}
+			digests[change.TenantKey] = digest
+			users[change.TenantKey] = make(map[string]bool)
+		}
+
+		digest.TotalChanges++
+		digest.ByAction[change.Action]++
+		if change.OperatorKey != "" {
+			digest.ByOperator[change.OperatorKey]++
+		}
+		if change.UserKey != "" {
+			users[change.TenantKey][change.UserKey] = true
+		}
+		if len(digest.Changes) < maxChanges {
+			digest.Changes = append(digest.Changes, change)
+		} else {
+			digest.Truncated = true
+		}
+	}
+
+	result := make([]ChangeDigest, 0, len(digests))
+	for tenantKey, digest := range digests {
+		digest.AffectedUsers = make([]string, 0, len(users[tenantKey]))
+		for userKey := range users[tenantKey] {
+			digest.AffectedUsers = append(digest.AffectedUsers, userKey)
+		}
+		sort.Strings(digest.AffectedUsers)
+		result = append(result, *digest)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TenantKey < result[j].TenantKey
+	})
+	return result
+}
+
+// NewWebhookDigestHook 创建以 JSON POST 投递摘要的 Webhook 回调
+// client 为空时使用超时为 DefaultWebhookTimeout 的默认客户端；非 2xx 响应视为投递失败
+func NewWebhookDigestHook(url string, client *http.Client) DigestHook {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+
+	return func(ctx context.Context, digest ChangeDigest) error {
+		body, err := json.Marshal(digest)
+		if err != nil {
+			return fmt.Errorf("序列化变更摘要失败: %v", err)
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("创建 Webhook 请求失败: %v", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+
+		response, err := client.Do(request)
+		if err != nil {
+			return fmt.Errorf("投递变更摘要失败: %v", err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return fmt.Errorf("投递变更摘要失败: Webhook 返回状态码 %d", response.StatusCode)
+		}
+		return nil
+	}
+}
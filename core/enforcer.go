@@ -1,12 +1,66 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
 )
 
+// RoleDomainResolver 角色权限域解析器
+// 返回角色在请求域下需要额外查询权限的域（例如共享角色的来源租户）
+type RoleDomainResolver func(roleKey, domain string) []string
+
+// RuleFilter 策略规则过滤器，返回 false 表示该规则在当前上下文中不生效
+type RuleFilter func(rule Policy) bool
+
+// AssignmentFilter 角色分配过滤器，返回 false 表示用户在该域的角色分配在当前上下文中不生效
+type AssignmentFilter func(userKey, roleKey, domain string) bool
+
+// CheckGuard 权限检查守卫，返回 false 表示该主体在该域的所有权限检查直接拒绝
+type CheckGuard func(subject, domain string) bool
+
 // Enforcer Casbin执行器的基础封装，提供核心权限操作
 type Enforcer struct {
 	enforcer *casbin.Enforcer
+
+	mu                 sync.RWMutex
+	watcher            persist.Watcher    // 策略同步 Watcher（可选）
+	reloadHooks        []func() error     // 策略重新加载后执行的回调
+	roleDomainResolver RoleDomainResolver // 角色权限域解析器（可选）
+	ruleFilters        []RuleFilter       // 权限检查时应用的规则过滤器
+	assignmentFilters  []AssignmentFilter // 权限检查时应用的角色分配过滤器
+	checkGuards        []CheckGuard       // 权限检查前执行的守卫
+	subjectNamespaces  bool               // 是否启用主体命名空间（user:/role: 前缀）
+	strictWriteActions bool               // 是否严格区分写操作（write 授权不隐含 create/update）
+
+	rulePriorityResolver  RulePriorityResolver        // 规则优先级解析器（可选）
+	ruleConditionResolver RuleConditionResolver       // 规则条件解析器（可选）
+	decisionObservers     []DecisionObserver          // 权限检查决策观察者
+	domainAliasResolver   DomainAliasResolver         // 域别名解析器（可选）
+	objectCleanupHooks    []ObjectCleanupHook         // 对象清理回调
+	rolloutResolver       RolloutResolver             // 灰度变更解析器（可选）
+	denyResolver          DenyResolver                // 拒绝规则解析器（可选）
+	domainWriteGuards     []DomainWriteGuard          // 策略写入前执行的域守卫
+	decisionCache         DecisionCache               // 权限检查结果缓存（可选）
+	permissionCache       PermissionCache             // 权限集合缓存（可选）
+	metrics               MetricsRecorder             // 指标采集（未设置时不采集）
+	checkComparison       CheckComparisonConfig       // 权限检查实现与影子比较配置
+	divergenceHooks       []CheckDivergenceHook       // 影子比较结果不一致时的回调
+	revision              atomic.Uint64               // 策略修订号，每次缓存失效（策略写入、重新加载或附属状态同步）加一
+	notifySuspended       int                         // 暂停同步通知的嵌套层数
+	journal               atomic.Pointer[RuleJournal] // 规则变更日志（可选），记录期间的规则写入
+	contextAdapter        *ContextAdapter             // 上下文感知的策略适配器（可选）
+	writeMu               sync.Mutex                  // 注册上下文感知适配器后串行化策略写入
+
+	versionMu       sync.Mutex // 保护策略版本缓存
+	version         string     // 缓存的策略版本
+	versionRevision uint64     // 策略版本对应的修订号
 }
 
 // NewEnforcer 创建核心权限执行器
@@ -15,118 +69,391 @@ func NewEnforcer(casbinEnforcer *casbin.Enforcer) (*Enforcer, error) {
 		return nil, ErrCasbinNotInitialized
 	}
 
-	return &Enforcer{
+	e := &Enforcer{
 		enforcer: casbinEnforcer,
-	}, nil
+		metrics:  NopMetrics(),
+		checkComparison: CheckComparisonConfig{
+			Primary:    CheckImplementationCustom,
+			SampleRate: 1,
+		},
+	}
+	e.registerMatcherFunctions()
+	return e, nil
 }
 
 // === 基础策略操作 ===
 
 // AddPolicy 添加权限策略
-func (e *Enforcer) AddPolicy(subject, domain string, permission Permission) error {
-	_, err := e.enforcer.AddPolicy(subject, domain, string(permission.Resource), string(permission.Action))
-	return err
+// 权限策略相关方法的 subject 均为存储中的主体标识，调用方通过 UserSubject/RoleSubject 生成
+func (e *Enforcer) AddPolicy(ctx context.Context, subject, domain string, permission Permission) error {
+	defer e.invalidateCache()
+	if err := e.checkDomainWrite(domain); err != nil {
+		return err
+	}
+	rules, err := e.policyRules(subject, domain, []Permission{permission}, false)
+	if err != nil {
+		return err
+	}
+	return e.insertRules(ctx, PolicyTypePermission, rules)
+}
+
+// HasPolicy 检查权限策略是否存在（精确匹配主体、域和权限）
+func (e *Enforcer) HasPolicy(subject, domain string, permission Permission) (bool, error) {
+	return e.enforcer.HasPolicy(subject, domain, string(permission.QualifiedResource()), string(permission.Action))
+}
+
+// AddPolicies 批量添加同一主体在同一域的权限策略
+// 已存在的策略和重复的权限会被跳过；适配器在单个事务中写入，要么全部成功要么全部失败
+func (e *Enforcer) AddPolicies(ctx context.Context, subject, domain string, permissions []Permission) error {
+	defer e.invalidateCache()
+	if len(permissions) == 0 {
+		return nil
+	}
+	if err := e.checkDomainWrite(domain); err != nil {
+		return err
+	}
+
+	rules, err := e.policyRules(subject, domain, permissions, false)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	return e.insertRules(ctx, PolicyTypePermission, rules)
 }
 
 // RemovePolicy 移除权限策略
-func (e *Enforcer) RemovePolicy(subject, domain string, permission Permission) error {
-	_, err := e.enforcer.RemovePolicy(subject, domain, string(permission.Resource), string(permission.Action))
-	return err
+func (e *Enforcer) RemovePolicy(ctx context.Context, subject, domain string, permission Permission) error {
+	defer e.invalidateCache()
+	rule := []string{subject, domain, string(permission.QualifiedResource()), string(permission.Action)}
+	return e.deleteRules(ctx, PolicyTypePermission, [][]string{rule})
+}
+
+// RemovePolicies 批量移除同一主体在同一域的权限策略
+// 不存在的策略会被跳过；适配器在单个事务中删除，要么全部成功要么全部失败
+func (e *Enforcer) RemovePolicies(ctx context.Context, subject, domain string, permissions []Permission) error {
+	defer e.invalidateCache()
+
+	rules, err := e.policyRules(subject, domain, permissions, true)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	return e.deleteRules(ctx, PolicyTypePermission, rules)
+}
+
+// policyRules 将权限转换为策略规则并去重，existing 为 true 时只保留已存在的策略，否则只保留不存在的策略
+// Casbin 的批量接口在部分规则已存在（或不存在）时会整体跳过，调用前需要先过滤
+func (e *Enforcer) policyRules(subject, domain string, permissions []Permission, existing bool) ([][]string, error) {
+	seen := make(map[Permission]bool, len(permissions))
+	rules := make([][]string, 0, len(permissions))
+	for _, permission := range permissions {
+		// 对象级权限按存储形式去重
+		permission = Permission{Resource: permission.QualifiedResource(), Action: permission.Action}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+
+		exists, err := e.HasPolicy(subject, domain, permission)
+		if err != nil {
+			return nil, err
+		}
+		if exists == existing {
+			rules = append(rules, []string{subject, domain, string(permission.QualifiedResource()), string(permission.Action)})
+		}
+	}
+	return rules, nil
+}
+
+// RemoveResourcePolicies 批量移除指定域中某个资源的所有权限策略（所有主体），返回被移除的策略
+// 适配器在单个事务中删除，要么全部成功要么全部失败
+func (e *Enforcer) RemoveResourcePolicies(ctx context.Context, domain string, resource Resource) ([]Policy, error) {
+	if domain == "" || resource == "" {
+		return nil, nil
+	}
+
+	return e.removePoliciesWhere(ctx, 1, []string{domain, string(resource)}, nil)
+}
+
+// removePoliciesWhere 按字段过滤查询权限策略，在一个批次中移除其中满足条件的策略，返回被移除的策略
+// fieldValues 中的空值表示不限制该字段，match 为空时移除所有过滤结果
+func (e *Enforcer) removePoliciesWhere(ctx context.Context, fieldIndex int, fieldValues []string, match func(policy []string) bool) ([]Policy, error) {
+	defer e.invalidateCache()
+	filtered, err := e.enforcer.GetFilteredPolicy(fieldIndex, fieldValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched [][]string
+	for _, policy := range filtered {
+		if len(policy) >= 4 && (match == nil || match(policy)) {
+			matched = append(matched, policy)
+		}
+	}
+	return e.removeRules(ctx, matched)
+}
+
+// removeRules 在一个批次中移除给定的权限策略，返回被移除的策略
+func (e *Enforcer) removeRules(ctx context.Context, matched [][]string) ([]Policy, error) {
+	var rules [][]string
+	var removed []Policy
+	for _, policy := range matched {
+		if len(policy) < 4 {
+			continue
+		}
+		rule, err := e.toPolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, policy)
+		removed = append(removed, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	if err := e.deleteRules(ctx, PolicyTypePermission, rules); err != nil {
+		return nil, err
+	}
+	return removed, nil
 }
 
 // GetPolicies 获取指定主体的权限策略
+// 返回的 Policy.Subject 为不带命名空间前缀的主体键
+// subject 或 domain 为空时不按该字段过滤
 func (e *Enforcer) GetPolicies(subject, domain string) ([]Policy, error) {
-
-	allPolicies, err := e.enforcer.GetPolicy()
+	// Casbin 的过滤查询中空值表示不限制该字段，只复制匹配的策略
+	matched, err := e.enforcer.GetFilteredPolicy(0, subject, domain)
 	if err != nil {
 		return nil, err
 	}
 
 	var policies []Policy
-	for _, policy := range allPolicies {
+	for _, policy := range matched {
 		if len(policy) >= 4 {
-			action, err := ParseAction(policy[3])
+			rule, err := e.toPolicy(policy)
 			if err != nil {
 				return nil, err
 			}
-			// 匹配主体和域
-			if (subject == "" || policy[0] == subject) &&
-				(domain == "" || policy[1] == domain) {
-				policies = append(policies, Policy{
-					Type:     PolicyTypePermission,
-					Subject:  policy[0],
-					Domain:   policy[1],
-					Resource: Resource(policy[2]),
-					Action:   action,
-				})
-			}
+			policies = append(policies, rule)
 		}
 	}
 
 	return policies, nil
 }
 
-// GetAllPolicies 获取所有权限策略
-func (e *Enforcer) GetAllPolicies() ([]Policy, error) {
-	return e.GetPolicies("", "")
-}
+// GetPoliciesForSubjects 批量获取多个主体在所有域的权限策略，每个主体按主体字段过滤查询
+// subjects 为存储中的主体标识，返回结果以存储中的主体标识为键，没有策略的主体不出现在结果中
+func (e *Enforcer) GetPoliciesForSubjects(subjects []string) (map[string][]Policy, error) {
+	policies := make(map[string][]Policy, len(subjects))
+	queried := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		// 空主体在过滤查询中表示不限制，会匹配所有策略
+		if subject == "" || queried[subject] {
+			continue
+		}
+		queried[subject] = true
 
-// ClearPolicies 清除指定主体的所有权限策略
-func (e *Enforcer) ClearPolicies(subject string) error {
+		matched, err := e.enforcer.GetFilteredPolicy(0, subject)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range matched {
+			if len(rule) < 4 {
+				continue
+			}
+			policy, err := e.toPolicy(rule)
+			if err != nil {
+				return nil, err
+			}
+			policies[subject] = append(policies[subject], policy)
+		}
+	}
+	return policies, nil
+}
 
-	policies, err := e.GetPolicies(subject, "")
+// RangePolicies 逐条遍历域内的权限策略（domain 为空时遍历所有域），fn 返回错误时停止遍历并返回该错误
+// 策略规则已在内存中，逐条转换后回调，不构造完整的结果列表
+func (e *Enforcer) RangePolicies(domain string, fn func(Policy) error) error {
+	matched, err := e.enforcer.GetFilteredPolicy(1, domain)
 	if err != nil {
 		return err
 	}
 
-	for _, policy := range policies {
-		_, err := e.enforcer.RemovePolicy(policy.Subject, policy.Domain, string(policy.Resource), string(policy.Action))
+	for _, rule := range matched {
+		if len(rule) < 4 {
+			continue
+		}
+		policy, err := e.toPolicy(rule)
 		if err != nil {
 			return err
 		}
+		if err := fn(policy); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
+// GetAllPolicies 获取所有权限策略
+func (e *Enforcer) GetAllPolicies() ([]Policy, error) {
+	return e.GetPolicies("", "")
+}
+
+// ClearPolicies 清除指定主体的所有权限策略
+func (e *Enforcer) ClearPolicies(ctx context.Context, subject string) error {
+	// 空主体在过滤查询中表示不限制，会匹配所有策略
+	if subject == "" {
+		return nil
+	}
+
+	defer e.invalidateCache()
+	return e.deleteFilteredRules(ctx, PolicyTypePermission, 0, subject)
+}
+
 // === 角色分配操作 ===
+// 角色分配相关方法的 userKey/roleKey 均为不带命名空间前缀的键，由执行器负责编码
 
 // AddGroupingPolicy 为用户分配角色
-func (e *Enforcer) AddGroupingPolicy(userKey, roleKey, domain string) error {
-	_, err := e.enforcer.AddRoleForUserInDomain(userKey, roleKey, domain)
-	return err
+func (e *Enforcer) AddGroupingPolicy(ctx context.Context, userKey, roleKey, domain string) error {
+	return e.AddGroupingPolicies(ctx, []GroupingPolicy{{UserKey: userKey, RoleKey: roleKey, TenantKey: domain}})
+}
+
+// AddGroupingPolicies 批量分配角色
+// 已存在的分配和重复项会被跳过；适配器在单个事务中写入，要么全部成功要么全部失败
+func (e *Enforcer) AddGroupingPolicies(ctx context.Context, policies []GroupingPolicy) error {
+	defer e.invalidateCache()
+
+	seen := make(map[GroupingPolicy]bool, len(policies))
+	rules := make([][]string, 0, len(policies))
+	for _, policy := range policies {
+		if seen[policy] {
+			continue
+		}
+		seen[policy] = true
+
+		if err := e.checkDomainWrite(policy.TenantKey); err != nil {
+			return err
+		}
+		rule := []string{e.UserSubject(policy.UserKey), e.RoleSubject(policy.RoleKey), policy.TenantKey}
+		exists, err := e.enforcer.HasGroupingPolicy(rule)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			rules = append(rules, rule)
+		}
+	}
+
+	return e.insertRules(ctx, PolicyTypeGrouping, rules)
 }
 
 // RemoveGroupingPolicy 移除用户角色
-func (e *Enforcer) RemoveGroupingPolicy(userKey, roleKey, domain string) error {
-	_, err := e.enforcer.DeleteRoleForUserInDomain(userKey, roleKey, domain)
-	return err
+func (e *Enforcer) RemoveGroupingPolicy(ctx context.Context, userKey, roleKey, domain string) error {
+	defer e.invalidateCache()
+	rule := []string{e.UserSubject(userKey), e.RoleSubject(roleKey), domain}
+	return e.deleteRules(ctx, PolicyTypeGrouping, [][]string{rule})
 }
 
-// GetRolesForUser 获取用户在指定域中的角色
+// GetRolesForUser 获取用户在指定域（含域别名）中的角色
 func (e *Enforcer) GetRolesForUser(userKey, domain string) ([]string, error) {
-	return e.enforcer.GetRolesForUserInDomain(userKey, domain), nil
+	userSubject := e.UserSubject(userKey)
+	var roles []string
+	seen := make(map[string]bool)
+	for _, equivalent := range e.equivalentDomains(domain) {
+		for _, role := range e.enforcer.GetRolesForUserInDomain(userSubject, equivalent) {
+			_, roleKey := e.decodeStoredSubject(role)
+			if !seen[roleKey] {
+				seen[roleKey] = true
+				roles = append(roles, roleKey)
+			}
+		}
+	}
+	return roles, nil
 }
 
 // ClearUserRoles 清除指定用户的所有角色分配
-func (e *Enforcer) ClearUserRoles(userKey string) error {
-	// 获取所有角色分配策略
-	allGroupPolicies, err := e.enforcer.GetGroupingPolicy()
+func (e *Enforcer) ClearUserRoles(ctx context.Context, userKey string) error {
+	// 空主体在过滤查询中表示不限制，会匹配所有角色分配
+	if userKey == "" {
+		return nil
+	}
+
+	defer e.invalidateCache()
+	return e.deleteFilteredRules(ctx, PolicyTypeGrouping, 0, e.UserSubject(userKey))
+}
+
+// ClearRoleAssignments 清除指定角色在所有域中的用户分配
+func (e *Enforcer) ClearRoleAssignments(ctx context.Context, roleKey string) error {
+	// 空主体在过滤查询中表示不限制，会匹配所有角色分配
+	if roleKey == "" {
+		return nil
+	}
+
+	defer e.invalidateCache()
+	for _, subject := range e.roleSubjectForms(roleKey) {
+		if err := e.deleteFilteredRules(ctx, PolicyTypeGrouping, 1, subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameSubject 将用户的所有权限策略和角色分配改写为新用户
+// 权限策略和角色分配分别在一个事务中批量更新；角色分配更新失败时回滚已更新的权限策略
+func (e *Enforcer) RenameSubject(ctx context.Context, oldKey, newKey string) (int, int, error) {
+	defer e.invalidateCache()
+	oldKey, newKey = e.UserSubject(oldKey), e.UserSubject(newKey)
+	// 空主体在过滤查询中表示不限制，会匹配所有策略和角色分配
+	if oldKey == "" {
+		return 0, 0, nil
+	}
+
+	matchedPolicies, err := e.enforcer.GetFilteredPolicy(0, oldKey)
 	if err != nil {
-		return err
+		return 0, 0, err
+	}
+	var oldPolicies, newPolicies [][]string
+	for _, policy := range matchedPolicies {
+		if len(policy) >= 4 {
+			oldPolicies = append(oldPolicies, policy)
+			renamed := append([]string{newKey}, policy[1:]...)
+			newPolicies = append(newPolicies, renamed)
+		}
 	}
 
-	// 找到所有该用户的角色分配并移除
-	for _, policy := range allGroupPolicies {
-		if len(policy) >= 3 && policy[0] == userKey {
-			_, err := e.enforcer.DeleteRoleForUserInDomain(policy[0], policy[1], policy[2])
-			if err != nil {
-				return err
+	matchedGroupings, err := e.enforcer.GetFilteredNamedGroupingPolicy("g", 0, oldKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	var oldGroupings, newGroupings [][]string
+	for _, grouping := range matchedGroupings {
+		if len(grouping) >= 3 {
+			oldGroupings = append(oldGroupings, grouping)
+			renamed := append([]string{newKey}, grouping[1:]...)
+			newGroupings = append(newGroupings, renamed)
+		}
+	}
+
+	if len(oldPolicies) > 0 {
+		if err := e.replaceRules(ctx, PolicyTypePermission, oldPolicies, newPolicies); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if len(oldGroupings) > 0 {
+		if err := e.replaceRules(ctx, PolicyTypeGrouping, oldGroupings, newGroupings); err != nil {
+			if len(oldPolicies) > 0 {
+				if rollbackErr := e.replaceRules(ctx, PolicyTypePermission, newPolicies, oldPolicies); rollbackErr != nil {
+					return 0, 0, fmt.Errorf("更新角色分配失败: %v，且回滚权限策略失败: %v", err, rollbackErr)
+				}
 			}
+			return 0, 0, err
 		}
 	}
 
-	return nil
+	return len(oldPolicies), len(oldGroupings), nil
 }
 
 // GetGroupingPolicies 获取所有角色分配策略
@@ -140,9 +467,11 @@ func (e *Enforcer) GetGroupingPolicies() ([]GroupingPolicy, error) {
 	var policies []GroupingPolicy
 	for _, policy := range allGroupPolicies {
 		if len(policy) >= 3 {
+			_, userKey := e.decodeStoredSubject(policy[0])
+			_, roleKey := e.decodeStoredSubject(policy[1])
 			policies = append(policies, GroupingPolicy{
-				UserKey:   policy[0],
-				RoleKey:   policy[1],
+				UserKey:   userKey,
+				RoleKey:   roleKey,
 				TenantKey: policy[2],
 			})
 		}
@@ -151,11 +480,65 @@ func (e *Enforcer) GetGroupingPolicies() ([]GroupingPolicy, error) {
 	return policies, nil
 }
 
+// RangeGroupingPolicies 逐条遍历域内的角色分配（domain 为空时遍历所有域），fn 返回错误时停止遍历并返回该错误
+func (e *Enforcer) RangeGroupingPolicies(domain string, fn func(GroupingPolicy) error) error {
+	matched, err := e.enforcer.GetFilteredGroupingPolicy(2, domain)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range matched {
+		if len(rule) < 3 {
+			continue
+		}
+		_, userKey := e.decodeStoredSubject(rule[0])
+		_, roleKey := e.decodeStoredSubject(rule[1])
+		if err := fn(GroupingPolicy{UserKey: userKey, RoleKey: roleKey, TenantKey: rule[2]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // === 权限检查操作 ===
 
 // CheckPermission 检查权限
-func (e *Enforcer) CheckPermission(subject, domain string, permission Permission) (bool, error) {
+func (e *Enforcer) CheckPermission(ctx context.Context, subject, domain string, permission Permission) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	start := time.Now()
+	key := decisionCacheKey(subject, domain, permission)
+	allowed, found := e.cachedDecision(key)
+	if !found {
+		revision := e.revision.Load()
+		var err error
+		allowed, err = e.checkPermission(subject, domain, permission)
+		if err != nil {
+			e.Metrics().ObserveCheck(domain, false, err, time.Since(start))
+			return false, err
+		}
+		// 检查期间缓存已失效时不写入，避免把失效前的结果放回缓存
+		if e.revision.Load() == revision {
+			e.cacheDecision(key, allowed)
+		}
+	}
+
+	e.Metrics().ObserveCheck(domain, allowed, nil, time.Since(start))
+	e.observeDecision(subject, domain, permission, allowed)
+	return allowed, nil
+}
+
+// checkPermission 检查权限（不通知决策观察者），按 CheckComparisonConfig 选择实现
+func (e *Enforcer) checkPermission(subject, domain string, permission Permission) (bool, error) {
+	if !e.checkAllowed(subject, domain) || e.denied(subject, domain, permission) {
+		return false, nil
+	}
+	return e.compareCheck(subject, domain, permission)
+}
 
+// customCheck 自定义实现的权限检查
+func (e *Enforcer) customCheck(subject, domain string, permission Permission) (bool, error) {
 	// 使用我们的跨域权限继承逻辑，而不是直接使用 Casbin Enforce
 	// 获取用户所有有效权限（包括跨域角色继承）
 	userPermissions, err := e.GetImplicitPermissions(subject, domain)
@@ -163,9 +546,9 @@ func (e *Enforcer) CheckPermission(subject, domain string, permission Permission
 		return false, err
 	}
 
-	// 检查目标权限是否在用户的有效权限中
+	// 检查用户的有效权限中是否有覆盖目标权限的授权（含对象通配符和类型级授权）
 	for _, userPerm := range userPermissions {
-		if userPerm.Resource == permission.Resource && userPerm.Action == permission.Action {
+		if e.covers(userPerm, permission) {
 			return true, nil
 		}
 	}
@@ -173,28 +556,102 @@ func (e *Enforcer) CheckPermission(subject, domain string, permission Permission
 	return false, nil
 }
 
-// GetImplicitPermissions 获取隐式权限（包括角色继承）
+// GetImplicitPermissions 获取隐式权限（包括角色继承），被拒绝规则覆盖的权限不包含在结果中
 func (e *Enforcer) GetImplicitPermissions(userKey, domain string) ([]Permission, error) {
+	rules, err := e.implicitRules(userKey, domain)
+	if err != nil {
+		return nil, err
+	}
 
+	permissions := make([]Permission, 0, len(rules))
+	for _, rule := range rules {
+		permissions = append(permissions, Permission{
+			Resource: rule.Resource,
+			Action:   rule.Action,
+		})
+	}
+
+	return e.withoutDenied(userKey, domain, permissions), nil
+}
+
+// implicitRules 获取用户在指定域中生效的所有规则（包括角色继承，已应用规则过滤器）
+// 附加了条件的规则需要请求属性才能评估，不包含在结果中
+// 启用权限集合缓存时从缓存读取
+func (e *Enforcer) implicitRules(userKey, domain string) ([]Policy, error) {
+	return e.cachedImplicitRules(userKey, domain)
+}
+
+// implicitRulesWithAttributes 获取用户在指定域中生效的所有规则，附加了条件的规则按请求属性评估
+func (e *Enforcer) implicitRulesWithAttributes(userKey, domain string, attrs map[string]any) ([]Policy, error) {
 	var allPolicies [][]string
+	userSubject := e.UserSubject(userKey)
+
+	domains := e.equivalentDomains(domain)
 
-	// 1. 获取用户在指定域的直接权限
-	userPolicies, err := e.enforcer.GetPermissionsForUser(userKey, domain)
-	if err == nil {
-		allPolicies = append(allPolicies, userPolicies...)
+	// 1. 获取用户在指定域（含域别名）的直接权限
+	for _, equivalent := range domains {
+		userPolicies, err := e.enforcer.GetPermissionsForUser(userSubject, equivalent)
+		if err == nil {
+			allPolicies = append(allPolicies, userPolicies...)
+		}
+	}
+
+	// 2. 获取用户在指定域（含别名）和全局域的角色（含继承），以及角色权限所在的域
+	// 3. 获取每个角色的权限（角色可能来自不同域，权限也可能定义在不同域）
+	roles := e.userRoles(userSubject, domain, domains)
+	roleRuleDomains := make(map[string]map[string]bool, len(roles))
+	for _, role := range roles {
+		roleRuleDomains[role.key] = make(map[string]bool, len(role.ruleDomains))
+		for _, checkDomain := range role.ruleDomains {
+			roleRuleDomains[role.key][checkDomain] = true
+			rolePolicies, err := e.enforcer.GetPermissionsForUser(role.subject, checkDomain)
+			if err == nil {
+				allPolicies = append(allPolicies, rolePolicies...)
+			}
+		}
 	}
 
-	// 2. 获取用户角色（检查指定域和全局域）
+	// 4. 转换为 Policy 结构（跳过被规则过滤器排除或条件不成立的策略）
+	rules := make([]Policy, 0, len(allPolicies))
+	for _, policy := range allPolicies {
+		if len(policy) >= 4 {
+			rule, err := e.toPolicy(policy)
+			if err != nil {
+				return nil, err
+			}
+			if !e.ruleApplies(rule) || !e.conditionHolds(rule, attrs) {
+				continue
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	// 5. 应用对该用户生效的灰度变更
+	return e.applyRollouts(userKey, domain, rules, roleRuleDomains)
+}
+
+// effectiveRole 用户在域中生效的角色
+type effectiveRole struct {
+	subject     string   // 策略存储中的角色主体
+	key         string   // 角色键
+	ruleDomains []string // 角色权限所在的域
+}
+
+// userRoles 获取用户在域中生效的角色（含角色继承）及角色权限所在的域
+// domains 为指定域及其别名；用户在全局域的角色同样生效
+func (e *Enforcer) userRoles(userSubject, domain string, domains []string) []effectiveRole {
 	var allRoles []string
 
-	// 2a. 获取用户在指定域的角色
-	tenantRoles := e.enforcer.GetRolesForUserInDomain(userKey, domain)
-	allRoles = append(allRoles, tenantRoles...)
+	// 获取用户在指定域（含域别名）的角色
+	for _, equivalent := range domains {
+		tenantRoles := e.enforcer.GetRolesForUserInDomain(userSubject, equivalent)
+		allRoles = append(allRoles, e.applicableRoles(userSubject, equivalent, tenantRoles)...)
+	}
 
-	// 2b. 获取用户在全局域的角色（如超级管理员）
+	// 获取用户在全局域的角色（如超级管理员）
 	if domain != "*" {
-		globalRoles := e.enforcer.GetRolesForUserInDomain(userKey, "*")
-		allRoles = append(allRoles, globalRoles...)
+		globalRoles := e.enforcer.GetRolesForUserInDomain(userSubject, "*")
+		allRoles = append(allRoles, e.applicableRoles(userSubject, "*", globalRoles)...)
 	}
 
 	// 去重角色
@@ -207,64 +664,53 @@ func (e *Enforcer) GetImplicitPermissions(userKey, domain string) ([]Permission,
 		}
 	}
 
-	// 3. 获取每个角色的权限
-	// 需要考虑角色可能来自不同域，权限也可能定义在不同域
 	domainsToCheck := []string{"*"} // 总是检查全局域
 	if domain != "*" {
-		domainsToCheck = append(domainsToCheck, domain) // 如果不是全局域，也检查指定域
+		domainsToCheck = append(domainsToCheck, domains...) // 如果不是全局域，也检查指定域及其别名
 	}
 
-	for _, role := range uniqueRoles {
-		// 在所有相关域中查找角色权限
-		for _, checkDomain := range domainsToCheck {
-			rolePolicies, err := e.enforcer.GetPermissionsForUser(role, checkDomain)
-			if err == nil {
-				allPolicies = append(allPolicies, rolePolicies...)
-			}
-		}
-	}
+	// 展开角色继承：在指定域（含别名）和全局域中定义的继承关系生效
+	uniqueRoles = e.expandInheritedRoles(uniqueRoles, domainsToCheck)
 
-	// 4. 转换为 Permission 结构
-	permissions := make([]Permission, 0, len(allPolicies))
-	for _, policy := range allPolicies {
-		if len(policy) >= 4 {
-			action, err := ParseAction(policy[3])
-			if err != nil {
-				return nil, err
-			}
-			permissions = append(permissions, Permission{
-				Resource: Resource(policy[2]),
-				Action:   action,
-			})
-		}
+	roles := make([]effectiveRole, 0, len(uniqueRoles))
+	for _, role := range uniqueRoles {
+		// 在所有相关域中查找角色权限（包含解析器提供的额外域）
+		_, roleKey := e.decodeStoredSubject(role)
+		roles = append(roles, effectiveRole{subject: role, key: roleKey, ruleDomains: e.roleDomains(roleKey, domain, domainsToCheck)})
 	}
-
-	return permissions, nil
+	return roles
 }
 
 // GetDirectPermissions 获取用户的直接权限（不包括角色继承）
 func (e *Enforcer) GetDirectPermissions(userKey, domain string) ([]Permission, error) {
-
-	// 获取所有策略，然后过滤出用户的直接权限（不包含角色权限）
-	allPolicies, err := e.enforcer.GetPolicy()
-	if err != nil {
-		return nil, err
+	if userKey == "" || domain == "" {
+		return nil, nil
 	}
 
+	// 按主体和域（含域别名）过滤查询用户的直接权限（不包含角色权限）
+	userSubject := e.UserSubject(userKey)
 	var permissions []Permission
-	for _, policy := range allPolicies {
-		if len(policy) >= 4 {
-			// 匹配主体和域，确保是用户的直接权限（不是角色权限）
-			if policy[0] == userKey && policy[1] == domain {
-				action, err := ParseAction(policy[3])
-				if err != nil {
-					return nil, err
-				}
-				permissions = append(permissions, Permission{
-					Resource: Resource(policy[2]),
-					Action:   action,
-				})
+	for _, equivalent := range e.equivalentDomains(domain) {
+		matched, err := e.enforcer.GetFilteredPolicy(0, userSubject, equivalent)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, policy := range matched {
+			if len(policy) < 4 {
+				continue
 			}
+			rule, err := e.toPolicy(policy)
+			if err != nil {
+				return nil, err
+			}
+			if !e.ruleApplies(rule) {
+				continue
+			}
+			permissions = append(permissions, Permission{
+				Resource: rule.Resource,
+				Action:   rule.Action,
+			})
 		}
 	}
 
@@ -289,16 +735,22 @@ func (e *Enforcer) IsRoleAssigned(userKey, roleKey, domain string) (bool, error)
 	return false, nil
 }
 
-// HasDirectPermission 检查是否有直接权限
+// HasDirectPermission 检查用户是否有直接权限
 func (e *Enforcer) HasDirectPermission(subject, domain string, permission Permission) (bool, error) {
-	policies, err := e.GetPolicies(subject, domain)
-	if err != nil {
-		return false, err
+	if !e.checkAllowed(subject, domain) || e.denied(subject, domain, permission) {
+		return false, nil
 	}
 
-	for _, policy := range policies {
-		if policy.Resource == permission.Resource && policy.Action == permission.Action {
-			return true, nil
+	for _, equivalent := range e.equivalentDomains(domain) {
+		policies, err := e.GetPolicies(e.UserSubject(subject), equivalent)
+		if err != nil {
+			return false, err
+		}
+
+		for _, policy := range policies {
+			if e.covers(policy.Permission(), permission) && e.ruleApplies(policy) {
+				return true, nil
+			}
 		}
 	}
 
@@ -307,13 +759,16 @@ func (e *Enforcer) HasDirectPermission(subject, domain string, permission Permis
 
 // IsRoleInUse 检查角色是否被使用（有用户分配了该角色）
 func (e *Enforcer) IsRoleInUse(roleKey string) (bool, error) {
-	groupPolicies, err := e.GetGroupingPolicies()
-	if err != nil {
-		return false, err
+	if roleKey == "" {
+		return false, nil
 	}
 
-	for _, policy := range groupPolicies {
-		if policy.RoleKey == roleKey {
+	for _, roleSubject := range e.roleSubjectForms(roleKey) {
+		assignments, err := e.enforcer.GetFilteredGroupingPolicy(1, roleSubject)
+		if err != nil {
+			return false, err
+		}
+		if len(assignments) > 0 {
 			return true, nil
 		}
 	}
@@ -322,24 +777,275 @@ func (e *Enforcer) IsRoleInUse(roleKey string) (bool, error) {
 }
 
 // GetUsersWithRole 获取拥有指定角色的所有用户
+// domain 为空时返回所有域中的用户
 func (e *Enforcer) GetUsersWithRole(roleKey, domain string) ([]string, error) {
+	if roleKey == "" {
+		return nil, nil
+	}
 
-	groupPolicies, err := e.GetGroupingPolicies()
-	if err != nil {
-		return nil, err
+	var users []string
+	for _, roleSubject := range e.roleSubjectForms(roleKey) {
+		assignments, err := e.enforcer.GetFilteredGroupingPolicy(1, roleSubject, domain)
+		if err != nil {
+			return nil, err
+		}
+		for _, assignment := range assignments {
+			if len(assignment) >= 3 {
+				_, userKey := e.decodeStoredSubject(assignment[0])
+				users = append(users, userKey)
+			}
+		}
+	}
+
+	return users, nil
+}
+
+// GetUsersWithPermission 获取在域中拥有目标权限的用户（直接授权和角色授权，含角色继承、全局角色和域别名）
+// 候选用户为在域（含别名）中有直接授权或在域（含别名）和全局域中有角色分配的用户，逐个按权限检查的规则判断，
+// 被拒绝规则覆盖或被检查守卫拦截的用户不在结果中；附加了条件的规则需要请求属性才能评估，不计入结果。
+// 未启用主体命名空间时通过 isRole 区分策略主体是用户还是角色
+func (e *Enforcer) GetUsersWithPermission(domain string, permission Permission, isRole func(key string) (bool, error)) ([]string, error) {
+	if domain == "" || !permission.IsValid() {
+		return nil, nil
 	}
 
+	domains := e.equivalentDomains(domain)
+	var subjects []string
+	assignedRoles := make(map[string]bool)
+	for _, equivalent := range domains {
+		policies, err := e.enforcer.GetFilteredPolicy(1, equivalent)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			if len(policy) >= 4 {
+				subjects = append(subjects, policy[0])
+			}
+		}
+	}
+	groupingDomains := domains
+	if domain != "*" {
+		groupingDomains = append(append([]string{}, domains...), "*")
+	}
+	for _, groupingDomain := range groupingDomains {
+		groupings, err := e.enforcer.GetFilteredGroupingPolicy(2, groupingDomain)
+		if err != nil {
+			return nil, err
+		}
+		for _, grouping := range groupings {
+			if len(grouping) >= 3 {
+				subjects = append(subjects, grouping[0])
+				assignedRoles[grouping[1]] = true
+			}
+		}
+	}
+
+	checked := make(map[string]bool, len(subjects))
 	var users []string
-	for _, policy := range groupPolicies {
-		if policy.RoleKey == roleKey && (domain == "" || policy.TenantKey == domain) {
-			users = append(users, policy.UserKey)
+	for _, subject := range subjects {
+		if checked[subject] || assignedRoles[subject] {
+			continue
+		}
+		checked[subject] = true
+
+		kind, key := e.decodeStoredSubject(subject)
+		if kind == SubjectKindRole {
+			continue
+		}
+		if kind == "" && isRole != nil {
+			role, err := isRole(key)
+			if err != nil {
+				return nil, err
+			}
+			if role {
+				continue
+			}
+		}
+
+		allowed, err := e.checkPermission(key, domain, permission)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			users = append(users, key)
 		}
 	}
 
+	sort.Strings(users)
 	return users, nil
 }
 
+// roleSubjectForms 返回角色在角色分配中可能的存储形式
+// 启用命名空间后，迁移前写入的不带前缀的角色分配仍按角色处理
+func (e *Enforcer) roleSubjectForms(roleKey string) []string {
+	roleSubject := e.RoleSubject(roleKey)
+	if roleSubject == roleKey {
+		return []string{roleKey}
+	}
+	return []string{roleSubject, roleKey}
+}
+
+// roleDomains 计算角色权限需要查询的域列表
+func (e *Enforcer) roleDomains(roleKey, domain string, baseDomains []string) []string {
+	e.mu.RLock()
+	resolver := e.roleDomainResolver
+	e.mu.RUnlock()
+
+	if resolver == nil {
+		return baseDomains
+	}
+
+	domains := append([]string{}, baseDomains...)
+	for _, extra := range resolver(roleKey, domain) {
+		duplicated := false
+		for _, existing := range domains {
+			if existing == extra {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			domains = append(domains, extra)
+		}
+	}
+
+	return domains
+}
+
+// AddRuleFilter 注册权限检查时应用的规则过滤器
+func (e *Enforcer) AddRuleFilter(filter RuleFilter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ruleFilters = append(e.ruleFilters, filter)
+}
+
+// ruleApplies 检查策略规则是否通过所有过滤器
+func (e *Enforcer) ruleApplies(rule Policy) bool {
+	e.mu.RLock()
+	filters := e.ruleFilters
+	e.mu.RUnlock()
+
+	for _, filter := range filters {
+		if !filter(rule) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddAssignmentFilter 注册权限检查时应用的角色分配过滤器
+func (e *Enforcer) AddAssignmentFilter(filter AssignmentFilter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.assignmentFilters = append(e.assignmentFilters, filter)
+}
+
+// applicableRoles 过滤用户在域中被角色分配过滤器排除的角色（角色为存储中的主体标识）
+func (e *Enforcer) applicableRoles(userSubject, domain string, roles []string) []string {
+	e.mu.RLock()
+	filters := e.assignmentFilters
+	e.mu.RUnlock()
+
+	if len(filters) == 0 {
+		return roles
+	}
+
+	_, userKey := e.decodeStoredSubject(userSubject)
+	applicable := make([]string, 0, len(roles))
+	for _, role := range roles {
+		_, roleKey := e.decodeStoredSubject(role)
+		applies := true
+		for _, filter := range filters {
+			if !filter(userKey, roleKey, domain) {
+				applies = false
+				break
+			}
+		}
+		if applies {
+			applicable = append(applicable, role)
+		}
+	}
+	return applicable
+}
+
+// AddCheckGuard 注册权限检查守卫
+func (e *Enforcer) AddCheckGuard(guard CheckGuard) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.checkGuards = append(e.checkGuards, guard)
+}
+
+// checkAllowed 检查主体在域内是否通过所有守卫
+func (e *Enforcer) checkAllowed(subject, domain string) bool {
+	e.mu.RLock()
+	guards := e.checkGuards
+	e.mu.RUnlock()
+
+	for _, guard := range guards {
+		if !guard(subject, domain) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetRoleDomainResolver 设置角色权限域解析器
+func (e *Enforcer) SetRoleDomainResolver(resolver RoleDomainResolver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.roleDomainResolver = resolver
+}
+
 // === Watcher 管理方法 ===
 
+// SetWatcher 设置策略同步 Watcher
+func (e *Enforcer) SetWatcher(watcher persist.Watcher) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.watcher = watcher
+}
+
+// NotifyWatcher 通知其他实例重新加载策略
+// 用于策略之外的状态（如角色共享、租户封锁）变更后的多副本同步，未配置 Watcher 时忽略
+func (e *Enforcer) NotifyWatcher() error {
+	e.invalidateCache()
+
+	e.mu.RLock()
+	watcher := e.watcher
+	suspended := e.notifySuspended > 0
+	e.mu.RUnlock()
+
+	// 暂停期间的通知合并到恢复时发布的一次通知中
+	if watcher == nil || suspended {
+		return nil
+	}
+	return watcher.Update()
+}
+
+// AddReloadHook 注册策略重新加载后的回调
+// 依赖数据库状态的内存缓存通过该回调在收到同步通知时刷新
+func (e *Enforcer) AddReloadHook(hook func() error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reloadHooks = append(e.reloadHooks, hook)
+}
+
 // LoadPolicy 手动重新加载策略（用于Watcher同步）
-func (e *Enforcer) LoadPolicy() error { return e.enforcer.LoadPolicy() }
+func (e *Enforcer) LoadPolicy() error {
+	defer e.invalidateCache()
+	if err := e.enforcer.LoadPolicy(); err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	hooks := append([]func() error{}, e.reloadHooks...)
+	e.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// benchmarkPolicies 基准测试的策略规模
+const benchmarkPolicies = 100000
+
+// newBenchmarkEnforcer 创建包含 100k 条权限策略的执行器：
+// 10000 个用户分布在 10 个租户，每人 10 条直接授权，并各分配 100 个角色中的一个
+func newBenchmarkEnforcer(b *testing.B) *Enforcer {
+	b.Helper()
+	e := newTestEnforcer(b)
+	ctx := context.Background()
+
+	const perUser = 10
+	permissions := make([]Permission, 0, perUser)
+	for i := 0; i < perUser; i++ {
+		permissions = append(permissions, Permission{Resource: Resource(fmt.Sprintf("resource%d", i)), Action: ActionRead})
+	}
+
+	groupings := make([]GroupingPolicy, 0, benchmarkPolicies/perUser)
+	for i := 0; i < benchmarkPolicies/perUser; i++ {
+		userKey, tenantKey := fmt.Sprintf("user%d", i), fmt.Sprintf("t%d", i%10)
+		if err := e.AddPolicies(ctx, e.UserSubject(userKey), tenantKey, permissions); err != nil {
+			b.Fatalf("AddPolicies() error = %v", err)
+		}
+		groupings = append(groupings, GroupingPolicy{UserKey: userKey, RoleKey: fmt.Sprintf("role%d", i%100), TenantKey: tenantKey})
+	}
+	if err := e.AddGroupingPolicies(ctx, groupings); err != nil {
+		b.Fatalf("AddGroupingPolicies() error = %v", err)
+	}
+	return e
+}
+
+func BenchmarkGetPolicies(b *testing.B) {
+	e := newBenchmarkEnforcer(b)
+	subject := e.UserSubject("user4242")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.GetPolicies(subject, "t2"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetDirectPermissions(b *testing.B) {
+	e := newBenchmarkEnforcer(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.GetDirectPermissions("user4242", "t2"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetUsersWithRole(b *testing.B) {
+	e := newBenchmarkEnforcer(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.GetUsersWithRole("role42", "t2"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGetPoliciesForSubjects(t *testing.T) {
+	e := newTestEnforcer(t)
+	ctx := context.Background()
+	if err := e.AddPolicies(ctx, "alice", "t1", []Permission{userRead, userWrite}); err != nil {
+		t.Fatalf("AddPolicies() error = %v", err)
+	}
+	if err := e.AddPolicy(ctx, "bob", "t2", userRead); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		subjects []string
+		want     map[string]int
+	}{
+		{name: "多个主体", subjects: []string{"alice", "bob"}, want: map[string]int{"alice": 2, "bob": 1}},
+		{name: "重复主体只查询一次", subjects: []string{"alice", "alice"}, want: map[string]int{"alice": 2}},
+		{name: "空主体不匹配所有策略", subjects: []string{""}, want: map[string]int{}},
+		{name: "没有策略的主体不出现在结果中", subjects: []string{"carol", "bob"}, want: map[string]int{"bob": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := e.GetPoliciesForSubjects(tt.subjects)
+			if err != nil {
+				t.Fatalf("GetPoliciesForSubjects() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetPoliciesForSubjects() = %v, want counts %v", got, tt.want)
+			}
+			for subject, count := range tt.want {
+				if len(got[subject]) != count {
+					t.Fatalf("GetPoliciesForSubjects()[%s] = %v, want %d policies", subject, got[subject], count)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameSubjectFiltered(t *testing.T) {
+	e := newTestEnforcer(t)
+	ctx := context.Background()
+	if err := e.AddPolicy(ctx, "alice", "t1", userRead); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	if err := e.AddPolicy(ctx, "bob", "t1", userWrite); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	if err := e.AddGroupingPolicy(ctx, "alice", "admin", "t1"); err != nil {
+		t.Fatalf("AddGroupingPolicy() error = %v", err)
+	}
+
+	// 空主体不改写任何规则
+	if policies, groupings, err := e.RenameSubject(ctx, "", "carol"); err != nil || policies != 0 || groupings != 0 {
+		t.Fatalf("RenameSubject(\"\") = %d, %d, %v, want 0, 0, nil", policies, groupings, err)
+	}
+
+	policies, groupings, err := e.RenameSubject(ctx, "alice", "carol")
+	if err != nil || policies != 1 || groupings != 1 {
+		t.Fatalf("RenameSubject() = %d, %d, %v, want 1, 1, nil", policies, groupings, err)
+	}
+	if exists, _ := e.HasPolicy("carol", "t1", userRead); !exists {
+		t.Fatalf("renamed policy missing")
+	}
+	if exists, _ := e.HasPolicy("bob", "t1", userWrite); !exists {
+		t.Fatalf("other subject's policy changed")
+	}
+	if roles, _ := e.GetRolesForUser("carol", "t1"); len(roles) != 1 || roles[0] != "admin" {
+		t.Fatalf("GetRolesForUser(carol) = %v, want [admin]", roles)
+	}
+}
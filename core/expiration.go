@@ -0,0 +1,43 @@
+package core
+
+import "time"
+
+// DefaultExpirationReapInterval 清理已过期授权的默认间隔
+const DefaultExpirationReapInterval = time.Minute
+
+// ExpirationConfig 限时授权配置
+type ExpirationConfig struct {
+	// Disabled 关闭本实例的过期清理（由其他实例清理），权限检查时仍忽略已过期的授权
+	Disabled bool `json:"disabled"`
+
+	// ReapInterval 撤销已过期授权的间隔，默认 1m
+	ReapInterval time.Duration `json:"reapInterval"`
+}
+
+// ExpiringGrantKind 限时授权类型
+type ExpiringGrantKind string
+
+const (
+	ExpiringPermission ExpiringGrantKind = "permission" // 用户直接权限（GrantTemporaryPermission）
+	ExpiringRole       ExpiringGrantKind = "role"       // 角色分配（AssignTemporaryRole）
+)
+
+// ExpiringGrant 到期自动撤销的授权
+// 过期后权限检查立即忽略该授权，策略由后台清理按 Config.Expiration.ReapInterval 撤销
+type ExpiringGrant struct {
+	Kind        ExpiringGrantKind `json:"kind"`                // 授权类型
+	UserKey     string            `json:"userKey"`             // 被授权用户
+	TenantKey   string            `json:"tenantKey"`           // 授权所在租户
+	Permission  Permission        `json:"permission,omitzero"` // 权限（Kind 为 permission 时有效）
+	RoleKey     string            `json:"roleKey,omitempty"`   // 角色（Kind 为 role 时有效）
+	OperatorKey string            `json:"operatorKey"`         // 授权的操作者
+	ExpiresAt   time.Time         `json:"expiresAt"`           // 过期时间
+	CreatedAt   time.Time         `json:"createdAt"`           // 设置过期时间的时间
+}
+
+// ExpiringGrantFilter 限时授权查询条件，空字段表示不限制
+type ExpiringGrantFilter struct {
+	TenantKey string    `json:"tenantKey"` // 租户
+	UserKey   string    `json:"userKey"`   // 被授权用户
+	Before    time.Time `json:"before"`    // 只返回在该时间之前过期的授权
+}
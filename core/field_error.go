@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+)
+
+// 参数约束（FieldError.Constraint）
+const (
+	ConstraintRequired  = "required"   // 不能为空
+	ConstraintNotGlobal = "not_global" // 不能是全局域 *
+	ConstraintDistinct  = "distinct"   // 不能与另一个参数相同
+	ConstraintFormat    = "format"     // 格式无效，如包含保留字符
+	ConstraintRange     = "range"      // 取值超出允许范围
+	ConstraintOneOf     = "one_of"     // 不是允许的取值之一
+)
+
+// constraintText 约束的说明文字
+var constraintText = map[string]string{
+	ConstraintRequired:  "不能为空",
+	ConstraintNotGlobal: "不能是全局域 *",
+	ConstraintDistinct:  "不能与其他参数相同",
+	ConstraintFormat:    "格式无效",
+	ConstraintRange:     "超出允许范围",
+	ConstraintOneOf:     "不是允许的取值",
+}
+
+// FieldError 参数校验错误，指明不满足约束的参数
+// 通过 errors.Is(err, ErrInvalidParameter) 判断仍然成立，errors.As 可取出具体参数
+type FieldError struct {
+	Field      string `json:"field"`            // 参数名，结构体字段为 "参数.字段"，如 permission.resource
+	Constraint string `json:"constraint"`       // 不满足的约束（Constraint 常量）
+	Detail     string `json:"detail,omitempty"` // 补充说明（可选）
+}
+
+// NewFieldError 创建参数校验错误
+func NewFieldError(field, constraint string) *FieldError {
+	return &FieldError{Field: field, Constraint: constraint}
+}
+
+// Error 返回错误信息
+func (e *FieldError) Error() string {
+	text, ok := constraintText[e.Constraint]
+	if !ok {
+		text = "不满足约束 " + e.Constraint
+	}
+	message := fmt.Sprintf("%s: %s %s", ErrInvalidParameter.Error(), e.Field, text)
+	if e.Detail != "" {
+		message += "（" + e.Detail + "）"
+	}
+	return message
+}
+
+// Unwrap 返回 ErrInvalidParameter，保持与原有错误判断兼容
+func (e *FieldError) Unwrap() error {
+	return ErrInvalidParameter
+}
+
+// RequireArg 校验参数不为空
+func RequireArg[T ~string](field string, value T) error {
+	if value == "" {
+		return NewFieldError(field, ConstraintRequired)
+	}
+	return nil
+}
+
+// RequireTenantArg 校验租户参数不为空且不是全局域
+func RequireTenantArg(field, tenantKey string) error {
+	if tenantKey == "" {
+		return NewFieldError(field, ConstraintRequired)
+	}
+	if tenantKey == "*" {
+		return NewFieldError(field, ConstraintNotGlobal)
+	}
+	return nil
+}
+
+// RequireItems 校验列表参数不为空
+func RequireItems[T any](field string, items []T) error {
+	if len(items) == 0 {
+		return NewFieldError(field, ConstraintRequired)
+	}
+	return nil
+}
+
+// RequirePermissionArg 校验权限参数的资源和操作不为空
+func RequirePermissionArg(field string, permission Permission) error {
+	if permission.Resource == "" {
+		return NewFieldError(field+".resource", ConstraintRequired)
+	}
+	if permission.Action == "" {
+		return NewFieldError(field+".action", ConstraintRequired)
+	}
+	return nil
+}
+
+// CheckArg valid 为 false 时返回参数不满足 constraint 的错误
+func CheckArg(valid bool, field, constraint string) error {
+	if !valid {
+		return NewFieldError(field, constraint)
+	}
+	return nil
+}
+
+// FirstError 返回第一个非空错误，用于按顺序校验多个参数
+func FirstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
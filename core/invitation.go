@@ -0,0 +1,28 @@
+package core
+
+import "time"
+
+// ShareInvitation 分享邀请（"通过链接分享"）
+// 持有令牌的用户接受邀请后获得邀请中的整组权限；令牌仅在创建时返回，数据库只保存其摘要
+type ShareInvitation struct {
+	ID          string       `json:"id"`              // 邀请ID
+	Token       string       `json:"token,omitempty"` // 邀请令牌（仅创建时返回）
+	TenantKey   string       `json:"tenantKey"`       // 授权所在租户
+	Permissions []Permission `json:"permissions"`     // 接受后授予的权限
+	CreatedBy   string       `json:"createdBy"`       // 创建者
+	CreatedAt   time.Time    `json:"createdAt"`       // 创建时间
+	ExpiresAt   time.Time    `json:"expiresAt"`       // 过期时间
+	MaxUses     int          `json:"maxUses"`         // 最大使用次数
+	UseCount    int          `json:"useCount"`        // 已使用次数
+	Revoked     bool         `json:"revoked"`         // 是否已撤销
+}
+
+// InvitationGrant 通过分享邀请获得的授权记录（授权来源）
+type InvitationGrant struct {
+	InvitationID string     `json:"invitationId"` // 来源邀请ID
+	UserKey      string     `json:"userKey"`      // 接受邀请的用户
+	TenantKey    string     `json:"tenantKey"`    // 授权所在租户
+	Permission   Permission `json:"permission"`   // 授予的权限
+	InvitedBy    string     `json:"invitedBy"`    // 邀请创建者
+	AcceptedAt   time.Time  `json:"acceptedAt"`   // 接受时间
+}
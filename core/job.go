@@ -0,0 +1,61 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobKind 异步任务类型，对应一个耗时的批量操作
+type JobKind string
+
+const (
+	JobKindImportRelationTuples JobKind = "import_relation_tuples" // 导入关系元组（ImportRelationTuples）
+	JobKindProvisionTenants     JobKind = "provision_tenants"      // 批量开通租户（ProvisionTenants）
+	JobKindDeleteTenant         JobKind = "delete_tenant"          // 删除租户（DeleteTenant）
+)
+
+// JobStatus 异步任务状态
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"   // 执行中
+	JobStatusSucceeded JobStatus = "succeeded" // 执行完成（批量操作中单项失败记录在结果中）
+	JobStatusFailed    JobStatus = "failed"    // 执行失败，已完成的步骤不会回滚
+	JobStatusCanceled  JobStatus = "canceled"  // 已取消，取消前完成的步骤不会回滚
+)
+
+// Finished 任务是否已结束
+func (s JobStatus) Finished() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+// JobSpec 异步任务参数，按 Kind 填写对应字段
+type JobSpec struct {
+	Kind           JobKind         `json:"kind"`                     // 任务类型
+	RelationTuples []RelationTuple `json:"relationTuples,omitempty"` // 导入的关系元组（import_relation_tuples）
+	Tenants        []TenantSpec    `json:"tenants,omitempty"`        // 开通的租户（provision_tenants）
+	TenantKey      string          `json:"tenantKey,omitempty"`      // 删除的租户（delete_tenant）
+}
+
+// JobProgress 异步任务进度
+type JobProgress struct {
+	Done  int `json:"done"`  // 已处理的项数
+	Total int `json:"total"` // 总项数
+}
+
+// Job 异步任务
+// 任务在提交它的实例上执行并把状态持久化到数据库，任意实例都可以查询进度和请求取消；
+// 执行实例在任务结束前退出时，任务会停留在 running 状态，UpdatedAt 不再更新
+type Job struct {
+	ID              string          `json:"id"`                  // 任务ID
+	Kind            JobKind         `json:"kind"`                // 任务类型
+	Status          JobStatus       `json:"status"`              // 任务状态
+	OperatorKey     string          `json:"operatorKey"`         // 提交任务的操作者
+	Progress        JobProgress     `json:"progress"`            // 执行进度
+	Result          json.RawMessage `json:"result,omitempty"`    // 结束后的操作结果（与同步方法的返回值相同）
+	Error           string          `json:"error,omitempty"`     // 失败原因
+	CancelRequested bool            `json:"cancelRequested"`     // 是否已请求取消
+	CreatedAt       time.Time       `json:"createdAt"`           // 提交时间
+	UpdatedAt       time.Time       `json:"updatedAt"`           // 最近一次进度更新时间
+	FinishedAt      time.Time       `json:"finishedAt,omitzero"` // 结束时间
+}
@@ -0,0 +1,35 @@
+package core
+
+import "time"
+
+// JobFunction 岗位目录条目：HR 系统的岗位编码对应租户内的一组角色
+// 入职、调岗、离职时通过 SyncUserJobFunction 按岗位调整用户的角色分配
+type JobFunction struct {
+	TenantKey string    `json:"tenantKey"` // 岗位所在租户
+	JobCode   string    `json:"jobCode"`   // 岗位编码（来自 HR 系统）
+	Name      string    `json:"name"`      // 岗位名称
+	RoleKeys  []string  `json:"roleKeys"`  // 岗位对应的角色
+	UpdatedBy string    `json:"updatedBy"` // 最后修改者
+	UpdatedAt time.Time `json:"updatedAt"` // 最后修改时间
+}
+
+// UserJobFunction 用户在租户内当前同步的岗位
+type UserJobFunction struct {
+	UserKey      string    `json:"userKey"`      // 用户
+	TenantKey    string    `json:"tenantKey"`    // 租户
+	JobCode      string    `json:"jobCode"`      // 岗位编码
+	ManagedRoles []string  `json:"managedRoles"` // 由岗位同步分配的角色（同步前已手动分配的角色不在其中）
+	SyncedBy     string    `json:"syncedBy"`     // 同步操作者
+	SyncedAt     time.Time `json:"syncedAt"`     // 同步时间
+}
+
+// JobFunctionSyncResult 岗位同步结果
+type JobFunctionSyncResult struct {
+	UserKey         string   `json:"userKey"`         // 用户
+	TenantKey       string   `json:"tenantKey"`       // 租户
+	PreviousJobCode string   `json:"previousJobCode"` // 同步前的岗位，首次同步时为空
+	JobCode         string   `json:"jobCode"`         // 同步后的岗位，离职时为空
+	AssignedRoles   []string `json:"assignedRoles"`   // 新分配的角色
+	RemovedRoles    []string `json:"removedRoles"`    // 移除的角色（只移除此前由岗位同步分配的角色）
+	KeptRoles       []string `json:"keptRoles"`       // 岗位要求且用户已手动持有的角色，不由岗位管理
+}
@@ -0,0 +1,211 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// RuleJournal 规则变更日志：记录期间经执行器实际新增和删除的权限策略与角色分配
+// 用于多步变更失败后只撤销这些变更，不影响其他实例在此期间写入的规则
+type RuleJournal struct {
+	mu      sync.Mutex
+	entries []journalEntry
+}
+
+// journalEntry 一次写入实际新增或删除的规则
+type journalEntry struct {
+	section PolicyType // 策略类型
+	added   bool       // true 为新增，false 为删除
+	rules   [][]string // 规则副本
+}
+
+// record 追加一次写入
+func (j *RuleJournal) record(section PolicyType, added bool, rules [][]string) {
+	if len(rules) == 0 {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, journalEntry{section: section, added: added, rules: copyRules(rules)})
+}
+
+// BeginJournal 开始记录规则变更，已有日志在记录时返回错误
+// 记录期间本执行器的所有规则写入都会进入日志，调用方需保证期间只有一个写入方
+func (e *Enforcer) BeginJournal() (*RuleJournal, error) {
+	journal := &RuleJournal{}
+	if !e.journal.CompareAndSwap(nil, journal) {
+		return nil, errors.New("已有规则变更日志在记录中")
+	}
+	return journal, nil
+}
+
+// EndJournal 停止记录规则变更，可以重复调用
+func (e *Enforcer) EndJournal(journal *RuleJournal) {
+	e.journal.CompareAndSwap(journal, nil)
+}
+
+// RevertJournal 按相反顺序撤销日志中的变更：删除新增的规则、补回删除的规则（经适配器持久化）
+// 撤销前确认规则的当前状态，已被其他调用方删除或补回的规则跳过；单步失败时继续撤销其余变更并返回所有错误
+func (e *Enforcer) RevertJournal(ctx context.Context, journal *RuleJournal) error {
+	e.EndJournal(journal)
+	defer e.invalidateCache()
+
+	journal.mu.Lock()
+	entries := journal.entries
+	journal.entries = nil
+	journal.mu.Unlock()
+
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		// 撤销新增时只删除仍然存在的规则，撤销删除时只补回仍然不存在的规则
+		rules, err := e.rulesWithState(entry.section, entry.rules, entry.added)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(rules) == 0 {
+			continue
+		}
+
+		if entry.added {
+			_, err = e.removeSectionRules(ctx, entry.section, rules)
+		} else {
+			_, err = e.addSectionRules(ctx, entry.section, rules)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// journalRules 将实际写入的规则记录到当前的规则变更日志（未在记录时忽略）
+func (e *Enforcer) journalRules(section PolicyType, added bool, rules [][]string) {
+	if journal := e.journal.Load(); journal != nil {
+		journal.record(section, added, rules)
+	}
+}
+
+// insertRules 新增规则并记录到规则变更日志
+// Casbin 的批量接口在部分规则已存在时会整体跳过，调用方需先过滤已存在的规则
+func (e *Enforcer) insertRules(ctx context.Context, section PolicyType, rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	ok, err := e.addSectionRules(ctx, section, rules)
+	if ok {
+		e.journalRules(section, true, rules)
+	}
+	return err
+}
+
+// deleteRules 删除规则并记录到规则变更日志，不存在的规则会被跳过
+func (e *Enforcer) deleteRules(ctx context.Context, section PolicyType, rules [][]string) error {
+	existing, err := e.rulesWithState(section, rules, true)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	ok, err := e.removeSectionRules(ctx, section, existing)
+	if ok {
+		e.journalRules(section, false, existing)
+	}
+	return err
+}
+
+// deleteFilteredRules 按字段过滤删除规则并记录到规则变更日志
+func (e *Enforcer) deleteFilteredRules(ctx context.Context, section PolicyType, fieldIndex int, fieldValues ...string) error {
+	var matched [][]string
+	var err error
+	if section == PolicyTypeGrouping {
+		matched, err = e.enforcer.GetFilteredGroupingPolicy(fieldIndex, fieldValues...)
+	} else {
+		matched, err = e.enforcer.GetFilteredPolicy(fieldIndex, fieldValues...)
+	}
+	if err != nil || len(matched) == 0 {
+		return err
+	}
+	return e.deleteRules(ctx, section, matched)
+}
+
+// replaceRules 将规则一一替换为新规则并记录到规则变更日志
+func (e *Enforcer) replaceRules(ctx context.Context, section PolicyType, oldRules, newRules [][]string) error {
+	if len(oldRules) == 0 {
+		return nil
+	}
+	ok, err := e.persistWrite(ctx, func() (bool, error) {
+		if section == PolicyTypeGrouping {
+			return e.enforcer.UpdateGroupingPolicies(oldRules, newRules)
+		}
+		return e.enforcer.UpdatePolicies(oldRules, newRules)
+	})
+	if ok {
+		e.journalRules(section, false, oldRules)
+		e.journalRules(section, true, newRules)
+	}
+	return err
+}
+
+// addSectionRules 按策略类型批量新增规则，不记录日志
+func (e *Enforcer) addSectionRules(ctx context.Context, section PolicyType, rules [][]string) (bool, error) {
+	return e.persistWrite(ctx, func() (bool, error) {
+		if section == PolicyTypeGrouping {
+			return e.enforcer.AddGroupingPolicies(rules)
+		}
+		return e.enforcer.AddPolicies(rules)
+	})
+}
+
+// removeSectionRules 按策略类型批量删除规则，不记录日志
+func (e *Enforcer) removeSectionRules(ctx context.Context, section PolicyType, rules [][]string) (bool, error) {
+	return e.persistWrite(ctx, func() (bool, error) {
+		if section == PolicyTypeGrouping {
+			return e.enforcer.RemoveGroupingPolicies(rules)
+		}
+		return e.enforcer.RemovePolicies(rules)
+	})
+}
+
+// rulesWithState 过滤规则，exists 为 true 时只保留已存在的规则，否则只保留不存在的规则（重复项只保留一条）
+func (e *Enforcer) rulesWithState(section PolicyType, rules [][]string, exists bool) ([][]string, error) {
+	seen := make(map[string]bool, len(rules))
+	var filtered [][]string
+	for _, rule := range rules {
+		key := ruleKey(rule)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var found bool
+		var err error
+		if section == PolicyTypeGrouping {
+			found, err = e.enforcer.HasGroupingPolicy(rule)
+		} else {
+			found, err = e.enforcer.HasPolicy(rule)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if found == exists {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered, nil
+}
+
+// copyRules 复制规则列表，避免日志引用 Casbin 内部的切片
+func copyRules(rules [][]string) [][]string {
+	copied := make([][]string, len(rules))
+	for i, rule := range rules {
+		copied[i] = append([]string(nil), rule...)
+	}
+	return copied
+}
+
+// ruleKey 生成规则的比较键
+func ruleKey(rule []string) string {
+	return strings.Join(rule, "\x00")
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+)
+
+// newTestEnforcer 创建使用内置模型、不带适配器的执行器
+func newTestEnforcer(t testing.TB) *Enforcer {
+	t.Helper()
+	m, err := model.NewModelFromString(DefaultModelText)
+	if err != nil {
+		t.Fatalf("NewModelFromString() error = %v", err)
+	}
+	casbinEnforcer, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatalf("casbin.NewEnforcer() error = %v", err)
+	}
+	e, err := NewEnforcer(casbinEnforcer)
+	if err != nil {
+		t.Fatalf("NewEnforcer() error = %v", err)
+	}
+	return e
+}
+
+func TestRevertJournal(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		// before 在日志开始前写入
+		before func(e *Enforcer) error
+		// during 在日志记录期间经执行器写入
+		during func(e *Enforcer) error
+		// concurrent 模拟其他实例在此期间的写入（不经过日志）
+		concurrent    func(e *Enforcer) error
+		wantPolicies  map[string]bool
+		wantGroupings map[string]bool
+	}{
+		{
+			name: "撤销新增的权限策略",
+			during: func(e *Enforcer) error {
+				return e.AddPolicies(ctx, "alice", "t1", []Permission{userRead, userWrite})
+			},
+			wantPolicies: map[string]bool{"alice|t1|user|read": false, "alice|t1|user|write": false},
+		},
+		{
+			name: "补回删除的权限策略",
+			before: func(e *Enforcer) error {
+				return e.AddPolicies(ctx, "alice", "t1", []Permission{userRead, userWrite})
+			},
+			during: func(e *Enforcer) error {
+				return e.ClearPolicies(ctx, "alice")
+			},
+			wantPolicies: map[string]bool{"alice|t1|user|read": true, "alice|t1|user|write": true},
+		},
+		{
+			name: "保留其他实例在此期间的写入",
+			before: func(e *Enforcer) error {
+				return e.AddPolicy(ctx, "bob", "t1", roleRead)
+			},
+			during: func(e *Enforcer) error {
+				return e.AddPolicy(ctx, "alice", "t1", userRead)
+			},
+			concurrent: func(e *Enforcer) error {
+				if _, err := e.enforcer.AddPolicy("carol", "t1", "user", "read"); err != nil {
+					return err
+				}
+				_, err := e.enforcer.RemovePolicy("bob", "t1", "role", "read")
+				return err
+			},
+			wantPolicies: map[string]bool{"alice|t1|user|read": false, "carol|t1|user|read": true, "bob|t1|role|read": false},
+		},
+		{
+			name: "已存在的规则不记录为新增",
+			before: func(e *Enforcer) error {
+				return e.AddPolicy(ctx, "alice", "t1", userRead)
+			},
+			during: func(e *Enforcer) error {
+				return e.AddPolicies(ctx, "alice", "t1", []Permission{userRead, userWrite})
+			},
+			wantPolicies: map[string]bool{"alice|t1|user|read": true, "alice|t1|user|write": false},
+		},
+		{
+			name: "撤销角色分配和主体改名",
+			before: func(e *Enforcer) error {
+				if err := e.AddPolicy(ctx, "alice", "t1", userRead); err != nil {
+					return err
+				}
+				return e.AddGroupingPolicy(ctx, "alice", "admin", "t1")
+			},
+			during: func(e *Enforcer) error {
+				if err := e.AddGroupingPolicy(ctx, "alice", "viewer", "t1"); err != nil {
+					return err
+				}
+				_, _, err := e.RenameSubject(ctx, "alice", "alice2")
+				return err
+			},
+			wantPolicies:  map[string]bool{"alice|t1|user|read": true, "alice2|t1|user|read": false},
+			wantGroupings: map[string]bool{"alice|admin|t1": true, "alice|viewer|t1": false, "alice2|admin|t1": false, "alice2|viewer|t1": false},
+		},
+		{
+			name: "撤销角色继承",
+			during: func(e *Enforcer) error {
+				return e.AddRoleInheritance(ctx, "admin", "editor", "t1")
+			},
+			wantGroupings: map[string]bool{"editor|admin|t1": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnforcer(t)
+			if tt.before != nil {
+				if err := tt.before(e); err != nil {
+					t.Fatalf("before error = %v", err)
+				}
+			}
+
+			journal, err := e.BeginJournal()
+			if err != nil {
+				t.Fatalf("BeginJournal() error = %v", err)
+			}
+			if err := tt.during(e); err != nil {
+				t.Fatalf("during error = %v", err)
+			}
+			if tt.concurrent != nil {
+				if err := tt.concurrent(e); err != nil {
+					t.Fatalf("concurrent error = %v", err)
+				}
+			}
+			if err := e.RevertJournal(ctx, journal); err != nil {
+				t.Fatalf("RevertJournal() error = %v", err)
+			}
+
+			for rule, want := range tt.wantPolicies {
+				got, err := e.enforcer.HasPolicy(splitRule(rule))
+				if err != nil {
+					t.Fatalf("HasPolicy(%s) error = %v", rule, err)
+				}
+				if got != want {
+					t.Errorf("policy %s exists = %v, want %v", rule, got, want)
+				}
+			}
+			for rule, want := range tt.wantGroupings {
+				got, err := e.enforcer.HasGroupingPolicy(splitRule(rule))
+				if err != nil {
+					t.Fatalf("HasGroupingPolicy(%s) error = %v", rule, err)
+				}
+				if got != want {
+					t.Errorf("grouping %s exists = %v, want %v", rule, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBeginJournalExclusive(t *testing.T) {
+	ctx := context.Background()
+	e := newTestEnforcer(t)
+	journal, err := e.BeginJournal()
+	if err != nil {
+		t.Fatalf("BeginJournal() error = %v", err)
+	}
+	if _, err := e.BeginJournal(); err == nil {
+		t.Fatalf("BeginJournal() while recording error = nil, want error")
+	}
+
+	e.EndJournal(journal)
+	if err := e.AddPolicy(ctx, "alice", "t1", userRead); err != nil {
+		t.Fatalf("AddPolicy() error = %v", err)
+	}
+	if len(journal.entries) != 0 {
+		t.Fatalf("journal recorded %d entries after EndJournal, want 0", len(journal.entries))
+	}
+}
+
+// splitRule 将 "a|b|c" 形式的规则拆分为字段
+func splitRule(rule string) []string {
+	return strings.Split(rule, "|")
+}
@@ -0,0 +1,63 @@
+package core
+
+// 强类型标识：操作者、用户、角色、租户在参数列表中位置相邻时，使用不同类型可以让参数错位在编译期暴露
+// 与字符串之间可以直接转换，如 core.UserKey("alice")、string(tenantKey)
+
+// OperatorKey 操作者标识（执行变更或查询的用户）
+type OperatorKey string
+
+// UserKey 用户标识
+type UserKey string
+
+// RoleKey 角色标识
+type RoleKey string
+
+// TenantKey 租户标识，GlobalTenant 表示全局域
+type TenantKey string
+
+// GlobalTenant 全局域
+const GlobalTenant TenantKey = "*"
+
+func (k OperatorKey) String() string { return string(k) }
+func (k UserKey) String() string     { return string(k) }
+func (k RoleKey) String() string     { return string(k) }
+func (k TenantKey) String() string   { return string(k) }
+
+// AsOperator 将用户标识作为操作者标识使用（如用户操作自己的数据）
+func (k UserKey) AsOperator() OperatorKey { return OperatorKey(k) }
+
+// IsGlobal 是否为全局域
+func (k TenantKey) IsGlobal() bool { return k == GlobalTenant }
+
+// UserKeysOf 将字符串切片转换为用户标识切片
+func UserKeysOf(keys []string) []UserKey { return keysOf[UserKey](keys) }
+
+// RoleKeysOf 将字符串切片转换为角色标识切片
+func RoleKeysOf(keys []string) []RoleKey { return keysOf[RoleKey](keys) }
+
+// TenantKeysOf 将字符串切片转换为租户标识切片
+func TenantKeysOf(keys []string) []TenantKey { return keysOf[TenantKey](keys) }
+
+// KeyStrings 将标识切片转换为字符串切片
+func KeyStrings[K ~string](keys []K) []string {
+	if keys == nil {
+		return nil
+	}
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = string(key)
+	}
+	return values
+}
+
+// keysOf 将字符串切片转换为标识切片
+func keysOf[K ~string](values []string) []K {
+	if values == nil {
+		return nil
+	}
+	keys := make([]K, len(values))
+	for i, value := range values {
+		keys[i] = K(value)
+	}
+	return keys
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	casbinlog "github.com/casbin/casbin/v2/log"
+)
+
+// Logger 结构化日志接口，*slog.Logger 即满足该接口
+// args 为交替出现的键值对（与 slog 相同），如 logger.Error("重新加载策略失败", "error", err)
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// DefaultLogger 默认日志输出（slog 默认 Logger）
+func DefaultLogger() Logger {
+	return slog.Default()
+}
+
+// NewSlogLogger 使用 slog 输出日志，所有日志带有 component=casbinx 字段；logger 为空时使用 slog 默认 Logger
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger.With("component", "casbinx")
+}
+
+// ZapSugaredLogger zap 的 SugaredLogger 中用到的方法，*zap.SugaredLogger 即满足该接口
+// 适配器只依赖该接口，casbinx 不引入 zap 依赖
+type ZapSugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// zapLogger zap 适配器
+type zapLogger struct {
+	sugar ZapSugaredLogger
+}
+
+// NewZapLogger 使用 zap 输出日志，如 core.NewZapLogger(zapLogger.Sugar())
+func NewZapLogger(sugar ZapSugaredLogger) Logger {
+	return zapLogger{sugar: sugar}
+}
+
+func (l zapLogger) Debug(msg string, args ...any) { l.sugar.Debugw(msg, args...) }
+func (l zapLogger) Info(msg string, args ...any)  { l.sugar.Infow(msg, args...) }
+func (l zapLogger) Warn(msg string, args ...any)  { l.sugar.Warnw(msg, args...) }
+func (l zapLogger) Error(msg string, args ...any) { l.sugar.Errorw(msg, args...) }
+
+// Printfer Printf 风格的日志输出，标准库 *log.Logger 即满足该接口
+type Printfer interface {
+	Printf(format string, v ...interface{})
+}
+
+// printfLogger Printf 适配器
+type printfLogger struct {
+	printer Printfer
+}
+
+// NewPrintfLogger 兼容 Printf 风格的日志输出，字段按 key=value 追加在消息之后
+func NewPrintfLogger(printer Printfer) Logger {
+	return printfLogger{printer: printer}
+}
+
+func (l printfLogger) Debug(msg string, args ...any) { l.print("DEBUG", msg, args) }
+func (l printfLogger) Info(msg string, args ...any)  { l.print("INFO", msg, args) }
+func (l printfLogger) Warn(msg string, args ...any)  { l.print("WARN", msg, args) }
+func (l printfLogger) Error(msg string, args ...any) { l.print("ERROR", msg, args) }
+
+// print 输出一行日志，键值对数量为奇数时最后一个值以 !BADKEY 为键（与 slog 相同）
+func (l printfLogger) print(level, msg string, args []any) {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "[CasbinX] %s %s", level, msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&builder, " !BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&builder, " %v=%v", args[i], args[i+1])
+	}
+	l.printer.Printf("%s", builder.String())
+}
+
+// casbinLogger 将 Casbin 内部日志转发到 Logger（错误为 Error 级别，其余为 Debug 级别）
+type casbinLogger struct {
+	logger  Logger
+	enabled atomic.Bool
+}
+
+// NewCasbinLogger 创建 Casbin 日志适配器，通过 casbin.Enforcer.SetLogger 设置
+// 未启用时 Casbin 不输出模型、策略和每次 Enforce 的日志，错误日志始终输出
+func NewCasbinLogger(logger Logger) casbinlog.Logger {
+	return &casbinLogger{logger: logger}
+}
+
+func (l *casbinLogger) EnableLog(enabled bool) { l.enabled.Store(enabled) }
+func (l *casbinLogger) IsEnabled() bool        { return l.enabled.Load() }
+
+func (l *casbinLogger) LogModel(model [][]string) {
+	if l.IsEnabled() {
+		l.logger.Debug("Casbin 模型", "model", model)
+	}
+}
+
+func (l *casbinLogger) LogEnforce(matcher string, request []interface{}, result bool, explains [][]string) {
+	if l.IsEnabled() {
+		l.logger.Debug("Casbin 权限检查", "matcher", matcher, "request", request, "result", result, "explains", explains)
+	}
+}
+
+func (l *casbinLogger) LogRole(roles []string) {
+	if l.IsEnabled() {
+		l.logger.Debug("Casbin 角色", "roles", roles)
+	}
+}
+
+func (l *casbinLogger) LogPolicy(policy map[string][][]string) {
+	if l.IsEnabled() {
+		l.logger.Debug("Casbin 策略", "policy", policy)
+	}
+}
+
+func (l *casbinLogger) LogError(err error, msg ...string) {
+	l.logger.Error("Casbin 错误", "error", err, "message", strings.Join(msg, " "))
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultManifestCacheSize 默认权限清单缓存容量
+const DefaultManifestCacheSize = 10000
+
+// PermissionManifest 用户在租户内的权限清单（供前端一次性获取后本地判断）
+// Stale 为 true 表示实时查询失败，返回的是最近一次成功查询的结果
+type PermissionManifest struct {
+	UserKey     string       `json:"userKey"`               // 用户标识
+	TenantKey   string       `json:"tenantKey"`             // 租户标识
+	Roles       []string     `json:"roles"`                 // 用户在租户内的角色
+	Permissions []Permission `json:"permissions"`           // 用户在租户内的有效权限（含角色继承）
+	Revision    uint64       `json:"revision"`              // 生成清单时本实例的策略修订号
+	GeneratedAt time.Time    `json:"generatedAt"`           // 清单生成时间，Stale 时为最近一次成功查询的时间
+	Stale       bool         `json:"stale"`                 // 是否为降级返回的过期清单
+	StaleReason string       `json:"staleReason,omitempty"` // 实时查询失败的原因
+}
+
+// ManifestCache 最近一次成功查询的权限清单缓存，用于授权存储不可用时降级
+// 与 DecisionCache 不同，策略变更时不清空：缓存只在实时查询失败时使用，并标记为过期
+type ManifestCache interface {
+	Get(key string) (*PermissionManifest, bool)   // 获取缓存的清单
+	Set(key string, manifest *PermissionManifest) // 写入清单
+}
+
+// memoryManifestCache 内存权限清单缓存，达到容量上限时整体清空
+type memoryManifestCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[string]*PermissionManifest
+}
+
+// NewMemoryManifestCache 创建内存权限清单缓存，size 为 0 时使用默认容量
+func NewMemoryManifestCache(size int) ManifestCache {
+	if size <= 0 {
+		size = DefaultManifestCacheSize
+	}
+	return &memoryManifestCache{
+		size:    size,
+		entries: make(map[string]*PermissionManifest),
+	}
+}
+
+// Get 获取缓存的清单
+func (c *memoryManifestCache) Get(key string) (*PermissionManifest, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	manifest, found := c.entries[key]
+	return manifest, found
+}
+
+// Set 写入清单
+func (c *memoryManifestCache) Set(key string, manifest *PermissionManifest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.size {
+		c.entries = make(map[string]*PermissionManifest)
+	}
+	c.entries[key] = manifest
+}
+
+// IsDegradable 判断错误是否可以降级处理：参数错误和业务拒绝（如 ErrPermissionDenied）不可降级，
+// 存储不可用等未知错误、ErrInternal 和 panic 可以降级
+func IsDegradable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return false
+	}
+	var coreErr Error
+	if errors.As(err, &coreErr) {
+		return coreErr.Code == ErrInternal.Code
+	}
+	return true
+}
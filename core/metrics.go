@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 缓存名称（MetricsRecorder.ObserveCache 的 cache 参数）
+const (
+	CacheDecision   = "decision"   // 权限检查结果缓存（WithCache）
+	CachePermission = "permission" // 权限集合缓存（WithPermissionCache）
+)
+
+// DefaultCheckLatencyBuckets 权限检查耗时的默认分桶（100µs 到约 3s）
+var DefaultCheckLatencyBuckets = prometheus.ExponentialBuckets(0.0001, 2, 16)
+
+// MetricsConfig 指标配置
+type MetricsConfig struct {
+	// Registerer 指标注册器（如 prometheus.DefaultRegisterer），为空时不采集指标
+	// 多个引擎实例（如数据驻留的各区域实例）使用同一注册器时共享同一组指标
+	Registerer prometheus.Registerer `json:"-"`
+
+	// Namespace 指标名前缀，默认 casbinx
+	Namespace string `json:"namespace"`
+
+	// CheckLatencyBuckets 权限检查耗时分桶（秒），默认 DefaultCheckLatencyBuckets
+	CheckLatencyBuckets []float64 `json:"checkLatencyBuckets"`
+
+	// DisableTenantLabel 检查计数不按租户区分，租户数量很大时用于控制指标基数
+	DisableTenantLabel bool `json:"disableTenantLabel"`
+}
+
+// Enabled 是否采集指标
+func (c MetricsConfig) Enabled() bool {
+	return c.Registerer != nil
+}
+
+// MetricsRecorder 指标采集回调，方法在调用方协程中同步执行，实现方不应阻塞
+type MetricsRecorder interface {
+	ObserveCheck(tenantKey string, allowed bool, err error, duration time.Duration) // 一次权限检查
+	ObserveCache(cache string, hit bool)                                            // 一次缓存查询
+	ObserveMutation(operation string, err error, duration time.Duration)            // 一次变更操作
+	ObserveReload(coalesced int, lag, duration time.Duration, err error)            // 一次策略重载，lag 为第一条同步通知到重载完成的时间
+	ObserveDBError(operation string)                                                // 一次数据库或存储错误
+	ObserveCheckComparison(result string)                                           // 一次影子比较（CheckComparisonMatch 等）
+	ObserveWatcherRejected(reason string)                                           // 一条未通过验证的同步通知（WatcherRejectUnsigned 等）
+	ObserveWatcherConnection(watcher WatcherType, event string)                     // 一次内置 Watcher 连接事件（WatcherEventReconnected 等）
+}
+
+// nopMetrics 不采集指标
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveCheck(string, bool, error, time.Duration)        {}
+func (nopMetrics) ObserveCache(string, bool)                              {}
+func (nopMetrics) ObserveMutation(string, error, time.Duration)           {}
+func (nopMetrics) ObserveReload(int, time.Duration, time.Duration, error) {}
+func (nopMetrics) ObserveDBError(string)                                  {}
+func (nopMetrics) ObserveCheckComparison(string)                          {}
+func (nopMetrics) ObserveWatcherRejected(string)                          {}
+func (nopMetrics) ObserveWatcherConnection(WatcherType, string)           {}
+
+// NopMetrics 不采集指标的 MetricsRecorder
+func NopMetrics() MetricsRecorder {
+	return nopMetrics{}
+}
+
+// prometheusMetrics Prometheus 指标实现
+type prometheusMetrics struct {
+	tenantLabel bool
+
+	checks           *prometheus.CounterVec
+	checkDuration    *prometheus.HistogramVec
+	cacheRequests    *prometheus.CounterVec
+	mutations        *prometheus.CounterVec
+	mutationDuration *prometheus.HistogramVec
+	reloads          *prometheus.CounterVec
+	reloadDuration   *prometheus.HistogramVec
+	watcherLag       *prometheus.HistogramVec
+	dbErrors         *prometheus.CounterVec
+	comparisons      *prometheus.CounterVec
+	watcherRejects   *prometheus.CounterVec
+	watcherEvents    *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics 按配置创建 Prometheus 指标并注册
+// 注册器中已存在同名指标时复用已有指标，同一进程内的多个引擎实例共享计数
+func NewPrometheusMetrics(config MetricsConfig) (MetricsRecorder, error) {
+	if !config.Enabled() {
+		return NopMetrics(), nil
+	}
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = "casbinx"
+	}
+	buckets := config.CheckLatencyBuckets
+	if len(buckets) == 0 {
+		buckets = DefaultCheckLatencyBuckets
+	}
+
+	m := &prometheusMetrics{tenantLabel: !config.DisableTenantLabel}
+	var err error
+	register := func(collector prometheus.Collector) prometheus.Collector {
+		if err != nil {
+			return collector
+		}
+		if registerErr := config.Registerer.Register(collector); registerErr != nil {
+			var already prometheus.AlreadyRegisteredError
+			if errors.As(registerErr, &already) {
+				return already.ExistingCollector
+			}
+			err = registerErr
+		}
+		return collector
+	}
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		collector := register(prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help}, labels))
+		vec, _ := collector.(*prometheus.CounterVec)
+		return vec
+	}
+	histogram := func(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+		collector := register(prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: namespace, Name: name, Help: help, Buckets: buckets}, labels))
+		vec, _ := collector.(*prometheus.HistogramVec)
+		return vec
+	}
+
+	m.checks = counter("checks_total", "权限检查次数（按租户和结果）", "tenant", "result")
+	m.checkDuration = histogram("check_duration_seconds", "权限检查耗时", buckets, "result")
+	m.cacheRequests = counter("cache_requests_total", "缓存查询次数（按缓存和命中情况）", "cache", "result")
+	m.mutations = counter("mutations_total", "变更操作次数（按操作和结果）", "operation", "result")
+	m.mutationDuration = histogram("mutation_duration_seconds", "变更操作耗时", prometheus.DefBuckets, "operation")
+	m.reloads = counter("policy_reloads_total", "策略重新加载次数（按结果）", "result")
+	m.reloadDuration = histogram("policy_reload_duration_seconds", "策略重新加载耗时", prometheus.DefBuckets)
+	m.watcherLag = histogram("watcher_lag_seconds", "从收到第一条同步通知到策略重新加载完成的时间", prometheus.DefBuckets)
+	m.dbErrors = counter("db_errors_total", "数据库或存储错误次数（按操作）", "operation")
+	m.comparisons = counter("check_comparisons_total", "权限检查影子比较次数（按结果）", "result")
+	m.watcherRejects = counter("watcher_messages_rejected_total", "未通过验证被丢弃的同步通知数（按原因）", "reason")
+	m.watcherEvents = counter("watcher_connection_events_total", "内置 Watcher 的断线、重连成功和重连失败次数（按类型和事件）", "watcher", "event")
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ObserveCheck 记录一次权限检查
+func (m *prometheusMetrics) ObserveCheck(tenantKey string, allowed bool, err error, duration time.Duration) {
+	result := "deny"
+	switch {
+	case err != nil:
+		result = "error"
+	case allowed:
+		result = "allow"
+	}
+	if !m.tenantLabel {
+		tenantKey = ""
+	}
+	m.checks.WithLabelValues(tenantKey, result).Inc()
+	m.checkDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// ObserveCache 记录一次缓存查询
+func (m *prometheusMetrics) ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveMutation 记录一次变更操作，结果按 ErrorClass 分类
+func (m *prometheusMetrics) ObserveMutation(operation string, err error, duration time.Duration) {
+	m.mutations.WithLabelValues(operation, ErrorClass(err)).Inc()
+	m.mutationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// ObserveReload 记录一次策略重载
+func (m *prometheusMetrics) ObserveReload(coalesced int, lag, duration time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.reloads.WithLabelValues(result).Inc()
+	m.reloadDuration.WithLabelValues().Observe(duration.Seconds())
+	m.watcherLag.WithLabelValues().Observe(lag.Seconds())
+}
+
+// ObserveDBError 记录一次数据库或存储错误
+func (m *prometheusMetrics) ObserveDBError(operation string) {
+	m.dbErrors.WithLabelValues(operation).Inc()
+}
+
+// ObserveCheckComparison 记录一次影子比较
+func (m *prometheusMetrics) ObserveCheckComparison(result string) {
+	m.comparisons.WithLabelValues(result).Inc()
+}
+
+// ObserveWatcherRejected 记录一条未通过验证的同步通知
+func (m *prometheusMetrics) ObserveWatcherRejected(reason string) {
+	m.watcherRejects.WithLabelValues(reason).Inc()
+}
+
+// ObserveWatcherConnection 记录一次内置 Watcher 连接事件
+func (m *prometheusMetrics) ObserveWatcherConnection(watcher WatcherType, event string) {
+	m.watcherEvents.WithLabelValues(string(watcher), event).Inc()
+}
+
+// ErrorClass 错误分类：nil 为 ok，core.Error（业务拒绝）为 rejected，
+// 调用方取消或超时为 canceled，其余（数据库、存储、panic 等）为 error
+func ErrorClass(err error) string {
+	var coreErr Error
+	var panicErr *PanicError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &panicErr):
+		return "error"
+	case errors.As(err, &coreErr):
+		return "rejected"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
+
+// SetMetrics 设置指标采集，传入 nil 表示不采集
+func (e *Enforcer) SetMetrics(metrics MetricsRecorder) {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = metrics
+}
+
+// Metrics 获取指标采集（未设置时为不采集的实现）
+func (e *Enforcer) Metrics() MetricsRecorder {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.metrics
+}
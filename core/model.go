@@ -0,0 +1,11 @@
+package core
+
+import (
+	_ "embed"
+)
+
+// DefaultModelText 内置的默认 Casbin 模型（带域的 RBAC）
+// 未设置 Config.ModelText 且默认路径下没有模型文件时使用
+//
+//go:embed rbac_model.conf
+var DefaultModelText string
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// 变更串行化队列默认值
+const (
+	DefaultMutationQueueWorkers = 8   // 默认工作协程数量
+	DefaultMutationQueueSize    = 256 // 默认每个工作协程的等待队列长度
+)
+
+// MutationQueueConfig 变更串行化队列配置
+// 启用后，同一主体的变更操作在本实例内按提交顺序逐个执行，不同主体的变更由有限的工作协程并行执行，
+// 避免并发的管理操作交错写入同一主体的策略，也减少适配器上的并发争用
+type MutationQueueConfig struct {
+	// Enabled 是否启用，默认关闭（变更在调用方协程中直接执行）
+	Enabled bool `json:"enabled"`
+
+	// Workers 工作协程数量，默认 8
+	Workers int `json:"workers"`
+
+	// QueueSize 每个工作协程的等待队列长度，默认 256；队列满时提交方阻塞等待
+	QueueSize int `json:"queueSize"`
+}
+
+// mutationTask 排队中的变更操作
+type mutationTask struct {
+	ctx  context.Context
+	fn   func() error
+	done chan error
+}
+
+// MutationQueue 按主体串行化变更操作的有界工作池
+// 工作协程按主体选择，同一主体在同一租户内的变更按提交顺序执行；
+// 同一主体在不同租户的变更以及跨租户的变更（如清除用户所有角色）之间同样保持顺序
+type MutationQueue struct {
+	queues []chan mutationTask
+}
+
+// NewMutationQueue 创建变更串行化队列并启动工作协程
+func NewMutationQueue(config MutationQueueConfig) *MutationQueue {
+	workers := config.Workers
+	if workers <= 0 {
+		workers = DefaultMutationQueueWorkers
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultMutationQueueSize
+	}
+
+	q := &MutationQueue{queues: make([]chan mutationTask, workers)}
+	for i := range q.queues {
+		q.queues[i] = make(chan mutationTask, queueSize)
+		go q.run(q.queues[i])
+	}
+	return q
+}
+
+// Submit 提交变更操作并等待执行结果
+// 排队期间 ctx 被取消时操作不会执行并返回 ctx 的错误；操作开始执行后会等待其完成
+func (q *MutationQueue) Submit(ctx context.Context, subject string, fn func() error) error {
+	task := mutationTask{ctx: ctx, fn: fn, done: make(chan error, 1)}
+
+	select {
+	case q.queues[q.worker(subject)] <- task:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return <-task.done
+}
+
+// worker 按主体选择工作协程
+func (q *MutationQueue) worker(subject string) int {
+	h := fnv.New32a()
+	h.Write([]byte(subject))
+	return int(h.Sum32() % uint32(len(q.queues)))
+}
+
+// run 工作协程：按提交顺序逐个执行变更操作
+func (q *MutationQueue) run(tasks chan mutationTask) {
+	for task := range tasks {
+		if err := task.ctx.Err(); err != nil {
+			task.done <- err
+			continue
+		}
+		task.done <- runMutationTask(task.fn)
+	}
+}
+
+// runMutationTask 执行变更操作，panic 转换为 PanicError 返回，避免工作协程退出
+func runMutationTask(fn func() error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = NewPanicError("MutationQueue", value, true)
+		}
+	}()
+	return fn()
+}
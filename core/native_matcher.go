@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+)
+
+// 内置模型匹配器使用的自定义函数名，Casbin 原生 Enforce 按这两个函数比较资源和操作，与自定义实现的覆盖规则一致
+const (
+	MatcherFuncObjectMatch = "objectMatch" // objectMatch(r.obj, p.obj)：资源完全一致、通配资源 *、类型级授权覆盖对象、对象通配符匹配
+	MatcherFuncActionMatch = "actionMatch" // actionMatch(r.act, p.act)：操作一致、通配操作 *，或未启用严格写操作时 write 覆盖 create/update
+)
+
+// registerMatcherFunctions 向 Casbin 执行器注册内置模型匹配器使用的函数（自定义模型也可以引用）
+func (e *Enforcer) registerMatcherFunctions() {
+	e.enforcer.AddFunction(MatcherFuncObjectMatch, func(args ...any) (any, error) {
+		requested, granted, err := matcherArgs(MatcherFuncObjectMatch, args)
+		if err != nil {
+			return false, err
+		}
+		return ObjectMatch(requested, granted), nil
+	})
+	e.enforcer.AddFunction(MatcherFuncActionMatch, func(args ...any) (any, error) {
+		requested, granted, err := matcherArgs(MatcherFuncActionMatch, args)
+		if err != nil {
+			return false, err
+		}
+		return e.actionCovers(Action(granted), Action(requested)), nil
+	})
+}
+
+// matcherArgs 解析匹配器函数的两个字符串参数（请求值在前，策略值在后）
+func matcherArgs(name string, args []any) (string, string, error) {
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("%s: 需要 2 个参数，实际 %d 个", name, len(args))
+	}
+	requested, ok1 := args[0].(string)
+	granted, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return "", "", fmt.Errorf("%s: 参数必须是字符串", name)
+	}
+	return requested, granted, nil
+}
+
+// ObjectMatch 检查存储中的授权资源是否覆盖请求的资源（均为 "资源" 或 "资源:对象ID" 形式），与 Permission.Covers 一致
+func ObjectMatch(requested, granted string) bool {
+	if requested == granted || granted == string(ResourceAll) {
+		return true
+	}
+	if ResourceType(Resource(requested)) == Resource(granted) {
+		return true
+	}
+	return matchObjectWildcard(granted, requested)
+}
+
+// ValidateNativeModel 检查当前模型的匹配器能否由 Casbin 原生 Enforce 求值
+// 使用占位请求执行一次 Enforce，匹配器引用了未定义的变量或函数时返回错误
+func (e *Enforcer) ValidateNativeModel() error {
+	if _, err := e.enforcer.Enforce("", "", "", ""); err != nil {
+		return fmt.Errorf("模型匹配器无法用于原生 Enforce: %v", err)
+	}
+	return nil
+}
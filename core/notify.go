@@ -0,0 +1,52 @@
+package core
+
+// === 同步通知批处理 ===
+
+// SuspendNotifications 暂停同步通知（策略写入的自动通知和 NotifyWatcher）
+// 可嵌套调用，每次调用需对应一次 ResumeNotifications
+func (e *Enforcer) SuspendNotifications() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.notifySuspended == 0 {
+		e.enforcer.EnableAutoNotifyWatcher(false)
+	}
+	e.notifySuspended++
+}
+
+// ResumeNotifications 恢复同步通知
+// 最外层恢复时重新开启自动通知，并发布一次通知让其他实例重新加载暂停期间的所有变更
+func (e *Enforcer) ResumeNotifications() error {
+	e.mu.Lock()
+	if e.notifySuspended == 0 {
+		e.mu.Unlock()
+		return nil
+	}
+	e.notifySuspended--
+	if e.notifySuspended > 0 {
+		e.mu.Unlock()
+		return nil
+	}
+	watcher := e.watcher
+	if watcher != nil {
+		e.enforcer.EnableAutoNotifyWatcher(true)
+	}
+	e.mu.Unlock()
+
+	if watcher == nil {
+		return nil
+	}
+	return watcher.Update()
+}
+
+// BatchNotifications 在 fn 执行期间暂停同步通知，结束后只发布一次通知
+// 用于批量变更，避免每条变更都触发其他实例全量重新加载；fn 失败时同样发布通知（部分变更可能已写入）
+func (e *Enforcer) BatchNotifications(fn func() error) (err error) {
+	e.SuspendNotifications()
+	defer func() {
+		if resumeErr := e.ResumeNotifications(); resumeErr != nil && err == nil {
+			err = resumeErr
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObjectIDPlaceholder 权限模板中的对象ID占位符
+const ObjectIDPlaceholder = "{id}"
+
+// ObjectPermissionTemplate 对象级权限模板集合
+// 每个模板形如 "project:{id}:read"：最后一段为操作，其余部分为资源模板，{id} 在实例化时替换为对象ID
+type ObjectPermissionTemplate struct {
+	Name      string   `json:"name"`      // 模板集合名称（如 project_owner）
+	Templates []string `json:"templates"` // 权限模板列表
+}
+
+// Instantiate 为对象实例化模板集合中的所有权限
+func (t ObjectPermissionTemplate) Instantiate(objectID string) ([]Permission, error) {
+	if err := FirstError(
+		RequireArg("objectID", objectID),
+		CheckArg(!strings.Contains(objectID, ":"), "objectID", ConstraintFormat),
+		CheckArg(!strings.Contains(objectID, ObjectIDPlaceholder), "objectID", ConstraintFormat),
+	); err != nil {
+		return nil, err
+	}
+
+	permissions := make([]Permission, 0, len(t.Templates))
+	for _, template := range t.Templates {
+		permission, err := InstantiatePermissionTemplate(template, objectID)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return NormalizePermissions(permissions), nil
+}
+
+// Resources 实例化后涉及的对象资源（去重，保持模板顺序）
+func (t ObjectPermissionTemplate) Resources(objectID string) ([]Resource, error) {
+	permissions, err := t.Instantiate(objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Resource]bool)
+	var resources []Resource
+	for _, permission := range permissions {
+		if !seen[permission.Resource] {
+			seen[permission.Resource] = true
+			resources = append(resources, permission.Resource)
+		}
+	}
+	return resources, nil
+}
+
+// InstantiatePermissionTemplate 将单个权限模板实例化为对象权限
+func InstantiatePermissionTemplate(template, objectID string) (Permission, error) {
+	separator := strings.LastIndex(template, ":")
+	if separator <= 0 || separator == len(template)-1 {
+		return Permission{}, fmt.Errorf("无效的权限模板 '%s'：格式应为 资源:{id}:操作", template)
+	}
+
+	resourceTemplate, actionText := template[:separator], template[separator+1:]
+	if !strings.Contains(resourceTemplate, ObjectIDPlaceholder) {
+		return Permission{}, fmt.Errorf("无效的权限模板 '%s'：资源部分缺少 %s 占位符", template, ObjectIDPlaceholder)
+	}
+
+	action, err := ParseAction(actionText)
+	if err != nil || action == ActionNone {
+		return Permission{}, fmt.Errorf("无效的权限模板 '%s'：未知操作 '%s'", template, actionText)
+	}
+
+	return Permission{
+		Resource: Resource(strings.ReplaceAll(resourceTemplate, ObjectIDPlaceholder, objectID)),
+		Action:   action,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ObjectCleanupHook 对象清理回调
+// 对象的权限策略删除后以 CleanupObject 调用方的 ctx 调用，用于清理与对象相关的附属数据（环境标记、分享、归属记录等）
+type ObjectCleanupHook func(ctx context.Context, resourceType, objectID string, removed []Policy) error
+
+// ObjectCleanupResult 对象权限清理结果
+type ObjectCleanupResult struct {
+	ResourceType    string   `json:"resourceType"`    // 对象类型
+	ObjectID        string   `json:"objectId"`        // 对象ID
+	RemovedPolicies []Policy `json:"removedPolicies"` // 被删除的权限策略（所有租户、所有主体）
+}
+
+// ObjectResource 生成对象资源标识，格式为 类型:ID
+func ObjectResource(resourceType, objectID string) Resource {
+	return Resource(resourceType + ":" + objectID)
+}
+
+// IsObjectResource 判断资源是否属于对象：资源等于 类型:ID，或以 类型:ID: 开头（对象的子资源）
+func IsObjectResource(resource Resource, resourceType, objectID string) bool {
+	objectResource := string(ObjectResource(resourceType, objectID))
+	return string(resource) == objectResource || strings.HasPrefix(string(resource), objectResource+":")
+}
+
+// AddObjectCleanupHook 注册对象清理回调
+func (e *Enforcer) AddObjectCleanupHook(hook ObjectCleanupHook) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.objectCleanupHooks = append(e.objectCleanupHooks, hook)
+}
+
+// CleanupObject 删除所有租户中所有主体对对象（含子资源）的权限策略，并依次执行对象清理回调
+// 策略在一个批次中删除；回调失败时返回第一个错误，但不会中断后续回调
+func (e *Enforcer) CleanupObject(ctx context.Context, resourceType, objectID string) (*ObjectCleanupResult, error) {
+	if err := FirstError(
+		RequireArg("resourceType", resourceType),
+		RequireArg("objectID", objectID),
+		CheckArg(!strings.Contains(resourceType, ":"), "resourceType", ConstraintFormat),
+		CheckArg(!strings.Contains(objectID, ":"), "objectID", ConstraintFormat),
+	); err != nil {
+		return nil, err
+	}
+
+	// 子资源需要按前缀匹配，过滤查询只支持字段精确匹配，因此不限制字段，逐条判断资源
+	removed, err := e.removePoliciesWhere(ctx, 2, nil, func(policy []string) bool {
+		return IsObjectResource(Resource(policy[2]), resourceType, objectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &ObjectCleanupResult{ResourceType: resourceType, ObjectID: objectID, RemovedPolicies: removed}
+
+	e.mu.RLock()
+	hooks := e.objectCleanupHooks
+	e.mu.RUnlock()
+
+	var firstErr error
+	for _, hook := range hooks {
+		if err := hook(ctx, resourceType, objectID, removed); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("对象清理回调失败: %w", err)
+		}
+	}
+
+	return result, firstErr
+}
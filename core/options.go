@@ -0,0 +1,51 @@
+package core
+
+// MutationOption 变更操作的可选参数
+type MutationOption func(*MutationOptions)
+
+// MutationOptions 变更操作选项集合
+type MutationOptions struct {
+	IdempotencyKey string   // 幂等键，客户端重试时复用同一个键可避免操作被重复执行
+	Environments   []string // 授权生效的环境列表，为空表示所有环境均生效
+	Reason         string   // 变更原因，写入审计日志
+	OperatorKey    string   // 操作者，只用于没有操作者参数的操作（如 DeleteRole），写入审计日志
+}
+
+// WithIdempotencyKey 为变更操作指定幂等键
+func WithIdempotencyKey(key string) MutationOption {
+	return func(o *MutationOptions) {
+		o.IdempotencyKey = key
+	}
+}
+
+// WithEnvironments 限定授权只在指定环境中生效（仅对授权类操作有效）
+func WithEnvironments(environments ...string) MutationOption {
+	return func(o *MutationOptions) {
+		o.Environments = append(o.Environments, environments...)
+	}
+}
+
+// WithReason 为变更操作附加原因说明，记录在审计日志中
+func WithReason(reason string) MutationOption {
+	return func(o *MutationOptions) {
+		o.Reason = reason
+	}
+}
+
+// WithOperator 为没有操作者参数的变更操作（如 DeleteRole）指定操作者，记录在审计日志中
+func WithOperator(operatorKey string) MutationOption {
+	return func(o *MutationOptions) {
+		o.OperatorKey = operatorKey
+	}
+}
+
+// ApplyMutationOptions 合并变更操作选项
+func ApplyMutationOptions(opts []MutationOption) MutationOptions {
+	var options MutationOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// 分页默认值
+const (
+	DefaultPageSize = 50  // 未指定 Limit 时的默认页大小
+	MaxPageSize     = 500 // 单页最大条数
+)
+
+// cursorVersion 游标编码版本，编码格式变更时递增以拒绝旧游标
+const cursorVersion = "v1"
+
+// Cursor 分页游标
+// 对调用方不透明，只能原样回传服务端返回的 NextCursor；空游标表示从第一页开始
+type Cursor string
+
+// EncodeCursor 将偏移量编码为游标
+func EncodeCursor(offset int) Cursor {
+	if offset <= 0 {
+		return ""
+	}
+	raw := cursorVersion + ":" + strconv.Itoa(offset)
+	return Cursor(base64.RawURLEncoding.EncodeToString([]byte(raw)))
+}
+
+// Offset 解码游标中的偏移量
+func (c Cursor) Offset() (int, error) {
+	if c == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	version, value, ok := strings.Cut(string(raw), ":")
+	if !ok || version != cursorVersion {
+		return 0, ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return offset, nil
+}
+
+// PageRequest 分页请求
+type PageRequest struct {
+	Cursor Cursor `json:"cursor"` // 上一页返回的 NextCursor，为空表示第一页
+	Limit  int    `json:"limit"`  // 页大小，<=0 使用 DefaultPageSize，超过 MaxPageSize 时截断
+}
+
+// PageSize 获取规范化后的页大小
+func (r PageRequest) PageSize() int {
+	if r.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if r.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return r.Limit
+}
+
+// PageResponse 分页响应
+type PageResponse[T any] struct {
+	Items      []T    `json:"items"`      // 当前页数据
+	NextCursor Cursor `json:"nextCursor"` // 下一页游标，没有更多数据时为空
+	HasMore    bool   `json:"hasMore"`    // 是否还有下一页
+	Total      int    `json:"total"`      // 符合条件的总条数
+	ResultMeta        // 结果元数据（策略相关的列表才设置）
+}
+
+// Paginate 对已排序的完整结果集进行分页
+// 调用方需保证 items 顺序稳定，否则翻页时可能出现重复或遗漏
+func Paginate[T any](items []T, page PageRequest) (*PageResponse[T], error) {
+	offset, err := page.Cursor.Offset()
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(items)
+	if offset > total {
+		offset = total
+	}
+
+	end := offset + page.PageSize()
+	if end > total {
+		end = total
+	}
+
+	response := &PageResponse[T]{
+		Items: append([]T{}, items[offset:end]...),
+		Total: total,
+	}
+	if end < total {
+		response.HasMore = true
+		response.NextCursor = EncodeCursor(end)
+	}
+
+	return response, nil
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"path"
+	"sort"
 	"strings"
 )
 
@@ -40,14 +42,71 @@ const (
 )
 
 // Permission 权限结构体
+// ObjectID 为空时是类型级权限（如 document:read）；指定 ObjectID 时是对象级权限（如 document 123 的 read），
+// 以 "资源:对象ID" 作为资源存储，与对象权限模板实例化出的资源形式一致。
+// 授权时 ObjectID 可以包含通配符（path.Match 语义，如 * 或 proj-*），匹配该类资源下的多个对象；
+// Resource 为 ResourceAll 时覆盖所有资源，Action 为 ActionAll 时覆盖所有操作（见 IsWildcard）
 type Permission struct {
-	Resource Resource `json:"resource"` // 资源类型，如user、graph、infoatom等
-	Action   Action   `json:"action"`   // 操作类型，如read、write、delete等
+	Resource Resource `json:"resource"`           // 资源类型，如user、graph、infoatom等
+	Action   Action   `json:"action"`             // 操作类型，如read、write、delete等
+	ObjectID string   `json:"objectId,omitempty"` // 对象ID（可选），为空表示类型级权限
 }
 
 // String 返回权限的字符串表示
 func (p Permission) String() string {
-	return fmt.Sprintf("%s:%s", p.Resource, p.Action)
+	return fmt.Sprintf("%s:%s", p.QualifiedResource(), p.Action)
+}
+
+// QualifiedResource 返回存储使用的资源：对象级权限为 "资源:对象ID"，类型级权限为资源本身
+func (p Permission) QualifiedResource() Resource {
+	if p.ObjectID == "" {
+		return p.Resource
+	}
+	return p.Resource + Resource(":"+p.ObjectID)
+}
+
+// Covers 检查授权 p 是否覆盖目标权限（操作必须相同，或授权的操作为 ActionAll）
+// 覆盖的情况：存储资源完全一致；授权的资源为 ResourceAll；授权的对象ID包含通配符且与目标同类资源的对象ID匹配；
+// 目标指定了 ObjectID 时，同类资源的类型级授权覆盖该类资源的所有对象
+func (p Permission) Covers(target Permission) bool {
+	if p.Action != target.Action && p.Action != ActionAll {
+		return false
+	}
+
+	granted, requested := string(p.QualifiedResource()), string(target.QualifiedResource())
+	if granted == requested || granted == string(ResourceAll) {
+		return true
+	}
+	if target.ObjectID != "" && Resource(granted) == target.Resource {
+		return true
+	}
+	return matchObjectWildcard(granted, requested)
+}
+
+// matchObjectWildcard 匹配对象通配符授权（如 document:* 匹配 document:123）
+// 通配符只在最后一个 ":" 之后的对象ID部分生效，资源类型必须完全一致，避免 "*" 之类的授权覆盖系统资源
+func matchObjectWildcard(granted, requested string) bool {
+	wildcard := strings.IndexAny(granted, "*?[")
+	if wildcard < 0 {
+		return false
+	}
+	separator := strings.LastIndex(granted[:wildcard], ":")
+	if separator <= 0 || strings.Contains(granted[separator+1:], ":") {
+		return false
+	}
+
+	prefix := granted[:separator+1]
+	if !strings.HasPrefix(requested, prefix) || strings.Contains(requested[len(prefix):], ":") {
+		return false
+	}
+	matched, err := path.Match(granted[len(prefix):], requested[len(prefix):])
+	return err == nil && matched
+}
+
+// IsWildcard 是否为通配授权：资源为 ResourceAll 或操作为 ActionAll
+// 对象ID中的通配符（如 document:*）只匹配同类资源的对象，不属于通配授权
+func (p Permission) IsWildcard() bool {
+	return p.Resource == ResourceAll || p.Action == ActionAll
 }
 
 // IsValid 检查权限是否有效
@@ -55,9 +114,9 @@ func (p Permission) IsValid() bool {
 	return p.Resource != "" && p.Action != ""
 }
 
-// Equal 检查两个权限是否相等
+// Equal 检查两个权限是否相等（按存储资源比较，Resource+ObjectID 与合并后的资源视为相同）
 func (p Permission) Equal(other Permission) bool {
-	return p.Resource == other.Resource && p.Action == other.Action
+	return p.QualifiedResource() == other.QualifiedResource() && p.Action == other.Action
 }
 
 // IsEmpty 检查权限是否为空
@@ -69,7 +128,7 @@ func (p Permission) IsEmpty() bool {
 func ParsePermission(permStr string) (Permission, error) {
 	parts := strings.Split(permStr, ":")
 	if len(parts) != 2 {
-		return Permission{}, ErrInvalidParameter
+		return Permission{}, &FieldError{Field: "permission", Constraint: ConstraintFormat, Detail: "应为 resource:action"}
 	}
 
 	action, err := ParseAction(strings.TrimSpace(parts[1]))
@@ -85,45 +144,31 @@ func ParsePermission(permStr string) (Permission, error) {
 
 // MergePermissions 合并权限列表，去重
 func MergePermissions(permLists ...[]Permission) []Permission {
-	permMap := make(map[string]Permission)
-
-	for _, permissions := range permLists {
-		for _, perm := range permissions {
-			key := perm.String()
-			permMap[key] = perm
-		}
-	}
-
-	result := make([]Permission, 0, len(permMap))
-	for _, perm := range permMap {
-		result = append(result, perm)
-	}
-
-	return result
+	return UnionPermissions(permLists...)
 }
 
 // ContainsPermission 检查权限列表是否包含指定权限
 func ContainsPermission(permissions []Permission, target Permission) bool {
 	for _, perm := range permissions {
-		if perm.Resource == target.Resource && perm.Action == target.Action {
+		if perm.Equal(target) {
 			return true
 		}
 	}
 	return false
 }
 
-// FilterPermissions 根据条件过滤权限
+// FilterPermissions 根据条件过滤权限，资源或操作为通配符 * 的权限匹配任意条件
 func FilterPermissions(permissions []Permission, resource Resource, action Action) []Permission {
 	var result []Permission
 
 	for _, perm := range permissions {
 		match := true
 
-		if resource != "" && perm.Resource != resource {
+		if resource != "" && perm.Resource != resource && perm.Resource != ResourceAll {
 			match = false
 		}
 
-		if action != "" && perm.Action != action {
+		if action != "" && perm.Action != action && perm.Action != ActionAll {
 			match = false
 		}
 
@@ -134,3 +179,90 @@ func FilterPermissions(permissions []Permission, resource Resource, action Actio
 
 	return result
 }
+
+// PermissionSortField 权限排序字段
+type PermissionSortField string
+
+const (
+	PermissionSortNone     PermissionSortField = ""         // 不排序
+	PermissionSortResource PermissionSortField = "resource" // 按资源排序，资源相同时按操作排序
+	PermissionSortAction   PermissionSortField = "action"   // 按操作排序，操作相同时按资源排序
+)
+
+// PermissionQueryOptions 权限查询结果的整理选项
+// 查询结果总是去重，其余整理按 过滤 -> 排序 的顺序进行
+type PermissionQueryOptions struct {
+	ResourcePrefix string              `json:"resourcePrefix"` // 只保留资源以该前缀开头的权限，为空不过滤
+	SortBy         PermissionSortField `json:"sortBy"`         // 排序字段
+	Descending     bool                `json:"descending"`     // 是否降序
+}
+
+// Apply 按选项整理权限列表（去重、过滤、排序），不修改原列表
+func (o PermissionQueryOptions) Apply(permissions []Permission) []Permission {
+	seen := make(map[Permission]bool, len(permissions))
+	result := make([]Permission, 0, len(permissions))
+	for _, perm := range permissions {
+		if seen[perm] {
+			continue
+		}
+		seen[perm] = true
+
+		if o.ResourcePrefix != "" && !strings.HasPrefix(string(perm.Resource), o.ResourcePrefix) {
+			continue
+		}
+		result = append(result, perm)
+	}
+
+	var less func(a, b Permission) bool
+	switch o.SortBy {
+	case PermissionSortResource:
+		less = func(a, b Permission) bool {
+			if a.Resource != b.Resource {
+				return a.Resource < b.Resource
+			}
+			return a.Action < b.Action
+		}
+	case PermissionSortAction:
+		less = func(a, b Permission) bool {
+			if a.Action != b.Action {
+				return a.Action < b.Action
+			}
+			return a.Resource < b.Resource
+		}
+	default:
+		return result
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if o.Descending {
+			return less(result[j], result[i])
+		}
+		return less(result[i], result[j])
+	})
+
+	return result
+}
+
+// PermissionGroup 按资源分组的权限
+type PermissionGroup struct {
+	Resource Resource `json:"resource"` // 资源类型
+	Actions  []Action `json:"actions"`  // 该资源上的操作列表
+}
+
+// GroupPermissionsByResource 按资源分组权限，分组顺序与资源在列表中首次出现的顺序一致
+func GroupPermissionsByResource(permissions []Permission) []PermissionGroup {
+	index := make(map[Resource]int)
+	var groups []PermissionGroup
+
+	for _, perm := range permissions {
+		i, ok := index[perm.Resource]
+		if !ok {
+			i = len(groups)
+			index[perm.Resource] = i
+			groups = append(groups, PermissionGroup{Resource: perm.Resource})
+		}
+		groups[i].Actions = append(groups[i].Actions, perm.Action)
+	}
+
+	return groups
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultPermissionCacheSize 默认权限集合缓存容量（用户-域组合数）
+const DefaultPermissionCacheSize = 5000
+
+// PermissionCache 用户在域内生效规则集合的缓存
+// 与 DecisionCache 按单个权限缓存检查结果不同，命中后同一用户在同一域内的任意权限检查都无需再枚举策略；
+// 任何策略写入、策略重新加载或附属状态同步都会整体清空缓存。缓存的规则切片由多个调用方共享，不能修改
+type PermissionCache interface {
+	Get(key string) (rules []Policy, found bool) // 获取缓存的规则集合
+	Set(key string, rules []Policy)              // 写入规则集合
+	Clear()                                      // 清空缓存
+}
+
+// lruPermissionEntry LRU 缓存条目
+type lruPermissionEntry struct {
+	key       string
+	rules     []Policy
+	expiresAt time.Time
+}
+
+// lruPermissionCache 带过期时间的 LRU 权限集合缓存
+type lruPermissionCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List               // 最近使用的条目在前
+	entries map[string]*list.Element // 键 -> 条目
+}
+
+// NewLRUPermissionCache 创建 LRU 权限集合缓存
+// size 为 0 时使用默认容量，超出容量时淘汰最久未使用的条目；ttl 为 0 时条目只在淘汰或清空时失效
+func NewLRUPermissionCache(size int, ttl time.Duration) PermissionCache {
+	if size <= 0 {
+		size = DefaultPermissionCacheSize
+	}
+	return &lruPermissionCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get 获取缓存的规则集合，过期条目视为未命中
+func (c *lruPermissionCache) Get(key string) ([]Policy, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruPermissionEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.rules, true
+}
+
+// Set 写入规则集合
+func (c *lruPermissionCache) Set(key string, rules []Policy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruPermissionEntry{key: key, rules: rules, expiresAt: time.Now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruPermissionEntry).key)
+	}
+}
+
+// Clear 清空缓存
+func (c *lruPermissionCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// SetPermissionCache 设置权限集合缓存，传入 nil 表示关闭缓存
+func (e *Enforcer) SetPermissionCache(cache PermissionCache) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.permissionCache = cache
+}
+
+// cachedImplicitRules 从缓存获取用户在域内生效的规则集合，未命中时计算并写入缓存
+// 计算期间发生失效时不写入结果，避免把失效前的规则集合放回缓存
+func (e *Enforcer) cachedImplicitRules(userKey, domain string) ([]Policy, error) {
+	e.mu.RLock()
+	cache := e.permissionCache
+	e.mu.RUnlock()
+
+	if cache == nil {
+		return e.implicitRulesWithAttributes(userKey, domain, nil)
+	}
+
+	key := userKey + "\x00" + domain
+	rules, found := cache.Get(key)
+	e.Metrics().ObserveCache(CachePermission, found)
+	if found {
+		return rules, nil
+	}
+
+	revision := e.revision.Load()
+	rules, err := e.implicitRulesWithAttributes(userKey, domain, nil)
+	if err != nil {
+		return nil, err
+	}
+	if e.revision.Load() == revision {
+		cache.Set(key, rules)
+	}
+	return rules, nil
+}
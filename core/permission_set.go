@@ -0,0 +1,82 @@
+package core
+
+import "strings"
+
+// 权限集合运算
+// 所有函数都会先对输入做规范化（去除首尾空白、丢弃空权限、对象ID合并到资源、去重），结果保持元素在输入中首次出现的顺序
+
+// NormalizePermissions 规范化权限列表：去除首尾空白、丢弃空权限并去重
+// 对象级权限的 ObjectID 合并为 "资源:对象ID" 形式的资源，与存储中的形式一致
+func NormalizePermissions(permissions []Permission) []Permission {
+	seen := make(map[Permission]bool, len(permissions))
+	result := make([]Permission, 0, len(permissions))
+
+	for _, perm := range permissions {
+		normalized := Permission{
+			Resource: Resource(strings.TrimSpace(string(perm.QualifiedResource()))),
+			Action:   Action(strings.TrimSpace(string(perm.Action))),
+		}
+		if normalized.IsEmpty() || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+// UnionPermissions 求多个权限列表的并集
+func UnionPermissions(permLists ...[]Permission) []Permission {
+	var all []Permission
+	for _, permissions := range permLists {
+		all = append(all, permissions...)
+	}
+	return NormalizePermissions(all)
+}
+
+// IntersectPermissions 求两个权限列表的交集（同时存在于 a 和 b 中的权限）
+func IntersectPermissions(a, b []Permission) []Permission {
+	set := permissionSet(b)
+
+	var result []Permission
+	for _, perm := range NormalizePermissions(a) {
+		if set[perm] {
+			result = append(result, perm)
+		}
+	}
+	return result
+}
+
+// DifferencePermissions 求两个权限列表的差集（存在于 a 但不存在于 b 中的权限）
+func DifferencePermissions(a, b []Permission) []Permission {
+	set := permissionSet(b)
+
+	var result []Permission
+	for _, perm := range NormalizePermissions(a) {
+		if !set[perm] {
+			result = append(result, perm)
+		}
+	}
+	return result
+}
+
+// IsPermissionSubset 检查 sub 中的所有权限是否都包含在 super 中
+func IsPermissionSubset(sub, super []Permission) bool {
+	return len(DifferencePermissions(sub, super)) == 0
+}
+
+// PermissionSetsEqual 检查两个权限列表作为集合是否相等（忽略顺序和重复）
+func PermissionSetsEqual(a, b []Permission) bool {
+	return IsPermissionSubset(a, b) && IsPermissionSubset(b, a)
+}
+
+// permissionSet 构造规范化后的权限集合
+func permissionSet(permissions []Permission) map[Permission]bool {
+	normalized := NormalizePermissions(permissions)
+	set := make(map[Permission]bool, len(normalized))
+	for _, perm := range normalized {
+		set[perm] = true
+	}
+	return set
+}
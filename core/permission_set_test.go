@@ -0,0 +1,156 @@
+package core
+
+import "testing"
+
+var (
+	userRead   = Permission{Resource: ResourceUser, Action: ActionRead}
+	userWrite  = Permission{Resource: ResourceUser, Action: ActionWrite}
+	roleRead   = Permission{Resource: ResourceRole, Action: ActionRead}
+	doc42Read  = Permission{Resource: "document", ObjectID: "42", Action: ActionRead}
+	doc42Fused = Permission{Resource: "document:42", Action: ActionRead}
+)
+
+// equalPermissionLists 按顺序比较权限列表，nil 与空列表视为相等
+func equalPermissionLists(a, b []Permission) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNormalizePermissions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []Permission
+		want  []Permission
+	}{
+		{name: "空列表", input: nil, want: nil},
+		{name: "去重并保持首次出现顺序", input: []Permission{userWrite, userRead, userWrite}, want: []Permission{userWrite, userRead}},
+		{name: "去除首尾空白", input: []Permission{{Resource: " user ", Action: " read"}}, want: []Permission{userRead}},
+		{name: "丢弃空权限", input: []Permission{{Resource: "user"}, {Action: ActionRead}, {Resource: "  ", Action: ActionRead}, userRead}, want: []Permission{userRead}},
+		{name: "对象ID合并到资源", input: []Permission{doc42Read, doc42Fused}, want: []Permission{doc42Fused}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePermissions(tt.input); !equalPermissionLists(got, tt.want) {
+				t.Fatalf("NormalizePermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnionPermissions(t *testing.T) {
+	tests := []struct {
+		name  string
+		lists [][]Permission
+		want  []Permission
+	}{
+		{name: "无输入", lists: nil, want: nil},
+		{name: "单个列表去重", lists: [][]Permission{{userRead, userRead}}, want: []Permission{userRead}},
+		{name: "多个列表合并", lists: [][]Permission{{userRead, userWrite}, {userWrite, roleRead}}, want: []Permission{userRead, userWrite, roleRead}},
+		{name: "对象级权限的两种形式视为同一权限", lists: [][]Permission{{doc42Read}, {doc42Fused}}, want: []Permission{doc42Fused}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnionPermissions(tt.lists...); !equalPermissionLists(got, tt.want) {
+				t.Fatalf("UnionPermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntersectPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []Permission
+		want []Permission
+	}{
+		{name: "无交集", a: []Permission{userRead}, b: []Permission{roleRead}, want: nil},
+		{name: "b 为空", a: []Permission{userRead}, b: nil, want: nil},
+		{name: "按 a 的顺序返回", a: []Permission{userWrite, roleRead, userRead}, b: []Permission{userRead, userWrite}, want: []Permission{userWrite, userRead}},
+		{name: "a 中的重复只保留一个", a: []Permission{userRead, userRead}, b: []Permission{userRead}, want: []Permission{userRead}},
+		{name: "规范化后比较", a: []Permission{doc42Read}, b: []Permission{{Resource: " document:42", Action: "read "}}, want: []Permission{doc42Fused}},
+		{name: "不按覆盖关系匹配", a: []Permission{doc42Read}, b: []Permission{{Resource: "document", Action: ActionRead}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IntersectPermissions(tt.a, tt.b); !equalPermissionLists(got, tt.want) {
+				t.Fatalf("IntersectPermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifferencePermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []Permission
+		want []Permission
+	}{
+		{name: "a 为空", a: nil, b: []Permission{userRead}, want: nil},
+		{name: "b 为空时返回规范化的 a", a: []Permission{userRead, userRead, userWrite}, b: nil, want: []Permission{userRead, userWrite}},
+		{name: "移除 b 中的权限", a: []Permission{userRead, userWrite, roleRead}, b: []Permission{userWrite}, want: []Permission{userRead, roleRead}},
+		{name: "完全包含时为空", a: []Permission{userRead}, b: []Permission{userRead, userWrite}, want: nil},
+		{name: "对象级权限的两种形式视为同一权限", a: []Permission{doc42Read}, b: []Permission{doc42Fused}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DifferencePermissions(tt.a, tt.b); !equalPermissionLists(got, tt.want) {
+				t.Fatalf("DifferencePermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPermissionSubset(t *testing.T) {
+	tests := []struct {
+		name       string
+		sub, super []Permission
+		want       bool
+	}{
+		{name: "空集是任何集合的子集", sub: nil, super: nil, want: true},
+		{name: "空权限被忽略", sub: []Permission{{Resource: "user"}}, super: nil, want: true},
+		{name: "真子集", sub: []Permission{userRead}, super: []Permission{userRead, userWrite}, want: true},
+		{name: "存在多余权限", sub: []Permission{userRead, roleRead}, super: []Permission{userRead, userWrite}, want: false},
+		{name: "忽略重复", sub: []Permission{userRead, userRead}, super: []Permission{userRead}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermissionSubset(tt.sub, tt.super); got != tt.want {
+				t.Fatalf("IsPermissionSubset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionSetsEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []Permission
+		want bool
+	}{
+		{name: "都为空", a: nil, b: []Permission{}, want: true},
+		{name: "忽略顺序和重复", a: []Permission{userRead, userWrite, userRead}, b: []Permission{userWrite, userRead}, want: true},
+		{name: "对象级权限的两种形式", a: []Permission{doc42Read}, b: []Permission{doc42Fused}, want: true},
+		{name: "一方多出权限", a: []Permission{userRead}, b: []Permission{userRead, userWrite}, want: false},
+		{name: "操作不同", a: []Permission{userRead}, b: []Permission{userWrite}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PermissionSetsEqual(tt.a, tt.b); got != tt.want {
+				t.Fatalf("PermissionSetsEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package core
+
+import "testing"
+
+func TestPermissionCoversWildcard(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted Permission
+		target  Permission
+		want    bool
+	}{
+		{name: "资源通配覆盖任意资源", granted: Permission{Resource: ResourceAll, Action: ActionRead}, target: userRead, want: true},
+		{name: "资源通配覆盖对象级权限", granted: Permission{Resource: ResourceAll, Action: ActionRead}, target: doc42Read, want: true},
+		{name: "资源通配不放宽操作", granted: Permission{Resource: ResourceAll, Action: ActionRead}, target: userWrite, want: false},
+		{name: "操作通配覆盖同一资源的任意操作", granted: Permission{Resource: ResourceUser, Action: ActionAll}, target: userWrite, want: true},
+		{name: "操作通配不放宽资源", granted: Permission{Resource: ResourceUser, Action: ActionAll}, target: roleRead, want: false},
+		{name: "完全通配", granted: Permission{Resource: ResourceAll, Action: ActionAll}, target: doc42Read, want: true},
+		{name: "目标为通配时不被具体授权覆盖", granted: userRead, target: Permission{Resource: ResourceAll, Action: ActionRead}, want: false},
+		{name: "目标操作为通配时不被具体授权覆盖", granted: userRead, target: Permission{Resource: ResourceUser, Action: ActionAll}, want: false},
+		{name: "对象通配只匹配同类资源", granted: Permission{Resource: "document", ObjectID: "*", Action: ActionRead}, target: doc42Read, want: true},
+		{name: "对象通配不匹配其他资源", granted: Permission{Resource: "document", ObjectID: "*", Action: ActionRead}, target: Permission{Resource: "folder", ObjectID: "42", Action: ActionRead}, want: false},
+		{name: "类型级授权覆盖对象", granted: Permission{Resource: "document", Action: ActionRead}, target: doc42Read, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.granted.Covers(tt.target); got != tt.want {
+				t.Fatalf("%v.Covers(%v) = %v, want %v", tt.granted, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectMatchWildcard(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		granted   string
+		want      bool
+	}{
+		{name: "完全一致", requested: "user", granted: "user", want: true},
+		{name: "资源通配匹配任意资源", requested: "user", granted: "*", want: true},
+		{name: "资源通配匹配对象", requested: "document:42", granted: "*", want: true},
+		{name: "类型级授权匹配对象", requested: "document:42", granted: "document", want: true},
+		{name: "对象通配", requested: "document:42", granted: "document:*", want: true},
+		{name: "对象前缀通配", requested: "document:proj-1", granted: "document:proj-*", want: true},
+		{name: "对象前缀通配不匹配", requested: "document:42", granted: "document:proj-*", want: false},
+		{name: "对象通配不跨资源", requested: "folder:42", granted: "document:*", want: false},
+		{name: "不同资源", requested: "role", granted: "user", want: false},
+		{name: "对象授权不覆盖类型", requested: "document", granted: "document:*", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ObjectMatch(tt.requested, tt.granted); got != tt.want {
+				t.Fatalf("ObjectMatch(%q, %q) = %v, want %v", tt.requested, tt.granted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionCoversWildcard(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    bool
+		granted   Action
+		requested Action
+		want      bool
+	}{
+		{name: "相同操作", granted: ActionRead, requested: ActionRead, want: true},
+		{name: "通配覆盖读", granted: ActionAll, requested: ActionRead, want: true},
+		{name: "通配覆盖自定义操作", granted: ActionAll, requested: Action("approve"), want: true},
+		{name: "严格区分写操作时通配仍覆盖 create", strict: true, granted: ActionAll, requested: ActionCreate, want: true},
+		{name: "write 覆盖 update", granted: ActionWrite, requested: ActionUpdate, want: true},
+		{name: "严格区分写操作时 write 不覆盖 create", strict: true, granted: ActionWrite, requested: ActionCreate, want: false},
+		{name: "具体操作不覆盖通配", granted: ActionRead, requested: ActionAll, want: false},
+		{name: "write 不覆盖通配", granted: ActionWrite, requested: ActionAll, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Enforcer{strictWriteActions: tt.strict}
+			if got := e.actionCovers(tt.granted, tt.requested); got != tt.want {
+				t.Fatalf("actionCovers(%q, %q) = %v, want %v", tt.granted, tt.requested, got, tt.want)
+			}
+		})
+	}
+}
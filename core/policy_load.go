@@ -0,0 +1,231 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// PolicyLoadConfig 策略加载配置
+type PolicyLoadConfig struct {
+	// Tolerant 容错加载：无效的策略行（字段缺失、操作非法、规则长度与模型不符等）被隔离到 casbin_policy_quarantine 表，
+	// 其余规则正常加载；默认关闭，此时遇到无效行整体加载失败。
+	// 只对内置的 GORM 适配器生效（包括 WithGormDB 注入的连接），自定义适配器仍按适配器自身的行为加载
+	Tolerant bool `json:"tolerant"`
+}
+
+// QuarantinedRule 加载时被隔离的策略行
+type QuarantinedRule struct {
+	PType      string    `json:"ptype"`      // 策略类型（p、g 等）
+	Rule       []string  `json:"rule"`       // 策略字段
+	Reason     string    `json:"reason"`     // 隔离原因
+	DetectedAt time.Time `json:"detectedAt"` // 首次发现时间
+}
+
+// String 返回策略行的字符串表示
+func (r QuarantinedRule) String() string {
+	return strings.Join(append([]string{r.PType}, r.Rule...), ", ")
+}
+
+// PolicyLoadReport 一次容错加载的结果
+type PolicyLoadReport struct {
+	LoadedAt    time.Time         `json:"loadedAt"`    // 加载时间
+	Loaded      int               `json:"loaded"`      // 成功加载的规则数
+	Quarantined []QuarantinedRule `json:"quarantined"` // 被隔离的策略行
+}
+
+// PolicyLoadHook 容错加载发现无效策略行时的回调（每次加载调用一次，report 包含本次所有被隔离的行）
+type PolicyLoadHook func(report PolicyLoadReport)
+
+// HealthStatus 引擎健康状态
+type HealthStatus struct {
+	Healthy    bool              `json:"healthy"`              // 最近一次重载成功、没有被隔离的策略行且 Watcher 处于连接状态
+	PolicyLoad *PolicyLoadReport `json:"policyLoad,omitempty"` // 最近一次容错加载的结果（未启用容错加载时为空）
+	Reload     ReloadStats       `json:"reload"`               // 同步通知触发的策略重载统计
+	Watcher    *WatcherStats     `json:"watcher,omitempty"`    // Watcher 连接状态（关闭 Watcher 或自定义 Watcher 不报告时为空）
+}
+
+// RuleSource 按行读取存储中的策略，每行为 ptype 加策略字段（末尾的空字段已去除）
+type RuleSource interface {
+	LoadRules(ctx context.Context) ([][]string, error)
+}
+
+// sqlRuleSource 从 GORM 适配器的策略表读取策略行
+type sqlRuleSource struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLRuleSource 创建从 GORM 适配器策略表（ptype, v0..v5）读取策略行的数据源
+func NewSQLRuleSource(db *sql.DB, table string) RuleSource {
+	return &sqlRuleSource{db: db, table: table}
+}
+
+// LoadRules 读取所有策略行
+func (s *sqlRuleSource) LoadRules(ctx context.Context) ([][]string, error) {
+	query := fmt.Sprintf(`SELECT COALESCE(ptype, ''), COALESCE(v0, ''), COALESCE(v1, ''), COALESCE(v2, ''), COALESCE(v3, ''), COALESCE(v4, ''), COALESCE(v5, '') FROM %s ORDER BY id`, s.table)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("读取策略表失败: %v", err)
+	}
+	defer rows.Close()
+
+	var rules [][]string
+	for rows.Next() {
+		rule := make([]string, 7)
+		if err := rows.Scan(&rule[0], &rule[1], &rule[2], &rule[3], &rule[4], &rule[5], &rule[6]); err != nil {
+			return nil, fmt.Errorf("读取策略行失败: %v", err)
+		}
+		// 与 GORM 适配器一致，去除末尾的空字段
+		for len(rule) > 1 && rule[len(rule)-1] == "" {
+			rule = rule[:len(rule)-1]
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// ValidateStoredRule 校验存储中的策略行（ptype 加策略字段）
+// 权限策略的主体、域、资源、操作和角色分配的三个字段不能为空，操作必须是内置操作或合法的自定义操作；
+// 规则长度与模型是否相符由加载时的模型校验负责
+func ValidateStoredRule(rule []string) error {
+	if len(rule) == 0 || rule[0] == "" {
+		return fmt.Errorf("缺少策略类型")
+	}
+
+	var required int
+	switch rule[0] {
+	case "p":
+		required = 4
+	case "g":
+		required = 3
+	default:
+		return nil
+	}
+	if len(rule)-1 < required {
+		return fmt.Errorf("字段数量不足：需要 %d 个，实际 %d 个", required, len(rule)-1)
+	}
+	for i, value := range rule[1 : required+1] {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("第 %d 个字段为空", i)
+		}
+	}
+
+	if rule[0] == "p" {
+		action, err := ParseAction(rule[4])
+		if err != nil && ValidateCustomAction(action) != nil {
+			return fmt.Errorf("非法操作 '%s'", rule[4])
+		}
+	}
+	return nil
+}
+
+// TolerantAdapter 容错加载适配器
+// 加载时逐行读取 RuleSource，无效的行被跳过并通过回调报告，其余规则正常加载；写入操作委托给原适配器
+type TolerantAdapter struct {
+	adapter persist.Adapter
+	source  RuleSource
+	onLoad  func(PolicyLoadReport)
+}
+
+// NewTolerantAdapter 创建容错加载适配器，onLoad 在每次加载完成后调用
+func NewTolerantAdapter(adapter persist.Adapter, source RuleSource, onLoad func(PolicyLoadReport)) *TolerantAdapter {
+	return &TolerantAdapter{adapter: adapter, source: source, onLoad: onLoad}
+}
+
+// LoadPolicy 逐行加载策略，读取策略表失败时整体失败
+func (a *TolerantAdapter) LoadPolicy(m model.Model) error {
+	rules, err := a.source.LoadRules(context.Background())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	report := PolicyLoadReport{LoadedAt: now, Quarantined: []QuarantinedRule{}}
+	for _, rule := range rules {
+		err := ValidateStoredRule(rule)
+		if err == nil {
+			err = persist.LoadPolicyArray(rule, m)
+		}
+		if err != nil {
+			report.Quarantined = append(report.Quarantined, QuarantinedRule{PType: rule[0], Rule: rule[1:], Reason: err.Error(), DetectedAt: now})
+			continue
+		}
+		report.Loaded++
+	}
+
+	if a.onLoad != nil {
+		a.onLoad(report)
+	}
+	return nil
+}
+
+// SavePolicy 保存所有策略
+func (a *TolerantAdapter) SavePolicy(m model.Model) error {
+	return a.adapter.SavePolicy(m)
+}
+
+// AddPolicy 添加策略
+func (a *TolerantAdapter) AddPolicy(sec string, ptype string, rule []string) error {
+	return a.adapter.AddPolicy(sec, ptype, rule)
+}
+
+// RemovePolicy 删除策略
+func (a *TolerantAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	return a.adapter.RemovePolicy(sec, ptype, rule)
+}
+
+// RemoveFilteredPolicy 按条件删除策略
+func (a *TolerantAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	return a.adapter.RemoveFilteredPolicy(sec, ptype, fieldIndex, fieldValues...)
+}
+
+// AddPolicies 批量添加策略
+func (a *TolerantAdapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	batch, ok := a.adapter.(persist.BatchAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持批量写入")
+	}
+	return batch.AddPolicies(sec, ptype, rules)
+}
+
+// RemovePolicies 批量删除策略
+func (a *TolerantAdapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	batch, ok := a.adapter.(persist.BatchAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持批量写入")
+	}
+	return batch.RemovePolicies(sec, ptype, rules)
+}
+
+// UpdatePolicy 更新策略
+func (a *TolerantAdapter) UpdatePolicy(sec string, ptype string, oldRule, newRule []string) error {
+	updatable, ok := a.adapter.(persist.UpdatableAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持更新策略")
+	}
+	return updatable.UpdatePolicy(sec, ptype, oldRule, newRule)
+}
+
+// UpdatePolicies 批量更新策略
+func (a *TolerantAdapter) UpdatePolicies(sec string, ptype string, oldRules, newRules [][]string) error {
+	updatable, ok := a.adapter.(persist.UpdatableAdapter)
+	if !ok {
+		return fmt.Errorf("适配器不支持更新策略")
+	}
+	return updatable.UpdatePolicies(sec, ptype, oldRules, newRules)
+}
+
+// UpdateFilteredPolicies 按条件替换策略
+func (a *TolerantAdapter) UpdateFilteredPolicies(sec string, ptype string, newRules [][]string, fieldIndex int, fieldValues ...string) ([][]string, error) {
+	updatable, ok := a.adapter.(persist.UpdatableAdapter)
+	if !ok {
+		return nil, fmt.Errorf("适配器不支持更新策略")
+	}
+	return updatable.UpdateFilteredPolicies(sec, ptype, newRules, fieldIndex, fieldValues...)
+}
@@ -0,0 +1,79 @@
+package core
+
+import "sort"
+
+// DefaultRulePriority 未显式设置优先级的规则的默认优先级
+// 数值越小优先级越高，与 Casbin priority 模型一致
+const DefaultRulePriority = 1000
+
+// RulePriorityResolver 规则优先级解析器，返回规则显式设置的优先级，未设置时 ok=false
+type RulePriorityResolver func(rule Policy) (priority int, ok bool)
+
+// SetRulePriorityResolver 设置规则优先级解析器
+func (e *Enforcer) SetRulePriorityResolver(resolver RulePriorityResolver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rulePriorityResolver = resolver
+}
+
+// rulePriority 计算规则的优先级
+func (e *Enforcer) rulePriority(rule Policy) int {
+	e.mu.RLock()
+	resolver := e.rulePriorityResolver
+	e.mu.RUnlock()
+
+	if resolver != nil {
+		if priority, ok := resolver(rule); ok {
+			return priority
+		}
+	}
+	return DefaultRulePriority
+}
+
+// SortRulesByPriority 按优先级对规则稳定排序，排在前面的规则优先生效
+// 优先级相同时，租户域的规则先于全局域（*）的规则，再按主体、资源、操作排序以保证结果确定
+func SortRulesByPriority(rules []Policy) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rulePrecedes(rules[i], rules[j])
+	})
+}
+
+// rulePrecedes 判断规则 a 是否先于规则 b 生效
+func rulePrecedes(a, b Policy) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if (a.Domain == "*") != (b.Domain == "*") {
+		return b.Domain == "*"
+	}
+	if a.Subject != b.Subject {
+		return a.Subject < b.Subject
+	}
+	if a.Resource != b.Resource {
+		return a.Resource < b.Resource
+	}
+	return a.Action < b.Action
+}
+
+// MatchingRules 获取用户在指定域中与目标权限匹配且生效的规则（包括角色继承），按优先级排序
+// 第一条规则即为决定检查结果的规则；用户被显式拒绝时没有生效的规则
+func (e *Enforcer) MatchingRules(userKey, domain string, permission Permission) ([]Policy, error) {
+	if !e.checkAllowed(userKey, domain) || e.denied(userKey, domain, permission) {
+		return nil, nil
+	}
+
+	rules, err := e.implicitRules(userKey, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Policy
+	for _, rule := range rules {
+		if e.covers(rule.Permission(), permission) {
+			matched = append(matched, rule)
+		}
+	}
+
+	SortRulesByPriority(matched)
+	return matched, nil
+}
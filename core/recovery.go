@@ -0,0 +1,46 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// RecoveryConfig panic 恢复配置
+// 开启时（默认），底层 Casbin 或适配器的 panic 在公开方法边界被转换为 PanicError 返回，并将堆栈写入日志
+type RecoveryConfig struct {
+	// Disabled 关闭 panic 恢复，panic 原样向上抛出（便于开发环境直接定位问题）
+	Disabled bool `json:"disabled"`
+
+	// DisableStack 不采集堆栈，日志和 PanicError 中只包含 panic 值
+	DisableStack bool `json:"disableStack"`
+}
+
+// PanicError 公开方法内部发生 panic 后转换得到的错误
+// errors.Is(err, ErrInternal) 可识别此类错误
+type PanicError struct {
+	Operation string      // 发生 panic 的方法名
+	Value     interface{} // panic 值
+	Stack     []byte      // panic 时的调用堆栈（关闭堆栈采集时为空）
+}
+
+// NewPanicError 创建 panic 错误，captureStack 为 true 时采集当前调用堆栈
+func NewPanicError(operation string, value interface{}, captureStack bool) *PanicError {
+	panicErr := &PanicError{
+		Operation: operation,
+		Value:     value,
+	}
+	if captureStack {
+		panicErr.Stack = debug.Stack()
+	}
+	return panicErr
+}
+
+// Error 实现 error 接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %s 发生 panic: %v", ErrInternal.Error(), e.Operation, e.Value)
+}
+
+// Unwrap 返回 ErrInternal，便于调用方按错误类型分类处理
+func (e *PanicError) Unwrap() error {
+	return ErrInternal
+}
@@ -0,0 +1,41 @@
+package core
+
+// RegoPackage 导出的 Rego 模块的包名，查询入口为 data.casbinx.authz.allow
+const RegoPackage = "casbinx.authz"
+
+// Rego 导出中无法翻译的结构类型
+const (
+	RegoConstructTenantLockdown = "tenant_lockdown" // 租户封锁：封锁期间仅放行指定角色，未翻译
+	RegoConstructTenantAlias    = "tenant_alias"    // 租户别名：别名下的授权已并入规范租户，但 input.tenant 需由调用方解析为规范租户键
+	RegoConstructEnvironment    = "environment"     // 环境限定授权：按导出实例的当前环境展开，不随 OPA 侧环境变化
+	RegoConstructRollout        = "rollout"         // 灰度变更：按用户百分比或租户白名单生效，未翻译
+)
+
+// RegoExport 租户权限的 OPA/Rego 导出结果
+// Module 与租户无关，多个租户的 Data 可以合并后加载到同一个 OPA 实例；
+// 查询输入为 {"user": ..., "tenant": ..., "resource": ..., "action": ...}
+type RegoExport struct {
+	TenantKey string              `json:"tenantKey"` // 导出的租户
+	Module    string              `json:"module"`    // Rego 策略模块
+	Data      map[string]any      `json:"data"`      // 数据文档，加载到 data 根路径（数据位于 data.casbinx.tenants.<租户键>）
+	Issues    []RegoFidelityIssue `json:"issues"`    // 无法翻译或语义存在差异的结构
+}
+
+// RegoTenantData 单个租户的 Rego 数据
+type RegoTenantData struct {
+	Users map[string]RegoSubjectData `json:"users"` // 用户的角色和直接权限
+	Roles map[string]RegoSubjectData `json:"roles"` // 角色的权限（含全局角色和链接到租户的共享角色）
+}
+
+// RegoSubjectData 主体在租户内的授权数据
+type RegoSubjectData struct {
+	Roles       []string     `json:"roles,omitempty"` // 分配的角色（仅用户）
+	Permissions []Permission `json:"permissions"`     // 权限列表
+}
+
+// RegoFidelityIssue 导出保真度问题
+type RegoFidelityIssue struct {
+	Construct string `json:"construct"`         // 结构类型，见 RegoConstruct 常量
+	Subject   string `json:"subject,omitempty"` // 涉及的主体、别名或灰度变更
+	Detail    string `json:"detail"`            // 差异说明
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Zanzibar 关系元组中使用的对象类型和关系
+//
+// casbinx 数据与关系元组的对应方式：
+//
+//	角色分配      tenant:<租户>#<角色>@user:<用户>
+//	用户直接权限  resource:<租户>/<资源>#<操作>@user:<用户>
+//	角色权限      resource:<租户>/<资源>#<操作>@tenant:<租户>#<角色>
+//	角色继承      tenant:<租户>#<父角色>@tenant:<租户>#<子角色>
+//
+// 全局域的租户部分为 "*"。对象级权限的资源（如 project:123）原样保留在对象标识中
+const (
+	RelationTypeTenant   = "tenant"   // 租户对象，关系为角色
+	RelationTypeResource = "resource" // 资源对象，关系为操作
+	RelationTypeUser     = "user"     // 用户主体
+)
+
+// RelationTuple Zanzibar 风格的关系元组，文本格式为 type:id#relation@subjectType:subjectID[#subjectRelation]
+type RelationTuple struct {
+	ObjectType      string `json:"objectType"`                // 对象类型
+	ObjectID        string `json:"objectId"`                  // 对象标识
+	Relation        string `json:"relation"`                  // 关系
+	SubjectType     string `json:"subjectType"`               // 主体类型
+	SubjectID       string `json:"subjectId"`                 // 主体标识
+	SubjectRelation string `json:"subjectRelation,omitempty"` // 主体关系（主体为用户集合时使用，如角色成员）
+}
+
+// String 返回关系元组的文本格式
+func (t RelationTuple) String() string {
+	subject := t.SubjectType + ":" + t.SubjectID
+	if t.SubjectRelation != "" {
+		subject += "#" + t.SubjectRelation
+	}
+	return fmt.Sprintf("%s:%s#%s@%s", t.ObjectType, t.ObjectID, t.Relation, subject)
+}
+
+// ParseRelationTuple 解析文本格式的关系元组
+func ParseRelationTuple(value string) (RelationTuple, error) {
+	object, subject, ok := strings.Cut(strings.TrimSpace(value), "@")
+	if !ok {
+		return RelationTuple{}, fmt.Errorf("%w: %s", ErrInvalidRelationTuple, value)
+	}
+
+	// 对象标识可能包含 ':'（对象级权限），关系取最后一个 '#' 之后的部分
+	hashIndex := strings.LastIndex(object, "#")
+	if hashIndex < 0 {
+		return RelationTuple{}, fmt.Errorf("%w: %s", ErrInvalidRelationTuple, value)
+	}
+	objectType, objectID, ok := strings.Cut(object[:hashIndex], ":")
+	if !ok {
+		return RelationTuple{}, fmt.Errorf("%w: %s", ErrInvalidRelationTuple, value)
+	}
+
+	subjectRef, subjectRelation, _ := strings.Cut(subject, "#")
+	subjectType, subjectID, ok := strings.Cut(subjectRef, ":")
+	if !ok {
+		return RelationTuple{}, fmt.Errorf("%w: %s", ErrInvalidRelationTuple, value)
+	}
+
+	tuple := RelationTuple{
+		ObjectType:      objectType,
+		ObjectID:        objectID,
+		Relation:        object[hashIndex+1:],
+		SubjectType:     subjectType,
+		SubjectID:       subjectID,
+		SubjectRelation: subjectRelation,
+	}
+	if tuple.ObjectType == "" || tuple.ObjectID == "" || tuple.Relation == "" || tuple.SubjectType == "" || tuple.SubjectID == "" {
+		return RelationTuple{}, fmt.Errorf("%w: %s", ErrInvalidRelationTuple, value)
+	}
+	return tuple, nil
+}
+
+// RelationImportResult 关系元组导入结果
+type RelationImportResult struct {
+	Imported int                     `json:"imported"` // 成功导入的元组数量
+	Failures []RelationImportFailure `json:"failures"` // 导入失败的元组
+}
+
+// RelationImportFailure 导入失败的关系元组
+type RelationImportFailure struct {
+	Tuple string `json:"tuple"` // 元组文本
+	Error string `json:"error"` // 失败原因
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// 策略重载合并默认值
+const (
+	DefaultReloadDebounce = 100 * time.Millisecond // 默认防抖窗口
+	DefaultReloadMaxDelay = time.Second            // 默认最大延迟
+)
+
+// ReloadStats 策略重载队列统计
+type ReloadStats struct {
+	Pending            int           `json:"pending"`            // 等待中的重载请求数（队列深度）
+	TotalRequests      int64         `json:"totalRequests"`      // 累计收到的重载请求数
+	TotalReloads       int64         `json:"totalReloads"`       // 累计实际执行的重载次数
+	LastReloadAt       time.Time     `json:"lastReloadAt"`       // 最近一次重载完成时间
+	LastReloadDuration time.Duration `json:"lastReloadDuration"` // 最近一次重载耗时
+	LastCoalesced      int           `json:"lastCoalesced"`      // 最近一次重载合并的请求数
+	LastError          string        `json:"lastError"`          // 最近一次重载的错误（成功时为空）
+}
+
+// ReloadCoalescer 策略重载合并器
+// 突发的同步通知在防抖窗口内合并为一次重载；持续有通知时，距第一个未处理请求超过最大延迟也会强制重载，
+// 避免每条通知都触发一次全量 LoadPolicy。
+type ReloadCoalescer struct {
+	reload   func() error
+	debounce time.Duration
+	maxDelay time.Duration
+	logger   Logger
+	metrics  MetricsRecorder
+
+	mu           sync.Mutex
+	timer        *time.Timer
+	firstPending time.Time
+	stats        ReloadStats
+
+	reloadMu sync.Mutex // 保证同一时间只有一个重载在执行
+}
+
+// NewReloadCoalescer 创建策略重载合并器，debounce/maxDelay 为 0 时使用默认值
+func NewReloadCoalescer(reload func() error, debounce, maxDelay time.Duration) *ReloadCoalescer {
+	if debounce <= 0 {
+		debounce = DefaultReloadDebounce
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultReloadMaxDelay
+	}
+	if maxDelay < debounce {
+		maxDelay = debounce
+	}
+
+	return &ReloadCoalescer{
+		reload:   reload,
+		debounce: debounce,
+		maxDelay: maxDelay,
+		logger:   DefaultLogger(),
+		metrics:  NopMetrics(),
+	}
+}
+
+// SetLogger 设置重载结果的日志输出，传入 nil 时使用 slog 默认 Logger
+func (c *ReloadCoalescer) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = DefaultLogger()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logger = logger
+}
+
+// SetMetrics 设置重载指标采集，传入 nil 时不采集
+func (c *ReloadCoalescer) SetMetrics(metrics MetricsRecorder) {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = metrics
+}
+
+// Request 提交一次重载请求
+func (c *ReloadCoalescer) Request() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.stats.Pending == 0 {
+		c.firstPending = now
+	}
+	c.stats.Pending++
+	c.stats.TotalRequests++
+
+	// 防抖：每个新请求推迟重载，但不超过第一个未处理请求的最大延迟
+	delay := c.debounce
+	if deadline := c.firstPending.Add(c.maxDelay); now.Add(delay).After(deadline) {
+		delay = deadline.Sub(now)
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.AfterFunc(delay, func() { _ = c.flush() })
+}
+
+// Flush 立即执行所有等待中的重载请求
+func (c *ReloadCoalescer) Flush() error {
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+
+	return c.flush()
+}
+
+// Stats 获取重载队列统计
+func (c *ReloadCoalescer) Stats() ReloadStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// flush 执行一次合并后的重载
+func (c *ReloadCoalescer) flush() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.mu.Lock()
+	coalesced := c.stats.Pending
+	firstPending := c.firstPending
+	c.stats.Pending = 0
+	c.mu.Unlock()
+
+	if coalesced == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := c.reload()
+	finished := time.Now()
+
+	c.mu.Lock()
+	c.stats.TotalReloads++
+	c.stats.LastReloadAt = finished
+	c.stats.LastReloadDuration = finished.Sub(start)
+	c.stats.LastCoalesced = coalesced
+	c.stats.LastError = ""
+	if err != nil {
+		c.stats.LastError = err.Error()
+	}
+	logger := c.logger
+	metrics := c.metrics
+	c.mu.Unlock()
+
+	metrics.ObserveReload(coalesced, finished.Sub(firstPending), finished.Sub(start), err)
+	if err != nil {
+		if ErrorClass(err) == "error" {
+			metrics.ObserveDBError("LoadPolicy")
+		}
+		logger.Error("重新加载策略失败", "coalesced", coalesced, "error", err)
+		return err
+	}
+	logger.Info("策略重新加载完成", "coalesced", coalesced, "duration", finished.Sub(start))
+	return nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// ResidencyConfig 数据驻留配置
+// 每个区域使用独立的数据库，受监管租户的策略只保存在其所属区域
+type ResidencyConfig struct {
+	Regions       map[string]string `json:"regions"`       // 区域 -> 数据库连接字符串
+	DefaultRegion string            `json:"defaultRegion"` // 默认区域，未指定区域的租户及租户区域登记表存放于此
+}
+
+// Enabled 是否配置了数据驻留
+func (c ResidencyConfig) Enabled() bool {
+	return len(c.Regions) > 0
+}
+
+// Validate 验证数据驻留配置
+func (c ResidencyConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if _, exists := c.Regions[c.DefaultRegion]; !exists {
+		return fmt.Errorf("默认区域 '%s' 未配置数据库", c.DefaultRegion)
+	}
+	for region, dsn := range c.Regions {
+		if region == "" || dsn == "" {
+			return fmt.Errorf("区域及其数据库连接字符串不能为空")
+		}
+	}
+	return nil
+}
+
+// TenantRegion 租户所属区域
+type TenantRegion struct {
+	TenantKey  string    `json:"tenantKey"`  // 租户键
+	Region     string    `json:"region"`     // 所属区域
+	AssignedBy string    `json:"assignedBy"` // 登记者
+	AssignedAt time.Time `json:"assignedAt"` // 登记时间
+}
+
+// DomainWriteGuard 域写入守卫，返回错误表示不允许向该域写入策略
+type DomainWriteGuard func(domain string) error
+
+// AddDomainWriteGuard 注册域写入守卫
+func (e *Enforcer) AddDomainWriteGuard(guard DomainWriteGuard) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.domainWriteGuards = append(e.domainWriteGuards, guard)
+}
+
+// checkDomainWrite 检查是否允许向域写入策略
+func (e *Enforcer) checkDomainWrite(domain string) error {
+	e.mu.RLock()
+	guards := e.domainWriteGuards
+	e.mu.RUnlock()
+
+	for _, guard := range guards {
+		if err := guard(domain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
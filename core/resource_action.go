@@ -0,0 +1,46 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// ResourceAction 租户在资源上定义的自定义操作（如 invoice:approve）
+// TenantKey 为 * 时对所有租户生效
+type ResourceAction struct {
+	TenantKey   string    `json:"tenantKey"`   // 定义所在的租户，* 表示全局
+	Resource    Resource  `json:"resource"`    // 资源类型
+	Action      Action    `json:"action"`      // 自定义操作
+	Description string    `json:"description"` // 操作说明
+	CreatedBy   string    `json:"createdBy"`   // 定义者
+	CreatedAt   time.Time `json:"createdAt"`   // 定义时间
+}
+
+// ActionValidator 授权时的操作校验器，内置操作之外的操作由其判断在租户的资源上是否已定义
+type ActionValidator func(tenantKey string, permission Permission) error
+
+// IsBuiltinAction 是否为内置操作
+func IsBuiltinAction(action Action) bool {
+	_, err := ParseAction(string(action))
+	return err == nil
+}
+
+// ValidateCustomAction 校验自定义操作名称：不能与内置操作重名，不能以 _ 开头，不能包含 : , 或空白字符
+func ValidateCustomAction(action Action) error {
+	if err := RequireArg("action", action); err != nil {
+		return err
+	}
+	if IsBuiltinAction(action) {
+		return &FieldError{Field: "action", Constraint: ConstraintFormat, Detail: "与内置操作重名"}
+	}
+	if strings.HasPrefix(string(action), "_") || strings.ContainsAny(string(action), ":, \t\r\n") {
+		return &FieldError{Field: "action", Constraint: ConstraintFormat, Detail: "不能以 _ 开头，不能包含 : , 或空白字符"}
+	}
+	return nil
+}
+
+// ResourceType 返回资源的类型部分：对象资源 "资源:对象ID" 返回 "资源"，类型级资源返回本身
+func ResourceType(resource Resource) Resource {
+	resourceType, _, _ := strings.Cut(string(resource), ":")
+	return Resource(resourceType)
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ResultMeta 查询结果的元数据
+// PolicyVersion 由策略内容计算，可跨实例比较；Revision 是本实例单调递增的修订号，
+// 每次策略写入或重新加载都会增加，适合在同一进程内作为结果缓存的键
+type ResultMeta struct {
+	GeneratedAt   time.Time `json:"generatedAt,omitzero"`    // 结果生成时间
+	PolicyVersion string    `json:"policyVersion,omitempty"` // 生成结果时的策略版本
+	Revision      uint64    `json:"revision,omitempty"`      // 生成结果时本实例的策略修订号
+}
+
+// CheckResult 携带修订号的权限检查结果
+// 调用方可按 (用户, 租户, 权限, Revision) 缓存检查结果，修订号变化后重新检查
+type CheckResult struct {
+	Allowed  bool   `json:"allowed"`  // 是否允许
+	Revision uint64 `json:"revision"` // 检查时本实例的策略修订号
+}
+
+// ListResult 列表查询结果
+// 除数据外携带生成时间和策略版本，调用方缓存结果后可通过比较 PolicyVersion 或 Revision 判断是否过期
+type ListResult[T any] struct {
+	Items []T `json:"items"` // 数据
+	Total int `json:"total"` // 总条数
+	ResultMeta
+}
+
+// NewListResult 创建列表查询结果，nil 列表序列化为 []
+func NewListResult[T any](items []T, meta ResultMeta) *ListResult[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &ListResult[T]{
+		Items:      items,
+		Total:      len(items),
+		ResultMeta: meta,
+	}
+}
+
+// Stamp 为分页响应设置结果元数据
+// 翻页过程中 PolicyVersion 发生变化说明策略已变更，调用方应从第一页重新获取
+func (p *PageResponse[T]) Stamp(meta ResultMeta) *PageResponse[T] {
+	p.ResultMeta = meta
+	return p
+}
+
+// Revision 返回本实例当前的策略修订号
+// 修订号单调递增，每次策略写入、重新加载或附属状态同步都会增加；不同实例的修订号互不相关
+func (e *Enforcer) Revision() uint64 {
+	return e.revision.Load()
+}
+
+// ResultMeta 生成查询结果的元数据，应在读取数据之前调用
+// 读取期间策略发生变更时，元数据中的版本较旧，调用方据此重新获取而不会误用过期结果
+func (e *Enforcer) ResultMeta() (ResultMeta, error) {
+	revision := e.Revision()
+	version, err := e.PolicyVersion()
+	if err != nil {
+		return ResultMeta{}, err
+	}
+	return ResultMeta{GeneratedAt: time.Now(), PolicyVersion: version, Revision: revision}, nil
+}
+
+// PolicyVersion 返回当前策略内容的版本标识
+// 由全部权限策略和角色分配的内容计算得到，与加载顺序无关，策略相同的实例版本相同，可用于跨实例比较。
+// 结果在缓存失效（策略写入、重新加载或附属状态同步）前复用
+func (e *Enforcer) PolicyVersion() (string, error) {
+	revision := e.revision.Load()
+
+	e.versionMu.Lock()
+	if e.versionRevision == revision && e.version != "" {
+		version := e.version
+		e.versionMu.Unlock()
+		return version, nil
+	}
+	e.versionMu.Unlock()
+
+	policies, err := e.enforcer.GetPolicy()
+	if err != nil {
+		return "", err
+	}
+	groupings, err := e.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return "", err
+	}
+
+	rows := make([]string, 0, len(policies)+len(groupings))
+	for _, policy := range policies {
+		rows = append(rows, "p\x00"+strings.Join(policy, "\x00"))
+	}
+	for _, grouping := range groupings {
+		rows = append(rows, "g\x00"+strings.Join(grouping, "\x00"))
+	}
+	sort.Strings(rows)
+
+	hash := sha256.New()
+	for _, row := range rows {
+		hash.Write([]byte(row))
+		hash.Write([]byte{'\n'})
+	}
+	version := hex.EncodeToString(hash.Sum(nil))[:16]
+
+	// 计算期间缓存已失效时不保存，下次调用重新计算
+	e.versionMu.Lock()
+	if e.revision.Load() == revision {
+		e.version = version
+		e.versionRevision = revision
+	}
+	e.versionMu.Unlock()
+
+	return version, nil
+}
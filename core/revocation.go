@@ -0,0 +1,16 @@
+package core
+
+import "time"
+
+// ResourceRevocation 按资源批量撤销的预览或执行结果
+type ResourceRevocation struct {
+	ID            int64     `json:"id,omitempty"`        // 撤销记录ID（仅执行后有值）
+	TenantKey     string    `json:"tenantKey"`           // 租户键
+	Resource      Resource  `json:"resource"`            // 被撤销的资源
+	Policies      []Policy  `json:"policies"`            // 受影响的权限策略
+	AffectedUsers []string  `json:"affectedUsers"`       // 受影响的用户（直接授权）
+	AffectedRoles []string  `json:"affectedRoles"`       // 受影响的角色
+	Applied       bool      `json:"applied"`             // 是否已执行（预览时为 false）
+	RevokedBy     string    `json:"revokedBy,omitempty"` // 执行撤销的操作者
+	RevokedAt     time.Time `json:"revokedAt,omitempty"` // 执行时间
+}
@@ -1,12 +1,28 @@
 package core
 
+import "time"
+
 // RoleFilter 角色过滤器
 type RoleFilter struct {
-	KeyPattern  string `json:"keyPattern"`  // 角色键匹配模式
-	NamePattern string `json:"namePattern"` // 角色名匹配模式
-	TenantKey   string `json:"tenantKey"`   // 租户键过滤条件
+	KeyPattern          string    `json:"keyPattern"`                    // 角色键匹配模式（glob，* 匹配任意字符串，? 匹配单个字符，不区分大小写）
+	NamePattern         string    `json:"namePattern"`                   // 角色名匹配模式（glob，规则同 KeyPattern）
+	DescriptionContains string    `json:"descriptionContains,omitempty"` // 角色描述包含的文本（不区分大小写）
+	TenantKey           string    `json:"tenantKey"`                     // 租户键过滤条件
+	OrderBy             RoleOrder `json:"orderBy,omitempty"`             // 排序字段，为空时 ListRoles 按创建时间倒序、ListRolesPage 按角色键排序
+	Descending          bool      `json:"descending,omitempty"`          // 是否倒序（OrderBy 为空时忽略）
 }
 
+// RoleOrder 角色列表排序字段
+type RoleOrder string
+
+const (
+	RoleOrderKey       RoleOrder = "key"        // 按角色键
+	RoleOrderName      RoleOrder = "name"       // 按角色名
+	RoleOrderTenant    RoleOrder = "tenant"     // 按归属租户
+	RoleOrderCreatedAt RoleOrder = "created_at" // 按创建时间
+	RoleOrderUpdatedAt RoleOrder = "updated_at" // 按更新时间
+)
+
 // Role 角色结构体
 type Role struct {
 	Key         string       `json:"key"`         // 角色唯一标识符
@@ -16,6 +32,38 @@ type Role struct {
 	TenantKey   string       `json:"tenantKey"`   // 角色归属的租户键，空表示全局角色
 }
 
+// RoleLock 角色冻结信息
+type RoleLock struct {
+	RoleKey  string    `json:"roleKey"`  // 被冻结的角色标识
+	Reason   string    `json:"reason"`   // 冻结原因
+	LockedBy string    `json:"lockedBy"` // 执行冻结的操作者
+	LockedAt time.Time `json:"lockedAt"` // 冻结时间
+}
+
+// SharedRole 已发布的共享角色
+type SharedRole struct {
+	RoleKey         string    `json:"roleKey"`         // 共享角色标识
+	SourceTenantKey string    `json:"sourceTenantKey"` // 角色来源租户（角色权限定义所在的域）
+	PublishedBy     string    `json:"publishedBy"`     // 发布者
+	PublishedAt     time.Time `json:"publishedAt"`     // 发布时间
+	LinkedTenants   []string  `json:"linkedTenants"`   // 已链接该角色的租户列表
+}
+
+// RoleAssignment 批量分配中的单个角色
+type RoleAssignment struct {
+	RoleKey   string `json:"roleKey"`   // 角色标识
+	TenantKey string `json:"tenantKey"` // 分配租户
+}
+
+// RoleAssignmentResult 批量分配角色中单个分配的结果
+type RoleAssignmentResult struct {
+	UserKey   string `json:"userKey"`   // 用户标识
+	RoleKey   string `json:"roleKey"`   // 角色标识
+	TenantKey string `json:"tenantKey"` // 分配租户
+	Success   bool   `json:"success"`   // 是否分配成功
+	Error     string `json:"error"`     // 失败原因（成功时为空）
+}
+
 func (r *Role) GetKey() string  { return r.Key }  // GetKey 获取角色键
 func (r *Role) GetName() string { return r.Name } // GetName 获取角色名
 
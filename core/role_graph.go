@@ -0,0 +1,186 @@
+package core
+
+import (
+	"context"
+	"sort"
+)
+
+// RoleInheritance 角色继承关系：子角色在租户内拥有父角色的全部权限
+type RoleInheritance struct {
+	ParentRoleKey string `json:"parentRoleKey"` // 父角色（被继承的角色）
+	ChildRoleKey  string `json:"childRoleKey"`  // 子角色
+	TenantKey     string `json:"tenantKey"`     // 继承生效的租户，* 表示所有租户
+}
+
+// InheritedPermission 角色有效权限及其来源
+type InheritedPermission struct {
+	Permission
+	Domain     string   `json:"domain"`     // 授权所在的域
+	SourceRole string   `json:"sourceRole"` // 贡献该权限的角色（角色自身或祖先角色）
+	Path       []string `json:"path"`       // 从查询角色到来源角色的继承链，角色自身的权限只包含角色本身
+	Inherited  bool     `json:"inherited"`  // 是否来自祖先角色
+}
+
+// GetRoleEffectivePermissions 获取角色的有效权限（展开角色继承链）
+// 按继承链广度优先展开，同一权限在同一域由多个角色贡献时只保留距离最近的来源；忽略占位权限，继承环会被安全跳过
+func (e *Enforcer) GetRoleEffectivePermissions(roleKey string) ([]InheritedPermission, error) {
+	type node struct {
+		roleKey string
+		path    []string
+	}
+
+	var result []InheritedPermission
+	seenRoles := map[string]bool{roleKey: true}
+	seenPermissions := make(map[string]bool)
+	queue := []node{{roleKey: roleKey, path: []string{roleKey}}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		policies, err := e.GetPolicies(e.RoleSubject(current.roleKey), "")
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			if policy.Resource == ResourcePlaceholder && policy.Action == ActionNone {
+				continue
+			}
+			key := policy.Domain + "\x00" + string(policy.Resource) + "\x00" + string(policy.Action)
+			if seenPermissions[key] {
+				continue
+			}
+			seenPermissions[key] = true
+			result = append(result, InheritedPermission{
+				Permission: Permission{Resource: policy.Resource, Action: policy.Action},
+				Domain:     policy.Domain,
+				SourceRole: current.roleKey,
+				Path:       current.path,
+				Inherited:  current.roleKey != roleKey,
+			})
+		}
+
+		parents, err := e.parentRoles(current.roleKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if seenRoles[parent] {
+				continue
+			}
+			seenRoles[parent] = true
+			path := append(append([]string{}, current.path...), parent)
+			queue = append(queue, node{roleKey: parent, path: path})
+		}
+	}
+
+	return result, nil
+}
+
+// parentRoles 获取角色直接继承的父角色（任意域），按字典序排列
+func (e *Enforcer) parentRoles(roleKey string) ([]string, error) {
+	groupings, err := e.enforcer.GetFilteredGroupingPolicy(0, e.RoleSubject(roleKey))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var parents []string
+	for _, grouping := range groupings {
+		if len(grouping) < 2 {
+			continue
+		}
+		_, parent := e.decodeStoredSubject(grouping[1])
+		if !seen[parent] {
+			seen[parent] = true
+			parents = append(parents, parent)
+		}
+	}
+
+	sort.Strings(parents)
+	return parents, nil
+}
+
+// AddRoleInheritance 添加角色继承：childKey 在 domain 中继承 parentKey 的权限
+// 继承以角色之间的 g 策略存储；会形成继承环（包括角色继承自身）时返回 ErrRoleInheritanceCycle
+func (e *Enforcer) AddRoleInheritance(ctx context.Context, parentKey, childKey, domain string) error {
+	defer e.invalidateCache()
+	if err := e.checkDomainWrite(domain); err != nil {
+		return err
+	}
+
+	cyclic, err := e.inheritsFrom(parentKey, childKey)
+	if err != nil {
+		return err
+	}
+	if parentKey == childKey || cyclic {
+		return ErrRoleInheritanceCycle
+	}
+
+	rule := []string{e.RoleSubject(childKey), e.RoleSubject(parentKey), domain}
+	exists, err := e.enforcer.HasGroupingPolicy(rule)
+	if err != nil || exists {
+		return err
+	}
+	return e.insertRules(ctx, PolicyTypeGrouping, [][]string{rule})
+}
+
+// RemoveRoleInheritance 移除角色继承
+func (e *Enforcer) RemoveRoleInheritance(ctx context.Context, parentKey, childKey, domain string) error {
+	defer e.invalidateCache()
+	rule := []string{e.RoleSubject(childKey), e.RoleSubject(parentKey), domain}
+	return e.deleteRules(ctx, PolicyTypeGrouping, [][]string{rule})
+}
+
+// RemoveRoleParents 移除角色作为子角色的所有继承关系（所有域）
+func (e *Enforcer) RemoveRoleParents(ctx context.Context, roleKey string) error {
+	defer e.invalidateCache()
+	return e.deleteFilteredRules(ctx, PolicyTypeGrouping, 0, e.RoleSubject(roleKey))
+}
+
+// inheritsFrom 检查角色是否直接或间接继承祖先角色（任意域）
+func (e *Enforcer) inheritsFrom(roleKey, ancestorKey string) (bool, error) {
+	seen := map[string]bool{roleKey: true}
+	queue := []string{roleKey}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		parents, err := e.parentRoles(current)
+		if err != nil {
+			return false, err
+		}
+		for _, parent := range parents {
+			if parent == ancestorKey {
+				return true, nil
+			}
+			if !seen[parent] {
+				seen[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return false, nil
+}
+
+// expandInheritedRoles 展开角色在指定域中直接或间接继承的父角色
+// roles 和返回值均为存储中的角色主体，返回值包含输入的角色；继承环会被安全跳过
+func (e *Enforcer) expandInheritedRoles(roles []string, domains []string) []string {
+	seen := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		seen[role] = true
+	}
+
+	expanded := append([]string{}, roles...)
+	for i := 0; i < len(expanded); i++ {
+		for _, domain := range domains {
+			for _, parent := range e.enforcer.GetRolesForUserInDomain(expanded[i], domain) {
+				if !seen[parent] {
+					seen[parent] = true
+					expanded = append(expanded, parent)
+				}
+			}
+		}
+	}
+	return expanded
+}
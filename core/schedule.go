@@ -0,0 +1,65 @@
+package core
+
+import "time"
+
+// DefaultSchedulePollInterval 定时变更的默认检查间隔
+const DefaultSchedulePollInterval = 30 * time.Second
+
+// ScheduleConfig 定时权限变更配置
+type ScheduleConfig struct {
+	// Disabled 关闭本实例的定时变更执行，仍可创建、查询和取消定时变更（由其他实例执行）
+	Disabled bool `json:"disabled"`
+
+	// PollInterval 检查到期变更的间隔，默认 30s；变更最迟在生效时间后一个间隔内执行
+	PollInterval time.Duration `json:"pollInterval"`
+}
+
+// ScheduledChangeAction 定时变更的操作
+type ScheduledChangeAction string
+
+const (
+	ScheduledGrant  ScheduledChangeAction = "grant"  // 授予用户权限（GrantPermission）
+	ScheduledRevoke ScheduledChangeAction = "revoke" // 撤销用户权限（RevokePermission）
+)
+
+// ScheduledChangeStatus 定时变更状态
+type ScheduledChangeStatus string
+
+const (
+	ScheduledChangePending  ScheduledChangeStatus = "pending"  // 等待生效
+	ScheduledChangeApplying ScheduledChangeStatus = "applying" // 已被实例认领，执行中
+	ScheduledChangeApplied  ScheduledChangeStatus = "applied"  // 已执行
+	ScheduledChangeFailed   ScheduledChangeStatus = "failed"   // 执行失败（如操作者在生效时已失去授权能力）
+	ScheduledChangeCanceled ScheduledChangeStatus = "canceled" // 已取消
+)
+
+// ScheduledChange 定时执行的权限变更
+type ScheduledChange struct {
+	Action     ScheduledChangeAction `json:"action"`     // 授予或撤销
+	UserKey    string                `json:"userKey"`    // 被授权用户
+	TenantKey  string                `json:"tenantKey"`  // 授权所在租户
+	Permission Permission            `json:"permission"` // 权限
+}
+
+// ScheduledPermissionChange 已登记的定时权限变更
+// 到达生效时间后由调度器以登记时的操作者身份执行，执行时按同步方法的规则重新校验操作者权限
+type ScheduledPermissionChange struct {
+	ScheduledChange
+	ID           string                `json:"id"`                   // 定时变更ID
+	OperatorKey  string                `json:"operatorKey"`          // 登记变更的操作者
+	Reason       string                `json:"reason,omitempty"`     // 变更原因（core.WithReason），执行时写入审计日志
+	Environments []string              `json:"environments"`         // 授权生效的环境（core.WithEnvironments），为空表示所有环境
+	EffectiveAt  time.Time             `json:"effectiveAt"`          // 生效时间
+	Status       ScheduledChangeStatus `json:"status"`               // 状态
+	Error        string                `json:"error,omitempty"`      // 执行失败原因
+	CanceledBy   string                `json:"canceledBy,omitempty"` // 取消者
+	CreatedAt    time.Time             `json:"createdAt"`            // 登记时间
+	FinishedAt   time.Time             `json:"finishedAt,omitzero"`  // 执行、失败或取消的时间
+}
+
+// ScheduledChangeFilter 定时变更查询条件，空字段表示不限制
+type ScheduledChangeFilter struct {
+	TenantKey string                `json:"tenantKey"` // 租户
+	UserKey   string                `json:"userKey"`   // 被授权用户
+	Status    ScheduledChangeStatus `json:"status"`    // 状态
+}
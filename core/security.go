@@ -9,10 +9,19 @@ type PermissionChecker interface {
 	CheckPermission(userKey, tenantKey string, permission Permission) (bool, error)
 }
 
+// PermissionCheckerFunc 函数形式的权限检查器
+type PermissionCheckerFunc func(userKey, tenantKey string, permission Permission) (bool, error)
+
+// CheckPermission 检查权限
+func (f PermissionCheckerFunc) CheckPermission(userKey, tenantKey string, permission Permission) (bool, error) {
+	return f(userKey, tenantKey, permission)
+}
+
 // SecurityValidator 安全验证器
 type SecurityValidator struct {
 	config            SecurityConfig
 	permissionChecker PermissionChecker
+	actionValidator   ActionValidator // 自定义操作校验器（可选）
 }
 
 // NewSecurityValidator 创建安全验证器
@@ -28,6 +37,11 @@ func (sv *SecurityValidator) SetPermissionChecker(checker PermissionChecker) {
 	sv.permissionChecker = checker
 }
 
+// SetActionValidator 设置自定义操作校验器，未设置时只允许授予内置操作
+func (sv *SecurityValidator) SetActionValidator(validator ActionValidator) {
+	sv.actionValidator = validator
+}
+
 // ValidatePermissionGrant 验证权限授予操作
 func (sv *SecurityValidator) ValidatePermissionGrant(operatorKey, targetUserKey, tenantKey string, permission Permission) error {
 	// 1. 防止自我提权检查（优先检查，覆盖所有其他检查）
@@ -35,12 +49,22 @@ func (sv *SecurityValidator) ValidatePermissionGrant(operatorKey, targetUserKey,
 		return err
 	}
 
-	// 2. 检查是否为系统权限
+	// 2. 通配授权只能由全局权限管理者授予
+	if err := sv.validateWildcardPermission(operatorKey, permission); err != nil {
+		return err
+	}
+
+	// 3. 检查是否为系统权限
 	if sv.isSystemPermission(permission) {
 		return ErrSystemPermissionImmutable
 	}
 
-	// 3. 验证操作者权限 - 使用正确的租户域进行权限验证
+	// 4. 检查操作是否已在租户的资源上定义
+	if err := sv.validateAction(tenantKey, permission); err != nil {
+		return err
+	}
+
+	// 5. 验证操作者权限 - 使用正确的租户域进行权限验证
 	if err := sv.validateOperatorPermission(operatorKey, tenantKey, permission); err != nil {
 		return err
 	}
@@ -55,12 +79,22 @@ func (sv *SecurityValidator) ValidatePermissionGrantWithDomain(operatorKey, targ
 		return err
 	}
 
-	// 2. 检查是否为系统权限
+	// 2. 通配授权只能由全局权限管理者授予
+	if err := sv.validateWildcardPermission(operatorKey, permission); err != nil {
+		return err
+	}
+
+	// 3. 检查是否为系统权限
 	if sv.isSystemPermission(permission) {
 		return ErrSystemPermissionImmutable
 	}
 
-	// 3. 验证操作者权限
+	// 4. 检查操作是否已在租户的资源上定义
+	if err := sv.validateAction(operatorDomain, permission); err != nil {
+		return err
+	}
+
+	// 5. 验证操作者权限
 	if err := sv.validateOperatorPermission(operatorKey, operatorDomain, permission); err != nil {
 		return err
 	}
@@ -75,12 +109,17 @@ func (sv *SecurityValidator) ValidatePermissionRevoke(operatorKey, targetUserKey
 		return err
 	}
 
-	// 2. 检查是否为系统权限
+	// 2. 通配授权只能由全局权限管理者撤销
+	if err := sv.validateWildcardPermission(operatorKey, permission); err != nil {
+		return err
+	}
+
+	// 3. 检查是否为系统权限
 	if sv.isSystemPermission(permission) {
 		return ErrSystemPermissionImmutable
 	}
 
-	// 3. 验证操作者权限 - 使用正确的租户域进行权限验证
+	// 4. 验证操作者权限 - 使用正确的租户域进行权限验证
 	if err := sv.validateOperatorPermission(operatorKey, tenantKey, permission); err != nil {
 		return err
 	}
@@ -88,11 +127,89 @@ func (sv *SecurityValidator) ValidatePermissionRevoke(operatorKey, targetUserKey
 	return nil
 }
 
-// isSystemPermission 检查是否为系统权限
+// ValidateRoleAssignment 验证角色分配操作
+// 包含通配授权的角色（超级角色）只能由全局权限管理者分配；
+// 启用 RestrictRoleAssignmentToOwnPermissions 时，要求角色的每个权限操作者在该租户内都拥有
+func (sv *SecurityValidator) ValidateRoleAssignment(operatorKey, tenantKey string, rolePermissions []Permission) error {
+	for _, permission := range rolePermissions {
+		if permission.IsWildcard() {
+			if err := sv.validateWildcardPermission(operatorKey, permission); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	if !sv.config.RestrictRoleAssignmentToOwnPermissions || sv.permissionChecker == nil {
+		return nil
+	}
+
+	for _, permission := range NormalizePermissions(rolePermissions) {
+		// 跳过角色占位权限
+		if permission.Resource == ResourcePlaceholder {
+			continue
+		}
+
+		hasPermission, err := sv.permissionChecker.CheckPermission(operatorKey, tenantKey, permission)
+		if err != nil {
+			return fmt.Errorf("检查操作者权限时出错: %w", err)
+		}
+		if !hasPermission {
+			return fmt.Errorf("%w: %s", ErrRoleExceedsOperatorPermissions, permission)
+		}
+	}
+
+	return nil
+}
+
+// validateWildcardPermission 检查通配授权（资源或操作为 *）的操作者：通配授权可能覆盖系统资源和所有租户资源，
+// 要求操作者在全局域拥有 permission:write；未设置权限检查器时无法确认操作者，一律拒绝
+func (sv *SecurityValidator) validateWildcardPermission(operatorKey string, permission Permission) error {
+	if !permission.IsWildcard() {
+		return nil
+	}
+	if permission.Resource == ResourceAll && permission.ObjectID != "" {
+		return &FieldError{Field: "permission.objectId", Constraint: ConstraintFormat, Detail: "资源为 * 时不能指定对象ID"}
+	}
+	if sv.permissionChecker == nil {
+		return fmt.Errorf("%w: %s", ErrWildcardPermissionRestricted, permission)
+	}
+
+	hasPermission, err := sv.permissionChecker.CheckPermission(operatorKey, "*", Permission{Resource: ResourcePermission, Action: ActionWrite})
+	if err != nil {
+		return fmt.Errorf("检查操作者权限时出错: %w", err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("%w: 操作者 %s 无法操作 %s", ErrWildcardPermissionRestricted, operatorKey, permission)
+	}
+	return nil
+}
+
+// validateAction 检查授予的操作：内置操作总是允许，其他操作须在租户（或全局）的资源上定义
+func (sv *SecurityValidator) validateAction(tenantKey string, permission Permission) error {
+	if IsBuiltinAction(permission.Action) {
+		return nil
+	}
+	if sv.actionValidator == nil || ValidateCustomAction(permission.Action) != nil {
+		return fmt.Errorf("%w: %s", ErrResourceActionNotDefined, permission)
+	}
+	return sv.actionValidator(tenantKey, permission)
+}
+
+// isSystemPermission 检查是否为系统权限：与系统权限一致，或通配的资源、操作覆盖任一系统权限（如 *:*、tenant:*、*:read）
 func (sv *SecurityValidator) isSystemPermission(permission Permission) bool {
+	var resources []Resource
+	var actions []Action
 	for _, sysPerm := range sv.config.SystemPermissions {
-		if permission.Resource == sysPerm.Resource && permission.Action == sysPerm.Action {
-			return true
+		resources = append(resources, sysPerm.Resource)
+		actions = append(actions, sysPerm.Action)
+	}
+
+	for _, concrete := range expandWildcard(permission, resources, actions) {
+		for _, sysPerm := range sv.config.SystemPermissions {
+			if concrete.Resource == sysPerm.Resource && concrete.Action == sysPerm.Action {
+				return true
+			}
 		}
 	}
 	return false
@@ -135,6 +252,11 @@ func (sv *SecurityValidator) UpdateSecurityConfig(config SecurityConfig) error {
 	return nil
 }
 
+// AllowsSelfQuery 用户能否不经权限检查查询自己在租户内的权限信息
+func (sv *SecurityValidator) AllowsSelfQuery(userKey, tenantKey string) bool {
+	return sv.config.SelfQuery.Allowed(userKey, tenantKey)
+}
+
 // ValidateSecurityConfig 验证安全配置的有效性
 func ValidateSecurityConfig(config SecurityConfig) error {
 
@@ -172,17 +294,17 @@ func (sv *SecurityValidator) PreventSelfElevation(operatorKey, targetKey string,
 // isManagementPermission 检查是否为管理权限
 func (sv *SecurityValidator) isManagementPermission(permission Permission) bool {
 	// 权限管理权限
-	if permission.Resource == ResourcePermission && (permission.Action == ActionWrite || permission.Action == ActionDelete) {
+	if permission.Resource == ResourcePermission && (IsWriteAction(permission.Action) || permission.Action == ActionDelete) {
 		return true
 	}
 
 	// 用户管理权限
-	if permission.Resource == ResourceUser && (permission.Action == ActionWrite || permission.Action == ActionDelete) {
+	if permission.Resource == ResourceUser && (IsWriteAction(permission.Action) || permission.Action == ActionDelete) {
 		return true
 	}
 
-	// 角色管理权限
-	if permission.Resource == ResourceRole && (permission.Action == ActionWrite || permission.Action == ActionDelete) {
+	// 角色管理权限（含角色分配权限）
+	if permission.Resource == ResourceRole && (IsWriteAction(permission.Action) || permission.Action == ActionDelete || permission.Action == ActionAssign) {
 		return true
 	}
 
@@ -191,9 +313,53 @@ func (sv *SecurityValidator) isManagementPermission(permission Permission) bool
 		return true
 	}
 
+	// 通配授权覆盖任一管理权限时同样视为管理权限
+	if permission.IsWildcard() {
+		return sv.coversManagementPermission(permission)
+	}
+
 	return false
 }
 
+// coversManagementPermission 将通配的资源或操作展开为具体的管理资源和操作，检查其中是否有管理权限
+func (sv *SecurityValidator) coversManagementPermission(permission Permission) bool {
+	resources := []Resource{ResourcePermission, ResourceUser, ResourceRole}
+	actions := []Action{ActionRead, ActionWrite, ActionDelete, ActionAssign}
+	for _, sysPerm := range sv.config.SystemPermissions {
+		resources = append(resources, sysPerm.Resource)
+		actions = append(actions, sysPerm.Action)
+	}
+
+	for _, concrete := range expandWildcard(permission, resources, actions) {
+		if sv.isManagementPermission(concrete) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandWildcard 将通配的资源或操作分别展开为候选的资源和操作，非通配的部分保持不变；
+// 只返回具体（非通配）的权限，对象ID不参与展开
+func expandWildcard(permission Permission, resources []Resource, actions []Action) []Permission {
+	if permission.Resource != ResourceAll {
+		resources = []Resource{permission.Resource}
+	}
+	if permission.Action != ActionAll {
+		actions = []Action{permission.Action}
+	}
+
+	expanded := make([]Permission, 0, len(resources)*len(actions))
+	for _, resource := range resources {
+		for _, action := range actions {
+			concrete := Permission{Resource: resource, Action: action}
+			if !concrete.IsWildcard() {
+				expanded = append(expanded, concrete)
+			}
+		}
+	}
+	return expanded
+}
+
 // validateOperatorPermission 验证操作者是否有权限执行指定操作
 func (sv *SecurityValidator) validateOperatorPermission(operatorKey, domain string, permission Permission) error {
 	if sv.permissionChecker == nil {
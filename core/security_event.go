@@ -0,0 +1,31 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// SecurityEventClass 敏感事件类别
+type SecurityEventClass string
+
+// 敏感事件类别
+const (
+	SecurityEventSystemRole SecurityEventClass = "system_role" // 系统角色变更：含系统权限的角色被创建、修改、删除，或租户管理员角色被分配
+	SecurityEventLockdown   SecurityEventClass = "lockdown"    // 租户封锁与解除
+	SecurityEventBreakGlass SecurityEventClass = "break_glass" // 应急提权：引擎不会产生，由应用的应急流程自行上报
+)
+
+// SecurityEvent 敏感事件，由引擎在相关操作成功后发出
+type SecurityEvent struct {
+	Class       SecurityEventClass `json:"class"`       // 事件类别
+	Action      string             `json:"action"`      // 具体操作，如 create、update、delete、assign、set、clear
+	OperatorKey string             `json:"operatorKey"` // 操作者（接口未提供操作者时为空）
+	TenantKey   string             `json:"tenantKey"`   // 租户标识
+	Target      string             `json:"target"`      // 操作对象，如角色标识
+	Detail      string             `json:"detail"`      // 补充说明，如变更的系统权限、封锁放行的角色
+	Time        time.Time          `json:"time"`        // 发生时间
+}
+
+// SecurityEventHook 敏感事件回调
+// 引擎在后台协程中调用，不阻塞触发事件的操作；返回的错误只记录日志
+type SecurityEventHook func(ctx context.Context, event SecurityEvent) error
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+// staticChecker 按 "用户|域|权限" 返回固定结果的权限检查器
+type staticChecker struct {
+	granted map[string]bool
+	err     error
+}
+
+func (c staticChecker) CheckPermission(userKey, tenantKey string, permission Permission) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	return c.granted[userKey+"|"+tenantKey+"|"+permission.String()], nil
+}
+
+func TestValidateWildcardPermission(t *testing.T) {
+	globalWriter := staticChecker{granted: map[string]bool{
+		"root|*|" + Permission{Resource: ResourcePermission, Action: ActionWrite}.String(): true,
+	}}
+	tenantWriter := staticChecker{granted: map[string]bool{
+		"root|tenant1|" + Permission{Resource: ResourcePermission, Action: ActionWrite}.String(): true,
+	}}
+	checkErr := errors.New("store unavailable")
+
+	tests := []struct {
+		name       string
+		checker    PermissionChecker
+		permission Permission
+		wantErr    error
+	}{
+		{name: "非通配授权不检查", checker: nil, permission: userRead},
+		{name: "对象通配不属于通配授权", checker: nil, permission: Permission{Resource: "document", ObjectID: "*", Action: ActionRead}},
+		{name: "全局 permission:write 可以操作资源通配", checker: globalWriter, permission: Permission{Resource: ResourceAll, Action: ActionRead}},
+		{name: "全局 permission:write 可以操作操作通配", checker: globalWriter, permission: Permission{Resource: ResourceUser, Action: ActionAll}},
+		{name: "只有租户内 permission:write 时拒绝", checker: tenantWriter, permission: Permission{Resource: ResourceAll, Action: ActionAll}, wantErr: ErrWildcardPermissionRestricted},
+		{name: "没有 permission:write 时拒绝", checker: staticChecker{}, permission: Permission{Resource: ResourceUser, Action: ActionAll}, wantErr: ErrWildcardPermissionRestricted},
+		{name: "未设置检查器时拒绝", checker: nil, permission: Permission{Resource: ResourceAll, Action: ActionRead}, wantErr: ErrWildcardPermissionRestricted},
+		{name: "资源通配不能指定对象ID", checker: globalWriter, permission: Permission{Resource: ResourceAll, ObjectID: "42", Action: ActionRead}, wantErr: ErrInvalidParameter},
+		{name: "检查出错时返回错误", checker: staticChecker{err: checkErr}, permission: Permission{Resource: ResourceAll, Action: ActionRead}, wantErr: checkErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := NewSecurityValidator(SecurityConfig{})
+			if tt.checker != nil {
+				sv.SetPermissionChecker(tt.checker)
+			}
+
+			err := sv.validateWildcardPermission("root", tt.permission)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("validateWildcardPermission() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateWildcardPermission() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRoleAssignmentWildcard(t *testing.T) {
+	globalWriter := staticChecker{granted: map[string]bool{
+		"root|*|" + Permission{Resource: ResourcePermission, Action: ActionWrite}.String(): true,
+	}}
+
+	tests := []struct {
+		name        string
+		checker     PermissionChecker
+		permissions []Permission
+		wantErr     error
+	}{
+		{name: "普通角色", checker: staticChecker{}, permissions: []Permission{userRead, userWrite}},
+		{name: "超级角色由全局 permission:write 分配", checker: globalWriter, permissions: []Permission{userRead, {Resource: ResourceAll, Action: ActionAll}}},
+		{name: "超级角色没有全局 permission:write 时拒绝", checker: staticChecker{}, permissions: []Permission{userRead, {Resource: ResourceAll, Action: ActionAll}}, wantErr: ErrWildcardPermissionRestricted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := NewSecurityValidator(SecurityConfig{})
+			sv.SetPermissionChecker(tt.checker)
+
+			err := sv.ValidateRoleAssignment("root", "tenant1", tt.permissions)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ValidateRoleAssignment() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateRoleAssignment() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPermissionTypeWildcard(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission Permission
+		want       PermissionType
+	}{
+		{name: "系统权限", permission: Permission{Resource: "tenant", Action: ActionWrite}, want: PermissionTypeSystem},
+		{name: "全通配覆盖系统权限", permission: Permission{Resource: ResourceAll, Action: ActionAll}, want: PermissionTypeSystem},
+		{name: "操作通配覆盖系统权限", permission: Permission{Resource: "tenant", Action: ActionAll}, want: PermissionTypeSystem},
+		{name: "资源通配覆盖系统权限", permission: Permission{Resource: ResourceAll, Action: ActionRead}, want: PermissionTypeSystem},
+		{name: "普通权限", permission: userRead, want: PermissionTypeNormal},
+		{name: "操作通配不覆盖系统权限", permission: Permission{Resource: ResourceUser, Action: ActionAll}, want: PermissionTypeNormal},
+		{name: "资源通配不覆盖系统权限", permission: Permission{Resource: ResourceAll, Action: ActionAssign}, want: PermissionTypeNormal},
+	}
+
+	sv := NewSecurityValidator(SecurityConfig{SystemPermissions: []Permission{
+		{Resource: "tenant", Action: ActionWrite},
+		{Resource: "tenant", Action: ActionRead},
+		{Resource: "system", Action: ActionDelete},
+	}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sv.GetPermissionType(tt.permission); got != tt.want {
+				t.Fatalf("GetPermissionType(%s) = %v, want %v", tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePermissionGrantWildcardSystem(t *testing.T) {
+	sv := NewSecurityValidator(SecurityConfig{SystemPermissions: []Permission{{Resource: "tenant", Action: ActionRead}}})
+	sv.SetPermissionChecker(staticChecker{granted: map[string]bool{
+		"root|*|" + Permission{Resource: ResourcePermission, Action: ActionWrite}.String(): true,
+	}})
+
+	for _, permission := range []Permission{
+		{Resource: ResourceAll, Action: ActionAll},
+		{Resource: "tenant", Action: ActionAll},
+		{Resource: ResourceAll, Action: ActionRead},
+	} {
+		if err := sv.ValidatePermissionGrant("root", "alice", "tenant1", permission); !errors.Is(err, ErrSystemPermissionImmutable) {
+			t.Fatalf("ValidatePermissionGrant(%s) error = %v, want %v", permission, err, ErrSystemPermissionImmutable)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import "sort"
+
+// RuleChange 模拟中的一条策略变更
+type RuleChange struct {
+	Subject    string     // 主体键（用户键或角色键）
+	Role       bool       // 主体是否为角色
+	Domain     string     // 策略所在的域
+	Permission Permission // 变更的权限
+	Remove     bool       // true 表示移除，false 表示新增
+}
+
+// PermissionDiff 一个用户在租户内有效授权的变化
+type PermissionDiff struct {
+	UserKey   string       `json:"userKey"`   // 用户标识
+	TenantKey string       `json:"tenantKey"` // 租户标识
+	Gained    []Permission `json:"gained"`    // 变更后新增的授权
+	Lost      []Permission `json:"lost"`      // 变更后失去的授权（同一授权仍有其他来源时不计入）
+}
+
+// PermissionSimulation 权限变更的模拟结果，变更不会写入
+type PermissionSimulation struct {
+	Added     []Permission     `json:"added"`     // 模拟新增的权限
+	Removed   []Permission     `json:"removed"`   // 模拟移除的权限
+	Evaluated int              `json:"evaluated"` // 评估的用户-租户组合数
+	Diffs     []PermissionDiff `json:"diffs"`     // 有效授权发生变化的用户，按租户、用户排序
+}
+
+// SortDiffs 按租户、用户排序
+func (s *PermissionSimulation) SortDiffs() {
+	sort.Slice(s.Diffs, func(i, j int) bool {
+		if s.Diffs[i].TenantKey != s.Diffs[j].TenantKey {
+			return s.Diffs[i].TenantKey < s.Diffs[j].TenantKey
+		}
+		return s.Diffs[i].UserKey < s.Diffs[j].UserKey
+	})
+}
+
+// SimulateRuleChanges 计算一组策略变更对用户在域内有效授权的影响，不写入策略
+// 用户规则在指定域（含别名）中生效；角色规则只在用户于该域通过分配或继承拥有该角色、且规则位于角色权限所在的域时生效。
+// 比较的是授权集合本身（不展开通配符和写操作隐含关系），附加条件的规则不计入
+func (e *Enforcer) SimulateRuleChanges(userKey, domain string, changes []RuleChange) (gained, lost []Permission, err error) {
+	rules, err := e.implicitRules(userKey, domain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 按来源记录规则，同一授权可能来自多个主体或域
+	type ruleKey struct {
+		subject    string
+		domain     string
+		permission Permission
+	}
+	before := make(map[ruleKey]bool, len(rules))
+	for _, rule := range rules {
+		before[ruleKey{subject: rule.Subject, domain: rule.Domain, permission: rule.Permission()}] = true
+	}
+	after := make(map[ruleKey]bool, len(before)+len(changes))
+	for key := range before {
+		after[key] = true
+	}
+
+	userDomains := make(map[string]bool)
+	for _, equivalent := range e.equivalentDomains(domain) {
+		userDomains[equivalent] = true
+	}
+	roleDomains := make(map[string]map[string]bool)
+	for _, role := range e.userRoles(e.UserSubject(userKey), domain, e.equivalentDomains(domain)) {
+		roleDomains[role.key] = make(map[string]bool, len(role.ruleDomains))
+		for _, ruleDomain := range role.ruleDomains {
+			roleDomains[role.key][ruleDomain] = true
+		}
+	}
+
+	for _, change := range changes {
+		key := ruleKey{subject: change.Subject, domain: change.Domain, permission: Permission{Resource: change.Permission.QualifiedResource(), Action: change.Permission.Action}}
+		if change.Remove {
+			delete(after, key)
+			continue
+		}
+
+		applies := change.Subject == userKey && userDomains[change.Domain]
+		subject := e.UserSubject(change.Subject)
+		if change.Role {
+			applies = roleDomains[change.Subject][change.Domain]
+			subject = e.RoleSubject(change.Subject)
+		}
+		if !applies {
+			continue
+		}
+		rule, err := e.toPolicy([]string{subject, change.Domain, string(key.permission.Resource), string(key.permission.Action)})
+		if err != nil {
+			return nil, nil, err
+		}
+		if e.ruleApplies(rule) {
+			after[key] = true
+		}
+	}
+
+	permissionsOf := func(rules map[ruleKey]bool) map[Permission]bool {
+		permissions := make(map[Permission]bool, len(rules))
+		for key := range rules {
+			if key.permission.Resource != ResourcePlaceholder {
+				permissions[key.permission] = true
+			}
+		}
+		return permissions
+	}
+	beforePermissions, afterPermissions := permissionsOf(before), permissionsOf(after)
+	for permission := range afterPermissions {
+		if !beforePermissions[permission] {
+			gained = append(gained, permission)
+		}
+	}
+	for permission := range beforePermissions {
+		if !afterPermissions[permission] {
+			lost = append(lost, permission)
+		}
+	}
+
+	sortPermissions(gained)
+	sortPermissions(lost)
+	return gained, lost, nil
+}
+
+// sortPermissions 按权限字符串排序
+func sortPermissions(permissions []Permission) {
+	sort.Slice(permissions, func(i, j int) bool {
+		return permissions[i].String() < permissions[j].String()
+	})
+}
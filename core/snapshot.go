@@ -0,0 +1,116 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// PolicySnapshotVersion 当前的策略快照格式版本
+const PolicySnapshotVersion = 1
+
+// PolicySnapshot 租户的策略快照，用于备份恢复、跨环境迁移和克隆租户
+// 快照只包含 json 标签，需要 YAML 时可使用兼容 json 标签的库（如 sigs.k8s.io/yaml）转换
+type PolicySnapshot struct {
+	Version    int                `json:"version"`    // 快照格式版本
+	TenantKey  string             `json:"tenantKey"`  // 导出的租户
+	ExportedAt time.Time          `json:"exportedAt"` // 导出时间
+	Roles      []SnapshotRole     `json:"roles"`      // 归属租户的角色（含角色权限）
+	Policies   []SnapshotPolicy   `json:"policies"`   // 租户内的主体直接权限（角色权限随角色导出）
+	Groupings  []SnapshotGrouping `json:"groupings"`  // 租户内的角色分配和角色继承
+}
+
+// SnapshotRole 快照中的角色
+type SnapshotRole struct {
+	Key         string       `json:"key"`         // 角色键
+	Name        string       `json:"name"`        // 角色显示名称
+	Description string       `json:"description"` // 角色描述
+	Permissions []Permission `json:"permissions"` // 角色权限（不含占位权限）
+}
+
+// SnapshotPolicy 快照中的直接权限
+type SnapshotPolicy struct {
+	Subject     string      `json:"subject"`               // 主体键
+	SubjectKind SubjectKind `json:"subjectKind,omitempty"` // 主体类型，仅在启用主体命名空间时填充
+	Resource    Resource    `json:"resource"`              // 资源（对象级权限为 "资源:对象ID"）
+	Action      Action      `json:"action"`                // 操作
+}
+
+// Permission 返回策略授予的权限
+func (p SnapshotPolicy) Permission() Permission {
+	return Permission{Resource: p.Resource, Action: p.Action}
+}
+
+// SnapshotGrouping 快照中的角色分配，主体为角色时表示角色继承（主体为子角色）
+type SnapshotGrouping struct {
+	Subject     string      `json:"subject"`     // 用户键或子角色键
+	SubjectKind SubjectKind `json:"subjectKind"` // user 或 role
+	RoleKey     string      `json:"roleKey"`     // 分配的角色或父角色
+}
+
+// RenameTenant 返回导入到另一个租户时的快照副本
+// 角色键、角色名称和描述中的源租户键替换为目标租户键（角色键全局唯一，约定包含租户键），引用角色的权限和分配随之改写
+func (s PolicySnapshot) RenameTenant(tenantKey string) PolicySnapshot {
+	if s.TenantKey == "" || tenantKey == s.TenantKey {
+		return s
+	}
+
+	rename := func(value string) string {
+		return strings.ReplaceAll(value, s.TenantKey, tenantKey)
+	}
+	roleKeys := make(map[string]string, len(s.Roles))
+	renamed := PolicySnapshot{Version: s.Version, TenantKey: tenantKey, ExportedAt: s.ExportedAt}
+	for _, role := range s.Roles {
+		roleKeys[role.Key] = rename(role.Key)
+		renamed.Roles = append(renamed.Roles, SnapshotRole{
+			Key:         rename(role.Key),
+			Name:        rename(role.Name),
+			Description: rename(role.Description),
+			Permissions: append([]Permission(nil), role.Permissions...),
+		})
+	}
+	roleKey := func(key string) string {
+		if mapped, ok := roleKeys[key]; ok {
+			return mapped
+		}
+		return key
+	}
+	renamed.Policies = append(renamed.Policies, s.Policies...)
+	for _, grouping := range s.Groupings {
+		if grouping.SubjectKind == SubjectKindRole {
+			grouping.Subject = roleKey(grouping.Subject)
+		}
+		grouping.RoleKey = roleKey(grouping.RoleKey)
+		renamed.Groupings = append(renamed.Groupings, grouping)
+	}
+	return renamed
+}
+
+// PolicyImportMode 策略快照的导入方式
+type PolicyImportMode string
+
+const (
+	PolicyImportMerge   PolicyImportMode = "merge"   // 合并：创建或更新快照中的角色，补充缺少的权限和分配，保留租户中的其他数据
+	PolicyImportReplace PolicyImportMode = "replace" // 替换：导入后租户内的角色、直接权限和分配与快照完全一致
+)
+
+// PolicyImportOptions 策略快照导入选项
+type PolicyImportOptions struct {
+	Mode      PolicyImportMode `json:"mode"`      // 导入方式，默认 merge
+	TenantKey string           `json:"tenantKey"` // 目标租户，为空时导入到快照的租户（不同时按 PolicySnapshot.RenameTenant 改写角色键）
+	DryRun    bool             `json:"dryRun"`    // 只校验并计算变更，不写入
+}
+
+// PolicyImportResult 策略快照导入结果（DryRun 时为将要执行的变更）
+type PolicyImportResult struct {
+	TenantKey        string             `json:"tenantKey"`        // 目标租户
+	Mode             PolicyImportMode   `json:"mode"`             // 导入方式
+	DryRun           bool               `json:"dryRun"`           // 是否为预演
+	Issues           []string           `json:"issues"`           // 校验问题，存在时不执行导入
+	CreatedRoles     []string           `json:"createdRoles"`     // 新建的角色
+	UpdatedRoles     []string           `json:"updatedRoles"`     // 名称、描述或权限有变化的角色
+	DeletedRoles     []string           `json:"deletedRoles"`     // 删除的角色（仅 replace）
+	AddedPolicies    []SnapshotPolicy   `json:"addedPolicies"`    // 新增的直接权限
+	RemovedPolicies  []SnapshotPolicy   `json:"removedPolicies"`  // 移除的直接权限（仅 replace）
+	AddedGroupings   []SnapshotGrouping `json:"addedGroupings"`   // 新增的角色分配和继承
+	RemovedGroupings []SnapshotGrouping `json:"removedGroupings"` // 移除的角色分配和继承（仅 replace）
+}
@@ -0,0 +1,215 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SubjectKind 主体类型
+type SubjectKind string
+
+const (
+	SubjectKindUser    SubjectKind = "user"    // 用户
+	SubjectKindRole    SubjectKind = "role"    // 角色
+	SubjectKindGroup   SubjectKind = "group"   // 用户组
+	SubjectKindService SubjectKind = "service" // 服务账号
+)
+
+// subjectSeparator 主体类型与主体键之间的分隔符
+const subjectSeparator = ":"
+
+// subjectKinds 所有已知的主体类型
+var subjectKinds = []SubjectKind{SubjectKindUser, SubjectKindRole, SubjectKindGroup, SubjectKindService}
+
+// EncodeSubject 生成带命名空间的主体标识，例如 "user:admin"、"role:admin"
+func EncodeSubject(kind SubjectKind, key string) string {
+	return string(kind) + subjectSeparator + key
+}
+
+// DecodeSubject 解析带命名空间的主体标识
+// 不带已知类型前缀时返回 ok=false
+func DecodeSubject(subject string) (kind SubjectKind, key string, ok bool) {
+	for _, candidate := range subjectKinds {
+		prefix := string(candidate) + subjectSeparator
+		if strings.HasPrefix(subject, prefix) {
+			return candidate, strings.TrimPrefix(subject, prefix), true
+		}
+	}
+	return "", subject, false
+}
+
+// SubjectMigrationReport 主体命名空间迁移结果
+type SubjectMigrationReport struct {
+	MigratedPolicies  int      `json:"migratedPolicies"`  // 改写的权限策略数量
+	MigratedGroupings int      `json:"migratedGroupings"` // 改写的角色分配数量
+	Ambiguous         []string `json:"ambiguous"`         // 同时作为用户和角色出现的主体（按角色迁移权限策略，需人工核对）
+}
+
+// === 主体命名空间 ===
+
+// EnableSubjectNamespaces 启用或关闭主体命名空间
+// 启用后用户、角色在策略存储中分别以 "user:"、"role:" 前缀保存，同名的用户和角色不会再互相冲突
+func (e *Enforcer) EnableSubjectNamespaces(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.subjectNamespaces = enabled
+}
+
+// SubjectNamespacesEnabled 是否启用了主体命名空间
+func (e *Enforcer) SubjectNamespacesEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.subjectNamespaces
+}
+
+// UserSubject 返回用户在策略存储中的主体标识
+func (e *Enforcer) UserSubject(userKey string) string {
+	return e.storedSubject(SubjectKindUser, userKey)
+}
+
+// RoleSubject 返回角色在策略存储中的主体标识
+func (e *Enforcer) RoleSubject(roleKey string) string {
+	return e.storedSubject(SubjectKindRole, roleKey)
+}
+
+// storedSubject 按命名空间设置生成存储用的主体标识，未启用命名空间时原样返回
+func (e *Enforcer) storedSubject(kind SubjectKind, key string) string {
+	if key == "" || !e.SubjectNamespacesEnabled() {
+		return key
+	}
+	return EncodeSubject(kind, key)
+}
+
+// decodeStoredSubject 将存储中的主体标识还原为主体类型和主体键
+// 未启用命名空间或主体不带前缀时类型为空
+func (e *Enforcer) decodeStoredSubject(subject string) (SubjectKind, string) {
+	if !e.SubjectNamespacesEnabled() {
+		return "", subject
+	}
+	kind, key, ok := DecodeSubject(subject)
+	if !ok {
+		return "", subject
+	}
+	return kind, key
+}
+
+// toPolicy 将存储中的权限策略转换为 Policy，主体标识还原为不带前缀的键并填充优先级
+func (e *Enforcer) toPolicy(rule []string) (Policy, error) {
+	// 自定义操作在授权时已校验，这里只要求名称合法
+	action, err := ParseAction(rule[3])
+	if err != nil && ValidateCustomAction(action) != nil {
+		return Policy{}, err
+	}
+	kind, subject := e.decodeStoredSubject(rule[0])
+	policy := Policy{
+		Type:        PolicyTypePermission,
+		Subject:     subject,
+		SubjectKind: kind,
+		Domain:      rule[1],
+		Resource:    Resource(rule[2]),
+		Action:      action,
+	}
+	policy.Priority = e.rulePriority(policy)
+	policy.Condition = e.ruleCondition(policy)
+	return policy, nil
+}
+
+// MigrateSubjectNamespaces 将未带命名空间前缀的存量策略改写为带前缀的格式
+// isRole 判断一个主体键是否为角色；角色分配中的第二列总是按角色处理。
+// 同一个键既被判定为角色、又作为用户出现在角色分配第一列时，记录到 Ambiguous 供人工核对。
+func (e *Enforcer) MigrateSubjectNamespaces(ctx context.Context, isRole func(key string) (bool, error)) (*SubjectMigrationReport, error) {
+	defer e.invalidateCache()
+	report := &SubjectMigrationReport{}
+
+	allGroupings, err := e.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return nil, err
+	}
+	groupedUsers := make(map[string]bool)
+	assignedRoles := make(map[string]bool)
+	var oldGroupings, newGroupings [][]string
+	for _, grouping := range allGroupings {
+		if len(grouping) < 3 {
+			continue
+		}
+		_, _, userPrefixed := DecodeSubject(grouping[0])
+		_, _, rolePrefixed := DecodeSubject(grouping[1])
+		if !userPrefixed {
+			groupedUsers[grouping[0]] = true
+		}
+		if !rolePrefixed {
+			assignedRoles[grouping[1]] = true
+		}
+		if userPrefixed && rolePrefixed {
+			continue
+		}
+		migrated := append([]string{}, grouping...)
+		if !userPrefixed {
+			migrated[0] = EncodeSubject(SubjectKindUser, grouping[0])
+		}
+		if !rolePrefixed {
+			migrated[1] = EncodeSubject(SubjectKindRole, grouping[1])
+		}
+		oldGroupings = append(oldGroupings, grouping)
+		newGroupings = append(newGroupings, migrated)
+	}
+
+	allPolicies, err := e.enforcer.GetPolicy()
+	if err != nil {
+		return nil, err
+	}
+	subjectIsRole := make(map[string]bool)
+	var oldPolicies, newPolicies [][]string
+	for _, policy := range allPolicies {
+		if len(policy) < 4 {
+			continue
+		}
+		if _, _, prefixed := DecodeSubject(policy[0]); prefixed {
+			continue
+		}
+
+		role, checked := subjectIsRole[policy[0]]
+		if !checked {
+			role = assignedRoles[policy[0]]
+			if !role {
+				if role, err = isRole(policy[0]); err != nil {
+					return nil, err
+				}
+			}
+			subjectIsRole[policy[0]] = role
+			if role && groupedUsers[policy[0]] {
+				report.Ambiguous = append(report.Ambiguous, policy[0])
+			}
+		}
+
+		kind := SubjectKindUser
+		if role {
+			kind = SubjectKindRole
+		}
+		migrated := append([]string{EncodeSubject(kind, policy[0])}, policy[1:]...)
+		oldPolicies = append(oldPolicies, policy)
+		newPolicies = append(newPolicies, migrated)
+	}
+
+	if len(oldPolicies) > 0 {
+		if err := e.replaceRules(ctx, PolicyTypePermission, oldPolicies, newPolicies); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(oldGroupings) > 0 {
+		if err := e.replaceRules(ctx, PolicyTypeGrouping, oldGroupings, newGroupings); err != nil {
+			if len(oldPolicies) > 0 {
+				if rollbackErr := e.replaceRules(ctx, PolicyTypePermission, newPolicies, oldPolicies); rollbackErr != nil {
+					return nil, fmt.Errorf("迁移角色分配失败: %v，且回滚权限策略失败: %v", err, rollbackErr)
+				}
+			}
+			return nil, err
+		}
+	}
+
+	report.MigratedPolicies = len(oldPolicies)
+	report.MigratedGroupings = len(oldGroupings)
+	return report, nil
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// TenantPlaceholder 角色模板中的租户占位符，实例化时替换为租户键
+const TenantPlaceholder = "{tenant}"
+
+// Tenant 租户信息
+type Tenant struct {
+	Key       string    `json:"key"`       // 租户唯一标识
+	Name      string    `json:"name"`      // 租户显示名称
+	CreatedBy string    `json:"createdBy"` // 创建者
+	CreatedAt time.Time `json:"createdAt"` // 创建时间
+
+	// Registered 是否已登记；未登记的租户只出现在策略或角色归属中，名称、创建者和创建时间为空
+	Registered bool `json:"registered"`
+}
+
+// TenantStats 租户统计（由策略和角色表计算，别名下的授权计入规范租户）
+type TenantStats struct {
+	TenantKey         string `json:"tenantKey"`         // 规范租户键
+	Users             int    `json:"users"`             // 在租户内拥有角色分配或直接权限的用户数
+	Roles             int    `json:"roles"`             // 归属租户的角色数（不含全局角色和链接的共享角色）
+	DirectPermissions int    `json:"directPermissions"` // 用户直接权限数
+	RoleAssignments   int    `json:"roleAssignments"`   // 用户角色分配数（不含全局分配和角色继承）
+}
+
+// TenantMember 租户成员（在租户内或全局拥有角色分配的用户）
+type TenantMember struct {
+	UserKey string   `json:"userKey"` // 用户标识
+	Roles   []string `json:"roles"`   // 用户在该租户生效的角色（含全局角色）
+}
+
+// RoleTemplate 角色模板，用于批量开通租户时在每个租户内实例化角色
+type RoleTemplate struct {
+	Key         string       `json:"key"`         // 角色键，支持 {tenant} 占位符（角色键全局唯一，建议包含租户键）
+	Name        string       `json:"name"`        // 角色显示名称，支持 {tenant} 占位符
+	Description string       `json:"description"` // 角色描述
+	Permissions []Permission `json:"permissions"` // 角色权限列表
+}
+
+// Instantiate 将模板中的租户占位符替换为指定租户键
+func (t RoleTemplate) Instantiate(tenantKey string) RoleTemplate {
+	permissions := make([]Permission, len(t.Permissions))
+	copy(permissions, t.Permissions)
+
+	return RoleTemplate{
+		Key:         strings.ReplaceAll(t.Key, TenantPlaceholder, tenantKey),
+		Name:        strings.ReplaceAll(t.Name, TenantPlaceholder, tenantKey),
+		Description: strings.ReplaceAll(t.Description, TenantPlaceholder, tenantKey),
+		Permissions: permissions,
+	}
+}
+
+// TenantSpec 租户开通规格
+type TenantSpec struct {
+	TenantKey     string         `json:"tenantKey"`     // 租户键
+	Name          string         `json:"name"`          // 租户显示名称
+	RoleTemplates []RoleTemplate `json:"roleTemplates"` // 需要在租户内实例化的角色模板
+	AdminRoleKey  string         `json:"adminRoleKey"`  // 管理员角色键，支持 {tenant} 占位符
+	AdminUserKeys []string       `json:"adminUserKeys"` // 租户管理员用户列表
+}
+
+// TenantProvisionResult 单个租户的开通结果
+type TenantProvisionResult struct {
+	TenantKey      string   `json:"tenantKey"`      // 租户键
+	Success        bool     `json:"success"`        // 是否全部步骤成功
+	CreatedRoles   []string `json:"createdRoles"`   // 已创建的角色
+	AssignedAdmins []string `json:"assignedAdmins"` // 已分配管理员角色的用户
+	Error          string   `json:"error"`          // 失败原因（成功时为空）
+}
+
+// TenantLockdown 租户封锁状态
+// 封锁期间，租户内所有权限检查对未持有放行角色的用户一律拒绝，用于数据事故的应急处置
+type TenantLockdown struct {
+	TenantKey    string    `json:"tenantKey"`    // 租户键
+	AllowedRoles []string  `json:"allowedRoles"` // 放行角色列表
+	LockedBy     string    `json:"lockedBy"`     // 封锁操作者
+	LockedAt     time.Time `json:"lockedAt"`     // 封锁时间
+}
+
+// TenantAlias 租户别名（旧租户键 -> 新租户键）
+// 租户改名后，旧键下的授权在权限检查时按新键生效，直到通过迁移物理改写
+type TenantAlias struct {
+	AliasKey  string    `json:"aliasKey"`  // 别名（旧租户键）
+	TenantKey string    `json:"tenantKey"` // 规范租户键（新租户键）
+	CreatedBy string    `json:"createdBy"` // 创建者
+	CreatedAt time.Time `json:"createdAt"` // 创建时间
+}
+
+// DomainRewriteResult 域改写结果
+type DomainRewriteResult struct {
+	RewrittenPolicies  int `json:"rewrittenPolicies"`  // 改写的权限策略数量
+	RewrittenGroupings int `json:"rewrittenGroupings"` // 改写的角色分配数量
+	RemovedDuplicates  int `json:"removedDuplicates"`  // 新域已存在而直接删除的重复策略数量
+}
+
+// TenantAliasMigrationResult 租户别名迁移结果
+type TenantAliasMigrationResult struct {
+	AliasKey  string `json:"aliasKey"`  // 别名（旧租户键）
+	TenantKey string `json:"tenantKey"` // 规范租户键
+	DomainRewriteResult
+	MigratedRoles int `json:"migratedRoles"` // 改写归属租户的角色数量
+}
+
+// TenantDeletionResult 租户删除结果
+type TenantDeletionResult struct {
+	TenantKey        string   `json:"tenantKey"`        // 租户键
+	AliasKeys        []string `json:"aliasKeys"`        // 随租户一并清理的别名
+	RemovedPolicies  int      `json:"removedPolicies"`  // 移除的权限策略数量（含别名下的策略）
+	RemovedGroupings int      `json:"removedGroupings"` // 移除的角色分配数量（含别名下的分配）
+	DeletedRoles     []string `json:"deletedRoles"`     // 删除的归属该租户的角色
+}
@@ -0,0 +1,169 @@
+package core
+
+import "fmt"
+
+// TraceStepKind 权限检查追踪步骤类型
+type TraceStepKind string
+
+const (
+	TraceStepGuard        TraceStepKind = "guard"         // 检查守卫（如租户封锁）
+	TraceStepDeny         TraceStepKind = "deny"          // 显式拒绝规则
+	TraceStepDirectRules  TraceStepKind = "direct_rules"  // 查询主体的直接授权
+	TraceStepRoleLookup   TraceStepKind = "role_lookup"   // 查询主体在某个域的角色
+	TraceStepRoleExpand   TraceStepKind = "role_expand"   // 在某个域展开角色的授权
+	TraceStepRuleMatched  TraceStepKind = "rule_matched"  // 规则与目标权限匹配
+	TraceStepRuleFiltered TraceStepKind = "rule_filtered" // 规则与目标权限匹配，但被规则过滤器排除（如环境不匹配）
+	TraceStepDecision     TraceStepKind = "decision"      // 最终决策
+)
+
+// TraceStep 权限检查追踪步骤
+type TraceStep struct {
+	Kind    TraceStepKind `json:"kind"`              // 步骤类型
+	Subject string        `json:"subject,omitempty"` // 涉及的主体（用户或角色）
+	Domain  string        `json:"domain,omitempty"`  // 查询的域
+	Roles   []string      `json:"roles,omitempty"`   // 查询到的角色（role_lookup）
+	Rule    *Policy       `json:"rule,omitempty"`    // 涉及的规则（rule_matched / rule_filtered）
+	Detail  string        `json:"detail"`            // 可读说明
+}
+
+// CheckTrace 权限检查追踪结果
+// 按评估顺序记录每一步，用于"诊断访问"类支持界面定位授权来源或拒绝原因
+type CheckTrace struct {
+	Subject    string      `json:"subject"`    // 被检查的主体
+	Domain     string      `json:"domain"`     // 被检查的域
+	Permission Permission  `json:"permission"` // 被检查的权限
+	Allowed    bool        `json:"allowed"`    // 最终决策
+	Steps      []TraceStep `json:"steps"`      // 有序的评估步骤
+
+	DecidingRule *Policy `json:"decidingRule,omitempty"` // 决定结果的规则（匹配规则中优先级最高的一条）
+}
+
+// addStep 追加追踪步骤
+func (t *CheckTrace) addStep(step TraceStep) {
+	t.Steps = append(t.Steps, step)
+}
+
+// TraceCheckPermission 检查权限并记录评估过程
+// 评估逻辑与 CheckPermission 一致，但不会在第一条匹配规则处停止，以便完整展示所有授权来源
+func (e *Enforcer) TraceCheckPermission(subject, domain string, permission Permission) (*CheckTrace, error) {
+	trace := &CheckTrace{Subject: subject, Domain: domain, Permission: permission}
+
+	// 1. 检查守卫
+	if !e.checkAllowed(subject, domain) {
+		trace.addStep(TraceStep{Kind: TraceStepGuard, Subject: subject, Domain: domain, Detail: "被检查守卫拒绝（例如租户处于封锁状态且主体未持有放行角色）"})
+		trace.addStep(TraceStep{Kind: TraceStepDecision, Detail: "拒绝"})
+		return trace, nil
+	}
+	trace.addStep(TraceStep{Kind: TraceStepGuard, Subject: subject, Domain: domain, Detail: "通过检查守卫"})
+
+	// 显式拒绝优先于所有授权
+	if denies := e.DenyingRules(subject, domain, permission); len(denies) > 0 {
+		for _, deny := range denies {
+			trace.addStep(TraceStep{Kind: TraceStepDeny, Subject: deny.SubjectKey, Domain: deny.TenantKey, Detail: fmt.Sprintf("%s %s 在域 %s 被显式拒绝 %s", deny.SubjectKind, deny.SubjectKey, deny.TenantKey, deny.Permission.String())})
+		}
+		trace.addStep(TraceStep{Kind: TraceStepDecision, Detail: "拒绝：显式拒绝优先于授权"})
+		return trace, nil
+	}
+
+	// 2. 直接授权
+	userSubject := e.UserSubject(subject)
+	domains := e.equivalentDomains(domain)
+	for _, equivalent := range domains {
+		directPolicies, err := e.enforcer.GetPermissionsForUser(userSubject, equivalent)
+		if err != nil {
+			continue
+		}
+		trace.addStep(TraceStep{Kind: TraceStepDirectRules, Subject: subject, Domain: equivalent, Detail: fmt.Sprintf("找到 %d 条直接授权", len(directPolicies))})
+		if err := e.traceRules(trace, directPolicies, permission); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. 角色查询（指定域及其别名、全局域）
+	lookupDomains := append([]string{}, domains...)
+	if domain != "*" {
+		lookupDomains = append(lookupDomains, "*")
+	}
+
+	roleSeen := make(map[string]bool)
+	var roles []string
+	for _, lookupDomain := range lookupDomains {
+		storedRoles := e.enforcer.GetRolesForUserInDomain(userSubject, lookupDomain)
+		domainRoles := e.applicableRoles(userSubject, lookupDomain, storedRoles)
+		for i, role := range domainRoles {
+			_, domainRoles[i] = e.decodeStoredSubject(role)
+		}
+		detail := fmt.Sprintf("在域 %s 找到 %d 个角色", lookupDomain, len(domainRoles))
+		if excluded := len(storedRoles) - len(domainRoles); excluded > 0 {
+			detail += fmt.Sprintf("（%d 个角色分配在当前上下文不生效，如已过期）", excluded)
+		}
+		trace.addStep(TraceStep{Kind: TraceStepRoleLookup, Subject: subject, Domain: lookupDomain, Roles: domainRoles, Detail: detail})
+		for _, role := range domainRoles {
+			if !roleSeen[role] {
+				roleSeen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	// 4. 角色授权展开
+	domainsToCheck := []string{"*"}
+	if domain != "*" {
+		domainsToCheck = append(domainsToCheck, domains...)
+	}
+	for _, role := range roles {
+		for _, checkDomain := range e.roleDomains(role, domain, domainsToCheck) {
+			rolePolicies, err := e.enforcer.GetPermissionsForUser(e.RoleSubject(role), checkDomain)
+			if err != nil {
+				continue
+			}
+			trace.addStep(TraceStep{Kind: TraceStepRoleExpand, Subject: role, Domain: checkDomain, Detail: fmt.Sprintf("角色 %s 在域 %s 有 %d 条授权", role, checkDomain, len(rolePolicies))})
+			if err := e.traceRules(trace, rolePolicies, permission); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 5. 决策
+	if trace.Allowed {
+		trace.addStep(TraceStep{Kind: TraceStepDecision, Rule: trace.DecidingRule, Detail: fmt.Sprintf("允许（决定规则优先级 %d）", trace.DecidingRule.Priority)})
+	} else {
+		trace.addStep(TraceStep{Kind: TraceStepDecision, Detail: "拒绝：没有匹配且生效的授权"})
+	}
+
+	return trace, nil
+}
+
+// traceRules 记录与目标权限匹配的规则
+func (e *Enforcer) traceRules(trace *CheckTrace, policies [][]string, permission Permission) error {
+	for _, policy := range policies {
+		if len(policy) < 4 || (Resource(policy[2]) != permission.Resource && Resource(policy[2]) != ResourceAll) {
+			continue
+		}
+
+		rule, err := e.toPolicy(policy)
+		if err != nil {
+			return err
+		}
+		if !e.actionCovers(rule.Action, permission.Action) {
+			continue
+		}
+
+		if !e.ruleApplies(rule) {
+			trace.addStep(TraceStep{Kind: TraceStepRuleFiltered, Subject: rule.Subject, Domain: rule.Domain, Rule: &rule, Detail: "规则匹配但在当前上下文不生效"})
+			continue
+		}
+		if rule.Condition != "" {
+			trace.addStep(TraceStep{Kind: TraceStepRuleFiltered, Subject: rule.Subject, Domain: rule.Domain, Rule: &rule, Detail: fmt.Sprintf("规则附加了条件 %s，只在 EnforceWithAttributes 中评估", rule.Condition)})
+			continue
+		}
+
+		trace.Allowed = true
+		if trace.DecidingRule == nil || rulePrecedes(rule, *trace.DecidingRule) {
+			deciding := rule
+			trace.DecidingRule = &deciding
+		}
+		trace.addStep(TraceStep{Kind: TraceStepRuleMatched, Subject: rule.Subject, Domain: rule.Domain, Rule: &rule, Detail: "规则匹配"})
+	}
+	return nil
+}
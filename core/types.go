@@ -15,11 +15,19 @@ const (
 
 // Policy 策略结构体
 type Policy struct {
-	Type     PolicyType `json:"type"`     // 策略类型，p为权限策略，g为角色分组策略
-	Subject  string     `json:"subject"`  // 主体标识，用户ID或角色名
-	Domain   string     `json:"domain"`   // 域标识，租户ID或*表示全局
-	Resource Resource   `json:"resource"` // 资源类型，如user、graph等
-	Action   Action     `json:"action"`   // 操作类型，如read、write等
+	Type        PolicyType  `json:"type"`                  // 策略类型，p为权限策略，g为角色分组策略
+	Subject     string      `json:"subject"`               // 主体标识，用户ID或角色名
+	SubjectKind SubjectKind `json:"subjectKind,omitempty"` // 主体类型，仅在启用主体命名空间时填充
+	Domain      string      `json:"domain"`                // 域标识，租户ID或*表示全局
+	Resource    Resource    `json:"resource"`              // 资源类型，如user、graph等
+	Action      Action      `json:"action"`                // 操作类型，如read、write等
+	Priority    int         `json:"priority"`              // 规则优先级，数值越小越优先
+	Condition   string      `json:"condition,omitempty"`   // 规则条件表达式，只在 EnforceWithAttributes 中评估
+}
+
+// Permission 返回策略授予的权限（对象级授权的资源为 "资源:对象ID" 形式）
+func (p Policy) Permission() Permission {
+	return Permission{Resource: p.Resource, Action: p.Action}
 }
 
 // GroupingPolicy 角色分配策略
@@ -32,8 +40,8 @@ type GroupingPolicy struct {
 // PermissionChange 权限变更记录
 type PermissionChange struct {
 	ID          string    `json:"id"`          // 变更记录唯一标识
-	UserKey     string    `json:"userKey"`     // 被操作的用户标识
-	Action      Action    `json:"action"`      // 操作类型：grant/revoke/assign/remove
+	UserKey     string    `json:"userKey"`     // 被操作的用户标识（角色变更时为空）
+	Action      Action    `json:"action"`      // 操作类型：grant/revoke/assign/remove/create/delete
 	Target      string    `json:"target"`      // 操作目标：permission或role
 	TenantKey   string    `json:"tenantKey"`   // 租户标识
 	OperatorKey string    `json:"operatorKey"` // 操作者用户标识
@@ -61,14 +69,59 @@ var (
 	ErrCasbinNotInitialized = Error{Code: "CASBIN_NOT_INITIALIZED", Message: "Casbin执行器未初始化"}
 	ErrRoleAlreadyExists    = Error{Code: "ROLE_ALREADY_EXISTS", Message: "角色已存在"}
 
+	// 幂等相关错误
+	ErrIdempotencyKeyConflict   = Error{Code: "IDEMPOTENCY_KEY_CONFLICT", Message: "幂等键已被用于其他操作"}
+	ErrIdempotencyKeyInProgress = Error{Code: "IDEMPOTENCY_KEY_IN_PROGRESS", Message: "相同幂等键的操作正在执行中"}
+
 	// 安全相关错误
-	ErrSelfElevationPrevented     = Error{Code: "SELF_ELEVATION_PREVENTED", Message: "不允许为自己分配管理员权限"}
-	ErrSystemPermissionImmutable  = Error{Code: "SYSTEM_PERMISSION_IMMUTABLE", Message: "系统权限不可变更"}
-	ErrSystemRoleImmutable        = Error{Code: "SYSTEM_ROLE_IMMUTABLE", Message: "该角色包含系统级权限（如租户管理、系统配置等），不允许修改。请创建新的自定义角色来调整权限"}
-	ErrSystemRoleAssignmentDenied = Error{Code: "SYSTEM_ROLE_ASSIGNMENT_DENIED", Message: "角色包含系统级权限，只能在租户初始化时分配给管理员用户"}
-	ErrSystemRoleRemovalDenied    = Error{Code: "SYSTEM_ROLE_REMOVAL_DENIED", Message: "无法移除该角色：角色包含系统级权限，移除后用户将无法管理系统"}
-	ErrTenantRoleInvalid          = Error{Code: "TENANT_ROLE_INVALID", Message: "指定的角色包含跨租户权限，不适合作为租户内管理员角色。租户内管理员应使用不包含租户管理权限的角色"}
-	ErrGlobalRoleAccessDenied     = Error{Code: "GLOBAL_ROLE_ACCESS_DENIED", Message: "操作全局域角色需要全局权限，当前用户只有租户级权限"}
-	ErrDelegationDepthExceeded    = Error{Code: "DELEGATION_DEPTH_EXCEEDED", Message: "超过权限传递深度限制"}
-	ErrInvalidPermissionType      = Error{Code: "INVALID_PERMISSION_TYPE", Message: "无效的权限类型"}
+	ErrSelfElevationPrevented         = Error{Code: "SELF_ELEVATION_PREVENTED", Message: "不允许为自己分配管理员权限"}
+	ErrSystemPermissionImmutable      = Error{Code: "SYSTEM_PERMISSION_IMMUTABLE", Message: "系统权限不可变更"}
+	ErrSystemRoleImmutable            = Error{Code: "SYSTEM_ROLE_IMMUTABLE", Message: "该角色包含系统级权限（如租户管理、系统配置等），不允许修改。请创建新的自定义角色来调整权限"}
+	ErrSystemRoleAssignmentDenied     = Error{Code: "SYSTEM_ROLE_ASSIGNMENT_DENIED", Message: "角色包含系统级权限，只能在租户初始化时分配给管理员用户"}
+	ErrSystemRoleRemovalDenied        = Error{Code: "SYSTEM_ROLE_REMOVAL_DENIED", Message: "无法移除该角色：角色包含系统级权限，移除后用户将无法管理系统"}
+	ErrTenantRoleInvalid              = Error{Code: "TENANT_ROLE_INVALID", Message: "指定的角色包含跨租户权限，不适合作为租户内管理员角色。租户内管理员应使用不包含租户管理权限的角色"}
+	ErrGlobalRoleAccessDenied         = Error{Code: "GLOBAL_ROLE_ACCESS_DENIED", Message: "操作全局域角色需要全局权限，当前用户只有租户级权限"}
+	ErrDelegationDepthExceeded        = Error{Code: "DELEGATION_DEPTH_EXCEEDED", Message: "超过权限传递深度限制"}
+	ErrInvalidPermissionType          = Error{Code: "INVALID_PERMISSION_TYPE", Message: "无效的权限类型"}
+	ErrRoleLocked                     = Error{Code: "ROLE_LOCKED", Message: "角色已被冻结，解冻前不允许修改权限或变更分配"}
+	ErrRoleInheritanceCycle           = Error{Code: "ROLE_INHERITANCE_CYCLE", Message: "角色继承关系会形成环"}
+	ErrRoleNotShared                  = Error{Code: "ROLE_NOT_SHARED", Message: "角色未发布为共享角色"}
+	ErrTenantAlreadyExists            = Error{Code: "TENANT_ALREADY_EXISTS", Message: "租户已存在"}
+	ErrRoleExceedsOperatorPermissions = Error{Code: "ROLE_EXCEEDS_OPERATOR_PERMISSIONS", Message: "角色包含操作者自身不具备的权限，无法分配"}
+	ErrCrossTenantAssignment          = Error{Code: "CROSS_TENANT_ASSIGNMENT", Message: "角色归属租户与分配租户不一致，跨租户分配需由全局管理员通过 AssignRoleCrossTenant 进行"}
+	ErrRoleCreationIncomplete         = Error{Code: "ROLE_CREATION_INCOMPLETE", Message: "角色创建失败且补偿未完成，存在残留数据需要人工清理"}
+	ErrUserKeyConflict                = Error{Code: "USER_KEY_CONFLICT", Message: "新用户标识已有权限策略或角色分配"}
+	ErrTenantAliasChain               = Error{Code: "TENANT_ALIAS_CHAIN", Message: "租户别名不能指向别名，已被别名指向的租户也不能作为别名"}
+	ErrTenantAliasNotFound            = Error{Code: "TENANT_ALIAS_NOT_FOUND", Message: "租户别名不存在"}
+	ErrSubjectNamespacesDisabled      = Error{Code: "SUBJECT_NAMESPACES_DISABLED", Message: "未启用主体命名空间，请先设置 Config.SubjectNamespaces"}
+	ErrInvalidCursor                  = Error{Code: "INVALID_CURSOR", Message: "无效的分页游标"}
+	ErrSharedRoleInUse                = Error{Code: "SHARED_ROLE_IN_USE", Message: "共享角色在该租户中仍有用户分配，请先移除分配后再取消链接"}
+	ErrSharedRoleLinked               = Error{Code: "SHARED_ROLE_LINKED", Message: "共享角色仍链接到其他租户，请先取消链接或强制取消发布"}
+	ErrInvitationNotFound             = Error{Code: "INVITATION_NOT_FOUND", Message: "分享邀请不存在"}
+	ErrInvitationUnavailable          = Error{Code: "INVITATION_UNAVAILABLE", Message: "分享邀请已过期、已撤销或已达到使用次数上限"}
+	ErrInvitationAlreadyAccepted      = Error{Code: "INVITATION_ALREADY_ACCEPTED", Message: "用户已接受过该分享邀请"}
+	ErrRolloutNotFound                = Error{Code: "ROLLOUT_NOT_FOUND", Message: "灰度变更不存在或已结束"}
+	ErrRolloutConflict                = Error{Code: "ROLLOUT_CONFLICT", Message: "该角色权限已有进行中的灰度变更"}
+	ErrCrossRegionOperation           = Error{Code: "CROSS_REGION_OPERATION", Message: "操作涉及不同数据驻留区域的租户，不允许跨区域移动数据"}
+	ErrRegionNotConfigured            = Error{Code: "REGION_NOT_CONFIGURED", Message: "数据驻留区域未配置"}
+	ErrEncryptionKeyUnavailable       = Error{Code: "ENCRYPTION_KEY_UNAVAILABLE", Message: "字段加密密钥不可用"}
+	ErrInternal                       = Error{Code: "INTERNAL_ERROR", Message: "内部错误"}
+	ErrInvalidRelationTuple           = Error{Code: "INVALID_RELATION_TUPLE", Message: "无效的关系元组"}
+	ErrUnsupportedRelationTuple       = Error{Code: "UNSUPPORTED_RELATION_TUPLE", Message: "关系元组无法映射为 casbinx 的授权或角色分配"}
+	ErrJobNotFound                    = Error{Code: "JOB_NOT_FOUND", Message: "异步任务不存在"}
+	ErrJobFinished                    = Error{Code: "JOB_FINISHED", Message: "异步任务已结束，无法取消"}
+	ErrJobCanceled                    = Error{Code: "JOB_CANCELED", Message: "异步任务已取消"}
+	ErrInvalidPolicySnapshot          = Error{Code: "INVALID_POLICY_SNAPSHOT", Message: "无效的策略快照"}
+	ErrResourceActionNotDefined       = Error{Code: "RESOURCE_ACTION_NOT_DEFINED", Message: "操作未在该租户的资源上定义"}
+	ErrResourceActionAlreadyExists    = Error{Code: "RESOURCE_ACTION_ALREADY_EXISTS", Message: "资源操作已定义"}
+	ErrResourceActionNotFound         = Error{Code: "RESOURCE_ACTION_NOT_FOUND", Message: "资源操作不存在"}
+	ErrResourceActionInUse            = Error{Code: "RESOURCE_ACTION_IN_USE", Message: "资源操作仍被授权引用，无法删除"}
+	ErrUnauthenticated                = Error{Code: "UNAUTHENTICATED", Message: "请求缺少用户身份"}
+	ErrJobFunctionNotFound            = Error{Code: "JOB_FUNCTION_NOT_FOUND", Message: "岗位不存在"}
+	ErrJobFunctionInUse               = Error{Code: "JOB_FUNCTION_IN_USE", Message: "岗位仍有用户，请先将这些用户同步到其他岗位"}
+	ErrScheduledChangeNotFound        = Error{Code: "SCHEDULED_CHANGE_NOT_FOUND", Message: "定时变更不存在"}
+	ErrScheduledChangeNotPending      = Error{Code: "SCHEDULED_CHANGE_NOT_PENDING", Message: "定时变更已执行或已取消"}
+	ErrTxRollbackIncomplete           = Error{Code: "TX_ROLLBACK_INCOMPLETE", Message: "补偿执行失败且撤销未完成，存在残留变更需要人工核对"}
+	ErrTxUnsupported                  = Error{Code: "TX_UNSUPPORTED", Message: "该操作无法撤销，不能在补偿执行（WithCompensation）内执行"}
+	ErrWildcardPermissionRestricted   = Error{Code: "WILDCARD_PERMISSION_RESTRICTED", Message: "通配授权（资源或操作为 *）只能由在全局域拥有 permission:write 的操作者授予、撤销或分配"}
 )
@@ -0,0 +1,71 @@
+package core
+
+import "time"
+
+// UserMergeResult 用户合并结果
+type UserMergeResult struct {
+	FromUserKey          string           `json:"fromUserKey"`          // 被合并（源）用户
+	ToUserKey            string           `json:"toUserKey"`            // 合并目标用户
+	MovedPermissions     []Policy         `json:"movedPermissions"`     // 迁移到目标用户的直接授权
+	MovedRoles           []GroupingPolicy `json:"movedRoles"`           // 迁移到目标用户的角色分配
+	DuplicatePermissions []Policy         `json:"duplicatePermissions"` // 目标用户已拥有，仅从源用户移除的直接授权
+	DuplicateRoles       []GroupingPolicy `json:"duplicateRoles"`       // 目标用户已拥有，仅从源用户移除的角色分配
+	MergedBy             string           `json:"mergedBy"`             // 操作者
+	MergedAt             time.Time        `json:"mergedAt"`             // 合并时间
+}
+
+// UserMergeRecord 用户合并记录
+type UserMergeRecord struct {
+	FromUserKey          string    `json:"fromUserKey"`          // 被合并（源）用户
+	ToUserKey            string    `json:"toUserKey"`            // 合并目标用户
+	MovedPermissions     int       `json:"movedPermissions"`     // 迁移的直接授权数
+	MovedRoles           int       `json:"movedRoles"`           // 迁移的角色分配数
+	DuplicatePermissions int       `json:"duplicatePermissions"` // 重复的直接授权数
+	DuplicateRoles       int       `json:"duplicateRoles"`       // 重复的角色分配数
+	MergedBy             string    `json:"mergedBy"`             // 操作者
+	MergedAt             time.Time `json:"mergedAt"`             // 合并时间
+}
+
+// UserDataConfig 用户数据删除的保留规则
+type UserDataConfig struct {
+	// AuditRetention 审计记录和合并记录的法定保留期，删除用户数据时保留期内的记录不删除；默认 0，即全部删除
+	AuditRetention time.Duration `json:"auditRetention"`
+
+	// PseudonymizeRetained 将保留的记录中的用户标识替换为本次删除生成的假名，默认保留原标识
+	PseudonymizeRetained bool `json:"pseudonymizeRetained"`
+}
+
+// UserPermissionData 导出的直接授权及其环境标记
+type UserPermissionData struct {
+	Policy
+	Environments []string `json:"environments,omitempty"` // 授权生效的环境，为空表示所有环境
+}
+
+// UserDataExport 用户数据导出（数据主体访问请求），包含 casbinx 保存的与该用户相关的全部数据
+type UserDataExport struct {
+	UserKey            string               `json:"userKey"`            // 用户标识
+	Permissions        []UserPermissionData `json:"permissions"`        // 所有租户的直接授权
+	Roles              []GroupingPolicy     `json:"roles"`              // 所有租户的角色分配
+	AuditEvents        []PermissionChange   `json:"auditEvents"`        // 用户作为被授权人或操作者的审计记录
+	InvitationGrants   []InvitationGrant    `json:"invitationGrants"`   // 通过分享邀请获得的授权来源
+	CreatedInvitations []ShareInvitation    `json:"createdInvitations"` // 用户创建的分享邀请（不含令牌）
+	Merges             []UserMergeRecord    `json:"merges"`             // 用户作为源或目标的合并记录
+	Decisions          []Decision           `json:"decisions"`          // 权限检查决策日志（未启用决策日志时为空）
+	ExportedAt         time.Time            `json:"exportedAt"`         // 导出时间
+}
+
+// UserDataDeletion 用户数据删除结果
+type UserDataDeletion struct {
+	UserKey                 string           `json:"userKey"`                 // 用户标识
+	Pseudonym               string           `json:"pseudonym"`               // 本次删除生成的假名，替换其他记录中引用的该用户
+	RemovedPermissions      []Policy         `json:"removedPermissions"`      // 删除的直接授权
+	RemovedRoles            []GroupingPolicy `json:"removedRoles"`            // 删除的角色分配
+	DeletedInvitationGrants int              `json:"deletedInvitationGrants"` // 删除的邀请授权来源和接受记录数
+	DeletedDecisions        int              `json:"deletedDecisions"`        // 删除的决策日志数
+	DeletedAuditEvents      int              `json:"deletedAuditEvents"`      // 删除的审计记录数
+	RetainedAuditEvents     int              `json:"retainedAuditEvents"`     // 保留期内保留的审计记录数
+	DeletedMerges           int              `json:"deletedMerges"`           // 删除的合并记录数
+	RetainedMerges          int              `json:"retainedMerges"`          // 保留期内保留的合并记录数
+	DeletedBy               string           `json:"deletedBy"`               // 操作者
+	DeletedAt               time.Time        `json:"deletedAt"`               // 删除时间
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"time"
+)
+
+// WatcherType 内置 Watcher 类型
+type WatcherType string
+
+const (
+	WatcherTypeRedis    WatcherType = "redis"    // Redis 发布订阅（默认）
+	WatcherTypePostgres WatcherType = "postgres" // PostgreSQL LISTEN/NOTIFY，复用策略数据库，无需额外组件
+	WatcherTypeNATS     WatcherType = "nats"     // NATS 发布订阅
+)
+
+// DefaultWatcherChannel 内置 Watcher 默认使用的频道（主题）名
+const DefaultWatcherChannel = "casbinx_sync"
+
+// Watcher 策略同步 Watcher
+// 与 Casbin 的 persist.Watcher 方法集一致，自定义实现可直接通过 engine.WithWatcher 注入：
+// 本实例修改策略后调用 Update 通知其他实例，收到其他实例的通知时调用 SetUpdateCallback 设置的回调
+type Watcher interface {
+	SetUpdateCallback(func(string)) error // 设置收到通知时的回调
+	Update() error                        // 通知其他实例策略已变更
+	Close()                               // 停止监听并释放连接
+}
+
+// WatcherResyncMessage 内置 Watcher 断线重连后传给更新回调的消息
+// 断线期间可能错过其他实例的通知，收到该消息时立即重新加载策略，不经过防抖合并
+const WatcherResyncMessage = "casbinx:resync"
+
+// 内置 Watcher 的连接事件（MetricsRecorder.ObserveWatcherConnection 的 event 参数）
+const (
+	WatcherEventDisconnected    = "disconnected"     // 检测到连接断开（包括心跳无响应）
+	WatcherEventReconnected     = "reconnected"      // 重连并重新订阅成功
+	WatcherEventReconnectFailed = "reconnect_failed" // 一次重连尝试失败，按退避等待后重试
+)
+
+// WatcherStats 内置 Watcher 的连接状态
+type WatcherStats struct {
+	Type              WatcherType `json:"type"`                       // Watcher 类型
+	Connected         bool        `json:"connected"`                  // 当前是否处于订阅状态
+	Disconnects       int64       `json:"disconnects"`                // 累计断线次数
+	Reconnects        int64       `json:"reconnects"`                 // 累计重连成功次数
+	ReconnectFailures int64       `json:"reconnectFailures"`          // 累计重连失败次数
+	LastError         string      `json:"lastError,omitempty"`        // 最近一次断线或重连失败的错误
+	LastDisconnectAt  time.Time   `json:"lastDisconnectAt,omitempty"` // 最近一次断线时间
+	LastReconnectAt   time.Time   `json:"lastReconnectAt,omitempty"`  // 最近一次重连成功时间
+}
+
+// WatcherStatsReporter 可报告连接状态的 Watcher，内置 Watcher 均实现；
+// 通过 engine.WithWatcher 注入的 Watcher 实现该接口时，连接状态同样计入 Health
+type WatcherStatsReporter interface {
+	Stats() WatcherStats
+}
+
+// 同步通知的拒绝原因（MetricsRecorder.ObserveWatcherRejected 的 reason 参数）
+const (
+	WatcherRejectUnsigned     = "unsigned"      // 未签名或格式无效
+	WatcherRejectUnknownKey   = "unknown_key"   // 签名密钥ID无法取得密钥
+	WatcherRejectBadSignature = "bad_signature" // 签名不匹配
+	WatcherRejectExpired      = "expired"       // 超出有效期（可能是重放）
+)
+
+// WatcherSigningConfig 同步通知签名配置，仅对内置 Watcher 生效
+// 启用后发送的通知使用当前密钥签名（HMAC-SHA256），收到的通知通过验证后才会触发策略重载，
+// 能写入消息通道（如 Redis）的攻击者无法伪造通知
+type WatcherSigningConfig struct {
+	// Keys 签名密钥提供者，为空时不签名；密钥至少 32 字节，不应与字段加密共用密钥
+	// 轮换密钥：先让所有实例的 Key 能取得新密钥，再将 CurrentKey 切换为新密钥，所有实例切换后移除旧密钥
+	Keys KeyProvider `json:"-"`
+
+	// MaxAge 通知的有效期（包括实例之间的时钟偏差），超过的通知视为重放并拒绝，默认 1 分钟
+	MaxAge time.Duration `json:"maxAge"`
+
+	// AcceptUnsigned 同时接受未签名的通知，用于在已有部署上逐个实例启用签名，全部实例启用后应关闭
+	AcceptUnsigned bool `json:"acceptUnsigned"`
+}
+
+// Enabled 是否启用签名
+func (c WatcherSigningConfig) Enabled() bool {
+	return c.Keys != nil
+}
@@ -0,0 +1,39 @@
+package core
+
+// === 写操作细分 ===
+
+// EnableStrictWriteActions 启用或关闭写操作的严格区分
+// 关闭时（默认）write 授权同时覆盖 create 和 update 检查；启用后 create、update 只能由对应的授权满足
+func (e *Enforcer) EnableStrictWriteActions(enabled bool) {
+	defer e.invalidateCache()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.strictWriteActions = enabled
+}
+
+// StrictWriteActionsEnabled 是否启用了写操作的严格区分
+func (e *Enforcer) StrictWriteActionsEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.strictWriteActions
+}
+
+// actionCovers 检查授权的操作是否覆盖目标操作：操作相同，授权的操作为 *，或未严格区分写操作时 write 覆盖 create/update
+func (e *Enforcer) actionCovers(granted, requested Action) bool {
+	if granted == requested || granted == ActionAll {
+		return true
+	}
+	if granted != ActionWrite || (requested != ActionCreate && requested != ActionUpdate) {
+		return false
+	}
+	return !e.StrictWriteActionsEnabled()
+}
+
+// covers 检查授权是否覆盖目标权限，在 Permission.Covers 的基础上应用写操作隐含规则
+func (e *Enforcer) covers(granted, target Permission) bool {
+	if !e.actionCovers(granted.Action, target.Action) {
+		return false
+	}
+	granted.Action = target.Action
+	return granted.Covers(target)
+}
@@ -0,0 +1,326 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// AssignRoleCrossTenant 跨租户分配角色（仅限全局管理员）
+// 用于在全局域分配角色，或将某个租户的角色分配到其他租户等 AssignRole 会拒绝的场景
+func (c *casbinxClient) AssignRoleCrossTenant(operatorKey, userKey, roleKey, tenantKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("AssignRoleCrossTenant", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "AssignRoleCrossTenant", []string{operatorKey, userKey, roleKey, tenantKey}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("userKey", userKey),
+			core.RequireArg("roleKey", roleKey),
+			core.RequireArg("tenantKey", tenantKey),
+		); err != nil {
+			return err
+		}
+
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
+
+		// 跨租户分配要求全局用户管理和全局角色管理权限
+		hasUserPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite})
+		if err != nil {
+			return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+		}
+		if !hasUserPermission {
+			return core.ErrGlobalRoleAccessDenied
+		}
+		if err := c.requireGlobalRoleManagement(operatorKey); err != nil {
+			return err
+		}
+
+		if _, err := c.roleManager.GetRole(c.ctx, roleKey); err != nil {
+			return fmt.Errorf("获取角色信息失败: %w", err)
+		}
+
+		if err := c.validateAssignableRole(operatorKey, roleKey, tenantKey); err != nil {
+			return err
+		}
+
+		return c.userManager.AssignRole(c.ctx, operatorKey, userKey, roleKey, tenantKey)
+	})
+}
+
+// ensureRoleTenantMatch 检查角色归属租户与分配租户是否兼容
+// 兼容的情况：角色属于该租户、角色为全局角色、角色是链接到该租户的共享角色。
+// 在全局域分配角色会使角色权限在所有租户生效，必须通过 AssignRoleCrossTenant 显式进行。
+func (c *casbinxClient) ensureRoleTenantMatch(roleKey, tenantKey string) error {
+	if tenantKey == "*" {
+		return core.ErrCrossTenantAssignment
+	}
+
+	role, err := c.roleManager.GetRole(c.ctx, roleKey)
+	if err != nil {
+		return fmt.Errorf("获取角色信息失败: %w", err)
+	}
+
+	if role.TenantKey == tenantKey || role.TenantKey == "*" || role.TenantKey == "" {
+		return nil
+	}
+	if c.roleManager.IsRoleLinked(c.ctx, roleKey, tenantKey) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: 角色 '%s' 属于租户 '%s'，不能分配到租户 '%s'", core.ErrCrossTenantAssignment, roleKey, role.TenantKey, tenantKey)
+}
+
+// validateAssignableRole 检查角色是否可以由操作者分配
+func (c *casbinxClient) validateAssignableRole(operatorKey, roleKey, tenantKey string) error {
+	// 检查角色是否包含系统权限
+	hasSystemPerms, err := c.roleManager.HasSystemPermissions(c.ctx, roleKey)
+	if err != nil {
+		return fmt.Errorf("检查角色系统权限时出错: %w", err)
+	}
+
+	if hasSystemPerms {
+		// 系统角色只能通过租户初始化接口分配，普通角色分配接口不允许
+		return core.ErrSystemRoleAssignmentDenied
+	}
+
+	// 防止通过分配高权限角色间接提权
+	rolePermissions, err := c.roleManager.GetRolePermissions(c.ctx, roleKey)
+	if err != nil {
+		return fmt.Errorf("获取角色权限失败: %w", err)
+	}
+
+	return c.securityValidator.ValidateRoleAssignment(operatorKey, tenantKey, rolePermissions)
+}
+
+// ensureRoleAssignPermission 检查操作者能否在租户中分配或移除角色
+// role:assign 只允许分配已有角色；role:write 包含修改角色的能力，同样允许分配（兼容旧授权）
+func (c *casbinxClient) ensureRoleAssignPermission(operatorKey, tenantKey string) error {
+	for _, action := range []core.Action{core.ActionAssign, core.ActionWrite} {
+		allowed, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, core.Permission{Resource: core.ResourceRole, Action: action})
+		if err != nil {
+			return fmt.Errorf("检查操作者角色分配权限时出错: %w", err)
+		}
+		if allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("操作者 %s 没有角色分配权限，无法分配角色", operatorKey)
+}
+
+// validateRoleAssignment 按 AssignRole 的规则检查操作者能否在租户中分配角色（与被分配的用户无关）
+func (c *casbinxClient) validateRoleAssignment(operatorKey, roleKey, tenantKey string) error {
+	// 冻结检查：冻结的角色不允许变更
+	if err := c.ensureRoleUnlocked(roleKey); err != nil {
+		return err
+	}
+
+	// 安全检查：验证操作者是否有用户管理权限
+	// 验证操作者有用户管理权限
+	userPermission := core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite}
+	hasUserPermission, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, userPermission)
+	if err != nil {
+		return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+	}
+	if !hasUserPermission {
+		return fmt.Errorf("操作者 %s 没有用户管理权限，无法分配角色", operatorKey)
+	}
+
+	// 验证操作者有角色分配权限
+	if err := c.ensureRoleAssignPermission(operatorKey, tenantKey); err != nil {
+		return err
+	}
+
+	// 角色归属租户必须与分配租户兼容，跨租户分配需使用 AssignRoleCrossTenant
+	if err := c.ensureRoleTenantMatch(roleKey, tenantKey); err != nil {
+		return err
+	}
+
+	return c.validateAssignableRole(operatorKey, roleKey, tenantKey)
+}
+
+// validateRoleRemoval 按 RemoveRole 的规则检查操作者能否移除用户在租户中的角色
+func (c *casbinxClient) validateRoleRemoval(operatorKey, userKey, roleKey, tenantKey string) error {
+	// 冻结检查：冻结的角色不允许变更
+	if err := c.ensureRoleUnlocked(roleKey); err != nil {
+		return err
+	}
+
+	// 安全检查：验证操作者是否有用户管理权限
+	// 验证操作者有用户管理权限
+	userPermission := core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite}
+	hasUserPermission, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, userPermission)
+	if err != nil {
+		return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+	}
+	if !hasUserPermission {
+		return fmt.Errorf("操作者 %s 没有用户管理权限，无法分配角色", operatorKey)
+	}
+
+	// 验证操作者有角色分配权限
+	if err := c.ensureRoleAssignPermission(operatorKey, tenantKey); err != nil {
+		return err
+	}
+
+	// 检查用户的角色是否包含系统权限
+	hasSystemPerms, err := c.roleManager.UserRoleHasSystemPermissions(c.ctx, userKey, roleKey, tenantKey)
+	if err != nil {
+		return fmt.Errorf("检查用户角色系统权限时出错: %w", err)
+	}
+
+	if hasSystemPerms {
+		// 系统角色只能通过租户初始化接口分配，不能移除
+		return core.ErrSystemRoleRemovalDenied
+	}
+
+	return nil
+}
+
+// AssignRoleToUsers 批量为用户分配角色
+// 操作者对角色的分配权限只校验一次，再逐个校验用户，未通过校验的用户不会中断批次；
+// 通过校验的分配在单个事务中写入并只发布一次同步通知，适用于租户开通时批量导入成员；
+// 使用幂等键重放时只返回首次执行的错误，不返回逐项结果
+func (c *casbinxClient) AssignRoleToUsers(operatorKey, roleKey, tenantKey string, userKeys []string, opts ...core.MutationOption) (_ []core.RoleAssignmentResult, err error) {
+	defer c.guard.recover("AssignRoleToUsers", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{operatorKey, roleKey, tenantKey}, userKeys...)
+
+	var results []core.RoleAssignmentResult
+	err = c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "AssignRoleToUsers", args, func() error {
+		assignments := make([]core.GroupingPolicy, 0, len(userKeys))
+		for _, userKey := range userKeys {
+			assignments = append(assignments, core.GroupingPolicy{UserKey: userKey, RoleKey: roleKey, TenantKey: tenantKey})
+		}
+
+		// 角色层面的校验失败时每个用户都以同样的原因失败
+		if err := c.validateRoleAssignment(operatorKey, roleKey, tenantKey); err != nil {
+			results = failedRoleAssignments(assignments, err)
+			return nil
+		}
+
+		var err error
+		results, err = c.assignRoles(operatorKey, assignments, nil, opts)
+		return err
+	})
+	return results, err
+}
+
+// AssignRoles 批量为单个用户分配多个角色
+// 每个角色按 AssignRole 的规则校验，未通过校验的角色不会中断批次；
+// 通过校验的分配在单个事务中写入并只发布一次同步通知；使用幂等键重放时只返回首次执行的错误，不返回逐项结果
+func (c *casbinxClient) AssignRoles(operatorKey, userKey string, roles []core.RoleAssignment, opts ...core.MutationOption) (_ []core.RoleAssignmentResult, err error) {
+	defer c.guard.recover("AssignRoles", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("userKey", userKey),
+		core.RequireItems("roles", roles),
+	); err != nil {
+		return nil, err
+	}
+
+	args := []string{operatorKey, userKey}
+	for _, role := range roles {
+		args = append(args, role.TenantKey+"/"+role.RoleKey)
+	}
+
+	var results []core.RoleAssignmentResult
+	err = c.runQueued(c.enforcer.UserSubject(userKey), opts, "AssignRoles", args, func() error {
+		assignments := make([]core.GroupingPolicy, 0, len(roles))
+		preFailures := make([]error, len(roles))
+		for i, role := range roles {
+			assignments = append(assignments, core.GroupingPolicy{UserKey: userKey, RoleKey: role.RoleKey, TenantKey: role.TenantKey})
+			if err := core.FirstError(
+				core.RequireArg("roles.roleKey", role.RoleKey),
+				core.RequireArg("roles.tenantKey", role.TenantKey),
+			); err != nil {
+				preFailures[i] = err
+				continue
+			}
+			preFailures[i] = c.validateRoleAssignment(operatorKey, role.RoleKey, role.TenantKey)
+		}
+
+		var err error
+		results, err = c.assignRoles(operatorKey, assignments, preFailures, opts)
+		return err
+	})
+	return results, err
+}
+
+// assignRoles 写入通过校验的角色分配并生成逐项结果
+// preFailures 为调用方已完成的校验结果（可为空），对应位置非空的分配不会写入
+func (c *casbinxClient) assignRoles(operatorKey string, assignments []core.GroupingPolicy, preFailures []error, opts []core.MutationOption) ([]core.RoleAssignmentResult, error) {
+	pending := make([]core.GroupingPolicy, 0, len(assignments))
+	pendingIndex := make([]int, 0, len(assignments))
+	failures := make([]error, len(assignments))
+	for i, assignment := range assignments {
+		if preFailures != nil && preFailures[i] != nil {
+			failures[i] = preFailures[i]
+			continue
+		}
+		pending = append(pending, assignment)
+		pendingIndex = append(pendingIndex, i)
+	}
+
+	if len(pending) > 0 {
+		var validationErrors []error
+		err := c.enforcer.BatchNotifications(func() error {
+			var err error
+			validationErrors, err = c.userManager.AssignRoles(c.ctx, operatorKey, pending)
+			if err != nil {
+				return err
+			}
+
+			// 写入成功的分配中已有的限时分配变为永久分配
+			for j, assignment := range pending {
+				if validationErrors[j] != nil {
+					continue
+				}
+				if err := c.expirations.ClearRoleExpiry(c.ctx, assignment.UserKey, assignment.RoleKey, assignment.TenantKey); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("批量分配角色失败: %w", err)
+		}
+		for j, i := range pendingIndex {
+			failures[i] = validationErrors[j]
+		}
+	}
+
+	results := make([]core.RoleAssignmentResult, 0, len(assignments))
+	for i, assignment := range assignments {
+		result := core.RoleAssignmentResult{UserKey: assignment.UserKey, RoleKey: assignment.RoleKey, TenantKey: assignment.TenantKey}
+		if failures[i] != nil {
+			result.Error = failures[i].Error()
+		} else {
+			result.Success = true
+			c.recordAudit(opts, core.PermissionChange{UserKey: assignment.UserKey, Action: core.AuditActionAssign, Target: assignment.RoleKey, TenantKey: assignment.TenantKey, OperatorKey: operatorKey})
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// failedRoleAssignments 生成全部失败的批量分配结果
+func failedRoleAssignments(assignments []core.GroupingPolicy, err error) []core.RoleAssignmentResult {
+	results := make([]core.RoleAssignmentResult, 0, len(assignments))
+	for _, assignment := range assignments {
+		results = append(results, core.RoleAssignmentResult{
+			UserKey:   assignment.UserKey,
+			RoleKey:   assignment.RoleKey,
+			TenantKey: assignment.TenantKey,
+			Error:     err.Error(),
+		})
+	}
+	return results
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// recordAudit 变更成功后写入审计日志
+// 审计写入失败只记录日志，不影响已经生效的变更；原因取自 core.WithReason。事务内的变更在事务提交后写入
+func (c *casbinxClient) recordAudit(opts []core.MutationOption, change core.PermissionChange) {
+	change.Reason = core.ApplyMutationOptions(opts).Reason
+	if c.tx != nil {
+		c.tx.recordAudit(change)
+		return
+	}
+	if err := c.audit.Record(c.ctx, change); err != nil {
+		c.guard.logger.Error("写入审计日志失败", "action", change.Action, "operator", change.OperatorKey, "target", change.Target, "tenant", change.TenantKey, "error", err)
+	}
+}
+
+// recordRolePermissionAudits 为角色权限的新增和删除逐条写入审计记录，Target 为 角色>权限
+func (c *casbinxClient) recordRolePermissionAudits(opts []core.MutationOption, operatorKey, roleKey, tenantKey string, added, removed []core.Permission) {
+	for _, permission := range added {
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionRoleGrant, Target: roleKey + ">" + permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+	}
+	for _, permission := range removed {
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionRoleRevoke, Target: roleKey + ">" + permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+	}
+}
+
+// ListAuditEvents 分页查询权限变更审计日志（按时间倒序）
+// 查询租户内的记录需要该租户的权限读取权限，filter.TenantKey 为空时需要全局权限读取权限
+func (c *casbinxClient) ListAuditEvents(operatorKey string, filter core.AuditFilter, page core.PageRequest) (_ *core.PageResponse[core.PermissionChange], err error) {
+	defer c.guard.recover("ListAuditEvents", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return nil, err
+	}
+
+	offset, err := page.Cursor.Offset()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.requireAuditRead(operatorKey, filter.TenantKey); err != nil {
+		return nil, err
+	}
+
+	limit := page.PageSize()
+	events, total, err := c.audit.ListEvents(c.ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &core.PageResponse[core.PermissionChange]{
+		Items: events,
+		Total: total,
+	}
+	if end := offset + len(events); end < total {
+		response.HasMore = true
+		response.NextCursor = core.EncodeCursor(end)
+	}
+
+	return response, nil
+}
+
+// GenerateChangeDigest 生成租户在时间范围内的权限变更摘要（按需生成，不经过投递回调）
+// 权限要求与 ListAuditEvents 相同
+func (c *casbinxClient) GenerateChangeDigest(operatorKey, tenantKey string, since, until time.Time) (_ *core.ChangeDigest, err error) {
+	defer c.guard.recover("GenerateChangeDigest", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.CheckArg(since.Before(until), "until", core.ConstraintRange),
+	); err != nil {
+		return nil, err
+	}
+	if err := c.requireAuditRead(operatorKey, tenantKey); err != nil {
+		return nil, err
+	}
+
+	digests, err := c.digests.Generate(c.ctx, tenantKey, since, until)
+	if err != nil {
+		return nil, err
+	}
+	if len(digests) == 0 {
+		return &core.ChangeDigest{
+			TenantKey:     tenantKey,
+			Since:         since,
+			Until:         until,
+			ByAction:      map[core.Action]int{},
+			ByOperator:    map[string]int{},
+			AffectedUsers: []string{},
+			Changes:       []core.PermissionChange{},
+		}, nil
+	}
+	return &digests[0], nil
+}
+
+// requireAuditRead 检查操作者是否可以查看租户的审计记录（tenantKey 为空或 * 时要求全局权限）
+func (c *casbinxClient) requireAuditRead(operatorKey, tenantKey string) error {
+	if tenantKey == "" {
+		tenantKey = "*"
+	}
+	allowed, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, core.Permission{Resource: core.ResourcePermission, Action: core.ActionRead})
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return core.ErrPermissionDenied
+	}
+	return nil
+}
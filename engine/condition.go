@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// SetGrantCondition 设置用户直接授权的条件表达式，表达式为空时清除条件
+// 调整条件与授予该权限需要相同的操作权限
+func (c *casbinxClient) SetGrantCondition(operatorKey, userKey, tenantKey string, permission core.Permission, expression string) (err error) {
+	defer c.guard.recover("SetGrantCondition", &err)
+	if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
+		return err
+	}
+
+	if expression == "" {
+		return c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission)
+	}
+	return c.conditions.SetPolicyCondition(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission, expression)
+}
+
+// SetRolePermissionCondition 设置角色授权的条件表达式，表达式为空时清除条件
+func (c *casbinxClient) SetRolePermissionCondition(operatorKey, roleKey string, permission core.Permission, expression string) (err error) {
+	defer c.guard.recover("SetRolePermissionCondition", &err)
+	roleTenantKey, err := c.validateRoleRuleChange(operatorKey, roleKey, []core.Permission{permission})
+	if err != nil {
+		return err
+	}
+
+	if expression == "" {
+		return c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindRole, roleKey, roleTenantKey, permission)
+	}
+	return c.conditions.SetPolicyCondition(c.ctx, core.SubjectKindRole, roleKey, roleTenantKey, permission, expression)
+}
@@ -0,0 +1,37 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// openCredentialDB 按连接字符串创建 PostgreSQL 连接池，每个新的物理连接建立前通过凭据回调获取密码
+// 已建立的连接不受令牌过期影响，只有新建连接需要有效令牌
+func openCredentialDB(dsn string, credentials core.DBCredentialsProvider) (*sql.DB, error) {
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("解析数据库连接字符串失败: %v", err)
+	}
+
+	beforeConnect := func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+		password, err := credentials(ctx, core.DBEndpoint{
+			Host:     connConfig.Host,
+			Port:     connConfig.Port,
+			User:     connConfig.User,
+			Database: connConfig.Database,
+		})
+		if err != nil {
+			return fmt.Errorf("获取数据库凭据失败: %v", err)
+		}
+		connConfig.Password = password
+		return nil
+	}
+
+	return stdlib.OpenDB(*config, stdlib.OptionBeforeConnect(beforeConnect)), nil
+}
@@ -1,68 +1,404 @@
 package engine
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
 	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"gorm.io/gorm"
 )
 
-// CasbinX CasbinX权限管理引擎接口
-type CasbinX interface {
+// PermissionChecker 权限检查能力
+// 业务服务通常只需要依赖此接口，便于在测试中模拟
+type PermissionChecker interface {
+	// 权限检查 (包括用户直接权限和通过角色继承的权限)
+	CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error)                             // 检查用户权限(含角色继承)
+	CheckPermissionVersioned(userKey, tenantKey string, permission core.Permission) (*core.CheckResult, error)       // 检查用户权限并返回检查时的策略修订号（用于按修订号缓存结果）
+	GetPolicyRevision() uint64                                                                                       // 获取本实例当前的策略修订号（每次策略写入或重新加载递增）
+	HasDirectPermission(userKey, tenantKey string, permission core.Permission) (bool, error)                         // 检查用户直接权限(不含角色)
+	HasRole(userKey, roleKey, tenantKey string) (bool, error)                                                        // 检查用户是否拥有角色
+	CheckPermissionDebug(userKey, tenantKey string, permission core.Permission) (*core.CheckTrace, error)            // 检查用户权限并返回评估过程（用于访问诊断）
+	ExplainPermission(userKey, tenantKey string, permission core.Permission) (*core.PermissionExplanation, error)    // 检查用户权限并说明授权链路（直接授权、角色、全局角色、继承的角色）
+	EnforceWithAttributes(userKey, tenantKey string, permission core.Permission, attrs map[string]any) (bool, error) // 检查用户权限并评估规则条件（时间窗口、IP 范围、自定义属性）
+
+	// 批量权限检查
+	CheckMultiplePermissions(userKey, tenantKey string, permissions []core.Permission) ([]bool, error) // 批量检查权限
+	HasAnyPermission(userKey, tenantKey string, permissions []core.Permission) (bool, error)           // 检查是否拥有任意一个权限
+	HasAllPermissions(userKey, tenantKey string, permissions []core.Permission) (bool, error)          // 检查是否拥有所有权限
+
+	// 资源和租户访问检查
+	CanAccessResource(userKey, tenantKey string, resource core.Resource) (bool, error)            // 检查是否可访问资源(任意操作)
+	CanAccessTenant(userKey, tenantKey string) (bool, error)                                      // 检查是否可访问租户
+	GetAvailableActions(userKey, tenantKey string, resource core.Resource) ([]core.Action, error) // 获取用户对资源的可用操作
+	GetUserTenants(userKey string) ([]string, error)                                              // 获取用户可访问的租户列表
+}
+
+// UserPermissionAdmin 用户权限与角色分配管理能力
+type UserPermissionAdmin interface {
 	// 用户权限管理
-	GrantPermission(operatorKey, userKey, tenantKey string, permission core.Permission) error  // 授予用户权限
-	RevokePermission(operatorKey, userKey, tenantKey string, permission core.Permission) error // 撤销用户权限
+	GrantPermission(operatorKey, userKey, tenantKey string, permission core.Permission, opts ...core.MutationOption) error                               // 授予用户权限
+	RevokePermission(operatorKey, userKey, tenantKey string, permission core.Permission, opts ...core.MutationOption) error                              // 撤销用户权限
+	GrantTemporaryPermission(operatorKey, userKey, tenantKey string, permission core.Permission, expiresAt time.Time, opts ...core.MutationOption) error // 授予用户限时权限（过期后自动撤销）
+	GrantPermissions(operatorKey, userKey, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) error                           // 批量授予用户权限（单个事务、一次同步通知）
+	RevokePermissions(operatorKey, userKey, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) error                          // 批量撤销用户权限（单个事务、一次同步通知）
 
 	// 安全版本的权限查询（需要操作者身份验证）
-	GetDirectPermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.Permission, error)    // 安全查询用户直接权限
-	GetEffectivePermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.Permission, error) // 安全查询用户有效权限
-	ClearUserPermissions(operatorKey, userKey, tenantKey string) error                               // 清除用户在指定租户的所有权限
-	GetUserPermissionsByResource(userKey, tenantKey, resource string) ([]core.Permission, error)     // 获取用户对特定资源的权限
+	GetDirectPermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.Permission, error)                                             // 安全查询用户直接权限
+	GetEffectivePermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.Permission, error)                                          // 安全查询用户有效权限
+	QueryEffectivePermissions(operatorKey, userKey, tenantKey string, options core.PermissionQueryOptions) ([]core.Permission, error)         // 安全查询用户有效权限（去重、过滤、排序）
+	GetEffectivePermissionGroups(operatorKey, userKey, tenantKey string, options core.PermissionQueryOptions) ([]core.PermissionGroup, error) // 安全查询用户有效权限并按资源分组
+	ClearUserPermissions(operatorKey, userKey, tenantKey string, opts ...core.MutationOption) error                                           // 清除用户在指定租户的所有权限
+	GetUserPermissionsByResourceSecure(operatorKey, userKey, tenantKey, resource string) ([]core.Permission, error)                           // 安全查询用户对特定资源的权限
+	GetUserPermissionManifest(operatorKey, userKey, tenantKey string) (*core.PermissionManifest, error)                                       // 安全查询用户的权限清单（角色和有效权限）
+	GetUserPermissionManifestCached(operatorKey, userKey, tenantKey string) (*core.PermissionManifest, error)                                 // 安全查询权限清单，存储不可用时返回最近一次的清单并标记为过期
+	AuthorizeUserQuery(operatorKey, userKey, tenantKey string) error                                                                          // 校验操作者能否查询用户的权限信息（查询自己遵循 SelfQuery 配置，查询他人需要 user:read）
 
 	// 用户角色分配
-	AssignRole(operatorKey, userKey, roleKey, tenantKey string) error // 为用户分配角色
-	RemoveRole(operatorKey, userKey, roleKey, tenantKey string) error // 移除用户角色
-	GetUserRoles(userKey, tenantKey string) ([]string, error)         // 获取用户角色列表
-	ClearUserRoles(operatorKey, userKey string) error                 // 清除用户所有角色分配
+	AssignRole(operatorKey, userKey, roleKey, tenantKey string, opts ...core.MutationOption) error                                                 // 为用户分配角色
+	AssignTemporaryRole(operatorKey, userKey, roleKey, tenantKey string, expiresAt time.Time, opts ...core.MutationOption) error                   // 为用户分配限时角色（过期后自动移除）
+	AssignRoleCrossTenant(operatorKey, userKey, roleKey, tenantKey string, opts ...core.MutationOption) error                                      // 跨租户分配角色（仅限全局管理员）
+	RemoveRole(operatorKey, userKey, roleKey, tenantKey string, opts ...core.MutationOption) error                                                 // 移除用户角色
+	GetUserRolesSecure(operatorKey, userKey, tenantKey string) ([]string, error)                                                                   // 安全查询用户角色列表
+	ClearUserRoles(operatorKey, userKey string, opts ...core.MutationOption) error                                                                 // 清除用户所有角色分配
+	AssignRoleToUsers(operatorKey, roleKey, tenantKey string, userKeys []string, opts ...core.MutationOption) ([]core.RoleAssignmentResult, error) // 批量为用户分配角色（单个事务、一次同步通知）
+	AssignRoles(operatorKey, userKey string, roles []core.RoleAssignment, opts ...core.MutationOption) ([]core.RoleAssignmentResult, error)        // 批量为用户分配多个角色（单个事务、一次同步通知）
+
+	// Deprecated: 不校验调用者身份，任何调用方都能枚举其他用户的权限，请使用 GetUserPermissionsByResourceSecure
+	GetUserPermissionsByResource(userKey, tenantKey, resource string) ([]core.Permission, error) // 获取用户对特定资源的权限
+	// Deprecated: 不校验调用者身份，任何调用方都能枚举其他用户的角色，请使用 GetUserRolesSecure
+	GetUserRoles(userKey, tenantKey string) ([]string, error) // 获取用户角色列表
+
+	// 岗位目录（HR 岗位编码到角色集合的映射，入职、调岗、离职时同步角色分配）
+	DefineJobFunction(operatorKey string, jobFunction core.JobFunction) error                                                              // 创建或替换岗位
+	RemoveJobFunction(operatorKey, tenantKey, jobCode string) error                                                                        // 删除岗位
+	GetJobFunction(tenantKey, jobCode string) (*core.JobFunction, error)                                                                   // 获取岗位
+	ListJobFunctions(tenantKey string) ([]*core.JobFunction, error)                                                                        // 获取租户的所有岗位
+	SyncUserJobFunction(operatorKey, userKey, tenantKey, jobCode string, opts ...core.MutationOption) (*core.JobFunctionSyncResult, error) // 按岗位调整用户的角色分配，jobCode 为空表示离职
+	GetUserJobFunction(userKey, tenantKey string) (*core.UserJobFunction, error)                                                           // 获取用户当前同步的岗位
+
+	// 限时授权（过期后权限检查立即忽略，后台清理撤销策略）
+	ListExpiringGrants(filter core.ExpiringGrantFilter) ([]*core.ExpiringGrant, error) // 按条件查询限时授权
+
+	// 拒绝规则（优先于所有授权，包括通过角色获得的授权）
+	DenyPermission(operatorKey string, rule core.DenyRule, opts ...core.MutationOption) error   // 添加用户或角色的拒绝规则
+	RevokeDeny(operatorKey string, rule core.DenyRule, opts ...core.MutationOption) error       // 移除拒绝规则
+	ListDenyRules(filter core.DenyRuleFilter) ([]*core.DenyRule, error)                         // 按条件查询拒绝规则
+	GetDeniedPermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.DenyRule, error) // 安全查询对用户生效的拒绝规则（有效权限查询不包含被拒绝的权限）
+
+	// 定时权限变更（计划中的组织调整，到达生效时间后由调度器执行）
+	SchedulePermissionChange(operatorKey string, change core.ScheduledChange, effectiveAt time.Time, opts ...core.MutationOption) (*core.ScheduledPermissionChange, error) // 登记定时授权或撤销
+	GetScheduledChange(changeID string) (*core.ScheduledPermissionChange, error)                                                                                           // 获取定时变更
+	ListScheduledChanges(filter core.ScheduledChangeFilter) ([]*core.ScheduledPermissionChange, error)                                                                     // 按条件查询定时变更
+	CancelScheduledChange(operatorKey, changeID string) (*core.ScheduledPermissionChange, error)                                                                           // 取消等待生效的定时变更
 
+	// 用户数据（数据主体访问与删除请求，要求全局用户管理权限）
+	ExportUserData(operatorKey, userKey string) (*core.UserDataExport, error)                                // 导出 casbinx 保存的与用户相关的全部数据
+	DeleteUserData(operatorKey, userKey string, opts ...core.MutationOption) (*core.UserDataDeletion, error) // 删除用户的授权、角色分配及相关记录，审计记录按保留规则处理
+}
+
+// RoleAdministrator 角色管理能力
+type RoleAdministrator interface {
 	// 角色管理
-	CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error // 创建角色
-	UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error // 更新角色信息
-	DeleteRole(roleKey string) error                                                                               // 删除角色
-	GetRole(roleKey string) (*core.Role, error)                                                                    // 获取角色详情
-	ListRoles(tenantKey string, filter *core.RoleFilter) ([]*core.Role, error)                                     // 获取角色列表
+	CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) error // 创建角色
+	UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) error // 更新角色信息
+	DeleteRole(roleKey string, opts ...core.MutationOption) error                                                                               // 删除角色
+	GetRole(roleKey string) (*core.Role, error)                                                                                                 // 获取角色详情
+	ListRoles(tenantKey string, filter *core.RoleFilter) ([]*core.Role, error)                                                                  // 获取角色列表
+	ListRolesPage(tenantKey string, filter *core.RoleFilter, page core.PageRequest) (*core.PageResponse[*core.Role], error)                     // 分页获取角色列表（支持按 filter.OrderBy 排序）
 
 	// 角色权限管理
-	GetRolePermissions(roleKey string) ([]core.Permission, error)                        // 获取角色权限列表
-	GrantRolePermission(operatorKey, roleKey string, permission core.Permission) error   // 授予角色权限
-	RevokeRolePermission(operatorKey, roleKey string, permission core.Permission) error  // 撤销角色权限
-	SetRolePermissions(operatorKey, roleKey string, permissions []core.Permission) error // 设置角色权限(覆盖)
+	GetRolePermissions(roleKey string) ([]core.Permission, error)                                                     // 获取角色权限列表
+	GetRoleEffectivePermissions(roleKey string) ([]core.InheritedPermission, error)                                   // 获取角色有效权限（展开继承链并标注来源角色）
+	GrantRolePermission(operatorKey, roleKey string, permission core.Permission, opts ...core.MutationOption) error   // 授予角色权限
+	RevokeRolePermission(operatorKey, roleKey string, permission core.Permission, opts ...core.MutationOption) error  // 撤销角色权限
+	SetRolePermissions(operatorKey, roleKey string, permissions []core.Permission, opts ...core.MutationOption) error // 设置角色权限(覆盖)
+
+	// 角色继承（子角色在租户内拥有父角色的全部权限，权限检查时传递展开）
+	AddRoleInheritance(operatorKey, parentRoleKey, childRoleKey, tenantKey string, opts ...core.MutationOption) error    // 添加角色继承
+	RemoveRoleInheritance(operatorKey, parentRoleKey, childRoleKey, tenantKey string, opts ...core.MutationOption) error // 移除角色继承
+	GetRoleHierarchy(tenantKey string) ([]core.RoleInheritance, error)                                                   // 获取租户内生效的角色继承关系
+
+	// 角色冻结（冻结期间禁止修改角色权限和变更角色分配）
+	LockRole(operatorKey, roleKey, reason string) error // 冻结角色
+	UnlockRole(operatorKey, roleKey string) error       // 解冻角色
+	GetRoleLock(roleKey string) (*core.RoleLock, error) // 获取角色冻结信息，未冻结时返回 nil
+
+	// 跨租户角色共享（链接的租户只读使用，来源角色变更自动生效）
+	PublishRole(operatorKey, roleKey string) error                 // 发布共享角色（需要全局角色管理权限）
+	UnpublishRole(operatorKey, roleKey string, force bool) error   // 取消发布共享角色（存在租户链接时需 force）
+	LinkSharedRole(operatorKey, roleKey, tenantKey string) error   // 将共享角色链接到租户
+	UnlinkSharedRole(operatorKey, roleKey, tenantKey string) error // 取消共享角色链接
+	ListSharedRoles() ([]*core.SharedRole, error)                  // 获取所有共享角色
 
 	// 角色用户管理
-	GetUsersWithRole(roleKey, tenantKey string) ([]string, error)           // 获取拥有指定角色的用户列表
+	GetUsersWithRole(roleKey, tenantKey string) ([]string, error)                                                      // 获取拥有指定角色的用户列表
+	GetUsersWithPermission(tenantKey string, permission core.Permission) ([]string, error)                             // 获取在租户内拥有指定权限的用户列表（直接授权和角色授权，含角色继承）
+	ListGroupingPolicies(tenantKey string) (*core.ListResult[core.GroupingPolicy], error)                              // 获取指定租户的所有角色分配（携带生成时间和策略版本）
+	ListGroupingPoliciesPage(tenantKey string, page core.PageRequest) (*core.PageResponse[core.GroupingPolicy], error) // 分页获取指定租户的角色分配
+
+	// Deprecated: 返回的裸切片不携带策略版本，调用方无法判断结果是否过期，请使用 ListGroupingPolicies
 	GetAllGroupingPolicies(tenantKey string) ([]core.GroupingPolicy, error) // 获取指定租户的所有角色分配
+}
 
-	// 权限检查 (包括用户直接权限和通过角色继承的权限)
-	CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error)     // 检查用户权限(含角色继承)
-	HasDirectPermission(userKey, tenantKey string, permission core.Permission) (bool, error) // 检查用户直接权限(不含角色)
-	HasRole(userKey, roleKey, tenantKey string) (bool, error)                                // 检查用户是否拥有角色
+// TenantAdmin 租户管理能力
+type TenantAdmin interface {
+	// 租户初始化
+	InitializeTenant(tenantKey, adminUserKey, adminRoleKey string, opts ...core.MutationOption) error // 初始化租户并分配管理员
 
-	// 批量权限检查
-	CheckMultiplePermissions(userKey, tenantKey string, permissions []core.Permission) ([]bool, error) // 批量检查权限
-	HasAnyPermission(userKey, tenantKey string, permissions []core.Permission) (bool, error)           // 检查是否拥有任意一个权限
-	HasAllPermissions(userKey, tenantKey string, permissions []core.Permission) (bool, error)          // 检查是否拥有所有权限
+	// 租户开通
+	ProvisionTenants(operatorKey string, specs []core.TenantSpec) ([]core.TenantProvisionResult, error) // 批量开通租户（登记租户、实例化角色模板、分配管理员）
+	GetTenant(tenantKey string) (*core.Tenant, error)                                                   // 获取已登记的租户信息
+	ListTenants() ([]*core.Tenant, error)                                                               // 获取所有租户（已登记的租户及策略、角色中出现的租户）
+	GetTenantStats(tenantKey string) (*core.TenantStats, error)                                         // 获取租户的用户、角色、直接权限和角色分配数量
+	DeleteTenant(operatorKey, tenantKey string) (*core.TenantDeletionResult, error)                     // 删除租户及其全部授权、角色和元数据（需要全局租户删除权限）
 
-	// 资源和租户访问检查
-	CanAccessResource(userKey, tenantKey string, resource core.Resource) (bool, error)            // 检查是否可访问资源(任意操作)
-	CanAccessTenant(userKey, tenantKey string) (bool, error)                                      // 检查是否可访问租户
-	GetAvailableActions(userKey, tenantKey string, resource core.Resource) ([]core.Action, error) // 获取用户对资源的可用操作
-	GetUserTenants(userKey string) ([]string, error)                                              // 获取用户可访问的租户列表
+	// 租户封锁（数据事故应急，封锁期间仅放行持有指定角色的用户）
+	SetTenantLockdown(operatorKey, tenantKey string, allowedRoles []string) error // 封锁租户
+	ClearTenantLockdown(operatorKey, tenantKey string) error                      // 解除租户封锁
+	GetTenantLockdown(tenantKey string) (*core.TenantLockdown, error)             // 获取租户封锁状态
 
-	// 租户初始化
-	InitializeTenant(tenantKey, adminUserKey, adminRoleKey string) error // 初始化租户并分配管理员
+	// 租户别名（租户改名后旧键下的授权继续生效，可择机物理迁移）
+	AddTenantAlias(operatorKey, aliasKey, tenantKey string) error                              // 添加租户别名（旧租户键 -> 新租户键）
+	RemoveTenantAlias(operatorKey, aliasKey string) error                                      // 移除租户别名
+	ListTenantAliases(tenantKey string) ([]core.TenantAlias, error)                            // 获取指向租户的所有别名
+	MigrateTenantAlias(operatorKey, aliasKey string) (*core.TenantAliasMigrationResult, error) // 将别名下的授权物理改写到新租户键
+}
+
+// TenantClient 绑定到单个租户的客户端
+// 方法与 CasbinX 中的同名方法相同但省略 tenantKey 参数，避免参数错位造成跨租户操作；
+// 不包含跨租户分配和角色定义变更等可能影响其他租户的操作
+type TenantClient interface {
+	TenantKey() string                            // 绑定的租户
+	WithContext(ctx context.Context) TenantClient // 返回在数据库操作中遵循 ctx 的副本
+
+	// 权限检查
+	CheckPermission(userKey string, permission core.Permission) (bool, error)                             // 检查用户权限(含角色继承)
+	CheckPermissionVersioned(userKey string, permission core.Permission) (*core.CheckResult, error)       // 检查用户权限并返回检查时的策略修订号
+	HasDirectPermission(userKey string, permission core.Permission) (bool, error)                         // 检查用户直接权限(不含角色)
+	HasRole(userKey, roleKey string) (bool, error)                                                        // 检查用户是否拥有角色
+	CheckPermissionDebug(userKey string, permission core.Permission) (*core.CheckTrace, error)            // 检查用户权限并返回评估过程
+	ExplainPermission(userKey string, permission core.Permission) (*core.PermissionExplanation, error)    // 检查用户权限并说明授权链路
+	EnforceWithAttributes(userKey string, permission core.Permission, attrs map[string]any) (bool, error) // 检查用户权限并评估规则条件
+	CheckMultiplePermissions(userKey string, permissions []core.Permission) ([]bool, error)               // 批量检查权限
+	HasAnyPermission(userKey string, permissions []core.Permission) (bool, error)                         // 检查是否拥有任意一个权限
+	HasAllPermissions(userKey string, permissions []core.Permission) (bool, error)                        // 检查是否拥有所有权限
+	CanAccessResource(userKey string, resource core.Resource) (bool, error)                               // 检查是否可访问资源(任意操作)
+	CanAccessTenant(userKey string) (bool, error)                                                         // 检查是否可访问租户
+	GetAvailableActions(userKey string, resource core.Resource) ([]core.Action, error)                    // 获取用户对资源的可用操作
+
+	// 用户权限管理
+	GrantPermission(operatorKey, userKey string, permission core.Permission, opts ...core.MutationOption) error                               // 授予用户权限
+	RevokePermission(operatorKey, userKey string, permission core.Permission, opts ...core.MutationOption) error                              // 撤销用户权限
+	GrantTemporaryPermission(operatorKey, userKey string, permission core.Permission, expiresAt time.Time, opts ...core.MutationOption) error // 授予用户限时权限
+	GrantPermissions(operatorKey, userKey string, permissions []core.Permission, opts ...core.MutationOption) error                           // 批量授予用户权限
+	RevokePermissions(operatorKey, userKey string, permissions []core.Permission, opts ...core.MutationOption) error                          // 批量撤销用户权限
+	ClearUserPermissions(operatorKey, userKey string, opts ...core.MutationOption) error                                                      // 清除用户在租户内的所有权限
+	GetDirectPermissionsSecure(operatorKey, userKey string) ([]core.Permission, error)                                                        // 安全查询用户直接权限
+	GetEffectivePermissionsSecure(operatorKey, userKey string) ([]core.Permission, error)                                                     // 安全查询用户有效权限
+	GetUserPermissionsByResourceSecure(operatorKey, userKey, resource string) ([]core.Permission, error)                                      // 安全查询用户对特定资源的权限
+
+	// 用户角色分配
+	AssignRole(operatorKey, userKey, roleKey string, opts ...core.MutationOption) error                                                 // 为用户分配角色
+	AssignTemporaryRole(operatorKey, userKey, roleKey string, expiresAt time.Time, opts ...core.MutationOption) error                   // 为用户分配限时角色
+	RemoveRole(operatorKey, userKey, roleKey string, opts ...core.MutationOption) error                                                 // 移除用户角色
+	AssignRoleToUsers(operatorKey, roleKey string, userKeys []string, opts ...core.MutationOption) ([]core.RoleAssignmentResult, error) // 批量为用户分配角色
+	GetUserRolesSecure(operatorKey, userKey string) ([]string, error)                                                                   // 安全查询用户角色列表
+
+	// 租户内查询
+	ListRoles(filter *core.RoleFilter) ([]*core.Role, error)                                // 获取租户的角色列表
+	GetUsersWithRole(roleKey string) ([]string, error)                                      // 获取拥有指定角色的用户列表
+	GetUsersWithPermission(permission core.Permission) ([]string, error)                    // 获取拥有指定权限的用户列表
+	ListGroupingPolicies() (*core.ListResult[core.GroupingPolicy], error)                   // 获取租户的所有角色分配
+	GetRoleHierarchy() ([]core.RoleInheritance, error)                                      // 获取租户内生效的角色继承关系
+	ListTenantMembers(page core.PageRequest) (*core.PageResponse[core.TenantMember], error) // 分页获取租户成员
+}
+
+// CasbinXV2 使用强类型标识的权限管理接口
+// 操作者、用户、角色、租户分别使用 core.OperatorKey、core.UserKey、core.RoleKey、core.TenantKey，
+// 参数错位在编译期报错；通过 NewV2 基于 CasbinX 创建，未覆盖的能力通过 V1 使用原接口
+type CasbinXV2 interface {
+	V1() CasbinX                                  // 返回底层的 CasbinX
+	ForTenant(tenant core.TenantKey) TenantClient // 返回绑定到租户的客户端
+
+	// 权限检查
+	CheckPermission(user core.UserKey, tenant core.TenantKey, permission core.Permission) (bool, error)                          // 检查用户权限(含角色继承)
+	HasDirectPermission(user core.UserKey, tenant core.TenantKey, permission core.Permission) (bool, error)                      // 检查用户直接权限(不含角色)
+	HasRole(user core.UserKey, role core.RoleKey, tenant core.TenantKey) (bool, error)                                           // 检查用户是否拥有角色
+	ExplainPermission(user core.UserKey, tenant core.TenantKey, permission core.Permission) (*core.PermissionExplanation, error) // 检查用户权限并说明授权链路
+	CheckMultiplePermissions(user core.UserKey, tenant core.TenantKey, permissions []core.Permission) ([]bool, error)            // 批量检查权限
+	GetUserTenants(user core.UserKey) ([]core.TenantKey, error)                                                                  // 获取用户可访问的租户列表
+
+	// 用户权限管理
+	GrantPermission(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey, permission core.Permission, opts ...core.MutationOption) error                               // 授予用户权限
+	RevokePermission(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey, permission core.Permission, opts ...core.MutationOption) error                              // 撤销用户权限
+	GrantTemporaryPermission(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey, permission core.Permission, expiresAt time.Time, opts ...core.MutationOption) error // 授予用户限时权限
+	GetEffectivePermissionsSecure(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey) ([]core.Permission, error)                                                     // 安全查询用户有效权限
+
+	// 用户角色分配
+	AssignRole(operator core.OperatorKey, user core.UserKey, role core.RoleKey, tenant core.TenantKey, opts ...core.MutationOption) error                                          // 为用户分配角色
+	AssignTemporaryRole(operator core.OperatorKey, user core.UserKey, role core.RoleKey, tenant core.TenantKey, expiresAt time.Time, opts ...core.MutationOption) error            // 为用户分配限时角色
+	RemoveRole(operator core.OperatorKey, user core.UserKey, role core.RoleKey, tenant core.TenantKey, opts ...core.MutationOption) error                                          // 移除用户角色
+	AssignRoleToUsers(operator core.OperatorKey, role core.RoleKey, tenant core.TenantKey, users []core.UserKey, opts ...core.MutationOption) ([]core.RoleAssignmentResult, error) // 批量为用户分配角色
+	GetUserRolesSecure(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey) ([]core.RoleKey, error)                                                                // 安全查询用户角色列表
+
+	// 角色管理
+	CreateRole(operator core.OperatorKey, role core.RoleKey, roleName, description string, tenant core.TenantKey, permissions []core.Permission, opts ...core.MutationOption) error // 创建角色
+	DeleteRole(role core.RoleKey, opts ...core.MutationOption) error                                                                                                                // 删除角色
+	GrantRolePermission(operator core.OperatorKey, role core.RoleKey, permission core.Permission, opts ...core.MutationOption) error                                                // 授予角色权限
+	RevokeRolePermission(operator core.OperatorKey, role core.RoleKey, permission core.Permission, opts ...core.MutationOption) error                                               // 撤销角色权限
+	GetUsersWithRole(role core.RoleKey, tenant core.TenantKey) ([]core.UserKey, error)                                                                                              // 获取拥有指定角色的用户列表
+	GetUsersWithPermission(tenant core.TenantKey, permission core.Permission) ([]core.UserKey, error)                                                                               // 获取在租户内拥有指定权限的用户列表
+}
+
+// CasbinX CasbinX权限管理引擎接口
+// 由上述按能力划分的小接口组合而成，调用方可以只依赖所需的子接口
+type CasbinX interface {
+	PermissionChecker
+	UserPermissionAdmin
+	RoleAdministrator
+	TenantAdmin
+
+	// 调用上下文（返回的副本在数据库操作中遵循 ctx 的取消与超时）
+	WithContext(ctx context.Context) CasbinX
+
+	// 补偿执行（fn 返回错误时以反向写入撤销本次写入的权限策略、角色分配、角色元数据和附属数据，结束后只发布一次同步通知）
+	// 不是原子事务：写入立即生效并对其他实例可见，撤销失败时只部分撤销并返回 core.ErrTxRollbackIncomplete
+	WithCompensation(fn func(tx CasbinX) error) error
+
+	// 租户绑定（返回的客户端省略 tenantKey 参数，所有操作都在该租户内执行）
+	ForTenant(tenantKey string) TenantClient
+
+	// 分页浏览（响应携带生成时间、策略版本和修订号，翻页过程中版本变化说明策略已变更）
+	GetPolicyVersion() (string, error)                                                                        // 获取当前策略版本（由策略内容计算，相同策略的实例版本相同）
+	ListTenantMembers(tenantKey string, page core.PageRequest) (*core.PageResponse[core.TenantMember], error) // 分页获取租户成员
+	ListPolicies(tenantKey string, page core.PageRequest) (*core.PageResponse[core.Policy], error)            // 分页浏览权限策略
+
+	// 按资源批量撤销（功能下线时撤销租户内所有用户和角色对该资源的授权）
+	PreviewResourceRevocation(operatorKey, tenantKey string, resource core.Resource) (*core.ResourceRevocation, error) // 预览受影响的策略、用户和角色
+	RevokeResourceFromTenant(operatorKey, tenantKey string, resource core.Resource) (*core.ResourceRevocation, error)  // 批量撤销并记录撤销结果
+
+	// 变更模拟（预览变更对有效授权的影响，不写入策略）
+	SimulateGrant(operatorKey, userKey, tenantKey string, permission core.Permission) (*core.PermissionSimulation, error) // 预览授予用户权限后的授权变化
+	SimulateRoleUpdate(operatorKey, roleKey string, permissions []core.Permission) (*core.PermissionSimulation, error)    // 预览设置角色权限后所有受影响用户的授权变化
+
+	// 授权分析（找出可以清理的直接授权）
+	AnalyzeRedundantGrants(tenantKey string) (*core.GrantAnalysis, error) // 列出已被用户角色覆盖的直接授权及处理建议
+
+	// 对象级权限（模板如 "project:{id}:read"，按对象实例化）
+	GrantObjectPermissions(operatorKey, userKey, tenantKey string, template core.ObjectPermissionTemplate, objectID string) error                      // 为用户授予对象的整组权限（对象创建时）
+	RevokeObjectPermissions(operatorKey, userKey, tenantKey string, template core.ObjectPermissionTemplate, objectID string) error                     // 撤销用户对对象的整组权限
+	PurgeObjectPermissions(operatorKey, tenantKey string, template core.ObjectPermissionTemplate, objectID string) ([]*core.ResourceRevocation, error) // 撤销所有主体对对象资源的授权（对象删除时）
+
+	// 对象生命周期（应用删除实体时一次性清理其所有权限及附属数据）
+	CleanupObjectPermissions(resourceType, objectID string) (*core.ObjectCleanupResult, error) // 删除所有租户中对象（含子资源）的权限策略并执行清理回调
+	RegisterObjectCleanupHook(hook core.ObjectCleanupHook)                                     // 注册对象清理回调（清理应用侧的分享、归属等记录）
+
+	// 分享邀请（"通过链接分享"，接受邀请即获得整组权限并记录授权来源）
+	CreateShareInvitation(operatorKey, tenantKey string, permissions []core.Permission, expiry time.Duration, maxUses int) (*core.ShareInvitation, error) // 创建分享邀请，返回包含令牌的邀请
+	AcceptInvitation(userKey, token string) (*core.ShareInvitation, error)                                                                                // 接受分享邀请并授予权限
+	RevokeShareInvitation(operatorKey, invitationID string) error                                                                                         // 撤销分享邀请（已授予的权限保留）
+	GetInvitationGrants(operatorKey, userKey, tenantKey string) ([]core.InvitationGrant, error)                                                           // 查询用户通过邀请获得的授权来源
+
+	// 规则优先级（数值越小越优先，未设置时为 core.DefaultRulePriority）
+	SetGrantPriority(operatorKey, userKey, tenantKey string, permission core.Permission, priority int) error // 设置用户直接授权的优先级
+	SetRolePermissionPriority(operatorKey, roleKey string, permission core.Permission, priority int) error   // 设置角色授权的优先级
+	ReorderRolePermissions(operatorKey, roleKey string, permissions []core.Permission) error                 // 按给定顺序重新设置角色授权的优先级
+
+	// 规则条件（附加条件的授权只在 EnforceWithAttributes 中条件成立时生效，普通检查视为不生效）
+	SetGrantCondition(operatorKey, userKey, tenantKey string, permission core.Permission, expression string) error // 设置用户直接授权的条件，表达式为空时清除
+	SetRolePermissionCondition(operatorKey, roleKey string, permission core.Permission, expression string) error   // 设置角色授权的条件，表达式为空时清除
+
+	// 角色权限灰度变更（按用户百分比或租户白名单在检查时生效，之后全量发布或回滚）
+	StageRolePermissionChange(operatorKey, roleKey string, permission core.Permission, change core.RolloutChange, target core.RolloutTarget) (*core.PolicyRollout, error) // 创建灰度变更
+	UpdateRolloutTarget(operatorKey string, rolloutID int64, target core.RolloutTarget) error                                                                             // 调整灰度范围
+	PromoteRollout(operatorKey string, rolloutID int64) error                                                                                                             // 全量发布：写入角色权限并结束灰度
+	RollbackRollout(operatorKey string, rolloutID int64) error                                                                                                            // 回滚：角色权限保持不变并结束灰度
+	ListStagedRollouts(roleKey string) ([]*core.PolicyRollout, error)                                                                                                     // 获取进行中的灰度变更
+
+	// 自定义资源操作（租户在资源上定义内置操作之外的操作，授权时校验操作已定义）
+	DefineResourceAction(operatorKey string, definition core.ResourceAction) error                        // 定义自定义操作（TenantKey 为 * 时对所有租户生效）
+	RemoveResourceAction(operatorKey, tenantKey string, resource core.Resource, action core.Action) error // 删除自定义操作（仍有授权引用时拒绝）
+	GetResourceActions(tenantKey string, resource core.Resource) ([]core.Action, error)                   // 获取租户内资源的可用操作（内置操作和自定义操作）
+	ListResourceActions(tenantKey string) ([]*core.ResourceAction, error)                                 // 获取在租户内生效的自定义操作定义
+
+	// 环境授权（Config.Environment 设置后，标记了环境的授权只在匹配的环境中生效）
+	GetGrantEnvironments(subjectKey, tenantKey string, permission core.Permission) ([]string, error) // 获取授权生效的环境列表
 
 	// Watcher 管理
-	RefreshPolicy() error // 手动刷新策略（从数据库重新加载）
+	RefreshPolicy() error                                   // 手动刷新策略（从数据库重新加载）
+	ExportPolicies(w io.Writer) error                       // 导出策略快照（配合 offline 包离线校验）
+	GetReloadStats() core.ReloadStats                       // 获取同步通知触发的策略重载统计（队列深度、最近重载时间等）
+	Health() core.HealthStatus                              // 获取健康状态（最近一次重载结果、容错加载隔离的策略行数量、Watcher 连接状态）
+	ListQuarantinedRules() ([]*core.QuarantinedRule, error) // 获取容错加载隔离的策略行
+	CleanupPlaceholders() (int, error)                      // 清理多余的角色占位策略，返回清理数量
+	BatchNotifications(fn func() error) error               // 在 fn 执行期间暂停同步通知，结束后只发布一次（用于批量变更）
+
+	// 决策日志（Config.DecisionLog.Enabled 启用后，权限检查结果异步批量写入数据库）
+	GetDecisionLogStats() core.DecisionLogStats // 获取决策日志缓冲统计（待写入数量、丢弃数量等）
+	FlushDecisionLog() error                    // 立即写入缓冲中的决策日志
+
+	// OPA 导出（供采用 OPA 的团队复用租户的角色和授权）
+	ExportToRego(tenantKey string) (*core.RegoExport, error) // 导出 Rego 模块和数据文档，并报告无法翻译的结构
+
+	// Zanzibar 关系元组（与 SpiceDB 等基于关系的系统互通）
+	ExportRelationTuples(tenantKey string) ([]core.RelationTuple, error)                                      // 导出角色分配、用户权限和角色权限（tenantKey 为空表示所有租户）
+	ImportRelationTuples(operatorKey string, tuples []core.RelationTuple) (*core.RelationImportResult, error) // 导入为授权和角色分配（逐条校验操作者权限）
+
+	// 流式导出（逐条回调，适用于规则数量巨大的租户）
+	StreamPolicies(tenantKey string, fn func(core.Policy) error) error                 // 逐条导出权限策略（tenantKey 为空表示所有租户）
+	StreamGroupingPolicies(tenantKey string, fn func(core.GroupingPolicy) error) error // 逐条导出角色分配和角色继承
+
+	// 策略备份与恢复（租户快照可用于跨环境迁移或克隆租户）
+	ExportTenantPolicies(tenantKey string) (*core.PolicySnapshot, error)                                                                    // 导出租户的角色、直接权限、角色分配和继承
+	ImportTenantPolicies(operatorKey string, snapshot core.PolicySnapshot, opts core.PolicyImportOptions) (*core.PolicyImportResult, error) // 按 merge/replace 导入快照（需要全局租户管理权限，支持预演）
+
+	// 审计日志（授权、撤销、角色分配与移除、角色创建与删除成功后写入 casbin_audit 表）
+	ListAuditEvents(operatorKey string, filter core.AuditFilter, page core.PageRequest) (*core.PageResponse[core.PermissionChange], error) // 按条件分页查询变更记录
+	GenerateChangeDigest(operatorKey, tenantKey string, since, until time.Time) (*core.ChangeDigest, error)                                // 按需生成租户的变更摘要（定时投递见 Config.Digest）
+	StreamAuditEvents(operatorKey string, filter core.AuditFilter, fn func(core.PermissionChange) error) error                             // 按写入顺序流式导出变更记录（分批读取，回调处理完才读取下一批）
+
+	// 主体命名空间（Config.SubjectNamespaces 启用后执行一次迁移）
+	MigrateSubjectNamespaces() (*core.SubjectMigrationReport, error) // 将存量策略的主体改写为 user:/role: 前缀格式
+
+	// 异步任务（耗时的批量操作在后台执行，任意实例都可以查询进度和请求取消）
+	SubmitJob(operatorKey string, spec core.JobSpec) (*core.Job, error) // 提交任务并立即返回（提交时校验操作者权限）
+	GetJobStatus(jobID string) (*core.Job, error)                       // 获取任务状态、进度和结果
+	CancelJob(operatorKey, jobID string) (*core.Job, error)             // 请求取消任务（提交者或全局系统管理员），已完成的部分不回滚
 }
 
 // NewCasbinx 创建CasbinX权限管理引擎
-func NewCasbinx(c core.Config) (CasbinX, error) {
-	return newCasbinxClient(c)
+// opts 用于替换或扩展默认组件，如 WithLogger、WithCache、WithHook、WithAdapter、WithWatcher
+func NewCasbinx(c core.Config, opts ...Option) (CasbinX, error) {
+	return newCasbinxClient(c, opts...)
 }
+
+// NewCasbinxWithDB 使用应用已有的 GORM 连接创建引擎，无需配置 Config.Dsn
+// 策略表和元数据表共享该连接的连接池；元数据表使用 PostgreSQL 语法，db 不是 PostgreSQL 连接时返回错误，
+// 其他数据库（如 MySQL、SQLite）只能通过 WithGormDB 存储策略
+func NewCasbinxWithDB(db *gorm.DB, c core.Config, opts ...Option) (CasbinX, error) {
+	if err := core.CheckArg(db != nil, "db", core.ConstraintRequired); err != nil {
+		return nil, err
+	}
+	if dialect := db.Dialector.Name(); dialect != "postgres" {
+		return nil, fmt.Errorf("元数据表使用 PostgreSQL 语法，NewCasbinxWithDB 需要 PostgreSQL 连接（当前为 %s），其他数据库请通过 WithGormDB 只存储策略", dialect)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取 GORM 底层连接失败: %v", err)
+	}
+
+	// 注入的连接放在最前，调用方显式传入的选项优先
+	opts = append([]Option{WithGormDB(db), WithSqlConn(sqlx.NewSqlConnFromDB(sqlDB))}, opts...)
+	return newCasbinxClient(c, opts...)
+}
+
+// 编译期确认客户端实现了完整接口（也就同时实现了各个子接口）
+var _ CasbinX = (*casbinxClient)(nil)
+
+var _ TenantClient = (*tenantClient)(nil)
+
+var _ CasbinXV2 = (*clientV2)(nil)
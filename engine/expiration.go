@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// expiredGrantReason 过期授权自动撤销时写入审计日志的原因
+const expiredGrantReason = "限时授权到期自动撤销"
+
+// GrantTemporaryPermission 授予用户在 expiresAt 过期的权限（如外包人员的临时访问）
+// 校验规则与 GrantPermission 相同；用户已拥有该权限时改为在 expiresAt 过期。
+// 过期后权限检查立即忽略该授权（已缓存的检查结果在后台清理撤销策略时失效），策略由后台清理按 Config.Expiration.ReapInterval 撤销
+func (c *casbinxClient) GrantTemporaryPermission(operatorKey, userKey, tenantKey string, permission core.Permission, expiresAt time.Time, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("GrantTemporaryPermission", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "GrantTemporaryPermission", []string{operatorKey, userKey, tenantKey, permission.String(), expiresAt.UTC().Format(time.RFC3339Nano)}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("userKey", userKey),
+			core.RequireArg("tenantKey", tenantKey),
+			core.CheckArg(expiresAt.After(time.Now()), "expiresAt", core.ConstraintRange),
+		); err != nil {
+			return err
+		}
+
+		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		// 先写入过期时间再授权，保证授权生效时过期时间已经存在
+		if err := c.expirations.SetPermissionExpiry(c.ctx, operatorKey, userKey, tenantKey, permission, expiresAt); err != nil {
+			return err
+		}
+
+		environments := core.ApplyMutationOptions(opts).Environments
+		if err := c.environments.SetPolicyEnvironments(c.ctx, userKey, tenantKey, permission, environments); err != nil {
+			return err
+		}
+
+		if err := c.userManager.GrantPermission(c.ctx, operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionGrant, Target: permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
+}
+
+// AssignTemporaryRole 为用户分配在 expiresAt 过期的角色
+// 校验规则与 AssignRole 相同；用户已拥有该角色时改为在 expiresAt 过期
+func (c *casbinxClient) AssignTemporaryRole(operatorKey, userKey, roleKey, tenantKey string, expiresAt time.Time, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("AssignTemporaryRole", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "AssignTemporaryRole", []string{operatorKey, userKey, roleKey, tenantKey, expiresAt.UTC().Format(time.RFC3339Nano)}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("userKey", userKey),
+			core.RequireArg("roleKey", roleKey),
+			core.RequireArg("tenantKey", tenantKey),
+			core.CheckArg(expiresAt.After(time.Now()), "expiresAt", core.ConstraintRange),
+		); err != nil {
+			return err
+		}
+
+		if err := c.validateRoleAssignment(operatorKey, roleKey, tenantKey); err != nil {
+			return err
+		}
+
+		if err := c.expirations.SetRoleExpiry(c.ctx, operatorKey, userKey, roleKey, tenantKey, expiresAt); err != nil {
+			return err
+		}
+
+		if err := c.userManager.AssignRole(c.ctx, operatorKey, userKey, roleKey, tenantKey); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionAssign, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
+}
+
+// ListExpiringGrants 按条件查询限时授权（按过期时间排序），包含已过期、尚未被后台清理撤销的授权
+func (c *casbinxClient) ListExpiringGrants(filter core.ExpiringGrantFilter) (_ []*core.ExpiringGrant, err error) {
+	defer c.guard.recover("ListExpiringGrants", &err)
+	return c.expirations.ListExpiring(c.ctx, filter)
+}
+
+// startExpirationReaper 启动过期授权的后台清理（不受调用上下文的取消和超时影响）
+func (c *casbinxClient) startExpirationReaper(config core.ExpirationConfig) {
+	if config.Disabled {
+		return
+	}
+	interval := config.ReapInterval
+	if interval <= 0 {
+		interval = core.DefaultExpirationReapInterval
+	}
+
+	reaper := *c
+	reaper.ctx = context.Background()
+	go reaper.runExpirationReaper(interval)
+}
+
+// runExpirationReaper 后台清理循环：按间隔撤销已过期的授权
+func (c *casbinxClient) runExpirationReaper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.reapExpiredGrants()
+	}
+}
+
+// reapExpiredGrants 撤销所有已过期的授权，全部撤销只发布一次同步通知
+func (c *casbinxClient) reapExpiredGrants() {
+	grants, err := c.expirations.ListExpiring(c.ctx, core.ExpiringGrantFilter{Before: time.Now()})
+	if err != nil {
+		c.guard.logger.Error("查询已过期授权失败", "error", err)
+		return
+	}
+	if len(grants) == 0 {
+		return
+	}
+
+	_ = c.enforcer.BatchNotifications(func() error {
+		for _, grant := range grants {
+			if err := c.revokeExpiredGrant(grant); err != nil {
+				c.guard.logger.Warn("撤销已过期授权失败", "kind", grant.Kind, "user", grant.UserKey, "tenant", grant.TenantKey, "role", grant.RoleKey, "permission", grant.Permission.String(), "error", err)
+			}
+		}
+		return nil
+	})
+}
+
+// revokeExpiredGrant 撤销已过期的授权
+// 先删除过期记录认领该授权（已续期或已被其他实例撤销时跳过），撤销失败时恢复记录，下一轮清理重试
+func (c *casbinxClient) revokeExpiredGrant(grant *core.ExpiringGrant) error {
+	return c.runQueued(c.enforcer.UserSubject(grant.UserKey), nil, "RevokeExpiredGrant", []string{grant.UserKey, grant.TenantKey, string(grant.Kind)}, func() error {
+		claimed, err := c.expirations.DeleteExpired(c.ctx, *grant)
+		if err != nil || !claimed {
+			return err
+		}
+
+		change := core.PermissionChange{UserKey: grant.UserKey, TenantKey: grant.TenantKey, OperatorKey: grant.OperatorKey}
+		switch grant.Kind {
+		case core.ExpiringPermission:
+			err = c.revokeExpiredPermission(grant)
+			change.Action, change.Target = core.AuditActionRevoke, grant.Permission.String()
+		case core.ExpiringRole:
+			err = c.userManager.RemoveRole(c.ctx, grant.OperatorKey, grant.UserKey, grant.RoleKey, grant.TenantKey)
+			change.Action, change.Target = core.AuditActionRemove, grant.RoleKey
+		default:
+			return core.NewFieldError("grant.kind", core.ConstraintOneOf)
+		}
+
+		if err != nil {
+			if restoreErr := c.restoreExpiry(grant); restoreErr != nil {
+				return fmt.Errorf("%w（恢复过期记录失败: %v）", err, restoreErr)
+			}
+			return err
+		}
+
+		c.recordAudit([]core.MutationOption{core.WithReason(expiredGrantReason)}, change)
+		return nil
+	})
+}
+
+// revokeExpiredPermission 撤销过期的用户直接权限并清理其附属状态（与 RevokePermission 一致）
+func (c *casbinxClient) revokeExpiredPermission(grant *core.ExpiringGrant) error {
+	if err := c.userManager.RevokePermission(c.ctx, grant.OperatorKey, grant.UserKey, grant.TenantKey, grant.Permission); err != nil {
+		return err
+	}
+	if err := c.priorities.ClearPolicyPriority(c.ctx, core.SubjectKindUser, grant.UserKey, grant.TenantKey, grant.Permission); err != nil {
+		return err
+	}
+	if err := c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindUser, grant.UserKey, grant.TenantKey, grant.Permission); err != nil {
+		return err
+	}
+	return c.environments.ClearPolicyEnvironments(c.ctx, grant.UserKey, grant.TenantKey, grant.Permission)
+}
+
+// restoreExpiry 恢复已删除的过期记录，使授权在权限检查中继续被排除
+func (c *casbinxClient) restoreExpiry(grant *core.ExpiringGrant) error {
+	if grant.Kind == core.ExpiringRole {
+		return c.expirations.SetRoleExpiry(c.ctx, grant.OperatorKey, grant.UserKey, grant.RoleKey, grant.TenantKey, grant.ExpiresAt)
+	}
+	return c.expirations.SetPermissionExpiry(c.ctx, grant.OperatorKey, grant.UserKey, grant.TenantKey, grant.Permission, grant.ExpiresAt)
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"context"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormContextAdapter 补充 GORM 适配器缺少的批量添加和批量更新 Ctx 方法，其余 Ctx 方法由 GORM 适配器提供
+type gormContextAdapter struct {
+	*gormadapter.Adapter
+}
+
+// AddPoliciesCtx 批量添加策略，已存在的行跳过
+func (a gormContextAdapter) AddPoliciesCtx(ctx context.Context, sec string, ptype string, rules [][]string) error {
+	lines := make([]gormadapter.CasbinRule, 0, len(rules))
+	for _, rule := range rules {
+		lines = append(lines, gormRule(ptype, rule))
+	}
+	return a.GetDb().WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&lines).Error
+}
+
+// UpdatePoliciesCtx 在一个数据库事务中批量更新策略
+func (a gormContextAdapter) UpdatePoliciesCtx(ctx context.Context, sec string, ptype string, oldRules, newRules [][]string) error {
+	return a.GetDb().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range oldRules {
+			oldLine, newLine := gormRule(ptype, oldRules[i]), gormRule(ptype, newRules[i])
+			if err := tx.Model(&oldLine).Where(&oldLine).Updates(newLine).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// gormRule 将策略转换为 GORM 适配器的策略行
+func gormRule(ptype string, rule []string) gormadapter.CasbinRule {
+	line := gormadapter.CasbinRule{Ptype: ptype}
+	fields := []*string{&line.V0, &line.V1, &line.V2, &line.V3, &line.V4, &line.V5}
+	for i := 0; i < len(rule) && i < len(fields); i++ {
+		*fields[i] = rule[i]
+	}
+	return line
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// openSQLite 打开 SQLite 内存数据库（单连接，保证所有操作访问同一个内存库）
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB() error = %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+// newSQLiteEnforcer 按引擎的方式在 db 上创建策略存储和核心执行器
+func newSQLiteEnforcer(t *testing.T, db *gorm.DB) *core.Enforcer {
+	t.Helper()
+	m, err := model.NewModelFromString(core.DefaultModelText)
+	if err != nil {
+		t.Fatalf("NewModelFromString() error = %v", err)
+	}
+	gormAdapter, err := gormadapter.NewAdapterByDBUseTableName(db, "", "casbin_rules")
+	if err != nil {
+		t.Fatalf("NewAdapterByDBUseTableName() error = %v", err)
+	}
+	contextAdapter := core.NewContextAdapter(gormContextAdapter{Adapter: gormAdapter})
+	casbinEnforcer, err := casbin.NewEnforcer(m, contextAdapter)
+	if err != nil {
+		t.Fatalf("casbin.NewEnforcer() error = %v", err)
+	}
+	casbinEnforcer.EnableAutoSave(true)
+	e, err := core.NewEnforcer(casbinEnforcer)
+	if err != nil {
+		t.Fatalf("core.NewEnforcer() error = %v", err)
+	}
+	e.SetContextAdapter(contextAdapter)
+	return e
+}
+
+// storedRules 读取策略表中的所有规则，格式为 ptype|v0|v1|...
+func storedRules(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var lines []gormadapter.CasbinRule
+	if err := db.Table("casbin_rules").Find(&lines).Error; err != nil {
+		t.Fatalf("读取策略表失败: %v", err)
+	}
+	rules := make([]string, 0, len(lines))
+	for _, line := range lines {
+		fields := []string{line.Ptype, line.V0, line.V1, line.V2, line.V3}
+		rules = append(rules, strings.TrimRight(strings.Join(fields, "|"), "|"))
+	}
+	slices.Sort(rules)
+	return rules
+}
+
+func TestSQLitePolicyStorage(t *testing.T) {
+	read := core.Permission{Resource: core.ResourceUser, Action: core.ActionRead}
+	write := core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite}
+
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, e *core.Enforcer) error
+		want  []string
+	}{
+		{
+			name: "批量授权",
+			write: func(ctx context.Context, e *core.Enforcer) error {
+				return e.AddPolicies(ctx, "alice", "t1", []core.Permission{read, write})
+			},
+			want: []string{"p|alice|t1|user|read", "p|alice|t1|user|write"},
+		},
+		{
+			name: "撤销授权",
+			write: func(ctx context.Context, e *core.Enforcer) error {
+				if err := e.AddPolicies(ctx, "alice", "t1", []core.Permission{read, write}); err != nil {
+					return err
+				}
+				return e.RemovePolicy(ctx, "alice", "t1", write)
+			},
+			want: []string{"p|alice|t1|user|read"},
+		},
+		{
+			name: "角色分配和主体改名",
+			write: func(ctx context.Context, e *core.Enforcer) error {
+				if err := e.AddPolicy(ctx, "alice", "t1", read); err != nil {
+					return err
+				}
+				if err := e.AddGroupingPolicy(ctx, "alice", "admin", "t1"); err != nil {
+					return err
+				}
+				_, _, err := e.RenameSubject(ctx, "alice", "bob")
+				return err
+			},
+			want: []string{"g|bob|admin|t1", "p|bob|t1|user|read"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openSQLite(t)
+			e := newSQLiteEnforcer(t, db)
+			if err := tt.write(context.Background(), e); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			if got := storedRules(t, db); !slices.Equal(got, tt.want) {
+				t.Fatalf("stored rules = %v, want %v", got, tt.want)
+			}
+
+			// 新执行器从同一数据库加载后规则一致
+			reloaded := newSQLiteEnforcer(t, db)
+			policies, err := reloaded.GetAllPolicies()
+			if err != nil {
+				t.Fatalf("GetAllPolicies() error = %v", err)
+			}
+			groupings, err := reloaded.GetGroupingPolicies()
+			if err != nil {
+				t.Fatalf("GetGroupingPolicies() error = %v", err)
+			}
+			if got := len(policies) + len(groupings); got != len(tt.want) {
+				t.Fatalf("reloaded %d rules, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSQLiteCanceledWrite(t *testing.T) {
+	db := openSQLite(t)
+	e := newSQLiteEnforcer(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := e.AddPolicy(ctx, "alice", "t1", core.Permission{Resource: core.ResourceUser, Action: core.ActionRead})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AddPolicy() error = %v, want %v", err, context.Canceled)
+	}
+	if got := storedRules(t, db); len(got) != 0 {
+		t.Fatalf("stored rules = %v, want none", got)
+	}
+}
+
+func TestNewCasbinxWithDBRequiresPostgres(t *testing.T) {
+	_, err := NewCasbinxWithDB(openSQLite(t), core.Config{})
+	if err == nil || !strings.Contains(err.Error(), "PostgreSQL") {
+		t.Fatalf("NewCasbinxWithDB(sqlite) error = %v, want PostgreSQL required", err)
+	}
+}
+
+func TestWithGormDBRequiresMetadataDsn(t *testing.T) {
+	config := core.Config{Watcher: core.WatcherConfig{Disabled: true}}
+	_, err := NewCasbinx(config, WithGormDB(openSQLite(t)))
+	if err == nil || !strings.Contains(err.Error(), "Config.Dsn") {
+		t.Fatalf("NewCasbinx(WithGormDB(sqlite)) error = %v, want Config.Dsn required", err)
+	}
+}
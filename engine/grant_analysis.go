@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// AnalyzeRedundantGrants 分析租户内用户的直接授权，列出可以清理的授权及处理建议
+// 直接授权已被用户在该租户（含全局域）的角色覆盖时，撤销直接授权不会改变用户的有效授权；
+// 直接授权被拒绝规则覆盖时，授权不生效，与拒绝规则相互矛盾
+func (c *casbinxClient) AnalyzeRedundantGrants(tenantKey string) (_ *core.GrantAnalysis, err error) {
+	defer c.guard.recover("AnalyzeRedundantGrants", &err)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	roles, err := c.roleManager.ListRoles(c.ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	roleKeys := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		roleKeys[role.Key] = true
+	}
+
+	policies, err := c.policyManager.ListPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &core.GrantAnalysis{TenantKey: tenantKey, Findings: []core.GrantFinding{}, GeneratedAt: time.Now()}
+	for _, policy := range policies {
+		// 只分析租户内用户主体的直接授权
+		if policy.Domain != tenantKey || policy.Resource == core.ResourcePlaceholder {
+			continue
+		}
+		if policy.SubjectKind == core.SubjectKindRole || (policy.SubjectKind == "" && roleKeys[policy.Subject]) {
+			continue
+		}
+		analysis.Evaluated++
+
+		if denies := c.enforcer.DenyingRules(policy.Subject, tenantKey, policy.Permission()); len(denies) > 0 {
+			analysis.Findings = append(analysis.Findings, core.GrantFinding{
+				Kind:        core.GrantFindingDenied,
+				UserKey:     policy.Subject,
+				TenantKey:   tenantKey,
+				Permission:  policy.Permission(),
+				Denies:      denies,
+				Remediation: "拒绝规则覆盖该授权，授权不生效：确认需要拒绝时调用 RevokePermission 撤销直接授权，否则调用 RevokeDeny 移除拒绝规则",
+			})
+			continue
+		}
+
+		covering, err := c.enforcer.CoveringRoles(policy.Subject, tenantKey, policy.Permission())
+		if err != nil {
+			return nil, err
+		}
+		if len(covering) == 0 {
+			continue
+		}
+		analysis.Findings = append(analysis.Findings, core.GrantFinding{
+			Kind:        core.GrantFindingRedundant,
+			UserKey:     policy.Subject,
+			TenantKey:   tenantKey,
+			Permission:  policy.Permission(),
+			Roles:       covering,
+			Remediation: fmt.Sprintf("角色 %s 已覆盖该授权，可调用 RevokePermission 撤销直接授权", strings.Join(covering, "、")),
+		})
+	}
+
+	analysis.SortFindings()
+	return analysis, nil
+}
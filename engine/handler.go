@@ -1,583 +1,1217 @@
 package engine
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"slices"
+	"sync"
 
 	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/action"
+	"github.com/rezeropoint/casbinx/internal/audit"
 	"github.com/rezeropoint/casbinx/internal/check"
+	"github.com/rezeropoint/casbinx/internal/condition"
+	"github.com/rezeropoint/casbinx/internal/decision"
+	"github.com/rezeropoint/casbinx/internal/deny"
+	"github.com/rezeropoint/casbinx/internal/digest"
+	"github.com/rezeropoint/casbinx/internal/environment"
+	"github.com/rezeropoint/casbinx/internal/expiration"
+	"github.com/rezeropoint/casbinx/internal/idempotency"
+	"github.com/rezeropoint/casbinx/internal/invitation"
+	"github.com/rezeropoint/casbinx/internal/job"
+	"github.com/rezeropoint/casbinx/internal/jobfunction"
 	"github.com/rezeropoint/casbinx/internal/policy"
+	"github.com/rezeropoint/casbinx/internal/priority"
+	"github.com/rezeropoint/casbinx/internal/quarantine"
 	"github.com/rezeropoint/casbinx/internal/role"
+	"github.com/rezeropoint/casbinx/internal/rollout"
+	"github.com/rezeropoint/casbinx/internal/schedule"
+	"github.com/rezeropoint/casbinx/internal/tenant"
 	"github.com/rezeropoint/casbinx/internal/user"
+	"github.com/rezeropoint/casbinx/internal/watcher"
+	"github.com/rezeropoint/casbinx/offline"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
-	rediswatcher "github.com/casbin/redis-watcher/v2"
-	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 // casbinxClient casbinx客户端实现
 type casbinxClient struct {
-	userManager       user.Manager            // 用户权限管理器
-	roleManager       role.Manager            // 角色权限管理器
-	checkManager      check.Manager           // 权限检查管理器
-	securityValidator *core.SecurityValidator // 安全验证器
-	policyManager     policy.Manager          // 策略管理器
+	enforcer          *core.Enforcer           // 核心执行器
+	userManager       user.Manager             // 用户权限管理器
+	roleManager       role.Manager             // 角色权限管理器
+	checkManager      check.Manager            // 权限检查管理器
+	securityValidator *core.SecurityValidator  // 安全验证器
+	policyManager     policy.Manager           // 策略管理器
+	idempotency       idempotency.Manager      // 幂等键管理器
+	environments      environment.Manager      // 授权环境标记管理器
+	tenantManager     tenant.Manager           // 租户管理器
+	priorities        priority.Manager         // 规则优先级管理器
+	conditions        condition.Manager        // 规则条件管理器
+	decisions         decision.Manager         // 决策日志管理器（未启用时为 nil）
+	invitations       invitation.Manager       // 分享邀请管理器
+	rollouts          rollout.Manager          // 灰度变更管理器
+	reloadCoalescer   *core.ReloadCoalescer    // 策略重载合并器
+	watcher           core.Watcher             // 策略同步 Watcher（关闭 Watcher 时为 nil）
+	ctx               context.Context          // 调用上下文，传递给数据库操作
+	guard             panicGuard               // 公开方法的 panic 恢复守卫
+	mutations         *core.MutationQueue      // 变更串行化队列（未启用时为 nil）
+	audit             audit.Manager            // 权限变更审计日志管理器
+	digests           digest.Manager           // 权限变更摘要管理器
+	securityHooks     []core.SecurityEventHook // 敏感事件回调
+	jobs              job.Manager              // 异步任务管理器
+	runningJobs       *sync.Map                // 本实例执行中的任务ID到取消标记的映射
+	actions           action.Manager           // 租户自定义资源操作管理器
+	policyLoads       *policyLoadMonitor       // 容错加载结果记录器（未启用容错加载时为 nil）
+	quarantine        quarantine.Manager       // 隔离策略行管理器（未启用容错加载时为 nil）
+	userData          core.UserDataConfig      // 用户数据删除的保留规则
+	jobFunctions      jobfunction.Manager      // 岗位目录管理器
+	schedules         schedule.Manager         // 定时权限变更管理器
+	expirations       expiration.Manager       // 限时授权管理器
+	denies            deny.Manager             // 拒绝规则管理器
+	txGate            *txGate                  // 事务闸门，事务执行期间其他变更等待
+	tx                *txState                 // 当前事务（不在事务中时为 nil）
+	manifests         core.ManifestCache       // 最近一次成功查询的权限清单（降级时返回）
 }
 
 // newCasbinxClient 创建casbinx客户端
-func newCasbinxClient(c core.Config) (*casbinxClient, error) {
+func newCasbinxClient(c core.Config, opts ...Option) (*casbinxClient, error) {
+	o := applyOptions(opts)
+	logger := o.logger
+	if logger == nil {
+		logger = c.Logger
+	}
+	if logger == nil {
+		logger = core.DefaultLogger()
+	}
+	guard := newPanicGuard(c.Recovery, logger)
+
 	// 如果未配置安全设置，使用默认安全配置
 	securityConfig := c.Security
 	if len(securityConfig.SystemPermissions) == 0 {
+		selfQuery := securityConfig.SelfQuery
 		securityConfig = core.DefaultSecurityConfig()
+		securityConfig.SelfQuery = selfQuery
 	}
 
-	// 验证 Watcher 配置（强制要求 Redis）
+	// 验证 Watcher 配置（未指定自定义 Watcher 且未关闭 Watcher 时按 Config.Watcher.Type 校验内置 Watcher）
 	watcherConfig := c.Watcher
-	if watcherConfig.Redis.Addr == "" {
-		return nil, fmt.Errorf("config.Watcher.Redis.Addr 未设置")
-	}
-	gormDB, err := gorm.Open(postgres.Open(c.Dsn), &gorm.Config{})
-	if err != nil {
-		return nil, fmt.Errorf("GORM 数据库连接失败: %v", err)
+	watcherEnabled := o.watcher != nil || !watcherConfig.Disabled
+	if o.watcher == nil && watcherEnabled {
+		if err := watcher.Validate(watcherConfig); err != nil {
+			return nil, err
+		}
 	}
 
-	// 设置默认路径
-	modelPaths := c.PossiblePaths
-	if len(modelPaths) == 0 {
-		modelPaths = []string{
-			"etc/casbin_model.conf",
-			"./casbin_model.conf",
-			"../etc/casbin_model.conf",
-		}
+	// 元数据表只支持 PostgreSQL：未注入元数据表连接时需要 Config.Dsn（WithGormDB 注入的连接只存储策略）
+	if o.sqlConn == nil && c.Dsn == "" {
+		return nil, fmt.Errorf("元数据表需要 PostgreSQL 连接：请配置 Config.Dsn，或通过 WithSqlConn、NewCasbinxWithDB 注入")
 	}
 
-	var modelPath string
-	for _, path := range modelPaths {
-		if _, err := os.Stat(path); err == nil {
-			modelPath = path
-			break
-		}
+	// 加载 Casbin 模型
+	casbinModel, err := loadModel(c)
+	if err != nil {
+		return nil, err
 	}
 
-	if modelPath == "" {
-		return nil, fmt.Errorf("Casbin模型文件不存在，已尝试路径: %v", modelPaths)
+	// 配置了凭据回调时，GORM 适配器和元数据表共享按 Config.Dsn 创建的连接池，新连接使用回调返回的短期密码
+	var credentialDB *sql.DB
+	if o.credentials != nil && ((o.adapter == nil && o.gormDB == nil) || o.sqlConn == nil) {
+		credentialDB, err = openCredentialDB(c.Dsn, o.credentials)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// 创建适配器
-	adapter, err := gormadapter.NewAdapterByDBUseTableName(gormDB, "", "casbin_rules")
-	if err != nil {
-		return nil, fmt.Errorf("创建Casbin适配器失败: %v", err)
+	// 创建适配器（未指定自定义适配器时使用 GORM 适配器，未注入 GORM 连接时连接 Config.Dsn）
+	// 适配器包装为上下文感知的适配器，策略写入使用调用方的 ctx
+	var policyLoads *policyLoadMonitor
+	var contextAdapter *core.ContextAdapter
+	var adapter persist.Adapter
+	if o.adapter != nil {
+		contextAdapter = core.NewContextAdapter(o.adapter)
+		adapter = contextAdapter
+	} else {
+		var err error
+		gormDB := o.gormDB
+		if gormDB == nil {
+			dialector := postgres.Open(c.Dsn)
+			if credentialDB != nil {
+				dialector = postgres.New(postgres.Config{Conn: credentialDB})
+			}
+			gormDB, err = gorm.Open(dialector, &gorm.Config{})
+			if err != nil {
+				return nil, fmt.Errorf("GORM 数据库连接失败: %v", err)
+			}
+		}
+		gormAdapter, err := gormadapter.NewAdapterByDBUseTableName(gormDB, "", "casbin_rules")
+		if err != nil {
+			return nil, fmt.Errorf("创建Casbin适配器失败: %v", err)
+		}
+		contextAdapter = core.NewContextAdapter(gormContextAdapter{Adapter: gormAdapter})
+		adapter = contextAdapter
+
+		// 容错加载：逐行读取策略表，无效的行被隔离，其余规则正常加载
+		if c.PolicyLoad.Tolerant {
+			sqlDB, err := gormDB.DB()
+			if err != nil {
+				return nil, fmt.Errorf("获取策略表连接失败: %v", err)
+			}
+			policyLoads = newPolicyLoadMonitor(logger, o.loads)
+			adapter = core.NewTolerantAdapter(adapter, core.NewSQLRuleSource(sqlDB, "casbin_rules"), policyLoads.record)
+		}
 	}
 
 	// 创建Casbin执行器
-	casbinEnforcer, err := casbin.NewEnforcer(modelPath, adapter)
+	casbinEnforcer, err := casbin.NewEnforcer(casbinModel, adapter)
 	if err != nil {
 		return nil, fmt.Errorf("创建Casbin执行器失败: %v", err)
 	}
 
-	// 启用自动保存和日志
+	// 启用自动保存，Casbin 内部日志转发到 Logger（Config.CasbinLog 控制是否输出）
 	casbinEnforcer.EnableAutoSave(true)
-	casbinEnforcer.EnableLog(true)
-
-	// 创建和配置 Redis Watcher
-	watcher, err := rediswatcher.NewWatcher(watcherConfig.Redis.Addr, rediswatcher.WatcherOptions{
-		Options: redis.Options{
-			Network:  watcherConfig.Redis.Network,
-			Password: watcherConfig.Redis.Password,
-			DB:       watcherConfig.Redis.DB,
-		},
-		Channel:    watcherConfig.Redis.Channel,
-		IgnoreSelf: watcherConfig.Redis.IgnoreSelf,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("创建 Redis Watcher 失败: %v", err)
-	}
+	casbinEnforcer.SetLogger(core.NewCasbinLogger(logger))
+	casbinEnforcer.EnableLog(c.CasbinLog)
 
-	// 设置 Watcher 到 Casbin 执行器
-	err = casbinEnforcer.SetWatcher(watcher)
+	// 创建核心执行器
+	coreEnforcer, err := core.NewEnforcer(casbinEnforcer)
 	if err != nil {
-		return nil, fmt.Errorf("设置 Watcher 失败: %v", err)
+		return nil, fmt.Errorf("创建核心执行器失败: %v", err)
 	}
+	coreEnforcer.SetContextAdapter(contextAdapter)
+	coreEnforcer.EnableSubjectNamespaces(c.SubjectNamespaces)
+	coreEnforcer.EnableStrictWriteActions(c.StrictWriteActions)
 
-	// 设置更新回调，当收到策略变更通知时自动重新加载策略
-	err = watcher.SetUpdateCallback(func(msg string) {
-		err := casbinEnforcer.LoadPolicy()
+	metrics := o.metrics
+	if metrics == nil {
+		metrics, err = core.NewPrometheusMetrics(c.Metrics)
 		if err != nil {
-			log.Printf("[CasbinX] 重新加载策略失败: %v", err)
+			return nil, fmt.Errorf("注册指标失败: %v", err)
 		}
-	})
-	if err != nil {
-		return nil, fmt.Errorf("设置 Watcher 更新回调失败: %v", err)
 	}
+	coreEnforcer.SetMetrics(metrics)
 
-	// 启用自动通知 Watcher（当本实例修改策略时自动通知其他实例）
-	casbinEnforcer.EnableAutoNotifyWatcher(true)
+	// 权限检查实现：影子模式下两种实现都执行，结果不一致时记录日志并调用回调
+	if err := coreEnforcer.SetCheckComparison(c.CheckComparison); err != nil {
+		return nil, fmt.Errorf("无效的权限检查比较配置: %v", err)
+	}
+	if c.CheckComparison.Shadow {
+		coreEnforcer.AddCheckDivergenceHook(func(divergence core.CheckDivergence) {
+			logger.Warn("权限检查实现结果不一致",
+				"user", divergence.UserKey,
+				"tenant", divergence.TenantKey,
+				"permission", divergence.Permission.String(),
+				"primary", divergence.Primary,
+				"primaryAllowed", divergence.PrimaryAllowed,
+				"shadowAllowed", divergence.ShadowAllowed,
+				"shadowError", divergence.ShadowError)
+		})
+	}
+	for _, hook := range o.diverge {
+		coreEnforcer.AddCheckDivergenceHook(hook)
+	}
 
-	// 创建核心执行器
-	coreEnforcer, err := core.NewEnforcer(casbinEnforcer)
-	if err != nil {
-		return nil, fmt.Errorf("创建核心执行器失败: %v", err)
+	coreEnforcer.SetDecisionCache(o.cache)
+	coreEnforcer.SetPermissionCache(o.rules)
+	for _, hook := range o.hooks {
+		coreEnforcer.AddReloadHook(hook)
+	}
+
+	// 设置更新回调，当收到策略变更通知时重新加载策略
+	// 突发通知经合并器合并，避免每条通知都触发一次全量加载
+	reloadCoalescer := core.NewReloadCoalescer(guard.wrap("LoadPolicy", coreEnforcer.LoadPolicy), watcherConfig.ReloadDebounce, watcherConfig.ReloadMaxDelay)
+	reloadCoalescer.SetLogger(logger)
+	reloadCoalescer.SetMetrics(metrics)
+
+	var policyWatcher core.Watcher
+	if watcherEnabled {
+		policyWatcher, err = setupWatcher(casbinEnforcer, coreEnforcer, reloadCoalescer, c, o.watcher, o.credentials, metrics, logger)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// 没有 Watcher 时实例之间不同步，多实例部署会出现策略不一致
+		logger.Warn("Watcher 已关闭，策略变更不会在实例之间同步，仅适用于单实例部署")
 	}
 
 	// 创建安全验证器
 	securityValidator := core.NewSecurityValidator(securityConfig)
 
+	// 元数据表连接（未注入时按 Config.Dsn 创建），所有管理器共享同一连接池
+	dbConn := o.sqlConn
+	if dbConn == nil {
+		if credentialDB != nil {
+			dbConn = sqlx.NewSqlConnFromDB(credentialDB)
+		} else {
+			dbConn = sqlx.NewSqlConn("postgres", c.Dsn)
+		}
+	}
+
 	// 创建管理器
-	userManager := user.NewManager(c.Dsn, coreEnforcer)
+	userManager, err := user.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, err
+	}
 	checkManager := check.NewManager(coreEnforcer)
-	roleManager, err := role.NewManager(c.Dsn, coreEnforcer, securityValidator)
+	cipher := core.NewFieldCipher(c.KeyProvider)
+	roleManager, err := role.NewManager(dbConn, coreEnforcer, securityValidator, cipher)
 	if err != nil {
 		return nil, err
 	}
-	policyManager, err := policy.NewManager(coreEnforcer)
+	policyManager, err := policy.NewManager(dbConn, coreEnforcer)
 	if err != nil {
 		return nil, fmt.Errorf("创建策略管理器失败: %v", err)
 	}
 
+	idempotencyManager, err := idempotency.NewManager(dbConn, c.Idempotency.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("创建幂等键管理器失败: %v", err)
+	}
+
+	environmentManager, err := environment.NewManager(dbConn, c.Environment, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建环境标记管理器失败: %v", err)
+	}
+
+	tenantManager, err := tenant.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建租户管理器失败: %v", err)
+	}
+
+	priorityManager, err := priority.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建规则优先级管理器失败: %v", err)
+	}
+
+	conditionManager, err := condition.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建规则条件管理器失败: %v", err)
+	}
+
+	invitationManager, err := invitation.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建分享邀请管理器失败: %v", err)
+	}
+
+	rolloutManager, err := rollout.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建灰度变更管理器失败: %v", err)
+	}
+
+	auditManager, err := audit.NewManager(dbConn, cipher)
+	if err != nil {
+		return nil, fmt.Errorf("创建审计日志管理器失败: %v", err)
+	}
+
+	// 变更摘要基于审计日志，启用 Config.Digest 时在每个周期边界投递
+	digestManager, err := digest.NewManager(dbConn, c.Digest, auditManager, o.digests, logger)
+	if err != nil {
+		return nil, fmt.Errorf("创建变更摘要管理器失败: %v", err)
+	}
+
+	jobManager, err := job.NewManager(dbConn)
+	if err != nil {
+		return nil, fmt.Errorf("创建异步任务管理器失败: %v", err)
+	}
+
+	actionManager, err := action.NewManager(dbConn, coreEnforcer, securityValidator)
+	if err != nil {
+		return nil, fmt.Errorf("创建自定义资源操作管理器失败: %v", err)
+	}
+
+	jobFunctionManager, err := jobfunction.NewManager(dbConn)
+	if err != nil {
+		return nil, fmt.Errorf("创建岗位目录管理器失败: %v", err)
+	}
+
+	scheduleManager, err := schedule.NewManager(dbConn)
+	if err != nil {
+		return nil, fmt.Errorf("创建定时权限变更管理器失败: %v", err)
+	}
+
+	expirationManager, err := expiration.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建限时授权管理器失败: %v", err)
+	}
+
+	denyManager, err := deny.NewManager(dbConn, coreEnforcer)
+	if err != nil {
+		return nil, fmt.Errorf("创建拒绝规则管理器失败: %v", err)
+	}
+
+	// 隔离表只在启用容错加载时创建
+	var quarantineManager quarantine.Manager
+	if policyLoads != nil {
+		quarantineManager, err = quarantine.NewManager(dbConn)
+		if err != nil {
+			return nil, fmt.Errorf("创建隔离策略行管理器失败: %v", err)
+		}
+		policyLoads.attach(quarantineManager)
+	}
+
+	// 决策日志为可选功能，未启用时不创建表也不注册观察者
+	var decisionManager decision.Manager
+	if c.DecisionLog.Enabled {
+		decisionManager, err = decision.NewManager(dbConn, c.DecisionLog, coreEnforcer)
+		if err != nil {
+			return nil, fmt.Errorf("创建决策日志管理器失败: %v", err)
+		}
+	}
+
+	// 变更串行化队列为可选功能，未启用时变更在调用方协程中直接执行
+	var mutationQueue *core.MutationQueue
+	if c.MutationQueue.Enabled {
+		mutationQueue = core.NewMutationQueue(c.MutationQueue)
+	}
+
 	// 设置权限检查器解决循环依赖
-	securityValidator.SetPermissionChecker(checkManager)
+	// 操作者权限校验只读内存中的策略，不依赖调用方的 ctx
+	securityValidator.SetPermissionChecker(core.PermissionCheckerFunc(func(userKey, tenantKey string, permission core.Permission) (bool, error) {
+		return checkManager.CheckPermission(context.Background(), userKey, tenantKey, permission)
+	}))
 
-	return &casbinxClient{
+	client := &casbinxClient{
+		enforcer:          coreEnforcer,
 		userManager:       userManager,
 		roleManager:       roleManager,
 		checkManager:      checkManager,
 		securityValidator: securityValidator,
 		policyManager:     policyManager,
-	}, nil
+		idempotency:       idempotencyManager,
+		environments:      environmentManager,
+		tenantManager:     tenantManager,
+		priorities:        priorityManager,
+		conditions:        conditionManager,
+		decisions:         decisionManager,
+		invitations:       invitationManager,
+		rollouts:          rolloutManager,
+		reloadCoalescer:   reloadCoalescer,
+		watcher:           policyWatcher,
+		ctx:               context.Background(),
+		guard:             guard,
+		mutations:         mutationQueue,
+		audit:             auditManager,
+		digests:           digestManager,
+		securityHooks:     o.events,
+		jobs:              jobManager,
+		runningJobs:       &sync.Map{},
+		actions:           actionManager,
+		policyLoads:       policyLoads,
+		quarantine:        quarantineManager,
+		userData:          c.UserData,
+		jobFunctions:      jobFunctionManager,
+		schedules:         scheduleManager,
+		expirations:       expirationManager,
+		denies:            denyManager,
+		txGate:            newTxGate(),
+		manifests:         core.NewMemoryManifestCache(0),
+	}
+
+	// 到期的定时变更由后台调度执行，过期的限时授权由后台清理撤销
+	client.startScheduler(c.Schedule)
+	client.startExpirationReaper(c.Expiration)
+	return client, nil
 }
 
-// 用户权限管理方法实现
-func (c *casbinxClient) GrantPermission(operatorKey, userKey, tenantKey string, permission core.Permission) error {
-	// 安全检查：进行提权验证
-	if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
-		return err
+// setupWatcher 创建（未指定自定义 Watcher 时按 Config.Watcher.Type 创建内置 Watcher）并挂载 Watcher
+// 本实例修改策略时自动通知其他实例，收到通知时经合并器重新加载策略；内置 Watcher 断线重连后立即重新加载
+func setupWatcher(casbinEnforcer *casbin.Enforcer, coreEnforcer *core.Enforcer, reloadCoalescer *core.ReloadCoalescer, c core.Config, customWatcher core.Watcher, credentials core.DBCredentialsProvider, metrics core.MetricsRecorder, logger core.Logger) (core.Watcher, error) {
+	w := customWatcher
+	if w == nil {
+		builtin, err := watcher.NewWatcher(c.Watcher, c.Dsn, credentials, metrics, logger)
+		if err != nil {
+			return nil, err
+		}
+		w = builtin
+	}
+
+	// 设置 Watcher 到 Casbin 执行器
+	if err := casbinEnforcer.SetWatcher(w); err != nil {
+		return nil, fmt.Errorf("设置 Watcher 失败: %v", err)
+	}
+
+	err := w.SetUpdateCallback(func(msg string) {
+		logger.Debug("收到策略同步通知", "message", msg)
+		reloadCoalescer.Request()
+		if msg == core.WatcherResyncMessage {
+			// 断线期间错过的变更数量未知，不等待防抖窗口，重载失败由合并器记录
+			_ = reloadCoalescer.Flush()
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("设置 Watcher 更新回调失败: %v", err)
 	}
 
-	return c.userManager.GrantPermission(operatorKey, userKey, tenantKey, permission)
+	// 启用自动通知 Watcher（当本实例修改策略时自动通知其他实例）
+	casbinEnforcer.EnableAutoNotifyWatcher(true)
+	coreEnforcer.SetWatcher(w)
+	return w, nil
 }
 
-func (c *casbinxClient) RevokePermission(operatorKey, userKey, tenantKey string, permission core.Permission) error {
-	// 安全检查：进行权限撤销验证
-	if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, userKey, tenantKey, permission); err != nil {
-		return err
+// WithContext 返回绑定指定上下文的客户端副本，副本的数据库操作遵循该上下文的取消与超时
+func (c *casbinxClient) WithContext(ctx context.Context) CasbinX {
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// 用户权限管理方法实现
+func (c *casbinxClient) GrantPermission(operatorKey, userKey, tenantKey string, permission core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("GrantPermission", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "GrantPermission", []string{operatorKey, userKey, tenantKey, permission.String()}, func() error {
+		// 安全检查：进行提权验证
+		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		// 先写入环境标记再授权，保证授权生效时环境限制已经存在
+		environments := core.ApplyMutationOptions(opts).Environments
+		if err := c.environments.SetPolicyEnvironments(c.ctx, userKey, tenantKey, permission, environments); err != nil {
+			return err
+		}
+
+		if err := c.userManager.GrantPermission(c.ctx, operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		// 已有的限时授权变为永久授权
+		if err := c.expirations.ClearPermissionExpiry(c.ctx, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionGrant, Target: permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
+}
+
+func (c *casbinxClient) RevokePermission(operatorKey, userKey, tenantKey string, permission core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("RevokePermission", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "RevokePermission", []string{operatorKey, userKey, tenantKey, permission.String()}, func() error {
+		// 安全检查：进行权限撤销验证
+		if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.userManager.RevokePermission(c.ctx, operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.priorities.ClearPolicyPriority(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.environments.ClearPolicyEnvironments(c.ctx, userKey, tenantKey, permission); err != nil {
+			return err
+		}
 
-	return c.userManager.RevokePermission(operatorKey, userKey, tenantKey, permission)
+		if err := c.expirations.ClearPermissionExpiry(c.ctx, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionRevoke, Target: permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
 }
 
 // GetDirectPermissionsSecure 安全地获取用户直接权限（需要权限验证）
-func (c *casbinxClient) GetDirectPermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.Permission, error) {
+func (c *casbinxClient) GetDirectPermissionsSecure(operatorKey, userKey, tenantKey string) (_ []core.Permission, err error) {
+	defer c.guard.recover("GetDirectPermissionsSecure", &err)
 	// 安全检查：验证查询权限
 	if err := c.validateQueryPermission(operatorKey, userKey, tenantKey); err != nil {
 		return nil, err
 	}
 
-	return c.userManager.GetDirectPermissions(userKey, tenantKey)
+	return c.userManager.GetDirectPermissions(c.ctx, userKey, tenantKey)
 }
 
 // GetEffectivePermissionsSecure 安全地获取用户有效权限（需要权限验证）
-func (c *casbinxClient) GetEffectivePermissionsSecure(operatorKey, userKey, tenantKey string) ([]core.Permission, error) {
+func (c *casbinxClient) GetEffectivePermissionsSecure(operatorKey, userKey, tenantKey string) (_ []core.Permission, err error) {
+	defer c.guard.recover("GetEffectivePermissionsSecure", &err)
 	// 安全检查：验证查询权限
 	if err := c.validateQueryPermission(operatorKey, userKey, tenantKey); err != nil {
 		return nil, err
 	}
 
-	return c.userManager.GetEffectivePermissions(userKey, tenantKey)
+	return c.userManager.GetEffectivePermissions(c.ctx, userKey, tenantKey)
+}
+
+// QueryEffectivePermissions 安全地获取用户有效权限，并按选项去重、过滤和排序
+func (c *casbinxClient) QueryEffectivePermissions(operatorKey, userKey, tenantKey string, options core.PermissionQueryOptions) (_ []core.Permission, err error) {
+	defer c.guard.recover("QueryEffectivePermissions", &err)
+	permissions, err := c.GetEffectivePermissionsSecure(operatorKey, userKey, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return options.Apply(permissions), nil
+}
+
+// GetEffectivePermissionGroups 安全地获取用户有效权限，并按资源分组
+func (c *casbinxClient) GetEffectivePermissionGroups(operatorKey, userKey, tenantKey string, options core.PermissionQueryOptions) (_ []core.PermissionGroup, err error) {
+	defer c.guard.recover("GetEffectivePermissionGroups", &err)
+	permissions, err := c.QueryEffectivePermissions(operatorKey, userKey, tenantKey, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GroupPermissionsByResource(permissions), nil
+}
+
+// AuthorizeUserQuery 校验操作者能否查询用户在租户内的权限信息（遵循 SecurityConfig.SelfQuery），不满足时返回 core.ErrPermissionDenied
+func (c *casbinxClient) AuthorizeUserQuery(operatorKey, userKey, tenantKey string) (err error) {
+	defer c.guard.recover("AuthorizeUserQuery", &err)
+	return c.validateQueryPermission(operatorKey, userKey, tenantKey)
 }
 
 // validateQueryPermission 验证查询权限
 func (c *casbinxClient) validateQueryPermission(operatorKey, targetUserKey, tenantKey string) error {
-	// 用户可以查询自己的权限
-	if operatorKey == targetUserKey {
+	// 用户可以查询自己的权限（SecurityConfig.SelfQuery 不允许自查时按查询他人处理）
+	if operatorKey == targetUserKey && c.securityValidator.AllowsSelfQuery(operatorKey, tenantKey) {
 		return nil
 	}
 
 	// 检查操作者在指定租户内是否有用户查看权限（只需要read权限即可查询）
 	permission := core.Permission{Resource: core.ResourceUser, Action: core.ActionRead}
-	hasPermission, err := c.checkManager.CheckPermission(operatorKey, tenantKey, permission)
+	hasPermission, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, permission)
 	if err != nil {
 		return fmt.Errorf("检查操作者权限时出错: %w", err)
 	}
 	if !hasPermission {
-		return fmt.Errorf("操作者 %s 在租户 %s 中没有用户查看权限，无法查询用户 %s 的权限信息", operatorKey, tenantKey, targetUserKey)
+		return fmt.Errorf("%w: 操作者 %s 在租户 %s 中没有用户查看权限，无法查询用户 %s 的权限信息", core.ErrPermissionDenied, operatorKey, tenantKey, targetUserKey)
 	}
 
 	return nil
 }
 
-func (c *casbinxClient) ClearUserPermissions(operatorKey, userKey, tenantKey string) error {
-	// 安全检查：验证操作者是否有用户管理权限（清除权限是管理操作）
-	// 先检查全局域权限
-	globalCheckFunc := func(resource core.Resource, action core.Action) (bool, error) {
-		permission := core.Permission{Resource: resource, Action: action}
-		return c.checkManager.CheckPermission(operatorKey, "*", permission)
-	}
-	hasGlobalPermission, err := core.HasManagePermission(globalCheckFunc, core.ResourceUser)
-	if err != nil {
-		return fmt.Errorf("检查操作者全局权限时出错: %w", err)
-	}
-
-	// 如果没有全局权限，检查指定租户的权限
-	hasTenantPermission := false
-	if !hasGlobalPermission {
-		tenantCheckFunc := func(resource core.Resource, action core.Action) (bool, error) {
+func (c *casbinxClient) ClearUserPermissions(operatorKey, userKey, tenantKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("ClearUserPermissions", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "ClearUserPermissions", []string{operatorKey, userKey, tenantKey}, func() error {
+		// 安全检查：验证操作者是否有用户管理权限（清除权限是管理操作）
+		// 先检查全局域权限
+		globalCheckFunc := func(resource core.Resource, action core.Action) (bool, error) {
 			permission := core.Permission{Resource: resource, Action: action}
-			return c.checkManager.CheckPermission(operatorKey, tenantKey, permission)
+			return c.checkManager.CheckPermission(c.ctx, operatorKey, "*", permission)
 		}
-		hasTenantPermission, err = core.HasManagePermission(tenantCheckFunc, core.ResourceUser)
+		hasGlobalPermission, err := core.HasManagePermission(globalCheckFunc, core.ResourceUser)
 		if err != nil {
-			return fmt.Errorf("检查操作者租户权限时出错: %w", err)
+			return fmt.Errorf("检查操作者全局权限时出错: %w", err)
 		}
-	}
 
-	if !hasGlobalPermission && !hasTenantPermission {
-		return fmt.Errorf("操作者 %s 没有在租户 %s 中的用户管理权限，无法清除用户权限", operatorKey, tenantKey)
-	}
+		// 如果没有全局权限，检查指定租户的权限
+		hasTenantPermission := false
+		if !hasGlobalPermission {
+			tenantCheckFunc := func(resource core.Resource, action core.Action) (bool, error) {
+				permission := core.Permission{Resource: resource, Action: action}
+				return c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, permission)
+			}
+			hasTenantPermission, err = core.HasManagePermission(tenantCheckFunc, core.ResourceUser)
+			if err != nil {
+				return fmt.Errorf("检查操作者租户权限时出错: %w", err)
+			}
+		}
 
-	return c.userManager.ClearUserPermissions(operatorKey, userKey)
-}
+		if !hasGlobalPermission && !hasTenantPermission {
+			return fmt.Errorf("操作者 %s 没有在租户 %s 中的用户管理权限，无法清除用户权限", operatorKey, tenantKey)
+		}
+
+		// 清除前读取用户在所有域的直接授权，用于逐条写入审计日志
+		cleared, err := c.enforcer.GetPolicies(c.enforcer.UserSubject(userKey), "")
+		if err != nil {
+			return err
+		}
 
-func (c *casbinxClient) GetUserPermissionsByResource(userKey, tenantKey, resource string) ([]core.Permission, error) {
-	return c.userManager.GetUserPermissionsByResource(userKey, tenantKey, resource)
+		if err := c.userManager.ClearUserPermissions(c.ctx, operatorKey, userKey); err != nil {
+			return err
+		}
+
+		for _, policy := range cleared {
+			c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionRevoke, Target: policy.Permission().String(), TenantKey: policy.Domain, OperatorKey: operatorKey})
+		}
+		return nil
+	})
 }
 
-func (c *casbinxClient) AssignRole(operatorKey, userKey, roleKey, tenantKey string) error {
-	// 安全检查：验证操作者是否有用户管理权限
-	// 验证操作者有用户管理权限
-	userPermission := core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite}
-	hasUserPermission, err := c.checkManager.CheckPermission(operatorKey, tenantKey, userPermission)
-	if err != nil {
-		return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
-	}
-	if !hasUserPermission {
-		return fmt.Errorf("操作者 %s 没有用户管理权限，无法分配角色", operatorKey)
+// GetUserPermissionsByResourceSecure 安全地获取用户对特定资源的权限（需要权限验证）
+func (c *casbinxClient) GetUserPermissionsByResourceSecure(operatorKey, userKey, tenantKey, resource string) (_ []core.Permission, err error) {
+	defer c.guard.recover("GetUserPermissionsByResourceSecure", &err)
+	// 安全检查：验证查询权限
+	if err := c.validateQueryPermission(operatorKey, userKey, tenantKey); err != nil {
+		return nil, err
 	}
 
-	// 验证操作者有角色管理权限
-	rolePermission := core.Permission{Resource: core.ResourceRole, Action: core.ActionWrite}
-	hasRolePermission, err := c.checkManager.CheckPermission(operatorKey, tenantKey, rolePermission)
-	if err != nil {
-		return fmt.Errorf("检查操作者角色管理权限时出错: %w", err)
-	}
-	if !hasRolePermission {
-		return fmt.Errorf("操作者 %s 没有角色管理权限，无法分配角色", operatorKey)
-	}
+	return c.userManager.GetUserPermissionsByResource(c.ctx, userKey, tenantKey, resource)
+}
 
-	// 检查角色是否包含系统权限
-	hasSystemPerms, err := c.roleManager.HasSystemPermissions(roleKey)
-	if err != nil {
-		return fmt.Errorf("检查角色系统权限时出错: %w", err)
-	}
+// GetUserPermissionsByResource 获取用户对特定资源的权限
+//
+// Deprecated: 不校验调用者身份，请使用 GetUserPermissionsByResourceSecure
+func (c *casbinxClient) GetUserPermissionsByResource(userKey, tenantKey, resource string) (_ []core.Permission, err error) {
+	defer c.guard.recover("GetUserPermissionsByResource", &err)
+	return c.userManager.GetUserPermissionsByResource(c.ctx, userKey, tenantKey, resource)
+}
 
-	if hasSystemPerms {
-		// 系统角色只能通过租户初始化接口分配，普通角色分配接口不允许
-		return core.ErrSystemRoleAssignmentDenied
-	}
+func (c *casbinxClient) AssignRole(operatorKey, userKey, roleKey, tenantKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("AssignRole", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "AssignRole", []string{operatorKey, userKey, roleKey, tenantKey}, func() error {
+		if err := c.validateRoleAssignment(operatorKey, roleKey, tenantKey); err != nil {
+			return err
+		}
+
+		if err := c.userManager.AssignRole(c.ctx, operatorKey, userKey, roleKey, tenantKey); err != nil {
+			return err
+		}
 
-	return c.userManager.AssignRole(operatorKey, userKey, roleKey, tenantKey)
+		// 已有的限时角色分配变为永久分配
+		if err := c.expirations.ClearRoleExpiry(c.ctx, userKey, roleKey, tenantKey); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionAssign, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
 }
 
-func (c *casbinxClient) RemoveRole(operatorKey, userKey, roleKey, tenantKey string) error {
-	// 安全检查：验证操作者是否有用户管理权限
-	// 验证操作者有用户管理权限
-	userPermission := core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite}
-	hasUserPermission, err := c.checkManager.CheckPermission(operatorKey, tenantKey, userPermission)
-	if err != nil {
-		return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
-	}
-	if !hasUserPermission {
-		return fmt.Errorf("操作者 %s 没有用户管理权限，无法分配角色", operatorKey)
-	}
+func (c *casbinxClient) RemoveRole(operatorKey, userKey, roleKey, tenantKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("RemoveRole", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "RemoveRole", []string{operatorKey, userKey, roleKey, tenantKey}, func() error {
+		if err := c.validateRoleRemoval(operatorKey, userKey, roleKey, tenantKey); err != nil {
+			return err
+		}
 
-	// 验证操作者有角色管理权限
-	rolePermission := core.Permission{Resource: core.ResourceRole, Action: core.ActionWrite}
-	hasRolePermission, err := c.checkManager.CheckPermission(operatorKey, tenantKey, rolePermission)
-	if err != nil {
-		return fmt.Errorf("检查操作者角色管理权限时出错: %w", err)
-	}
-	if !hasRolePermission {
-		return fmt.Errorf("操作者 %s 没有角色管理权限，无法分配角色", operatorKey)
-	}
+		if err := c.userManager.RemoveRole(c.ctx, operatorKey, userKey, roleKey, tenantKey); err != nil {
+			return err
+		}
 
-	// 检查用户的角色是否包含系统权限
-	hasSystemPerms, err := c.roleManager.UserRoleHasSystemPermissions(userKey, roleKey, tenantKey)
-	if err != nil {
-		return fmt.Errorf("检查用户角色系统权限时出错: %w", err)
-	}
+		if err := c.expirations.ClearRoleExpiry(c.ctx, userKey, roleKey, tenantKey); err != nil {
+			return err
+		}
 
-	if hasSystemPerms {
-		// 系统角色只能通过租户初始化接口分配，不能移除
-		return core.ErrSystemRoleRemovalDenied
+		c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionRemove, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
+}
+
+// GetUserRolesSecure 安全地获取用户角色列表（需要权限验证）
+func (c *casbinxClient) GetUserRolesSecure(operatorKey, userKey, tenantKey string) (_ []string, err error) {
+	defer c.guard.recover("GetUserRolesSecure", &err)
+	// 安全检查：验证查询权限
+	if err := c.validateQueryPermission(operatorKey, userKey, tenantKey); err != nil {
+		return nil, err
 	}
 
-	return c.userManager.RemoveRole(operatorKey, userKey, roleKey, tenantKey)
+	return c.userManager.GetUserRoles(c.ctx, userKey, tenantKey)
 }
 
-func (c *casbinxClient) GetUserRoles(userKey, tenantKey string) ([]string, error) {
-	return c.userManager.GetUserRoles(userKey, tenantKey)
+// GetUserRoles 获取用户角色列表
+//
+// Deprecated: 不校验调用者身份，请使用 GetUserRolesSecure
+func (c *casbinxClient) GetUserRoles(userKey, tenantKey string) (_ []string, err error) {
+	defer c.guard.recover("GetUserRoles", &err)
+	return c.userManager.GetUserRoles(c.ctx, userKey, tenantKey)
 }
 
-func (c *casbinxClient) ClearUserRoles(operatorKey, userKey string) error {
-	return c.userManager.ClearUserRoles(operatorKey, userKey)
+func (c *casbinxClient) ClearUserRoles(operatorKey, userKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("ClearUserRoles", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "ClearUserRoles", []string{operatorKey, userKey}, func() error {
+		// 冻结检查：用户持有冻结角色时不允许清除分配
+		if err := c.ensureUserRolesUnlocked(userKey); err != nil {
+			return err
+		}
+
+		// 清除前读取用户在所有域的角色分配，用于逐条写入审计日志
+		var cleared []core.GroupingPolicy
+		if err := c.enforcer.RangeGroupingPolicies("", func(grouping core.GroupingPolicy) error {
+			if grouping.UserKey == userKey {
+				cleared = append(cleared, grouping)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if err := c.userManager.ClearUserRoles(c.ctx, operatorKey, userKey); err != nil {
+			return err
+		}
+
+		for _, grouping := range cleared {
+			c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionRemove, Target: grouping.RoleKey, TenantKey: grouping.TenantKey, OperatorKey: operatorKey})
+		}
+		return nil
+	})
 }
 
-func (c *casbinxClient) HasDirectPermission(userKey, tenantKey string, permission core.Permission) (bool, error) {
-	return c.checkManager.HasDirectPermission(userKey, tenantKey, permission)
+func (c *casbinxClient) HasDirectPermission(userKey, tenantKey string, permission core.Permission) (_ bool, err error) {
+	defer c.guard.recover("HasDirectPermission", &err)
+	return c.checkManager.HasDirectPermission(c.ctx, userKey, tenantKey, permission)
 }
 
-func (c *casbinxClient) HasRole(userKey, roleKey, tenantKey string) (bool, error) {
-	return c.checkManager.HasRole(userKey, roleKey, tenantKey)
+func (c *casbinxClient) HasRole(userKey, roleKey, tenantKey string) (_ bool, err error) {
+	defer c.guard.recover("HasRole", &err)
+	return c.checkManager.HasRole(c.ctx, userKey, roleKey, tenantKey)
 }
 
-func (c *casbinxClient) CheckMultiplePermissions(userKey, tenantKey string, permissions []core.Permission) ([]bool, error) {
-	return c.checkManager.CheckMultiplePermissions(userKey, tenantKey, permissions)
+func (c *casbinxClient) CheckMultiplePermissions(userKey, tenantKey string, permissions []core.Permission) (_ []bool, err error) {
+	defer c.guard.recover("CheckMultiplePermissions", &err)
+	return c.checkManager.CheckMultiplePermissions(c.ctx, userKey, tenantKey, permissions)
 }
 
-func (c *casbinxClient) HasAnyPermission(userKey, tenantKey string, permissions []core.Permission) (bool, error) {
-	return c.checkManager.HasAnyPermission(userKey, tenantKey, permissions)
+func (c *casbinxClient) HasAnyPermission(userKey, tenantKey string, permissions []core.Permission) (_ bool, err error) {
+	defer c.guard.recover("HasAnyPermission", &err)
+	return c.checkManager.HasAnyPermission(c.ctx, userKey, tenantKey, permissions)
 }
 
-func (c *casbinxClient) HasAllPermissions(userKey, tenantKey string, permissions []core.Permission) (bool, error) {
-	return c.checkManager.HasAllPermissions(userKey, tenantKey, permissions)
+func (c *casbinxClient) HasAllPermissions(userKey, tenantKey string, permissions []core.Permission) (_ bool, err error) {
+	defer c.guard.recover("HasAllPermissions", &err)
+	return c.checkManager.HasAllPermissions(c.ctx, userKey, tenantKey, permissions)
 }
 
-func (c *casbinxClient) CanAccessResource(userKey, tenantKey string, resource core.Resource) (bool, error) {
-	return c.checkManager.CanAccessResource(userKey, tenantKey, resource)
+func (c *casbinxClient) CanAccessResource(userKey, tenantKey string, resource core.Resource) (_ bool, err error) {
+	defer c.guard.recover("CanAccessResource", &err)
+	return c.checkManager.CanAccessResource(c.ctx, userKey, tenantKey, resource)
 }
 
-func (c *casbinxClient) CanAccessTenant(userKey, tenantKey string) (bool, error) {
-	return c.checkManager.CanAccessTenant(userKey, tenantKey)
+func (c *casbinxClient) CanAccessTenant(userKey, tenantKey string) (_ bool, err error) {
+	defer c.guard.recover("CanAccessTenant", &err)
+	return c.checkManager.CanAccessTenant(c.ctx, userKey, tenantKey)
 }
 
-func (c *casbinxClient) GetAvailableActions(userKey, tenantKey string, resource core.Resource) ([]core.Action, error) {
-	return c.checkManager.GetAvailableActions(userKey, tenantKey, resource)
+func (c *casbinxClient) GetAvailableActions(userKey, tenantKey string, resource core.Resource) (_ []core.Action, err error) {
+	defer c.guard.recover("GetAvailableActions", &err)
+	actions, err := c.checkManager.GetAvailableActions(c.ctx, userKey, tenantKey, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// 追加租户在该资源上定义的自定义操作
+	for _, definition := range c.actions.ListActions(c.ctx, tenantKey, core.ResourceType(resource)) {
+		allowed, err := c.checkManager.CheckPermission(c.ctx, userKey, tenantKey, core.Permission{Resource: resource, Action: definition.Action})
+		if err != nil {
+			return nil, err
+		}
+		if allowed && !slices.Contains(actions, definition.Action) {
+			actions = append(actions, definition.Action)
+		}
+	}
+	return actions, nil
 }
 
-func (c *casbinxClient) GetUserTenants(userKey string) ([]string, error) {
-	return c.checkManager.GetUserTenants(userKey)
+func (c *casbinxClient) GetUserTenants(userKey string) (_ []string, err error) {
+	defer c.guard.recover("GetUserTenants", &err)
+	return c.checkManager.GetUserTenants(c.ctx, userKey)
 }
 
 // 角色权限管理方法实现
-func (c *casbinxClient) CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error {
-	// 安全检查：验证角色中的权限
-	for _, permission := range permissions {
-		// 使用新的带域验证方法
-		if err := c.securityValidator.ValidatePermissionGrantWithDomain(operatorKey, roleKey, tenantKey, permission); err != nil {
-			return fmt.Errorf("角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+func (c *casbinxClient) CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("CreateRole", &err)
+	return c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "CreateRole", append([]string{operatorKey, roleKey, roleName, description, tenantKey}, permissionStrings(permissions)...), func() error {
+		// 安全检查：验证角色中的权限
+		for _, permission := range permissions {
+			// 使用新的带域验证方法
+			if err := c.securityValidator.ValidatePermissionGrantWithDomain(operatorKey, roleKey, tenantKey, permission); err != nil {
+				return fmt.Errorf("角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+			}
+		}
+
+		if err := c.roleManager.CreateRole(c.ctx, operatorKey, roleKey, roleName, description, tenantKey, permissions); err != nil {
+			return err
 		}
-	}
 
-	return c.roleManager.CreateRole(operatorKey, roleKey, roleName, description, tenantKey, permissions)
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionCreate, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		c.emitSystemRoleEvent("create", operatorKey, roleKey, tenantKey, permissions)
+		return nil
+	})
 }
 
-func (c *casbinxClient) UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error {
-	// 检查全局角色操作权限
-	if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
-		return err
-	}
+func (c *casbinxClient) UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("UpdateRole", &err)
+	return c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "UpdateRole", append([]string{operatorKey, roleKey, roleName, description, tenantKey}, permissionStrings(permissions)...), func() error {
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
 
-	// 获取角色的旧权限
-	oldPermissions, err := c.roleManager.GetRolePermissions(roleKey)
-	if err != nil {
-		return fmt.Errorf("获取角色旧权限失败: %w", err)
-	}
+		// 检查全局角色操作权限
+		if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
+			return err
+		}
+
+		// 获取角色的旧权限
+		oldPermissions, err := c.roleManager.GetRolePermissions(c.ctx, roleKey)
+		if err != nil {
+			return fmt.Errorf("获取角色旧权限失败: %w", err)
+		}
 
-	// 找出新增和删除的权限
-	addedPermissions := findAddedPermissions(oldPermissions, permissions)
-	removedPermissions := findRemovedPermissions(oldPermissions, permissions)
+		// 找出新增和删除的权限
+		addedPermissions := core.DifferencePermissions(permissions, oldPermissions)
+		removedPermissions := core.DifferencePermissions(oldPermissions, permissions)
 
-	// 安全检查：只验证新增的权限
-	for _, permission := range addedPermissions {
-		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, tenantKey, permission); err != nil {
-			return fmt.Errorf("新增角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+		// 安全检查：只验证新增的权限
+		for _, permission := range addedPermissions {
+			if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, tenantKey, permission); err != nil {
+				return fmt.Errorf("新增角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+			}
 		}
-	}
 
-	// 安全检查：只验证删除的权限（防止删除系统权限）
-	for _, permission := range removedPermissions {
-		if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, tenantKey, permission); err != nil {
-			return fmt.Errorf("删除角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+		// 安全检查：只验证删除的权限（防止删除系统权限）
+		for _, permission := range removedPermissions {
+			if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, tenantKey, permission); err != nil {
+				return fmt.Errorf("删除角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+			}
+		}
+
+		if err := c.roleManager.UpdateRole(c.ctx, operatorKey, roleKey, roleName, description, tenantKey, permissions); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionUpdate, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		c.recordRolePermissionAudits(opts, operatorKey, roleKey, tenantKey, addedPermissions, removedPermissions)
+		c.emitSystemRoleEvent("update", operatorKey, roleKey, tenantKey, addedPermissions, removedPermissions)
+		return nil
+	})
+}
+
+func (c *casbinxClient) DeleteRole(roleKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("DeleteRole", &err)
+	return c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "DeleteRole", []string{roleKey}, func() error {
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
+
+		// 删除前读取角色归属租户和权限，用于审计记录和敏感事件
+		tenantKey := ""
+		var permissions []core.Permission
+		if existing, err := c.roleManager.GetRole(c.ctx, roleKey); err == nil && existing != nil {
+			tenantKey = existing.TenantKey
+			permissions = existing.Permissions
+		}
+
+		if err := c.roleManager.DeleteRole(c.ctx, roleKey); err != nil {
+			return err
+		}
+
+		if err := c.denies.DeleteRole(c.ctx, roleKey); err != nil {
+			return err
 		}
-	}
 
-	return c.roleManager.UpdateRole(operatorKey, roleKey, roleName, description, tenantKey, permissions)
+		// DeleteRole 没有操作者参数，操作者通过 core.WithOperator 指定
+		operatorKey := core.ApplyMutationOptions(opts).OperatorKey
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionDelete, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		c.emitSystemRoleEvent("delete", operatorKey, roleKey, tenantKey, permissions)
+		return nil
+	})
 }
 
-func (c *casbinxClient) DeleteRole(roleKey string) error {
-	return c.roleManager.DeleteRole(roleKey)
+func (c *casbinxClient) GetRole(roleKey string) (_ *core.Role, err error) {
+	defer c.guard.recover("GetRole", &err)
+	return c.roleManager.GetRole(c.ctx, roleKey)
 }
 
-func (c *casbinxClient) GetRole(roleKey string) (*core.Role, error) {
-	return c.roleManager.GetRole(roleKey)
+func (c *casbinxClient) ListRoles(tenantKey string, filter *core.RoleFilter) (_ []*core.Role, err error) {
+	defer c.guard.recover("ListRoles", &err)
+	return c.roleManager.ListRoles(c.ctx, tenantKey, filter)
 }
 
-func (c *casbinxClient) ListRoles(tenantKey string, filter *core.RoleFilter) ([]*core.Role, error) {
-	return c.roleManager.ListRoles(tenantKey, filter)
+func (c *casbinxClient) GetRolePermissions(roleKey string) (_ []core.Permission, err error) {
+	defer c.guard.recover("GetRolePermissions", &err)
+	return c.roleManager.GetRolePermissions(c.ctx, roleKey)
 }
 
-func (c *casbinxClient) GetRolePermissions(roleKey string) ([]core.Permission, error) {
-	return c.roleManager.GetRolePermissions(roleKey)
+// GetRoleEffectivePermissions 获取角色有效权限，每条权限标注贡献它的角色和继承链
+func (c *casbinxClient) GetRoleEffectivePermissions(roleKey string) (_ []core.InheritedPermission, err error) {
+	defer c.guard.recover("GetRoleEffectivePermissions", &err)
+	return c.roleManager.GetRoleEffectivePermissions(c.ctx, roleKey)
 }
 
-func (c *casbinxClient) GrantRolePermission(operatorKey, roleKey string, permission core.Permission) error {
-	// 检查全局角色操作权限
-	if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
-		return err
-	}
+func (c *casbinxClient) GrantRolePermission(operatorKey, roleKey string, permission core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("GrantRolePermission", &err)
+	return c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "GrantRolePermission", []string{operatorKey, roleKey, permission.String()}, func() error {
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
 
-	// 获取角色信息确定其租户域
-	role, err := c.roleManager.GetRole(roleKey)
-	if err != nil {
-		return fmt.Errorf("获取角色信息失败: %w", err)
-	}
+		// 检查全局角色操作权限
+		if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
+			return err
+		}
 
-	// 使用角色所属的租户域（不会为空，默认为"*"表示全局角色）
-	roleTenantKey := role.TenantKey
+		// 获取角色信息确定其租户域
+		role, err := c.roleManager.GetRole(c.ctx, roleKey)
+		if err != nil {
+			return fmt.Errorf("获取角色信息失败: %w", err)
+		}
 
-	// 安全检查：验证权限授予
-	permissionToValidate := core.Permission{Resource: permission.Resource, Action: permission.Action}
-	if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, roleTenantKey, permissionToValidate); err != nil {
-		return err
-	}
+		// 使用角色所属的租户域（不会为空，默认为"*"表示全局角色）
+		roleTenantKey := role.TenantKey
 
-	return c.roleManager.GrantPermission(operatorKey, roleKey, permission)
+		// 安全检查：验证权限授予
+		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, roleTenantKey, permission); err != nil {
+			return err
+		}
+
+		environments := core.ApplyMutationOptions(opts).Environments
+		if err := c.environments.SetPolicyEnvironments(c.ctx, roleKey, roleTenantKey, permission, environments); err != nil {
+			return err
+		}
+
+		if err := c.roleManager.GrantPermission(c.ctx, operatorKey, roleKey, permission); err != nil {
+			return err
+		}
+
+		c.recordRolePermissionAudits(opts, operatorKey, roleKey, roleTenantKey, []core.Permission{permission}, nil)
+		c.emitSystemRoleEvent("grant", operatorKey, roleKey, roleTenantKey, []core.Permission{permission})
+		return nil
+	})
 }
 
-func (c *casbinxClient) RevokeRolePermission(operatorKey, roleKey string, permission core.Permission) error {
-	// 检查全局角色操作权限
-	if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
-		return err
-	}
+func (c *casbinxClient) RevokeRolePermission(operatorKey, roleKey string, permission core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("RevokeRolePermission", &err)
+	return c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "RevokeRolePermission", []string{operatorKey, roleKey, permission.String()}, func() error {
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
 
-	// 获取角色信息确定其租户域
-	role, err := c.roleManager.GetRole(roleKey)
-	if err != nil {
-		return fmt.Errorf("获取角色信息失败: %w", err)
-	}
+		// 检查全局角色操作权限
+		if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
+			return err
+		}
 
-	// 使用角色所属的租户域（不会为空，默认为"*"表示全局角色）
-	roleTenantKey := role.TenantKey
+		// 获取角色信息确定其租户域
+		role, err := c.roleManager.GetRole(c.ctx, roleKey)
+		if err != nil {
+			return fmt.Errorf("获取角色信息失败: %w", err)
+		}
 
-	// 安全检查：验证权限撤销
-	permissionToValidate := core.Permission{Resource: permission.Resource, Action: permission.Action}
-	if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, roleTenantKey, permissionToValidate); err != nil {
-		return err
-	}
+		// 使用角色所属的租户域（不会为空，默认为"*"表示全局角色）
+		roleTenantKey := role.TenantKey
+
+		// 安全检查：验证权限撤销
+		if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, roleTenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.roleManager.RevokePermission(c.ctx, operatorKey, roleKey, permission); err != nil {
+			return err
+		}
 
-	return c.roleManager.RevokePermission(operatorKey, roleKey, permission)
+		if err := c.priorities.ClearPolicyPriority(c.ctx, core.SubjectKindRole, roleKey, roleTenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindRole, roleKey, roleTenantKey, permission); err != nil {
+			return err
+		}
+
+		if err := c.environments.ClearPolicyEnvironments(c.ctx, roleKey, roleTenantKey, permission); err != nil {
+			return err
+		}
+
+		c.recordRolePermissionAudits(opts, operatorKey, roleKey, roleTenantKey, nil, []core.Permission{permission})
+		c.emitSystemRoleEvent("revoke", operatorKey, roleKey, roleTenantKey, []core.Permission{permission})
+		return nil
+	})
 }
 
-func (c *casbinxClient) SetRolePermissions(operatorKey, roleKey string, permissions []core.Permission) error {
-	// 检查全局角色操作权限
-	if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
-		return err
-	}
+func (c *casbinxClient) SetRolePermissions(operatorKey, roleKey string, permissions []core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("SetRolePermissions", &err)
+	return c.runQueued(c.enforcer.RoleSubject(roleKey), opts, "SetRolePermissions", append([]string{operatorKey, roleKey}, permissionStrings(permissions)...), func() error {
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
 
-	// 获取角色信息确定其租户域
-	role, err := c.roleManager.GetRole(roleKey)
-	if err != nil {
-		return fmt.Errorf("获取角色信息失败: %w", err)
-	}
+		// 检查全局角色操作权限
+		if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
+			return err
+		}
+
+		// 获取角色信息确定其租户域
+		role, err := c.roleManager.GetRole(c.ctx, roleKey)
+		if err != nil {
+			return fmt.Errorf("获取角色信息失败: %w", err)
+		}
 
-	// 使用角色所属的租户域（不会为空，默认为"*"表示全局角色）
-	roleTenantKey := role.TenantKey
+		// 使用角色所属的租户域（不会为空，默认为"*"表示全局角色）
+		roleTenantKey := role.TenantKey
 
-	// 获取角色的旧权限
-	oldPermissions := role.Permissions
+		// 获取角色的旧权限
+		oldPermissions := role.Permissions
 
-	// 找出新增和删除的权限
-	addedPermissions := findAddedPermissions(oldPermissions, permissions)
-	removedPermissions := findRemovedPermissions(oldPermissions, permissions)
+		// 找出新增和删除的权限
+		addedPermissions := core.DifferencePermissions(permissions, oldPermissions)
+		removedPermissions := core.DifferencePermissions(oldPermissions, permissions)
 
-	// 安全检查：只验证新增的权限
-	for _, permission := range addedPermissions {
-		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, roleTenantKey, permission); err != nil {
-			return fmt.Errorf("新增角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+		// 安全检查：只验证新增的权限
+		for _, permission := range addedPermissions {
+			if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, roleTenantKey, permission); err != nil {
+				return fmt.Errorf("新增角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+			}
 		}
-	}
 
-	// 安全检查：只验证删除的权限（防止删除系统权限）
-	for _, permission := range removedPermissions {
-		if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, roleTenantKey, permission); err != nil {
-			return fmt.Errorf("删除角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+		// 安全检查：只验证删除的权限（防止删除系统权限）
+		for _, permission := range removedPermissions {
+			if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, roleTenantKey, permission); err != nil {
+				return fmt.Errorf("删除角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+			}
 		}
-	}
 
-	return c.roleManager.SetRolePermissions(roleKey, permissions)
+		if err := c.roleManager.SetRolePermissions(c.ctx, roleKey, permissions); err != nil {
+			return err
+		}
+
+		c.recordRolePermissionAudits(opts, operatorKey, roleKey, roleTenantKey, addedPermissions, removedPermissions)
+		c.emitSystemRoleEvent("update", operatorKey, roleKey, roleTenantKey, addedPermissions, removedPermissions)
+		return nil
+	})
+}
+
+func (c *casbinxClient) GetUsersWithRole(roleKey, tenantKey string) (_ []string, err error) {
+	defer c.guard.recover("GetUsersWithRole", &err)
+	return c.roleManager.GetUsersWithRole(c.ctx, roleKey, tenantKey)
 }
 
-func (c *casbinxClient) GetUsersWithRole(roleKey, tenantKey string) ([]string, error) {
-	return c.roleManager.GetUsersWithRole(roleKey, tenantKey)
+func (c *casbinxClient) GetUsersWithPermission(tenantKey string, permission core.Permission) (_ []string, err error) {
+	defer c.guard.recover("GetUsersWithPermission", &err)
+	return c.roleManager.GetUsersWithPermission(c.ctx, tenantKey, permission)
 }
 
-func (c *casbinxClient) GetAllGroupingPolicies(tenantKey string) ([]core.GroupingPolicy, error) {
-	return c.roleManager.GetAllGroupingPolicies(tenantKey)
+func (c *casbinxClient) GetAllGroupingPolicies(tenantKey string) (_ []core.GroupingPolicy, err error) {
+	defer c.guard.recover("GetAllGroupingPolicies", &err)
+	return c.roleManager.GetAllGroupingPolicies(c.ctx, tenantKey)
 }
 
 // CheckPermission 权限检查快捷方法
-func (c *casbinxClient) CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error) {
-	return c.checkManager.CheckPermission(userKey, tenantKey, permission)
+func (c *casbinxClient) CheckPermission(userKey, tenantKey string, permission core.Permission) (_ bool, err error) {
+	defer c.guard.recover("CheckPermission", &err)
+	return c.checkManager.CheckPermission(c.ctx, userKey, tenantKey, permission)
 }
 
-// InitializeTenant 初始化租户并分配管理员
-func (c *casbinxClient) InitializeTenant(tenantKey, adminUserKey, adminRoleKey string) error {
+// CheckPermissionVersioned 检查用户权限并返回检查时的策略修订号
+// 修订号在检查之前读取，检查期间发生的策略变更会使修订号增加，调用方据此判断缓存的结果已过期
+func (c *casbinxClient) CheckPermissionVersioned(userKey, tenantKey string, permission core.Permission) (_ *core.CheckResult, err error) {
+	defer c.guard.recover("CheckPermissionVersioned", &err)
+	revision := c.enforcer.Revision()
+	allowed, err := c.checkManager.CheckPermission(c.ctx, userKey, tenantKey, permission)
+	if err != nil {
+		return nil, err
+	}
+	return &core.CheckResult{Allowed: allowed, Revision: revision}, nil
+}
 
-	// 该接口是为了确保系统权限被限制时，在初始化租户的场景仍然能分配系统权限
+// GetPolicyRevision 获取本实例当前的策略修订号
+func (c *casbinxClient) GetPolicyRevision() uint64 {
+	return c.enforcer.Revision()
+}
 
-	// 验证参数
-	if tenantKey == "" || adminUserKey == "" || adminRoleKey == "" {
-		return core.ErrInvalidParameter
-	}
+// EnforceWithAttributes 检查用户权限并按请求属性评估规则条件
+func (c *casbinxClient) EnforceWithAttributes(userKey, tenantKey string, permission core.Permission, attrs map[string]any) (_ bool, err error) {
+	defer c.guard.recover("EnforceWithAttributes", &err)
+	return c.checkManager.EnforceWithAttributes(c.ctx, userKey, tenantKey, permission, attrs)
+}
 
-	// 1. 检查角色是否存在
-	role, err := c.GetRole(adminRoleKey)
-	if err != nil {
-		return fmt.Errorf("指定的管理员角色 '%s' 不存在", adminRoleKey)
-	}
+// CheckPermissionDebug 检查用户权限并返回有序的评估步骤（查询的域、展开的角色、匹配的规则）
+func (c *casbinxClient) CheckPermissionDebug(userKey, tenantKey string, permission core.Permission) (_ *core.CheckTrace, err error) {
+	defer c.guard.recover("CheckPermissionDebug", &err)
+	return c.checkManager.CheckPermissionDebug(c.ctx, userKey, tenantKey, permission)
+}
+
+// ExplainPermission 检查用户权限并说明结果的成因（直接授权、角色、全局角色或继承的角色）
+func (c *casbinxClient) ExplainPermission(userKey, tenantKey string, permission core.Permission) (_ *core.PermissionExplanation, err error) {
+	defer c.guard.recover("ExplainPermission", &err)
+	return c.checkManager.ExplainPermission(c.ctx, userKey, tenantKey, permission)
+}
 
-	// 2. 检查角色权限是否符合租户管理员要求
-	hasTenantPermissions := false
-	hasSystemBasePermissions := false
+// InitializeTenant 初始化租户并分配管理员
+func (c *casbinxClient) InitializeTenant(tenantKey, adminUserKey, adminRoleKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("InitializeTenant", &err)
+	return c.runQueued(c.enforcer.UserSubject(adminUserKey), opts, "InitializeTenant", []string{tenantKey, adminUserKey, adminRoleKey}, func() error {
+		// 该接口是为了确保系统权限被限制时，在初始化租户的场景仍然能分配系统权限
+
+		// 验证参数
+		if err := core.FirstError(
+			core.RequireArg("tenantKey", tenantKey),
+			core.RequireArg("adminUserKey", adminUserKey),
+			core.RequireArg("adminRoleKey", adminRoleKey),
+		); err != nil {
+			return err
+		}
 
-	for _, perm := range role.Permissions {
-		// 检查是否包含租户管理权限（不允许）
-		if perm.Resource == core.ResourceTenant || perm.Resource == core.ResourceTagTenant {
-			hasTenantPermissions = true
+		// 1. 检查角色是否存在
+		role, err := c.GetRole(adminRoleKey)
+		if err != nil {
+			return fmt.Errorf("指定的管理员角色 '%s' 不存在", adminRoleKey)
 		}
-		// 检查是否包含系统基础权限（必须）
-		// 系统基础权限包括：system, user, permission, role, tag_user，但不包括 tenant 和 tag_tenant
-		if perm.Resource == core.ResourceSystem ||
-			perm.Resource == core.ResourceUser ||
-			perm.Resource == core.ResourcePermission ||
-			perm.Resource == core.ResourceRole ||
-			perm.Resource == core.ResourceTagUser {
-			hasSystemBasePermissions = true
+
+		// 2. 检查角色权限是否符合租户管理员要求
+		hasTenantPermissions := false
+		hasSystemBasePermissions := false
+
+		for _, perm := range role.Permissions {
+			// 检查是否包含租户管理权限（不允许）
+			if perm.Resource == core.ResourceTenant || perm.Resource == core.ResourceTagTenant {
+				hasTenantPermissions = true
+			}
+			// 检查是否包含系统基础权限（必须）
+			// 系统基础权限包括：system, user, permission, role, tag_user，但不包括 tenant 和 tag_tenant
+			if perm.Resource == core.ResourceSystem ||
+				perm.Resource == core.ResourceUser ||
+				perm.Resource == core.ResourcePermission ||
+				perm.Resource == core.ResourceRole ||
+				perm.Resource == core.ResourceTagUser {
+				hasSystemBasePermissions = true
+			}
 		}
-	}
 
-	// 不允许有租户管理权限
-	if hasTenantPermissions {
-		return fmt.Errorf("角色 '%s' 包含租户管理权限，租户内管理员不允许跨租户操作", adminRoleKey)
-	}
+		// 不允许有租户管理权限
+		if hasTenantPermissions {
+			return fmt.Errorf("角色 '%s' 包含租户管理权限，租户内管理员不允许跨租户操作", adminRoleKey)
+		}
 
-	// 必须有系统权限
-	if !hasSystemBasePermissions {
-		return fmt.Errorf("角色 '%s' 缺少系统级权限，无法作为租户管理员角色", adminRoleKey)
-	}
+		// 必须有系统权限
+		if !hasSystemBasePermissions {
+			return fmt.Errorf("角色 '%s' 缺少系统级权限，无法作为租户管理员角色", adminRoleKey)
+		}
+
+		// 3. 登记租户（已登记时不做处理）
+		if err := c.tenantManager.EnsureTenant(c.ctx, adminUserKey, tenantKey); err != nil {
+			return err
+		}
 
-	// 3. 分配角色给管理员用户（绕过系统权限检查）
-	return c.userManager.AssignRole("system", adminUserKey, adminRoleKey, tenantKey)
+		// 4. 分配角色给管理员用户（绕过系统权限检查）
+		if err := c.userManager.AssignRole(c.ctx, "system", adminUserKey, adminRoleKey, tenantKey); err != nil {
+			return err
+		}
+
+		c.emitSecurityEvent(core.SecurityEvent{
+			Class:     core.SecurityEventSystemRole,
+			Action:    "assign",
+			TenantKey: tenantKey,
+			Target:    adminRoleKey,
+			Detail:    "租户管理员 " + adminUserKey,
+		})
+		return nil
+	})
 }
 
 // hasGlobalRoleAssignments 检查角色是否有全局域分配
 func (c *casbinxClient) hasGlobalRoleAssignments(roleKey string) (bool, error) {
 	// 获取在全局域分配该角色的用户
-	users, err := c.roleManager.GetUsersWithRole(roleKey, "*")
+	users, err := c.roleManager.GetUsersWithRole(c.ctx, roleKey, "*")
 	if err != nil {
 		return false, err
 	}
@@ -588,7 +1222,7 @@ func (c *casbinxClient) hasGlobalRoleAssignments(roleKey string) (bool, error) {
 
 // hasGlobalPermission 检查用户是否有全局权限
 func (c *casbinxClient) hasGlobalPermission(operatorKey string, permission core.Permission) (bool, error) {
-	return c.checkManager.CheckPermission(operatorKey, "*", permission)
+	return c.checkManager.CheckPermission(c.ctx, operatorKey, "*", permission)
 }
 
 // validateGlobalRoleOperation 验证全局角色操作权限
@@ -605,19 +1239,7 @@ func (c *casbinxClient) validateGlobalRoleOperation(operatorKey, roleKey string)
 	}
 
 	// 检查操作者是否有全局权限（角色管理权限）
-	hasGlobalPerm, err := c.hasGlobalPermission(operatorKey, core.Permission{
-		Resource: core.ResourceRole,
-		Action:   core.ActionWrite,
-	})
-	if err != nil {
-		return fmt.Errorf("检查全局权限失败: %w", err)
-	}
-
-	if !hasGlobalPerm {
-		return core.ErrGlobalRoleAccessDenied
-	}
-
-	return nil
+	return c.requireGlobalRoleManagement(operatorKey)
 }
 
 // isTenantInitializationScenario 判断是否为租户初始化场景
@@ -642,43 +1264,87 @@ func (c *casbinxClient) isTenantInitializationScenario(userKey, roleKey, tenantK
 			userKey[len(userKey)-9:len(userKey)-4] == "-admin-")
 }
 
+// GetGrantEnvironments 获取授权生效的环境列表，未标记环境时返回空列表（所有环境生效）
+func (c *casbinxClient) GetGrantEnvironments(subjectKey, tenantKey string, permission core.Permission) (_ []string, err error) {
+	defer c.guard.recover("GetGrantEnvironments", &err)
+	if err := core.FirstError(
+		core.RequireArg("subjectKey", subjectKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequirePermissionArg("permission", permission),
+	); err != nil {
+		return nil, err
+	}
+
+	environments := c.environments.GetPolicyEnvironments(c.ctx, subjectKey, tenantKey, permission)
+	if environments == nil {
+		return []string{}, nil
+	}
+	return environments, nil
+}
+
 // === Watcher 管理方法实现 ===
 
 // RefreshPolicy 手动刷新策略（从数据库重新加载）
-func (c *casbinxClient) RefreshPolicy() error {
-	return c.policyManager.RefreshPolicy()
+func (c *casbinxClient) RefreshPolicy() (err error) {
+	defer c.guard.recover("RefreshPolicy", &err)
+	return c.policyManager.RefreshPolicy(c.ctx)
+}
+
+// ExportPolicies 导出策略快照（Casbin 文件适配器格式），可配合 offline 包在无数据库环境中校验权限
+// 策略逐条写出，不在内存中构造完整的快照
+func (c *casbinxClient) ExportPolicies(w io.Writer) (err error) {
+	defer c.guard.recover("ExportPolicies", &err)
+	writer := offline.NewPolicyWriter(w)
+	if err := c.streamPolicies("", writer.WritePolicy); err != nil {
+		return err
+	}
+	if err := c.enforcer.RangeGroupingPolicies("", writer.WriteGrouping); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// GetReloadStats 获取策略同步重载队列统计
+func (c *casbinxClient) GetReloadStats() core.ReloadStats {
+	return c.reloadCoalescer.Stats()
 }
 
-// === 权限对比辅助函数 ===
+// CleanupPlaceholders 清理多余的角色占位策略（维护接口）
+func (c *casbinxClient) CleanupPlaceholders() (_ int, err error) {
+	defer c.guard.recover("CleanupPlaceholders", &err)
+	return c.roleManager.CleanupPlaceholders(c.ctx)
+}
 
-// permissionExists 检查权限是否存在于权限列表中
-func permissionExists(permission core.Permission, permissions []core.Permission) bool {
-	for _, p := range permissions {
-		if p.Resource == permission.Resource && p.Action == permission.Action {
-			return true
-		}
+// GetDecisionLogStats 获取决策日志缓冲统计
+func (c *casbinxClient) GetDecisionLogStats() core.DecisionLogStats {
+	if c.decisions == nil {
+		return core.DecisionLogStats{}
 	}
-	return false
+	return c.decisions.Stats()
 }
 
-// findAddedPermissions 找出新增的权限（在新权限中但不在旧权限中）
-func findAddedPermissions(oldPermissions, newPermissions []core.Permission) []core.Permission {
-	var added []core.Permission
-	for _, newPerm := range newPermissions {
-		if !permissionExists(newPerm, oldPermissions) {
-			added = append(added, newPerm)
-		}
+// FlushDecisionLog 立即写入缓冲中的决策日志（例如在进程退出前调用）
+func (c *casbinxClient) FlushDecisionLog() (err error) {
+	defer c.guard.recover("FlushDecisionLog", &err)
+	if c.decisions == nil {
+		return nil
 	}
-	return added
+	return c.decisions.Flush(c.ctx)
 }
 
-// findRemovedPermissions 找出删除的权限（在旧权限中但不在新权限中）
-func findRemovedPermissions(oldPermissions, newPermissions []core.Permission) []core.Permission {
-	var removed []core.Permission
-	for _, oldPerm := range oldPermissions {
-		if !permissionExists(oldPerm, newPermissions) {
-			removed = append(removed, oldPerm)
-		}
+// MigrateSubjectNamespaces 将存量策略迁移为带命名空间前缀的主体
+// 迁移前的策略在命名空间模式下无法被识别，因此不校验操作者权限，应由部署流程在启用配置后执行
+func (c *casbinxClient) MigrateSubjectNamespaces() (_ *core.SubjectMigrationReport, err error) {
+	defer c.guard.recover("MigrateSubjectNamespaces", &err)
+	return c.roleManager.MigrateSubjectNamespaces(c.ctx)
+}
+
+// BatchNotifications 在 fn 执行期间暂停同步通知，结束后只发布一次
+// fn 中可以调用任意变更方法；暂停期间本实例立即可见变更，其他实例在通知发布后统一重新加载
+func (c *casbinxClient) BatchNotifications(fn func() error) (err error) {
+	defer c.guard.recover("BatchNotifications", &err)
+	if err := core.CheckArg(fn != nil, "fn", core.ConstraintRequired); err != nil {
+		return err
 	}
-	return removed
+	return c.enforcer.BatchNotifications(fn)
 }
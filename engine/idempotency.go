@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// runIdempotent 按幂等键执行变更操作
+// 未指定幂等键时直接执行；指定时同一个键在有效期内只会成功执行一次，重试请求直接返回成功
+func (c *casbinxClient) runIdempotent(opts []core.MutationOption, operation string, args []string, fn func() error) error {
+	// 事务外的变更等待本实例执行中的事务结束，事务内的变更直接执行
+	if c.tx == nil {
+		c.txGate.enter()
+		defer c.txGate.exit()
+	}
+
+	options := core.ApplyMutationOptions(opts)
+	if options.IdempotencyKey == "" {
+		return fn()
+	}
+
+	// 指纹由操作名和参数组成，用于识别同一个键被复用到不同操作的情况
+	fingerprint := operation + ":" + strings.Join(append(args, options.Environments...), "|")
+
+	acquired, err := c.idempotency.Acquire(c.ctx, options.IdempotencyKey, fingerprint)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		// 相同操作已成功执行，直接返回
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		// 操作失败时释放幂等键，允许客户端使用同一个键重试；释放不受调用上下文取消影响
+		if releaseErr := c.idempotency.Release(context.Background(), options.IdempotencyKey); releaseErr != nil {
+			return fmt.Errorf("%w（释放幂等键失败: %v）", err, releaseErr)
+		}
+		return err
+	}
+
+	return c.idempotency.Complete(c.ctx, options.IdempotencyKey)
+}
+
+// permissionStrings 将权限列表转换为字符串列表（用于生成操作指纹）
+func permissionStrings(permissions []core.Permission) []string {
+	result := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, permission.String())
+	}
+	return result
+}
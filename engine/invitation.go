@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// CreateShareInvitation 创建分享邀请（"通过链接分享"）
+// 操作者需要能够在租户内授予邀请中的每个权限；返回的令牌只出现这一次，应由调用方放入分享链接
+func (c *casbinxClient) CreateShareInvitation(operatorKey, tenantKey string, permissions []core.Permission, expiry time.Duration, maxUses int) (_ *core.ShareInvitation, err error) {
+	defer c.guard.recover("CreateShareInvitation", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return nil, err
+	}
+
+	// 创建时还不知道接受者，目标用户留空，仅校验系统权限和操作者权限
+	for _, permission := range permissions {
+		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, "", tenantKey, permission); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.invitations.CreateInvitation(c.ctx, operatorKey, tenantKey, permissions, expiry, maxUses)
+}
+
+// AcceptInvitation 接受分享邀请，为用户授予邀请中的权限并记录授权来源
+// 授权以邀请创建者的身份进行并重新校验，创建者失去授权能力后邀请随之失效；
+// 任一权限授予失败时撤销本次新授予的权限并归还使用次数
+func (c *casbinxClient) AcceptInvitation(userKey, token string) (_ *core.ShareInvitation, err error) {
+	defer c.guard.recover("AcceptInvitation", &err)
+	invitation, err := c.invitations.ConsumeInvitation(c.ctx, userKey, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var granted []core.Permission
+	for _, permission := range invitation.Permissions {
+		if err := c.securityValidator.ValidatePermissionGrant(invitation.CreatedBy, userKey, invitation.TenantKey, permission); err != nil {
+			return nil, c.rollbackInvitationGrants(invitation, userKey, granted, err)
+		}
+
+		exists, err := c.checkManager.HasDirectPermission(c.ctx, userKey, invitation.TenantKey, permission)
+		if err != nil {
+			return nil, c.rollbackInvitationGrants(invitation, userKey, granted, err)
+		}
+		if exists {
+			continue
+		}
+
+		if err := c.userManager.GrantPermission(c.ctx, invitation.CreatedBy, userKey, invitation.TenantKey, permission); err != nil {
+			return nil, c.rollbackInvitationGrants(invitation, userKey, granted, err)
+		}
+		granted = append(granted, permission)
+	}
+
+	// 仅记录本次新授予的权限，用户此前已拥有的权限来源不变
+	if err := c.invitations.RecordGrants(c.ctx, invitation, userKey, granted); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// RevokeShareInvitation 撤销分享邀请，已接受邀请的用户保留已授予的权限
+// 邀请创建者可以撤销自己的邀请，其他操作者需要租户内的权限管理权限
+func (c *casbinxClient) RevokeShareInvitation(operatorKey, invitationID string) (err error) {
+	defer c.guard.recover("RevokeShareInvitation", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return err
+	}
+
+	invitation, err := c.invitations.GetInvitation(c.ctx, invitationID)
+	if err != nil {
+		return err
+	}
+
+	if invitation.CreatedBy != operatorKey {
+		hasPermission, err := c.checkManager.CheckPermission(c.ctx, operatorKey, invitation.TenantKey, core.Permission{
+			Resource: core.ResourcePermission,
+			Action:   core.ActionWrite,
+		})
+		if err != nil {
+			return err
+		}
+		if !hasPermission {
+			return core.ErrPermissionDenied
+		}
+	}
+
+	return c.invitations.RevokeInvitation(c.ctx, invitationID)
+}
+
+// GetInvitationGrants 获取用户在租户内通过分享邀请获得且仍然有效的授权来源（需要权限验证）
+func (c *casbinxClient) GetInvitationGrants(operatorKey, userKey, tenantKey string) (_ []core.InvitationGrant, err error) {
+	defer c.guard.recover("GetInvitationGrants", &err)
+	if err := c.validateQueryPermission(operatorKey, userKey, tenantKey); err != nil {
+		return nil, err
+	}
+
+	return c.invitations.GetInvitationGrants(c.ctx, userKey, tenantKey)
+}
+
+// rollbackInvitationGrants 撤销接受邀请过程中已授予的权限并归还使用次数，返回原始错误
+// 回滚不受调用上下文取消影响，避免上下文超时导致授权残留
+func (c *casbinxClient) rollbackInvitationGrants(invitation *core.ShareInvitation, userKey string, granted []core.Permission, cause error) error {
+	for _, permission := range granted {
+		if err := c.userManager.RevokePermission(context.Background(), invitation.CreatedBy, userKey, invitation.TenantKey, permission); err != nil {
+			return fmt.Errorf("%w（回滚已授予的权限失败: %v）", cause, err)
+		}
+	}
+
+	if err := c.invitations.ReleaseInvitation(context.Background(), invitation.ID, userKey); err != nil {
+		return fmt.Errorf("%w（归还邀请使用次数失败: %v）", cause, err)
+	}
+
+	return cause
+}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// jobProgressInterval 任务进度写入数据库的最小间隔，取消请求同样按该间隔从数据库读取
+const jobProgressInterval = time.Second
+
+// jobProgress 批量操作的进度回调，每处理完一项调用一次；返回 core.ErrJobCanceled 时操作应停止
+// 同步调用时为 nil，不报告进度
+type jobProgress func(progress core.JobProgress) error
+
+// report 报告进度（done 为已处理项数）
+func (p jobProgress) report(done, total int) error {
+	if p == nil {
+		return nil
+	}
+	return p(core.JobProgress{Done: done, Total: total})
+}
+
+// SubmitJob 提交异步任务，立即返回执行中的任务
+// 操作者权限在提交时按同步方法的规则校验；任务在当前实例的后台协程中执行，不受调用上下文的取消和超时影响
+func (c *casbinxClient) SubmitJob(operatorKey string, spec core.JobSpec) (_ *core.Job, err error) {
+	defer c.guard.recover("SubmitJob", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return nil, err
+	}
+
+	total, err := c.validateJob(operatorKey, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.jobs.CreateJob(c.ctx, operatorKey, spec, total)
+	if err != nil {
+		return nil, err
+	}
+
+	canceled := &atomic.Bool{}
+	c.runningJobs.Store(job.ID, canceled)
+	runner := *c
+	runner.ctx = context.WithoutCancel(c.ctx)
+	go runner.runJob(job.ID, operatorKey, spec, canceled)
+
+	return job, nil
+}
+
+// GetJobStatus 获取异步任务的状态、进度和结果
+func (c *casbinxClient) GetJobStatus(jobID string) (_ *core.Job, err error) {
+	defer c.guard.recover("GetJobStatus", &err)
+	return c.jobs.GetJob(c.ctx, jobID)
+}
+
+// CancelJob 请求取消异步任务
+// 任务在处理完当前项后停止，已完成的部分不会回滚；只有提交者或拥有全局系统管理权限的操作者可以取消
+func (c *casbinxClient) CancelJob(operatorKey, jobID string) (_ *core.Job, err error) {
+	defer c.guard.recover("CancelJob", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("jobID", jobID),
+	); err != nil {
+		return nil, err
+	}
+
+	job, err := c.jobs.GetJob(c.ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.OperatorKey != operatorKey {
+		hasPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{Resource: core.ResourceSystem, Action: core.ActionWrite})
+		if err != nil {
+			return nil, fmt.Errorf("检查系统管理权限失败: %w", err)
+		}
+		if !hasPermission {
+			return nil, core.ErrPermissionDenied
+		}
+	}
+
+	// 任务在本实例执行时立即生效，在其他实例执行时由执行实例在下一次写入进度时读取
+	if canceled, ok := c.runningJobs.Load(jobID); ok {
+		canceled.(*atomic.Bool).Store(true)
+	}
+	return c.jobs.RequestCancel(c.ctx, jobID)
+}
+
+// validateJob 按同步方法的规则校验任务参数和操作者权限，返回任务的总项数
+func (c *casbinxClient) validateJob(operatorKey string, spec core.JobSpec) (int, error) {
+	switch spec.Kind {
+	case core.JobKindImportRelationTuples:
+		// 导入逐条校验操作者权限，与同步方法一致
+		return len(spec.RelationTuples), nil
+	case core.JobKindProvisionTenants:
+		if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+			return 0, err
+		}
+		return len(spec.Tenants), nil
+	case core.JobKindDeleteTenant:
+		if err := core.RequireTenantArg("spec.tenantKey", spec.TenantKey); err != nil {
+			return 0, err
+		}
+		if err := c.requireGlobalTenantPermission(operatorKey, core.ActionDelete); err != nil {
+			return 0, err
+		}
+		// 总步骤数取决于租户别名数量，执行时更新
+		return 0, nil
+	default:
+		return 0, &core.FieldError{Field: "spec.kind", Constraint: core.ConstraintOneOf, Detail: "不支持的任务类型 " + string(spec.Kind)}
+	}
+}
+
+// runJob 在后台执行任务并保存结果
+func (c *casbinxClient) runJob(jobID, operatorKey string, spec core.JobSpec, canceled *atomic.Bool) {
+	defer c.runningJobs.Delete(jobID)
+
+	result, err := c.executeJob(operatorKey, spec, c.jobReporter(jobID, canceled))
+
+	status, message := core.JobStatusSucceeded, ""
+	switch {
+	case errors.Is(err, core.ErrJobCanceled):
+		status = core.JobStatusCanceled
+	case err != nil:
+		status, message = core.JobStatusFailed, err.Error()
+	}
+
+	if err := c.jobs.FinishJob(c.ctx, jobID, status, result, message); err != nil {
+		c.guard.logger.Error("保存异步任务结果失败", "jobId", jobID, "status", status, "error", err)
+	}
+}
+
+// executeJob 按任务类型执行对应的批量操作
+func (c *casbinxClient) executeJob(operatorKey string, spec core.JobSpec, progress jobProgress) (_ any, err error) {
+	defer c.guard.recover("Job:"+string(spec.Kind), &err)
+	switch spec.Kind {
+	case core.JobKindImportRelationTuples:
+		return c.importRelationTuples(operatorKey, spec.RelationTuples, progress)
+	case core.JobKindProvisionTenants:
+		return c.provisionTenants(operatorKey, spec.Tenants, progress)
+	case core.JobKindDeleteTenant:
+		return c.deleteTenant(operatorKey, spec.TenantKey, progress)
+	default:
+		return nil, core.NewFieldError("spec.kind", core.ConstraintOneOf)
+	}
+}
+
+// jobReporter 创建任务的进度回调
+// 进度按 jobProgressInterval 节流写入数据库，写入时读取其他实例发出的取消请求；写入失败只记录日志，不中断任务
+func (c *casbinxClient) jobReporter(jobID string, canceled *atomic.Bool) jobProgress {
+	var lastWrite time.Time
+	return func(progress core.JobProgress) error {
+		finished := progress.Done >= progress.Total
+		if canceled.Load() && !finished {
+			return core.ErrJobCanceled
+		}
+		if !finished && time.Since(lastWrite) < jobProgressInterval {
+			return nil
+		}
+
+		lastWrite = time.Now()
+		cancelRequested, err := c.jobs.UpdateProgress(c.ctx, jobID, progress)
+		if err != nil {
+			c.guard.logger.Warn("写入异步任务进度失败", "jobId", jobID, "done", progress.Done, "total", progress.Total, "error", err)
+			return nil
+		}
+		if cancelRequested && !finished {
+			canceled.Store(true)
+			return core.ErrJobCanceled
+		}
+		return nil
+	}
+}
@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// DefineJobFunction 创建或替换租户的岗位（HR 岗位编码到角色集合的映射）
+// 岗位中的角色必须存在且可以在该租户分配；修改岗位不会立即调整已同步用户的角色，下次 SyncUserJobFunction 时生效。
+// 要求操作者拥有租户的 tenant:write 权限
+func (c *casbinxClient) DefineJobFunction(operatorKey string, jobFunction core.JobFunction) (err error) {
+	defer c.guard.recover("DefineJobFunction", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("jobFunction.tenantKey", jobFunction.TenantKey),
+		core.RequireArg("jobFunction.jobCode", jobFunction.JobCode),
+		core.RequireItems("jobFunction.roleKeys", jobFunction.RoleKeys),
+	); err != nil {
+		return err
+	}
+	if err := c.requireTenantWrite(operatorKey, jobFunction.TenantKey); err != nil {
+		return err
+	}
+
+	for _, roleKey := range jobFunction.RoleKeys {
+		if err := c.ensureRoleTenantMatch(roleKey, jobFunction.TenantKey); err != nil {
+			return err
+		}
+	}
+
+	return c.jobFunctions.SetJobFunction(c.ctx, operatorKey, jobFunction)
+}
+
+// RemoveJobFunction 删除租户的岗位，仍有用户同步在该岗位时返回 ErrJobFunctionInUse
+func (c *casbinxClient) RemoveJobFunction(operatorKey, tenantKey, jobCode string) (err error) {
+	defer c.guard.recover("RemoveJobFunction", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireArg("jobCode", jobCode),
+	); err != nil {
+		return err
+	}
+	if err := c.requireTenantWrite(operatorKey, tenantKey); err != nil {
+		return err
+	}
+
+	return c.jobFunctions.DeleteJobFunction(c.ctx, tenantKey, jobCode)
+}
+
+// GetJobFunction 获取租户的岗位，不存在时返回 ErrJobFunctionNotFound
+func (c *casbinxClient) GetJobFunction(tenantKey, jobCode string) (_ *core.JobFunction, err error) {
+	defer c.guard.recover("GetJobFunction", &err)
+	return c.jobFunctions.GetJobFunction(c.ctx, tenantKey, jobCode)
+}
+
+// ListJobFunctions 获取租户的所有岗位（按岗位编码排序）
+func (c *casbinxClient) ListJobFunctions(tenantKey string) (_ []*core.JobFunction, err error) {
+	defer c.guard.recover("ListJobFunctions", &err)
+	return c.jobFunctions.ListJobFunctions(c.ctx, tenantKey)
+}
+
+// SyncUserJobFunction 按岗位调整用户在租户内的角色分配（入职、调岗、离职）
+// 分配岗位要求而用户未持有的角色，移除此前由岗位同步分配、新岗位不再要求的角色；手动分配的角色不受影响。
+// jobCode 为空表示离职，移除所有由岗位同步分配的角色。每个分配和移除按 AssignRole、RemoveRole 的规则校验操作者，
+// 任一项未通过校验时不做任何改动；所有变更只发布一次同步通知。
+func (c *casbinxClient) SyncUserJobFunction(operatorKey, userKey, tenantKey, jobCode string, opts ...core.MutationOption) (_ *core.JobFunctionSyncResult, err error) {
+	defer c.guard.recover("SyncUserJobFunction", &err)
+	var result *core.JobFunctionSyncResult
+	err = c.runQueued(c.enforcer.UserSubject(userKey), opts, "SyncUserJobFunction", []string{operatorKey, userKey, tenantKey, jobCode}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("userKey", userKey),
+			core.RequireArg("tenantKey", tenantKey),
+		); err != nil {
+			return err
+		}
+
+		// 没有角色变更时同样要求操作者拥有租户的用户管理权限
+		allowed, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite})
+		if err != nil {
+			return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+		}
+		if !allowed {
+			return core.ErrPermissionDenied
+		}
+
+		current, err := c.jobFunctions.GetUserJobFunction(c.ctx, userKey, tenantKey)
+		if err != nil {
+			return err
+		}
+		var desired []string
+		if jobCode != "" {
+			jobFunction, err := c.jobFunctions.GetJobFunction(c.ctx, tenantKey, jobCode)
+			if err != nil {
+				return err
+			}
+			desired = jobFunction.RoleKeys
+		}
+		held, err := c.enforcer.GetRolesForUser(userKey, tenantKey)
+		if err != nil {
+			return err
+		}
+
+		syncResult := &core.JobFunctionSyncResult{UserKey: userKey, TenantKey: tenantKey, JobCode: jobCode}
+		var managed []string
+		if current != nil {
+			syncResult.PreviousJobCode = current.JobCode
+			managed = current.ManagedRoles
+		}
+
+		// 计算变更：只移除由岗位管理的角色，已手动持有的角色不纳入岗位管理
+		var nextManaged []string
+		for _, roleKey := range desired {
+			switch {
+			case !containsString(held, roleKey):
+				syncResult.AssignedRoles = append(syncResult.AssignedRoles, roleKey)
+				nextManaged = append(nextManaged, roleKey)
+			case containsString(managed, roleKey):
+				nextManaged = append(nextManaged, roleKey)
+			default:
+				syncResult.KeptRoles = append(syncResult.KeptRoles, roleKey)
+			}
+		}
+		for _, roleKey := range managed {
+			if !containsString(desired, roleKey) && containsString(held, roleKey) {
+				syncResult.RemovedRoles = append(syncResult.RemovedRoles, roleKey)
+			}
+		}
+
+		// 先完成全部校验，任一项失败时不做改动
+		for _, roleKey := range syncResult.AssignedRoles {
+			if err := c.validateRoleAssignment(operatorKey, roleKey, tenantKey); err != nil {
+				return fmt.Errorf("分配角色 %s 失败: %w", roleKey, err)
+			}
+		}
+		for _, roleKey := range syncResult.RemovedRoles {
+			if err := c.validateRoleRemoval(operatorKey, userKey, roleKey, tenantKey); err != nil {
+				return fmt.Errorf("移除角色 %s 失败: %w", roleKey, err)
+			}
+		}
+
+		err = c.enforcer.BatchNotifications(func() error {
+			if len(syncResult.AssignedRoles) > 0 {
+				assignments := make([]core.GroupingPolicy, 0, len(syncResult.AssignedRoles))
+				for _, roleKey := range syncResult.AssignedRoles {
+					assignments = append(assignments, core.GroupingPolicy{UserKey: userKey, RoleKey: roleKey, TenantKey: tenantKey})
+				}
+				failures, err := c.userManager.AssignRoles(c.ctx, operatorKey, assignments)
+				if err != nil {
+					return err
+				}
+				if err := core.FirstError(failures...); err != nil {
+					return err
+				}
+			}
+			for _, roleKey := range syncResult.RemovedRoles {
+				if err := c.userManager.RemoveRole(c.ctx, operatorKey, userKey, roleKey, tenantKey); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("同步岗位角色失败: %w", err)
+		}
+
+		for _, roleKey := range syncResult.AssignedRoles {
+			c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionAssign, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		}
+		for _, roleKey := range syncResult.RemovedRoles {
+			c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionRemove, Target: roleKey, TenantKey: tenantKey, OperatorKey: operatorKey})
+		}
+
+		if err := c.jobFunctions.SaveUserJobFunction(c.ctx, core.UserJobFunction{
+			UserKey:      userKey,
+			TenantKey:    tenantKey,
+			JobCode:      jobCode,
+			ManagedRoles: nextManaged,
+			SyncedBy:     operatorKey,
+		}); err != nil {
+			return err
+		}
+
+		result = syncResult
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetUserJobFunction 获取用户在租户内当前同步的岗位，未同步时返回 nil
+func (c *casbinxClient) GetUserJobFunction(userKey, tenantKey string) (_ *core.UserJobFunction, err error) {
+	defer c.guard.recover("GetUserJobFunction", &err)
+	return c.jobFunctions.GetUserJobFunction(c.ctx, userKey, tenantKey)
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// GetUserPermissionManifest 安全地获取用户在租户内的权限清单（角色和有效权限）
+func (c *casbinxClient) GetUserPermissionManifest(operatorKey, userKey, tenantKey string) (_ *core.PermissionManifest, err error) {
+	defer c.guard.recover("GetUserPermissionManifest", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return nil, err
+	}
+
+	revision := c.enforcer.Revision()
+	roles, err := c.GetUserRolesSecure(operatorKey, userKey, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := c.GetEffectivePermissionsSecure(operatorKey, userKey, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if roles == nil {
+		roles = []string{}
+	}
+	if permissions == nil {
+		permissions = []core.Permission{}
+	}
+	return &core.PermissionManifest{
+		UserKey:     userKey,
+		TenantKey:   tenantKey,
+		Roles:       roles,
+		Permissions: permissions,
+		Revision:    revision,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// GetUserPermissionManifestCached 获取权限清单，实时查询因存储不可用等原因失败时返回最近一次成功查询的清单并标记为过期
+// 缓存按操作者、用户和租户区分，只返回该操作者此前通过校验获得的清单；参数错误和权限拒绝不降级，没有缓存时返回原错误
+func (c *casbinxClient) GetUserPermissionManifestCached(operatorKey, userKey, tenantKey string) (_ *core.PermissionManifest, err error) {
+	defer c.guard.recover("GetUserPermissionManifestCached", &err)
+	key := strings.Join([]string{operatorKey, userKey, tenantKey}, "\x00")
+	manifest, err := c.GetUserPermissionManifest(operatorKey, userKey, tenantKey)
+	if err == nil {
+		c.manifests.Set(key, manifest)
+		return manifest, nil
+	}
+	if !core.IsDegradable(err) {
+		return nil, err
+	}
+
+	cached, found := c.manifests.Get(key)
+	if !found {
+		return nil, err
+	}
+	c.guard.logger.Warn("实时查询权限清单失败，返回缓存的清单", "operator", operatorKey, "user", userKey, "tenant", tenantKey, "generatedAt", cached.GeneratedAt, "error", err)
+
+	stale := *cached
+	stale.Stale = true
+	stale.StaleReason = err.Error()
+	return &stale, nil
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/casbin/casbin/v2/model"
+)
+
+// defaultModelPaths 未配置 PossiblePaths 时尝试的模型文件路径
+var defaultModelPaths = []string{
+	"etc/casbin_model.conf",
+	"./casbin_model.conf",
+	"../etc/casbin_model.conf",
+}
+
+// loadModel 加载 Casbin 模型
+// 优先使用 Config.ModelText，其次是 PossiblePaths 中第一个存在的文件；
+// 显式配置的 PossiblePaths 都不存在时报错，默认路径都不存在时使用内置模型
+func loadModel(c core.Config) (model.Model, error) {
+	if c.ModelText != "" {
+		m, err := model.NewModelFromString(c.ModelText)
+		if err != nil {
+			return nil, fmt.Errorf("解析 Config.ModelText 失败: %v", err)
+		}
+		return m, nil
+	}
+
+	modelPaths := c.PossiblePaths
+	if len(modelPaths) == 0 {
+		modelPaths = defaultModelPaths
+	}
+
+	for _, path := range modelPaths {
+		if _, err := os.Stat(path); err == nil {
+			m, err := model.NewModelFromFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("加载Casbin模型文件 '%s' 失败: %v", path, err)
+			}
+			return m, nil
+		}
+	}
+
+	if len(c.PossiblePaths) > 0 {
+		return nil, fmt.Errorf("Casbin模型文件不存在，已尝试路径: %v", modelPaths)
+	}
+
+	m, err := model.NewModelFromString(core.DefaultModelText)
+	if err != nil {
+		return nil, fmt.Errorf("加载内置Casbin模型失败: %v", err)
+	}
+	return m, nil
+}
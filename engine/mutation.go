@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// runQueued 经变更串行化队列执行幂等变更，同一主体的变更按提交顺序执行
+// 未启用 Config.MutationQueue 时直接在调用方协程中执行；跨主体的操作（合并、重命名用户）不经过队列。
+// 耗时（含排队时间）和结果记录到变更指标
+func (c *casbinxClient) runQueued(subject string, opts []core.MutationOption, operation string, args []string, fn func() error) (err error) {
+	start := time.Now()
+	defer func() {
+		metrics := c.enforcer.Metrics()
+		metrics.ObserveMutation(operation, err, time.Since(start))
+		if core.ErrorClass(err) == "error" {
+			metrics.ObserveDBError(operation)
+		}
+	}()
+
+	run := func() error {
+		return c.runIdempotent(opts, operation, args, fn)
+	}
+	// 事务内的变更不经过队列：队列中等待事务结束的变更会阻塞同一主体后续的事务内变更
+	if c.mutations == nil || c.tx != nil {
+		return run()
+	}
+	return c.mutations.Submit(c.ctx, subject, run)
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// GrantObjectPermissions 为用户授予对象的整组权限（对象创建时调用）
+// 任一权限授予失败时撤销本次新授予的权限，不会留下只授予了一部分的对象权限
+func (c *casbinxClient) GrantObjectPermissions(operatorKey, userKey, tenantKey string, template core.ObjectPermissionTemplate, objectID string) (err error) {
+	defer c.guard.recover("GrantObjectPermissions", &err)
+	permissions, err := template.Instantiate(objectID)
+	if err != nil {
+		return err
+	}
+
+	var granted []core.Permission
+	for _, permission := range permissions {
+		exists, err := c.checkManager.HasDirectPermission(c.ctx, userKey, tenantKey, permission)
+		if err != nil {
+			return err
+		}
+
+		if err := c.GrantPermission(operatorKey, userKey, tenantKey, permission); err != nil {
+			return c.rollbackObjectGrants(operatorKey, userKey, tenantKey, granted, err)
+		}
+		if !exists {
+			granted = append(granted, permission)
+		}
+	}
+
+	return nil
+}
+
+// RevokeObjectPermissions 撤销用户对对象的整组权限
+func (c *casbinxClient) RevokeObjectPermissions(operatorKey, userKey, tenantKey string, template core.ObjectPermissionTemplate, objectID string) (err error) {
+	defer c.guard.recover("RevokeObjectPermissions", &err)
+	permissions, err := template.Instantiate(objectID)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if err := c.RevokePermission(operatorKey, userKey, tenantKey, permission); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PurgeObjectPermissions 撤销租户内所有用户和角色对对象资源的授权（对象删除时调用，防止遗留孤儿权限）
+// 按模板实例化出的每个对象资源批量撤销，不限于模板中的操作
+func (c *casbinxClient) PurgeObjectPermissions(operatorKey, tenantKey string, template core.ObjectPermissionTemplate, objectID string) (_ []*core.ResourceRevocation, err error) {
+	defer c.guard.recover("PurgeObjectPermissions", &err)
+	resources, err := template.Resources(objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	revocations := make([]*core.ResourceRevocation, 0, len(resources))
+	for _, resource := range resources {
+		revocation, err := c.RevokeResourceFromTenant(operatorKey, tenantKey, resource)
+		if err != nil {
+			return revocations, err
+		}
+		revocations = append(revocations, revocation)
+	}
+
+	return revocations, nil
+}
+
+// rollbackObjectGrants 撤销本次已授予的对象权限
+func (c *casbinxClient) rollbackObjectGrants(operatorKey, userKey, tenantKey string, granted []core.Permission, cause error) error {
+	for _, permission := range granted {
+		if err := c.RevokePermission(operatorKey, userKey, tenantKey, permission); err != nil {
+			return fmt.Errorf("%w（回滚已授予的对象权限 %s 失败: %v）", cause, permission.String(), err)
+		}
+	}
+	return cause
+}
+
+// CleanupObjectPermissions 对象删除时清理其全部权限（应用删除实体后调用）
+// 删除所有租户中所有主体对 类型:ID 及其子资源（类型:ID:*）的授权，随后执行对象清理回调，
+// 由回调清理环境标记、优先级、分享和归属等附属记录；失去全部权限的角色会补充占位权限。
+// 该调用由应用的实体生命周期触发而非用户操作，因此不校验操作者权限；清理回调的写入无法撤销，不能在 WithCompensation 内调用
+func (c *casbinxClient) CleanupObjectPermissions(resourceType, objectID string) (_ *core.ObjectCleanupResult, err error) {
+	defer c.guard.recover("CleanupObjectPermissions", &err)
+	if c.tx != nil {
+		return nil, fmt.Errorf("%w: CleanupObjectPermissions", core.ErrTxUnsupported)
+	}
+	return c.policyManager.CleanupObject(c.ctx, resourceType, objectID)
+}
+
+// RegisterObjectCleanupHook 注册对象清理回调，用于在对象权限清理后同步删除应用侧的分享、归属等记录
+func (c *casbinxClient) RegisterObjectCleanupHook(hook core.ObjectCleanupHook) {
+	if hook == nil {
+		return
+	}
+	c.policyManager.AddObjectCleanupHook(c.ctx, hook)
+}
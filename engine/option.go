@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/casbin/casbin/v2/persist"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"gorm.io/gorm"
+)
+
+// Option NewCasbinx 的可选参数
+type Option func(*options)
+
+// options 引擎构造选项集合
+type options struct {
+	logger  core.Logger                // 日志输出（为空时使用 Config.Logger）
+	cache   core.DecisionCache         // 权限检查结果缓存（为空时不缓存）
+	rules   core.PermissionCache       // 权限集合缓存（为空时不缓存）
+	hooks   []func() error             // 策略重新加载后执行的回调
+	adapter persist.Adapter            // 策略存储适配器（为空时使用 Config.Dsn 创建 GORM 适配器）
+	gormDB  *gorm.DB                   // GORM 适配器使用的数据库连接（为空时按 Config.Dsn 连接 PostgreSQL）
+	sqlConn sqlx.SqlConn               // 元数据表使用的数据库连接（为空时按 Config.Dsn 创建）
+	watcher core.Watcher               // 策略同步 Watcher（为空时按 Config.Watcher.Type 创建内置 Watcher）
+	digests []core.DigestHook          // 权限变更摘要投递回调
+	events  []core.SecurityEventHook   // 敏感事件回调
+	loads   []core.PolicyLoadHook      // 容错加载发现无效策略行时的回调
+	metrics core.MetricsRecorder       // 指标采集（为空时按 Config.Metrics 创建）
+	diverge []core.CheckDivergenceHook // 影子比较结果不一致时的回调
+
+	credentials core.DBCredentialsProvider // 数据库凭据回调（为空时使用 Config.Dsn 中的密码）
+}
+
+// WithLogger 指定日志输出（如策略重载、panic 恢复、安全拒绝的日志），优先于 Config.Logger
+// 标准库 *log.Logger 等 Printf 风格的输出可通过 core.NewPrintfLogger 适配
+func WithLogger(logger core.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithCache 启用权限检查结果缓存
+// 本实例的策略写入和收到的同步通知都会清空缓存；可使用 core.NewMemoryDecisionCache 或自定义实现
+func WithCache(cache core.DecisionCache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithPermissionCache 启用权限集合缓存，缓存用户在租户内生效的规则集合
+// 同一用户在同一租户内检查不同权限时无需重复枚举策略；失效时机与 WithCache 相同，可同时启用。
+// 可使用 core.NewLRUPermissionCache 或自定义实现
+func WithPermissionCache(cache core.PermissionCache) Option {
+	return func(o *options) {
+		o.rules = cache
+	}
+}
+
+// WithHook 注册策略重新加载后执行的回调（用于刷新应用侧依赖权限数据的缓存）
+func WithHook(hook func() error) Option {
+	return func(o *options) {
+		if hook != nil {
+			o.hooks = append(o.hooks, hook)
+		}
+	}
+}
+
+// WithAdapter 指定策略存储适配器，替代默认的 GORM 适配器
+// 元数据表（角色、租户等）仍使用 Config.Dsn
+func WithAdapter(adapter persist.Adapter) Option {
+	return func(o *options) {
+		o.adapter = adapter
+	}
+}
+
+// WithGormDB 复用已有的 GORM 连接存储策略，可以是 GORM 支持的任意数据库（如 MySQL、SQLite）
+// 只有策略表 casbin_rules 在该连接中创建；角色、租户等元数据表只支持 PostgreSQL，仍需配置 Config.Dsn 或 WithSqlConn。
+// 同时指定 WithAdapter 时以 WithAdapter 为准
+func WithGormDB(db *gorm.DB) Option {
+	return func(o *options) {
+		o.gormDB = db
+	}
+}
+
+// WithSqlConn 复用已有的 sqlx 连接存储角色、租户等元数据，连接需指向 PostgreSQL
+func WithSqlConn(conn sqlx.SqlConn) Option {
+	return func(o *options) {
+		o.sqlConn = conn
+	}
+}
+
+// WithDBCredentials 使用凭据回调获取数据库密码，替代 Config.Dsn 中的静态密码（如 RDS IAM、CloudSQL IAM 认证）
+// 按 Config.Dsn 创建的 GORM 连接、sqlx 连接和 Postgres Watcher 连接在建立每个新连接前调用回调；
+// 通过 WithGormDB、WithSqlConn 注入的连接不受影响。可配合 core.CachedDBCredentials 复用未过期的令牌
+func WithDBCredentials(provider core.DBCredentialsProvider) Option {
+	return func(o *options) {
+		o.credentials = provider
+	}
+}
+
+// WithWatcher 指定策略同步 Watcher，替代按 Config.Watcher.Type 创建的内置 Watcher
+// Casbin 生态中的 persist.Watcher 实现可以直接传入；Config.Watcher 中的重载合并参数仍然生效
+func WithWatcher(watcher core.Watcher) Option {
+	return func(o *options) {
+		o.watcher = watcher
+	}
+}
+
+// WithDigestHook 注册权限变更摘要的投递回调（如 core.NewWebhookDigestHook），配合 Config.Digest 使用
+func WithDigestHook(hook core.DigestHook) Option {
+	return func(o *options) {
+		if hook != nil {
+			o.digests = append(o.digests, hook)
+		}
+	}
+}
+
+// WithSecurityEventHook 注册敏感事件回调（系统角色变更、租户封锁等），如 notifier 包提供的 Slack/Teams 通知
+func WithSecurityEventHook(hook core.SecurityEventHook) Option {
+	return func(o *options) {
+		if hook != nil {
+			o.events = append(o.events, hook)
+		}
+	}
+}
+
+// applyOptions 合并构造选项
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithPolicyLoadHook 注册容错加载发现无效策略行时的回调（配合 Config.PolicyLoad.Tolerant 使用）
+// 回调在加载完成后同步执行，应尽快返回（如转发到告警系统）
+func WithPolicyLoadHook(hook core.PolicyLoadHook) Option {
+	return func(o *options) {
+		if hook != nil {
+			o.loads = append(o.loads, hook)
+		}
+	}
+}
+
+// WithMetrics 指定指标采集回调（如对接 Prometheus 以外的监控系统），优先于 Config.Metrics
+func WithMetrics(metrics core.MetricsRecorder) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithCheckDivergenceHook 注册影子比较结果不一致时的回调（配合 Config.CheckComparison.Shadow 使用），如写入分析表
+func WithCheckDivergenceHook(hook core.CheckDivergenceHook) Option {
+	return func(o *options) {
+		if hook != nil {
+			o.diverge = append(o.diverge, hook)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// ListRolesPage 分页获取角色列表（默认按角色键排序，filter.OrderBy 指定其他排序字段）
+// 分页在数据库中完成，只加载当前页角色的权限
+func (c *casbinxClient) ListRolesPage(tenantKey string, filter *core.RoleFilter, page core.PageRequest) (_ *core.PageResponse[*core.Role], err error) {
+	defer c.guard.recover("ListRolesPage", &err)
+	meta, err := c.enforcer.ResultMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.roleManager.ListRolesPage(c.ctx, tenantKey, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.Stamp(meta), nil
+}
+
+// ListTenantMembers 分页获取租户成员（按用户键排序，包含拥有全局角色的用户）
+func (c *casbinxClient) ListTenantMembers(tenantKey string, page core.PageRequest) (_ *core.PageResponse[core.TenantMember], err error) {
+	defer c.guard.recover("ListTenantMembers", &err)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	meta, err := c.enforcer.ResultMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	groupings, err := c.roleManager.GetAllGroupingPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rolesByUser := make(map[string][]string)
+	for _, grouping := range groupings {
+		rolesByUser[grouping.UserKey] = append(rolesByUser[grouping.UserKey], grouping.RoleKey)
+	}
+
+	members := make([]core.TenantMember, 0, len(rolesByUser))
+	for userKey, roles := range rolesByUser {
+		sort.Strings(roles)
+		members = append(members, core.TenantMember{UserKey: userKey, Roles: roles})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserKey < members[j].UserKey
+	})
+
+	response, err := core.Paginate(members, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.Stamp(meta), nil
+}
+
+// ListPolicies 分页浏览租户内的权限策略（tenantKey 为空表示所有租户，不含角色占位策略）
+func (c *casbinxClient) ListPolicies(tenantKey string, page core.PageRequest) (_ *core.PageResponse[core.Policy], err error) {
+	defer c.guard.recover("ListPolicies", &err)
+	meta, err := c.enforcer.ResultMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := c.policyManager.ListPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := core.Paginate(policies, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.Stamp(meta), nil
+}
+
+// ListGroupingPolicies 获取指定租户的所有角色分配，结果携带生成时间、策略版本和修订号
+func (c *casbinxClient) ListGroupingPolicies(tenantKey string) (_ *core.ListResult[core.GroupingPolicy], err error) {
+	defer c.guard.recover("ListGroupingPolicies", &err)
+	meta, err := c.enforcer.ResultMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	groupings, err := c.roleManager.GetAllGroupingPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+	return core.NewListResult(groupings, meta), nil
+}
+
+// ListGroupingPoliciesPage 分页获取指定租户的角色分配（按用户键、角色键排序）
+func (c *casbinxClient) ListGroupingPoliciesPage(tenantKey string, page core.PageRequest) (_ *core.PageResponse[core.GroupingPolicy], err error) {
+	defer c.guard.recover("ListGroupingPoliciesPage", &err)
+	meta, err := c.enforcer.ResultMeta()
+	if err != nil {
+		return nil, err
+	}
+
+	groupings, err := c.roleManager.GetAllGroupingPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(groupings, func(i, j int) bool {
+		if groupings[i].UserKey != groupings[j].UserKey {
+			return groupings[i].UserKey < groupings[j].UserKey
+		}
+		if groupings[i].RoleKey != groupings[j].RoleKey {
+			return groupings[i].RoleKey < groupings[j].RoleKey
+		}
+		return groupings[i].TenantKey < groupings[j].TenantKey
+	})
+
+	response, err := core.Paginate(groupings, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.Stamp(meta), nil
+}
+
+// GetPolicyVersion 获取当前策略版本
+func (c *casbinxClient) GetPolicyVersion() (_ string, err error) {
+	defer c.guard.recover("GetPolicyVersion", &err)
+	return c.enforcer.PolicyVersion()
+}
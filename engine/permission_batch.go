@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// GrantPermissions 批量授予用户权限
+// 所有权限先逐个通过提权校验，任一不通过则整体拒绝；策略在单个事务中写入，批次结束后只发布一次同步通知。
+// 用户已拥有的权限会被跳过，环境限制（WithEnvironments）对批次中的每个权限生效
+func (c *casbinxClient) GrantPermissions(operatorKey, userKey, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("GrantPermissions", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "GrantPermissions", permissionBatchArgs(operatorKey, userKey, tenantKey, permissions), func() error {
+		if err := core.RequireItems("permissions", permissions); err != nil {
+			return err
+		}
+
+		// 安全检查：逐个进行提权验证
+		for _, permission := range permissions {
+			if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
+				return fmt.Errorf("权限 '%s': %w", permission, err)
+			}
+		}
+
+		environments := core.ApplyMutationOptions(opts).Environments
+		err := c.enforcer.BatchNotifications(func() error {
+			// 先写入环境标记再授权，保证授权生效时环境限制已经存在
+			for _, permission := range permissions {
+				if err := c.environments.SetPolicyEnvironments(c.ctx, userKey, tenantKey, permission, environments); err != nil {
+					return err
+				}
+			}
+			if err := c.userManager.GrantPermissions(c.ctx, operatorKey, userKey, tenantKey, permissions); err != nil {
+				return err
+			}
+
+			// 已有的限时授权变为永久授权
+			for _, permission := range permissions {
+				if err := c.expirations.ClearPermissionExpiry(c.ctx, userKey, tenantKey, permission); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, permission := range permissions {
+			c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionGrant, Target: permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+		}
+		return nil
+	})
+}
+
+// RevokePermissions 批量撤销用户权限
+// 所有权限先逐个通过撤销校验，任一不通过则整体拒绝；策略在单个事务中删除，批次结束后只发布一次同步通知。
+// 用户未拥有的权限会被跳过
+func (c *casbinxClient) RevokePermissions(operatorKey, userKey, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("RevokePermissions", &err)
+	return c.runQueued(c.enforcer.UserSubject(userKey), opts, "RevokePermissions", permissionBatchArgs(operatorKey, userKey, tenantKey, permissions), func() error {
+		if err := core.RequireItems("permissions", permissions); err != nil {
+			return err
+		}
+
+		// 安全检查：逐个进行权限撤销验证
+		for _, permission := range permissions {
+			if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, userKey, tenantKey, permission); err != nil {
+				return fmt.Errorf("权限 '%s': %w", permission, err)
+			}
+		}
+
+		err := c.enforcer.BatchNotifications(func() error {
+			if err := c.userManager.RevokePermissions(c.ctx, operatorKey, userKey, tenantKey, permissions); err != nil {
+				return err
+			}
+
+			// 策略已删除，清理附属的优先级、条件、环境标记和过期时间
+			for _, permission := range permissions {
+				if err := c.priorities.ClearPolicyPriority(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission); err != nil {
+					return err
+				}
+				if err := c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission); err != nil {
+					return err
+				}
+				if err := c.environments.ClearPolicyEnvironments(c.ctx, userKey, tenantKey, permission); err != nil {
+					return err
+				}
+				if err := c.expirations.ClearPermissionExpiry(c.ctx, userKey, tenantKey, permission); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, permission := range permissions {
+			c.recordAudit(opts, core.PermissionChange{UserKey: userKey, Action: core.AuditActionRevoke, Target: permission.String(), TenantKey: tenantKey, OperatorKey: operatorKey})
+		}
+		return nil
+	})
+}
+
+// permissionBatchArgs 生成批量授权的幂等参数
+func permissionBatchArgs(operatorKey, userKey, tenantKey string, permissions []core.Permission) []string {
+	args := make([]string, 0, len(permissions)+3)
+	args = append(args, operatorKey, userKey, tenantKey)
+	for _, permission := range permissions {
+		args = append(args, permission.String())
+	}
+	return args
+}
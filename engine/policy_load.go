@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/quarantine"
+)
+
+// policyLoadMonitor 记录容错加载的结果：保存最近一次报告、写入隔离表并调用回调
+// 首次加载发生在创建 Casbin 执行器时，此时隔离表管理器尚未创建，报告在 attach 时补写
+type policyLoadMonitor struct {
+	logger core.Logger
+	hooks  []core.PolicyLoadHook
+
+	mu         sync.Mutex
+	last       *core.PolicyLoadReport
+	quarantine quarantine.Manager
+}
+
+// newPolicyLoadMonitor 创建容错加载结果记录器
+func newPolicyLoadMonitor(logger core.Logger, hooks []core.PolicyLoadHook) *policyLoadMonitor {
+	return &policyLoadMonitor{logger: logger, hooks: hooks}
+}
+
+// record 容错适配器的加载回调
+func (m *policyLoadMonitor) record(report core.PolicyLoadReport) {
+	m.mu.Lock()
+	m.last = &report
+	manager := m.quarantine
+	m.mu.Unlock()
+
+	if manager != nil {
+		m.persist(manager, report)
+	}
+	if len(report.Quarantined) == 0 {
+		return
+	}
+
+	for _, rule := range report.Quarantined {
+		m.logger.Warn("策略行无效，已隔离", "rule", rule.String(), "reason", rule.Reason)
+	}
+	for _, hook := range m.hooks {
+		hook(report)
+	}
+}
+
+// attach 设置隔离表管理器，并补写创建执行器时的加载结果
+func (m *policyLoadMonitor) attach(manager quarantine.Manager) {
+	m.mu.Lock()
+	m.quarantine = manager
+	last := m.last
+	m.mu.Unlock()
+
+	if last != nil {
+		m.persist(manager, *last)
+	}
+}
+
+// persist 写入隔离表，失败只记录日志，不影响已经完成的加载
+func (m *policyLoadMonitor) persist(manager quarantine.Manager, report core.PolicyLoadReport) {
+	if err := manager.Record(context.Background(), report); err != nil {
+		m.logger.Error("写入隔离策略行失败", "quarantined", len(report.Quarantined), "error", err)
+	}
+}
+
+// lastReport 获取最近一次加载结果
+func (m *policyLoadMonitor) lastReport() *core.PolicyLoadReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.last == nil {
+		return nil
+	}
+	report := *m.last
+	return &report
+}
+
+// Health 获取引擎健康状态：最近一次重载是否成功，容错加载时是否有被隔离的策略行，Watcher 是否处于连接状态
+func (c *casbinxClient) Health() core.HealthStatus {
+	status := core.HealthStatus{Reload: c.reloadCoalescer.Stats()}
+	if c.policyLoads != nil {
+		status.PolicyLoad = c.policyLoads.lastReport()
+	}
+	if reporter, ok := c.watcher.(core.WatcherStatsReporter); ok {
+		stats := reporter.Stats()
+		status.Watcher = &stats
+	}
+	status.Healthy = status.Reload.LastError == "" &&
+		(status.PolicyLoad == nil || len(status.PolicyLoad.Quarantined) == 0) &&
+		(status.Watcher == nil || status.Watcher.Connected)
+	return status
+}
+
+// ListQuarantinedRules 获取容错加载隔离的策略行（未启用容错加载时为空）
+// 修复或删除策略表中的无效行后，下一次加载会将其移出隔离表
+func (c *casbinxClient) ListQuarantinedRules() (_ []*core.QuarantinedRule, err error) {
+	defer c.guard.recover("ListQuarantinedRules", &err)
+	if c.quarantine == nil {
+		return []*core.QuarantinedRule{}, nil
+	}
+	return c.quarantine.List(c.ctx)
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// SetGrantPriority 设置用户直接授权的优先级
+// 调整优先级与授予该权限需要相同的操作权限
+func (c *casbinxClient) SetGrantPriority(operatorKey, userKey, tenantKey string, permission core.Permission, priority int) (err error) {
+	defer c.guard.recover("SetGrantPriority", &err)
+	if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
+		return err
+	}
+
+	return c.priorities.SetPolicyPriority(c.ctx, core.SubjectKindUser, userKey, tenantKey, permission, priority)
+}
+
+// SetRolePermissionPriority 设置角色授权的优先级
+func (c *casbinxClient) SetRolePermissionPriority(operatorKey, roleKey string, permission core.Permission, priority int) (err error) {
+	defer c.guard.recover("SetRolePermissionPriority", &err)
+	roleTenantKey, err := c.validateRoleRuleChange(operatorKey, roleKey, []core.Permission{permission})
+	if err != nil {
+		return err
+	}
+
+	return c.priorities.SetPolicyPriority(c.ctx, core.SubjectKindRole, roleKey, roleTenantKey, permission, priority)
+}
+
+// ReorderRolePermissions 按给定顺序重新设置角色授权的优先级，排在前面的授权优先生效
+func (c *casbinxClient) ReorderRolePermissions(operatorKey, roleKey string, permissions []core.Permission) (err error) {
+	defer c.guard.recover("ReorderRolePermissions", &err)
+	roleTenantKey, err := c.validateRoleRuleChange(operatorKey, roleKey, permissions)
+	if err != nil {
+		return err
+	}
+
+	return c.priorities.ReorderPolicies(c.ctx, core.SubjectKindRole, roleKey, roleTenantKey, permissions)
+}
+
+// validateRoleRuleChange 验证操作者可以调整角色授权的优先级或条件，返回角色所属租户域
+func (c *casbinxClient) validateRoleRuleChange(operatorKey, roleKey string, permissions []core.Permission) (string, error) {
+	// 冻结检查：冻结的角色不允许变更
+	if err := c.ensureRoleUnlocked(roleKey); err != nil {
+		return "", err
+	}
+
+	// 检查全局角色操作权限
+	if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
+		return "", err
+	}
+
+	role, err := c.roleManager.GetRole(c.ctx, roleKey)
+	if err != nil {
+		return "", fmt.Errorf("获取角色信息失败: %w", err)
+	}
+
+	for _, permission := range permissions {
+		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, role.TenantKey, permission); err != nil {
+			return "", err
+		}
+	}
+
+	return role.TenantKey, nil
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// securityDenials 记录为安全拒绝日志的错误
+var securityDenials = []error{
+	core.ErrPermissionDenied,
+	core.ErrSelfElevationPrevented,
+	core.ErrSystemPermissionImmutable,
+	core.ErrRoleExceedsOperatorPermissions,
+}
+
+// panicGuard 公开方法边界的 panic 恢复守卫
+type panicGuard struct {
+	config core.RecoveryConfig
+	logger core.Logger
+}
+
+// newPanicGuard 创建 panic 恢复守卫，logger 为空时使用 slog 默认 Logger
+func newPanicGuard(config core.RecoveryConfig, logger core.Logger) panicGuard {
+	if logger == nil {
+		logger = core.DefaultLogger()
+	}
+	return panicGuard{config: config, logger: logger}
+}
+
+// recover 将 panic 转换为 core.PanicError 写入 err，并将堆栈写入日志；方法因安全校验被拒绝时记录 Warn 日志
+// 必须在方法开头直接 defer 调用；关闭恢复时不调用 recover，panic 原样向上抛出
+func (g panicGuard) recover(operation string, err *error) {
+	if *err != nil {
+		for _, denial := range securityDenials {
+			if errors.Is(*err, denial) {
+				g.logger.Warn("操作被安全校验拒绝", "operation", operation, "error", *err)
+				break
+			}
+		}
+	}
+	if g.config.Disabled {
+		return
+	}
+	if value := recover(); value != nil {
+		panicErr := core.NewPanicError(operation, value, !g.config.DisableStack)
+		g.logger.Error("公开方法发生 panic", "operation", operation, "panic", value, "stack", panicErr.Stack)
+		*err = panicErr
+	}
+}
+
+// wrap 为后台执行的函数加上 panic 恢复（如同步通知触发的策略重载，panic 会导致宿主进程退出）
+func (g panicGuard) wrap(operation string, fn func() error) func() error {
+	return func() (err error) {
+		defer g.recover(operation, &err)
+		return fn()
+	}
+}
@@ -0,0 +1,253 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// regoModule 导出的 Rego 模块，与引擎的检查语义对应：
+// 用户在租户内的直接权限，或用户在租户/全局域分配的角色所拥有的权限
+const regoModule = `package casbinx.authz
+
+import rego.v1
+
+default allow := false
+
+tenant := data.casbinx.tenants[input.tenant]
+
+# 授权覆盖请求：资源和操作一致，或授权的资源、操作为通配符 *
+covers(permission) if {
+	not permission.objectId
+	permission.resource in {input.resource, "*"}
+	permission.action in {input.action, "*"}
+}
+
+# 用户在租户内的直接权限
+allow if {
+	some permission in tenant.users[input.user].permissions
+	covers(permission)
+}
+
+# 用户分配的角色（租户内角色、全局角色、链接到租户的共享角色）拥有的权限
+allow if {
+	some role in tenant.users[input.user].roles
+	some permission in tenant.roles[role].permissions
+	covers(permission)
+}
+`
+
+// ExportToRego 将租户的角色、角色分配和权限策略导出为 OPA/Rego 模块和数据文档
+// 引擎侧的数据库状态（租户封锁、灰度变更等）无法完整翻译，列在 Issues 中
+func (c *casbinxClient) ExportToRego(tenantKey string) (_ *core.RegoExport, err error) {
+	defer c.guard.recover("ExportToRego", &err)
+	if err := core.RequireTenantArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	var issues []core.RegoFidelityIssue
+
+	// 别名下的授权在检查时按规范租户生效，一并导出
+	domains := map[string]bool{tenantKey: true}
+	for _, alias := range c.tenantManager.ListTenantAliases(c.ctx, tenantKey) {
+		domains[alias.AliasKey] = true
+		issues = append(issues, core.RegoFidelityIssue{
+			Construct: core.RegoConstructTenantAlias,
+			Subject:   alias.AliasKey,
+			Detail:    fmt.Sprintf("别名 %s 下的授权已并入 %s，查询时 input.tenant 需使用规范租户键", alias.AliasKey, tenantKey),
+		})
+	}
+
+	if lockdown := c.tenantManager.GetTenantLockdown(c.ctx, tenantKey); lockdown != nil {
+		issues = append(issues, core.RegoFidelityIssue{
+			Construct: core.RegoConstructTenantLockdown,
+			Subject:   tenantKey,
+			Detail:    fmt.Sprintf("租户处于封锁状态（仅放行角色 %v），导出的策略未包含封锁限制", lockdown.AllowedRoles),
+		})
+	}
+
+	// 角色权限：租户角色、全局角色和链接到租户的共享角色
+	roles, err := c.roleManager.ListRoles(c.ctx, tenantKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	data := core.RegoTenantData{
+		Users: make(map[string]core.RegoSubjectData),
+		Roles: make(map[string]core.RegoSubjectData, len(roles)),
+	}
+	for _, role := range roles {
+		var permissions []core.Permission
+		for _, permission := range role.Permissions {
+			if permission.Resource != core.ResourcePlaceholder {
+				permissions = append(permissions, permission)
+			}
+		}
+		data.Roles[role.Key] = core.RegoSubjectData{Permissions: sortedPermissions(permissions)}
+	}
+
+	// 角色分配：租户（含别名）和全局域
+	groupings, err := c.roleManager.GetAllGroupingPolicies(c.ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	userRoles := make(map[string][]string)
+	roleParents := make(map[string][]string)
+	for _, grouping := range groupings {
+		if grouping.TenantKey != "*" && !domains[grouping.TenantKey] {
+			continue
+		}
+		// 主体为角色的分组策略是角色继承
+		if _, isRole := data.Roles[grouping.UserKey]; isRole {
+			roleParents[grouping.UserKey] = append(roleParents[grouping.UserKey], grouping.RoleKey)
+			continue
+		}
+		if !slices.Contains(userRoles[grouping.UserKey], grouping.RoleKey) {
+			userRoles[grouping.UserKey] = append(userRoles[grouping.UserKey], grouping.RoleKey)
+		}
+	}
+	flattenRoleInheritance(data.Roles, roleParents)
+
+	// 用户直接权限：只有租户（含别名）域内的授权生效
+	policies, err := c.policyManager.ListPolicies(c.ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	currentEnvironment := c.environments.CurrentEnvironment(c.ctx)
+	userPermissions := make(map[string][]core.Permission)
+	for _, policy := range policies {
+		if !domains[policy.Domain] || (policy.SubjectKind != "" && policy.SubjectKind != core.SubjectKindUser) {
+			continue
+		}
+		// 未启用主体命名空间时，以角色列表区分用户和角色
+		if _, isRole := data.Roles[policy.Subject]; isRole && policy.SubjectKind == "" {
+			continue
+		}
+
+		permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+		if environments := c.environments.GetPolicyEnvironments(c.ctx, policy.Subject, policy.Domain, permission); len(environments) > 0 {
+			applies := currentEnvironment == "" || slices.Contains(environments, currentEnvironment)
+			outcome := "已包含"
+			if !applies {
+				outcome = "未包含"
+			}
+			issues = append(issues, core.RegoFidelityIssue{
+				Construct: core.RegoConstructEnvironment,
+				Subject:   policy.Subject,
+				Detail:    fmt.Sprintf("授权 %s 仅在环境 %v 中生效，已按当前环境 %q 展开（导出结果%s该授权）", permission, environments, currentEnvironment, outcome),
+			})
+			if !applies {
+				continue
+			}
+		}
+		userPermissions[policy.Subject] = append(userPermissions[policy.Subject], permission)
+	}
+
+	for userKey, roleKeys := range userRoles {
+		sort.Strings(roleKeys)
+		data.Users[userKey] = core.RegoSubjectData{Roles: roleKeys, Permissions: sortedPermissions(userPermissions[userKey])}
+	}
+	for userKey, permissions := range userPermissions {
+		if _, exists := data.Users[userKey]; !exists {
+			data.Users[userKey] = core.RegoSubjectData{Permissions: sortedPermissions(permissions)}
+		}
+	}
+
+	// 未严格区分写操作时 write 授权覆盖 create/update，Rego 中权限按字面比较，导出时展开
+	if !c.enforcer.StrictWriteActionsEnabled() {
+		for roleKey, role := range data.Roles {
+			role.Permissions = sortedPermissions(expandWriteActions(role.Permissions))
+			data.Roles[roleKey] = role
+		}
+		for userKey, user := range data.Users {
+			user.Permissions = sortedPermissions(expandWriteActions(user.Permissions))
+			data.Users[userKey] = user
+		}
+	}
+
+	// 灰度变更在检查时按用户或租户生效，导出结果只包含已发布的角色权限
+	rollouts, err := c.rollouts.ListStagedRollouts(c.ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, rollout := range rollouts {
+		if _, exists := data.Roles[rollout.RoleKey]; !exists {
+			continue
+		}
+		issues = append(issues, core.RegoFidelityIssue{
+			Construct: core.RegoConstructRollout,
+			Subject:   rollout.RoleKey,
+			Detail:    fmt.Sprintf("角色 %s 的权限 %s 有进行中的灰度变更（%s），导出结果按未变更处理", rollout.RoleKey, rollout.Permission, rollout.Change),
+		})
+	}
+
+	return &core.RegoExport{
+		TenantKey: tenantKey,
+		Module:    regoModule,
+		Data: map[string]any{
+			"casbinx": map[string]any{
+				"tenants": map[string]core.RegoTenantData{tenantKey: data},
+			},
+		},
+		Issues: issues,
+	}, nil
+}
+
+// sortedPermissions 去重并按资源、操作排序，保证导出结果稳定
+func sortedPermissions(permissions []core.Permission) []core.Permission {
+	result := core.NormalizePermissions(permissions)
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Resource != result[j].Resource {
+			return result[i].Resource < result[j].Resource
+		}
+		return result[i].Action < result[j].Action
+	})
+	return result
+}
+
+// expandWriteActions 为每个 write 授权补充对应的 create、update 授权
+func expandWriteActions(permissions []core.Permission) []core.Permission {
+	result := append([]core.Permission{}, permissions...)
+	for _, permission := range permissions {
+		if permission.Action != core.ActionWrite {
+			continue
+		}
+		for _, action := range []core.Action{core.ActionCreate, core.ActionUpdate} {
+			expanded := permission
+			expanded.Action = action
+			result = append(result, expanded)
+		}
+	}
+	return result
+}
+
+// flattenRoleInheritance 将父角色的权限（传递地）合并到子角色，导出的策略无需再展开继承
+func flattenRoleInheritance(roles map[string]core.RegoSubjectData, parents map[string][]string) {
+	flattened := make(map[string][]core.Permission, len(roles))
+	for roleKey := range parents {
+		seen := map[string]bool{roleKey: true}
+		queue := []string{roleKey}
+		var permissions []core.Permission
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, permission := range roles[current].Permissions {
+				if !slices.Contains(permissions, permission) {
+					permissions = append(permissions, permission)
+				}
+			}
+			for _, parent := range parents[current] {
+				if !seen[parent] {
+					seen[parent] = true
+					queue = append(queue, parent)
+				}
+			}
+		}
+		flattened[roleKey] = permissions
+	}
+
+	for roleKey, permissions := range flattened {
+		roles[roleKey] = core.RegoSubjectData{Permissions: sortedPermissions(permissions)}
+	}
+}
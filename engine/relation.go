@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// ExportRelationTuples 将角色分配、用户权限和角色权限（含对象级权限）导出为 Zanzibar 风格的关系元组
+// tenantKey 为空时导出所有租户；指定租户时同时导出全局域的角色分配和权限
+func (c *casbinxClient) ExportRelationTuples(tenantKey string) (_ []core.RelationTuple, err error) {
+	defer c.guard.recover("ExportRelationTuples", &err)
+
+	roles, err := c.roleManager.ListRoles(c.ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	roleKeys := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		roleKeys[role.Key] = true
+	}
+
+	inScope := func(domain string) bool {
+		return tenantKey == "" || domain == tenantKey || domain == "*"
+	}
+
+	var tuples []core.RelationTuple
+
+	policies, err := c.policyManager.ListPolicies(c.ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if !inScope(policy.Domain) {
+			continue
+		}
+		tuple := core.RelationTuple{
+			ObjectType: core.RelationTypeResource,
+			ObjectID:   policy.Domain + "/" + string(policy.Resource),
+			Relation:   string(policy.Action),
+		}
+		switch {
+		case policy.SubjectKind == core.SubjectKindRole || (policy.SubjectKind == "" && roleKeys[policy.Subject]):
+			tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation = core.RelationTypeTenant, policy.Domain, policy.Subject
+		case policy.SubjectKind == "" || policy.SubjectKind == core.SubjectKindUser:
+			tuple.SubjectType, tuple.SubjectID = core.RelationTypeUser, policy.Subject
+		default:
+			tuple.SubjectType, tuple.SubjectID = string(policy.SubjectKind), policy.Subject
+		}
+		tuples = append(tuples, tuple)
+	}
+
+	groupings, err := c.roleManager.GetAllGroupingPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, grouping := range groupings {
+		tuple := core.RelationTuple{
+			ObjectType:  core.RelationTypeTenant,
+			ObjectID:    grouping.TenantKey,
+			Relation:    grouping.RoleKey,
+			SubjectType: core.RelationTypeUser,
+			SubjectID:   grouping.UserKey,
+		}
+		// 角色继承：子角色的成员同时是父角色的成员
+		if roleKeys[grouping.UserKey] {
+			tuple.SubjectType, tuple.SubjectID, tuple.SubjectRelation = core.RelationTypeTenant, grouping.TenantKey, grouping.UserKey
+		}
+		tuples = append(tuples, tuple)
+	}
+
+	sort.Slice(tuples, func(i, j int) bool {
+		return tuples[i].String() < tuples[j].String()
+	})
+	return tuples, nil
+}
+
+// ImportRelationTuples 将关系元组导入为用户授权、角色授权、角色分配和角色继承
+// 每个元组按对应的公开方法（GrantPermission、GrantRolePermission、AssignRole、AddRoleInheritance）校验操作者权限，
+// 单个元组失败不会中断批次；批次期间暂停同步通知，结束后只发布一次
+func (c *casbinxClient) ImportRelationTuples(operatorKey string, tuples []core.RelationTuple) (_ *core.RelationImportResult, err error) {
+	defer c.guard.recover("ImportRelationTuples", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return nil, err
+	}
+	return c.importRelationTuples(operatorKey, tuples, nil)
+}
+
+// importRelationTuples 逐条导入关系元组，每处理一条报告一次进度，任务取消时停止并返回已导入的结果
+func (c *casbinxClient) importRelationTuples(operatorKey string, tuples []core.RelationTuple, progress jobProgress) (*core.RelationImportResult, error) {
+	result := &core.RelationImportResult{Failures: []core.RelationImportFailure{}}
+	err := c.enforcer.BatchNotifications(func() error {
+		for i, tuple := range tuples {
+			if err := c.importRelationTuple(operatorKey, tuple); err != nil {
+				result.Failures = append(result.Failures, core.RelationImportFailure{Tuple: tuple.String(), Error: err.Error()})
+			} else {
+				result.Imported++
+			}
+			if err := progress.report(i+1, len(tuples)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, core.ErrJobCanceled) {
+		return result, err
+	}
+	if err != nil {
+		return result, fmt.Errorf("发布同步通知失败: %w", err)
+	}
+
+	return result, nil
+}
+
+// importRelationTuple 导入单个关系元组
+func (c *casbinxClient) importRelationTuple(operatorKey string, tuple core.RelationTuple) error {
+	switch tuple.ObjectType {
+	case core.RelationTypeResource:
+		tenantKey, resource, ok := strings.Cut(tuple.ObjectID, "/")
+		if !ok || tenantKey == "" || resource == "" {
+			return fmt.Errorf("%w: 资源对象标识应为 <租户>/<资源>", core.ErrInvalidRelationTuple)
+		}
+		permission := core.Permission{Resource: core.Resource(resource), Action: core.Action(tuple.Relation)}
+
+		switch {
+		case tuple.SubjectType == core.RelationTypeUser && tuple.SubjectRelation == "":
+			return c.GrantPermission(operatorKey, tuple.SubjectID, tenantKey, permission)
+		case tuple.SubjectType == core.RelationTypeTenant && tuple.SubjectRelation != "":
+			// 角色权限定义在角色归属的域中，元组中的租户必须与角色归属一致
+			if tuple.SubjectID != tenantKey {
+				return fmt.Errorf("%w: 角色权限的主体租户与资源租户不一致", core.ErrUnsupportedRelationTuple)
+			}
+			role, err := c.roleManager.GetRole(c.ctx, tuple.SubjectRelation)
+			if err != nil {
+				return err
+			}
+			roleTenant := role.TenantKey
+			if roleTenant == "" {
+				roleTenant = "*"
+			}
+			if roleTenant != tenantKey {
+				return fmt.Errorf("%w: 角色 '%s' 属于租户 '%s'", core.ErrUnsupportedRelationTuple, role.Key, roleTenant)
+			}
+			return c.GrantRolePermission(operatorKey, role.Key, permission)
+		}
+
+	case core.RelationTypeTenant:
+		if tuple.SubjectType == core.RelationTypeUser && tuple.SubjectRelation == "" {
+			// 全局域分配会使角色在所有租户生效，只能通过跨租户分配进行
+			if tuple.ObjectID == "*" {
+				return c.AssignRoleCrossTenant(operatorKey, tuple.SubjectID, tuple.Relation, tuple.ObjectID)
+			}
+			return c.AssignRole(operatorKey, tuple.SubjectID, tuple.Relation, tuple.ObjectID)
+		}
+		if tuple.SubjectType == core.RelationTypeTenant && tuple.SubjectRelation != "" {
+			// 角色继承：子角色的成员同时是父角色的成员
+			if tuple.SubjectID != tuple.ObjectID {
+				return fmt.Errorf("%w: 角色继承的主体租户与对象租户不一致", core.ErrUnsupportedRelationTuple)
+			}
+			return c.AddRoleInheritance(operatorKey, tuple.Relation, tuple.SubjectRelation, tuple.ObjectID)
+		}
+	}
+
+	return core.ErrUnsupportedRelationTuple
+}
@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/residency"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// ResidencyRouter 数据驻留路由器
+// 为每个区域创建独立的 CasbinX 实例（独立数据库、独立同步频道），按租户所属区域路由读写。
+// 每个区域实例都拒绝写入不属于本区域的租户，即使调用方取错了实例，策略也不会离开租户所属区域
+type ResidencyRouter interface {
+	ForTenant(tenantKey string) (CasbinX, error)                    // 获取租户所属区域的实例
+	ForTenants(tenantKeys ...string) (CasbinX, error)               // 获取多个租户共同所属区域的实例，租户分属不同区域时拒绝
+	ForRegion(region string) (CasbinX, error)                       // 获取指定区域的实例
+	AssignTenantRegion(operatorKey, tenantKey, region string) error // 登记租户所属区域（登记后不可变更）
+	GetTenantRegion(tenantKey string) (string, error)               // 获取租户所属区域
+	ListTenantRegions() ([]*core.TenantRegion, error)               // 获取所有已登记的租户区域
+	Regions() []string                                              // 获取已配置的区域
+}
+
+// residencyRouter 数据驻留路由器实现
+type residencyRouter struct {
+	config  core.ResidencyConfig
+	regions residency.Manager
+	clients map[string]*casbinxClient // 区域 -> 实例
+}
+
+// NewResidencyRouter 按 Config.Residency 创建数据驻留路由器
+// Config.Dsn 被忽略，每个区域使用 Regions 中配置的数据库
+func NewResidencyRouter(c core.Config) (ResidencyRouter, error) {
+	if !c.Residency.Enabled() {
+		return nil, core.ErrRegionNotConfigured
+	}
+	if err := c.Residency.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 租户区域登记表保存在默认区域
+	regionManager, err := residency.NewManager(sqlx.NewSqlConn("postgres", c.Residency.Regions[c.Residency.DefaultRegion]), c.Residency.DefaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("创建租户区域管理器失败: %v", err)
+	}
+
+	router := &residencyRouter{
+		config:  c.Residency,
+		regions: regionManager,
+		clients: make(map[string]*casbinxClient, len(c.Residency.Regions)),
+	}
+
+	for region, dsn := range c.Residency.Regions {
+		regionConfig := c
+		regionConfig.Dsn = dsn
+		// 各区域使用独立的同步频道，避免一个区域的策略变更触发其他区域重新加载
+		if regionConfig.Watcher.Redis.Channel != "" {
+			regionConfig.Watcher.Redis.Channel = regionConfig.Watcher.Redis.Channel + ":" + region
+		}
+
+		client, err := newCasbinxClient(regionConfig)
+		if err != nil {
+			return nil, fmt.Errorf("创建区域 '%s' 的实例失败: %w", region, err)
+		}
+		client.enforcer.AddDomainWriteGuard(router.regionWriteGuard(region))
+		router.clients[region] = client
+	}
+
+	return router, nil
+}
+
+// ForTenant 获取租户所属区域的实例
+func (r *residencyRouter) ForTenant(tenantKey string) (CasbinX, error) {
+	return r.ForTenants(tenantKey)
+}
+
+// ForTenants 获取多个租户共同所属区域的实例
+// 用于跨租户操作（如跨租户分配角色、链接共享角色），租户分属不同区域时返回 ErrCrossRegionOperation
+func (r *residencyRouter) ForTenants(tenantKeys ...string) (CasbinX, error) {
+	region := ""
+	for _, tenantKey := range tenantKeys {
+		// 全局域在每个区域都存在，不参与路由
+		if tenantKey == "*" {
+			continue
+		}
+		tenantRegion, err := r.regions.GetRegion(context.Background(), tenantKey)
+		if err != nil {
+			return nil, err
+		}
+		if region != "" && region != tenantRegion {
+			return nil, core.ErrCrossRegionOperation
+		}
+		region = tenantRegion
+	}
+	if region == "" {
+		region = r.config.DefaultRegion
+	}
+
+	return r.ForRegion(region)
+}
+
+// ForRegion 获取指定区域的实例
+func (r *residencyRouter) ForRegion(region string) (CasbinX, error) {
+	client, exists := r.clients[region]
+	if !exists {
+		return nil, core.ErrRegionNotConfigured
+	}
+	return client, nil
+}
+
+// AssignTenantRegion 登记租户所属区域
+// 要求操作者在默认区域拥有全局租户管理权限；已在默认区域写入数据的租户不能再登记到其他区域，
+// 否则已有策略会滞留在默认区域
+func (r *residencyRouter) AssignTenantRegion(operatorKey, tenantKey, region string) error {
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireTenantArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+	if _, exists := r.clients[region]; !exists {
+		return core.ErrRegionNotConfigured
+	}
+
+	defaultClient := r.clients[r.config.DefaultRegion]
+	if err := defaultClient.requireGlobalTenantManagement(operatorKey); err != nil {
+		return err
+	}
+
+	if region != r.config.DefaultRegion {
+		hasData, err := defaultClient.tenantHasPolicies(tenantKey)
+		if err != nil {
+			return err
+		}
+		if hasData {
+			return core.ErrCrossRegionOperation
+		}
+	}
+
+	return r.regions.AssignRegion(context.Background(), operatorKey, tenantKey, region)
+}
+
+// GetTenantRegion 获取租户所属区域
+func (r *residencyRouter) GetTenantRegion(tenantKey string) (string, error) {
+	return r.regions.GetRegion(context.Background(), tenantKey)
+}
+
+// ListTenantRegions 获取所有已登记的租户区域
+func (r *residencyRouter) ListTenantRegions() ([]*core.TenantRegion, error) {
+	return r.regions.ListTenantRegions(context.Background())
+}
+
+// Regions 获取已配置的区域
+func (r *residencyRouter) Regions() []string {
+	regions := make([]string, 0, len(r.clients))
+	for region := range r.clients {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// regionWriteGuard 区域写入守卫：拒绝向不属于该区域的租户写入策略
+// 只守卫写入；读取不属于本区域的租户时本区域没有对应策略，检查结果自然为拒绝
+func (r *residencyRouter) regionWriteGuard(region string) core.DomainWriteGuard {
+	return func(domain string) error {
+		if domain == "*" {
+			return nil
+		}
+		tenantRegion, err := r.regions.GetRegion(context.Background(), domain)
+		if err != nil {
+			return err
+		}
+		if tenantRegion != region {
+			return core.ErrCrossRegionOperation
+		}
+		return nil
+	}
+}
+
+// tenantHasPolicies 检查租户在本实例中是否已有权限策略或角色分配
+func (c *casbinxClient) tenantHasPolicies(tenantKey string) (bool, error) {
+	policies, err := c.enforcer.GetPolicies("", tenantKey)
+	if err != nil {
+		return false, err
+	}
+	if len(policies) > 0 {
+		return true, nil
+	}
+
+	groupings, err := c.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return false, err
+	}
+	for _, grouping := range groupings {
+		if grouping.TenantKey == tenantKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// errStopRange 遍历策略时找到结果后提前停止
+var errStopRange = errors.New("stop range")
+
+// DefineResourceAction 为租户的资源定义自定义操作（如 invoice:approve），定义后即可在该租户内授予
+// tenantKey 为 * 时定义对所有租户生效的操作，要求全局租户管理权限；否则要求租户的 tenant:write 权限
+func (c *casbinxClient) DefineResourceAction(operatorKey string, definition core.ResourceAction) (err error) {
+	defer c.guard.recover("DefineResourceAction", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("definition.tenantKey", definition.TenantKey),
+	); err != nil {
+		return err
+	}
+	if err := c.requireTenantWrite(operatorKey, definition.TenantKey); err != nil {
+		return err
+	}
+
+	return c.actions.DefineAction(c.ctx, operatorKey, definition)
+}
+
+// RemoveResourceAction 删除租户资源上的自定义操作
+// 仍有授权（用户或角色，含对象级授权）引用该操作时返回 ErrResourceActionInUse，需先撤销这些授权
+func (c *casbinxClient) RemoveResourceAction(operatorKey, tenantKey string, resource core.Resource, action core.Action) (err error) {
+	defer c.guard.recover("RemoveResourceAction", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireArg("resource", resource),
+		core.RequireArg("action", action),
+	); err != nil {
+		return err
+	}
+	if err := c.requireTenantWrite(operatorKey, tenantKey); err != nil {
+		return err
+	}
+
+	// 全局定义被删除后，只有自己也定义了该操作的租户中的授权仍然有效
+	domain := tenantKey
+	if tenantKey == "*" {
+		domain = ""
+	}
+	err = c.enforcer.RangePolicies(domain, func(policy core.Policy) error {
+		if policy.Action != action || core.ResourceType(policy.Resource) != resource {
+			return nil
+		}
+		if tenantKey == "*" && policy.Domain != "*" && c.actions.IsDefined(c.ctx, policy.Domain, resource, action) {
+			return nil
+		}
+		return errStopRange
+	})
+	if errors.Is(err, errStopRange) {
+		return fmt.Errorf("%w: %s:%s", core.ErrResourceActionInUse, resource, action)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.actions.RemoveAction(c.ctx, tenantKey, resource, action)
+}
+
+// GetResourceActions 获取租户内资源的可用操作：资源的内置操作加上租户和全局定义的自定义操作
+func (c *casbinxClient) GetResourceActions(tenantKey string, resource core.Resource) (_ []core.Action, err error) {
+	defer c.guard.recover("GetResourceActions", &err)
+	if err := core.FirstError(
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireArg("resource", resource),
+	); err != nil {
+		return nil, err
+	}
+
+	builtin := core.GetResourceActions(resource)
+	actions := make([]core.Action, 0, len(builtin))
+	seen := make(map[core.Action]bool, len(builtin))
+	for _, action := range builtin {
+		seen[action] = true
+		actions = append(actions, action)
+	}
+	for _, definition := range c.actions.ListActions(c.ctx, tenantKey, resource) {
+		if !seen[definition.Action] {
+			seen[definition.Action] = true
+			actions = append(actions, definition.Action)
+		}
+	}
+	return actions, nil
+}
+
+// ListResourceActions 获取在租户内生效的所有自定义操作定义（含全局定义），按资源、操作排序
+func (c *casbinxClient) ListResourceActions(tenantKey string) (_ []*core.ResourceAction, err error) {
+	defer c.guard.recover("ListResourceActions", &err)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+	return c.actions.ListActions(c.ctx, tenantKey, ""), nil
+}
+
+// requireTenantWrite 要求操作者拥有租户的 tenant:write 权限（tenantKey 为 * 时要求全局权限）
+func (c *casbinxClient) requireTenantWrite(operatorKey, tenantKey string) error {
+	if tenantKey == "*" {
+		return c.requireGlobalTenantManagement(operatorKey)
+	}
+	allowed, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, core.Permission{Resource: core.ResourceTenant, Action: core.ActionWrite})
+	if err != nil {
+		return fmt.Errorf("检查租户管理权限失败: %w", err)
+	}
+	if !allowed {
+		return core.ErrPermissionDenied
+	}
+	return nil
+}
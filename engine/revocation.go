@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// PreviewResourceRevocation 预览按资源批量撤销会影响的策略、用户和角色
+// 预览同样校验操作者权限，确保预览通过时实际撤销不会因权限不足失败
+func (c *casbinxClient) PreviewResourceRevocation(operatorKey, tenantKey string, resource core.Resource) (_ *core.ResourceRevocation, err error) {
+	defer c.guard.recover("PreviewResourceRevocation", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return nil, err
+	}
+
+	preview, err := c.policyManager.PreviewResourceRevocation(c.ctx, tenantKey, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validateResourceRevocation(operatorKey, preview); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// RevokeResourceFromTenant 撤销租户内所有用户和角色对资源的授权（例如下线功能时）
+// 所有策略在一个批次中删除并记录撤销结果；失去全部权限的角色会补充占位权限，相关的环境标记和优先级一并清理
+func (c *casbinxClient) RevokeResourceFromTenant(operatorKey, tenantKey string, resource core.Resource) (_ *core.ResourceRevocation, err error) {
+	defer c.guard.recover("RevokeResourceFromTenant", &err)
+	if _, err := c.PreviewResourceRevocation(operatorKey, tenantKey, resource); err != nil {
+		return nil, err
+	}
+
+	revocation, err := c.policyManager.RevokeResource(c.ctx, operatorKey, tenantKey, resource)
+	if err != nil {
+		return revocation, err
+	}
+
+	for _, policy := range revocation.Policies {
+		permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+		kind := core.SubjectKindUser
+		if containsString(revocation.AffectedRoles, policy.Subject) {
+			kind = core.SubjectKindRole
+		}
+		if err := c.priorities.ClearPolicyPriority(c.ctx, kind, policy.Subject, policy.Domain, permission); err != nil {
+			return revocation, err
+		}
+		if err := c.conditions.ClearPolicyCondition(c.ctx, kind, policy.Subject, policy.Domain, permission); err != nil {
+			return revocation, err
+		}
+		if err := c.environments.ClearPolicyEnvironments(c.ctx, policy.Subject, policy.Domain, permission); err != nil {
+			return revocation, err
+		}
+	}
+
+	for _, roleKey := range revocation.AffectedRoles {
+		if err := c.roleManager.EnsurePlaceholder(c.ctx, roleKey); err != nil {
+			return revocation, err
+		}
+	}
+
+	return revocation, nil
+}
+
+// validateResourceRevocation 验证操作者可以撤销预览中的每一条授权
+func (c *casbinxClient) validateResourceRevocation(operatorKey string, preview *core.ResourceRevocation) error {
+	for _, roleKey := range preview.AffectedRoles {
+		// 冻结检查：冻结的角色不允许变更
+		if err := c.ensureRoleUnlocked(roleKey); err != nil {
+			return err
+		}
+	}
+
+	for _, policy := range preview.Policies {
+		permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+		if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, policy.Subject, policy.Domain, permission); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
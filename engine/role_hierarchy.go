@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// AddRoleInheritance 添加角色继承：子角色在租户内继承父角色的全部权限（如 editor 继承 viewer）
+// 子角色的成员由此获得父角色的权限，因此父角色按分配规则校验：不能是系统角色，也不能超出操作者自身的权限。
+// tenantKey 为 * 时继承在所有租户生效，需要全局角色管理权限；会形成继承环时返回 core.ErrRoleInheritanceCycle
+func (c *casbinxClient) AddRoleInheritance(operatorKey, parentRoleKey, childRoleKey, tenantKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("AddRoleInheritance", &err)
+	return c.runQueued(c.enforcer.RoleSubject(childRoleKey), opts, "AddRoleInheritance", []string{operatorKey, parentRoleKey, childRoleKey, tenantKey}, func() error {
+		if err := c.validateRoleInheritanceChange(operatorKey, parentRoleKey, childRoleKey, tenantKey); err != nil {
+			return err
+		}
+
+		// 继承等同于把父角色分配给子角色的所有成员
+		if err := c.validateAssignableRole(operatorKey, parentRoleKey, tenantKey); err != nil {
+			return err
+		}
+
+		if err := c.roleManager.AddRoleInheritance(c.ctx, parentRoleKey, childRoleKey, tenantKey); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionInherit, Target: roleInheritanceTarget(parentRoleKey, childRoleKey), TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
+}
+
+// RemoveRoleInheritance 移除角色继承
+func (c *casbinxClient) RemoveRoleInheritance(operatorKey, parentRoleKey, childRoleKey, tenantKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("RemoveRoleInheritance", &err)
+	return c.runQueued(c.enforcer.RoleSubject(childRoleKey), opts, "RemoveRoleInheritance", []string{operatorKey, parentRoleKey, childRoleKey, tenantKey}, func() error {
+		if err := c.validateRoleInheritanceChange(operatorKey, parentRoleKey, childRoleKey, tenantKey); err != nil {
+			return err
+		}
+
+		if err := c.roleManager.RemoveRoleInheritance(c.ctx, parentRoleKey, childRoleKey, tenantKey); err != nil {
+			return err
+		}
+
+		c.recordAudit(opts, core.PermissionChange{Action: core.AuditActionDisinherit, Target: roleInheritanceTarget(parentRoleKey, childRoleKey), TenantKey: tenantKey, OperatorKey: operatorKey})
+		return nil
+	})
+}
+
+// GetRoleHierarchy 获取租户内生效的角色继承关系（包括全局域中定义的继承），tenantKey 为空时返回所有租户
+func (c *casbinxClient) GetRoleHierarchy(tenantKey string) (_ []core.RoleInheritance, err error) {
+	defer c.guard.recover("GetRoleHierarchy", &err)
+	return c.roleManager.GetRoleHierarchy(c.ctx, tenantKey)
+}
+
+// validateRoleInheritanceChange 检查操作者能否变更子角色在租户内的继承关系
+func (c *casbinxClient) validateRoleInheritanceChange(operatorKey, parentRoleKey, childRoleKey, tenantKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("parentRoleKey", parentRoleKey),
+		core.RequireArg("childRoleKey", childRoleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	// 冻结检查：继承变更会改变子角色的有效权限
+	if err := c.ensureRoleUnlocked(childRoleKey); err != nil {
+		return err
+	}
+
+	// 子角色有全局域分配时要求全局权限
+	if err := c.validateGlobalRoleOperation(operatorKey, childRoleKey); err != nil {
+		return err
+	}
+
+	if tenantKey == "*" {
+		return c.requireGlobalRoleManagement(operatorKey)
+	}
+
+	hasRolePermission, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, core.Permission{Resource: core.ResourceRole, Action: core.ActionWrite})
+	if err != nil {
+		return fmt.Errorf("检查操作者角色管理权限时出错: %w", err)
+	}
+	if !hasRolePermission {
+		return fmt.Errorf("操作者 %s 没有角色管理权限，无法变更角色继承", operatorKey)
+	}
+
+	// 两个角色都必须在该租户可用
+	if err := c.ensureRoleTenantMatch(parentRoleKey, tenantKey); err != nil {
+		return err
+	}
+	return c.ensureRoleTenantMatch(childRoleKey, tenantKey)
+}
+
+// roleInheritanceTarget 生成审计记录中角色继承的目标（父角色>子角色）
+func roleInheritanceTarget(parentRoleKey, childRoleKey string) string {
+	return parentRoleKey + ">" + childRoleKey
+}
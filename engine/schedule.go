@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// scheduleBatchSize 每次认领的到期变更数量上限，到期变更更多时连续认领直到处理完
+const scheduleBatchSize = 100
+
+// SchedulePermissionChange 登记在 effectiveAt 生效的授权或撤销（计划中的组织调整）
+// 操作者权限在登记时按 GrantPermission、RevokePermission 的规则校验，到期后由调度器以操作者身份执行并再次校验；
+// opts 中的 core.WithReason、core.WithEnvironments 在执行时生效
+func (c *casbinxClient) SchedulePermissionChange(operatorKey string, change core.ScheduledChange, effectiveAt time.Time, opts ...core.MutationOption) (_ *core.ScheduledPermissionChange, err error) {
+	defer c.guard.recover("SchedulePermissionChange", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("change.userKey", change.UserKey),
+		core.RequireArg("change.tenantKey", change.TenantKey),
+		core.CheckArg(effectiveAt.After(time.Now()), "effectiveAt", core.ConstraintRange),
+	); err != nil {
+		return nil, err
+	}
+	if err := c.validateScheduledChange(operatorKey, change); err != nil {
+		return nil, err
+	}
+
+	options := core.ApplyMutationOptions(opts)
+	return c.schedules.CreateChange(c.ctx, core.ScheduledPermissionChange{
+		ScheduledChange: change,
+		OperatorKey:     operatorKey,
+		Reason:          options.Reason,
+		Environments:    options.Environments,
+		EffectiveAt:     effectiveAt,
+	})
+}
+
+// GetScheduledChange 获取定时变更，不存在时返回 ErrScheduledChangeNotFound
+func (c *casbinxClient) GetScheduledChange(changeID string) (_ *core.ScheduledPermissionChange, err error) {
+	defer c.guard.recover("GetScheduledChange", &err)
+	return c.schedules.GetChange(c.ctx, changeID)
+}
+
+// ListScheduledChanges 按条件查询定时变更（按生效时间排序）
+func (c *casbinxClient) ListScheduledChanges(filter core.ScheduledChangeFilter) (_ []*core.ScheduledPermissionChange, err error) {
+	defer c.guard.recover("ListScheduledChanges", &err)
+	return c.schedules.ListChanges(c.ctx, filter)
+}
+
+// CancelScheduledChange 取消等待生效的定时变更，已执行或已取消时返回 ErrScheduledChangeNotPending
+// 登记者可以取消；其他操作者需要能够直接执行该变更
+func (c *casbinxClient) CancelScheduledChange(operatorKey, changeID string) (_ *core.ScheduledPermissionChange, err error) {
+	defer c.guard.recover("CancelScheduledChange", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("changeID", changeID),
+	); err != nil {
+		return nil, err
+	}
+
+	change, err := c.schedules.GetChange(c.ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change.OperatorKey != operatorKey {
+		if err := c.validateScheduledChange(operatorKey, change.ScheduledChange); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.schedules.CancelChange(c.ctx, changeID, operatorKey)
+}
+
+// validateScheduledChange 按同步方法的规则校验操作者能否执行变更
+func (c *casbinxClient) validateScheduledChange(operatorKey string, change core.ScheduledChange) error {
+	switch change.Action {
+	case core.ScheduledGrant:
+		return c.securityValidator.ValidatePermissionGrant(operatorKey, change.UserKey, change.TenantKey, change.Permission)
+	case core.ScheduledRevoke:
+		return c.securityValidator.ValidatePermissionRevoke(operatorKey, change.UserKey, change.TenantKey, change.Permission)
+	default:
+		return &core.FieldError{Field: "change.action", Constraint: core.ConstraintOneOf, Detail: "不支持的定时变更操作 " + string(change.Action)}
+	}
+}
+
+// runScheduler 后台调度循环：按间隔认领到期的定时变更并执行
+// 多实例部署时每个变更只被一个实例认领；Config.Schedule.Disabled 的实例不启动该循环
+func (c *casbinxClient) runScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.applyDueChanges()
+	}
+}
+
+// applyDueChanges 认领并执行所有已到期的定时变更
+func (c *casbinxClient) applyDueChanges() {
+	for {
+		changes, err := c.schedules.ClaimDue(c.ctx, time.Now(), scheduleBatchSize)
+		if err != nil {
+			c.guard.logger.Error("认领到期定时变更失败", "error", err)
+			return
+		}
+
+		for _, change := range changes {
+			c.applyScheduledChange(change)
+		}
+		if len(changes) < scheduleBatchSize {
+			return
+		}
+	}
+}
+
+// applyScheduledChange 以登记时的操作者身份执行定时变更并记录结果
+// 执行失败（如操作者已失去授权能力）时标记为失败，不会重试
+func (c *casbinxClient) applyScheduledChange(change *core.ScheduledPermissionChange) {
+	opts := []core.MutationOption{core.WithReason(change.Reason), core.WithEnvironments(change.Environments...)}
+
+	var err error
+	switch change.Action {
+	case core.ScheduledGrant:
+		err = c.GrantPermission(change.OperatorKey, change.UserKey, change.TenantKey, change.Permission, opts...)
+	case core.ScheduledRevoke:
+		err = c.RevokePermission(change.OperatorKey, change.UserKey, change.TenantKey, change.Permission, opts...)
+	default:
+		err = core.NewFieldError("change.action", core.ConstraintOneOf)
+	}
+
+	status, message := core.ScheduledChangeApplied, ""
+	if err != nil {
+		status, message = core.ScheduledChangeFailed, err.Error()
+		c.guard.logger.Warn("执行定时权限变更失败", "changeId", change.ID, "action", change.Action, "user", change.UserKey, "tenant", change.TenantKey, "operator", change.OperatorKey, "error", err)
+	}
+
+	if err := c.schedules.FinishChange(c.ctx, change.ID, status, message); err != nil {
+		c.guard.logger.Error("保存定时变更结果失败", "changeId", change.ID, "status", status, "error", err)
+	}
+}
+
+// startScheduler 启动定时变更的后台调度（调度器不受调用上下文的取消和超时影响）
+func (c *casbinxClient) startScheduler(config core.ScheduleConfig) {
+	if config.Disabled {
+		return
+	}
+	interval := config.PollInterval
+	if interval <= 0 {
+		interval = core.DefaultSchedulePollInterval
+	}
+
+	runner := *c
+	runner.ctx = context.Background()
+	go runner.runScheduler(interval)
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// emitSecurityEvent 在后台依次调用敏感事件回调
+func (c *casbinxClient) emitSecurityEvent(event core.SecurityEvent) {
+	if len(c.securityHooks) == 0 {
+		return
+	}
+	event.Time = time.Now()
+
+	go func() {
+		for _, hook := range c.securityHooks {
+			if err := hook(context.Background(), event); err != nil {
+				c.guard.logger.Error("敏感事件回调失败", "class", event.Class, "action", event.Action, "target", event.Target, "error", err)
+			}
+		}
+	}()
+}
+
+// emitSystemRoleEvent 权限变更涉及系统权限时发出系统角色变更事件
+func (c *casbinxClient) emitSystemRoleEvent(action, operatorKey, roleKey, tenantKey string, permissions ...[]core.Permission) {
+	var system []string
+	for _, group := range permissions {
+		for _, permission := range group {
+			if c.securityValidator.GetPermissionType(permission) == core.PermissionTypeSystem {
+				system = append(system, permission.String())
+			}
+		}
+	}
+	if len(system) == 0 {
+		return
+	}
+
+	c.emitSecurityEvent(core.SecurityEvent{
+		Class:       core.SecurityEventSystemRole,
+		Action:      action,
+		OperatorKey: operatorKey,
+		TenantKey:   tenantKey,
+		Target:      roleKey,
+		Detail:      strings.Join(system, ", "),
+	})
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// PublishRole 将租户角色发布为共享角色（需要全局角色管理权限）
+func (c *casbinxClient) PublishRole(operatorKey, roleKey string) (err error) {
+	defer c.guard.recover("PublishRole", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("roleKey", roleKey),
+	); err != nil {
+		return err
+	}
+
+	if err := c.requireGlobalRoleManagement(operatorKey); err != nil {
+		return err
+	}
+
+	return c.roleManager.PublishRole(c.ctx, operatorKey, roleKey)
+}
+
+// UnpublishRole 取消发布共享角色（需要全局角色管理权限）
+// 角色仍链接到其他租户时返回 ErrSharedRoleLinked，force 为 true 时同时移除所有租户链接
+func (c *casbinxClient) UnpublishRole(operatorKey, roleKey string, force bool) (err error) {
+	defer c.guard.recover("UnpublishRole", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("roleKey", roleKey),
+	); err != nil {
+		return err
+	}
+
+	if err := c.requireGlobalRoleManagement(operatorKey); err != nil {
+		return err
+	}
+
+	return c.roleManager.UnpublishRole(c.ctx, roleKey, force)
+}
+
+// LinkSharedRole 将共享角色以只读方式链接到租户，来源角色的权限变更会自动生效
+func (c *casbinxClient) LinkSharedRole(operatorKey, roleKey, tenantKey string) (err error) {
+	defer c.guard.recover("LinkSharedRole", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	if err := c.requireTenantRoleManagement(operatorKey, tenantKey); err != nil {
+		return err
+	}
+
+	return c.roleManager.LinkSharedRole(c.ctx, operatorKey, roleKey, tenantKey)
+}
+
+// UnlinkSharedRole 取消共享角色与租户的链接
+func (c *casbinxClient) UnlinkSharedRole(operatorKey, roleKey, tenantKey string) (err error) {
+	defer c.guard.recover("UnlinkSharedRole", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	if err := c.requireTenantRoleManagement(operatorKey, tenantKey); err != nil {
+		return err
+	}
+
+	return c.roleManager.UnlinkSharedRole(c.ctx, roleKey, tenantKey)
+}
+
+// ListSharedRoles 获取所有共享角色
+func (c *casbinxClient) ListSharedRoles() (_ []*core.SharedRole, err error) {
+	defer c.guard.recover("ListSharedRoles", &err)
+	return c.roleManager.ListSharedRoles(c.ctx)
+}
+
+// requireGlobalRoleManagement 验证操作者拥有全局角色管理权限
+func (c *casbinxClient) requireGlobalRoleManagement(operatorKey string) error {
+	hasGlobalPerm, err := c.hasGlobalPermission(operatorKey, core.Permission{
+		Resource: core.ResourceRole,
+		Action:   core.ActionWrite,
+	})
+	if err != nil {
+		return fmt.Errorf("检查全局权限失败: %w", err)
+	}
+	if !hasGlobalPerm {
+		return core.ErrGlobalRoleAccessDenied
+	}
+	return nil
+}
+
+// requireTenantRoleManagement 验证操作者在租户内拥有角色管理权限
+func (c *casbinxClient) requireTenantRoleManagement(operatorKey, tenantKey string) error {
+	permission := core.Permission{Resource: core.ResourceRole, Action: core.ActionWrite}
+	hasPermission, err := c.checkManager.CheckPermission(c.ctx, operatorKey, tenantKey, permission)
+	if err != nil {
+		return fmt.Errorf("检查操作者角色管理权限时出错: %w", err)
+	}
+	if !hasPermission {
+		return fmt.Errorf("操作者 %s 在租户 %s 中没有角色管理权限", operatorKey, tenantKey)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// SimulateGrant 预览授予用户权限后用户在租户内有效授权的变化，不写入策略
+// 只对有权执行该授权的操作者开放（校验规则与 GrantPermission 相同）
+func (c *casbinxClient) SimulateGrant(operatorKey, userKey, tenantKey string, permission core.Permission) (_ *core.PermissionSimulation, err error) {
+	defer c.guard.recover("SimulateGrant", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequirePermissionArg("permission", permission),
+	); err != nil {
+		return nil, err
+	}
+	if err := c.securityValidator.ValidatePermissionGrant(operatorKey, userKey, tenantKey, permission); err != nil {
+		return nil, err
+	}
+
+	simulation := &core.PermissionSimulation{Added: []core.Permission{permission}, Removed: []core.Permission{}, Diffs: []core.PermissionDiff{}}
+	changes := []core.RuleChange{{Subject: userKey, Domain: tenantKey, Permission: permission}}
+	if err := c.simulateUser(simulation, userKey, tenantKey, changes); err != nil {
+		return nil, err
+	}
+	return simulation, nil
+}
+
+// SimulateRoleUpdate 预览将角色权限设置为 permissions 后所有受影响用户的有效授权变化，不写入策略
+// 受影响的用户包括直接分配了该角色的用户和通过角色继承获得该角色的用户；
+// 只对有权执行该更新的操作者开放（校验规则与 UpdateRole 相同）
+func (c *casbinxClient) SimulateRoleUpdate(operatorKey, roleKey string, permissions []core.Permission) (_ *core.PermissionSimulation, err error) {
+	defer c.guard.recover("SimulateRoleUpdate", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("roleKey", roleKey),
+	); err != nil {
+		return nil, err
+	}
+
+	role, err := c.roleManager.GetRole(c.ctx, roleKey)
+	if err != nil {
+		return nil, err
+	}
+	roleDomain := role.TenantKey
+	if roleDomain == "" {
+		roleDomain = "*"
+	}
+
+	if err := c.validateGlobalRoleOperation(operatorKey, roleKey); err != nil {
+		return nil, err
+	}
+	added := core.DifferencePermissions(permissions, role.Permissions)
+	removed := core.DifferencePermissions(role.Permissions, permissions)
+	for _, permission := range added {
+		if err := c.securityValidator.ValidatePermissionGrant(operatorKey, roleKey, roleDomain, permission); err != nil {
+			return nil, fmt.Errorf("新增角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+		}
+	}
+	for _, permission := range removed {
+		if err := c.securityValidator.ValidatePermissionRevoke(operatorKey, roleKey, roleDomain, permission); err != nil {
+			return nil, fmt.Errorf("删除角色权限验证失败 %s:%s - %w", permission.Resource, permission.Action, err)
+		}
+	}
+
+	simulation := &core.PermissionSimulation{Added: nonNilPermissions(added), Removed: nonNilPermissions(removed), Diffs: []core.PermissionDiff{}}
+	changes := make([]core.RuleChange, 0, len(added)+len(removed))
+	for _, permission := range added {
+		changes = append(changes, core.RuleChange{Subject: roleKey, Role: true, Domain: roleDomain, Permission: permission})
+	}
+	for _, permission := range removed {
+		changes = append(changes, core.RuleChange{Subject: roleKey, Role: true, Domain: roleDomain, Permission: permission, Remove: true})
+	}
+
+	// 拥有该角色的角色集合：角色本身及所有（间接）继承它的子角色
+	hierarchy, err := c.roleManager.GetRoleHierarchy(c.ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	holders := map[string]bool{roleKey: true}
+	for expanded := true; expanded; {
+		expanded = false
+		for _, inheritance := range hierarchy {
+			if holders[inheritance.ParentRoleKey] && !holders[inheritance.ChildRoleKey] {
+				holders[inheritance.ChildRoleKey] = true
+				expanded = true
+			}
+		}
+	}
+
+	groupings, err := c.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return nil, err
+	}
+	evaluated := make(map[[2]string]bool)
+	for _, grouping := range groupings {
+		// 主体为角色的记录是继承关系，其成员通过自己的分配记录评估
+		if !holders[grouping.RoleKey] || holders[grouping.UserKey] {
+			continue
+		}
+		pair := [2]string{grouping.UserKey, grouping.TenantKey}
+		if evaluated[pair] {
+			continue
+		}
+		evaluated[pair] = true
+		if err := c.simulateUser(simulation, grouping.UserKey, grouping.TenantKey, changes); err != nil {
+			return nil, err
+		}
+	}
+
+	simulation.SortDiffs()
+	return simulation, nil
+}
+
+// simulateUser 评估一个用户在租户内的授权变化，有变化时追加到模拟结果
+func (c *casbinxClient) simulateUser(simulation *core.PermissionSimulation, userKey, tenantKey string, changes []core.RuleChange) error {
+	gained, lost, err := c.enforcer.SimulateRuleChanges(userKey, tenantKey, changes)
+	if err != nil {
+		return err
+	}
+
+	simulation.Evaluated++
+	if len(gained) > 0 || len(lost) > 0 {
+		simulation.Diffs = append(simulation.Diffs, core.PermissionDiff{
+			UserKey:   userKey,
+			TenantKey: tenantKey,
+			Gained:    nonNilPermissions(gained),
+			Lost:      nonNilPermissions(lost),
+		})
+	}
+	return nil
+}
+
+// nonNilPermissions 空列表序列化为 [] 而不是 null
+func nonNilPermissions(permissions []core.Permission) []core.Permission {
+	if permissions == nil {
+		return []core.Permission{}
+	}
+	return permissions
+}
@@ -0,0 +1,408 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// ExportTenantPolicies 导出租户的策略快照：归属租户的角色（含权限）、租户内的直接权限、角色分配和角色继承
+// 规则优先级、条件和环境标记等元数据不包含在快照中
+func (c *casbinxClient) ExportTenantPolicies(tenantKey string) (_ *core.PolicySnapshot, err error) {
+	defer c.guard.recover("ExportTenantPolicies", &err)
+	if err := core.RequireTenantArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	roles, err := c.roleManager.ListRoles(c.ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := c.tenantSnapshot(tenantKey, rolesByKey(roles))
+	if err != nil {
+		return nil, err
+	}
+	snapshot.ExportedAt = time.Now()
+	return &snapshot.PolicySnapshot, nil
+}
+
+// ImportTenantPolicies 将策略快照导入租户
+// 导入前校验快照格式、权限和引用的角色，存在问题时不做任何修改；DryRun 只返回将要执行的变更。
+// 每项变更按对应的公开方法（CreateRole、GrantPermissions、AssignRole 等）校验操作者权限，
+// 批次期间暂停同步通知；某项失败时停止导入并返回已执行的部分，已执行的变更不会回滚
+func (c *casbinxClient) ImportTenantPolicies(operatorKey string, snapshot core.PolicySnapshot, opts core.PolicyImportOptions) (_ *core.PolicyImportResult, err error) {
+	defer c.guard.recover("ImportTenantPolicies", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return nil, err
+	}
+	if opts.Mode == "" {
+		opts.Mode = core.PolicyImportMerge
+	}
+	if opts.Mode != core.PolicyImportMerge && opts.Mode != core.PolicyImportReplace {
+		return nil, &core.FieldError{Field: "opts.mode", Constraint: core.ConstraintOneOf, Detail: "不支持的导入方式 " + string(opts.Mode)}
+	}
+	if opts.TenantKey == "" {
+		opts.TenantKey = snapshot.TenantKey
+	}
+	if err := core.RequireTenantArg("opts.tenantKey", opts.TenantKey); err != nil {
+		return nil, err
+	}
+
+	// 导入会整体改写租户的授权，要求操作者拥有全局租户管理权限
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return nil, err
+	}
+
+	snapshot = snapshot.RenameTenant(opts.TenantKey)
+	plan, err := c.planPolicyImport(snapshot, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := plan.result
+	if len(result.Issues) > 0 {
+		if opts.DryRun {
+			return &result, nil
+		}
+		return &result, fmt.Errorf("%w: %s", core.ErrInvalidPolicySnapshot, result.Issues[0])
+	}
+	if opts.DryRun {
+		return &result, nil
+	}
+
+	err = c.enforcer.BatchNotifications(func() error {
+		return c.applyPolicyImport(operatorKey, plan)
+	})
+	if err != nil {
+		return &result, err
+	}
+	return &result, nil
+}
+
+// policyImportPlan 快照导入计划，result 中记录变更列表，roles 为需要创建或更新的角色
+type policyImportPlan struct {
+	result core.PolicyImportResult
+	roles  map[string]core.SnapshotRole
+}
+
+// planPolicyImport 校验快照并对比租户当前状态，计算需要执行的变更
+func (c *casbinxClient) planPolicyImport(snapshot core.PolicySnapshot, opts core.PolicyImportOptions) (*policyImportPlan, error) {
+	tenantKey := opts.TenantKey
+	plan := &policyImportPlan{
+		result: core.PolicyImportResult{TenantKey: tenantKey, Mode: opts.Mode, DryRun: opts.DryRun, Issues: []string{}},
+		roles:  make(map[string]core.SnapshotRole, len(snapshot.Roles)),
+	}
+	result := &plan.result
+	issue := func(format string, args ...any) {
+		result.Issues = append(result.Issues, fmt.Sprintf(format, args...))
+	}
+
+	if snapshot.Version != core.PolicySnapshotVersion {
+		issue("不支持的快照版本 %d（当前版本 %d）", snapshot.Version, core.PolicySnapshotVersion)
+		return plan, nil
+	}
+
+	roles, err := c.roleManager.ListRoles(c.ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	existingRoles := rolesByKey(roles)
+	current, err := c.tenantSnapshot(tenantKey, existingRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	// 角色
+	snapshotRoles := make(map[string]bool, len(snapshot.Roles))
+	for _, role := range snapshot.Roles {
+		if role.Key == "" || role.Name == "" {
+			issue("角色 '%s' 缺少角色键或名称", role.Key)
+			continue
+		}
+		if snapshotRoles[role.Key] {
+			issue("角色 '%s' 在快照中重复出现", role.Key)
+			continue
+		}
+		snapshotRoles[role.Key] = true
+		for _, permission := range role.Permissions {
+			if !permission.IsValid() {
+				issue("角色 '%s' 的权限 '%s' 无效", role.Key, permission)
+			}
+		}
+
+		existing, ok := existingRoles[role.Key]
+		switch {
+		case !ok:
+			result.CreatedRoles = append(result.CreatedRoles, role.Key)
+			plan.roles[role.Key] = role
+		case existing.TenantKey != tenantKey:
+			issue("角色键 '%s' 已被租户 '%s' 使用", role.Key, existing.TenantKey)
+		case existing.Name != role.Name || existing.Description != role.Description ||
+			len(core.DifferencePermissions(role.Permissions, existing.Permissions)) > 0 ||
+			len(core.DifferencePermissions(existing.Permissions, role.Permissions)) > 0:
+			result.UpdatedRoles = append(result.UpdatedRoles, role.Key)
+			plan.roles[role.Key] = role
+		}
+	}
+	if opts.Mode == core.PolicyImportReplace {
+		for _, role := range current.Roles {
+			if !snapshotRoles[role.Key] {
+				result.DeletedRoles = append(result.DeletedRoles, role.Key)
+			}
+		}
+	}
+
+	// 导入后可用的角色：快照中的角色，以及未被删除的已有角色
+	deleted := make(map[string]bool, len(result.DeletedRoles))
+	for _, roleKey := range result.DeletedRoles {
+		deleted[roleKey] = true
+	}
+	roleAvailable := func(roleKey string) bool {
+		_, exists := existingRoles[roleKey]
+		return snapshotRoles[roleKey] || (exists && !deleted[roleKey])
+	}
+
+	// 直接权限
+	existingPolicies := make(map[core.SnapshotPolicy]bool, len(current.Policies))
+	for _, policy := range current.Policies {
+		existingPolicies[policy] = true
+	}
+	snapshotPolicies := make(map[core.SnapshotPolicy]bool, len(snapshot.Policies))
+	for _, policy := range snapshot.Policies {
+		if policy.Subject == "" || !policy.Permission().IsValid() {
+			issue("主体 '%s' 的直接权限 '%s' 无效", policy.Subject, policy.Permission())
+			continue
+		}
+		if policy.SubjectKind != "" && policy.SubjectKind != core.SubjectKindUser {
+			issue("不支持导入 %s 主体 '%s' 的直接权限", policy.SubjectKind, policy.Subject)
+			continue
+		}
+		policy.SubjectKind = current.policyKind()
+		if snapshotPolicies[policy] {
+			continue
+		}
+		snapshotPolicies[policy] = true
+		if !existingPolicies[policy] {
+			result.AddedPolicies = append(result.AddedPolicies, policy)
+		}
+	}
+	if opts.Mode == core.PolicyImportReplace {
+		for _, policy := range current.Policies {
+			if !snapshotPolicies[policy] {
+				result.RemovedPolicies = append(result.RemovedPolicies, policy)
+			}
+		}
+	}
+
+	// 角色分配和继承
+	existingGroupings := make(map[core.SnapshotGrouping]bool, len(current.Groupings))
+	for _, grouping := range current.Groupings {
+		existingGroupings[grouping] = true
+	}
+	snapshotGroupings := make(map[core.SnapshotGrouping]bool, len(snapshot.Groupings))
+	for _, grouping := range snapshot.Groupings {
+		if grouping.Subject == "" || grouping.RoleKey == "" {
+			issue("角色分配 '%s' -> '%s' 缺少主体或角色", grouping.Subject, grouping.RoleKey)
+			continue
+		}
+		if grouping.SubjectKind != core.SubjectKindUser && grouping.SubjectKind != core.SubjectKindRole {
+			issue("角色分配 '%s' -> '%s' 的主体类型 '%s' 无效", grouping.Subject, grouping.RoleKey, grouping.SubjectKind)
+			continue
+		}
+		if !roleAvailable(grouping.RoleKey) {
+			issue("角色分配引用的角色 '%s' 不存在", grouping.RoleKey)
+			continue
+		}
+		if grouping.SubjectKind == core.SubjectKindRole && !roleAvailable(grouping.Subject) {
+			issue("角色继承引用的子角色 '%s' 不存在", grouping.Subject)
+			continue
+		}
+		if snapshotGroupings[grouping] {
+			continue
+		}
+		snapshotGroupings[grouping] = true
+		if !existingGroupings[grouping] {
+			result.AddedGroupings = append(result.AddedGroupings, grouping)
+		}
+	}
+	if opts.Mode == core.PolicyImportReplace {
+		for _, grouping := range current.Groupings {
+			if !snapshotGroupings[grouping] {
+				result.RemovedGroupings = append(result.RemovedGroupings, grouping)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// applyPolicyImport 按计划执行导入：先创建和更新角色，再补充权限和分配，最后移除多余的分配、权限和角色
+func (c *casbinxClient) applyPolicyImport(operatorKey string, plan *policyImportPlan) error {
+	result := &plan.result
+	tenantKey := result.TenantKey
+
+	for _, roleKey := range result.CreatedRoles {
+		role := plan.roles[roleKey]
+		if err := c.CreateRole(operatorKey, role.Key, role.Name, role.Description, tenantKey, role.Permissions); err != nil {
+			return fmt.Errorf("创建角色 '%s' 失败: %w", role.Key, err)
+		}
+	}
+	for _, roleKey := range result.UpdatedRoles {
+		role := plan.roles[roleKey]
+		if err := c.UpdateRole(operatorKey, role.Key, role.Name, role.Description, tenantKey, role.Permissions); err != nil {
+			return fmt.Errorf("更新角色 '%s' 失败: %w", role.Key, err)
+		}
+	}
+
+	for userKey, permissions := range policiesBySubject(result.AddedPolicies) {
+		if err := c.GrantPermissions(operatorKey, userKey, tenantKey, permissions); err != nil {
+			return fmt.Errorf("授予用户 '%s' 权限失败: %w", userKey, err)
+		}
+	}
+	for _, grouping := range result.AddedGroupings {
+		if err := c.applySnapshotGrouping(operatorKey, tenantKey, grouping, true); err != nil {
+			return err
+		}
+	}
+
+	for _, grouping := range result.RemovedGroupings {
+		if err := c.applySnapshotGrouping(operatorKey, tenantKey, grouping, false); err != nil {
+			return err
+		}
+	}
+	for userKey, permissions := range policiesBySubject(result.RemovedPolicies) {
+		if err := c.RevokePermissions(operatorKey, userKey, tenantKey, permissions); err != nil {
+			return fmt.Errorf("撤销用户 '%s' 权限失败: %w", userKey, err)
+		}
+	}
+	for _, roleKey := range result.DeletedRoles {
+		if err := c.DeleteRole(roleKey, core.WithOperator(operatorKey)); err != nil {
+			return fmt.Errorf("删除角色 '%s' 失败: %w", roleKey, err)
+		}
+	}
+
+	return nil
+}
+
+// applySnapshotGrouping 添加或移除快照中的角色分配（主体为角色时为角色继承）
+func (c *casbinxClient) applySnapshotGrouping(operatorKey, tenantKey string, grouping core.SnapshotGrouping, add bool) error {
+	var err error
+	switch {
+	case grouping.SubjectKind == core.SubjectKindRole && add:
+		err = c.AddRoleInheritance(operatorKey, grouping.RoleKey, grouping.Subject, tenantKey)
+	case grouping.SubjectKind == core.SubjectKindRole:
+		err = c.RemoveRoleInheritance(operatorKey, grouping.RoleKey, grouping.Subject, tenantKey)
+	case add:
+		err = c.AssignRole(operatorKey, grouping.Subject, grouping.RoleKey, tenantKey)
+	default:
+		err = c.RemoveRole(operatorKey, grouping.Subject, grouping.RoleKey, tenantKey)
+	}
+	if err != nil {
+		return fmt.Errorf("角色分配 '%s' -> '%s': %w", grouping.Subject, grouping.RoleKey, err)
+	}
+	return nil
+}
+
+// tenantSnapshot 读取租户当前的角色、直接权限和角色分配，roles 为所有角色（用于区分主体是用户还是角色）
+func (c *casbinxClient) tenantSnapshot(tenantKey string, roles map[string]*core.Role) (*tenantPolicySnapshot, error) {
+	snapshot := &tenantPolicySnapshot{
+		PolicySnapshot: core.PolicySnapshot{
+			Version:   core.PolicySnapshotVersion,
+			TenantKey: tenantKey,
+			Roles:     []core.SnapshotRole{},
+			Policies:  []core.SnapshotPolicy{},
+			Groupings: []core.SnapshotGrouping{},
+		},
+		namespaced: c.enforcer.SubjectNamespacesEnabled(),
+	}
+
+	for _, role := range roles {
+		if role.TenantKey != tenantKey {
+			continue
+		}
+		snapshot.Roles = append(snapshot.Roles, core.SnapshotRole{
+			Key:         role.Key,
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+	sort.Slice(snapshot.Roles, func(i, j int) bool {
+		return snapshot.Roles[i].Key < snapshot.Roles[j].Key
+	})
+
+	// 角色的权限随角色导出，这里只保留非角色主体的直接权限（已按主体、资源、操作排序）
+	policies, err := c.policyManager.ListPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.Domain != tenantKey || policy.SubjectKind == core.SubjectKindRole || (policy.SubjectKind == "" && roles[policy.Subject] != nil) {
+			continue
+		}
+		snapshot.Policies = append(snapshot.Policies, core.SnapshotPolicy{
+			Subject:     policy.Subject,
+			SubjectKind: policy.SubjectKind,
+			Resource:    policy.Resource,
+			Action:      policy.Action,
+		})
+	}
+
+	groupings, err := c.roleManager.GetAllGroupingPolicies(c.ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, grouping := range groupings {
+		if grouping.TenantKey != tenantKey {
+			continue
+		}
+		kind := core.SubjectKindUser
+		if roles[grouping.UserKey] != nil {
+			kind = core.SubjectKindRole
+		}
+		snapshot.Groupings = append(snapshot.Groupings, core.SnapshotGrouping{Subject: grouping.UserKey, SubjectKind: kind, RoleKey: grouping.RoleKey})
+	}
+	sort.Slice(snapshot.Groupings, func(i, j int) bool {
+		a, b := snapshot.Groupings[i], snapshot.Groupings[j]
+		if a.RoleKey != b.RoleKey {
+			return a.RoleKey < b.RoleKey
+		}
+		return a.Subject < b.Subject
+	})
+
+	return snapshot, nil
+}
+
+// tenantPolicySnapshot 租户当前状态的快照，namespaced 表示直接权限的主体类型是否已填充
+type tenantPolicySnapshot struct {
+	core.PolicySnapshot
+	namespaced bool
+}
+
+// policyKind 返回用户直接权限在当前快照中的主体类型，用于与导入的快照对比
+func (s *tenantPolicySnapshot) policyKind() core.SubjectKind {
+	if s.namespaced {
+		return core.SubjectKindUser
+	}
+	return ""
+}
+
+// rolesByKey 按角色键索引角色
+func rolesByKey(roles []*core.Role) map[string]*core.Role {
+	indexed := make(map[string]*core.Role, len(roles))
+	for _, role := range roles {
+		indexed[role.Key] = role
+	}
+	return indexed
+}
+
+// policiesBySubject 按主体分组直接权限
+func policiesBySubject(policies []core.SnapshotPolicy) map[string][]core.Permission {
+	grouped := make(map[string][]core.Permission)
+	for _, policy := range policies {
+		grouped[policy.Subject] = append(grouped[policy.Subject], policy.Permission())
+	}
+	return grouped
+}
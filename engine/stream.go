@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// auditStreamBatchSize 流式导出审计日志时每批查询的记录数
+const auditStreamBatchSize = 500
+
+// StreamPolicies 逐条回调租户内的权限策略（tenantKey 为空时为所有租户，不含角色占位策略）
+// fn 返回错误时停止并返回该错误；回调同步执行，调用方写出较慢时遍历随之放缓
+func (c *casbinxClient) StreamPolicies(tenantKey string, fn func(core.Policy) error) (err error) {
+	defer c.guard.recover("StreamPolicies", &err)
+	if err := core.CheckArg(fn != nil, "fn", core.ConstraintRequired); err != nil {
+		return err
+	}
+	return c.streamPolicies(tenantKey, fn)
+}
+
+// StreamGroupingPolicies 逐条回调租户内的角色分配和角色继承（tenantKey 为空时为所有租户）
+func (c *casbinxClient) StreamGroupingPolicies(tenantKey string, fn func(core.GroupingPolicy) error) (err error) {
+	defer c.guard.recover("StreamGroupingPolicies", &err)
+	if err := core.CheckArg(fn != nil, "fn", core.ConstraintRequired); err != nil {
+		return err
+	}
+	return c.enforcer.RangeGroupingPolicies(tenantKey, fn)
+}
+
+// StreamAuditEvents 按写入顺序逐条回调符合条件的审计记录，权限要求与 ListAuditEvents 相同
+// 记录分批从数据库读取，当前批次回调完成后才读取下一批，内存占用与总记录数无关
+func (c *casbinxClient) StreamAuditEvents(operatorKey string, filter core.AuditFilter, fn func(core.PermissionChange) error) (err error) {
+	defer c.guard.recover("StreamAuditEvents", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.CheckArg(fn != nil, "fn", core.ConstraintRequired),
+	); err != nil {
+		return err
+	}
+	if err := c.requireAuditRead(operatorKey, filter.TenantKey); err != nil {
+		return err
+	}
+	return c.audit.StreamEvents(c.ctx, filter, auditStreamBatchSize, fn)
+}
+
+// streamPolicies 遍历权限策略并跳过角色占位策略
+func (c *casbinxClient) streamPolicies(tenantKey string, fn func(core.Policy) error) error {
+	return c.enforcer.RangePolicies(tenantKey, func(policy core.Policy) error {
+		if policy.Resource == core.ResourcePlaceholder {
+			return nil
+		}
+		return fn(policy)
+	})
+}
@@ -0,0 +1,395 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// ProvisionTenants 批量开通租户
+// 每个租户依次执行：登记租户 -> 实例化角色模板 -> 分配管理员角色。
+// 单个租户失败不会中断整个批次，失败原因记录在对应的结果中；已完成的步骤不会回滚，
+// 调用方可根据结果中的 CreatedRoles / AssignedAdmins 决定重试或清理。
+func (c *casbinxClient) ProvisionTenants(operatorKey string, specs []core.TenantSpec) (_ []core.TenantProvisionResult, err error) {
+	defer c.guard.recover("ProvisionTenants", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return nil, err
+	}
+
+	// 批量开通涉及租户登记，要求操作者拥有全局租户管理权限
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return nil, err
+	}
+
+	return c.provisionTenants(operatorKey, specs, nil)
+}
+
+// provisionTenants 逐个开通租户，每开通一个报告一次进度，任务取消时停止并返回已处理租户的结果
+func (c *casbinxClient) provisionTenants(operatorKey string, specs []core.TenantSpec, progress jobProgress) ([]core.TenantProvisionResult, error) {
+	results := make([]core.TenantProvisionResult, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+	for i, spec := range specs {
+		if err := progress.report(i, len(specs)); err != nil {
+			return results, err
+		}
+
+		result := core.TenantProvisionResult{TenantKey: spec.TenantKey}
+
+		if seen[spec.TenantKey] {
+			result.Error = fmt.Sprintf("租户 '%s' 在批次中重复出现", spec.TenantKey)
+			results = append(results, result)
+			continue
+		}
+		seen[spec.TenantKey] = true
+
+		if err := c.provisionTenant(operatorKey, spec, &result); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+	}
+
+	return results, progress.report(len(specs), len(specs))
+}
+
+// provisionTenant 开通单个租户，执行进度写入 result
+func (c *casbinxClient) provisionTenant(operatorKey string, spec core.TenantSpec, result *core.TenantProvisionResult) error {
+	if err := core.RequireTenantArg("spec.tenantKey", spec.TenantKey); err != nil {
+		return err
+	}
+
+	// 1. 登记租户
+	name := spec.Name
+	if name == "" {
+		name = spec.TenantKey
+	}
+	if err := c.tenantManager.RegisterTenant(c.ctx, operatorKey, spec.TenantKey, name); err != nil {
+		return err
+	}
+
+	// 2. 实例化角色模板
+	for _, template := range spec.RoleTemplates {
+		instance := template.Instantiate(spec.TenantKey)
+		if err := c.CreateRole(operatorKey, instance.Key, instance.Name, instance.Description, spec.TenantKey, instance.Permissions); err != nil {
+			return fmt.Errorf("创建角色 '%s' 失败: %w", instance.Key, err)
+		}
+		result.CreatedRoles = append(result.CreatedRoles, instance.Key)
+	}
+
+	// 3. 分配管理员
+	if len(spec.AdminUserKeys) == 0 {
+		return nil
+	}
+	if spec.AdminRoleKey == "" {
+		return errors.New("指定了租户管理员但未指定管理员角色")
+	}
+	adminRoleKey := core.RoleTemplate{Key: spec.AdminRoleKey}.Instantiate(spec.TenantKey).Key
+	for _, adminUserKey := range spec.AdminUserKeys {
+		if err := c.InitializeTenant(spec.TenantKey, adminUserKey, adminRoleKey); err != nil {
+			return fmt.Errorf("分配管理员 '%s' 失败: %w", adminUserKey, err)
+		}
+		result.AssignedAdmins = append(result.AssignedAdmins, adminUserKey)
+	}
+
+	return nil
+}
+
+// GetTenant 获取已登记的租户信息
+func (c *casbinxClient) GetTenant(tenantKey string) (_ *core.Tenant, err error) {
+	defer c.guard.recover("GetTenant", &err)
+	return c.tenantManager.GetTenant(c.ctx, tenantKey)
+}
+
+// ListTenants 获取所有租户，按租户键排序
+// 包括已登记的租户，以及未登记但出现在权限策略、角色分配或角色归属中的租户；别名和全局域 * 不计入
+func (c *casbinxClient) ListTenants() (_ []*core.Tenant, err error) {
+	defer c.guard.recover("ListTenants", &err)
+	registered, err := c.tenantManager.ListTenants(c.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := make(map[string]*core.Tenant, len(registered))
+	for _, tenant := range registered {
+		tenants[tenant.Key] = tenant
+	}
+	addTenant := func(tenantKey string) {
+		if tenantKey == "" || tenantKey == "*" || tenants[tenantKey] != nil {
+			return
+		}
+		if c.tenantManager.GetTenantAlias(c.ctx, tenantKey) != nil {
+			return
+		}
+		tenants[tenantKey] = &core.Tenant{Key: tenantKey}
+	}
+
+	policies, err := c.enforcer.GetAllPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		addTenant(policy.Domain)
+	}
+
+	groupings, err := c.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for _, grouping := range groupings {
+		addTenant(grouping.TenantKey)
+	}
+
+	roles, err := c.roleManager.ListRoles(c.ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		addTenant(role.TenantKey)
+	}
+
+	result := make([]*core.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		result = append(result, tenant)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// GetTenantStats 获取租户统计
+// 别名会解析为规范租户，别名下尚未迁移的授权一并计入；角色继承、全局角色分配和角色占位策略不计入
+func (c *casbinxClient) GetTenantStats(tenantKey string) (_ *core.TenantStats, err error) {
+	defer c.guard.recover("GetTenantStats", &err)
+	if err := core.RequireTenantArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	tenantKey = c.tenantManager.ResolveTenantKey(c.ctx, tenantKey)
+	domains := map[string]bool{tenantKey: true}
+	for _, alias := range c.tenantManager.ListTenantAliases(c.ctx, tenantKey) {
+		domains[alias.AliasKey] = true
+	}
+
+	stats := &core.TenantStats{TenantKey: tenantKey}
+
+	// 租户可见的角色（含全局角色和链接的共享角色）用于区分角色继承和用户分配
+	roles, err := c.roleManager.ListRoles(c.ctx, tenantKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	roleKeys := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		roleKeys[role.Key] = true
+		if role.TenantKey == tenantKey {
+			stats.Roles++
+		}
+	}
+
+	users := make(map[string]bool)
+	groupings, err := c.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for _, grouping := range groupings {
+		if !domains[grouping.TenantKey] || roleKeys[grouping.UserKey] {
+			continue
+		}
+		stats.RoleAssignments++
+		users[grouping.UserKey] = true
+	}
+
+	for domain := range domains {
+		policies, err := c.policyManager.ListPolicies(c.ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			if policy.SubjectKind == core.SubjectKindRole || (policy.SubjectKind == "" && roleKeys[policy.Subject]) {
+				continue
+			}
+			stats.DirectPermissions++
+			users[policy.Subject] = true
+		}
+	}
+
+	stats.Users = len(users)
+	return stats, nil
+}
+
+// DeleteTenant 删除租户（下线）
+// 依次移除租户及其别名下的所有权限策略和角色分配、归属租户的角色及其元数据、规则条件、规则优先级和环境标记，
+// 最后删除租户登记信息、封锁状态和别名。所有变更合并为一次同步通知；中途失败时已完成的步骤不会回滚，可重复执行
+func (c *casbinxClient) DeleteTenant(operatorKey, tenantKey string) (_ *core.TenantDeletionResult, err error) {
+	defer c.guard.recover("DeleteTenant", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireTenantArg("tenantKey", tenantKey),
+	); err != nil {
+		return nil, err
+	}
+	if err := c.requireGlobalTenantPermission(operatorKey, core.ActionDelete); err != nil {
+		return nil, err
+	}
+
+	return c.deleteTenant(operatorKey, tenantKey, nil)
+}
+
+// deleteTenant 删除租户，按域（租户及其别名）、角色、租户登记三类步骤报告进度，任务取消时在步骤之间停止
+func (c *casbinxClient) deleteTenant(operatorKey, tenantKey string, progress jobProgress) (*core.TenantDeletionResult, error) {
+	result := &core.TenantDeletionResult{TenantKey: tenantKey}
+	for _, alias := range c.tenantManager.ListTenantAliases(c.ctx, tenantKey) {
+		result.AliasKeys = append(result.AliasKeys, alias.AliasKey)
+	}
+
+	domains := append([]string{tenantKey}, result.AliasKeys...)
+	total := len(domains) + 2
+	err := c.enforcer.BatchNotifications(func() error {
+		for i, domain := range domains {
+			if err := progress.report(i, total); err != nil {
+				return err
+			}
+
+			policies, groupings, err := c.enforcer.RemoveDomain(c.ctx, domain)
+			result.RemovedPolicies += policies
+			result.RemovedGroupings += groupings
+			if err != nil {
+				return err
+			}
+			if err := c.conditions.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.priorities.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.environments.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.actions.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.jobFunctions.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.schedules.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.expirations.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+			if err := c.denies.DeleteDomain(c.ctx, domain); err != nil {
+				return err
+			}
+		}
+
+		if err := progress.report(len(domains), total); err != nil {
+			return err
+		}
+		deletedRoles, err := c.roleManager.DeleteTenantRoles(c.ctx, tenantKey)
+		result.DeletedRoles = deletedRoles
+		for _, roleKey := range deletedRoles {
+			c.recordAudit(nil, core.PermissionChange{Action: core.AuditActionDelete, OperatorKey: operatorKey, Target: roleKey, TenantKey: tenantKey})
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := progress.report(len(domains)+1, total); err != nil {
+			return err
+		}
+		if err := c.tenantManager.DeleteTenant(c.ctx, tenantKey); err != nil {
+			return err
+		}
+		return progress.report(total, total)
+	})
+	return result, err
+}
+
+// SetTenantLockdown 封锁租户（数据事故应急）
+// 封锁期间租户内的所有权限检查对未持有放行角色的用户一律拒绝，状态通过 Watcher 同步到所有实例
+func (c *casbinxClient) SetTenantLockdown(operatorKey, tenantKey string, allowedRoles []string) (err error) {
+	defer c.guard.recover("SetTenantLockdown", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireTenantArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return err
+	}
+
+	if err := c.tenantManager.SetTenantLockdown(c.ctx, operatorKey, tenantKey, allowedRoles); err != nil {
+		return err
+	}
+
+	c.emitSecurityEvent(core.SecurityEvent{
+		Class:       core.SecurityEventLockdown,
+		Action:      "set",
+		OperatorKey: operatorKey,
+		TenantKey:   tenantKey,
+		Target:      tenantKey,
+		Detail:      "放行角色: " + strings.Join(allowedRoles, ", "),
+	})
+	return nil
+}
+
+// ClearTenantLockdown 解除租户封锁
+func (c *casbinxClient) ClearTenantLockdown(operatorKey, tenantKey string) (err error) {
+	defer c.guard.recover("ClearTenantLockdown", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return err
+	}
+
+	if err := c.tenantManager.ClearTenantLockdown(c.ctx, tenantKey); err != nil {
+		return err
+	}
+
+	c.emitSecurityEvent(core.SecurityEvent{
+		Class:       core.SecurityEventLockdown,
+		Action:      "clear",
+		OperatorKey: operatorKey,
+		TenantKey:   tenantKey,
+		Target:      tenantKey,
+	})
+	return nil
+}
+
+// GetTenantLockdown 获取租户封锁状态，未封锁时返回 nil
+func (c *casbinxClient) GetTenantLockdown(tenantKey string) (_ *core.TenantLockdown, err error) {
+	defer c.guard.recover("GetTenantLockdown", &err)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+	return c.tenantManager.GetTenantLockdown(c.ctx, tenantKey), nil
+}
+
+// requireGlobalTenantManagement 要求操作者拥有全局租户管理权限
+func (c *casbinxClient) requireGlobalTenantManagement(operatorKey string) error {
+	return c.requireGlobalTenantPermission(operatorKey, core.ActionWrite)
+}
+
+// requireGlobalTenantPermission 验证操作者拥有全局租户操作权限
+func (c *casbinxClient) requireGlobalTenantPermission(operatorKey string, action core.Action) error {
+	hasPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{
+		Resource: core.ResourceTenant,
+		Action:   action,
+	})
+	if err != nil {
+		return fmt.Errorf("检查租户管理权限失败: %w", err)
+	}
+	if !hasPermission {
+		return core.ErrPermissionDenied
+	}
+	return nil
+}
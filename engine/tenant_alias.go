@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// AddTenantAlias 添加租户别名（旧租户键 -> 新租户键）
+// 添加后，使用任一租户键进行权限检查时，旧键和新键下的授权都会生效
+func (c *casbinxClient) AddTenantAlias(operatorKey, aliasKey, tenantKey string) (err error) {
+	defer c.guard.recover("AddTenantAlias", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return err
+	}
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return err
+	}
+
+	return c.tenantManager.AddTenantAlias(c.ctx, operatorKey, aliasKey, tenantKey)
+}
+
+// RemoveTenantAlias 移除租户别名
+// 别名下仍有未迁移的授权时，这些授权将不再对新租户键生效，通常应先调用 MigrateTenantAlias
+func (c *casbinxClient) RemoveTenantAlias(operatorKey, aliasKey string) (err error) {
+	defer c.guard.recover("RemoveTenantAlias", &err)
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		return err
+	}
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return err
+	}
+
+	return c.tenantManager.RemoveTenantAlias(c.ctx, aliasKey)
+}
+
+// ListTenantAliases 获取指向租户的所有别名
+func (c *casbinxClient) ListTenantAliases(tenantKey string) (_ []core.TenantAlias, err error) {
+	defer c.guard.recover("ListTenantAliases", &err)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+	return c.tenantManager.ListTenantAliases(c.ctx, c.tenantManager.ResolveTenantKey(c.ctx, tenantKey)), nil
+}
+
+// MigrateTenantAlias 将别名下的授权物理改写到规范租户键
+// 依次改写权限策略和角色分配、角色归属租户、环境标记和规则优先级；别名本身保留，旧租户键的请求仍可正常解析。
+// 可在业务低峰期执行，重复执行是安全的
+func (c *casbinxClient) MigrateTenantAlias(operatorKey, aliasKey string) (_ *core.TenantAliasMigrationResult, err error) {
+	defer c.guard.recover("MigrateTenantAlias", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("aliasKey", aliasKey),
+	); err != nil {
+		return nil, err
+	}
+	if err := c.requireGlobalTenantManagement(operatorKey); err != nil {
+		return nil, err
+	}
+
+	alias := c.tenantManager.GetTenantAlias(c.ctx, aliasKey)
+	if alias == nil {
+		return nil, core.ErrTenantAliasNotFound
+	}
+
+	rewritten, err := c.tenantManager.RewriteAliasRules(c.ctx, aliasKey)
+	if err != nil {
+		return nil, err
+	}
+	result := &core.TenantAliasMigrationResult{
+		AliasKey:            alias.AliasKey,
+		TenantKey:           alias.TenantKey,
+		DomainRewriteResult: *rewritten,
+	}
+
+	if result.MigratedRoles, err = c.roleManager.RenameTenant(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.environments.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.priorities.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.conditions.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.actions.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.jobFunctions.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.schedules.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.expirations.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+	if err := c.denies.RenameDomain(c.ctx, alias.AliasKey, alias.TenantKey); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
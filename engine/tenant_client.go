@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// tenantClient 绑定租户的客户端实现，所有操作都转发到底层客户端并使用绑定的租户
+type tenantClient struct {
+	client    CasbinX
+	tenantKey string
+}
+
+// ForTenant 返回绑定到租户的客户端，方法省略 tenantKey 参数，所有操作都在该租户内执行
+// 租户键为空或为全局域 * 时，客户端的每个方法都返回参数校验错误
+func (c *casbinxClient) ForTenant(tenantKey string) TenantClient {
+	return &tenantClient{client: c, tenantKey: tenantKey}
+}
+
+func (t *tenantClient) TenantKey() string {
+	return t.tenantKey
+}
+
+func (t *tenantClient) WithContext(ctx context.Context) TenantClient {
+	return t.client.WithContext(ctx).ForTenant(t.tenantKey)
+}
+
+// scope 校验绑定的租户，全局域不允许绑定，避免操作扩散到所有租户
+func (t *tenantClient) scope() error {
+	return core.RequireTenantArg("tenantKey", t.tenantKey)
+}
+
+// 权限检查
+
+func (t *tenantClient) CheckPermission(userKey string, permission core.Permission) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.CheckPermission(userKey, t.tenantKey, permission)
+}
+
+func (t *tenantClient) CheckPermissionVersioned(userKey string, permission core.Permission) (*core.CheckResult, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.CheckPermissionVersioned(userKey, t.tenantKey, permission)
+}
+
+func (t *tenantClient) HasDirectPermission(userKey string, permission core.Permission) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.HasDirectPermission(userKey, t.tenantKey, permission)
+}
+
+func (t *tenantClient) HasRole(userKey, roleKey string) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.HasRole(userKey, roleKey, t.tenantKey)
+}
+
+func (t *tenantClient) CheckPermissionDebug(userKey string, permission core.Permission) (*core.CheckTrace, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.CheckPermissionDebug(userKey, t.tenantKey, permission)
+}
+
+func (t *tenantClient) ExplainPermission(userKey string, permission core.Permission) (*core.PermissionExplanation, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.ExplainPermission(userKey, t.tenantKey, permission)
+}
+
+func (t *tenantClient) EnforceWithAttributes(userKey string, permission core.Permission, attrs map[string]any) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.EnforceWithAttributes(userKey, t.tenantKey, permission, attrs)
+}
+
+func (t *tenantClient) CheckMultiplePermissions(userKey string, permissions []core.Permission) ([]bool, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.CheckMultiplePermissions(userKey, t.tenantKey, permissions)
+}
+
+func (t *tenantClient) HasAnyPermission(userKey string, permissions []core.Permission) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.HasAnyPermission(userKey, t.tenantKey, permissions)
+}
+
+func (t *tenantClient) HasAllPermissions(userKey string, permissions []core.Permission) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.HasAllPermissions(userKey, t.tenantKey, permissions)
+}
+
+func (t *tenantClient) CanAccessResource(userKey string, resource core.Resource) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.CanAccessResource(userKey, t.tenantKey, resource)
+}
+
+func (t *tenantClient) CanAccessTenant(userKey string) (bool, error) {
+	if err := t.scope(); err != nil {
+		return false, err
+	}
+	return t.client.CanAccessTenant(userKey, t.tenantKey)
+}
+
+func (t *tenantClient) GetAvailableActions(userKey string, resource core.Resource) ([]core.Action, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetAvailableActions(userKey, t.tenantKey, resource)
+}
+
+// 用户权限管理
+
+func (t *tenantClient) GrantPermission(operatorKey, userKey string, permission core.Permission, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.GrantPermission(operatorKey, userKey, t.tenantKey, permission, opts...)
+}
+
+func (t *tenantClient) RevokePermission(operatorKey, userKey string, permission core.Permission, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.RevokePermission(operatorKey, userKey, t.tenantKey, permission, opts...)
+}
+
+func (t *tenantClient) GrantTemporaryPermission(operatorKey, userKey string, permission core.Permission, expiresAt time.Time, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.GrantTemporaryPermission(operatorKey, userKey, t.tenantKey, permission, expiresAt, opts...)
+}
+
+func (t *tenantClient) GrantPermissions(operatorKey, userKey string, permissions []core.Permission, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.GrantPermissions(operatorKey, userKey, t.tenantKey, permissions, opts...)
+}
+
+func (t *tenantClient) RevokePermissions(operatorKey, userKey string, permissions []core.Permission, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.RevokePermissions(operatorKey, userKey, t.tenantKey, permissions, opts...)
+}
+
+func (t *tenantClient) ClearUserPermissions(operatorKey, userKey string, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.ClearUserPermissions(operatorKey, userKey, t.tenantKey, opts...)
+}
+
+func (t *tenantClient) GetDirectPermissionsSecure(operatorKey, userKey string) ([]core.Permission, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetDirectPermissionsSecure(operatorKey, userKey, t.tenantKey)
+}
+
+func (t *tenantClient) GetEffectivePermissionsSecure(operatorKey, userKey string) ([]core.Permission, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetEffectivePermissionsSecure(operatorKey, userKey, t.tenantKey)
+}
+
+func (t *tenantClient) GetUserPermissionsByResourceSecure(operatorKey, userKey, resource string) ([]core.Permission, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetUserPermissionsByResourceSecure(operatorKey, userKey, t.tenantKey, resource)
+}
+
+// 用户角色分配
+
+func (t *tenantClient) AssignRole(operatorKey, userKey, roleKey string, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.AssignRole(operatorKey, userKey, roleKey, t.tenantKey, opts...)
+}
+
+func (t *tenantClient) AssignTemporaryRole(operatorKey, userKey, roleKey string, expiresAt time.Time, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.AssignTemporaryRole(operatorKey, userKey, roleKey, t.tenantKey, expiresAt, opts...)
+}
+
+func (t *tenantClient) RemoveRole(operatorKey, userKey, roleKey string, opts ...core.MutationOption) error {
+	if err := t.scope(); err != nil {
+		return err
+	}
+	return t.client.RemoveRole(operatorKey, userKey, roleKey, t.tenantKey, opts...)
+}
+
+func (t *tenantClient) AssignRoleToUsers(operatorKey, roleKey string, userKeys []string, opts ...core.MutationOption) ([]core.RoleAssignmentResult, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.AssignRoleToUsers(operatorKey, roleKey, t.tenantKey, userKeys, opts...)
+}
+
+func (t *tenantClient) GetUserRolesSecure(operatorKey, userKey string) ([]string, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetUserRolesSecure(operatorKey, userKey, t.tenantKey)
+}
+
+// 租户内查询
+
+func (t *tenantClient) ListRoles(filter *core.RoleFilter) ([]*core.Role, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.ListRoles(t.tenantKey, filter)
+}
+
+func (t *tenantClient) GetUsersWithRole(roleKey string) ([]string, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetUsersWithRole(roleKey, t.tenantKey)
+}
+
+func (t *tenantClient) GetUsersWithPermission(permission core.Permission) ([]string, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetUsersWithPermission(t.tenantKey, permission)
+}
+
+func (t *tenantClient) ListGroupingPolicies() (*core.ListResult[core.GroupingPolicy], error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.ListGroupingPolicies(t.tenantKey)
+}
+
+func (t *tenantClient) GetRoleHierarchy() ([]core.RoleInheritance, error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.GetRoleHierarchy(t.tenantKey)
+}
+
+func (t *tenantClient) ListTenantMembers(page core.PageRequest) (*core.PageResponse[core.TenantMember], error) {
+	if err := t.scope(); err != nil {
+		return nil, err
+	}
+	return t.client.ListTenantMembers(t.tenantKey, page)
+}
@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/role"
+)
+
+// txState 事务状态：事务内的审计记录在提交后写入，回滚时丢弃；附属数据的撤销操作在回滚时执行
+type txState struct {
+	mu     sync.Mutex
+	audits []core.PermissionChange
+	undos  []func(ctx context.Context) error // 附属数据的撤销操作，按写入顺序排列
+}
+
+// recordAudit 暂存事务内的审计记录
+func (s *txState) recordAudit(change core.PermissionChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.audits = append(s.audits, change)
+}
+
+// recordUndo 登记附属数据写入的撤销操作
+func (s *txState) recordUndo(undo func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.undos = append(s.undos, undo)
+}
+
+// undo 按相反顺序执行撤销操作，单步失败时继续执行其余操作并返回所有错误
+func (s *txState) undo(ctx context.Context) error {
+	s.mu.Lock()
+	undos := s.undos
+	s.undos = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(undos) - 1; i >= 0; i-- {
+		if err := undos[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// txGate 事务闸门：事务执行期间本实例事务外的变更等待事务结束，事务开始前等待执行中的变更完成
+// 变更执行中嵌套调用其他公开方法（如 InitializeTenant 内的 CreateRole）不会被等待中的事务阻塞
+type txGate struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	active int  // 执行中的事务外变更数
+	locked bool // 是否有事务正在执行
+}
+
+// newTxGate 创建事务闸门
+func newTxGate() *txGate {
+	gate := &txGate{}
+	gate.cond = sync.NewCond(&gate.mu)
+	return gate
+}
+
+// enter 事务外的变更开始执行，有事务正在执行时等待
+func (g *txGate) enter() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.locked {
+		g.cond.Wait()
+	}
+	g.active++
+}
+
+// exit 事务外的变更执行结束
+func (g *txGate) exit() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	g.cond.Broadcast()
+}
+
+// lock 事务开始，等待其他事务和执行中的变更结束
+func (g *txGate) lock() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.locked || g.active > 0 {
+		g.cond.Wait()
+	}
+	g.locked = true
+}
+
+// unlock 事务结束
+func (g *txGate) unlock() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.locked = false
+	g.cond.Broadcast()
+}
+
+// WithCompensation 执行多步变更，fn 返回错误（或 panic）时以补偿写入撤销 fn 通过 tx 执行的变更，成功时保留
+// 不提供原子性和隔离：每次写入立即提交到存储并对权限检查和其他实例可见，失败时通过反向写入逐条撤销，
+// 撤销失败（如存储不可用）时变更只被部分撤销；审计记录在结束后写入，撤销时丢弃，同步通知在执行期间暂停，结束后只发布一次。
+// 撤销只针对本次自身写入的权限策略、角色分配、角色元数据，以及规则优先级、条件、环境标记和授权过期时间，
+// 其他实例在执行期间的写入保留；共享角色的发布和链接、角色冻结以及已完成的幂等键不撤销。
+// 按域或用户批量迁移、删除附属数据的操作（如 MigrateTenantAlias、DeleteTenant、RenameUser、DeleteUserData、MergeUsers）
+// 和 CleanupObjectPermissions 无法按条撤销，在 fn 内调用时返回 core.ErrTxUnsupported。
+// 执行期间本实例的其他变更等待结束（其他实例的写入不受限制），fn 内的变更必须通过 tx 执行，否则会相互等待。
+// 在 fn 内再次调用 WithCompensation 时并入当前执行；撤销未完成时返回 core.ErrTxRollbackIncomplete
+func (c *casbinxClient) WithCompensation(fn func(tx CasbinX) error) (err error) {
+	defer c.guard.recover("WithCompensation", &err)
+	if c.tx != nil {
+		return fn(c)
+	}
+
+	c.txGate.lock()
+	defer c.txGate.unlock()
+
+	rules, err := c.enforcer.BeginJournal()
+	if err != nil {
+		return err
+	}
+	defer c.enforcer.EndJournal(rules)
+	roles, err := c.roleManager.BeginMetadataJournal()
+	if err != nil {
+		return err
+	}
+	defer c.roleManager.EndMetadataJournal(roles)
+
+	tx := *c
+	tx.tx = &txState{}
+	tx.wrapTxManagers()
+	err = c.enforcer.BatchNotifications(func() error {
+		if err := runTx(&tx, fn); err != nil {
+			return c.rollbackTx(rules, roles, tx.tx, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, change := range tx.tx.audits {
+		if err := c.audit.Record(c.ctx, change); err != nil {
+			c.guard.logger.Error("写入审计日志失败", "action", change.Action, "operator", change.OperatorKey, "target", change.Target, "tenant", change.TenantKey, "error", err)
+		}
+	}
+	return nil
+}
+
+// runTx 执行事务函数，panic 转换为错误以便回滚（不受 RecoveryConfig.Disabled 影响）
+func runTx(tx *casbinxClient, fn func(tx CasbinX) error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr := core.NewPanicError("WithCompensation", value, !tx.guard.config.DisableStack)
+			tx.guard.logger.Error("事务函数发生 panic，回滚事务", "panic", value, "stack", panicErr.Stack)
+			err = panicErr
+		}
+	}()
+	return fn(tx)
+}
+
+// rollbackTx 撤销事务自身的写入：先撤销规则，再恢复角色元数据，最后按相反顺序恢复附属数据，返回导致回滚的原始错误
+// 事务因 ctx 取消失败时回滚仍需写入，因此使用不随 ctx 取消的上下文；单步失败时继续撤销其余变更
+func (c *casbinxClient) rollbackTx(rules *core.RuleJournal, roles *role.MetadataJournal, state *txState, cause error) error {
+	ctx := context.WithoutCancel(c.ctx)
+
+	var errs []error
+	if err := c.enforcer.RevertJournal(ctx, rules); err != nil {
+		errs = append(errs, fmt.Errorf("撤销权限策略和角色分配失败: %v", err))
+	}
+	if err := c.roleManager.RevertMetadataJournal(ctx, roles); err != nil {
+		errs = append(errs, fmt.Errorf("恢复角色元数据失败: %v", err))
+	}
+	if err := state.undo(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("恢复附属数据失败: %v", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: 执行失败（%v），%v", core.ErrTxRollbackIncomplete, cause, errors.Join(errs...))
+	}
+	return cause
+}
@@ -0,0 +1,262 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/condition"
+	"github.com/rezeropoint/casbinx/internal/environment"
+	"github.com/rezeropoint/casbinx/internal/expiration"
+	"github.com/rezeropoint/casbinx/internal/priority"
+)
+
+// wrapTxManagers 将附属数据管理器替换为事务内的版本：写入前记录原值并登记撤销操作，
+// 无法按条撤销的批量迁移和删除返回 core.ErrTxUnsupported
+func (c *casbinxClient) wrapTxManagers() {
+	c.priorities = txPriorityManager{Manager: c.priorities, tx: c.tx}
+	c.conditions = txConditionManager{Manager: c.conditions, tx: c.tx}
+	c.environments = txEnvironmentManager{Manager: c.environments, tx: c.tx}
+	c.expirations = txExpirationManager{Manager: c.expirations, tx: c.tx}
+}
+
+// txUnsupported 返回事务内不支持的附属数据操作错误
+func txUnsupported(operation string) error {
+	return fmt.Errorf("%w: %s", core.ErrTxUnsupported, operation)
+}
+
+// txPriorityManager 事务内的规则优先级管理器
+type txPriorityManager struct {
+	priority.Manager
+	tx *txState
+}
+
+// SetPolicyPriority 设置规则优先级，回滚时恢复原优先级
+func (m txPriorityManager) SetPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission, priority int) error {
+	m.recordPriority(ctx, kind, subjectKey, domain, permission)
+	return m.Manager.SetPolicyPriority(ctx, kind, subjectKey, domain, permission, priority)
+}
+
+// ClearPolicyPriority 清除规则优先级，回滚时恢复原优先级
+func (m txPriorityManager) ClearPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) error {
+	m.recordPriority(ctx, kind, subjectKey, domain, permission)
+	return m.Manager.ClearPolicyPriority(ctx, kind, subjectKey, domain, permission)
+}
+
+// ReorderPolicies 重新设置规则优先级，回滚时恢复各规则的原优先级
+func (m txPriorityManager) ReorderPolicies(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permissions []core.Permission) error {
+	for _, permission := range permissions {
+		m.recordPriority(ctx, kind, subjectKey, domain, permission)
+	}
+	return m.Manager.ReorderPolicies(ctx, kind, subjectKey, domain, permissions)
+}
+
+// RenameDomain 事务内不支持
+func (m txPriorityManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	return txUnsupported("迁移域的规则优先级")
+}
+
+// DeleteDomain 事务内不支持
+func (m txPriorityManager) DeleteDomain(ctx context.Context, domain string) error {
+	return txUnsupported("删除域的规则优先级")
+}
+
+// recordPriority 记录规则的原优先级并登记撤销操作
+func (m txPriorityManager) recordPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) {
+	previous, found := m.Manager.GetPolicyPriority(ctx, kind, subjectKey, domain, permission)
+	m.tx.recordUndo(func(ctx context.Context) error {
+		if found {
+			return m.Manager.SetPolicyPriority(ctx, kind, subjectKey, domain, permission, previous)
+		}
+		return m.Manager.ClearPolicyPriority(ctx, kind, subjectKey, domain, permission)
+	})
+}
+
+// txConditionManager 事务内的规则条件管理器
+type txConditionManager struct {
+	condition.Manager
+	tx *txState
+}
+
+// SetPolicyCondition 设置规则条件，回滚时恢复原条件
+func (m txConditionManager) SetPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission, expression string) error {
+	m.recordCondition(ctx, kind, subjectKey, domain, permission)
+	return m.Manager.SetPolicyCondition(ctx, kind, subjectKey, domain, permission, expression)
+}
+
+// ClearPolicyCondition 清除规则条件，回滚时恢复原条件
+func (m txConditionManager) ClearPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) error {
+	m.recordCondition(ctx, kind, subjectKey, domain, permission)
+	return m.Manager.ClearPolicyCondition(ctx, kind, subjectKey, domain, permission)
+}
+
+// RenameDomain 事务内不支持
+func (m txConditionManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	return txUnsupported("迁移域的规则条件")
+}
+
+// DeleteDomain 事务内不支持
+func (m txConditionManager) DeleteDomain(ctx context.Context, domain string) error {
+	return txUnsupported("删除域的规则条件")
+}
+
+// recordCondition 记录规则的原条件并登记撤销操作
+func (m txConditionManager) recordCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) {
+	previous, found := m.Manager.GetPolicyCondition(ctx, kind, subjectKey, domain, permission)
+	m.tx.recordUndo(func(ctx context.Context) error {
+		if found {
+			return m.Manager.SetPolicyCondition(ctx, kind, subjectKey, domain, permission, previous)
+		}
+		return m.Manager.ClearPolicyCondition(ctx, kind, subjectKey, domain, permission)
+	})
+}
+
+// txEnvironmentManager 事务内的授权环境标记管理器
+type txEnvironmentManager struct {
+	environment.Manager
+	tx *txState
+}
+
+// SetPolicyEnvironments 设置授权生效的环境，回滚时恢复原环境标记
+func (m txEnvironmentManager) SetPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission, environments []string) error {
+	m.recordEnvironments(ctx, subject, domain, permission)
+	return m.Manager.SetPolicyEnvironments(ctx, subject, domain, permission, environments)
+}
+
+// ClearPolicyEnvironments 清除授权的环境标记，回滚时恢复原环境标记
+func (m txEnvironmentManager) ClearPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission) error {
+	m.recordEnvironments(ctx, subject, domain, permission)
+	return m.Manager.ClearPolicyEnvironments(ctx, subject, domain, permission)
+}
+
+// RenameSubject 事务内不支持
+func (m txEnvironmentManager) RenameSubject(ctx context.Context, oldSubject, newSubject string) error {
+	return txUnsupported("迁移主体的环境标记")
+}
+
+// RenameDomain 事务内不支持
+func (m txEnvironmentManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	return txUnsupported("迁移域的环境标记")
+}
+
+// DeleteDomain 事务内不支持
+func (m txEnvironmentManager) DeleteDomain(ctx context.Context, domain string) error {
+	return txUnsupported("删除域的环境标记")
+}
+
+// recordEnvironments 记录授权的原环境标记并登记撤销操作
+func (m txEnvironmentManager) recordEnvironments(ctx context.Context, subject, domain string, permission core.Permission) {
+	previous := m.Manager.GetPolicyEnvironments(ctx, subject, domain, permission)
+	m.tx.recordUndo(func(ctx context.Context) error {
+		if len(previous) > 0 {
+			return m.Manager.SetPolicyEnvironments(ctx, subject, domain, permission, previous)
+		}
+		return m.Manager.ClearPolicyEnvironments(ctx, subject, domain, permission)
+	})
+}
+
+// txExpirationManager 事务内的限时授权管理器
+type txExpirationManager struct {
+	expiration.Manager
+	tx *txState
+}
+
+// SetPermissionExpiry 设置用户直接权限的过期时间，回滚时恢复原过期记录
+func (m txExpirationManager) SetPermissionExpiry(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission, expiresAt time.Time) error {
+	if err := m.recordPermissionExpiry(ctx, userKey, tenantKey, permission); err != nil {
+		return err
+	}
+	return m.Manager.SetPermissionExpiry(ctx, operatorKey, userKey, tenantKey, permission, expiresAt)
+}
+
+// ClearPermissionExpiry 清除用户直接权限的过期时间，回滚时恢复原过期记录
+func (m txExpirationManager) ClearPermissionExpiry(ctx context.Context, userKey, tenantKey string, permission core.Permission) error {
+	if err := m.recordPermissionExpiry(ctx, userKey, tenantKey, permission); err != nil {
+		return err
+	}
+	return m.Manager.ClearPermissionExpiry(ctx, userKey, tenantKey, permission)
+}
+
+// SetRoleExpiry 设置角色分配的过期时间，回滚时恢复原过期记录
+func (m txExpirationManager) SetRoleExpiry(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string, expiresAt time.Time) error {
+	if err := m.recordRoleExpiry(ctx, userKey, roleKey, tenantKey); err != nil {
+		return err
+	}
+	return m.Manager.SetRoleExpiry(ctx, operatorKey, userKey, roleKey, tenantKey, expiresAt)
+}
+
+// ClearRoleExpiry 清除角色分配的过期时间，回滚时恢复原过期记录
+func (m txExpirationManager) ClearRoleExpiry(ctx context.Context, userKey, roleKey, tenantKey string) error {
+	if err := m.recordRoleExpiry(ctx, userKey, roleKey, tenantKey); err != nil {
+		return err
+	}
+	return m.Manager.ClearRoleExpiry(ctx, userKey, roleKey, tenantKey)
+}
+
+// DeleteExpired 删除过期记录，回滚时恢复被删除的记录
+func (m txExpirationManager) DeleteExpired(ctx context.Context, grant core.ExpiringGrant) (bool, error) {
+	claimed, err := m.Manager.DeleteExpired(ctx, grant)
+	if claimed {
+		m.tx.recordUndo(func(ctx context.Context) error { return m.restore(ctx, &grant) })
+	}
+	return claimed, err
+}
+
+// RenameUser 事务内不支持
+func (m txExpirationManager) RenameUser(ctx context.Context, oldKey, newKey string) error {
+	return txUnsupported("迁移用户的限时授权")
+}
+
+// DeleteUser 事务内不支持
+func (m txExpirationManager) DeleteUser(ctx context.Context, userKey string) error {
+	return txUnsupported("删除用户的限时授权")
+}
+
+// RenameDomain 事务内不支持
+func (m txExpirationManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	return txUnsupported("迁移租户的限时授权")
+}
+
+// DeleteDomain 事务内不支持
+func (m txExpirationManager) DeleteDomain(ctx context.Context, domain string) error {
+	return txUnsupported("删除租户的限时授权")
+}
+
+// recordPermissionExpiry 记录用户直接权限的原过期记录并登记撤销操作
+func (m txExpirationManager) recordPermissionExpiry(ctx context.Context, userKey, tenantKey string, permission core.Permission) error {
+	previous, err := m.Manager.GetPermissionExpiry(ctx, userKey, tenantKey, permission)
+	if err != nil {
+		return err
+	}
+	m.tx.recordUndo(func(ctx context.Context) error {
+		if previous != nil {
+			return m.restore(ctx, previous)
+		}
+		return m.Manager.ClearPermissionExpiry(ctx, userKey, tenantKey, permission)
+	})
+	return nil
+}
+
+// recordRoleExpiry 记录角色分配的原过期记录并登记撤销操作
+func (m txExpirationManager) recordRoleExpiry(ctx context.Context, userKey, roleKey, tenantKey string) error {
+	previous, err := m.Manager.GetRoleExpiry(ctx, userKey, roleKey, tenantKey)
+	if err != nil {
+		return err
+	}
+	m.tx.recordUndo(func(ctx context.Context) error {
+		if previous != nil {
+			return m.restore(ctx, previous)
+		}
+		return m.Manager.ClearRoleExpiry(ctx, userKey, roleKey, tenantKey)
+	})
+	return nil
+}
+
+// restore 按原操作者和过期时间写回过期记录
+func (m txExpirationManager) restore(ctx context.Context, grant *core.ExpiringGrant) error {
+	if grant.Kind == core.ExpiringRole {
+		return m.Manager.SetRoleExpiry(ctx, grant.OperatorKey, grant.UserKey, grant.RoleKey, grant.TenantKey, grant.ExpiresAt)
+	}
+	return m.Manager.SetPermissionExpiry(ctx, grant.OperatorKey, grant.UserKey, grant.TenantKey, grant.Permission, grant.ExpiresAt)
+}
@@ -0,0 +1,252 @@
+package engine
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// ExportUserData 导出 casbinx 保存的与用户相关的全部数据（数据主体访问请求）
+// 包含所有租户的直接授权及环境标记、角色分配、审计记录、邀请授权来源、创建的邀请、合并记录和决策日志；
+// 导出跨越所有租户，要求操作者拥有全局用户查看权限。当前版本没有委托授权，导出中不包含此类数据。
+func (c *casbinxClient) ExportUserData(operatorKey, userKey string) (_ *core.UserDataExport, err error) {
+	defer c.guard.recover("ExportUserData", &err)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("userKey", userKey),
+	); err != nil {
+		return nil, err
+	}
+
+	hasPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{Resource: core.ResourceUser, Action: core.ActionRead})
+	if err != nil {
+		return nil, fmt.Errorf("检查操作者用户查看权限时出错: %w", err)
+	}
+	if !hasPermission {
+		return nil, core.ErrPermissionDenied
+	}
+
+	export := &core.UserDataExport{UserKey: userKey, ExportedAt: time.Now()}
+
+	// 1. 直接授权及环境标记
+	policies, err := c.userPolicies(userKey)
+	if err != nil {
+		return nil, err
+	}
+	export.Permissions = make([]core.UserPermissionData, 0, len(policies))
+	for _, policy := range policies {
+		permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+		export.Permissions = append(export.Permissions, core.UserPermissionData{
+			Policy:       policy,
+			Environments: c.environments.GetPolicyEnvironments(c.ctx, userKey, policy.Domain, permission),
+		})
+	}
+
+	// 2. 角色分配
+	if export.Roles, err = c.userGroupings(userKey); err != nil {
+		return nil, err
+	}
+
+	// 3. 审计记录：用户作为被授权人或操作者
+	if export.AuditEvents, err = c.userAuditEvents(userKey); err != nil {
+		return nil, err
+	}
+
+	// 4. 分享邀请
+	if export.InvitationGrants, err = c.invitations.ListUserGrants(c.ctx, userKey); err != nil {
+		return nil, err
+	}
+	if export.CreatedInvitations, err = c.invitations.ListCreatedInvitations(c.ctx, userKey); err != nil {
+		return nil, err
+	}
+
+	// 5. 合并记录
+	if export.Merges, err = c.userManager.ListMerges(c.ctx, userKey); err != nil {
+		return nil, err
+	}
+
+	// 6. 决策日志
+	export.Decisions = []core.Decision{}
+	if c.decisions != nil {
+		if export.Decisions, err = c.decisions.ListUserDecisions(c.ctx, userKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return export, nil
+}
+
+// DeleteUserData 删除用户的授权、角色分配及 casbinx 保存的相关记录（数据主体删除请求）
+// 审计记录和合并记录按 Config.UserData 的保留规则处理：保留期内的记录保留（可替换为假名），其余删除；
+// 用户创建的分享邀请被撤销，其他用户记录中引用该用户的位置替换为本次删除生成的假名。
+// 删除跨越所有租户，要求操作者拥有全局用户管理权限。
+func (c *casbinxClient) DeleteUserData(operatorKey, userKey string, opts ...core.MutationOption) (_ *core.UserDataDeletion, err error) {
+	defer c.guard.recover("DeleteUserData", &err)
+	var result *core.UserDataDeletion
+	err = c.runQueued(c.enforcer.UserSubject(userKey), opts, "DeleteUserData", []string{operatorKey, userKey}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("userKey", userKey),
+		); err != nil {
+			return err
+		}
+
+		hasPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite})
+		if err != nil {
+			return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+		}
+		if !hasPermission {
+			return core.ErrPermissionDenied
+		}
+
+		// 冻结检查：用户持有冻结角色时不允许删除其角色分配
+		if err := c.ensureUserRolesUnlocked(userKey); err != nil {
+			return err
+		}
+
+		pseudonym, err := newPseudonym()
+		if err != nil {
+			return err
+		}
+		deletion := &core.UserDataDeletion{UserKey: userKey, Pseudonym: pseudonym, DeletedBy: operatorKey}
+
+		// 1. 授权及其优先级、条件、环境标记，角色分配
+		if deletion.RemovedPermissions, err = c.userPolicies(userKey); err != nil {
+			return err
+		}
+		if deletion.RemovedRoles, err = c.userGroupings(userKey); err != nil {
+			return err
+		}
+		if err := c.userManager.ClearUserPermissions(c.ctx, operatorKey, userKey); err != nil {
+			return err
+		}
+		if err := c.userManager.ClearUserRoles(c.ctx, operatorKey, userKey); err != nil {
+			return err
+		}
+		for _, policy := range deletion.RemovedPermissions {
+			permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+			if err := c.priorities.ClearPolicyPriority(c.ctx, core.SubjectKindUser, userKey, policy.Domain, permission); err != nil {
+				return err
+			}
+			if err := c.conditions.ClearPolicyCondition(c.ctx, core.SubjectKindUser, userKey, policy.Domain, permission); err != nil {
+				return err
+			}
+			if err := c.environments.ClearPolicyEnvironments(c.ctx, userKey, policy.Domain, permission); err != nil {
+				return err
+			}
+		}
+		if err := c.jobFunctions.DeleteUser(c.ctx, userKey); err != nil {
+			return err
+		}
+		if err := c.schedules.DeleteUser(c.ctx, userKey); err != nil {
+			return err
+		}
+		if err := c.expirations.DeleteUser(c.ctx, userKey); err != nil {
+			return err
+		}
+		if err := c.denies.DeleteUser(c.ctx, userKey); err != nil {
+			return err
+		}
+
+		// 2. 分享邀请和决策日志（无保留要求）
+		if deletion.DeletedInvitationGrants, err = c.invitations.EraseUser(c.ctx, userKey, pseudonym); err != nil {
+			return err
+		}
+		if c.decisions != nil {
+			if deletion.DeletedDecisions, err = c.decisions.EraseUser(c.ctx, userKey); err != nil {
+				return err
+			}
+		}
+
+		// 3. 审计记录和合并记录按保留规则处理
+		var retainSince time.Time
+		if c.userData.AuditRetention > 0 {
+			retainSince = time.Now().Add(-c.userData.AuditRetention)
+		}
+		retainedPseudonym := ""
+		if c.userData.PseudonymizeRetained {
+			retainedPseudonym = pseudonym
+		}
+		if deletion.DeletedAuditEvents, deletion.RetainedAuditEvents, err = c.audit.EraseUser(c.ctx, userKey, retainSince, retainedPseudonym); err != nil {
+			return err
+		}
+		if deletion.DeletedMerges, deletion.RetainedMerges, err = c.userManager.EraseMerges(c.ctx, userKey, retainSince, retainedPseudonym); err != nil {
+			return err
+		}
+
+		deletion.DeletedAt = time.Now()
+		result = deletion
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// userPolicies 获取用户在所有租户的直接授权
+func (c *casbinxClient) userPolicies(userKey string) ([]core.Policy, error) {
+	policies, err := c.policyManager.ListPolicies(c.ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	userPolicies := []core.Policy{}
+	for _, policy := range policies {
+		if policy.Subject == userKey {
+			userPolicies = append(userPolicies, policy)
+		}
+	}
+	return userPolicies, nil
+}
+
+// userGroupings 获取用户在所有租户的角色分配
+func (c *casbinxClient) userGroupings(userKey string) ([]core.GroupingPolicy, error) {
+	groupings, err := c.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return nil, err
+	}
+	userGroupings := []core.GroupingPolicy{}
+	for _, grouping := range groupings {
+		if grouping.UserKey == userKey {
+			userGroupings = append(userGroupings, grouping)
+		}
+	}
+	return userGroupings, nil
+}
+
+// userAuditEvents 获取用户作为被授权人或操作者的审计记录（按时间顺序，两者相同的记录只出现一次）
+func (c *casbinxClient) userAuditEvents(userKey string) ([]core.PermissionChange, error) {
+	events := []core.PermissionChange{}
+	seen := make(map[string]bool)
+	collect := func(change core.PermissionChange) error {
+		if !seen[change.ID] {
+			seen[change.ID] = true
+			events = append(events, change)
+		}
+		return nil
+	}
+	if err := c.audit.StreamEvents(c.ctx, core.AuditFilter{UserKey: userKey}, auditStreamBatchSize, collect); err != nil {
+		return nil, err
+	}
+	if err := c.audit.StreamEvents(c.ctx, core.AuditFilter{OperatorKey: userKey}, auditStreamBatchSize, collect); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// newPseudonym 生成用户数据删除使用的随机假名，与原用户标识无关联
+func newPseudonym() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成假名失败: %v", err)
+	}
+	return "erased-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// MergeUsers 账号合并：将源用户的直接授权和角色分配迁移到目标用户
+// 目标用户已拥有的授权或分配只从源用户移除；授权的环境标记随授权迁移，重复授权取两者中更宽的生效范围。
+// 合并跨越所有租户，要求操作者拥有全局用户管理权限。
+func (c *casbinxClient) MergeUsers(operatorKey, fromUserKey, toUserKey string, opts ...core.MutationOption) (_ *core.UserMergeResult, err error) {
+	defer c.guard.recover("MergeUsers", &err)
+	var result *core.UserMergeResult
+	err = c.runIdempotent(opts, "MergeUsers", []string{operatorKey, fromUserKey, toUserKey}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("fromUserKey", fromUserKey),
+			core.RequireArg("toUserKey", toUserKey),
+			core.CheckArg(fromUserKey != toUserKey, "toUserKey", core.ConstraintDistinct),
+		); err != nil {
+			return err
+		}
+
+		// 防止自我提权：操作者不能把其他账号的权限合并到自己名下
+		if c.securityValidator.GetSecurityConfig().PreventSelfElevation && (operatorKey == fromUserKey || operatorKey == toUserKey) {
+			return core.ErrSelfElevationPrevented
+		}
+
+		hasPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite})
+		if err != nil {
+			return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+		}
+		if !hasPermission {
+			return core.ErrPermissionDenied
+		}
+
+		// 冻结检查：源用户持有的冻结角色不允许变更分配
+		if err := c.ensureUserRolesUnlocked(fromUserKey); err != nil {
+			return err
+		}
+
+		// 先为目标用户写入环境标记，保证授权迁移后立即带有正确的环境限制
+		policies, err := c.policyManager.ListPolicies(c.ctx, "")
+		if err != nil {
+			return err
+		}
+
+		// targetGrants 以源用户为主体记录目标用户已有的授权，便于与源用户授权直接比较
+		var fromPolicies []core.Policy
+		targetGrants := make(map[core.Policy]bool)
+		for _, policy := range policies {
+			switch policy.Subject {
+			case fromUserKey:
+				fromPolicies = append(fromPolicies, policy)
+			case toUserKey:
+				policy.Subject = fromUserKey
+				targetGrants[policy] = true
+			}
+		}
+		for _, policy := range fromPolicies {
+			hasTarget := targetGrants[policy]
+			if err := c.mergeGrantEnvironments(policy, fromUserKey, toUserKey, hasTarget); err != nil {
+				return err
+			}
+		}
+
+		result, err = c.userManager.MergeUsers(c.ctx, operatorKey, fromUserKey, toUserKey)
+		if err != nil {
+			return err
+		}
+
+		// 迁移后的角色由目标用户手动持有，源用户的岗位记录不再有效
+		if err := c.jobFunctions.DeleteUser(c.ctx, fromUserKey); err != nil {
+			return err
+		}
+
+		// 迁移后的授权由目标用户永久持有，源用户的过期记录不再有效
+		if err := c.expirations.DeleteUser(c.ctx, fromUserKey); err != nil {
+			return err
+		}
+
+		// 源用户的拒绝规则继续限制目标用户，避免合并账号绕过拒绝
+		if err := c.denies.RenameUser(c.ctx, fromUserKey, toUserKey); err != nil {
+			return err
+		}
+
+		// 源用户尚未生效的定时变更改为作用于目标用户
+		if err := c.schedules.RenameUser(c.ctx, fromUserKey, toUserKey); err != nil {
+			return err
+		}
+
+		// 清理源用户的环境标记
+		for _, policy := range fromPolicies {
+			permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+			if err := c.environments.ClearPolicyEnvironments(c.ctx, fromUserKey, policy.Domain, permission); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeGrantEnvironments 计算并写入目标用户授权合并后的环境标记
+// hasTarget 表示目标用户在同一域已拥有该授权
+func (c *casbinxClient) mergeGrantEnvironments(policy core.Policy, fromUserKey, toUserKey string, hasTarget bool) error {
+	permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+	fromEnvironments := c.environments.GetPolicyEnvironments(c.ctx, fromUserKey, policy.Domain, permission)
+
+	if !hasTarget {
+		// 授权将被迁移：沿用源用户的环境标记
+		if fromEnvironments == nil {
+			return nil
+		}
+		return c.environments.SetPolicyEnvironments(c.ctx, toUserKey, policy.Domain, permission, fromEnvironments)
+	}
+
+	// 重复授权：未标记表示所有环境生效，取更宽的范围
+	toEnvironments := c.environments.GetPolicyEnvironments(c.ctx, toUserKey, policy.Domain, permission)
+	if toEnvironments == nil {
+		return nil
+	}
+	if fromEnvironments == nil {
+		return c.environments.ClearPolicyEnvironments(c.ctx, toUserKey, policy.Domain, permission)
+	}
+
+	merged := append([]string{}, toEnvironments...)
+	for _, environment := range fromEnvironments {
+		if !containsString(merged, environment) {
+			merged = append(merged, environment)
+		}
+	}
+	return c.environments.SetPolicyEnvironments(c.ctx, toUserKey, policy.Domain, permission, merged)
+}
+
+// containsString 检查字符串列表是否包含指定值
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
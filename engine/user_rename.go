@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// RenameUser 修改用户标识（如基于邮箱的用户标识变更），改写该用户在所有租户的授权、角色分配及环境标记
+// 新标识已有策略时返回 ErrUserKeyConflict；要求操作者拥有全局用户管理权限
+func (c *casbinxClient) RenameUser(operatorKey, oldKey, newKey string, opts ...core.MutationOption) (err error) {
+	defer c.guard.recover("RenameUser", &err)
+	return c.runIdempotent(opts, "RenameUser", []string{operatorKey, oldKey, newKey}, func() error {
+		if err := core.FirstError(
+			core.RequireArg("operatorKey", operatorKey),
+			core.RequireArg("oldKey", oldKey),
+			core.RequireArg("newKey", newKey),
+			core.CheckArg(oldKey != newKey, "newKey", core.ConstraintDistinct),
+		); err != nil {
+			return err
+		}
+
+		hasPermission, err := c.hasGlobalPermission(operatorKey, core.Permission{Resource: core.ResourceUser, Action: core.ActionWrite})
+		if err != nil {
+			return fmt.Errorf("检查操作者用户管理权限时出错: %w", err)
+		}
+		if !hasPermission {
+			return core.ErrPermissionDenied
+		}
+
+		// 冻结检查：用户持有冻结角色时不允许改写其角色分配
+		if err := c.ensureUserRolesUnlocked(oldKey); err != nil {
+			return err
+		}
+
+		// 冲突检查：新标识已被使用时不做任何改动
+		inUse, err := c.userManager.IsSubjectInUse(c.ctx, newKey)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return core.ErrUserKeyConflict
+		}
+
+		// 先迁移环境标记，保证改写后的授权立即带有正确的环境限制
+		if err := c.environments.RenameSubject(c.ctx, oldKey, newKey); err != nil {
+			return err
+		}
+
+		if err := c.userManager.RenameUser(c.ctx, oldKey, newKey); err != nil {
+			// 策略改写失败时把环境标记迁回原标识
+			if rollbackErr := c.environments.RenameSubject(c.ctx, newKey, oldKey); rollbackErr != nil {
+				return fmt.Errorf("%w（回滚环境标记失败: %v）", err, rollbackErr)
+			}
+			return err
+		}
+
+		if err := c.jobFunctions.RenameUser(c.ctx, oldKey, newKey); err != nil {
+			return err
+		}
+
+		if err := c.schedules.RenameUser(c.ctx, oldKey, newKey); err != nil {
+			return err
+		}
+
+		if err := c.expirations.RenameUser(c.ctx, oldKey, newKey); err != nil {
+			return err
+		}
+
+		return c.denies.RenameUser(c.ctx, oldKey, newKey)
+	})
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// clientV2 强类型接口实现，将强类型标识转换为字符串后转发到 CasbinX
+type clientV2 struct {
+	client CasbinX
+}
+
+// NewV2 基于已有客户端创建强类型接口，两者共享同一个引擎
+func NewV2(client CasbinX) CasbinXV2 {
+	return &clientV2{client: client}
+}
+
+func (c *clientV2) V1() CasbinX {
+	return c.client
+}
+
+func (c *clientV2) ForTenant(tenant core.TenantKey) TenantClient {
+	return c.client.ForTenant(string(tenant))
+}
+
+// 权限检查
+
+func (c *clientV2) CheckPermission(user core.UserKey, tenant core.TenantKey, permission core.Permission) (bool, error) {
+	return c.client.CheckPermission(string(user), string(tenant), permission)
+}
+
+func (c *clientV2) HasDirectPermission(user core.UserKey, tenant core.TenantKey, permission core.Permission) (bool, error) {
+	return c.client.HasDirectPermission(string(user), string(tenant), permission)
+}
+
+func (c *clientV2) HasRole(user core.UserKey, role core.RoleKey, tenant core.TenantKey) (bool, error) {
+	return c.client.HasRole(string(user), string(role), string(tenant))
+}
+
+func (c *clientV2) ExplainPermission(user core.UserKey, tenant core.TenantKey, permission core.Permission) (*core.PermissionExplanation, error) {
+	return c.client.ExplainPermission(string(user), string(tenant), permission)
+}
+
+func (c *clientV2) CheckMultiplePermissions(user core.UserKey, tenant core.TenantKey, permissions []core.Permission) ([]bool, error) {
+	return c.client.CheckMultiplePermissions(string(user), string(tenant), permissions)
+}
+
+func (c *clientV2) GetUserTenants(user core.UserKey) ([]core.TenantKey, error) {
+	tenants, err := c.client.GetUserTenants(string(user))
+	return core.TenantKeysOf(tenants), err
+}
+
+// 用户权限管理
+
+func (c *clientV2) GrantPermission(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey, permission core.Permission, opts ...core.MutationOption) error {
+	return c.client.GrantPermission(string(operator), string(user), string(tenant), permission, opts...)
+}
+
+func (c *clientV2) RevokePermission(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey, permission core.Permission, opts ...core.MutationOption) error {
+	return c.client.RevokePermission(string(operator), string(user), string(tenant), permission, opts...)
+}
+
+func (c *clientV2) GrantTemporaryPermission(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey, permission core.Permission, expiresAt time.Time, opts ...core.MutationOption) error {
+	return c.client.GrantTemporaryPermission(string(operator), string(user), string(tenant), permission, expiresAt, opts...)
+}
+
+func (c *clientV2) GetEffectivePermissionsSecure(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey) ([]core.Permission, error) {
+	return c.client.GetEffectivePermissionsSecure(string(operator), string(user), string(tenant))
+}
+
+// 用户角色分配
+
+func (c *clientV2) AssignRole(operator core.OperatorKey, user core.UserKey, role core.RoleKey, tenant core.TenantKey, opts ...core.MutationOption) error {
+	return c.client.AssignRole(string(operator), string(user), string(role), string(tenant), opts...)
+}
+
+func (c *clientV2) AssignTemporaryRole(operator core.OperatorKey, user core.UserKey, role core.RoleKey, tenant core.TenantKey, expiresAt time.Time, opts ...core.MutationOption) error {
+	return c.client.AssignTemporaryRole(string(operator), string(user), string(role), string(tenant), expiresAt, opts...)
+}
+
+func (c *clientV2) RemoveRole(operator core.OperatorKey, user core.UserKey, role core.RoleKey, tenant core.TenantKey, opts ...core.MutationOption) error {
+	return c.client.RemoveRole(string(operator), string(user), string(role), string(tenant), opts...)
+}
+
+func (c *clientV2) AssignRoleToUsers(operator core.OperatorKey, role core.RoleKey, tenant core.TenantKey, users []core.UserKey, opts ...core.MutationOption) ([]core.RoleAssignmentResult, error) {
+	return c.client.AssignRoleToUsers(string(operator), string(role), string(tenant), core.KeyStrings(users), opts...)
+}
+
+func (c *clientV2) GetUserRolesSecure(operator core.OperatorKey, user core.UserKey, tenant core.TenantKey) ([]core.RoleKey, error) {
+	roles, err := c.client.GetUserRolesSecure(string(operator), string(user), string(tenant))
+	return core.RoleKeysOf(roles), err
+}
+
+// 角色管理
+
+func (c *clientV2) CreateRole(operator core.OperatorKey, role core.RoleKey, roleName, description string, tenant core.TenantKey, permissions []core.Permission, opts ...core.MutationOption) error {
+	return c.client.CreateRole(string(operator), string(role), roleName, description, string(tenant), permissions, opts...)
+}
+
+func (c *clientV2) DeleteRole(role core.RoleKey, opts ...core.MutationOption) error {
+	return c.client.DeleteRole(string(role), opts...)
+}
+
+func (c *clientV2) GrantRolePermission(operator core.OperatorKey, role core.RoleKey, permission core.Permission, opts ...core.MutationOption) error {
+	return c.client.GrantRolePermission(string(operator), string(role), permission, opts...)
+}
+
+func (c *clientV2) RevokeRolePermission(operator core.OperatorKey, role core.RoleKey, permission core.Permission, opts ...core.MutationOption) error {
+	return c.client.RevokeRolePermission(string(operator), string(role), permission, opts...)
+}
+
+func (c *clientV2) GetUsersWithRole(role core.RoleKey, tenant core.TenantKey) ([]core.UserKey, error) {
+	users, err := c.client.GetUsersWithRole(string(role), string(tenant))
+	return core.UserKeysOf(users), err
+}
+
+func (c *clientV2) GetUsersWithPermission(tenant core.TenantKey, permission core.Permission) ([]core.UserKey, error) {
+	users, err := c.client.GetUsersWithPermission(string(tenant), permission)
+	return core.UserKeysOf(users), err
+}
@@ -0,0 +1,220 @@
+// Package gozero 提供 go-zero REST 服务的鉴权中间件和 ServiceContext 辅助
+//
+// 中间件基于 middleware.Authorizer，默认从 go-zero JWT 认证写入请求上下文的声明（claims）中读取用户和租户，
+// 可直接用于 rest.WithMiddlewares、server.Use 或 api 文件中声明的中间件。
+package gozero
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/engine"
+	"github.com/rezeropoint/casbinx/middleware"
+
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/pathvar"
+)
+
+// 默认读取的 JWT 声明
+const (
+	DefaultUserClaim   = "userId"   // 用户标识
+	DefaultTenantClaim = "tenantId" // 租户标识
+)
+
+// FromClaim 从 go-zero JWT 认证写入上下文的声明读取标识
+// go-zero 以声明名（string）为键写入上下文，数字声明解析为 json.Number 或 float64，均转换为字符串
+func FromClaim(name string) middleware.Extractor {
+	return func(r *http.Request) string {
+		return claimString(r.Context().Value(name))
+	}
+}
+
+// claimString 将声明值转换为字符串，不支持的类型返回空字符串
+func claimString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return ""
+	}
+}
+
+// Middleware 将 net/http 鉴权器转换为 go-zero 中间件
+func Middleware(authz *middleware.Authorizer) rest.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return authz.Middleware(next).ServeHTTP
+	}
+}
+
+// Authz 挂在 go-zero ServiceContext 上的权限组件，包含引擎和 HTTP 鉴权器
+type Authz struct {
+	CasbinX    engine.CasbinX
+	Authorizer *middleware.Authorizer
+}
+
+// Option NewAuthz 的可选参数
+type Option func(*options)
+
+// options Authz 构造选项集合
+type options struct {
+	userClaim   string              // 用户标识声明
+	tenantClaim string              // 租户标识声明
+	engine      []engine.Option     // 引擎构造选项
+	authorizer  []middleware.Option // 鉴权器选项（优先于声明提取）
+}
+
+// WithClaims 指定读取用户和租户的 JWT 声明，默认 userId 和 tenantId，传入空字符串时保持默认
+func WithClaims(userClaim, tenantClaim string) Option {
+	return func(o *options) {
+		if userClaim != "" {
+			o.userClaim = userClaim
+		}
+		if tenantClaim != "" {
+			o.tenantClaim = tenantClaim
+		}
+	}
+}
+
+// WithEngineOptions 传入引擎构造选项，如 engine.WithLogger、engine.WithCache
+func WithEngineOptions(opts ...engine.Option) Option {
+	return func(o *options) {
+		o.engine = append(o.engine, opts...)
+	}
+}
+
+// WithAuthorizerOptions 传入鉴权器选项，如 middleware.WithPermissionMapper；提取方式选项覆盖默认的声明提取
+func WithAuthorizerOptions(opts ...middleware.Option) Option {
+	return func(o *options) {
+		o.authorizer = append(o.authorizer, opts...)
+	}
+}
+
+// newOptions 应用构造选项
+func newOptions(opts []Option) options {
+	o := options{userClaim: DefaultUserClaim, tenantClaim: DefaultTenantClaim}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewAuthz 按配置创建引擎和鉴权器，用于 svc.NewServiceContext
+func NewAuthz(c core.Config, opts ...Option) (*Authz, error) {
+	o := newOptions(opts)
+	casbinx, err := engine.NewCasbinx(c, o.engine...)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuthzWithEngine(casbinx, opts...), nil
+}
+
+// NewAuthzWithEngine 使用已创建的引擎创建鉴权器，引擎选项被忽略
+func NewAuthzWithEngine(casbinx engine.CasbinX, opts ...Option) *Authz {
+	o := newOptions(opts)
+
+	// 上下文中已写入的标识优先（如自定义认证中间件调用了 middleware.WithUserKey），其次读取 JWT 声明
+	authorizerOpts := append([]middleware.Option{
+		middleware.WithUserExtractor(middleware.FirstOf(
+			func(r *http.Request) string { return middleware.UserKeyFromContext(r.Context()) },
+			FromClaim(o.userClaim),
+		)),
+		middleware.WithTenantExtractor(middleware.FirstOf(
+			func(r *http.Request) string { return middleware.TenantKeyFromContext(r.Context()) },
+			FromClaim(o.tenantClaim),
+		)),
+	}, o.authorizer...)
+
+	return &Authz{
+		CasbinX:    casbinx,
+		Authorizer: middleware.New(casbinx, authorizerOpts...),
+	}
+}
+
+// MustNewAuthz 同 NewAuthz，创建失败时 panic，与 go-zero 的 MustNewServer 等用法一致
+func MustNewAuthz(c core.Config, opts ...Option) *Authz {
+	authz, err := NewAuthz(c, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("创建 CasbinX 失败: %v", err))
+	}
+	return authz
+}
+
+// Middleware 按路由和请求方法推导权限的 go-zero 中间件
+func (a *Authz) Middleware() rest.Middleware {
+	return Middleware(a.Authorizer)
+}
+
+// Require 使用固定权限的 go-zero 中间件，用于单个路由或路由组
+func (a *Authz) Require(permission core.Permission) rest.Middleware {
+	require := a.Authorizer.Require(permission)
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return require(next).ServeHTTP
+	}
+}
+
+// RequireTagged 使用请求结构体上 casbinx 标签声明的权限的 go-zero 中间件，对象ID读取 go-zero 的路由参数
+// 标注格式见 middleware.PermissionTag；标注无效时返回错误
+//
+//	type DeleteOrderReq struct {
+//		_  struct{} `casbinx:"resource=order,action=delete,object=id"`
+//		ID string   `path:"id"`
+//	}
+func (a *Authz) RequireTagged(req any) (rest.Middleware, error) {
+	tag, err := middleware.ParsePermissionTag(req)
+	if err != nil {
+		return nil, err
+	}
+	require := a.Authorizer.RequireMapper(tag.Mapper(func(r *http.Request, name string) string {
+		return pathvar.Vars(r)[name]
+	}))
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return require(next).ServeHTTP
+	}, nil
+}
+
+// MustRequireTagged 同 RequireTagged，标注无效时 panic，便于在路由注册代码中直接使用
+func (a *Authz) MustRequireTagged(req any) rest.Middleware {
+	require, err := a.RequireTagged(req)
+	if err != nil {
+		panic(fmt.Sprintf("解析权限标注失败: %v", err))
+	}
+	return require
+}
+
+// TaggedRoutes 按请求结构体的权限标注为路由逐个挂载鉴权中间件，requests 的键为 Method + " " + Path
+// 没有登记请求结构体的路由返回错误，避免新增路由遗漏权限声明
+//
+//	routes, err := svcCtx.Authz.TaggedRoutes([]rest.Route{...}, map[string]any{
+//		"DELETE /orders/:id": types.DeleteOrderReq{},
+//	})
+//	server.AddRoutes(routes)
+func (a *Authz) TaggedRoutes(routes []rest.Route, requests map[string]any) ([]rest.Route, error) {
+	tagged := make([]rest.Route, 0, len(routes))
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		req, ok := requests[key]
+		if !ok {
+			return nil, fmt.Errorf("路由 %s 没有登记请求结构体", key)
+		}
+		require, err := a.RequireTagged(req)
+		if err != nil {
+			return nil, fmt.Errorf("路由 %s: %w", key, err)
+		}
+		route.Handler = require(route.Handler)
+		tagged = append(tagged, route)
+	}
+	return tagged, nil
+}
@@ -0,0 +1,232 @@
+// Package interceptor 提供 gRPC 鉴权拦截器
+//
+// 拦截器按完整方法名（如 /order.v1.OrderService/CreateOrder）查找需要的权限，
+// 从 metadata 中读取用户和租户（读取方式可配置）并检查，未通过时返回对应的 gRPC 状态码。
+// 方法与权限的对应关系通过登记表配置，也可以通过 PermissionResolver 从 proto 选项等来源读取。
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 默认读取的 metadata 键
+const (
+	DefaultUserKeyMetadata   = "x-user-key"   // 用户标识
+	DefaultTenantKeyMetadata = "x-tenant-key" // 租户标识
+)
+
+// Checker 权限检查接口，engine.CasbinX 满足该接口
+type Checker interface {
+	CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error)
+}
+
+// Extractor 从请求上下文中提取标识，返回空字符串表示没有该标识
+type Extractor func(ctx context.Context) string
+
+// PermissionResolver 按完整方法名解析需要的权限，ok 为 false 表示该方法未登记
+// 用于从 proto 方法选项等来源读取权限，登记表中没有的方法才会调用
+type PermissionResolver func(ctx context.Context, fullMethod string) (permission core.Permission, ok bool, err error)
+
+// FromMetadata 从 incoming metadata 读取第一个值（只应在网关已认证并覆盖该键时使用）
+func FromMetadata(key string) Extractor {
+	key = strings.ToLower(key)
+	return func(ctx context.Context) string {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return ""
+		}
+		values := md.Get(key)
+		if len(values) == 0 {
+			return ""
+		}
+		return strings.TrimSpace(values[0])
+	}
+}
+
+// FromContext 从上下文的指定键读取字符串，如认证拦截器写入的用户标识
+func FromContext(key any) Extractor {
+	return func(ctx context.Context) string {
+		value, _ := ctx.Value(key).(string)
+		return value
+	}
+}
+
+// Static 固定值，如单租户服务的租户标识
+func Static(value string) Extractor {
+	return func(context.Context) string {
+		return value
+	}
+}
+
+// Authorizer gRPC 鉴权器
+type Authorizer struct {
+	checker   Checker
+	user      Extractor
+	tenant    Extractor
+	methods   map[string]core.Permission
+	public    map[string]bool
+	resolver  PermissionResolver
+	allowMiss bool
+}
+
+// Option 鉴权器选项
+type Option func(*Authorizer)
+
+// WithUserExtractor 指定用户标识的提取方式，默认读取 metadata 的 x-user-key
+func WithUserExtractor(extractor Extractor) Option {
+	return func(a *Authorizer) {
+		if extractor != nil {
+			a.user = extractor
+		}
+	}
+}
+
+// WithTenantExtractor 指定租户标识的提取方式，默认读取 metadata 的 x-tenant-key
+func WithTenantExtractor(extractor Extractor) Option {
+	return func(a *Authorizer) {
+		if extractor != nil {
+			a.tenant = extractor
+		}
+	}
+}
+
+// WithMethodPermissions 登记方法需要的权限，键为完整方法名，可多次调用
+func WithMethodPermissions(methods map[string]core.Permission) Option {
+	return func(a *Authorizer) {
+		for method, permission := range methods {
+			a.methods[method] = permission
+		}
+	}
+}
+
+// WithPublicMethods 登记不需要鉴权的方法（如健康检查、反射服务）
+func WithPublicMethods(methods ...string) Option {
+	return func(a *Authorizer) {
+		for _, method := range methods {
+			a.public[method] = true
+		}
+	}
+}
+
+// WithPermissionResolver 登记表中没有的方法通过 resolver 解析权限，如读取 proto 方法选项
+func WithPermissionResolver(resolver PermissionResolver) Option {
+	return func(a *Authorizer) {
+		a.resolver = resolver
+	}
+}
+
+// AllowUnmappedMethods 放行未登记权限的方法，默认拒绝，避免新增方法遗漏登记时被直接暴露
+func AllowUnmappedMethods() Option {
+	return func(a *Authorizer) {
+		a.allowMiss = true
+	}
+}
+
+// New 创建鉴权器
+func New(checker Checker, opts ...Option) *Authorizer {
+	a := &Authorizer{
+		checker: checker,
+		user:    FromMetadata(DefaultUserKeyMetadata),
+		tenant:  FromMetadata(DefaultTenantKeyMetadata),
+		methods: make(map[string]core.Permission),
+		public:  make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Authorize 检查调用方能否调用方法：公开方法和放行的未登记方法直接通过；
+// 缺少用户返回 ErrUnauthenticated，缺少租户返回 *core.FieldError，未登记的方法和没有权限返回 ErrPermissionDenied
+func (a *Authorizer) Authorize(ctx context.Context, fullMethod string) error {
+	if a.public[fullMethod] {
+		return nil
+	}
+	permission, ok, err := a.methodPermission(ctx, fullMethod)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if a.allowMiss {
+			return nil
+		}
+		return core.ErrPermissionDenied
+	}
+
+	userKey := a.user(ctx)
+	if userKey == "" {
+		return core.ErrUnauthenticated
+	}
+	tenantKey := a.tenant(ctx)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return err
+	}
+
+	allowed, err := a.checker.CheckPermission(userKey, tenantKey, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return core.ErrPermissionDenied
+	}
+	return nil
+}
+
+// methodPermission 查找方法需要的权限，先查登记表再调用解析器
+func (a *Authorizer) methodPermission(ctx context.Context, fullMethod string) (core.Permission, bool, error) {
+	if permission, ok := a.methods[fullMethod]; ok {
+		return permission, true, nil
+	}
+	if a.resolver == nil {
+		return core.Permission{}, false, nil
+	}
+	return a.resolver(ctx, fullMethod)
+}
+
+// UnaryServerInterceptor 一元调用拦截器
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.Authorize(ctx, info.FullMethod); err != nil {
+			return nil, Status(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 流式调用拦截器，在建立流时鉴权一次
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Authorize(ss.Context(), info.FullMethod); err != nil {
+			return Status(err)
+		}
+		return handler(srv, ss)
+	}
+}
+
+// Status 将鉴权错误转换为 gRPC 状态：缺少用户为 Unauthenticated，参数错误为 InvalidArgument，
+// 内部错误为 Internal（不暴露错误详情），其余 core.Error（包括 ErrPermissionDenied）为 PermissionDenied，消息包含错误码
+func Status(err error) error {
+	var coreErr core.Error
+	switch {
+	case errors.Is(err, core.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, core.ErrUnauthenticated.Error())
+	case errors.Is(err, core.ErrInvalidParameter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, core.ErrInternal):
+		return status.Error(codes.Internal, core.ErrInternal.Error())
+	case errors.As(err, &coreErr):
+		return status.Error(codes.PermissionDenied, coreErr.Error())
+	default:
+		return status.Error(codes.Internal, core.ErrInternal.Error())
+	}
+}
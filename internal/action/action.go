@@ -0,0 +1,24 @@
+package action
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 租户自定义资源操作管理器接口
+type Manager interface {
+	DefineAction(ctx context.Context, operatorKey string, definition core.ResourceAction) error           // 定义资源操作，已存在时返回 ErrResourceActionAlreadyExists
+	RemoveAction(ctx context.Context, tenantKey string, resource core.Resource, action core.Action) error // 删除资源操作，不存在时返回 ErrResourceActionNotFound
+	ListActions(ctx context.Context, tenantKey string, resource core.Resource) []*core.ResourceAction     // 获取在租户资源上生效的自定义操作（含全局定义），resource 为空时返回所有资源
+	IsDefined(ctx context.Context, tenantKey string, resource core.Resource, action core.Action) bool     // 操作是否已在租户（或全局）的资源上定义
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error                                  // 将域的所有自定义操作迁移到新域
+	DeleteDomain(ctx context.Context, domain string) error                                                // 删除域的所有自定义操作
+}
+
+// NewManager 创建租户自定义资源操作管理器，并注册为授权时的操作校验器
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer, securityValidator *core.SecurityValidator) (Manager, error) {
+	return newActionManager(dbConn, enforcer, securityValidator)
+}
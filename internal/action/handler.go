@@ -0,0 +1,257 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// actionManager 租户自定义资源操作管理器实现
+type actionManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu      sync.RWMutex
+	actions map[string]*core.ResourceAction // 定义键 -> 自定义操作
+}
+
+// newActionManager 创建租户自定义资源操作管理器实现
+func newActionManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer, securityValidator *core.SecurityValidator) (*actionManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("自定义资源操作管理器初始化失败: %v", err)
+	}
+
+	manager := &actionManager{
+		enforcer: enforcer,
+		dbConn:   dbConn,
+		actions:  make(map[string]*core.ResourceAction),
+	}
+
+	if err := manager.loadActions(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新自定义操作，并在授权时校验操作是否已定义
+	enforcer.AddReloadHook(func() error { return manager.loadActions(context.Background()) })
+	securityValidator.SetActionValidator(manager.validateAction)
+
+	return manager, nil
+}
+
+// DefineAction 定义资源操作
+func (m *actionManager) DefineAction(ctx context.Context, operatorKey string, definition core.ResourceAction) error {
+	if err := core.FirstError(
+		core.RequireArg("definition.tenantKey", definition.TenantKey),
+		core.RequireArg("definition.resource", definition.Resource),
+		core.CheckArg(!strings.Contains(string(definition.Resource), ":"), "definition.resource", core.ConstraintFormat),
+	); err != nil {
+		return err
+	}
+	if err := core.ValidateCustomAction(definition.Action); err != nil {
+		return err
+	}
+
+	insertSQL := `
+		INSERT INTO casbin_resource_actions (tenant_key, resource, action, description, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_key, resource, action) DO NOTHING
+	`
+	result, err := m.dbConn.ExecCtx(ctx, insertSQL, definition.TenantKey, string(definition.Resource), string(definition.Action), definition.Description, operatorKey)
+	if err != nil {
+		return fmt.Errorf("定义资源操作失败: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("定义资源操作失败: %v", err)
+	}
+	if affected == 0 {
+		return core.ErrResourceActionAlreadyExists
+	}
+
+	return m.sync(ctx)
+}
+
+// RemoveAction 删除资源操作
+func (m *actionManager) RemoveAction(ctx context.Context, tenantKey string, resource core.Resource, action core.Action) error {
+	if err := core.FirstError(
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireArg("resource", resource),
+		core.RequireArg("action", action),
+	); err != nil {
+		return err
+	}
+
+	deleteSQL := `DELETE FROM casbin_resource_actions WHERE tenant_key = $1 AND resource = $2 AND action = $3`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, tenantKey, string(resource), string(action))
+	if err != nil {
+		return fmt.Errorf("删除资源操作失败: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("删除资源操作失败: %v", err)
+	}
+	if affected == 0 {
+		return core.ErrResourceActionNotFound
+	}
+
+	return m.sync(ctx)
+}
+
+// ListActions 获取在租户资源上生效的自定义操作，按资源、操作排序
+func (m *actionManager) ListActions(ctx context.Context, tenantKey string, resource core.Resource) []*core.ResourceAction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	actions := make([]*core.ResourceAction, 0)
+	for _, definition := range m.actions {
+		if definition.TenantKey != tenantKey && definition.TenantKey != "*" {
+			continue
+		}
+		if resource != "" && definition.Resource != resource {
+			continue
+		}
+		copied := *definition
+		actions = append(actions, &copied)
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Resource != actions[j].Resource {
+			return actions[i].Resource < actions[j].Resource
+		}
+		if actions[i].Action != actions[j].Action {
+			return actions[i].Action < actions[j].Action
+		}
+		return actions[i].TenantKey < actions[j].TenantKey
+	})
+	return actions
+}
+
+// IsDefined 操作是否已在租户（或全局）的资源上定义
+func (m *actionManager) IsDefined(ctx context.Context, tenantKey string, resource core.Resource, action core.Action) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	resourceType := core.ResourceType(resource)
+	_, defined := m.actions[actionKey(tenantKey, string(resourceType), string(action))]
+	if !defined {
+		_, defined = m.actions[actionKey("*", string(resourceType), string(action))]
+	}
+	return defined
+}
+
+// RenameDomain 将旧域下的所有自定义操作迁移到新域，新域已有的定义保留
+func (m *actionManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	var affected int64
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteSQL := `
+			DELETE FROM casbin_resource_actions o
+			WHERE o.tenant_key = $1
+			  AND EXISTS (
+			    SELECT 1 FROM casbin_resource_actions n
+			    WHERE n.tenant_key = $2 AND n.resource = o.resource AND n.action = o.action
+			  )
+		`
+		if _, err := session.ExecCtx(ctx, deleteSQL, oldDomain, newDomain); err != nil {
+			return fmt.Errorf("清理重复的资源操作失败: %v", err)
+		}
+
+		updateSQL := `UPDATE casbin_resource_actions SET tenant_key = $2 WHERE tenant_key = $1`
+		result, err := session.ExecCtx(ctx, updateSQL, oldDomain, newDomain)
+		if err != nil {
+			return fmt.Errorf("迁移资源操作失败: %v", err)
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 没有需要迁移的记录时无需同步
+	if affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// DeleteDomain 删除域下的所有自定义操作
+func (m *actionManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	deleteSQL := `DELETE FROM casbin_resource_actions WHERE tenant_key = $1`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, domain)
+	if err != nil {
+		return fmt.Errorf("删除域资源操作失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// validateAction 操作校验器：自定义操作须在租户（或全局）的资源上定义
+func (m *actionManager) validateAction(tenantKey string, permission core.Permission) error {
+	if !m.IsDefined(context.Background(), tenantKey, permission.Resource, permission.Action) {
+		return fmt.Errorf("%w: %s", core.ErrResourceActionNotDefined, permission)
+	}
+	return nil
+}
+
+// loadActions 从数据库加载自定义操作到内存
+func (m *actionManager) loadActions(ctx context.Context) error {
+	var records []*resourceActionRecord
+	selectSQL := `SELECT tenant_key, resource, action, description, created_by, created_at FROM casbin_resource_actions`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载自定义资源操作失败: %v", err)
+	}
+
+	actions := make(map[string]*core.ResourceAction, len(records))
+	for _, record := range records {
+		actions[actionKey(record.TenantKey, record.Resource, record.Action)] = &core.ResourceAction{
+			TenantKey:   record.TenantKey,
+			Resource:    core.Resource(record.Resource),
+			Action:      core.Action(record.Action),
+			Description: record.Description,
+			CreatedBy:   record.CreatedBy,
+			CreatedAt:   record.CreatedAt,
+		}
+	}
+
+	m.mu.Lock()
+	m.actions = actions
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地自定义操作并通知其他实例
+func (m *actionManager) sync(ctx context.Context) error {
+	if err := m.loadActions(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// actionKey 生成定义键
+func actionKey(tenantKey, resource, action string) string {
+	return strings.Join([]string{tenantKey, resource, action}, "\x00")
+}
@@ -0,0 +1,40 @@
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// resourceActionRecord 自定义资源操作记录
+type resourceActionRecord struct {
+	TenantKey   string    `db:"tenant_key"`
+	Resource    string    `db:"resource"`
+	Action      string    `db:"action"`
+	Description string    `db:"description"`
+	CreatedBy   string    `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// initDB 初始化数据库，创建自定义资源操作表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_resource_actions (
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_key, resource, action)
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_resource_actions表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 权限变更审计日志管理器接口
+type Manager interface {
+	Record(ctx context.Context, change core.PermissionChange) error                                                       // 写入一条变更记录，时间戳为空时使用当前时间
+	ListEvents(ctx context.Context, filter core.AuditFilter, offset, limit int) ([]core.PermissionChange, int, error)     // 按条件分页查询变更记录（按时间倒序），同时返回符合条件的总条数
+	StreamEvents(ctx context.Context, filter core.AuditFilter, batchSize int, fn func(core.PermissionChange) error) error // 按条件逐条回调变更记录（按写入顺序分批查询）
+	EraseUser(ctx context.Context, userKey string, retainSince time.Time, pseudonym string) (int, int, error)             // 删除用户作为被授权人或操作者的记录，retainSince 之后的记录保留（pseudonym 非空时替换用户标识），返回删除和保留的条数
+}
+
+// NewManager 创建审计日志管理器，变更原因使用 cipher 加密存储
+func NewManager(dbConn sqlx.SqlConn, cipher *core.FieldCipher) (Manager, error) {
+	return newAuditManager(dbConn, cipher)
+}
@@ -0,0 +1,247 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// auditManager 审计日志管理器实现
+type auditManager struct {
+	dbConn sqlx.SqlConn
+	cipher *core.FieldCipher // 敏感字段加密器（变更原因）
+}
+
+// newAuditManager 创建审计日志管理器实现
+func newAuditManager(dbConn sqlx.SqlConn, cipher *core.FieldCipher) (*auditManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("审计日志管理器初始化失败: %v", err)
+	}
+
+	return &auditManager{
+		dbConn: dbConn,
+		cipher: cipher,
+	}, nil
+}
+
+// Record 写入一条变更记录
+func (m *auditManager) Record(ctx context.Context, change core.PermissionChange) error {
+	if err := core.FirstError(
+		core.RequireArg("change.action", change.Action),
+		core.RequireArg("change.target", change.Target),
+	); err != nil {
+		return err
+	}
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+
+	reason, err := m.cipher.Encrypt(change.Reason)
+	if err != nil {
+		return err
+	}
+
+	insertSQL := `
+		INSERT INTO casbin_audit (user_key, action, target, tenant_key, operator_key, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, insertSQL, change.UserKey, string(change.Action), change.Target,
+		change.TenantKey, change.OperatorKey, reason, change.Timestamp); err != nil {
+		return fmt.Errorf("写入审计日志失败: %v", err)
+	}
+
+	return nil
+}
+
+// ListEvents 按条件分页查询变更记录
+func (m *auditManager) ListEvents(ctx context.Context, filter core.AuditFilter, offset, limit int) ([]core.PermissionChange, int, error) {
+	where, args := buildFilter(filter)
+
+	var total int
+	countSQL := `SELECT COUNT(*) FROM casbin_audit` + where
+	if err := m.dbConn.QueryRowCtx(ctx, &total, countSQL, args...); err != nil {
+		return nil, 0, fmt.Errorf("统计审计日志失败: %v", err)
+	}
+	if total == 0 || offset >= total {
+		return []core.PermissionChange{}, total, nil
+	}
+
+	var records []auditRecord
+	selectSQL := fmt.Sprintf(`
+		SELECT id, user_key, action, target, tenant_key, operator_key, reason, created_at
+		FROM casbin_audit%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("查询审计日志失败: %v", err)
+	}
+
+	changes := make([]core.PermissionChange, 0, len(records))
+	for _, record := range records {
+		change, err := m.toChange(record)
+		if err != nil {
+			return nil, 0, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, total, nil
+}
+
+// StreamEvents 按时间顺序逐条回调符合条件的变更记录
+// 按记录 ID 分批查询，每批 batchSize 条，回调处理完当前批次后才查询下一批；fn 返回错误时停止并返回该错误
+func (m *auditManager) StreamEvents(ctx context.Context, filter core.AuditFilter, batchSize int, fn func(core.PermissionChange) error) error {
+	if err := core.FirstError(
+		core.CheckArg(batchSize > 0, "batchSize", core.ConstraintRange),
+		core.CheckArg(fn != nil, "fn", core.ConstraintRequired),
+	); err != nil {
+		return err
+	}
+
+	where, args := buildFilter(filter)
+	if where == "" {
+		where = " WHERE"
+	} else {
+		where += " AND"
+	}
+
+	var lastID int64
+	for {
+		var records []auditRecord
+		selectSQL := fmt.Sprintf(`
+			SELECT id, user_key, action, target, tenant_key, operator_key, reason, created_at
+			FROM casbin_audit%s id > $%d
+			ORDER BY id
+			LIMIT $%d
+		`, where, len(args)+1, len(args)+2)
+		if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, append(args, lastID, batchSize)...); err != nil {
+			return fmt.Errorf("查询审计日志失败: %v", err)
+		}
+
+		for _, record := range records {
+			change, err := m.toChange(record)
+			if err != nil {
+				return err
+			}
+			if err := fn(change); err != nil {
+				return err
+			}
+			lastID = record.ID
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+	}
+}
+
+// toChange 转换审计记录，解密变更原因
+func (m *auditManager) toChange(record auditRecord) (core.PermissionChange, error) {
+	reason, err := m.cipher.Decrypt(record.Reason)
+	if err != nil {
+		return core.PermissionChange{}, err
+	}
+	return core.PermissionChange{
+		ID:          strconv.FormatInt(record.ID, 10),
+		UserKey:     record.UserKey,
+		Action:      core.Action(record.Action),
+		Target:      record.Target,
+		TenantKey:   record.TenantKey,
+		OperatorKey: record.OperatorKey,
+		Timestamp:   record.CreatedAt,
+		Reason:      reason,
+	}, nil
+}
+
+// buildFilter 根据查询条件生成 WHERE 子句和参数
+func buildFilter(filter core.AuditFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(column string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s $%d", column, len(args)))
+	}
+
+	if filter.UserKey != "" {
+		add("user_key =", filter.UserKey)
+	}
+	if filter.TenantKey != "" {
+		add("tenant_key =", filter.TenantKey)
+	}
+	if filter.OperatorKey != "" {
+		add("operator_key =", filter.OperatorKey)
+	}
+	if filter.Action != "" {
+		add("action =", string(filter.Action))
+	}
+	if filter.Target != "" {
+		add("target =", filter.Target)
+	}
+	if !filter.Since.IsZero() {
+		add("created_at >=", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		add("created_at <", filter.Until)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// EraseUser 删除用户作为被授权人或操作者的变更记录
+// retainSince 为零值时删除全部记录；否则保留 retainSince 之后的记录，pseudonym 非空时将其中的用户标识替换为假名
+func (m *auditManager) EraseUser(ctx context.Context, userKey string, retainSince time.Time, pseudonym string) (int, int, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return 0, 0, err
+	}
+
+	deleteSQL := `DELETE FROM casbin_audit WHERE (user_key = $1 OR operator_key = $1)`
+	args := []any{userKey}
+	if !retainSince.IsZero() {
+		deleteSQL += ` AND created_at < $2`
+		args = append(args, retainSince)
+	}
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("删除审计日志失败: %v", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("删除审计日志失败: %v", err)
+	}
+	if retainSince.IsZero() {
+		return int(deleted), 0, nil
+	}
+
+	var retained int64
+	if pseudonym != "" {
+		updateSQL := `
+			UPDATE casbin_audit SET
+				user_key = CASE WHEN user_key = $1 THEN $2 ELSE user_key END,
+				operator_key = CASE WHEN operator_key = $1 THEN $2 ELSE operator_key END
+			WHERE user_key = $1 OR operator_key = $1
+		`
+		result, err := m.dbConn.ExecCtx(ctx, updateSQL, userKey, pseudonym)
+		if err != nil {
+			return 0, 0, fmt.Errorf("替换审计日志用户标识失败: %v", err)
+		}
+		if retained, err = result.RowsAffected(); err != nil {
+			return 0, 0, fmt.Errorf("替换审计日志用户标识失败: %v", err)
+		}
+	} else {
+		countSQL := `SELECT COUNT(*) FROM casbin_audit WHERE user_key = $1 OR operator_key = $1`
+		if err := m.dbConn.QueryRowCtx(ctx, &retained, countSQL, userKey); err != nil {
+			return 0, 0, fmt.Errorf("统计审计日志失败: %v", err)
+		}
+	}
+
+	return int(deleted), int(retained), nil
+}
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// auditRecord 审计日志记录
+type auditRecord struct {
+	ID          int64     `db:"id"`
+	UserKey     string    `db:"user_key"`
+	Action      string    `db:"action"`
+	Target      string    `db:"target"`
+	TenantKey   string    `db:"tenant_key"`
+	OperatorKey string    `db:"operator_key"`
+	Reason      string    `db:"reason"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// initDB 初始化数据库，创建审计日志表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_audit (
+    id BIGSERIAL PRIMARY KEY,
+    user_key VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(64) NOT NULL,
+    target VARCHAR(512) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL DEFAULT '',
+    operator_key VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_casbin_audit_tenant ON casbin_audit(tenant_key, created_at);
+CREATE INDEX IF NOT EXISTS idx_casbin_audit_user ON casbin_audit(user_key, created_at);
+CREATE INDEX IF NOT EXISTS idx_casbin_audit_operator ON casbin_audit(operator_key, created_at);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_audit表失败: %v", err)
+	}
+
+	return nil
+}
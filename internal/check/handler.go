@@ -1,6 +1,8 @@
 package check
 
 import (
+	"context"
+
 	"github.com/rezeropoint/casbinx/core"
 )
 
@@ -17,19 +19,34 @@ func newCheckManager(enforcer *core.Enforcer) Manager {
 }
 
 // CheckPermission 权限检查 (包括直接权限和通过角色继承的权限)
-func (m *checkManager) CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error) {
+func (m *checkManager) CheckPermission(ctx context.Context, userKey, tenantKey string, permission core.Permission) (bool, error) {
 	// 使用 Casbin 的 Enforce 方法，它会自动检查用户的直接权限和角色继承权限
-	return m.enforcer.CheckPermission(userKey, tenantKey, permission)
+	return m.enforcer.CheckPermission(ctx, userKey, tenantKey, permission)
 }
 
 // HasDirectPermission 检查用户是否有直接权限 (不包括角色权限)
-func (m *checkManager) HasDirectPermission(userKey, tenantKey string, permission core.Permission) (bool, error) {
+func (m *checkManager) HasDirectPermission(ctx context.Context, userKey, tenantKey string, permission core.Permission) (bool, error) {
 	// 只检查用户的直接权限，不包括通过角色继承的权限
 	return m.enforcer.HasDirectPermission(userKey, tenantKey, permission)
 }
 
+// CheckPermissionDebug 检查用户权限并返回有序的评估步骤
+func (m *checkManager) CheckPermissionDebug(ctx context.Context, userKey, tenantKey string, permission core.Permission) (*core.CheckTrace, error) {
+	return m.enforcer.TraceCheckPermission(userKey, tenantKey, permission)
+}
+
+// ExplainPermission 检查用户权限并返回产生授权的链路
+func (m *checkManager) ExplainPermission(ctx context.Context, userKey, tenantKey string, permission core.Permission) (*core.PermissionExplanation, error) {
+	return m.enforcer.ExplainPermission(userKey, tenantKey, permission)
+}
+
+// EnforceWithAttributes 检查用户权限，附加了条件的规则按请求属性评估
+func (m *checkManager) EnforceWithAttributes(ctx context.Context, userKey, tenantKey string, permission core.Permission, attrs map[string]any) (bool, error) {
+	return m.enforcer.CheckPermissionWithAttributes(ctx, userKey, tenantKey, permission, attrs)
+}
+
 // HasRole 检查用户是否有角色
-func (m *checkManager) HasRole(userKey, roleKey, tenantKey string) (bool, error) {
+func (m *checkManager) HasRole(ctx context.Context, userKey, roleKey, tenantKey string) (bool, error) {
 	// 检查用户在指定租户下是否有指定角色
 	roles, err := m.enforcer.GetRolesForUser(userKey, tenantKey)
 	if err != nil {
@@ -45,11 +62,11 @@ func (m *checkManager) HasRole(userKey, roleKey, tenantKey string) (bool, error)
 }
 
 // CheckMultiplePermissions 批量权限检查
-func (m *checkManager) CheckMultiplePermissions(userKey, tenantKey string, permissions []core.Permission) ([]bool, error) {
+func (m *checkManager) CheckMultiplePermissions(ctx context.Context, userKey, tenantKey string, permissions []core.Permission) ([]bool, error) {
 	results := make([]bool, len(permissions))
 
 	for i, permission := range permissions {
-		hasPermission, err := m.CheckPermission(userKey, tenantKey, permission)
+		hasPermission, err := m.CheckPermission(ctx, userKey, tenantKey, permission)
 		if err != nil {
 			return nil, err
 		}
@@ -60,9 +77,9 @@ func (m *checkManager) CheckMultiplePermissions(userKey, tenantKey string, permi
 }
 
 // HasAnyPermission 检查是否有任意一个权限
-func (m *checkManager) HasAnyPermission(userKey, tenantKey string, permissions []core.Permission) (bool, error) {
+func (m *checkManager) HasAnyPermission(ctx context.Context, userKey, tenantKey string, permissions []core.Permission) (bool, error) {
 	for _, permission := range permissions {
-		hasPermission, err := m.CheckPermission(userKey, tenantKey, permission)
+		hasPermission, err := m.CheckPermission(ctx, userKey, tenantKey, permission)
 		if err != nil {
 			return false, err
 		}
@@ -74,9 +91,9 @@ func (m *checkManager) HasAnyPermission(userKey, tenantKey string, permissions [
 }
 
 // HasAllPermissions 检查是否有所有权限
-func (m *checkManager) HasAllPermissions(userKey, tenantKey string, permissions []core.Permission) (bool, error) {
+func (m *checkManager) HasAllPermissions(ctx context.Context, userKey, tenantKey string, permissions []core.Permission) (bool, error) {
 	for _, permission := range permissions {
-		hasPermission, err := m.CheckPermission(userKey, tenantKey, permission)
+		hasPermission, err := m.CheckPermission(ctx, userKey, tenantKey, permission)
 		if err != nil {
 			return false, err
 		}
@@ -88,10 +105,10 @@ func (m *checkManager) HasAllPermissions(userKey, tenantKey string, permissions
 }
 
 // CanAccessResource 检查是否可以访问资源 (任意操作)
-func (m *checkManager) CanAccessResource(userKey, tenantKey string, resource core.Resource) (bool, error) {
+func (m *checkManager) CanAccessResource(ctx context.Context, userKey, tenantKey string, resource core.Resource) (bool, error) {
 	// 使用基础操作列表，检查用户是否对资源有任意一种操作权限
 	for _, action := range core.AllActions {
-		hasPermission, err := m.CheckPermission(userKey, tenantKey, core.Permission{Resource: resource, Action: action})
+		hasPermission, err := m.CheckPermission(ctx, userKey, tenantKey, core.Permission{Resource: resource, Action: action})
 		if err != nil {
 			return false, err
 		}
@@ -103,13 +120,13 @@ func (m *checkManager) CanAccessResource(userKey, tenantKey string, resource cor
 }
 
 // GetAvailableActions 获取用户可执行的操作
-func (m *checkManager) GetAvailableActions(userKey, tenantKey string, resource core.Resource) ([]core.Action, error) {
+func (m *checkManager) GetAvailableActions(ctx context.Context, userKey, tenantKey string, resource core.Resource) ([]core.Action, error) {
 	// 获取资源的可用操作列表
 	resourceActions := core.GetResourceActions(resource)
 	availableActions := make([]core.Action, 0)
 
 	for _, action := range resourceActions {
-		hasPermission, err := m.CheckPermission(userKey, tenantKey, core.Permission{Resource: resource, Action: action})
+		hasPermission, err := m.CheckPermission(ctx, userKey, tenantKey, core.Permission{Resource: resource, Action: action})
 		if err != nil {
 			return nil, err
 		}
@@ -122,9 +139,9 @@ func (m *checkManager) GetAvailableActions(userKey, tenantKey string, resource c
 }
 
 // CanAccessTenant 检查是否可以访问租户
-func (m *checkManager) CanAccessTenant(userKey, tenantKey string) (bool, error) {
+func (m *checkManager) CanAccessTenant(ctx context.Context, userKey, tenantKey string) (bool, error) {
 	// 1. 检查用户是否有全局租户管理权限
-	hasTenantReadPermission, err := m.CheckPermission(userKey, "*", core.Permission{
+	hasTenantReadPermission, err := m.CheckPermission(ctx, userKey, "*", core.Permission{
 		Resource: core.ResourceTenant,
 		Action:   core.ActionRead,
 	})
@@ -158,7 +175,7 @@ func (m *checkManager) CanAccessTenant(userKey, tenantKey string) (bool, error)
 
 	for _, resource := range coreResources {
 		for _, action := range core.AllActions {
-			hasPermission, err := m.CheckPermission(userKey, tenantKey, core.Permission{
+			hasPermission, err := m.CheckPermission(ctx, userKey, tenantKey, core.Permission{
 				Resource: resource,
 				Action:   action,
 			})
@@ -175,9 +192,9 @@ func (m *checkManager) CanAccessTenant(userKey, tenantKey string) (bool, error)
 }
 
 // GetUserTenants 获取用户可访问的租户
-func (m *checkManager) GetUserTenants(userKey string) ([]string, error) {
+func (m *checkManager) GetUserTenants(ctx context.Context, userKey string) ([]string, error) {
 	// 获取用户的所有租户权限
-	policies, err := m.enforcer.GetPolicies(userKey, "")
+	policies, err := m.enforcer.GetPolicies(m.enforcer.UserSubject(userKey), "")
 	if err != nil {
 		return nil, err
 	}
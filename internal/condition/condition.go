@@ -0,0 +1,23 @@
+package condition
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 规则条件管理器接口
+type Manager interface {
+	SetPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission, expression string) error // 设置规则条件表达式（只在 EnforceWithAttributes 中成立时生效）
+	ClearPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) error                  // 清除规则条件，规则恢复为无条件生效
+	GetPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) (string, bool)           // 获取规则附加的条件表达式，未设置时返回 false
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error                                                                           // 将域的所有规则条件迁移到新域
+	DeleteDomain(ctx context.Context, domain string) error                                                                                         // 删除域的所有规则条件
+}
+
+// NewManager 创建规则条件管理器
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newConditionManager(dbConn, enforcer)
+}
@@ -0,0 +1,254 @@
+package condition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// conditionManager 规则条件管理器实现
+type conditionManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu         sync.RWMutex
+	conditions map[string]string // 策略键 -> 条件表达式
+}
+
+// newConditionManager 创建规则条件管理器实现
+func newConditionManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*conditionManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("规则条件管理器初始化失败: %v", err)
+	}
+
+	manager := &conditionManager{
+		enforcer:   enforcer,
+		dbConn:     dbConn,
+		conditions: make(map[string]string),
+	}
+
+	if err := manager.loadConditions(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新条件，并为权限检查提供规则条件
+	enforcer.AddReloadHook(func() error { return manager.loadConditions(context.Background()) })
+	enforcer.SetRuleConditionResolver(manager.resolveCondition)
+	enforcer.AddObjectCleanupHook(manager.cleanupObject)
+
+	return manager, nil
+}
+
+// SetPolicyCondition 设置规则条件表达式，写入前校验表达式语法
+func (m *conditionManager) SetPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission, expression string) error {
+	if err := core.ValidateCondition(expression); err != nil {
+		return &core.FieldError{Field: "expression", Constraint: core.ConstraintFormat, Detail: err.Error()}
+	}
+
+	subject, err := m.ruleSubject(kind, subjectKey, domain, permission)
+	if err != nil {
+		return err
+	}
+
+	upsertSQL := `
+		INSERT INTO casbin_policy_conditions (subject, domain, resource, action, expression)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subject, domain, resource, action) DO UPDATE
+		SET expression = EXCLUDED.expression, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, upsertSQL, subject, domain, string(permission.QualifiedResource()), string(permission.Action), expression); err != nil {
+		return fmt.Errorf("设置规则条件失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// ClearPolicyCondition 清除规则条件
+func (m *conditionManager) ClearPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) error {
+	subject := m.storedSubject(kind, subjectKey)
+
+	// 未设置条件的规则无需清理，避免无意义的同步通知
+	m.mu.RLock()
+	_, exists := m.conditions[policyKey(subject, domain, string(permission.QualifiedResource()), string(permission.Action))]
+	m.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	deleteSQL := `DELETE FROM casbin_policy_conditions WHERE subject = $1 AND domain = $2 AND resource = $3 AND action = $4`
+	if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, subject, domain, string(permission.QualifiedResource()), string(permission.Action)); err != nil {
+		return fmt.Errorf("清除规则条件失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// GetPolicyCondition 获取规则附加的条件表达式，未设置时返回 false
+func (m *conditionManager) GetPolicyCondition(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) (string, bool) {
+	subject := m.storedSubject(kind, subjectKey)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	expression, ok := m.conditions[policyKey(subject, domain, string(permission.QualifiedResource()), string(permission.Action))]
+	return expression, ok
+}
+
+// RenameDomain 将旧域下的所有规则条件迁移到新域，新域已有的规则条件保留
+func (m *conditionManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	var affected int64
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteSQL := `
+			DELETE FROM casbin_policy_conditions o
+			WHERE o.domain = $1
+			  AND EXISTS (
+			    SELECT 1 FROM casbin_policy_conditions n
+			    WHERE n.domain = $2 AND n.subject = o.subject AND n.resource = o.resource AND n.action = o.action
+			  )
+		`
+		if _, err := session.ExecCtx(ctx, deleteSQL, oldDomain, newDomain); err != nil {
+			return fmt.Errorf("清理重复的规则条件失败: %v", err)
+		}
+
+		updateSQL := `UPDATE casbin_policy_conditions SET domain = $2, updated_at = CURRENT_TIMESTAMP WHERE domain = $1`
+		result, err := session.ExecCtx(ctx, updateSQL, oldDomain, newDomain)
+		if err != nil {
+			return fmt.Errorf("迁移规则条件失败: %v", err)
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 没有需要迁移的记录时无需同步
+	if affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// DeleteDomain 删除域下的所有规则条件
+func (m *conditionManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	deleteSQL := `DELETE FROM casbin_policy_conditions WHERE domain = $1`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, domain)
+	if err != nil {
+		return fmt.Errorf("删除域规则条件失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// cleanupObject 对象清理回调：删除对象（含子资源）的规则条件
+func (m *conditionManager) cleanupObject(ctx context.Context, resourceType, objectID string, removed []core.Policy) error {
+	objectResource := string(core.ObjectResource(resourceType, objectID))
+	deleteSQL := `DELETE FROM casbin_policy_conditions WHERE resource = $1 OR starts_with(resource, $1 || ':')`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, objectResource)
+	if err != nil {
+		return fmt.Errorf("清理对象规则条件失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// ruleSubject 校验规则存在并返回存储中的主体标识
+func (m *conditionManager) ruleSubject(kind core.SubjectKind, subjectKey, domain string, permission core.Permission) (string, error) {
+	if err := core.FirstError(
+		core.RequireArg("subjectKey", subjectKey),
+		core.RequireArg("permission.resource", permission.Resource),
+		core.RequireArg("permission.action", permission.Action),
+	); err != nil {
+		return "", err
+	}
+
+	subject := m.storedSubject(kind, subjectKey)
+	exists, err := m.enforcer.HasPolicy(subject, domain, permission)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", core.ErrPermissionNotFound
+	}
+	return subject, nil
+}
+
+// storedSubject 生成存储中的主体标识
+func (m *conditionManager) storedSubject(kind core.SubjectKind, subjectKey string) string {
+	if kind == core.SubjectKindRole {
+		return m.enforcer.RoleSubject(subjectKey)
+	}
+	return m.enforcer.UserSubject(subjectKey)
+}
+
+// resolveCondition 规则条件解析器：返回规则附加的条件表达式
+func (m *conditionManager) resolveCondition(rule core.Policy) (string, bool) {
+	subject := rule.Subject
+	if rule.SubjectKind != "" {
+		subject = core.EncodeSubject(rule.SubjectKind, rule.Subject)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	expression, ok := m.conditions[policyKey(subject, rule.Domain, string(rule.Resource), string(rule.Action))]
+	return expression, ok
+}
+
+// loadConditions 从数据库加载规则条件到内存
+func (m *conditionManager) loadConditions(ctx context.Context) error {
+	var records []*policyConditionRecord
+	selectSQL := `SELECT subject, domain, resource, action, expression FROM casbin_policy_conditions`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载规则条件失败: %v", err)
+	}
+
+	conditions := make(map[string]string, len(records))
+	for _, record := range records {
+		conditions[policyKey(record.Subject, record.Domain, record.Resource, record.Action)] = record.Expression
+	}
+
+	m.mu.Lock()
+	m.conditions = conditions
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地条件并通知其他实例
+func (m *conditionManager) sync(ctx context.Context) error {
+	if err := m.loadConditions(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// policyKey 生成策略键
+func policyKey(subject, domain, resource, action string) string {
+	return strings.Join([]string{subject, domain, resource, action}, "\x00")
+}
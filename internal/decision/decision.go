@@ -0,0 +1,26 @@
+package decision
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 权限检查决策日志管理器接口
+type Manager interface {
+	Record(decision core.Decision)   // 提交决策记录（非阻塞，缓冲区满时按背压策略丢弃）
+	Flush(ctx context.Context) error // 立即将缓冲区中的记录写入数据库
+	Stats() core.DecisionLogStats    // 获取缓冲统计（队列深度、丢弃数量等）
+	Close() error                    // 停止后台刷新并写入剩余记录
+
+	// 用户数据（导出与删除）
+	ListUserDecisions(ctx context.Context, userKey string) ([]core.Decision, error) // 获取用户的决策记录（先写入缓冲区中的记录）
+	EraseUser(ctx context.Context, userKey string) (int, error)                     // 删除用户的决策记录（先写入缓冲区中的记录），返回删除的条数
+}
+
+// NewManager 创建决策日志管理器，并注册为执行器的决策观察者
+func NewManager(dbConn sqlx.SqlConn, config core.DecisionLogConfig, enforcer *core.Enforcer) (Manager, error) {
+	return newDecisionManager(dbConn, config, enforcer)
+}
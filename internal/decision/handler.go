@@ -0,0 +1,269 @@
+package decision
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// decisionManager 决策日志管理器实现
+// 决策先写入固定容量的环形缓冲区，由后台协程按批次写入数据库，权限检查路径上不产生数据库写入
+type decisionManager struct {
+	dbConn sqlx.SqlConn
+	config core.DecisionLogConfig
+
+	mu     sync.Mutex
+	buffer []core.Decision // 环形缓冲区
+	head   int             // 最旧记录的位置
+	size   int             // 缓冲中的记录数
+	stats  core.DecisionLogStats
+
+	flushMu sync.Mutex    // 保证同一时间只有一个批次在写入
+	wake    chan struct{} // 缓冲达到批次大小时唤醒后台刷新
+	done    chan struct{}
+	stopped sync.WaitGroup
+	closed  bool
+}
+
+// newDecisionManager 创建决策日志管理器实现
+func newDecisionManager(dbConn sqlx.SqlConn, config core.DecisionLogConfig, enforcer *core.Enforcer) (*decisionManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("决策日志管理器初始化失败: %v", err)
+	}
+
+	config = applyDefaults(config)
+	manager := &decisionManager{
+		dbConn: dbConn,
+		config: config,
+		buffer: make([]core.Decision, config.BufferSize),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	manager.stopped.Add(1)
+	go manager.run()
+
+	enforcer.AddDecisionObserver(manager.Record)
+
+	return manager, nil
+}
+
+// applyDefaults 填充配置默认值
+func applyDefaults(config core.DecisionLogConfig) core.DecisionLogConfig {
+	if config.BufferSize <= 0 {
+		config.BufferSize = core.DefaultDecisionBufferSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = core.DefaultDecisionBatchSize
+	}
+	if config.BatchSize > config.BufferSize {
+		config.BatchSize = config.BufferSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = core.DefaultDecisionFlushInterval
+	}
+	if config.Backpressure == "" {
+		config.Backpressure = core.DecisionDropOldest
+	}
+	return config
+}
+
+// Record 提交决策记录
+func (m *decisionManager) Record(decision core.Decision) {
+	m.mu.Lock()
+	m.stats.Recorded++
+	m.push(decision)
+	full := m.size >= m.config.BatchSize
+	m.mu.Unlock()
+
+	if full {
+		select {
+		case m.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// push 将记录放入缓冲区，缓冲区满时按背压策略丢弃（调用方持有锁）
+func (m *decisionManager) push(decision core.Decision) {
+	capacity := len(m.buffer)
+	if m.size == capacity {
+		m.stats.Dropped++
+		if m.config.Backpressure == core.DecisionDropNewest {
+			return
+		}
+		// 覆盖最旧的记录
+		m.buffer[m.head] = decision
+		m.head = (m.head + 1) % capacity
+		return
+	}
+
+	m.buffer[(m.head+m.size)%capacity] = decision
+	m.size++
+}
+
+// take 取出最多 n 条最旧的记录（调用方持有锁）
+func (m *decisionManager) take(n int) []core.Decision {
+	if n > m.size {
+		n = m.size
+	}
+
+	capacity := len(m.buffer)
+	batch := make([]core.Decision, n)
+	for i := 0; i < n; i++ {
+		batch[i] = m.buffer[(m.head+i)%capacity]
+	}
+	m.head = (m.head + n) % capacity
+	m.size -= n
+	return batch
+}
+
+// requeue 将写入失败的批次放回缓冲区头部，超出容量的部分计入丢弃（调用方持有锁）
+func (m *decisionManager) requeue(batch []core.Decision) {
+	capacity := len(m.buffer)
+	free := capacity - m.size
+	if len(batch) > free {
+		m.stats.Dropped += int64(len(batch) - free)
+		batch = batch[len(batch)-free:]
+	}
+
+	for i := len(batch) - 1; i >= 0; i-- {
+		m.head = (m.head - 1 + capacity) % capacity
+		m.buffer[m.head] = batch[i]
+		m.size++
+	}
+}
+
+// Flush 立即将缓冲区中的记录写入数据库
+func (m *decisionManager) Flush(ctx context.Context) error {
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+
+	for {
+		m.mu.Lock()
+		batch := m.take(m.config.BatchSize)
+		m.mu.Unlock()
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := insertDecisions(ctx, m.dbConn, batch); err != nil {
+			m.mu.Lock()
+			m.requeue(batch)
+			m.stats.FlushErrors++
+			m.stats.LastError = err.Error()
+			m.mu.Unlock()
+			return err
+		}
+
+		m.mu.Lock()
+		m.stats.Written += int64(len(batch))
+		m.stats.LastFlushAt = time.Now()
+		m.stats.LastFlushSize = len(batch)
+		m.stats.LastError = ""
+		m.mu.Unlock()
+	}
+}
+
+// Stats 获取缓冲统计
+func (m *decisionManager) Stats() core.DecisionLogStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Pending = m.size
+	return stats
+}
+
+// Close 停止后台刷新并写入剩余记录
+func (m *decisionManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	close(m.done)
+	m.stopped.Wait()
+	return m.Flush(context.Background())
+}
+
+// run 后台刷新循环：定时刷新，或缓冲达到批次大小时立即刷新
+func (m *decisionManager) run() {
+	defer m.stopped.Done()
+
+	ticker := time.NewTicker(m.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+		// 写入失败已记录在统计中，失败批次留在缓冲区等待下一次刷新
+		_ = m.Flush(context.Background())
+	}
+}
+
+// ListUserDecisions 获取用户的决策记录（按检查时间顺序），查询前先写入缓冲区中的记录
+func (m *decisionManager) ListUserDecisions(ctx context.Context, userKey string) ([]core.Decision, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
+	}
+	if err := m.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	var records []decisionRecord
+	selectSQL := `
+		SELECT user_key, tenant_key, resource, action, allowed, checked_at
+		FROM system_decision_logs
+		WHERE user_key = $1
+		ORDER BY checked_at, id
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, userKey); err != nil {
+		return nil, fmt.Errorf("查询决策日志失败: %v", err)
+	}
+
+	decisions := make([]core.Decision, 0, len(records))
+	for _, record := range records {
+		decisions = append(decisions, core.Decision{
+			UserKey:    record.UserKey,
+			TenantKey:  record.TenantKey,
+			Permission: core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.Action)},
+			Allowed:    record.Allowed,
+			CheckedAt:  record.CheckedAt,
+		})
+	}
+	return decisions, nil
+}
+
+// EraseUser 删除用户的决策记录，删除前先写入缓冲区中的记录，避免缓冲中的记录在删除后写入
+func (m *decisionManager) EraseUser(ctx context.Context, userKey string) (int, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return 0, err
+	}
+	if err := m.Flush(ctx); err != nil {
+		return 0, err
+	}
+
+	result, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_decision_logs WHERE user_key = $1`, userKey)
+	if err != nil {
+		return 0, fmt.Errorf("删除决策日志失败: %v", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("删除决策日志失败: %v", err)
+	}
+	return int(deleted), nil
+}
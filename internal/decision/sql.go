@@ -0,0 +1,72 @@
+package decision
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// decisionColumns 决策日志写入列数
+const decisionColumns = 6
+
+// decisionRecord 决策日志记录
+type decisionRecord struct {
+	UserKey   string    `db:"user_key"`
+	TenantKey string    `db:"tenant_key"`
+	Resource  string    `db:"resource"`
+	Action    string    `db:"action"`
+	Allowed   bool      `db:"allowed"`
+	CheckedAt time.Time `db:"checked_at"`
+}
+
+// initDB 初始化数据库，创建决策日志表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_decision_logs (
+    id BIGSERIAL PRIMARY KEY,
+    user_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_decision_logs_user ON system_decision_logs(user_key, checked_at);
+CREATE INDEX IF NOT EXISTS idx_system_decision_logs_tenant ON system_decision_logs(tenant_key, checked_at);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_decision_logs表失败: %v", err)
+	}
+
+	return nil
+}
+
+// insertDecisions 以单条多行 INSERT 批量写入决策记录
+func insertDecisions(ctx context.Context, dbConn sqlx.SqlConn, decisions []core.Decision) error {
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(decisions))
+	args := make([]any, 0, len(decisions)*decisionColumns)
+	for i, decision := range decisions {
+		base := i * decisionColumns
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", base+1, base+2, base+3, base+4, base+5, base+6))
+		args = append(args, decision.UserKey, decision.TenantKey, string(decision.Permission.Resource), string(decision.Permission.Action), decision.Allowed, decision.CheckedAt)
+	}
+
+	insertSQL := `INSERT INTO system_decision_logs (user_key, tenant_key, resource, action, allowed, checked_at) VALUES ` + strings.Join(placeholders, ", ")
+	if _, err := dbConn.ExecCtx(ctx, insertSQL, args...); err != nil {
+		return fmt.Errorf("写入决策日志失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package deny
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 拒绝规则管理器接口
+// 拒绝规则独立于 Casbin 策略存储，权限检查时通过拒绝规则解析器优先于授权生效
+type Manager interface {
+	// 拒绝规则
+	AddDeny(ctx context.Context, rule core.DenyRule) error                                                                         // 添加拒绝规则，已存在时更新操作者和原因
+	RemoveDeny(ctx context.Context, kind core.SubjectKind, subjectKey, tenantKey string, permission core.Permission) (bool, error) // 移除拒绝规则，规则不存在时返回 false
+	HasDeny(kind core.SubjectKind, subjectKey, tenantKey string, permission core.Permission) bool                                  // 检查拒绝规则是否存在
+	ListDenies(ctx context.Context, filter core.DenyRuleFilter) ([]*core.DenyRule, error)                                          // 按条件查询拒绝规则
+
+	// 用户、角色和租户变更
+	RenameUser(ctx context.Context, oldKey, newKey string) error         // 将用户的拒绝规则迁移到新标识
+	DeleteUser(ctx context.Context, userKey string) error                // 删除用户的拒绝规则
+	DeleteRole(ctx context.Context, roleKey string) error                // 删除角色的拒绝规则
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error // 将租户的拒绝规则迁移到新租户，新租户已有的规则保留
+	DeleteDomain(ctx context.Context, domain string) error               // 删除租户的拒绝规则
+}
+
+// NewManager 创建拒绝规则管理器
+// 拒绝规则加载到内存，权限检查时通过 core.DenyResolver 读取
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newDenyManager(dbConn, enforcer)
+}
@@ -0,0 +1,303 @@
+package deny
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// denyManager 拒绝规则管理器实现
+type denyManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu    sync.RWMutex
+	rules map[string][]core.DenyRule // 主体键（类型、主体、租户） -> 拒绝规则
+}
+
+// newDenyManager 创建拒绝规则管理器实现
+func newDenyManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*denyManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("拒绝规则管理器初始化失败: %v", err)
+	}
+
+	manager := &denyManager{
+		enforcer: enforcer,
+		dbConn:   dbConn,
+		rules:    make(map[string][]core.DenyRule),
+	}
+
+	if err := manager.loadRules(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新拒绝规则（其他实例的变更通过 Watcher 触发重新加载）
+	enforcer.AddReloadHook(func() error { return manager.loadRules(context.Background()) })
+	enforcer.SetDenyResolver(manager.resolveDenies)
+
+	return manager, nil
+}
+
+// AddDeny 添加拒绝规则，已存在时更新操作者和原因
+func (m *denyManager) AddDeny(ctx context.Context, rule core.DenyRule) error {
+	if err := core.FirstError(
+		core.CheckArg(rule.SubjectKind == core.SubjectKindUser || rule.SubjectKind == core.SubjectKindRole, "subjectKind", core.ConstraintOneOf),
+		core.RequireArg("subjectKey", rule.SubjectKey),
+		core.RequireArg("tenantKey", rule.TenantKey),
+		core.CheckArg(rule.Permission.IsValid(), "permission", core.ConstraintRequired),
+	); err != nil {
+		return err
+	}
+
+	upsertSQL := `
+		INSERT INTO casbin_deny_rules (subject_kind, subject_key, tenant_key, resource, action, operator_key, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (subject_kind, subject_key, tenant_key, resource, action) DO UPDATE
+		SET operator_key = EXCLUDED.operator_key, reason = EXCLUDED.reason
+	`
+	_, err := m.dbConn.ExecCtx(ctx, upsertSQL, string(rule.SubjectKind), rule.SubjectKey, rule.TenantKey,
+		string(rule.Permission.QualifiedResource()), string(rule.Permission.Action), rule.OperatorKey, rule.Reason)
+	if err != nil {
+		return fmt.Errorf("添加拒绝规则失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// RemoveDeny 移除拒绝规则，规则不存在时返回 false
+func (m *denyManager) RemoveDeny(ctx context.Context, kind core.SubjectKind, subjectKey, tenantKey string, permission core.Permission) (bool, error) {
+	deleteSQL := `
+		DELETE FROM casbin_deny_rules
+		WHERE subject_kind = $1 AND subject_key = $2 AND tenant_key = $3 AND resource = $4 AND action = $5
+	`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, string(kind), subjectKey, tenantKey, string(permission.QualifiedResource()), string(permission.Action))
+	if err != nil {
+		return false, fmt.Errorf("移除拒绝规则失败: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("移除拒绝规则失败: %v", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	return true, m.sync(ctx)
+}
+
+// HasDeny 检查拒绝规则是否存在（精确匹配主体、租户和权限）
+func (m *denyManager) HasDeny(kind core.SubjectKind, subjectKey, tenantKey string, permission core.Permission) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, rule := range m.rules[subjectIndexKey(kind, subjectKey, tenantKey)] {
+		if rule.Permission.Equal(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListDenies 按条件查询拒绝规则
+func (m *denyManager) ListDenies(ctx context.Context, filter core.DenyRuleFilter) ([]*core.DenyRule, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TenantKey != "" {
+		add("tenant_key = $%d", filter.TenantKey)
+	}
+	if filter.SubjectKind != "" {
+		add("subject_kind = $%d", string(filter.SubjectKind))
+	}
+	if filter.SubjectKey != "" {
+		add("subject_key = $%d", filter.SubjectKey)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var records []denyRuleRecord
+	selectSQL := `SELECT ` + denyRuleColumns + ` FROM casbin_deny_rules` + where + ` ORDER BY tenant_key, subject_kind, subject_key, resource, action`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, args...); err != nil {
+		return nil, fmt.Errorf("查询拒绝规则失败: %v", err)
+	}
+
+	rules := make([]*core.DenyRule, 0, len(records))
+	for _, record := range records {
+		rule := toRule(record)
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+// RenameUser 将用户的拒绝规则迁移到新标识，新标识已有的规则保留
+func (m *denyManager) RenameUser(ctx context.Context, oldKey, newKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldKey", oldKey),
+		core.RequireArg("newKey", newKey),
+	); err != nil {
+		return err
+	}
+
+	statements := []string{
+		`DELETE FROM casbin_deny_rules o
+		 WHERE o.subject_kind = 'user' AND o.subject_key = $1
+		   AND EXISTS (
+		     SELECT 1 FROM casbin_deny_rules n
+		     WHERE n.subject_kind = 'user' AND n.subject_key = $2
+		       AND n.tenant_key = o.tenant_key AND n.resource = o.resource AND n.action = o.action
+		   )`,
+		`UPDATE casbin_deny_rules SET subject_key = $2 WHERE subject_kind = 'user' AND subject_key = $1`,
+	}
+	return m.migrate(ctx, statements, oldKey, newKey, "迁移用户拒绝规则失败")
+}
+
+// DeleteUser 删除用户的拒绝规则
+func (m *denyManager) DeleteUser(ctx context.Context, userKey string) error {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return err
+	}
+	return m.delete(ctx, `DELETE FROM casbin_deny_rules WHERE subject_kind = 'user' AND subject_key = $1`, userKey, "删除用户拒绝规则失败")
+}
+
+// DeleteRole 删除角色的拒绝规则
+func (m *denyManager) DeleteRole(ctx context.Context, roleKey string) error {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return err
+	}
+	return m.delete(ctx, `DELETE FROM casbin_deny_rules WHERE subject_kind = 'role' AND subject_key = $1`, roleKey, "删除角色拒绝规则失败")
+}
+
+// RenameDomain 将租户的拒绝规则迁移到新租户，新租户已有的规则保留
+func (m *denyManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	statements := []string{
+		`DELETE FROM casbin_deny_rules o
+		 WHERE o.tenant_key = $1
+		   AND EXISTS (
+		     SELECT 1 FROM casbin_deny_rules n
+		     WHERE n.tenant_key = $2 AND n.subject_kind = o.subject_kind AND n.subject_key = o.subject_key
+		       AND n.resource = o.resource AND n.action = o.action
+		   )`,
+		`UPDATE casbin_deny_rules SET tenant_key = $2 WHERE tenant_key = $1`,
+	}
+	return m.migrate(ctx, statements, oldDomain, newDomain, "迁移租户拒绝规则失败")
+}
+
+// DeleteDomain 删除租户的拒绝规则
+func (m *denyManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+	return m.delete(ctx, `DELETE FROM casbin_deny_rules WHERE tenant_key = $1`, domain, "删除租户拒绝规则失败")
+}
+
+// migrate 在事务中执行迁移语句（参数为旧值和新值）
+func (m *denyManager) migrate(ctx context.Context, statements []string, oldValue, newValue, failure string) error {
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, statement := range statements {
+			if _, err := session.ExecCtx(ctx, statement, oldValue, newValue); err != nil {
+				return fmt.Errorf("%s: %v", failure, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.sync(ctx)
+}
+
+// delete 执行删除语句，没有删除任何规则时不同步
+func (m *denyManager) delete(ctx context.Context, deleteSQL, value, failure string) error {
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, value)
+	if err != nil {
+		return fmt.Errorf("%s: %v", failure, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// resolveDenies 拒绝规则解析器：返回主体在域中以及 * 域的拒绝规则
+func (m *denyManager) resolveDenies(kind core.SubjectKind, subjectKey, domain string) []core.DenyRule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.rules) == 0 {
+		return nil
+	}
+
+	rules := append([]core.DenyRule{}, m.rules[subjectIndexKey(kind, subjectKey, domain)]...)
+	if domain != "*" {
+		rules = append(rules, m.rules[subjectIndexKey(kind, subjectKey, "*")]...)
+	}
+	return rules
+}
+
+// loadRules 从数据库加载拒绝规则到内存
+func (m *denyManager) loadRules(ctx context.Context) error {
+	var records []denyRuleRecord
+	selectSQL := `SELECT ` + denyRuleColumns + ` FROM casbin_deny_rules`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载拒绝规则失败: %v", err)
+	}
+
+	rules := make(map[string][]core.DenyRule, len(records))
+	for _, record := range records {
+		rule := toRule(record)
+		key := subjectIndexKey(rule.SubjectKind, rule.SubjectKey, rule.TenantKey)
+		rules[key] = append(rules[key], rule)
+	}
+
+	m.mu.Lock()
+	m.rules = rules
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地拒绝规则并通知其他实例（同时使权限检查缓存失效）
+func (m *denyManager) sync(ctx context.Context) error {
+	if err := m.loadRules(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// subjectIndexKey 生成主体键
+func subjectIndexKey(kind core.SubjectKind, subjectKey, tenantKey string) string {
+	return strings.Join([]string{string(kind), subjectKey, tenantKey}, "\x00")
+}
+
+// toRule 转换拒绝规则记录
+func toRule(record denyRuleRecord) core.DenyRule {
+	return core.DenyRule{
+		SubjectKind: core.SubjectKind(record.SubjectKind),
+		SubjectKey:  record.SubjectKey,
+		TenantKey:   record.TenantKey,
+		Permission:  core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.Action)},
+		OperatorKey: record.OperatorKey,
+		Reason:      record.Reason,
+		CreatedAt:   record.CreatedAt,
+	}
+}
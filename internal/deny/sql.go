@@ -0,0 +1,50 @@
+package deny
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// denyRuleRecord 拒绝规则记录
+type denyRuleRecord struct {
+	SubjectKind string    `db:"subject_kind"`
+	SubjectKey  string    `db:"subject_key"`
+	TenantKey   string    `db:"tenant_key"`
+	Resource    string    `db:"resource"`
+	Action      string    `db:"action"`
+	OperatorKey string    `db:"operator_key"`
+	Reason      string    `db:"reason"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// denyRuleColumns 查询拒绝规则的列
+const denyRuleColumns = `subject_kind, subject_key, tenant_key, resource, action, operator_key, reason, created_at`
+
+// initDB 初始化数据库，创建拒绝规则表
+// resource 为存储形式的资源（对象级拒绝为 "资源:对象ID"）
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_deny_rules (
+    subject_kind VARCHAR(16) NOT NULL,
+    subject_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    operator_key VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subject_kind, subject_key, tenant_key, resource, action)
+);
+
+CREATE INDEX IF NOT EXISTS idx_casbin_deny_rules_tenant_key ON casbin_deny_rules(tenant_key);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_deny_rules表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/audit"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 权限变更摘要管理器接口
+type Manager interface {
+	Generate(ctx context.Context, tenantKey string, since, until time.Time) ([]core.ChangeDigest, error) // 汇总时间范围内的变更摘要（tenantKey 为空表示所有租户，每个有变更的租户一份）
+	Close() error                                                                                        // 停止定时投递
+}
+
+// NewManager 创建变更摘要管理器
+// config.Enabled 时启动后台协程，在每个周期边界汇总变更并调用 hooks 投递
+func NewManager(dbConn sqlx.SqlConn, config core.DigestConfig, auditManager audit.Manager, hooks []core.DigestHook, logger core.Logger) (Manager, error) {
+	return newDigestManager(dbConn, config, auditManager, hooks, logger)
+}
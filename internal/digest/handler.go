@@ -0,0 +1,171 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/internal/audit"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// auditPageSize 汇总时分页读取审计日志的页大小
+const auditPageSize = 500
+
+// digestManager 变更摘要管理器实现
+type digestManager struct {
+	dbConn sqlx.SqlConn
+	config core.DigestConfig
+	audit  audit.Manager
+	hooks  []core.DigestHook
+	logger core.Logger
+
+	done      chan struct{}
+	stopped   sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// newDigestManager 创建变更摘要管理器实现
+func newDigestManager(dbConn sqlx.SqlConn, config core.DigestConfig, auditManager audit.Manager, hooks []core.DigestHook, logger core.Logger) (*digestManager, error) {
+	if logger == nil {
+		logger = core.DefaultLogger()
+	}
+
+	manager := &digestManager{
+		dbConn: dbConn,
+		config: applyDefaults(config),
+		audit:  auditManager,
+		hooks:  hooks,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+
+	if !config.Enabled {
+		return manager, nil
+	}
+	if len(hooks) == 0 {
+		return nil, fmt.Errorf("Config.Digest 已启用，但未通过 WithDigestHook 注册投递回调")
+	}
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("变更摘要管理器初始化失败: %v", err)
+	}
+
+	manager.stopped.Add(1)
+	go manager.run()
+
+	return manager, nil
+}
+
+// applyDefaults 填充配置默认值
+func applyDefaults(config core.DigestConfig) core.DigestConfig {
+	if config.Interval <= 0 {
+		config.Interval = core.DefaultDigestInterval
+	}
+	if config.Window <= 0 {
+		config.Window = config.Interval
+	}
+	if config.MaxChanges <= 0 {
+		config.MaxChanges = core.DefaultDigestMaxChanges
+	}
+	return config
+}
+
+// Generate 汇总时间范围内的变更摘要
+func (m *digestManager) Generate(ctx context.Context, tenantKey string, since, until time.Time) ([]core.ChangeDigest, error) {
+	filter := core.AuditFilter{TenantKey: tenantKey, Since: since, Until: until}
+
+	var changes []core.PermissionChange
+	for offset := 0; ; offset += auditPageSize {
+		page, total, err := m.audit.ListEvents(ctx, filter, offset, auditPageSize)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, page...)
+		if len(page) == 0 || offset+len(page) >= total {
+			break
+		}
+	}
+
+	return core.BuildChangeDigests(changes, since, until, m.config.MaxChanges), nil
+}
+
+// Close 停止定时投递
+func (m *digestManager) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+	m.stopped.Wait()
+	return nil
+}
+
+// run 后台投递循环：等待到下一个周期边界后投递该周期的摘要
+func (m *digestManager) run() {
+	defer m.stopped.Done()
+
+	for {
+		periodEnd := time.Now().Truncate(m.config.Interval).Add(m.config.Interval)
+		timer := time.NewTimer(time.Until(periodEnd))
+		select {
+		case <-m.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := m.deliver(context.Background(), periodEnd); err != nil {
+			m.logger.Error("投递权限变更摘要失败", "periodEnd", periodEnd.UTC().Format(time.RFC3339), "error", err)
+		}
+	}
+}
+
+// deliver 认领周期并投递摘要；其他实例已认领的周期直接跳过
+func (m *digestManager) deliver(ctx context.Context, periodEnd time.Time) error {
+	claimed, err := m.claimPeriod(ctx, periodEnd)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	digests, err := m.Generate(ctx, "", periodEnd.Add(-m.config.Window), periodEnd)
+	if err != nil {
+		return err
+	}
+
+	// 单个回调或租户投递失败不影响其他租户
+	var failures int
+	for _, digest := range digests {
+		for _, hook := range m.hooks {
+			if err := hook(ctx, digest); err != nil {
+				failures++
+				m.logger.Error("投递权限变更摘要失败", "tenant", digest.TenantKey, "error", err)
+			}
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d 次投递失败", failures)
+	}
+	return nil
+}
+
+// claimPeriod 认领周期，返回是否由本实例投递
+func (m *digestManager) claimPeriod(ctx context.Context, periodEnd time.Time) (bool, error) {
+	insertSQL := `
+		INSERT INTO system_digest_runs (period_end) VALUES ($1)
+		ON CONFLICT (period_end) DO NOTHING
+	`
+	result, err := m.dbConn.ExecCtx(ctx, insertSQL, periodEnd)
+	if err != nil {
+		return false, fmt.Errorf("认领摘要周期失败: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("认领摘要周期失败: %v", err)
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,24 @@
+package digest
+
+import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// initDB 初始化数据库，创建摘要投递记录表（多实例部署时用于认领周期，保证每个周期只投递一次）
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_digest_runs (
+    period_end TIMESTAMP WITH TIME ZONE PRIMARY KEY,
+    claimed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_digest_runs表失败: %v", err)
+	}
+
+	return nil
+}
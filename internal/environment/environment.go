@@ -0,0 +1,25 @@
+package environment
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 授权环境标记管理器接口
+type Manager interface {
+	SetPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission, environments []string) error // 设置授权生效的环境（为空表示所有环境）
+	ClearPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission) error                      // 清除授权的环境标记
+	GetPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission) []string                     // 获取授权生效的环境，未标记时返回 nil
+	RenameSubject(ctx context.Context, oldSubject, newSubject string) error                                                     // 将主体的所有环境标记迁移到新主体
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error                                                        // 将域的所有环境标记迁移到新域
+	DeleteDomain(ctx context.Context, domain string) error                                                                      // 删除域的所有环境标记
+	CurrentEnvironment(ctx context.Context) string                                                                              // 获取当前运行环境
+}
+
+// NewManager 创建授权环境标记管理器
+func NewManager(dbConn sqlx.SqlConn, currentEnvironment string, enforcer *core.Enforcer) (Manager, error) {
+	return newEnvironmentManager(dbConn, currentEnvironment, enforcer)
+}
@@ -0,0 +1,263 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// environmentManager 授权环境标记管理器实现
+type environmentManager struct {
+	enforcer           *core.Enforcer
+	dbConn             sqlx.SqlConn
+	currentEnvironment string
+
+	mu   sync.RWMutex
+	tags map[string][]string // 策略键 -> 生效环境列表
+}
+
+// newEnvironmentManager 创建授权环境标记管理器实现
+func newEnvironmentManager(dbConn sqlx.SqlConn, currentEnvironment string, enforcer *core.Enforcer) (*environmentManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("环境标记管理器初始化失败: %v", err)
+	}
+
+	manager := &environmentManager{
+		enforcer:           enforcer,
+		dbConn:             dbConn,
+		currentEnvironment: currentEnvironment,
+		tags:               make(map[string][]string),
+	}
+
+	if err := manager.loadTags(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新环境标记，并在权限检查时过滤不属于当前环境的授权
+	enforcer.AddReloadHook(func() error { return manager.loadTags(context.Background()) })
+	enforcer.AddRuleFilter(manager.ruleApplies)
+	enforcer.AddObjectCleanupHook(manager.cleanupObject)
+
+	return manager, nil
+}
+
+// SetPolicyEnvironments 设置授权生效的环境
+func (m *environmentManager) SetPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission, environments []string) error {
+	if len(environments) == 0 {
+		return m.ClearPolicyEnvironments(ctx, subject, domain, permission)
+	}
+
+	for _, environment := range environments {
+		if err := core.FirstError(
+			core.RequireArg("environment", environment),
+			core.CheckArg(!strings.Contains(environment, ","), "environment", core.ConstraintFormat),
+		); err != nil {
+			return err
+		}
+	}
+
+	upsertSQL := `
+		INSERT INTO casbin_policy_environments (subject, domain, resource, action, environments)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subject, domain, resource, action) DO UPDATE
+		SET environments = EXCLUDED.environments, updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := m.dbConn.ExecCtx(ctx, upsertSQL, subject, domain, string(permission.QualifiedResource()), string(permission.Action), strings.Join(environments, ","))
+	if err != nil {
+		return fmt.Errorf("设置授权环境失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// ClearPolicyEnvironments 清除授权的环境标记
+func (m *environmentManager) ClearPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission) error {
+	// 未标记的授权无需清理，避免无意义的同步通知
+	if m.GetPolicyEnvironments(ctx, subject, domain, permission) == nil {
+		return nil
+	}
+
+	deleteSQL := `DELETE FROM casbin_policy_environments WHERE subject = $1 AND domain = $2 AND resource = $3 AND action = $4`
+	_, err := m.dbConn.ExecCtx(ctx, deleteSQL, subject, domain, string(permission.QualifiedResource()), string(permission.Action))
+	if err != nil {
+		return fmt.Errorf("清除授权环境失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// GetPolicyEnvironments 获取授权生效的环境
+func (m *environmentManager) GetPolicyEnvironments(ctx context.Context, subject, domain string, permission core.Permission) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.tags[policyKey(subject, domain, string(permission.QualifiedResource()), string(permission.Action))]
+}
+
+// RenameSubject 将主体的所有环境标记迁移到新主体
+func (m *environmentManager) RenameSubject(ctx context.Context, oldSubject, newSubject string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldSubject", oldSubject),
+		core.RequireArg("newSubject", newSubject),
+	); err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE casbin_policy_environments SET subject = $2, updated_at = CURRENT_TIMESTAMP WHERE subject = $1`
+	result, err := m.dbConn.ExecCtx(ctx, updateSQL, oldSubject, newSubject)
+	if err != nil {
+		return fmt.Errorf("迁移授权环境标记失败: %v", err)
+	}
+
+	// 没有环境标记时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// RenameDomain 将旧域下的所有环境标记迁移到新域，新域已有的环境标记保留
+func (m *environmentManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	var affected int64
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteSQL := `
+			DELETE FROM casbin_policy_environments o
+			WHERE o.domain = $1
+			  AND EXISTS (
+			    SELECT 1 FROM casbin_policy_environments n
+			    WHERE n.domain = $2 AND n.subject = o.subject AND n.resource = o.resource AND n.action = o.action
+			  )
+		`
+		if _, err := session.ExecCtx(ctx, deleteSQL, oldDomain, newDomain); err != nil {
+			return fmt.Errorf("清理重复的环境标记失败: %v", err)
+		}
+
+		updateSQL := `UPDATE casbin_policy_environments SET domain = $2, updated_at = CURRENT_TIMESTAMP WHERE domain = $1`
+		result, err := session.ExecCtx(ctx, updateSQL, oldDomain, newDomain)
+		if err != nil {
+			return fmt.Errorf("迁移环境标记失败: %v", err)
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 没有需要迁移的记录时无需同步
+	if affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// DeleteDomain 删除域下的所有环境标记
+func (m *environmentManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	deleteSQL := `DELETE FROM casbin_policy_environments WHERE domain = $1`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, domain)
+	if err != nil {
+		return fmt.Errorf("删除域环境标记失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// cleanupObject 对象清理回调：删除对象（含子资源）的环境标记
+func (m *environmentManager) cleanupObject(ctx context.Context, resourceType, objectID string, removed []core.Policy) error {
+	objectResource := string(core.ObjectResource(resourceType, objectID))
+	deleteSQL := `DELETE FROM casbin_policy_environments WHERE resource = $1 OR starts_with(resource, $1 || ':')`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, objectResource)
+	if err != nil {
+		return fmt.Errorf("清理对象环境标记失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// CurrentEnvironment 获取当前运行环境
+func (m *environmentManager) CurrentEnvironment(ctx context.Context) string {
+	return m.currentEnvironment
+}
+
+// ruleApplies 规则过滤器：标记了环境的授权只在匹配的环境中生效
+func (m *environmentManager) ruleApplies(rule core.Policy) bool {
+	if m.currentEnvironment == "" {
+		return true
+	}
+
+	m.mu.RLock()
+	environments, tagged := m.tags[policyKey(rule.Subject, rule.Domain, string(rule.Resource), string(rule.Action))]
+	m.mu.RUnlock()
+
+	if !tagged {
+		return true
+	}
+
+	for _, environment := range environments {
+		if environment == m.currentEnvironment {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTags 从数据库加载环境标记到内存
+func (m *environmentManager) loadTags(ctx context.Context) error {
+	var records []*policyEnvironmentRecord
+	selectSQL := `SELECT subject, domain, resource, action, environments FROM casbin_policy_environments`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载授权环境标记失败: %v", err)
+	}
+
+	tags := make(map[string][]string, len(records))
+	for _, record := range records {
+		tags[policyKey(record.Subject, record.Domain, record.Resource, record.Action)] = strings.Split(record.Environments, ",")
+	}
+
+	m.mu.Lock()
+	m.tags = tags
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地环境标记并通知其他实例
+func (m *environmentManager) sync(ctx context.Context) error {
+	if err := m.loadTags(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// policyKey 生成策略键
+func policyKey(subject, domain, resource, action string) string {
+	return strings.Join([]string{subject, domain, resource, action}, "\x00")
+}
@@ -0,0 +1,38 @@
+package environment
+
+import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// policyEnvironmentRecord 授权环境标记记录
+type policyEnvironmentRecord struct {
+	Subject      string `db:"subject"`
+	Domain       string `db:"domain"`
+	Resource     string `db:"resource"`
+	Action       string `db:"action"`
+	Environments string `db:"environments"`
+}
+
+// initDB 初始化数据库，创建授权环境标记表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_policy_environments (
+    subject VARCHAR(255) NOT NULL,
+    domain VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    environments TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subject, domain, resource, action)
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_policy_environments表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package expiration
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 限时授权管理器接口
+// 只负责过期时间的持久化和权限检查时的过期过滤，授权的写入和撤销由 engine 执行
+type Manager interface {
+	// 过期时间
+	SetPermissionExpiry(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission, expiresAt time.Time) error // 设置用户直接权限的过期时间
+	SetRoleExpiry(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string, expiresAt time.Time) error                          // 设置角色分配的过期时间
+	ClearPermissionExpiry(ctx context.Context, userKey, tenantKey string, permission core.Permission) error                                 // 清除用户直接权限的过期时间（授权变为永久或已撤销）
+	ClearRoleExpiry(ctx context.Context, userKey, roleKey, tenantKey string) error                                                          // 清除角色分配的过期时间
+	GetPermissionExpiry(ctx context.Context, userKey, tenantKey string, permission core.Permission) (*core.ExpiringGrant, error)            // 获取用户直接权限的过期记录，没有过期时间时返回 nil
+	GetRoleExpiry(ctx context.Context, userKey, roleKey, tenantKey string) (*core.ExpiringGrant, error)                                     // 获取角色分配的过期记录，没有过期时间时返回 nil
+	ListExpiring(ctx context.Context, filter core.ExpiringGrantFilter) ([]*core.ExpiringGrant, error)                                       // 按条件查询限时授权（按过期时间排序）
+	DeleteExpired(ctx context.Context, grant core.ExpiringGrant) (bool, error)                                                              // 删除已撤销的过期记录，记录已续期或已被其他实例删除时返回 false
+
+	// 用户和租户变更
+	RenameUser(ctx context.Context, oldKey, newKey string) error         // 将用户的限时授权迁移到新标识
+	DeleteUser(ctx context.Context, userKey string) error                // 删除用户的限时授权记录
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error // 将租户的限时授权迁移到新租户，新租户已有的记录保留
+	DeleteDomain(ctx context.Context, domain string) error               // 删除租户的限时授权记录
+}
+
+// NewManager 创建限时授权管理器
+// 已过期的直接权限和角色分配在权限检查时通过规则过滤器和角色分配过滤器排除
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newExpirationManager(dbConn, enforcer)
+}
@@ -0,0 +1,384 @@
+package expiration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// expirationManager 限时授权管理器实现
+type expirationManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu       sync.RWMutex
+	expiries map[string]time.Time // 授权键 -> 过期时间
+}
+
+// newExpirationManager 创建限时授权管理器实现
+func newExpirationManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*expirationManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("限时授权管理器初始化失败: %v", err)
+	}
+
+	manager := &expirationManager{
+		enforcer: enforcer,
+		dbConn:   dbConn,
+		expiries: make(map[string]time.Time),
+	}
+
+	if err := manager.loadExpiries(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新过期时间，并在权限检查时排除已过期的授权（不等待后台清理）
+	enforcer.AddReloadHook(func() error { return manager.loadExpiries(context.Background()) })
+	enforcer.AddRuleFilter(manager.ruleApplies)
+	enforcer.AddAssignmentFilter(manager.assignmentApplies)
+
+	return manager, nil
+}
+
+// SetPermissionExpiry 设置用户直接权限的过期时间
+func (m *expirationManager) SetPermissionExpiry(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission, expiresAt time.Time) error {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.CheckArg(!expiresAt.IsZero(), "expiresAt", core.ConstraintRequired),
+	); err != nil {
+		return err
+	}
+
+	grant := core.ExpiringGrant{Kind: core.ExpiringPermission, UserKey: userKey, TenantKey: tenantKey, Permission: permission}
+	return m.setExpiry(ctx, operatorKey, grant, expiresAt)
+}
+
+// SetRoleExpiry 设置角色分配的过期时间
+func (m *expirationManager) SetRoleExpiry(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string, expiresAt time.Time) error {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.CheckArg(!expiresAt.IsZero(), "expiresAt", core.ConstraintRequired),
+	); err != nil {
+		return err
+	}
+
+	grant := core.ExpiringGrant{Kind: core.ExpiringRole, UserKey: userKey, TenantKey: tenantKey, RoleKey: roleKey}
+	return m.setExpiry(ctx, operatorKey, grant, expiresAt)
+}
+
+// setExpiry 写入或更新过期时间
+func (m *expirationManager) setExpiry(ctx context.Context, operatorKey string, grant core.ExpiringGrant, expiresAt time.Time) error {
+	upsertSQL := `
+		INSERT INTO casbin_grant_expirations (kind, user_key, tenant_key, resource, action, role_key, operator_key, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (kind, user_key, tenant_key, resource, action, role_key) DO UPDATE
+		SET operator_key = EXCLUDED.operator_key, expires_at = EXCLUDED.expires_at, created_at = CURRENT_TIMESTAMP
+	`
+	resource, action := grantTarget(grant)
+	_, err := m.dbConn.ExecCtx(ctx, upsertSQL, string(grant.Kind), grant.UserKey, grant.TenantKey, resource, action, grant.RoleKey, operatorKey, expiresAt)
+	if err != nil {
+		return fmt.Errorf("设置授权过期时间失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// ClearPermissionExpiry 清除用户直接权限的过期时间
+func (m *expirationManager) ClearPermissionExpiry(ctx context.Context, userKey, tenantKey string, permission core.Permission) error {
+	return m.clearExpiry(ctx, core.ExpiringGrant{Kind: core.ExpiringPermission, UserKey: userKey, TenantKey: tenantKey, Permission: permission})
+}
+
+// ClearRoleExpiry 清除角色分配的过期时间
+func (m *expirationManager) ClearRoleExpiry(ctx context.Context, userKey, roleKey, tenantKey string) error {
+	return m.clearExpiry(ctx, core.ExpiringGrant{Kind: core.ExpiringRole, UserKey: userKey, TenantKey: tenantKey, RoleKey: roleKey})
+}
+
+// clearExpiry 删除过期时间记录
+func (m *expirationManager) clearExpiry(ctx context.Context, grant core.ExpiringGrant) error {
+	// 没有过期时间的授权无需清理，避免无意义的同步通知
+	m.mu.RLock()
+	_, found := m.expiries[grantKey(grant)]
+	m.mu.RUnlock()
+	if !found {
+		return nil
+	}
+
+	resource, action := grantTarget(grant)
+	deleteSQL := `
+		DELETE FROM casbin_grant_expirations
+		WHERE kind = $1 AND user_key = $2 AND tenant_key = $3 AND resource = $4 AND action = $5 AND role_key = $6
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, string(grant.Kind), grant.UserKey, grant.TenantKey, resource, action, grant.RoleKey); err != nil {
+		return fmt.Errorf("清除授权过期时间失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// GetPermissionExpiry 获取用户直接权限的过期记录
+func (m *expirationManager) GetPermissionExpiry(ctx context.Context, userKey, tenantKey string, permission core.Permission) (*core.ExpiringGrant, error) {
+	return m.getExpiry(ctx, core.ExpiringGrant{Kind: core.ExpiringPermission, UserKey: userKey, TenantKey: tenantKey, Permission: permission})
+}
+
+// GetRoleExpiry 获取角色分配的过期记录
+func (m *expirationManager) GetRoleExpiry(ctx context.Context, userKey, roleKey, tenantKey string) (*core.ExpiringGrant, error) {
+	return m.getExpiry(ctx, core.ExpiringGrant{Kind: core.ExpiringRole, UserKey: userKey, TenantKey: tenantKey, RoleKey: roleKey})
+}
+
+// getExpiry 从数据库查询过期记录（包含操作者），没有记录时返回 nil
+func (m *expirationManager) getExpiry(ctx context.Context, grant core.ExpiringGrant) (*core.ExpiringGrant, error) {
+	resource, action := grantTarget(grant)
+	var records []grantExpirationRecord
+	selectSQL := `
+		SELECT ` + grantExpirationColumns + ` FROM casbin_grant_expirations
+		WHERE kind = $1 AND user_key = $2 AND tenant_key = $3 AND resource = $4 AND action = $5 AND role_key = $6
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, string(grant.Kind), grant.UserKey, grant.TenantKey, resource, action, grant.RoleKey); err != nil {
+		return nil, fmt.Errorf("查询授权过期时间失败: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return toGrant(records[0]), nil
+}
+
+// ListExpiring 按条件查询限时授权
+func (m *expirationManager) ListExpiring(ctx context.Context, filter core.ExpiringGrantFilter) ([]*core.ExpiringGrant, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TenantKey != "" {
+		add("tenant_key = $%d", filter.TenantKey)
+	}
+	if filter.UserKey != "" {
+		add("user_key = $%d", filter.UserKey)
+	}
+	if !filter.Before.IsZero() {
+		add("expires_at <= $%d", filter.Before)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var records []grantExpirationRecord
+	selectSQL := `SELECT ` + grantExpirationColumns + ` FROM casbin_grant_expirations` + where + ` ORDER BY expires_at, user_key`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, args...); err != nil {
+		return nil, fmt.Errorf("查询限时授权失败: %v", err)
+	}
+
+	grants := make([]*core.ExpiringGrant, 0, len(records))
+	for _, record := range records {
+		grants = append(grants, toGrant(record))
+	}
+	return grants, nil
+}
+
+// DeleteExpired 删除已撤销的过期记录
+// 只删除过期时间与 grant 一致的记录，撤销期间被续期的记录保留
+func (m *expirationManager) DeleteExpired(ctx context.Context, grant core.ExpiringGrant) (bool, error) {
+	resource, action := grantTarget(grant)
+	deleteSQL := `
+		DELETE FROM casbin_grant_expirations
+		WHERE kind = $1 AND user_key = $2 AND tenant_key = $3 AND resource = $4 AND action = $5 AND role_key = $6 AND expires_at = $7
+	`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, string(grant.Kind), grant.UserKey, grant.TenantKey, resource, action, grant.RoleKey, grant.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("删除过期授权记录失败: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("删除过期授权记录失败: %v", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	return true, m.sync(ctx)
+}
+
+// RenameUser 将用户的限时授权迁移到新标识
+func (m *expirationManager) RenameUser(ctx context.Context, oldKey, newKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldKey", oldKey),
+		core.RequireArg("newKey", newKey),
+	); err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE casbin_grant_expirations SET user_key = $2 WHERE user_key = $1`
+	result, err := m.dbConn.ExecCtx(ctx, updateSQL, oldKey, newKey)
+	if err != nil {
+		return fmt.Errorf("迁移用户限时授权失败: %v", err)
+	}
+
+	// 没有限时授权时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// DeleteUser 删除用户的限时授权记录
+func (m *expirationManager) DeleteUser(ctx context.Context, userKey string) error {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return err
+	}
+
+	result, err := m.dbConn.ExecCtx(ctx, `DELETE FROM casbin_grant_expirations WHERE user_key = $1`, userKey)
+	if err != nil {
+		return fmt.Errorf("删除用户限时授权失败: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// RenameDomain 将租户的限时授权迁移到新租户，新租户已有的记录保留
+func (m *expirationManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		statements := []string{
+			`DELETE FROM casbin_grant_expirations o
+			 WHERE o.tenant_key = $1
+			   AND EXISTS (
+			     SELECT 1 FROM casbin_grant_expirations n
+			     WHERE n.tenant_key = $2 AND n.kind = o.kind AND n.user_key = o.user_key
+			       AND n.resource = o.resource AND n.action = o.action AND n.role_key = o.role_key
+			   )`,
+			`UPDATE casbin_grant_expirations SET tenant_key = $2 WHERE tenant_key = $1`,
+		}
+		for _, statement := range statements {
+			if _, err := session.ExecCtx(ctx, statement, oldDomain, newDomain); err != nil {
+				return fmt.Errorf("迁移租户限时授权失败: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.sync(ctx)
+}
+
+// DeleteDomain 删除租户的限时授权记录
+func (m *expirationManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	result, err := m.dbConn.ExecCtx(ctx, `DELETE FROM casbin_grant_expirations WHERE tenant_key = $1`, domain)
+	if err != nil {
+		return fmt.Errorf("删除租户限时授权失败: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// ruleApplies 规则过滤器：排除已过期的用户直接权限
+func (m *expirationManager) ruleApplies(rule core.Policy) bool {
+	if rule.SubjectKind == core.SubjectKindRole {
+		return true
+	}
+	grant := core.ExpiringGrant{Kind: core.ExpiringPermission, UserKey: rule.Subject, TenantKey: rule.Domain, Permission: rule.Permission()}
+	return m.active(grantKey(grant))
+}
+
+// assignmentApplies 角色分配过滤器：排除已过期的角色分配
+func (m *expirationManager) assignmentApplies(userKey, roleKey, domain string) bool {
+	grant := core.ExpiringGrant{Kind: core.ExpiringRole, UserKey: userKey, TenantKey: domain, RoleKey: roleKey}
+	return m.active(grantKey(grant))
+}
+
+// active 检查授权是否未过期（没有过期时间的授权总是有效）
+func (m *expirationManager) active(key string) bool {
+	m.mu.RLock()
+	expiresAt, found := m.expiries[key]
+	m.mu.RUnlock()
+
+	return !found || time.Now().Before(expiresAt)
+}
+
+// loadExpiries 从数据库加载过期时间到内存
+func (m *expirationManager) loadExpiries(ctx context.Context) error {
+	var records []grantExpirationRecord
+	selectSQL := `SELECT ` + grantExpirationColumns + ` FROM casbin_grant_expirations`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载授权过期时间失败: %v", err)
+	}
+
+	expiries := make(map[string]time.Time, len(records))
+	for _, record := range records {
+		expiries[grantKey(*toGrant(record))] = record.ExpiresAt
+	}
+
+	m.mu.Lock()
+	m.expiries = expiries
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地过期时间并通知其他实例
+func (m *expirationManager) sync(ctx context.Context) error {
+	if err := m.loadExpiries(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// grantTarget 返回授权在表中的资源和操作列（角色分配为空）
+func grantTarget(grant core.ExpiringGrant) (string, string) {
+	if grant.Kind == core.ExpiringRole {
+		return "", ""
+	}
+	return string(grant.Permission.QualifiedResource()), string(grant.Permission.Action)
+}
+
+// grantKey 生成授权键
+func grantKey(grant core.ExpiringGrant) string {
+	resource, action := grantTarget(grant)
+	return strings.Join([]string{string(grant.Kind), grant.UserKey, grant.TenantKey, resource, action, grant.RoleKey}, "\x00")
+}
+
+// toGrant 转换限时授权记录
+func toGrant(record grantExpirationRecord) *core.ExpiringGrant {
+	grant := &core.ExpiringGrant{
+		Kind:        core.ExpiringGrantKind(record.Kind),
+		UserKey:     record.UserKey,
+		TenantKey:   record.TenantKey,
+		RoleKey:     record.RoleKey,
+		OperatorKey: record.OperatorKey,
+		ExpiresAt:   record.ExpiresAt,
+		CreatedAt:   record.CreatedAt,
+	}
+	if grant.Kind == core.ExpiringPermission {
+		grant.Permission = core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.Action)}
+	}
+	return grant
+}
@@ -0,0 +1,52 @@
+package expiration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// grantExpirationRecord 限时授权记录
+type grantExpirationRecord struct {
+	Kind        string    `db:"kind"`
+	UserKey     string    `db:"user_key"`
+	TenantKey   string    `db:"tenant_key"`
+	Resource    string    `db:"resource"`
+	Action      string    `db:"action"`
+	RoleKey     string    `db:"role_key"`
+	OperatorKey string    `db:"operator_key"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// grantExpirationColumns 查询限时授权的列
+const grantExpirationColumns = `kind, user_key, tenant_key, resource, action, role_key, operator_key, expires_at, created_at`
+
+// initDB 初始化数据库，创建限时授权表
+// 直接权限的 role_key 为空，角色分配的 resource 和 action 为空
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_grant_expirations (
+    kind VARCHAR(16) NOT NULL,
+    user_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL DEFAULT '',
+    action VARCHAR(255) NOT NULL DEFAULT '',
+    role_key VARCHAR(255) NOT NULL DEFAULT '',
+    operator_key VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kind, user_key, tenant_key, resource, action, role_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_casbin_grant_expirations_expires_at ON casbin_grant_expirations(expires_at);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_grant_expirations表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// idempotencyManager 幂等键管理器实现
+type idempotencyManager struct {
+	dbConn sqlx.SqlConn
+	ttl    time.Duration
+}
+
+// newIdempotencyManager 创建幂等键管理器实现
+func newIdempotencyManager(dbConn sqlx.SqlConn, ttl time.Duration) (*idempotencyManager, error) {
+	if ttl <= 0 {
+		ttl = core.DefaultIdempotencyTTL
+	}
+
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("幂等键管理器初始化失败: %v", err)
+	}
+
+	return &idempotencyManager{
+		dbConn: dbConn,
+		ttl:    ttl,
+	}, nil
+}
+
+// Acquire 占用幂等键
+// 返回 true 表示首次执行，调用方应继续执行操作；返回 false 表示相同操作已成功执行过
+func (m *idempotencyManager) Acquire(ctx context.Context, key, fingerprint string) (bool, error) {
+	if err := core.RequireArg("key", key); err != nil {
+		return false, err
+	}
+
+	// 清理过期的幂等键，使过期后的同名键可以被重新占用
+	if _, err := m.dbConn.ExecCtx(ctx, `DELETE FROM casbin_idempotency_keys WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		return false, fmt.Errorf("清理过期幂等键失败: %v", err)
+	}
+
+	insertSQL := `
+		INSERT INTO casbin_idempotency_keys (idempotency_key, fingerprint, status, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+	result, err := m.dbConn.ExecCtx(ctx, insertSQL, key, fingerprint, statusPending, time.Now().Add(m.ttl))
+	if err != nil {
+		return false, fmt.Errorf("占用幂等键失败: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("占用幂等键失败: %v", err)
+	}
+	if affected == 1 {
+		return true, nil
+	}
+
+	// 幂等键已存在，判断是否为同一操作的重试
+	var record idempotencyRecord
+	selectSQL := `
+		SELECT idempotency_key, fingerprint, status, created_at, expires_at
+		FROM casbin_idempotency_keys WHERE idempotency_key = $1
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, key); err != nil {
+		return false, fmt.Errorf("查询幂等键失败: %v", err)
+	}
+
+	if record.Fingerprint != fingerprint {
+		return false, core.ErrIdempotencyKeyConflict
+	}
+	if record.Status != statusCompleted {
+		return false, core.ErrIdempotencyKeyInProgress
+	}
+
+	return false, nil
+}
+
+// Complete 标记幂等键对应的操作已完成
+func (m *idempotencyManager) Complete(ctx context.Context, key string) error {
+	updateSQL := `UPDATE casbin_idempotency_keys SET status = $2 WHERE idempotency_key = $1`
+	if _, err := m.dbConn.ExecCtx(ctx, updateSQL, key, statusCompleted); err != nil {
+		return fmt.Errorf("更新幂等键状态失败: %v", err)
+	}
+	return nil
+}
+
+// Release 释放幂等键
+func (m *idempotencyManager) Release(ctx context.Context, key string) error {
+	deleteSQL := `DELETE FROM casbin_idempotency_keys WHERE idempotency_key = $1 AND status = $2`
+	if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, key, statusPending); err != nil {
+		return fmt.Errorf("释放幂等键失败: %v", err)
+	}
+	return nil
+}
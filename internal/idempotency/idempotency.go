@@ -0,0 +1,20 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 幂等键管理器接口
+type Manager interface {
+	Acquire(ctx context.Context, key, fingerprint string) (bool, error) // 占用幂等键，返回 false 表示该操作此前已成功执行
+	Complete(ctx context.Context, key string) error                     // 标记幂等键对应的操作已成功完成
+	Release(ctx context.Context, key string) error                      // 释放幂等键（操作失败时调用，允许客户端重试）
+}
+
+// NewManager 创建幂等键管理器
+func NewManager(dbConn sqlx.SqlConn, ttl time.Duration) (Manager, error) {
+	return newIdempotencyManager(dbConn, ttl)
+}
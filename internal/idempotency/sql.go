@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+const (
+	statusPending   = "pending"   // 操作执行中
+	statusCompleted = "completed" // 操作已完成
+)
+
+// idempotencyRecord 幂等键记录
+type idempotencyRecord struct {
+	IdempotencyKey string       `db:"idempotency_key"`
+	Fingerprint    string       `db:"fingerprint"`
+	Status         string       `db:"status"`
+	CreatedAt      sql.NullTime `db:"created_at"`
+	ExpiresAt      sql.NullTime `db:"expires_at"`
+}
+
+// initDB 初始化数据库，创建幂等键表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_idempotency_keys (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_casbin_idempotency_keys_expires_at ON casbin_idempotency_keys(expires_at);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_idempotency_keys表失败: %v", err)
+	}
+
+	return nil
+}
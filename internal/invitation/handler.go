@@ -0,0 +1,462 @@
+package invitation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// invitationManager 分享邀请管理器实现
+type invitationManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+}
+
+// newInvitationManager 创建分享邀请管理器实现
+func newInvitationManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*invitationManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("分享邀请管理器初始化失败: %v", err)
+	}
+
+	manager := &invitationManager{
+		enforcer: enforcer,
+		dbConn:   dbConn,
+	}
+
+	// 对象删除时清理邀请中的对象权限和授权来源
+	enforcer.AddObjectCleanupHook(manager.cleanupObject)
+
+	return manager, nil
+}
+
+// CreateInvitation 创建分享邀请
+func (m *invitationManager) CreateInvitation(ctx context.Context, operatorKey, tenantKey string, permissions []core.Permission, expiry time.Duration, maxUses int) (*core.ShareInvitation, error) {
+	permissions = core.NormalizePermissions(permissions)
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireItems("permissions", permissions),
+		core.CheckArg(expiry > 0, "expiry", core.ConstraintRange),
+		core.CheckArg(maxUses > 0, "maxUses", core.ConstraintRange),
+	); err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		if err := core.RequirePermissionArg("permission", permission); err != nil {
+			return nil, err
+		}
+	}
+
+	invitationID, err := randomString(16, hex.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+	token, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &core.ShareInvitation{
+		ID:          invitationID,
+		Token:       token,
+		TenantKey:   tenantKey,
+		Permissions: permissions,
+		CreatedBy:   operatorKey,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(expiry),
+		MaxUses:     maxUses,
+	}
+
+	err = m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		insertSQL := `
+			INSERT INTO system_share_invitations (invitation_id, token_hash, tenant_key, created_by, created_at, expires_at, max_uses)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`
+		if _, err := session.ExecCtx(ctx, insertSQL, invitationID, hashToken(token), tenantKey, operatorKey, invitation.CreatedAt, invitation.ExpiresAt, maxUses); err != nil {
+			return fmt.Errorf("创建分享邀请失败: %v", err)
+		}
+
+		for _, permission := range permissions {
+			permissionSQL := `INSERT INTO system_share_invitation_permissions (invitation_id, resource, action) VALUES ($1, $2, $3)`
+			if _, err := session.ExecCtx(ctx, permissionSQL, invitationID, string(permission.QualifiedResource()), string(permission.Action)); err != nil {
+				return fmt.Errorf("保存分享邀请权限失败: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// ConsumeInvitation 占用一次邀请使用次数
+// 同一用户只能接受一次；过期、撤销或达到使用上限的邀请返回 ErrInvitationUnavailable
+func (m *invitationManager) ConsumeInvitation(ctx context.Context, userKey, token string) (*core.ShareInvitation, error) {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("token", token),
+	); err != nil {
+		return nil, err
+	}
+
+	var record invitationRecord
+	selectSQL := `
+		SELECT invitation_id, tenant_key, created_by, created_at, expires_at, max_uses, use_count, revoked
+		FROM system_share_invitations WHERE token_hash = $1
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, hashToken(token)); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("查询分享邀请失败: %v", err)
+	}
+
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		acceptSQL := `
+			INSERT INTO system_share_invitation_acceptances (invitation_id, user_key)
+			VALUES ($1, $2)
+			ON CONFLICT (invitation_id, user_key) DO NOTHING
+		`
+		result, err := session.ExecCtx(ctx, acceptSQL, record.InvitationID, userKey)
+		if err != nil {
+			return fmt.Errorf("记录邀请接受失败: %v", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("记录邀请接受失败: %v", err)
+		} else if affected == 0 {
+			return core.ErrInvitationAlreadyAccepted
+		}
+
+		// 条件更新保证并发接受时不会超出使用上限
+		updateSQL := `
+			UPDATE system_share_invitations SET use_count = use_count + 1
+			WHERE invitation_id = $1 AND NOT revoked AND expires_at > CURRENT_TIMESTAMP AND use_count < max_uses
+		`
+		result, err = session.ExecCtx(ctx, updateSQL, record.InvitationID)
+		if err != nil {
+			return fmt.Errorf("更新邀请使用次数失败: %v", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("更新邀请使用次数失败: %v", err)
+		} else if affected == 0 {
+			return core.ErrInvitationUnavailable
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record.UseCount++
+	return m.toInvitation(ctx, record)
+}
+
+// ReleaseInvitation 归还占用的使用次数
+func (m *invitationManager) ReleaseInvitation(ctx context.Context, invitationID, userKey string) error {
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteSQL := `DELETE FROM system_share_invitation_acceptances WHERE invitation_id = $1 AND user_key = $2`
+		result, err := session.ExecCtx(ctx, deleteSQL, invitationID, userKey)
+		if err != nil {
+			return fmt.Errorf("归还邀请使用次数失败: %v", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return err
+		}
+
+		updateSQL := `UPDATE system_share_invitations SET use_count = use_count - 1 WHERE invitation_id = $1 AND use_count > 0`
+		if _, err := session.ExecCtx(ctx, updateSQL, invitationID); err != nil {
+			return fmt.Errorf("归还邀请使用次数失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// RecordGrants 记录通过邀请获得的授权来源
+func (m *invitationManager) RecordGrants(ctx context.Context, invitation *core.ShareInvitation, userKey string, permissions []core.Permission) error {
+	if err := core.FirstError(
+		core.CheckArg(invitation != nil, "invitation", core.ConstraintRequired),
+		core.RequireArg("userKey", userKey),
+	); err != nil {
+		return err
+	}
+
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		upsertSQL := `
+			INSERT INTO system_invitation_grants (user_key, tenant_key, resource, action, invitation_id, invited_by, accepted_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_key, tenant_key, resource, action)
+			DO UPDATE SET invitation_id = $5, invited_by = $6, accepted_at = CURRENT_TIMESTAMP
+		`
+		for _, permission := range permissions {
+			if _, err := session.ExecCtx(ctx, upsertSQL, userKey, invitation.TenantKey, string(permission.QualifiedResource()), string(permission.Action), invitation.ID, invitation.CreatedBy); err != nil {
+				return fmt.Errorf("记录邀请授权来源失败: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetInvitation 获取分享邀请
+func (m *invitationManager) GetInvitation(ctx context.Context, invitationID string) (*core.ShareInvitation, error) {
+	if err := core.RequireArg("invitationID", invitationID); err != nil {
+		return nil, err
+	}
+
+	var record invitationRecord
+	selectSQL := `
+		SELECT invitation_id, tenant_key, created_by, created_at, expires_at, max_uses, use_count, revoked
+		FROM system_share_invitations WHERE invitation_id = $1
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, invitationID); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("查询分享邀请失败: %v", err)
+	}
+
+	return m.toInvitation(ctx, record)
+}
+
+// RevokeInvitation 撤销分享邀请
+func (m *invitationManager) RevokeInvitation(ctx context.Context, invitationID string) error {
+	if err := core.RequireArg("invitationID", invitationID); err != nil {
+		return err
+	}
+
+	result, err := m.dbConn.ExecCtx(ctx, `UPDATE system_share_invitations SET revoked = TRUE WHERE invitation_id = $1`, invitationID)
+	if err != nil {
+		return fmt.Errorf("撤销分享邀请失败: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return core.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// GetInvitationGrants 获取用户在租户内仍然有效的邀请授权来源
+// 授权已被撤销的来源记录不会返回
+func (m *invitationManager) GetInvitationGrants(ctx context.Context, userKey, tenantKey string) ([]core.InvitationGrant, error) {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return nil, err
+	}
+
+	var records []*invitationGrantRecord
+	selectSQL := `
+		SELECT invitation_id, user_key, tenant_key, resource, action, invited_by, accepted_at
+		FROM system_invitation_grants
+		WHERE user_key = $1 AND tenant_key = $2
+		ORDER BY accepted_at DESC, resource, action
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, userKey, tenantKey); err != nil {
+		return nil, fmt.Errorf("查询邀请授权来源失败: %v", err)
+	}
+
+	grants := make([]core.InvitationGrant, 0, len(records))
+	for _, record := range records {
+		permission := core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.Action)}
+		exists, err := m.enforcer.HasDirectPermission(userKey, tenantKey, permission)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		grants = append(grants, core.InvitationGrant{
+			InvitationID: record.InvitationID,
+			UserKey:      record.UserKey,
+			TenantKey:    record.TenantKey,
+			Permission:   permission,
+			InvitedBy:    record.InvitedBy,
+			AcceptedAt:   record.AcceptedAt,
+		})
+	}
+
+	return grants, nil
+}
+
+// ListUserGrants 获取用户在所有租户的邀请授权来源记录（包含授权已被撤销的记录）
+func (m *invitationManager) ListUserGrants(ctx context.Context, userKey string) ([]core.InvitationGrant, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
+	}
+
+	var records []*invitationGrantRecord
+	selectSQL := `
+		SELECT invitation_id, user_key, tenant_key, resource, action, invited_by, accepted_at
+		FROM system_invitation_grants
+		WHERE user_key = $1
+		ORDER BY accepted_at, tenant_key, resource, action
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, userKey); err != nil {
+		return nil, fmt.Errorf("查询邀请授权来源失败: %v", err)
+	}
+
+	grants := make([]core.InvitationGrant, 0, len(records))
+	for _, record := range records {
+		grants = append(grants, core.InvitationGrant{
+			InvitationID: record.InvitationID,
+			UserKey:      record.UserKey,
+			TenantKey:    record.TenantKey,
+			Permission:   core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.Action)},
+			InvitedBy:    record.InvitedBy,
+			AcceptedAt:   record.AcceptedAt,
+		})
+	}
+	return grants, nil
+}
+
+// ListCreatedInvitations 获取用户创建的分享邀请（不含令牌）
+func (m *invitationManager) ListCreatedInvitations(ctx context.Context, userKey string) ([]core.ShareInvitation, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
+	}
+
+	var records []invitationRecord
+	selectSQL := `
+		SELECT invitation_id, tenant_key, created_by, created_at, expires_at, max_uses, use_count, revoked
+		FROM system_share_invitations WHERE created_by = $1
+		ORDER BY created_at, invitation_id
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, userKey); err != nil {
+		return nil, fmt.Errorf("查询分享邀请失败: %v", err)
+	}
+
+	invitations := make([]core.ShareInvitation, 0, len(records))
+	for _, record := range records {
+		invitation, err := m.toInvitation(ctx, record)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *invitation)
+	}
+	return invitations, nil
+}
+
+// EraseUser 删除用户的邀请授权来源和接受记录，用户创建的邀请被撤销，其他记录中的创建者替换为假名
+// 返回删除的授权来源和接受记录数
+func (m *invitationManager) EraseUser(ctx context.Context, userKey, pseudonym string) (int, error) {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("pseudonym", pseudonym),
+	); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, deleteSQL := range []string{
+			`DELETE FROM system_invitation_grants WHERE user_key = $1`,
+			`DELETE FROM system_share_invitation_acceptances WHERE user_key = $1`,
+		} {
+			result, err := session.ExecCtx(ctx, deleteSQL, userKey)
+			if err != nil {
+				return fmt.Errorf("删除邀请接受记录失败: %v", err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("删除邀请接受记录失败: %v", err)
+			}
+			deleted += affected
+		}
+
+		if _, err := session.ExecCtx(ctx, `UPDATE system_share_invitations SET created_by = $2, revoked = TRUE WHERE created_by = $1`, userKey, pseudonym); err != nil {
+			return fmt.Errorf("撤销用户创建的分享邀请失败: %v", err)
+		}
+		if _, err := session.ExecCtx(ctx, `UPDATE system_invitation_grants SET invited_by = $2 WHERE invited_by = $1`, userKey, pseudonym); err != nil {
+			return fmt.Errorf("替换邀请授权来源创建者失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
+}
+
+// cleanupObject 对象清理回调：删除邀请中的对象权限及对象授权来源，不再包含任何权限的邀请一并删除
+func (m *invitationManager) cleanupObject(ctx context.Context, resourceType, objectID string, removed []core.Policy) error {
+	objectResource := string(core.ObjectResource(resourceType, objectID))
+
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteGrantsSQL := `DELETE FROM system_invitation_grants WHERE resource = $1 OR starts_with(resource, $1 || ':')`
+		if _, err := session.ExecCtx(ctx, deleteGrantsSQL, objectResource); err != nil {
+			return fmt.Errorf("清理对象邀请授权来源失败: %v", err)
+		}
+
+		deletePermissionsSQL := `DELETE FROM system_share_invitation_permissions WHERE resource = $1 OR starts_with(resource, $1 || ':')`
+		if _, err := session.ExecCtx(ctx, deletePermissionsSQL, objectResource); err != nil {
+			return fmt.Errorf("清理对象邀请权限失败: %v", err)
+		}
+
+		deleteEmptySQL := `
+			DELETE FROM system_share_invitations i
+			WHERE NOT EXISTS (SELECT 1 FROM system_share_invitation_permissions p WHERE p.invitation_id = i.invitation_id)
+		`
+		if _, err := session.ExecCtx(ctx, deleteEmptySQL); err != nil {
+			return fmt.Errorf("清理空分享邀请失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// toInvitation 转换邀请记录并加载邀请权限
+func (m *invitationManager) toInvitation(ctx context.Context, record invitationRecord) (*core.ShareInvitation, error) {
+	var permissionRecords []*invitationPermissionRecord
+	selectSQL := `SELECT resource, action FROM system_share_invitation_permissions WHERE invitation_id = $1 ORDER BY resource, action`
+	if err := m.dbConn.QueryRowsCtx(ctx, &permissionRecords, selectSQL, record.InvitationID); err != nil {
+		return nil, fmt.Errorf("查询分享邀请权限失败: %v", err)
+	}
+
+	permissions := make([]core.Permission, 0, len(permissionRecords))
+	for _, permissionRecord := range permissionRecords {
+		permissions = append(permissions, core.Permission{
+			Resource: core.Resource(permissionRecord.Resource),
+			Action:   core.Action(permissionRecord.Action),
+		})
+	}
+
+	return &core.ShareInvitation{
+		ID:          record.InvitationID,
+		TenantKey:   record.TenantKey,
+		Permissions: permissions,
+		CreatedBy:   record.CreatedBy,
+		CreatedAt:   record.CreatedAt,
+		ExpiresAt:   record.ExpiresAt,
+		MaxUses:     record.MaxUses,
+		UseCount:    record.UseCount,
+		Revoked:     record.Revoked,
+	}, nil
+}
+
+// hashToken 计算邀请令牌摘要，数据库中不保存令牌明文
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomString 生成指定字节数的随机字符串
+func randomString(size int, encode func([]byte) string) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %v", err)
+	}
+	return encode(buf), nil
+}
@@ -0,0 +1,31 @@
+package invitation
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 分享邀请管理器接口
+type Manager interface {
+	CreateInvitation(ctx context.Context, operatorKey, tenantKey string, permissions []core.Permission, expiry time.Duration, maxUses int) (*core.ShareInvitation, error) // 创建分享邀请，返回包含令牌的邀请
+	ConsumeInvitation(ctx context.Context, userKey, token string) (*core.ShareInvitation, error)                                                                          // 占用一次邀请使用次数（校验过期、撤销和使用上限）
+	ReleaseInvitation(ctx context.Context, invitationID, userKey string) error                                                                                            // 归还占用的使用次数（授权失败时调用）
+	RecordGrants(ctx context.Context, invitation *core.ShareInvitation, userKey string, permissions []core.Permission) error                                              // 记录通过邀请获得的授权来源
+	GetInvitation(ctx context.Context, invitationID string) (*core.ShareInvitation, error)                                                                                // 获取分享邀请（不含令牌）
+	RevokeInvitation(ctx context.Context, invitationID string) error                                                                                                      // 撤销分享邀请，已授予的权限不受影响
+	GetInvitationGrants(ctx context.Context, userKey, tenantKey string) ([]core.InvitationGrant, error)                                                                   // 获取用户在租户内仍然有效的邀请授权来源
+
+	// 用户数据（导出与删除）
+	ListUserGrants(ctx context.Context, userKey string) ([]core.InvitationGrant, error)         // 获取用户在所有租户的邀请授权来源记录
+	ListCreatedInvitations(ctx context.Context, userKey string) ([]core.ShareInvitation, error) // 获取用户创建的分享邀请（不含令牌）
+	EraseUser(ctx context.Context, userKey, pseudonym string) (int, error)                      // 删除用户的授权来源和接受记录，撤销用户创建的邀请并将创建者替换为假名
+}
+
+// NewManager 创建分享邀请管理器
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newInvitationManager(dbConn, enforcer)
+}
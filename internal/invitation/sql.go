@@ -0,0 +1,100 @@
+package invitation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// invitationRecord 分享邀请记录
+type invitationRecord struct {
+	InvitationID string    `db:"invitation_id"`
+	TenantKey    string    `db:"tenant_key"`
+	CreatedBy    string    `db:"created_by"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	MaxUses      int       `db:"max_uses"`
+	UseCount     int       `db:"use_count"`
+	Revoked      bool      `db:"revoked"`
+}
+
+// invitationPermissionRecord 分享邀请权限记录
+type invitationPermissionRecord struct {
+	Resource string `db:"resource"`
+	Action   string `db:"action"`
+}
+
+// invitationGrantRecord 邀请授权来源记录
+type invitationGrantRecord struct {
+	InvitationID string    `db:"invitation_id"`
+	UserKey      string    `db:"user_key"`
+	TenantKey    string    `db:"tenant_key"`
+	Resource     string    `db:"resource"`
+	Action       string    `db:"action"`
+	InvitedBy    string    `db:"invited_by"`
+	AcceptedAt   time.Time `db:"accepted_at"`
+}
+
+// initDB 初始化数据库，创建分享邀请相关表
+func initDB(dbConn sqlx.SqlConn) error {
+	createInvitationsSQL := `
+CREATE TABLE IF NOT EXISTS system_share_invitations (
+    invitation_id VARCHAR(64) PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    tenant_key VARCHAR(255) NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_uses INTEGER NOT NULL,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    revoked BOOLEAN NOT NULL DEFAULT FALSE
+);
+`
+	if _, err := dbConn.Exec(createInvitationsSQL); err != nil {
+		return fmt.Errorf("创建system_share_invitations表失败: %v", err)
+	}
+
+	createPermissionsSQL := `
+CREATE TABLE IF NOT EXISTS system_share_invitation_permissions (
+    invitation_id VARCHAR(64) NOT NULL REFERENCES system_share_invitations(invitation_id) ON DELETE CASCADE,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    PRIMARY KEY (invitation_id, resource, action)
+);
+`
+	if _, err := dbConn.Exec(createPermissionsSQL); err != nil {
+		return fmt.Errorf("创建system_share_invitation_permissions表失败: %v", err)
+	}
+
+	createAcceptancesSQL := `
+CREATE TABLE IF NOT EXISTS system_share_invitation_acceptances (
+    invitation_id VARCHAR(64) NOT NULL REFERENCES system_share_invitations(invitation_id) ON DELETE CASCADE,
+    user_key VARCHAR(255) NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (invitation_id, user_key)
+);
+`
+	if _, err := dbConn.Exec(createAcceptancesSQL); err != nil {
+		return fmt.Errorf("创建system_share_invitation_acceptances表失败: %v", err)
+	}
+
+	// 授权来源按授权本身记录，同一权限被多次邀请授予时保留最近一次的来源
+	createGrantsSQL := `
+CREATE TABLE IF NOT EXISTS system_invitation_grants (
+    user_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    invitation_id VARCHAR(64) NOT NULL,
+    invited_by VARCHAR(255) NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_key, tenant_key, resource, action)
+);
+`
+	if _, err := dbConn.Exec(createGrantsSQL); err != nil {
+		return fmt.Errorf("创建system_invitation_grants表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// jobManager 异步任务管理器实现
+type jobManager struct {
+	dbConn sqlx.SqlConn
+}
+
+// newJobManager 创建异步任务管理器实现
+func newJobManager(dbConn sqlx.SqlConn) (*jobManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("异步任务管理器初始化失败: %v", err)
+	}
+	return &jobManager{dbConn: dbConn}, nil
+}
+
+// CreateJob 创建执行中的任务，任务参数以 JSON 保存便于排查
+func (m *jobManager) CreateJob(ctx context.Context, operatorKey string, spec core.JobSpec, total int) (*core.Job, error) {
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", operatorKey),
+		core.RequireArg("spec.kind", spec.Kind),
+	); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("生成任务ID失败: %v", err)
+	}
+	jobID := hex.EncodeToString(buf)
+
+	encodedSpec, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("序列化任务参数失败: %v", err)
+	}
+
+	insertSQL := `
+		INSERT INTO system_jobs (job_id, kind, status, operator_key, spec, progress_total)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, insertSQL, jobID, string(spec.Kind), string(core.JobStatusRunning), operatorKey, string(encodedSpec), total); err != nil {
+		return nil, fmt.Errorf("创建异步任务失败: %v", err)
+	}
+
+	return m.GetJob(ctx, jobID)
+}
+
+// UpdateProgress 更新任务进度，返回是否已请求取消（取消请求可能来自其他实例）
+func (m *jobManager) UpdateProgress(ctx context.Context, jobID string, progress core.JobProgress) (bool, error) {
+	var cancelRequested bool
+	updateSQL := `
+		UPDATE system_jobs SET progress_done = $2, progress_total = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE job_id = $1
+		RETURNING cancel_requested
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &cancelRequested, updateSQL, jobID, progress.Done, progress.Total); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return false, core.ErrJobNotFound
+		}
+		return false, fmt.Errorf("更新任务进度失败: %v", err)
+	}
+	return cancelRequested, nil
+}
+
+// FinishJob 结束任务并保存操作结果
+func (m *jobManager) FinishJob(ctx context.Context, jobID string, status core.JobStatus, result any, errMessage string) error {
+	var encodedResult any
+	if result != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("序列化任务结果失败: %v", err)
+		}
+		encodedResult = string(encoded)
+	}
+
+	var message any
+	if errMessage != "" {
+		message = errMessage
+	}
+
+	updateSQL := `
+		UPDATE system_jobs
+		SET status = $2, result = $3, error_message = $4, updated_at = CURRENT_TIMESTAMP, finished_at = CURRENT_TIMESTAMP
+		WHERE job_id = $1
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, updateSQL, jobID, string(status), encodedResult, message); err != nil {
+		return fmt.Errorf("结束异步任务失败: %v", err)
+	}
+	return nil
+}
+
+// GetJob 获取任务状态
+func (m *jobManager) GetJob(ctx context.Context, jobID string) (*core.Job, error) {
+	if err := core.RequireArg("jobID", jobID); err != nil {
+		return nil, err
+	}
+
+	var record jobRecord
+	selectSQL := `
+		SELECT job_id, kind, status, operator_key, progress_done, progress_total, result, error_message,
+		       cancel_requested, created_at, updated_at, finished_at
+		FROM system_jobs WHERE job_id = $1
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, jobID); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrJobNotFound
+		}
+		return nil, fmt.Errorf("获取异步任务失败: %v", err)
+	}
+
+	return toJob(record), nil
+}
+
+// RequestCancel 请求取消任务，执行中的任务在处理下一项前停止
+func (m *jobManager) RequestCancel(ctx context.Context, jobID string) (*core.Job, error) {
+	if err := core.RequireArg("jobID", jobID); err != nil {
+		return nil, err
+	}
+
+	updateSQL := `
+		UPDATE system_jobs SET cancel_requested = TRUE, updated_at = CURRENT_TIMESTAMP
+		WHERE job_id = $1 AND status = $2
+	`
+	result, err := m.dbConn.ExecCtx(ctx, updateSQL, jobID, string(core.JobStatusRunning))
+	if err != nil {
+		return nil, fmt.Errorf("取消异步任务失败: %v", err)
+	}
+
+	job, err := m.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return job, core.ErrJobFinished
+	}
+	return job, nil
+}
+
+// toJob 转换任务记录
+func toJob(record jobRecord) *core.Job {
+	job := &core.Job{
+		ID:              record.JobID,
+		Kind:            core.JobKind(record.Kind),
+		Status:          core.JobStatus(record.Status),
+		OperatorKey:     record.OperatorKey,
+		Progress:        core.JobProgress{Done: record.ProgressDone, Total: record.ProgressTotal},
+		Error:           record.ErrorMessage.String,
+		CancelRequested: record.CancelRequested,
+		CreatedAt:       record.CreatedAt,
+		UpdatedAt:       record.UpdatedAt,
+	}
+	if record.Result.Valid {
+		job.Result = json.RawMessage(record.Result.String)
+	}
+	if record.FinishedAt.Valid {
+		job.FinishedAt = record.FinishedAt.Time
+	}
+	return job
+}
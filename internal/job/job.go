@@ -0,0 +1,23 @@
+package job
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 异步任务管理器接口（只负责任务状态的持久化，任务由 engine 执行）
+type Manager interface {
+	CreateJob(ctx context.Context, operatorKey string, spec core.JobSpec, total int) (*core.Job, error)            // 创建执行中的任务
+	UpdateProgress(ctx context.Context, jobID string, progress core.JobProgress) (cancelRequested bool, err error) // 更新任务进度，返回是否已请求取消
+	FinishJob(ctx context.Context, jobID string, status core.JobStatus, result any, errMessage string) error       // 结束任务并保存操作结果
+	GetJob(ctx context.Context, jobID string) (*core.Job, error)                                                   // 获取任务状态
+	RequestCancel(ctx context.Context, jobID string) (*core.Job, error)                                            // 请求取消任务，任务已结束时返回 ErrJobFinished
+}
+
+// NewManager 创建异步任务管理器
+func NewManager(dbConn sqlx.SqlConn) (Manager, error) {
+	return newJobManager(dbConn)
+}
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// jobRecord 异步任务记录
+type jobRecord struct {
+	JobID           string         `db:"job_id"`
+	Kind            string         `db:"kind"`
+	Status          string         `db:"status"`
+	OperatorKey     string         `db:"operator_key"`
+	ProgressDone    int            `db:"progress_done"`
+	ProgressTotal   int            `db:"progress_total"`
+	Result          sql.NullString `db:"result"`
+	ErrorMessage    sql.NullString `db:"error_message"`
+	CancelRequested bool           `db:"cancel_requested"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+	FinishedAt      sql.NullTime   `db:"finished_at"`
+}
+
+// initDB 初始化数据库，创建异步任务表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_jobs (
+    job_id VARCHAR(64) PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    operator_key VARCHAR(255) NOT NULL,
+    spec TEXT NOT NULL,
+    progress_done INTEGER NOT NULL DEFAULT 0,
+    progress_total INTEGER NOT NULL DEFAULT 0,
+    result TEXT,
+    error_message TEXT,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_jobs_operator_key ON system_jobs(operator_key);
+`
+	if _, err := dbConn.Exec(createTableSQL); err != nil {
+		return fmt.Errorf("创建system_jobs表失败: %v", err)
+	}
+
+	return nil
+}
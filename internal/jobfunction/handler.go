@@ -0,0 +1,309 @@
+package jobfunction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// jobFunctionManager 岗位目录管理器实现
+type jobFunctionManager struct {
+	dbConn sqlx.SqlConn
+}
+
+// newJobFunctionManager 创建岗位目录管理器实现
+func newJobFunctionManager(dbConn sqlx.SqlConn) (*jobFunctionManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("岗位目录管理器初始化失败: %v", err)
+	}
+
+	return &jobFunctionManager{dbConn: dbConn}, nil
+}
+
+// SetJobFunction 创建或替换岗位，角色列表去重后保存
+func (m *jobFunctionManager) SetJobFunction(ctx context.Context, operatorKey string, jobFunction core.JobFunction) error {
+	if err := core.FirstError(
+		core.RequireArg("jobFunction.tenantKey", jobFunction.TenantKey),
+		core.RequireArg("jobFunction.jobCode", jobFunction.JobCode),
+		core.RequireItems("jobFunction.roleKeys", jobFunction.RoleKeys),
+	); err != nil {
+		return err
+	}
+	for _, roleKey := range jobFunction.RoleKeys {
+		if err := core.FirstError(
+			core.RequireArg("jobFunction.roleKeys", roleKey),
+			core.CheckArg(!strings.Contains(roleKey, ","), "jobFunction.roleKeys", core.ConstraintFormat),
+		); err != nil {
+			return err
+		}
+	}
+
+	upsertSQL := `
+		INSERT INTO system_job_functions (tenant_key, job_code, name, role_keys, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_key, job_code)
+		DO UPDATE SET name = EXCLUDED.name, role_keys = EXCLUDED.role_keys, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, upsertSQL, jobFunction.TenantKey, jobFunction.JobCode, jobFunction.Name,
+		joinKeys(dedupe(jobFunction.RoleKeys)), operatorKey); err != nil {
+		return fmt.Errorf("保存岗位失败: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteJobFunction 删除岗位
+func (m *jobFunctionManager) DeleteJobFunction(ctx context.Context, tenantKey, jobCode string) error {
+	if err := core.FirstError(
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireArg("jobCode", jobCode),
+	); err != nil {
+		return err
+	}
+
+	var users int
+	countSQL := `SELECT COUNT(*) FROM system_user_job_functions WHERE tenant_key = $1 AND job_code = $2`
+	if err := m.dbConn.QueryRowCtx(ctx, &users, countSQL, tenantKey, jobCode); err != nil {
+		return fmt.Errorf("统计岗位用户失败: %v", err)
+	}
+	if users > 0 {
+		return core.ErrJobFunctionInUse
+	}
+
+	result, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_job_functions WHERE tenant_key = $1 AND job_code = $2`, tenantKey, jobCode)
+	if err != nil {
+		return fmt.Errorf("删除岗位失败: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return core.ErrJobFunctionNotFound
+	}
+
+	return nil
+}
+
+// GetJobFunction 获取岗位
+func (m *jobFunctionManager) GetJobFunction(ctx context.Context, tenantKey, jobCode string) (*core.JobFunction, error) {
+	if err := core.FirstError(
+		core.RequireArg("tenantKey", tenantKey),
+		core.RequireArg("jobCode", jobCode),
+	); err != nil {
+		return nil, err
+	}
+
+	var record jobFunctionRecord
+	selectSQL := `
+		SELECT tenant_key, job_code, name, role_keys, updated_by, updated_at
+		FROM system_job_functions WHERE tenant_key = $1 AND job_code = $2
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, tenantKey, jobCode); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrJobFunctionNotFound
+		}
+		return nil, fmt.Errorf("查询岗位失败: %v", err)
+	}
+
+	return toJobFunction(record), nil
+}
+
+// ListJobFunctions 获取租户的所有岗位
+func (m *jobFunctionManager) ListJobFunctions(ctx context.Context, tenantKey string) ([]*core.JobFunction, error) {
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	var records []jobFunctionRecord
+	selectSQL := `
+		SELECT tenant_key, job_code, name, role_keys, updated_by, updated_at
+		FROM system_job_functions WHERE tenant_key = $1
+		ORDER BY job_code
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, tenantKey); err != nil {
+		return nil, fmt.Errorf("查询岗位列表失败: %v", err)
+	}
+
+	jobFunctions := make([]*core.JobFunction, 0, len(records))
+	for _, record := range records {
+		jobFunctions = append(jobFunctions, toJobFunction(record))
+	}
+	return jobFunctions, nil
+}
+
+// GetUserJobFunction 获取用户在租户内当前同步的岗位
+func (m *jobFunctionManager) GetUserJobFunction(ctx context.Context, userKey, tenantKey string) (*core.UserJobFunction, error) {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return nil, err
+	}
+
+	var record userJobFunctionRecord
+	selectSQL := `
+		SELECT user_key, tenant_key, job_code, managed_roles, synced_by, synced_at
+		FROM system_user_job_functions WHERE user_key = $1 AND tenant_key = $2
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, userKey, tenantKey); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询用户岗位失败: %v", err)
+	}
+
+	return &core.UserJobFunction{
+		UserKey:      record.UserKey,
+		TenantKey:    record.TenantKey,
+		JobCode:      record.JobCode,
+		ManagedRoles: splitKeys(record.ManagedRoles),
+		SyncedBy:     record.SyncedBy,
+		SyncedAt:     record.SyncedAt,
+	}, nil
+}
+
+// SaveUserJobFunction 保存用户的岗位同步结果
+func (m *jobFunctionManager) SaveUserJobFunction(ctx context.Context, record core.UserJobFunction) error {
+	if err := core.FirstError(
+		core.RequireArg("record.userKey", record.UserKey),
+		core.RequireArg("record.tenantKey", record.TenantKey),
+	); err != nil {
+		return err
+	}
+
+	// 离职：不再由岗位管理任何角色
+	if record.JobCode == "" {
+		deleteSQL := `DELETE FROM system_user_job_functions WHERE user_key = $1 AND tenant_key = $2`
+		if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, record.UserKey, record.TenantKey); err != nil {
+			return fmt.Errorf("删除用户岗位失败: %v", err)
+		}
+		return nil
+	}
+
+	upsertSQL := `
+		INSERT INTO system_user_job_functions (user_key, tenant_key, job_code, managed_roles, synced_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_key, tenant_key)
+		DO UPDATE SET job_code = EXCLUDED.job_code, managed_roles = EXCLUDED.managed_roles, synced_by = EXCLUDED.synced_by, synced_at = CURRENT_TIMESTAMP
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, upsertSQL, record.UserKey, record.TenantKey, record.JobCode,
+		joinKeys(record.ManagedRoles), record.SyncedBy); err != nil {
+		return fmt.Errorf("保存用户岗位失败: %v", err)
+	}
+
+	return nil
+}
+
+// RenameUser 将用户的岗位记录迁移到新标识
+func (m *jobFunctionManager) RenameUser(ctx context.Context, oldKey, newKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldKey", oldKey),
+		core.RequireArg("newKey", newKey),
+	); err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE system_user_job_functions SET user_key = $2 WHERE user_key = $1`
+	if _, err := m.dbConn.ExecCtx(ctx, updateSQL, oldKey, newKey); err != nil {
+		return fmt.Errorf("迁移用户岗位失败: %v", err)
+	}
+	return nil
+}
+
+// DeleteUser 删除用户在所有租户的岗位记录
+func (m *jobFunctionManager) DeleteUser(ctx context.Context, userKey string) error {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return err
+	}
+
+	if _, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_user_job_functions WHERE user_key = $1`, userKey); err != nil {
+		return fmt.Errorf("删除用户岗位失败: %v", err)
+	}
+	return nil
+}
+
+// RenameDomain 将旧租户的岗位目录和用户岗位迁移到新租户，新租户已有的岗位和用户岗位保留
+func (m *jobFunctionManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		statements := []string{
+			`DELETE FROM system_job_functions o
+			 WHERE o.tenant_key = $1
+			   AND EXISTS (SELECT 1 FROM system_job_functions n WHERE n.tenant_key = $2 AND n.job_code = o.job_code)`,
+			`UPDATE system_job_functions SET tenant_key = $2 WHERE tenant_key = $1`,
+			`DELETE FROM system_user_job_functions o
+			 WHERE o.tenant_key = $1
+			   AND EXISTS (SELECT 1 FROM system_user_job_functions n WHERE n.tenant_key = $2 AND n.user_key = o.user_key)`,
+			`UPDATE system_user_job_functions SET tenant_key = $2 WHERE tenant_key = $1`,
+		}
+		for _, statement := range statements {
+			if _, err := session.ExecCtx(ctx, statement, oldDomain, newDomain); err != nil {
+				return fmt.Errorf("迁移岗位目录失败: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDomain 删除租户的岗位目录和用户岗位
+func (m *jobFunctionManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, `DELETE FROM system_user_job_functions WHERE tenant_key = $1`, domain); err != nil {
+			return fmt.Errorf("删除租户用户岗位失败: %v", err)
+		}
+		if _, err := session.ExecCtx(ctx, `DELETE FROM system_job_functions WHERE tenant_key = $1`, domain); err != nil {
+			return fmt.Errorf("删除租户岗位目录失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// toJobFunction 转换岗位记录
+func toJobFunction(record jobFunctionRecord) *core.JobFunction {
+	return &core.JobFunction{
+		TenantKey: record.TenantKey,
+		JobCode:   record.JobCode,
+		Name:      record.Name,
+		RoleKeys:  splitKeys(record.RoleKeys),
+		UpdatedBy: record.UpdatedBy,
+		UpdatedAt: record.UpdatedAt,
+	}
+}
+
+// joinKeys 将角色列表编码为逗号分隔的字符串
+func joinKeys(keys []string) string {
+	return strings.Join(keys, ",")
+}
+
+// splitKeys 解析逗号分隔的角色列表
+func splitKeys(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// dedupe 按首次出现的顺序去除重复项
+func dedupe(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package jobfunction
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 岗位目录管理器接口
+type Manager interface {
+	// 岗位目录
+	SetJobFunction(ctx context.Context, operatorKey string, jobFunction core.JobFunction) error // 创建或替换岗位
+	DeleteJobFunction(ctx context.Context, tenantKey, jobCode string) error                     // 删除岗位，仍有用户时返回 ErrJobFunctionInUse
+	GetJobFunction(ctx context.Context, tenantKey, jobCode string) (*core.JobFunction, error)   // 获取岗位，不存在时返回 ErrJobFunctionNotFound
+	ListJobFunctions(ctx context.Context, tenantKey string) ([]*core.JobFunction, error)        // 获取租户的所有岗位（按岗位编码排序）
+
+	// 用户岗位
+	GetUserJobFunction(ctx context.Context, userKey, tenantKey string) (*core.UserJobFunction, error) // 获取用户在租户内当前同步的岗位，未同步时返回 nil
+	SaveUserJobFunction(ctx context.Context, record core.UserJobFunction) error                       // 保存用户的岗位同步结果，岗位编码为空时删除记录
+
+	// 用户和租户变更
+	RenameUser(ctx context.Context, oldKey, newKey string) error         // 将用户的岗位记录迁移到新标识
+	DeleteUser(ctx context.Context, userKey string) error                // 删除用户在所有租户的岗位记录
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error // 将租户的岗位目录和用户岗位迁移到新租户，新租户已有的岗位保留
+	DeleteDomain(ctx context.Context, domain string) error               // 删除租户的岗位目录和用户岗位
+}
+
+// NewManager 创建岗位目录管理器
+func NewManager(dbConn sqlx.SqlConn) (Manager, error) {
+	return newJobFunctionManager(dbConn)
+}
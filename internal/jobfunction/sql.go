@@ -0,0 +1,63 @@
+package jobfunction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// jobFunctionRecord 岗位记录
+type jobFunctionRecord struct {
+	TenantKey string    `db:"tenant_key"`
+	JobCode   string    `db:"job_code"`
+	Name      string    `db:"name"`
+	RoleKeys  string    `db:"role_keys"`
+	UpdatedBy string    `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// userJobFunctionRecord 用户岗位记录
+type userJobFunctionRecord struct {
+	UserKey      string    `db:"user_key"`
+	TenantKey    string    `db:"tenant_key"`
+	JobCode      string    `db:"job_code"`
+	ManagedRoles string    `db:"managed_roles"`
+	SyncedBy     string    `db:"synced_by"`
+	SyncedAt     time.Time `db:"synced_at"`
+}
+
+// initDB 初始化数据库，创建岗位目录表和用户岗位表
+// 角色列表以逗号分隔存储
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_job_functions (
+    tenant_key VARCHAR(255) NOT NULL,
+    job_code VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    role_keys TEXT NOT NULL,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_key, job_code)
+);
+
+CREATE TABLE IF NOT EXISTS system_user_job_functions (
+    user_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    job_code VARCHAR(255) NOT NULL,
+    managed_roles TEXT NOT NULL DEFAULT '',
+    synced_by VARCHAR(255) NOT NULL,
+    synced_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_key, tenant_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_user_job_functions_job ON system_user_job_functions(tenant_key, job_code);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建岗位目录表失败: %v", err)
+	}
+
+	return nil
+}
@@ -1,28 +1,39 @@
 package policy
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // policyManager 策略管理器实现
 type policyManager struct {
 	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
 }
 
 // newPolicyManager 创建策略管理器实现
-func newPolicyManager(enforcer *core.Enforcer) (*policyManager, error) {
+func newPolicyManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*policyManager, error) {
 	if enforcer == nil {
 		return nil, fmt.Errorf("核心执行器未初始化")
 	}
+
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("策略管理器初始化失败: %v", err)
+	}
+
 	return &policyManager{
 		enforcer: enforcer,
+		dbConn:   dbConn,
 	}, nil
 }
 
 // RefreshPolicy 手动刷新策略（从数据库重新加载）
-func (p *policyManager) RefreshPolicy() error {
+func (p *policyManager) RefreshPolicy(ctx context.Context) error {
 	if p.enforcer == nil {
 		return fmt.Errorf("核心执行器未初始化")
 	}
@@ -34,3 +45,36 @@ func (p *policyManager) RefreshPolicy() error {
 
 	return nil
 }
+
+// ListPolicies 获取租户内的权限策略
+func (p *policyManager) ListPolicies(ctx context.Context, tenantKey string) ([]core.Policy, error) {
+	policies, err := p.enforcer.GetPolicies("", tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]core.Policy, 0, len(policies))
+	for _, policy := range policies {
+		// 跳过角色占位策略
+		if policy.Resource == core.ResourcePlaceholder {
+			continue
+		}
+		filtered = append(filtered, policy)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Action < b.Action
+	})
+
+	return filtered, nil
+}
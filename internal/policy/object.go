@@ -0,0 +1,17 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// CleanupObject 删除所有租户中对象（含子资源）的权限策略，并执行对象清理回调
+func (p *policyManager) CleanupObject(ctx context.Context, resourceType, objectID string) (*core.ObjectCleanupResult, error) {
+	return p.enforcer.CleanupObject(ctx, resourceType, objectID)
+}
+
+// AddObjectCleanupHook 注册对象清理回调
+func (p *policyManager) AddObjectCleanupHook(ctx context.Context, hook core.ObjectCleanupHook) {
+	p.enforcer.AddObjectCleanupHook(hook)
+}
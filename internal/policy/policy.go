@@ -1,16 +1,35 @@
 package policy
 
 import (
+	"context"
+
 	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // Manager 策略管理器接口
 type Manager interface {
 	// RefreshPolicy 手动刷新策略（从数据库重新加载）
-	RefreshPolicy() error
+	RefreshPolicy(ctx context.Context) error
+
+	// ListPolicies 获取租户内的权限策略（按租户、主体、资源、操作排序，不含角色占位策略）
+	ListPolicies(ctx context.Context, tenantKey string) ([]core.Policy, error)
+
+	// PreviewResourceRevocation 预览按资源批量撤销会影响的策略、用户和角色
+	PreviewResourceRevocation(ctx context.Context, tenantKey string, resource core.Resource) (*core.ResourceRevocation, error)
+
+	// RevokeResource 在一个批次中撤销租户内所有主体对资源的授权，并记录撤销结果
+	RevokeResource(ctx context.Context, operatorKey, tenantKey string, resource core.Resource) (*core.ResourceRevocation, error)
+
+	// CleanupObject 删除所有租户中对象（含子资源）的权限策略，并执行对象清理回调
+	CleanupObject(ctx context.Context, resourceType, objectID string) (*core.ObjectCleanupResult, error)
+
+	// AddObjectCleanupHook 注册对象清理回调
+	AddObjectCleanupHook(ctx context.Context, hook core.ObjectCleanupHook)
 }
 
 // NewManager 创建策略管理器
-func NewManager(enforcer *core.Enforcer) (Manager, error) {
-	return newPolicyManager(enforcer)
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newPolicyManager(dbConn, enforcer)
 }
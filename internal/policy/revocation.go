@@ -0,0 +1,128 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// PreviewResourceRevocation 预览按资源批量撤销会影响的策略和主体
+func (p *policyManager) PreviewResourceRevocation(ctx context.Context, tenantKey string, resource core.Resource) (*core.ResourceRevocation, error) {
+	if err := validateRevocationParams(tenantKey, resource); err != nil {
+		return nil, err
+	}
+
+	policies, err := p.enforcer.GetPolicies("", tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var affected []core.Policy
+	for _, policy := range policies {
+		if policy.Resource == resource {
+			affected = append(affected, policy)
+		}
+	}
+
+	return p.buildRevocation(ctx, tenantKey, resource, affected)
+}
+
+// RevokeResource 在一个批次中撤销租户内所有主体对资源的授权，并记录撤销结果
+func (p *policyManager) RevokeResource(ctx context.Context, operatorKey, tenantKey string, resource core.Resource) (*core.ResourceRevocation, error) {
+	if err := validateRevocationParams(tenantKey, resource); err != nil {
+		return nil, err
+	}
+
+	removed, err := p.enforcer.RemoveResourcePolicies(ctx, tenantKey, resource)
+	if err != nil {
+		return nil, fmt.Errorf("批量撤销资源授权失败: %v", err)
+	}
+
+	revocation, err := p.buildRevocation(ctx, tenantKey, resource, removed)
+	if err != nil {
+		return nil, err
+	}
+	revocation.Applied = true
+	revocation.RevokedBy = operatorKey
+	revocation.RevokedAt = time.Now()
+
+	insertSQL := `
+		INSERT INTO system_resource_revocations (tenant_key, resource, revoked_policies, affected_users, affected_roles, revoked_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	err = p.dbConn.QueryRowCtx(ctx, &revocation.ID, insertSQL, tenantKey, string(resource), len(removed),
+		strings.Join(revocation.AffectedUsers, ","), strings.Join(revocation.AffectedRoles, ","), operatorKey)
+	if err != nil {
+		// 策略已撤销，记录失败不回滚撤销结果
+		return revocation, fmt.Errorf("记录资源撤销结果失败: %v", err)
+	}
+
+	return revocation, nil
+}
+
+// buildRevocation 汇总受影响的用户和角色
+func (p *policyManager) buildRevocation(ctx context.Context, tenantKey string, resource core.Resource, policies []core.Policy) (*core.ResourceRevocation, error) {
+	revocation := &core.ResourceRevocation{
+		TenantKey:     tenantKey,
+		Resource:      resource,
+		Policies:      []core.Policy{},
+		AffectedUsers: []string{},
+		AffectedRoles: []string{},
+	}
+
+	seenUsers := make(map[string]bool)
+	seenRoles := make(map[string]bool)
+	for _, policy := range policies {
+		revocation.Policies = append(revocation.Policies, policy)
+
+		isRole, err := p.isRoleSubject(ctx, policy)
+		if err != nil {
+			return nil, err
+		}
+		if isRole {
+			if !seenRoles[policy.Subject] {
+				seenRoles[policy.Subject] = true
+				revocation.AffectedRoles = append(revocation.AffectedRoles, policy.Subject)
+			}
+		} else if !seenUsers[policy.Subject] {
+			seenUsers[policy.Subject] = true
+			revocation.AffectedUsers = append(revocation.AffectedUsers, policy.Subject)
+		}
+	}
+
+	sort.Strings(revocation.AffectedUsers)
+	sort.Strings(revocation.AffectedRoles)
+	return revocation, nil
+}
+
+// isRoleSubject 判断策略主体是否为角色
+// 启用主体命名空间时直接使用主体类型，否则查询角色表
+func (p *policyManager) isRoleSubject(ctx context.Context, policy core.Policy) (bool, error) {
+	if policy.SubjectKind != "" {
+		return policy.SubjectKind == core.SubjectKindRole, nil
+	}
+
+	var count int
+	countSQL := `SELECT COUNT(*) FROM system_roles WHERE role_key = $1`
+	if err := p.dbConn.QueryRowCtx(ctx, &count, countSQL, policy.Subject); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// validateRevocationParams 验证批量撤销参数
+func validateRevocationParams(tenantKey string, resource core.Resource) error {
+	if err := core.FirstError(
+		core.RequireTenantArg("tenantKey", tenantKey),
+		core.RequireArg("resource", resource),
+		core.CheckArg(resource != core.ResourcePlaceholder, "resource", core.ConstraintFormat),
+	); err != nil {
+		return err
+	}
+	return nil
+}
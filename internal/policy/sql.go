@@ -0,0 +1,32 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// initDB 初始化数据库，创建资源批量撤销记录表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_resource_revocations (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    revoked_policies INT NOT NULL DEFAULT 0,
+    affected_users TEXT NOT NULL DEFAULT '',
+    affected_roles TEXT NOT NULL DEFAULT '',
+    revoked_by VARCHAR(255) NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_resource_revocations_tenant ON system_resource_revocations(tenant_key, resource);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_resource_revocations表失败: %v", err)
+	}
+
+	return nil
+}
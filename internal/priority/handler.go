@@ -0,0 +1,298 @@
+package priority
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// reorderStep 重新排序时相邻规则的优先级间隔，便于之后在中间插入规则
+const reorderStep = 10
+
+// priorityManager 规则优先级管理器实现
+type priorityManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu         sync.RWMutex
+	priorities map[string]int // 策略键 -> 优先级
+}
+
+// newPriorityManager 创建规则优先级管理器实现
+func newPriorityManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*priorityManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("规则优先级管理器初始化失败: %v", err)
+	}
+
+	manager := &priorityManager{
+		enforcer:   enforcer,
+		dbConn:     dbConn,
+		priorities: make(map[string]int),
+	}
+
+	if err := manager.loadPriorities(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新优先级，并为权限检查提供规则优先级
+	enforcer.AddReloadHook(func() error { return manager.loadPriorities(context.Background()) })
+	enforcer.SetRulePriorityResolver(manager.resolvePriority)
+	enforcer.AddObjectCleanupHook(manager.cleanupObject)
+
+	return manager, nil
+}
+
+// SetPolicyPriority 设置规则优先级
+func (m *priorityManager) SetPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission, priority int) error {
+	subject, err := m.ruleSubject(kind, subjectKey, domain, permission)
+	if err != nil {
+		return err
+	}
+
+	if err := upsertPriority(ctx, m.dbConn, subject, domain, permission, priority); err != nil {
+		return err
+	}
+
+	return m.sync(ctx)
+}
+
+// ClearPolicyPriority 清除规则优先级
+func (m *priorityManager) ClearPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) error {
+	subject := m.storedSubject(kind, subjectKey)
+
+	// 未设置优先级的规则无需清理，避免无意义的同步通知
+	m.mu.RLock()
+	_, exists := m.priorities[policyKey(subject, domain, string(permission.QualifiedResource()), string(permission.Action))]
+	m.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	deleteSQL := `DELETE FROM casbin_policy_priorities WHERE subject = $1 AND domain = $2 AND resource = $3 AND action = $4`
+	if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, subject, domain, string(permission.QualifiedResource()), string(permission.Action)); err != nil {
+		return fmt.Errorf("清除规则优先级失败: %v", err)
+	}
+
+	return m.sync(ctx)
+}
+
+// GetPolicyPriority 获取规则单独设置的优先级，未设置时返回 false
+func (m *priorityManager) GetPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) (int, bool) {
+	subject := m.storedSubject(kind, subjectKey)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	priority, ok := m.priorities[policyKey(subject, domain, string(permission.QualifiedResource()), string(permission.Action))]
+	return priority, ok
+}
+
+// ReorderPolicies 按给定顺序重新设置规则优先级，排在前面的规则优先级更高
+// 所有规则在一个事务中更新；未列出的规则保持原有优先级
+func (m *priorityManager) ReorderPolicies(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permissions []core.Permission) error {
+	if err := core.RequireItems("permissions", permissions); err != nil {
+		return err
+	}
+
+	seen := make(map[core.Permission]bool, len(permissions))
+	var subject string
+	for _, permission := range permissions {
+		if seen[permission] {
+			return fmt.Errorf("重新排序的权限重复: %s", permission.String())
+		}
+		seen[permission] = true
+
+		ruleSubject, err := m.ruleSubject(kind, subjectKey, domain, permission)
+		if err != nil {
+			return err
+		}
+		subject = ruleSubject
+	}
+
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for i, permission := range permissions {
+			if err := upsertPriority(ctx, session, subject, domain, permission, (i+1)*reorderStep); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.sync(ctx)
+}
+
+// RenameDomain 将旧域下的所有规则优先级迁移到新域，新域已有的规则优先级保留
+func (m *priorityManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	var affected int64
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteSQL := `
+			DELETE FROM casbin_policy_priorities o
+			WHERE o.domain = $1
+			  AND EXISTS (
+			    SELECT 1 FROM casbin_policy_priorities n
+			    WHERE n.domain = $2 AND n.subject = o.subject AND n.resource = o.resource AND n.action = o.action
+			  )
+		`
+		if _, err := session.ExecCtx(ctx, deleteSQL, oldDomain, newDomain); err != nil {
+			return fmt.Errorf("清理重复的规则优先级失败: %v", err)
+		}
+
+		updateSQL := `UPDATE casbin_policy_priorities SET domain = $2, updated_at = CURRENT_TIMESTAMP WHERE domain = $1`
+		result, err := session.ExecCtx(ctx, updateSQL, oldDomain, newDomain)
+		if err != nil {
+			return fmt.Errorf("迁移规则优先级失败: %v", err)
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// 没有需要迁移的记录时无需同步
+	if affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// DeleteDomain 删除域下的所有规则优先级
+func (m *priorityManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	deleteSQL := `DELETE FROM casbin_policy_priorities WHERE domain = $1`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, domain)
+	if err != nil {
+		return fmt.Errorf("删除域规则优先级失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// cleanupObject 对象清理回调：删除对象（含子资源）的规则优先级
+func (m *priorityManager) cleanupObject(ctx context.Context, resourceType, objectID string, removed []core.Policy) error {
+	objectResource := string(core.ObjectResource(resourceType, objectID))
+	deleteSQL := `DELETE FROM casbin_policy_priorities WHERE resource = $1 OR starts_with(resource, $1 || ':')`
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, objectResource)
+	if err != nil {
+		return fmt.Errorf("清理对象规则优先级失败: %v", err)
+	}
+
+	// 没有相关记录时无需同步
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return nil
+	}
+
+	return m.sync(ctx)
+}
+
+// ruleSubject 校验规则存在并返回存储中的主体标识
+func (m *priorityManager) ruleSubject(kind core.SubjectKind, subjectKey, domain string, permission core.Permission) (string, error) {
+	if err := core.FirstError(
+		core.RequireArg("subjectKey", subjectKey),
+		core.RequireArg("permission.resource", permission.Resource),
+		core.RequireArg("permission.action", permission.Action),
+	); err != nil {
+		return "", err
+	}
+
+	subject := m.storedSubject(kind, subjectKey)
+	exists, err := m.enforcer.HasPolicy(subject, domain, permission)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", core.ErrPermissionNotFound
+	}
+	return subject, nil
+}
+
+// storedSubject 生成存储中的主体标识
+func (m *priorityManager) storedSubject(kind core.SubjectKind, subjectKey string) string {
+	if kind == core.SubjectKindRole {
+		return m.enforcer.RoleSubject(subjectKey)
+	}
+	return m.enforcer.UserSubject(subjectKey)
+}
+
+// resolvePriority 规则优先级解析器：返回规则显式设置的优先级
+func (m *priorityManager) resolvePriority(rule core.Policy) (int, bool) {
+	subject := rule.Subject
+	if rule.SubjectKind != "" {
+		subject = core.EncodeSubject(rule.SubjectKind, rule.Subject)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	priority, ok := m.priorities[policyKey(subject, rule.Domain, string(rule.Resource), string(rule.Action))]
+	return priority, ok
+}
+
+// loadPriorities 从数据库加载规则优先级到内存
+func (m *priorityManager) loadPriorities(ctx context.Context) error {
+	var records []*policyPriorityRecord
+	selectSQL := `SELECT subject, domain, resource, action, priority FROM casbin_policy_priorities`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载规则优先级失败: %v", err)
+	}
+
+	priorities := make(map[string]int, len(records))
+	for _, record := range records {
+		priorities[policyKey(record.Subject, record.Domain, record.Resource, record.Action)] = record.Priority
+	}
+
+	m.mu.Lock()
+	m.priorities = priorities
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地优先级并通知其他实例
+func (m *priorityManager) sync(ctx context.Context) error {
+	if err := m.loadPriorities(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// upsertPriority 写入规则优先级
+func upsertPriority(ctx context.Context, session sqlx.Session, subject, domain string, permission core.Permission, priority int) error {
+	upsertSQL := `
+		INSERT INTO casbin_policy_priorities (subject, domain, resource, action, priority)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (subject, domain, resource, action) DO UPDATE
+		SET priority = EXCLUDED.priority, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := session.ExecCtx(ctx, upsertSQL, subject, domain, string(permission.QualifiedResource()), string(permission.Action), priority); err != nil {
+		return fmt.Errorf("设置规则优先级失败: %v", err)
+	}
+	return nil
+}
+
+// policyKey 生成策略键
+func policyKey(subject, domain, resource, action string) string {
+	return strings.Join([]string{subject, domain, resource, action}, "\x00")
+}
@@ -0,0 +1,24 @@
+package priority
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 规则优先级管理器接口
+type Manager interface {
+	SetPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission, priority int) error // 设置规则优先级（数值越小越优先）
+	ClearPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) error             // 清除规则优先级，恢复默认优先级
+	GetPolicyPriority(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permission core.Permission) (int, bool)         // 获取规则单独设置的优先级，未设置时返回 false
+	ReorderPolicies(ctx context.Context, kind core.SubjectKind, subjectKey, domain string, permissions []core.Permission) error              // 按给定顺序重新设置规则优先级
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error                                                                     // 将域的所有规则优先级迁移到新域
+	DeleteDomain(ctx context.Context, domain string) error                                                                                   // 删除域的所有规则优先级
+}
+
+// NewManager 创建规则优先级管理器
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newPriorityManager(dbConn, enforcer)
+}
@@ -0,0 +1,38 @@
+package priority
+
+import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// policyPriorityRecord 规则优先级记录
+type policyPriorityRecord struct {
+	Subject  string `db:"subject"`
+	Domain   string `db:"domain"`
+	Resource string `db:"resource"`
+	Action   string `db:"action"`
+	Priority int    `db:"priority"`
+}
+
+// initDB 初始化数据库，创建规则优先级表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_policy_priorities (
+    subject VARCHAR(255) NOT NULL,
+    domain VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    priority INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subject, domain, resource, action)
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_policy_priorities表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package quarantine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// quarantineManager 隔离策略行管理器实现
+type quarantineManager struct {
+	dbConn sqlx.SqlConn
+}
+
+// newQuarantineManager 创建隔离策略行管理器实现
+func newQuarantineManager(dbConn sqlx.SqlConn) (*quarantineManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("隔离策略行管理器初始化失败: %v", err)
+	}
+	return &quarantineManager{dbConn: dbConn}, nil
+}
+
+// Record 按一次加载结果更新隔离表
+// 仍然无效的行保留首次发现时间，本次加载未再出现的行视为已修复并删除；多个实例重复写入同一结果是幂等的
+func (m *quarantineManager) Record(ctx context.Context, report core.PolicyLoadReport) error {
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		upsertSQL := `
+			INSERT INTO casbin_policy_quarantine (rule, ptype, reason, detected_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (rule) DO UPDATE
+			SET reason = EXCLUDED.reason, last_seen_at = EXCLUDED.last_seen_at
+		`
+		for _, rule := range report.Quarantined {
+			key, err := ruleKey(rule)
+			if err != nil {
+				return err
+			}
+			if _, err := session.ExecCtx(ctx, upsertSQL, key, rule.PType, rule.Reason, report.LoadedAt); err != nil {
+				return fmt.Errorf("记录隔离策略行失败: %v", err)
+			}
+		}
+
+		deleteSQL := `DELETE FROM casbin_policy_quarantine WHERE last_seen_at < $1`
+		if _, err := session.ExecCtx(ctx, deleteSQL, report.LoadedAt); err != nil {
+			return fmt.Errorf("清理已修复的隔离策略行失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// List 获取所有被隔离的策略行
+func (m *quarantineManager) List(ctx context.Context) ([]*core.QuarantinedRule, error) {
+	var records []*quarantinedRuleRecord
+	selectSQL := `SELECT rule, ptype, reason, detected_at FROM casbin_policy_quarantine ORDER BY detected_at, rule`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return nil, fmt.Errorf("查询隔离策略行失败: %v", err)
+	}
+
+	rules := make([]*core.QuarantinedRule, 0, len(records))
+	for _, record := range records {
+		var fields []string
+		if err := json.Unmarshal([]byte(record.Rule), &fields); err != nil {
+			return nil, fmt.Errorf("解析隔离策略行失败: %v", err)
+		}
+		if len(fields) > 0 {
+			fields = fields[1:]
+		}
+		rules = append(rules, &core.QuarantinedRule{
+			PType:      record.PType,
+			Rule:       fields,
+			Reason:     record.Reason,
+			DetectedAt: record.DetectedAt,
+		})
+	}
+	return rules, nil
+}
+
+// ruleKey 以 JSON 数组（ptype 加策略字段）作为隔离行的唯一键，字段中含逗号也不会冲突
+func ruleKey(rule core.QuarantinedRule) (string, error) {
+	key, err := json.Marshal(append([]string{rule.PType}, rule.Rule...))
+	if err != nil {
+		return "", fmt.Errorf("序列化隔离策略行失败: %v", err)
+	}
+	return string(key), nil
+}
@@ -0,0 +1,20 @@
+package quarantine
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 隔离策略行管理器接口
+type Manager interface {
+	Record(ctx context.Context, report core.PolicyLoadReport) error // 按一次加载结果更新隔离表（新增本次隔离的行，删除已修复的行）
+	List(ctx context.Context) ([]*core.QuarantinedRule, error)      // 获取所有被隔离的策略行，按首次发现时间排序
+}
+
+// NewManager 创建隔离策略行管理器
+func NewManager(dbConn sqlx.SqlConn) (Manager, error) {
+	return newQuarantineManager(dbConn)
+}
@@ -0,0 +1,36 @@
+package quarantine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// quarantinedRuleRecord 隔离策略行记录
+type quarantinedRuleRecord struct {
+	Rule       string    `db:"rule"`
+	PType      string    `db:"ptype"`
+	Reason     string    `db:"reason"`
+	DetectedAt time.Time `db:"detected_at"`
+}
+
+// initDB 初始化数据库，创建隔离策略行表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS casbin_policy_quarantine (
+    rule TEXT PRIMARY KEY,
+    ptype VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建casbin_policy_quarantine表失败: %v", err)
+	}
+
+	return nil
+}
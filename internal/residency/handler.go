@@ -0,0 +1,113 @@
+package residency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// residencyManager 租户区域登记管理器实现
+type residencyManager struct {
+	dbConn        sqlx.SqlConn
+	defaultRegion string
+
+	mu      sync.RWMutex
+	regions map[string]string // 租户 -> 区域（仅缓存已登记的租户，登记后不可变更）
+}
+
+// newResidencyManager 创建租户区域登记管理器实现
+func newResidencyManager(dbConn sqlx.SqlConn, defaultRegion string) (*residencyManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("租户区域管理器初始化失败: %v", err)
+	}
+
+	return &residencyManager{
+		dbConn:        dbConn,
+		defaultRegion: defaultRegion,
+		regions:       make(map[string]string),
+	}, nil
+}
+
+// AssignRegion 登记租户所属区域
+// 重复登记相同区域视为成功；变更区域意味着迁移数据，一律拒绝
+func (m *residencyManager) AssignRegion(ctx context.Context, operatorKey, tenantKey, region string) error {
+	if err := core.FirstError(
+		core.RequireTenantArg("tenantKey", tenantKey),
+		core.RequireArg("region", region),
+	); err != nil {
+		return err
+	}
+
+	insertSQL := `
+		INSERT INTO system_tenant_regions (tenant_key, region, assigned_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_key) DO NOTHING
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, insertSQL, tenantKey, region, operatorKey); err != nil {
+		return fmt.Errorf("登记租户区域失败: %v", err)
+	}
+
+	assigned, err := m.GetRegion(ctx, tenantKey)
+	if err != nil {
+		return err
+	}
+	if assigned != region {
+		return core.ErrCrossRegionOperation
+	}
+
+	return nil
+}
+
+// GetRegion 获取租户所属区域
+func (m *residencyManager) GetRegion(ctx context.Context, tenantKey string) (string, error) {
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return "", err
+	}
+
+	m.mu.RLock()
+	region, cached := m.regions[tenantKey]
+	m.mu.RUnlock()
+	if cached {
+		return region, nil
+	}
+
+	selectSQL := `SELECT region FROM system_tenant_regions WHERE tenant_key = $1`
+	if err := m.dbConn.QueryRowCtx(ctx, &region, selectSQL, tenantKey); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			// 未登记的租户可能稍后由其他实例登记，不缓存
+			return m.defaultRegion, nil
+		}
+		return "", fmt.Errorf("查询租户区域失败: %v", err)
+	}
+
+	m.mu.Lock()
+	m.regions[tenantKey] = region
+	m.mu.Unlock()
+
+	return region, nil
+}
+
+// ListTenantRegions 获取所有已登记的租户区域
+func (m *residencyManager) ListTenantRegions(ctx context.Context) ([]*core.TenantRegion, error) {
+	var records []*tenantRegionRecord
+	selectSQL := `SELECT tenant_key, region, assigned_by, assigned_at FROM system_tenant_regions ORDER BY tenant_key`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return nil, fmt.Errorf("查询租户区域失败: %v", err)
+	}
+
+	regions := make([]*core.TenantRegion, 0, len(records))
+	for _, record := range records {
+		regions = append(regions, &core.TenantRegion{
+			TenantKey:  record.TenantKey,
+			Region:     record.Region,
+			AssignedBy: record.AssignedBy,
+			AssignedAt: record.AssignedAt,
+		})
+	}
+	return regions, nil
+}
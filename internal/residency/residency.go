@@ -0,0 +1,21 @@
+package residency
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 租户区域登记管理器接口（登记表保存在默认区域）
+type Manager interface {
+	AssignRegion(ctx context.Context, operatorKey, tenantKey, region string) error // 登记租户所属区域，已登记的租户不允许变更区域
+	GetRegion(ctx context.Context, tenantKey string) (string, error)               // 获取租户所属区域，未登记的租户属于默认区域
+	ListTenantRegions(ctx context.Context) ([]*core.TenantRegion, error)           // 获取所有已登记的租户区域
+}
+
+// NewManager 创建租户区域登记管理器
+func NewManager(dbConn sqlx.SqlConn, defaultRegion string) (Manager, error) {
+	return newResidencyManager(dbConn, defaultRegion)
+}
@@ -0,0 +1,35 @@
+package residency
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// tenantRegionRecord 租户区域记录
+type tenantRegionRecord struct {
+	TenantKey  string    `db:"tenant_key"`
+	Region     string    `db:"region"`
+	AssignedBy string    `db:"assigned_by"`
+	AssignedAt time.Time `db:"assigned_at"`
+}
+
+// initDB 初始化数据库，创建租户区域登记表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_tenant_regions (
+    tenant_key VARCHAR(255) PRIMARY KEY,
+    region VARCHAR(64) NOT NULL,
+    assigned_by VARCHAR(255) NOT NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_tenant_regions表失败: %v", err)
+	}
+
+	return nil
+}
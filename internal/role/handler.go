@@ -1,7 +1,10 @@
 package role
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/rezeropoint/casbinx/core"
 
@@ -13,18 +16,22 @@ type roleManager struct {
 	enforcer          *core.Enforcer
 	dbConn            sqlx.SqlConn
 	securityValidator *core.SecurityValidator
+	cipher            *core.FieldCipher // 敏感字段加密器（角色描述、冻结原因）
+
+	sharedMu    sync.RWMutex
+	sharedLinks map[string]map[string]string // 共享角色链接：租户 -> 角色 -> 来源租户
+
+	journal atomic.Pointer[MetadataJournal] // 角色元数据变更日志（可选），记录期间的元数据写入
 }
 
 // newRoleManager 创建角色权限管理器实现
-func newRoleManager(dsn string, enforcer *core.Enforcer, securityValidator *core.SecurityValidator) (*roleManager, error) {
-	// 初始化 PostgreSQL - 使用 URL 格式的 DSN
-	dbConn := sqlx.NewSqlConn("postgres", dsn)
-
+func newRoleManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer, securityValidator *core.SecurityValidator, cipher *core.FieldCipher) (*roleManager, error) {
 	// 创建管理器实例
 	manager := &roleManager{
 		enforcer:          enforcer,
 		dbConn:            dbConn,
 		securityValidator: securityValidator,
+		cipher:            cipher,
 	}
 
 	// 启动时初始化数据库表，如果失败则返回错误，让调用者决定如何处理
@@ -32,14 +39,25 @@ func newRoleManager(dsn string, enforcer *core.Enforcer, securityValidator *core
 		return nil, fmt.Errorf("角色管理器初始化失败，数据库表创建失败: %v", err)
 	}
 
+	// 加载共享角色链接，并在策略重新加载时同步刷新
+	if err := manager.loadSharedLinks(context.Background()); err != nil {
+		return nil, err
+	}
+	enforcer.AddReloadHook(func() error { return manager.loadSharedLinks(context.Background()) })
+	enforcer.SetRoleDomainResolver(manager.resolveSharedRoleDomains)
+	enforcer.AddObjectCleanupHook(manager.restorePlaceholdersAfterCleanup)
+
 	return manager, nil
 }
 
 // CreateRole 创建自定义角色
-func (m *roleManager) CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error {
+func (m *roleManager) CreateRole(ctx context.Context, operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error {
 	// 验证参数
-	if roleKey == "" || roleName == "" {
-		return core.ErrInvalidParameter
+	if err := core.FirstError(
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("roleName", roleName),
+	); err != nil {
+		return err
 	}
 
 	// 如果tenantKey为空，默认为全局角色
@@ -48,7 +66,7 @@ func (m *roleManager) CreateRole(operatorKey, roleKey, roleName, description, te
 	}
 
 	// 检查角色是否已存在
-	exists, err := m.isRoleExists(roleKey)
+	exists, err := m.isRoleExists(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -58,46 +76,38 @@ func (m *roleManager) CreateRole(operatorKey, roleKey, roleName, description, te
 
 	// 安全检查已在engine层处理
 
-	// 创建角色元数据
-	err = m.createRoleMetadata(roleKey, roleName, description, tenantKey, operatorKey)
-	if err != nil {
+	// 角色创建分两步：写入元数据 -> 批量写入策略。
+	// 元数据与策略位于不同的存储连接，无法共用一个事务，因此采用补偿方式：
+	// 策略写入失败时删除已写入的元数据和策略，并校验补偿结果。
+	if err := m.createRoleMetadata(ctx, roleKey, roleName, description, tenantKey, operatorKey); err != nil {
 		return fmt.Errorf("创建角色元数据失败: %v", err)
 	}
 
-	// 如果没有权限，添加一个占位权限来标识角色存在
-	if len(permissions) == 0 {
-		err = m.enforcer.AddPolicy(roleKey, tenantKey, core.Permission{Resource: core.ResourcePlaceholder, Action: core.ActionNone})
-		if err != nil {
-			// 回滚角色元数据
-			m.deleteRoleMetadata(roleKey)
-			return err
-		}
-		return nil
+	// 没有实际权限时写入占位权限来标识角色存在
+	rolePermissions := core.NormalizePermissions(permissions)
+	if len(rolePermissions) == 0 {
+		rolePermissions = []core.Permission{placeholderPermission}
 	}
 
-	// 添加角色权限
-	err = m.setRolePermissionsInTenant(roleKey, tenantKey, permissions)
-	if err != nil {
-		// 回滚角色元数据
-		m.deleteRoleMetadata(roleKey)
-		return err
+	if err := m.enforcer.AddPolicies(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, rolePermissions); err != nil {
+		return m.compensateCreateRole(ctx, roleKey, err)
 	}
 
 	return nil
 }
 
 // UpdateRole 更新自定义角色
-func (m *roleManager) UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error {
+func (m *roleManager) UpdateRole(ctx context.Context, operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error {
 	// 验证参数
-	if roleKey == "" {
-		return core.ErrInvalidParameter
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return err
 	}
 
 	// tenantKey 不会为空（数据库约束 NOT NULL DEFAULT '*'）
 	// 删除不必要的空值检查
 
 	// 检查角色是否存在
-	exists, err := m.isRoleExists(roleKey)
+	exists, err := m.isRoleExists(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -106,14 +116,14 @@ func (m *roleManager) UpdateRole(operatorKey, roleKey, roleName, description, te
 	}
 
 	// 获取角色的旧权限
-	oldPermissions, err := m.GetRolePermissions(roleKey)
+	oldPermissions, err := m.GetRolePermissions(ctx, roleKey)
 	if err != nil {
 		return fmt.Errorf("获取角色旧权限失败: %v", err)
 	}
 
 	// 找出新增和删除的权限
-	addedPermissions := findAddedPermissionsInRole(oldPermissions, permissions)
-	removedPermissions := findRemovedPermissionsInRole(oldPermissions, permissions)
+	addedPermissions := core.DifferencePermissions(permissions, oldPermissions)
+	removedPermissions := core.DifferencePermissions(oldPermissions, permissions)
 
 	// 检查是否尝试新增系统权限
 	if m.hasSystemPermissionsInList(addedPermissions) {
@@ -129,25 +139,25 @@ func (m *roleManager) UpdateRole(operatorKey, roleKey, roleName, description, te
 
 	// 更新角色元数据
 	if roleName != "" || description != "" {
-		err = m.updateRoleMetadata(roleKey, roleName, description)
+		err = m.updateRoleMetadata(ctx, roleKey, roleName, description)
 		if err != nil {
 			return fmt.Errorf("更新角色元数据失败: %v", err)
 		}
 	}
 
 	// 更新角色权限
-	return m.setRolePermissionsInTenant(roleKey, tenantKey, permissions)
+	return m.setRolePermissionsInTenant(ctx, roleKey, tenantKey, permissions)
 }
 
 // DeleteRole 删除自定义角色
-func (m *roleManager) DeleteRole(roleKey string) error {
+func (m *roleManager) DeleteRole(ctx context.Context, roleKey string) error {
 	// 验证参数
-	if roleKey == "" {
-		return core.ErrInvalidParameter
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return err
 	}
 
 	// 检查角色是否存在
-	exists, err := m.isRoleExists(roleKey)
+	exists, err := m.isRoleExists(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -156,45 +166,55 @@ func (m *roleManager) DeleteRole(roleKey string) error {
 	}
 
 	// 检查是否为系统角色（包含系统权限）
-	if hasSystemPerms, _ := m.HasSystemPermissions(roleKey); hasSystemPerms {
+	if hasSystemPerms, _ := m.HasSystemPermissions(ctx, roleKey); hasSystemPerms {
 		return core.ErrSystemRoleImmutable
 	}
 
 	// 删除角色权限
-	if err := m.enforcer.ClearPolicies(roleKey); err != nil {
+	if err := m.enforcer.ClearPolicies(ctx, m.enforcer.RoleSubject(roleKey)); err != nil {
+		return err
+	}
+
+	// 删除角色继承关系
+	if err := m.clearRoleInheritance(ctx, roleKey); err != nil {
 		return err
 	}
 
 	// 删除角色元数据
-	if err := m.deleteRoleMetadata(roleKey); err != nil {
+	if err := m.deleteRoleMetadata(ctx, roleKey); err != nil {
 		return fmt.Errorf("删除角色元数据失败: %v", err)
 	}
 
+	// 清理共享发布和链接（角色已删除，链接无论是否存在都需要移除）
+	if err := m.UnpublishRole(ctx, roleKey, true); err != nil {
+		return err
+	}
+
 	// 删除用户角色分配
 	return nil
 }
 
 // GetRole 获取角色详情
-func (m *roleManager) GetRole(roleKey string) (*core.Role, error) {
-	if roleKey == "" {
-		return nil, core.ErrInvalidParameter
+func (m *roleManager) GetRole(ctx context.Context, roleKey string) (*core.Role, error) {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return nil, err
 	}
 
 	// 从数据库获取角色元数据
-	roleMetadata, err := m.getRoleMetadata(roleKey)
+	roleMetadata, err := m.getRoleMetadata(ctx, roleKey)
 	if err != nil {
 		return nil, fmt.Errorf("角色 '%s' 不存在", roleKey)
 	}
 
 	// 获取角色权限
-	permissions, err := m.GetRolePermissions(roleKey)
+	permissions, err := m.GetRolePermissions(ctx, roleKey)
 	if err != nil {
 		return nil, err
 	}
 
-	description := ""
-	if roleMetadata.Description.Valid {
-		description = roleMetadata.Description.String
+	description, err := m.decryptNullString(roleMetadata.Description)
+	if err != nil {
+		return nil, err
 	}
 
 	return &core.Role{
@@ -206,52 +226,24 @@ func (m *roleManager) GetRole(roleKey string) (*core.Role, error) {
 	}, nil
 }
 
-// ListRoles 获取角色列表
-func (m *roleManager) ListRoles(tenantKey string, filter *core.RoleFilter) ([]*core.Role, error) {
-	// 从数据库获取角色列表
-	roleMetadataList, err := m.listRoleMetadata(tenantKey)
+// ListRoles 获取角色列表（过滤和排序在数据库中完成）
+func (m *roleManager) ListRoles(ctx context.Context, tenantKey string, filter *core.RoleFilter) ([]*core.Role, error) {
+	records, err := m.queryRoles(ctx, tenantKey, filter, "created_at DESC")
 	if err != nil {
 		return nil, err
 	}
 
-	var roles []*core.Role
-	for _, roleMetadata := range roleMetadataList {
-		// 获取角色权限
-		permissions, err := m.GetRolePermissions(roleMetadata.RoleKey)
-		if err != nil {
-			continue // 跳过获取权限失败的角色
-		}
-
-		description := ""
-		if roleMetadata.Description.Valid {
-			description = roleMetadata.Description.String
-		}
-
-		role := &core.Role{
-			Key:         roleMetadata.RoleKey,
-			Name:        roleMetadata.Name,
-			Description: description,
-			Permissions: permissions,
-			TenantKey:   roleMetadata.TenantKey,
-		}
-
-		// 应用过滤条件
-		if m.matchRoleFilter(role, filter) {
-			roles = append(roles, role)
-		}
-	}
-
-	return roles, nil
+	return m.rolesWithPermissions(records)
 }
 
 // GetRolePermissions 获取角色权限
-func (m *roleManager) GetRolePermissions(roleKey string) ([]core.Permission, error) {
-	if roleKey == "" {
-		return nil, core.ErrInvalidParameter
+func (m *roleManager) GetRolePermissions(ctx context.Context, roleKey string) ([]core.Permission, error) {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return nil, err
 	}
 
 	// 验证 roleKey 确实是角色（存在于 roles 表中）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return nil, err
 	}
@@ -260,36 +252,44 @@ func (m *roleManager) GetRolePermissions(roleKey string) ([]core.Permission, err
 	}
 
 	// 获取角色权限
-	policies, err := m.enforcer.GetPolicies(roleKey, "")
+	policies, err := m.enforcer.GetPolicies(m.enforcer.RoleSubject(roleKey), "")
 	if err != nil {
 		return nil, err
 	}
 
-	permissions := make([]core.Permission, 0, len(policies))
-	for _, policy := range policies {
-		// 跳过占位权限
-		if policy.Resource == core.ResourcePlaceholder && policy.Action == core.ActionNone {
-			continue
-		}
+	return rolePermissions(policies), nil
+}
+
+// GetRoleEffectivePermissions 获取角色有效权限（展开继承链并标注来源角色）
+func (m *roleManager) GetRoleEffectivePermissions(ctx context.Context, roleKey string) ([]core.InheritedPermission, error) {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return nil, err
+	}
 
-		permissions = append(permissions, core.Permission{
-			Resource: policy.Resource,
-			Action:   policy.Action,
-		})
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
+	if err != nil {
+		return nil, err
+	}
+	if !isRole {
+		return nil, fmt.Errorf("'%s' 不是一个有效的角色", roleKey)
 	}
 
-	return permissions, nil
+	return m.enforcer.GetRoleEffectivePermissions(roleKey)
 }
 
 // GrantPermission 为角色授予权限
-func (m *roleManager) GrantPermission(operatorKey, roleKey string, permission core.Permission) error {
+func (m *roleManager) GrantPermission(ctx context.Context, operatorKey, roleKey string, permission core.Permission) error {
 	// 验证参数
-	if roleKey == "" || permission.Resource == "" || permission.Action == "" {
-		return core.ErrInvalidParameter
+	if err := core.FirstError(
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("permission.resource", permission.Resource),
+		core.RequireArg("permission.action", permission.Action),
+	); err != nil {
+		return err
 	}
 
 	// 验证 roleKey 确实是角色（存在于 roles 表中）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -298,14 +298,14 @@ func (m *roleManager) GrantPermission(operatorKey, roleKey string, permission co
 	}
 
 	// 检查是否为系统角色（包含系统权限）
-	if hasSystemPerms, _ := m.HasSystemPermissions(roleKey); hasSystemPerms {
+	if hasSystemPerms, _ := m.HasSystemPermissions(ctx, roleKey); hasSystemPerms {
 		return core.ErrSystemRoleImmutable
 	}
 
 	// 安全检查已在engine层处理
 
 	// 获取角色的归属租户
-	role, err := m.GetRole(roleKey)
+	role, err := m.GetRole(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -314,18 +314,27 @@ func (m *roleManager) GrantPermission(operatorKey, roleKey string, permission co
 	tenantKey := role.TenantKey
 
 	// 为角色添加权限（使用角色归属的租户域）
-	return m.enforcer.AddPolicy(roleKey, tenantKey, permission)
+	if err := m.enforcer.AddPolicy(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, permission); err != nil {
+		return err
+	}
+
+	// 角色已有实际权限，不再需要占位权限
+	return m.removePlaceholder(ctx, roleKey, tenantKey)
 }
 
 // RevokePermission 撤销角色权限
-func (m *roleManager) RevokePermission(operatorKey, roleKey string, permission core.Permission) error {
+func (m *roleManager) RevokePermission(ctx context.Context, operatorKey, roleKey string, permission core.Permission) error {
 	// 验证参数
-	if roleKey == "" || permission.Resource == "" || permission.Action == "" {
-		return core.ErrInvalidParameter
+	if err := core.FirstError(
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("permission.resource", permission.Resource),
+		core.RequireArg("permission.action", permission.Action),
+	); err != nil {
+		return err
 	}
 
 	// 验证 roleKey 确实是角色（存在于 roles 表中）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -334,14 +343,14 @@ func (m *roleManager) RevokePermission(operatorKey, roleKey string, permission c
 	}
 
 	// 检查是否为系统角色（包含系统权限）
-	if hasSystemPerms, _ := m.HasSystemPermissions(roleKey); hasSystemPerms {
+	if hasSystemPerms, _ := m.HasSystemPermissions(ctx, roleKey); hasSystemPerms {
 		return core.ErrSystemRoleImmutable
 	}
 
 	// 安全检查已在engine层处理
 
 	// 获取角色的归属租户
-	role, err := m.GetRole(roleKey)
+	role, err := m.GetRole(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -350,17 +359,22 @@ func (m *roleManager) RevokePermission(operatorKey, roleKey string, permission c
 	tenantKey := role.TenantKey
 
 	// 撤销角色权限
-	return m.enforcer.RemovePolicy(roleKey, tenantKey, permission)
+	if err := m.enforcer.RemovePolicy(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, permission); err != nil {
+		return err
+	}
+
+	// 撤销最后一个实际权限后补回占位权限，保持角色可被识别
+	return m.ensurePlaceholder(ctx, roleKey, tenantKey)
 }
 
 // SetRolePermissions 设置角色的所有权限（替换现有权限）
-func (m *roleManager) SetRolePermissions(roleKey string, permissions []core.Permission) error {
-	if roleKey == "" {
-		return core.ErrInvalidParameter
+func (m *roleManager) SetRolePermissions(ctx context.Context, roleKey string, permissions []core.Permission) error {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return err
 	}
 
 	// 验证 roleKey 确实是角色（存在于 roles 表中）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -369,14 +383,14 @@ func (m *roleManager) SetRolePermissions(roleKey string, permissions []core.Perm
 	}
 
 	// 获取角色的旧权限
-	oldPermissions, err := m.GetRolePermissions(roleKey)
+	oldPermissions, err := m.GetRolePermissions(ctx, roleKey)
 	if err != nil {
 		return fmt.Errorf("获取角色旧权限失败: %v", err)
 	}
 
 	// 找出新增和删除的权限
-	addedPermissions := findAddedPermissionsInRole(oldPermissions, permissions)
-	removedPermissions := findRemovedPermissionsInRole(oldPermissions, permissions)
+	addedPermissions := core.DifferencePermissions(permissions, oldPermissions)
+	removedPermissions := core.DifferencePermissions(oldPermissions, permissions)
 
 	// 检查是否尝试新增系统权限
 	if m.hasSystemPermissionsInList(addedPermissions) {
@@ -388,26 +402,41 @@ func (m *roleManager) SetRolePermissions(roleKey string, permissions []core.Perm
 		return core.ErrSystemRoleImmutable
 	}
 
-	return m.setRolePermissions(roleKey, permissions)
+	return m.setRolePermissions(ctx, roleKey, permissions)
 }
 
 // GetUsersWithRole 获取拥有指定角色的用户
-func (m *roleManager) GetUsersWithRole(roleKey, tenantKey string) ([]string, error) {
-	if roleKey == "" {
-		return nil, core.ErrInvalidParameter
+func (m *roleManager) GetUsersWithRole(ctx context.Context, roleKey, tenantKey string) ([]string, error) {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return nil, err
 	}
 
 	return m.enforcer.GetUsersWithRole(roleKey, tenantKey)
 }
 
+// GetUsersWithPermission 获取在租户内拥有指定权限的用户（直接授权和角色授权）
+// 已登记在角色表中的策略主体按角色处理，不出现在结果中
+func (m *roleManager) GetUsersWithPermission(ctx context.Context, tenantKey string, permission core.Permission) ([]string, error) {
+	if err := core.FirstError(
+		core.RequireArg("tenantKey", tenantKey),
+		core.CheckArg(permission.IsValid(), "permission", core.ConstraintRequired),
+	); err != nil {
+		return nil, err
+	}
+
+	return m.enforcer.GetUsersWithPermission(tenantKey, permission, func(key string) (bool, error) {
+		return m.isRoleExistsInDB(ctx, key)
+	})
+}
+
 // HasSystemPermissions 检查角色是否包含系统权限
-func (m *roleManager) HasSystemPermissions(roleKey string) (bool, error) {
-	if roleKey == "" {
-		return false, core.ErrInvalidParameter
+func (m *roleManager) HasSystemPermissions(ctx context.Context, roleKey string) (bool, error) {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return false, err
 	}
 
 	// 验证 roleKey 确实是角色（存在于 roles 表中）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return false, err
 	}
@@ -417,7 +446,7 @@ func (m *roleManager) HasSystemPermissions(roleKey string) (bool, error) {
 	}
 
 	// 获取角色权限（此时 GetRolePermissions 已经包含了角色验证）
-	permissions, err := m.GetRolePermissions(roleKey)
+	permissions, err := m.GetRolePermissions(ctx, roleKey)
 	if err != nil {
 		return false, err
 	}
@@ -433,13 +462,16 @@ func (m *roleManager) HasSystemPermissions(roleKey string) (bool, error) {
 }
 
 // UserRoleHasSystemPermissions 检查用户的角色是否包含系统权限
-func (m *roleManager) UserRoleHasSystemPermissions(userKey, roleKey, tenantKey string) (bool, error) {
-	if userKey == "" || roleKey == "" {
-		return false, core.ErrInvalidParameter
+func (m *roleManager) UserRoleHasSystemPermissions(ctx context.Context, userKey, roleKey, tenantKey string) (bool, error) {
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("roleKey", roleKey),
+	); err != nil {
+		return false, err
 	}
 
 	// 验证 roleKey 确实是角色（存在于 roles 表中）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return false, err
 	}
@@ -458,11 +490,11 @@ func (m *roleManager) UserRoleHasSystemPermissions(userKey, roleKey, tenantKey s
 	}
 
 	// 检查角色是否包含系统权限（此时 HasSystemPermissions 已经包含了角色验证）
-	return m.HasSystemPermissions(roleKey)
+	return m.HasSystemPermissions(ctx, roleKey)
 }
 
 // GetAllGroupingPolicies 获取指定租户的所有角色分配
-func (m *roleManager) GetAllGroupingPolicies(tenantKey string) ([]core.GroupingPolicy, error) {
+func (m *roleManager) GetAllGroupingPolicies(ctx context.Context, tenantKey string) ([]core.GroupingPolicy, error) {
 	// 获取所有角色分组策略
 	allGroupings, err := m.enforcer.GetGroupingPolicies()
 	if err != nil {
@@ -481,40 +513,6 @@ func (m *roleManager) GetAllGroupingPolicies(tenantKey string) ([]core.GroupingP
 	return filteredGroupings, nil
 }
 
-// === 权限对比辅助函数 ===
-
-// permissionExistsInList 检查权限是否存在于权限列表中
-func permissionExistsInList(permission core.Permission, permissions []core.Permission) bool {
-	for _, p := range permissions {
-		if p.Resource == permission.Resource && p.Action == permission.Action {
-			return true
-		}
-	}
-	return false
-}
-
-// findAddedPermissionsInRole 找出新增的权限（在新权限中但不在旧权限中）
-func findAddedPermissionsInRole(oldPermissions, newPermissions []core.Permission) []core.Permission {
-	var added []core.Permission
-	for _, newPerm := range newPermissions {
-		if !permissionExistsInList(newPerm, oldPermissions) {
-			added = append(added, newPerm)
-		}
-	}
-	return added
-}
-
-// findRemovedPermissionsInRole 找出删除的权限（在旧权限中但不在新权限中）
-func findRemovedPermissionsInRole(oldPermissions, newPermissions []core.Permission) []core.Permission {
-	var removed []core.Permission
-	for _, oldPerm := range oldPermissions {
-		if !permissionExistsInList(oldPerm, newPermissions) {
-			removed = append(removed, oldPerm)
-		}
-	}
-	return removed
-}
-
 // hasSystemPermissionsInList 检查权限列表中是否包含系统权限
 func (m *roleManager) hasSystemPermissionsInList(permissions []core.Permission) bool {
 	for _, perm := range permissions {
@@ -1,13 +1,17 @@
 package role
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+
 	"github.com/rezeropoint/casbinx/core"
 )
 
 // setRolePermissions 设置角色权限（内部方法）
-func (m *roleManager) setRolePermissions(roleKey string, permissions []core.Permission) error {
+func (m *roleManager) setRolePermissions(ctx context.Context, roleKey string, permissions []core.Permission) error {
 	// 先清除现有权限
-	if err := m.enforcer.ClearPolicies(roleKey); err != nil {
+	if err := m.enforcer.ClearPolicies(ctx, m.enforcer.RoleSubject(roleKey)); err != nil {
 		return err
 	}
 
@@ -17,23 +21,23 @@ func (m *roleManager) setRolePermissions(roleKey string, permissions []core.Perm
 			continue
 		}
 
-		if err := m.enforcer.AddPolicy(roleKey, "*", perm); err != nil {
+		if err := m.enforcer.AddPolicy(ctx, m.enforcer.RoleSubject(roleKey), "*", perm); err != nil {
 			return err
 		}
 	}
 
 	// 如果最终没有任何权限，添加占位权限
 	if len(permissions) == 0 {
-		return m.enforcer.AddPolicy(roleKey, "*", core.Permission{Resource: core.ResourcePlaceholder, Action: core.ActionNone})
+		return m.enforcer.AddPolicy(ctx, m.enforcer.RoleSubject(roleKey), "*", placeholderPermission)
 	}
 
 	return nil
 }
 
 // setRolePermissionsInTenant 在指定租户中设置角色权限
-func (m *roleManager) setRolePermissionsInTenant(roleKey, tenantKey string, permissions []core.Permission) error {
+func (m *roleManager) setRolePermissionsInTenant(ctx context.Context, roleKey, tenantKey string, permissions []core.Permission) error {
 	// 先清除现有权限
-	if err := m.enforcer.ClearPolicies(roleKey); err != nil {
+	if err := m.enforcer.ClearPolicies(ctx, m.enforcer.RoleSubject(roleKey)); err != nil {
 		return err
 	}
 
@@ -43,23 +47,23 @@ func (m *roleManager) setRolePermissionsInTenant(roleKey, tenantKey string, perm
 			continue
 		}
 
-		if err := m.enforcer.AddPolicy(roleKey, tenantKey, perm); err != nil {
+		if err := m.enforcer.AddPolicy(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, perm); err != nil {
 			return err
 		}
 	}
 
 	// 如果最终没有任何权限，添加占位权限
 	if len(permissions) == 0 {
-		return m.enforcer.AddPolicy(roleKey, tenantKey, core.Permission{Resource: core.ResourcePlaceholder, Action: core.ActionNone})
+		return m.enforcer.AddPolicy(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, placeholderPermission)
 	}
 
 	return nil
 }
 
 // isRoleExists 检查角色是否存在
-func (m *roleManager) isRoleExists(roleKey string) (bool, error) {
+func (m *roleManager) isRoleExists(ctx context.Context, roleKey string) (bool, error) {
 	// 优先从数据库检查角色是否存在
-	exists, err := m.isRoleExistsInDB(roleKey)
+	exists, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return false, err
 	}
@@ -68,7 +72,7 @@ func (m *roleManager) isRoleExists(roleKey string) (bool, error) {
 	}
 
 	// 如果数据库中不存在，检查是否有权限策略（兼容旧数据）
-	policies, err := m.enforcer.GetPolicies(roleKey, "")
+	policies, err := m.enforcer.GetPolicies(m.enforcer.RoleSubject(roleKey), "")
 	if err != nil {
 		return false, err
 	}
@@ -96,6 +100,10 @@ func (m *roleManager) getCustomRolesByTenant(tenantKey string) ([]*core.Role, er
 	roleTenantMap := make(map[string]string) // 记录角色的归属租户
 
 	for _, policy := range policies {
+		// 启用主体命名空间时可以区分用户的直接授权，跳过
+		if policy.SubjectKind == core.SubjectKindUser {
+			continue
+		}
 		roleKey := policy.Subject
 
 		// 租户过滤逻辑
@@ -144,25 +152,20 @@ func (m *roleManager) getCustomRolesByTenant(tenantKey string) ([]*core.Role, er
 	return roles, nil
 }
 
-// matchRoleFilter 检查角色是否匹配过滤条件
-func (m *roleManager) matchRoleFilter(role *core.Role, filter *core.RoleFilter) bool {
-	if filter == nil {
-		return true
-	}
-
-	if filter.KeyPattern != "" && role.Key != filter.KeyPattern {
-		return false
-	}
-
-	if filter.NamePattern != "" && role.Name != filter.NamePattern {
-		return false
-	}
+// rolePermissions 将角色的策略转换为权限列表，跳过占位权限
+func rolePermissions(policies []core.Policy) []core.Permission {
+	permissions := make([]core.Permission, 0, len(policies))
+	for _, policy := range policies {
+		if policy.Resource == core.ResourcePlaceholder && policy.Action == core.ActionNone {
+			continue
+		}
 
-	if filter.TenantKey != "" && role.TenantKey != filter.TenantKey {
-		return false
+		permissions = append(permissions, core.Permission{
+			Resource: policy.Resource,
+			Action:   policy.Action,
+		})
 	}
-
-	return true
+	return permissions
 }
 
 // isSystemPermission 检查是否为系统权限（使用安全验证器配置）
@@ -171,41 +174,60 @@ func (m *roleManager) isSystemPermission(permission core.Permission) bool {
 }
 
 // createRoleMetadata 在数据库中创建角色元数据
-func (m *roleManager) createRoleMetadata(roleKey, name, description, tenantKey, createdBy string) error {
+func (m *roleManager) createRoleMetadata(ctx context.Context, roleKey, name, description, tenantKey, createdBy string) error {
+	description, err := m.cipher.Encrypt(description)
+	if err != nil {
+		return err
+	}
+	if err := m.journalRoles(ctx, roleKey); err != nil {
+		return err
+	}
+
 	insertSQL := `
 		INSERT INTO system_roles (role_key, name, description, tenant_key, created_by)
 		VALUES ($1, $2, $3, $4, $5)
 	`
-	_, err := m.dbConn.Exec(insertSQL, roleKey, name, description, tenantKey, createdBy)
+	_, err = m.dbConn.ExecCtx(ctx, insertSQL, roleKey, name, description, tenantKey, createdBy)
 	return err
 }
 
 // updateRoleMetadata 更新数据库中的角色元数据
-func (m *roleManager) updateRoleMetadata(roleKey, name, description string) error {
+func (m *roleManager) updateRoleMetadata(ctx context.Context, roleKey, name, description string) error {
+	description, err := m.cipher.Encrypt(description)
+	if err != nil {
+		return err
+	}
+	if err := m.journalRoles(ctx, roleKey); err != nil {
+		return err
+	}
+
 	updateSQL := `
 		UPDATE system_roles
 		SET name = $2, description = $3, updated_at = CURRENT_TIMESTAMP
 		WHERE role_key = $1
 	`
-	_, err := m.dbConn.Exec(updateSQL, roleKey, name, description)
+	_, err = m.dbConn.ExecCtx(ctx, updateSQL, roleKey, name, description)
 	return err
 }
 
 // deleteRoleMetadata 删除数据库中的角色元数据
-func (m *roleManager) deleteRoleMetadata(roleKey string) error {
+func (m *roleManager) deleteRoleMetadata(ctx context.Context, roleKey string) error {
+	if err := m.journalRoles(ctx, roleKey); err != nil {
+		return err
+	}
 	deleteSQL := `DELETE FROM system_roles WHERE role_key = $1`
-	_, err := m.dbConn.Exec(deleteSQL, roleKey)
+	_, err := m.dbConn.ExecCtx(ctx, deleteSQL, roleKey)
 	return err
 }
 
 // getRoleMetadata 从数据库获取角色元数据
-func (m *roleManager) getRoleMetadata(roleKey string) (*roleMetadata, error) {
+func (m *roleManager) getRoleMetadata(ctx context.Context, roleKey string) (*roleMetadata, error) {
 	var role roleMetadata
 	selectSQL := `
 		SELECT role_key, name, description, tenant_key, created_at, updated_at, created_by
 		FROM system_roles WHERE role_key = $1
 	`
-	err := m.dbConn.QueryRow(&role, selectSQL, roleKey)
+	err := m.dbConn.QueryRowCtx(ctx, &role, selectSQL, roleKey)
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +235,7 @@ func (m *roleManager) getRoleMetadata(roleKey string) (*roleMetadata, error) {
 }
 
 // listRoleMetadata 从数据库获取角色列表
-func (m *roleManager) listRoleMetadata(tenantKey string) ([]*roleMetadata, error) {
+func (m *roleManager) listRoleMetadata(ctx context.Context, tenantKey string) ([]*roleMetadata, error) {
 	var roles []*roleMetadata
 	var selectSQL string
 	var args []interface{}
@@ -225,16 +247,18 @@ func (m *roleManager) listRoleMetadata(tenantKey string) ([]*roleMetadata, error
 			FROM system_roles ORDER BY created_at DESC
 		`
 	} else {
-		// 获取指定租户的角色（包括全局角色）
+		// 获取指定租户的角色（包括全局角色和链接到该租户的共享角色）
 		selectSQL = `
 			SELECT role_key, name, description, tenant_key, created_at, updated_at, created_by
-			FROM system_roles WHERE tenant_key = $1 OR tenant_key = '*'
+			FROM system_roles
+			WHERE tenant_key = $1 OR tenant_key = '*'
+				OR role_key IN (SELECT role_key FROM system_shared_role_links WHERE tenant_key = $1)
 			ORDER BY created_at DESC
 		`
 		args = append(args, tenantKey)
 	}
 
-	err := m.dbConn.QueryRows(&roles, selectSQL, args...)
+	err := m.dbConn.QueryRowsCtx(ctx, &roles, selectSQL, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -242,12 +266,55 @@ func (m *roleManager) listRoleMetadata(tenantKey string) ([]*roleMetadata, error
 }
 
 // isRoleExistsInDB 检查角色是否在数据库中存在
-func (m *roleManager) isRoleExistsInDB(roleKey string) (bool, error) {
+func (m *roleManager) isRoleExistsInDB(ctx context.Context, roleKey string) (bool, error) {
 	var count int
 	countSQL := `SELECT COUNT(*) FROM system_roles WHERE role_key = $1`
-	err := m.dbConn.QueryRow(&count, countSQL, roleKey)
+	err := m.dbConn.QueryRowCtx(ctx, &count, countSQL, roleKey)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// compensateCreateRoleAttempts 角色创建补偿的最大尝试次数
+const compensateCreateRoleAttempts = 3
+
+// compensateCreateRole 角色创建失败后的补偿：删除已写入的策略和元数据，并校验补偿结果
+// 补偿校验通过时返回原始错误；校验失败时返回 ErrRoleCreationIncomplete，提示需要人工清理
+func (m *roleManager) compensateCreateRole(ctx context.Context, roleKey string, cause error) error {
+	// 调用方的 ctx 已取消时补偿仍需完成
+	ctx = context.WithoutCancel(ctx)
+	for attempt := 0; attempt < compensateCreateRoleAttempts; attempt++ {
+		// 单步失败不立即返回，以最终校验结果为准
+		_ = m.enforcer.ClearPolicies(ctx, m.enforcer.RoleSubject(roleKey))
+		_ = m.deleteRoleMetadata(ctx, roleKey)
+
+		if m.verifyRoleAbsent(ctx, roleKey) {
+			return fmt.Errorf("创建角色权限失败: %w", cause)
+		}
+	}
+
+	return fmt.Errorf("%w: 角色 '%s' 创建失败（%v），且补偿未能清理已写入的数据", core.ErrRoleCreationIncomplete, roleKey, cause)
+}
+
+// verifyRoleAbsent 校验角色的元数据和策略均已不存在
+func (m *roleManager) verifyRoleAbsent(ctx context.Context, roleKey string) bool {
+	exists, err := m.isRoleExistsInDB(ctx, roleKey)
+	if err != nil || exists {
+		return false
+	}
+
+	policies, err := m.enforcer.GetPolicies(m.enforcer.RoleSubject(roleKey), "")
+	if err != nil {
+		return false
+	}
+	return len(policies) == 0
+}
+
+// decryptNullString 解密可为空的敏感字段
+func (m *roleManager) decryptNullString(value sql.NullString) (string, error) {
+	if !value.Valid {
+		return "", nil
+	}
+	return m.cipher.Decrypt(value.String)
+}
@@ -0,0 +1,106 @@
+package role
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// AddRoleInheritance 添加角色继承：子角色在租户内继承父角色的权限
+func (m *roleManager) AddRoleInheritance(ctx context.Context, parentRoleKey, childRoleKey, tenantKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("parentRoleKey", parentRoleKey),
+		core.RequireArg("childRoleKey", childRoleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	for _, roleKey := range []string{parentRoleKey, childRoleKey} {
+		isRole, err := m.isRoleExistsInDB(ctx, roleKey)
+		if err != nil {
+			return err
+		}
+		if !isRole {
+			return fmt.Errorf("'%s' 不是一个有效的角色", roleKey)
+		}
+	}
+
+	return m.enforcer.AddRoleInheritance(ctx, parentRoleKey, childRoleKey, tenantKey)
+}
+
+// RemoveRoleInheritance 移除角色继承
+func (m *roleManager) RemoveRoleInheritance(ctx context.Context, parentRoleKey, childRoleKey, tenantKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("parentRoleKey", parentRoleKey),
+		core.RequireArg("childRoleKey", childRoleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	return m.enforcer.RemoveRoleInheritance(ctx, parentRoleKey, childRoleKey, tenantKey)
+}
+
+// GetRoleHierarchy 获取租户内生效的角色继承关系（包括全局域中定义的继承），tenantKey 为空时返回所有租户
+func (m *roleManager) GetRoleHierarchy(ctx context.Context, tenantKey string) ([]core.RoleInheritance, error) {
+	groupings, err := m.GetAllGroupingPolicies(ctx, tenantKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分组策略中主体为角色的记录是继承关系，其余为用户角色分配
+	isRole := make(map[string]bool)
+	var hierarchy []core.RoleInheritance
+	for _, grouping := range groupings {
+		role, checked := isRole[grouping.UserKey]
+		if !checked {
+			role, err = m.isRoleExistsInDB(ctx, grouping.UserKey)
+			if err != nil {
+				return nil, err
+			}
+			isRole[grouping.UserKey] = role
+		}
+		if role {
+			hierarchy = append(hierarchy, core.RoleInheritance{
+				ParentRoleKey: grouping.RoleKey,
+				ChildRoleKey:  grouping.UserKey,
+				TenantKey:     grouping.TenantKey,
+			})
+		}
+	}
+
+	sort.Slice(hierarchy, func(i, j int) bool {
+		if hierarchy[i].TenantKey != hierarchy[j].TenantKey {
+			return hierarchy[i].TenantKey < hierarchy[j].TenantKey
+		}
+		if hierarchy[i].ParentRoleKey != hierarchy[j].ParentRoleKey {
+			return hierarchy[i].ParentRoleKey < hierarchy[j].ParentRoleKey
+		}
+		return hierarchy[i].ChildRoleKey < hierarchy[j].ChildRoleKey
+	})
+	return hierarchy, nil
+}
+
+// clearRoleInheritance 删除角色时移除其作为父角色和子角色的所有继承关系
+func (m *roleManager) clearRoleInheritance(ctx context.Context, roleKey string) error {
+	if err := m.enforcer.RemoveRoleParents(ctx, roleKey); err != nil {
+		return err
+	}
+
+	hierarchy, err := m.GetRoleHierarchy(ctx, "")
+	if err != nil {
+		return err
+	}
+	for _, inheritance := range hierarchy {
+		if inheritance.ParentRoleKey != roleKey {
+			continue
+		}
+		if err := m.enforcer.RemoveRoleInheritance(ctx, roleKey, inheritance.ChildRoleKey, inheritance.TenantKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// MetadataJournal 角色元数据变更日志：记录期间被写入的角色在首次写入前的元数据，
+// 用于多步变更失败后只恢复这些角色，不影响其他实例在此期间写入的角色
+type MetadataJournal struct {
+	mu    sync.Mutex
+	order []string                 // 按首次写入顺序排列的角色键
+	roles map[string]*roleMetadata // 角色键 -> 首次写入前的元数据，nil 表示写入前不存在
+}
+
+// BeginMetadataJournal 开始记录角色元数据变更，已有日志在记录时返回错误
+func (m *roleManager) BeginMetadataJournal() (*MetadataJournal, error) {
+	journal := &MetadataJournal{roles: make(map[string]*roleMetadata)}
+	if !m.journal.CompareAndSwap(nil, journal) {
+		return nil, errors.New("已有角色元数据变更日志在记录中")
+	}
+	return journal, nil
+}
+
+// EndMetadataJournal 停止记录角色元数据变更，可以重复调用
+func (m *roleManager) EndMetadataJournal(journal *MetadataJournal) {
+	m.journal.CompareAndSwap(journal, nil)
+}
+
+// RevertMetadataJournal 将日志中的角色恢复到首次写入前的元数据：删除期间创建的角色，写回期间修改或删除的角色
+// 描述按写入前的密文原样写回；只恢复日志中的角色，在一个数据库事务中完成
+func (m *roleManager) RevertMetadataJournal(ctx context.Context, journal *MetadataJournal) error {
+	m.EndMetadataJournal(journal)
+
+	journal.mu.Lock()
+	order, roles := journal.order, journal.roles
+	journal.order, journal.roles = nil, make(map[string]*roleMetadata)
+	journal.mu.Unlock()
+	if len(order) == 0 {
+		return nil
+	}
+
+	upsertSQL := `
+		INSERT INTO system_roles (role_key, name, description, tenant_key, created_at, updated_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (role_key) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description, tenant_key = EXCLUDED.tenant_key,
+		    created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, created_by = EXCLUDED.created_by
+	`
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		for _, roleKey := range order {
+			record := roles[roleKey]
+			if record == nil {
+				if _, err := session.ExecCtx(ctx, `DELETE FROM system_roles WHERE role_key = $1`, roleKey); err != nil {
+					return fmt.Errorf("恢复角色元数据失败: %v", err)
+				}
+				continue
+			}
+			if _, err := session.ExecCtx(ctx, upsertSQL, record.RoleKey, record.Name, record.Description, record.TenantKey,
+				record.CreatedAt, record.UpdatedAt, record.CreatedBy); err != nil {
+				return fmt.Errorf("恢复角色元数据失败: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// journalRoles 在写入角色元数据前记录角色的当前元数据（未在记录时忽略，每个角色只记录首次写入前的状态）
+func (m *roleManager) journalRoles(ctx context.Context, roleKeys ...string) error {
+	journal := m.journal.Load()
+	if journal == nil {
+		return nil
+	}
+
+	for _, roleKey := range roleKeys {
+		journal.mu.Lock()
+		_, recorded := journal.roles[roleKey]
+		journal.mu.Unlock()
+		if recorded {
+			continue
+		}
+
+		var records []*roleMetadata
+		selectSQL := `SELECT ` + roleMetadataColumns + ` FROM system_roles WHERE role_key = $1`
+		if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, roleKey); err != nil {
+			return fmt.Errorf("记录角色元数据变更失败: %v", err)
+		}
+
+		var record *roleMetadata
+		if len(records) > 0 {
+			record = records[0]
+		}
+		journal.mu.Lock()
+		if _, recorded := journal.roles[roleKey]; !recorded {
+			journal.roles[roleKey] = record
+			journal.order = append(journal.order, roleKey)
+		}
+		journal.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package role
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// MigrateSubjectNamespaces 将存量策略迁移为带命名空间前缀的主体
+// 已登记在角色表中或出现在角色分配中的主体按角色迁移，其余按用户迁移
+func (m *roleManager) MigrateSubjectNamespaces(ctx context.Context) (*core.SubjectMigrationReport, error) {
+	if !m.enforcer.SubjectNamespacesEnabled() {
+		return nil, core.ErrSubjectNamespacesDisabled
+	}
+	return m.enforcer.MigrateSubjectNamespaces(ctx, func(key string) (bool, error) {
+		return m.isRoleExistsInDB(ctx, key)
+	})
+}
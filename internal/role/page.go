@@ -0,0 +1,228 @@
+package role
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// roleOrderColumns 角色排序字段对应的列，只允许白名单中的列出现在 ORDER BY 中
+var roleOrderColumns = map[core.RoleOrder]string{
+	core.RoleOrderKey:       "role_key",
+	core.RoleOrderName:      "name",
+	core.RoleOrderTenant:    "tenant_key",
+	core.RoleOrderCreatedAt: "created_at",
+	core.RoleOrderUpdatedAt: "updated_at",
+}
+
+// ListRolesPage 分页获取角色列表，过滤、排序和分页在数据库中完成，只加载当前页角色的权限
+// 启用字段加密且按描述搜索时，描述只能在解密后匹配，此时在内存中过滤和分页
+func (m *roleManager) ListRolesPage(ctx context.Context, tenantKey string, filter *core.RoleFilter, page core.PageRequest) (*core.PageResponse[*core.Role], error) {
+	if m.decryptedDescriptionSearch(filter) {
+		return m.listRolesPageInMemory(ctx, tenantKey, filter, page)
+	}
+
+	offset, err := page.Cursor.Offset()
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := roleConditions(tenantKey, filter, true)
+	orderBy, err := roleOrderClause(filter, "role_key")
+	if err != nil {
+		return nil, err
+	}
+
+	var total int
+	countSQL := `SELECT COUNT(*) FROM system_roles` + where
+	if err := m.dbConn.QueryRowCtx(ctx, &total, countSQL, args...); err != nil {
+		return nil, fmt.Errorf("统计角色数量失败: %v", err)
+	}
+
+	limit := page.PageSize()
+	var records []*roleMetadata
+	selectSQL := fmt.Sprintf(`SELECT `+roleMetadataColumns+` FROM system_roles%s ORDER BY %s LIMIT $%d OFFSET $%d`,
+		where, orderBy, len(args)+1, len(args)+2)
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, append(args, limit, offset)...); err != nil {
+		return nil, fmt.Errorf("查询角色列表失败: %v", err)
+	}
+
+	roles, err := m.rolesWithPermissions(records)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &core.PageResponse[*core.Role]{Items: roles, Total: total}
+	if end := offset + len(roles); end < total {
+		response.HasMore = true
+		response.NextCursor = core.EncodeCursor(end)
+	}
+	return response, nil
+}
+
+// listRolesPageInMemory 查询所有符合条件的角色元数据，解密后按描述过滤，再在内存中分页
+func (m *roleManager) listRolesPageInMemory(ctx context.Context, tenantKey string, filter *core.RoleFilter, page core.PageRequest) (*core.PageResponse[*core.Role], error) {
+	records, err := m.queryRoles(ctx, tenantKey, filter, "role_key")
+	if err != nil {
+		return nil, err
+	}
+
+	paged, err := core.Paginate(records, page)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := m.rolesWithPermissions(paged.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &core.PageResponse[*core.Role]{Items: roles, NextCursor: paged.NextCursor, HasMore: paged.HasMore, Total: paged.Total}, nil
+}
+
+// queryRoles 按租户和过滤条件查询角色元数据（不分页），defaultOrder 为未指定排序字段时的排序
+func (m *roleManager) queryRoles(ctx context.Context, tenantKey string, filter *core.RoleFilter, defaultOrder string) ([]*roleMetadata, error) {
+	decrypted := m.decryptedDescriptionSearch(filter)
+	where, args := roleConditions(tenantKey, filter, !decrypted)
+	orderBy, err := roleOrderClause(filter, defaultOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*roleMetadata
+	selectSQL := `SELECT ` + roleMetadataColumns + ` FROM system_roles` + where + ` ORDER BY ` + orderBy
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, args...); err != nil {
+		return nil, err
+	}
+	if !decrypted {
+		return records, nil
+	}
+
+	matched := records[:0]
+	for _, record := range records {
+		description, err := m.decryptNullString(record.Description)
+		if err != nil {
+			return nil, err
+		}
+		if containsFold(description, filter.DescriptionContains) {
+			matched = append(matched, record)
+		}
+	}
+	return matched, nil
+}
+
+// decryptedDescriptionSearch 是否需要在解密后按描述过滤（启用了字段加密，数据库中的描述是密文）
+func (m *roleManager) decryptedDescriptionSearch(filter *core.RoleFilter) bool {
+	return filter != nil && filter.DescriptionContains != "" && m.cipher.Enabled()
+}
+
+// rolesWithPermissions 将角色元数据转换为角色，一次遍历批量加载所有角色的权限，避免逐个角色查询
+func (m *roleManager) rolesWithPermissions(records []*roleMetadata) ([]*core.Role, error) {
+	roles := make([]*core.Role, 0, len(records))
+	subjects := make([]string, 0, len(records))
+	for _, record := range records {
+		description, err := m.decryptNullString(record.Description)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, &core.Role{
+			Key:         record.RoleKey,
+			Name:        record.Name,
+			Description: description,
+			TenantKey:   record.TenantKey,
+		})
+		subjects = append(subjects, m.enforcer.RoleSubject(record.RoleKey))
+	}
+
+	policiesBySubject, err := m.enforcer.GetPoliciesForSubjects(subjects)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		role.Permissions = rolePermissions(policiesBySubject[m.enforcer.RoleSubject(role.Key)])
+	}
+	return roles, nil
+}
+
+// roleConditions 生成角色查询的 WHERE 子句和参数
+// 指定租户时包括该租户的角色、全局角色和链接到该租户的共享角色；withDescription 为 false 时不在 SQL 中匹配描述
+func roleConditions(tenantKey string, filter *core.RoleFilter, withDescription bool) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if tenantKey != "" {
+		add(`(tenant_key = $? OR tenant_key = '*' OR role_key IN (SELECT role_key FROM system_shared_role_links WHERE tenant_key = $?))`, tenantKey)
+	}
+	if filter != nil {
+		if filter.KeyPattern != "" {
+			add("role_key ILIKE $?", globToLike(filter.KeyPattern))
+		}
+		if filter.NamePattern != "" {
+			add("name ILIKE $?", globToLike(filter.NamePattern))
+		}
+		if filter.DescriptionContains != "" && withDescription {
+			add("description ILIKE $?", "%"+escapeLike(filter.DescriptionContains)+"%")
+		}
+		if filter.TenantKey != "" {
+			add("tenant_key = $?", filter.TenantKey)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// roleOrderClause 生成 ORDER BY 子句，排序字段相同时按角色键排序以保证翻页顺序稳定
+func roleOrderClause(filter *core.RoleFilter, defaultOrder string) (string, error) {
+	if filter == nil || filter.OrderBy == "" {
+		if defaultOrder == "role_key" {
+			return defaultOrder, nil
+		}
+		return defaultOrder + ", role_key", nil
+	}
+
+	column, ok := roleOrderColumns[filter.OrderBy]
+	if !ok {
+		return "", core.NewFieldError("filter.orderBy", core.ConstraintOneOf)
+	}
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	if column == "role_key" {
+		return column + " " + direction, nil
+	}
+	return column + " " + direction + ", role_key", nil
+}
+
+// globToLike 将 glob 模式转换为 LIKE 模式：* 匹配任意字符串，? 匹配单个字符，其余字符按字面匹配
+func globToLike(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteByte('%')
+		case '?':
+			b.WriteByte('_')
+		default:
+			b.WriteString(escapeLike(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// escapeLike 转义 LIKE 模式中的特殊字符（PostgreSQL 默认转义符为反斜杠）
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// containsFold 忽略大小写检查 s 是否包含 substr
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
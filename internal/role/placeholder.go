@@ -0,0 +1,114 @@
+package role
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// placeholderPermission 角色占位权限，用于标识没有任何实际权限的角色
+var placeholderPermission = core.Permission{Resource: core.ResourcePlaceholder, Action: core.ActionNone}
+
+// isPlaceholder 检查策略是否为占位权限
+func isPlaceholder(policy core.Policy) bool {
+	return policy.Resource == placeholderPermission.Resource && policy.Action == placeholderPermission.Action
+}
+
+// removePlaceholder 移除角色的占位权限（角色获得实际权限后调用）
+func (m *roleManager) removePlaceholder(ctx context.Context, roleKey, tenantKey string) error {
+	return m.enforcer.RemovePolicy(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, placeholderPermission)
+}
+
+// ensurePlaceholder 角色没有任何实际权限时补充占位权限
+func (m *roleManager) ensurePlaceholder(ctx context.Context, roleKey, tenantKey string) error {
+	policies, err := m.enforcer.GetPolicies(m.enforcer.RoleSubject(roleKey), "")
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if !isPlaceholder(policy) {
+			return nil
+		}
+	}
+
+	return m.enforcer.AddPolicy(ctx, m.enforcer.RoleSubject(roleKey), tenantKey, placeholderPermission)
+}
+
+// EnsurePlaceholder 角色没有任何实际权限时补充占位权限（批量撤销后调用，保证角色仍可被识别）
+func (m *roleManager) EnsurePlaceholder(ctx context.Context, roleKey string) error {
+	role, err := m.GetRole(ctx, roleKey)
+	if err != nil {
+		return err
+	}
+	return m.ensurePlaceholder(ctx, roleKey, role.TenantKey)
+}
+
+// restorePlaceholdersAfterCleanup 对象清理回调：失去全部实际权限的角色补充占位权限
+func (m *roleManager) restorePlaceholdersAfterCleanup(ctx context.Context, resourceType, objectID string, removed []core.Policy) error {
+	checked := make(map[string]bool)
+	for _, policy := range removed {
+		if policy.SubjectKind == core.SubjectKindUser || checked[policy.Subject] {
+			continue
+		}
+		checked[policy.Subject] = true
+
+		isRole, err := m.isRoleExistsInDB(ctx, policy.Subject)
+		if err != nil {
+			return err
+		}
+		if !isRole {
+			continue
+		}
+		if err := m.EnsurePlaceholder(ctx, policy.Subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CleanupPlaceholders 清理多余的占位权限
+// 移除已拥有实际权限的角色的占位权限，以及角色已被删除后遗留的占位权限，返回清理的策略数量
+func (m *roleManager) CleanupPlaceholders(ctx context.Context) (int, error) {
+	policies, err := m.enforcer.GetAllPolicies()
+	if err != nil {
+		return 0, err
+	}
+
+	// 统计拥有实际权限的主体（启用主体命名空间时跳过用户的直接授权）
+	hasRealPermissions := make(map[string]bool)
+	for _, policy := range policies {
+		if policy.SubjectKind == core.SubjectKindUser {
+			continue
+		}
+		if !isPlaceholder(policy) {
+			hasRealPermissions[policy.Subject] = true
+		}
+	}
+
+	removed := 0
+	for _, policy := range policies {
+		if !isPlaceholder(policy) {
+			continue
+		}
+
+		stale := hasRealPermissions[policy.Subject]
+		if !stale {
+			exists, err := m.isRoleExistsInDB(ctx, policy.Subject)
+			if err != nil {
+				return removed, err
+			}
+			stale = !exists
+		}
+		if !stale {
+			continue
+		}
+
+		if err := m.enforcer.RemovePolicy(ctx, m.enforcer.RoleSubject(policy.Subject), policy.Domain, placeholderPermission); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
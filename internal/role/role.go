@@ -1,34 +1,69 @@
 package role
 
 import (
+	"context"
+
 	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // Manager 角色权限管理器接口
 type Manager interface {
 	// 角色管理
-	CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error // 创建角色
-	UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error // 更新角色信息
-	DeleteRole(roleKey string) error                                                                               // 删除角色
-	GetRole(roleKey string) (*core.Role, error)                                                                    // 获取角色详情
-	ListRoles(tenantKey string, filter *core.RoleFilter) ([]*core.Role, error)                                     // 获取角色列表
+	CreateRole(ctx context.Context, operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error          // 创建角色
+	UpdateRole(ctx context.Context, operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission) error          // 更新角色信息
+	DeleteRole(ctx context.Context, roleKey string) error                                                                                        // 删除角色
+	GetRole(ctx context.Context, roleKey string) (*core.Role, error)                                                                             // 获取角色详情
+	ListRoles(ctx context.Context, tenantKey string, filter *core.RoleFilter) ([]*core.Role, error)                                              // 获取角色列表
+	ListRolesPage(ctx context.Context, tenantKey string, filter *core.RoleFilter, page core.PageRequest) (*core.PageResponse[*core.Role], error) // 分页获取角色列表（在数据库中分页，只加载当前页的权限）
 
 	// 角色系统权限检查
-	HasSystemPermissions(roleKey string) (bool, error)                             // 检查角色是否包含系统权限
-	UserRoleHasSystemPermissions(userKey, roleKey, tenantKey string) (bool, error) // 检查用户的角色是否包含系统权限
+	HasSystemPermissions(ctx context.Context, roleKey string) (bool, error)                             // 检查角色是否包含系统权限
+	UserRoleHasSystemPermissions(ctx context.Context, userKey, roleKey, tenantKey string) (bool, error) // 检查用户的角色是否包含系统权限
 
 	// 角色权限管理
-	GetRolePermissions(roleKey string) ([]core.Permission, error)                   // 获取角色权限列表
-	GrantPermission(operatorKey, roleKey string, permission core.Permission) error  // 授予角色权限
-	RevokePermission(operatorKey, roleKey string, permission core.Permission) error // 撤销角色权限
-	SetRolePermissions(roleKey string, permissions []core.Permission) error         // 设置角色权限(覆盖)
+	GetRolePermissions(ctx context.Context, roleKey string) ([]core.Permission, error)                   // 获取角色权限列表
+	GetRoleEffectivePermissions(ctx context.Context, roleKey string) ([]core.InheritedPermission, error) // 获取角色有效权限（展开继承链并标注来源角色）
+	GrantPermission(ctx context.Context, operatorKey, roleKey string, permission core.Permission) error  // 授予角色权限
+	RevokePermission(ctx context.Context, operatorKey, roleKey string, permission core.Permission) error // 撤销角色权限
+	SetRolePermissions(ctx context.Context, roleKey string, permissions []core.Permission) error         // 设置角色权限(覆盖)
+	CleanupPlaceholders(ctx context.Context) (int, error)                                                // 清理多余的占位权限，返回清理数量
+	EnsurePlaceholder(ctx context.Context, roleKey string) error                                         // 角色没有实际权限时补充占位权限
+	MigrateSubjectNamespaces(ctx context.Context) (*core.SubjectMigrationReport, error)                  // 将存量策略迁移为带命名空间前缀的主体
+	RenameTenant(ctx context.Context, oldTenantKey, newTenantKey string) (int, error)                    // 将归属旧租户的角色改写到新租户
+	DeleteTenantRoles(ctx context.Context, tenantKey string) ([]string, error)                           // 删除归属租户的所有角色，返回删除的角色键
+
+	// 角色继承
+	AddRoleInheritance(ctx context.Context, parentRoleKey, childRoleKey, tenantKey string) error    // 添加角色继承（子角色继承父角色的权限）
+	RemoveRoleInheritance(ctx context.Context, parentRoleKey, childRoleKey, tenantKey string) error // 移除角色继承
+	GetRoleHierarchy(ctx context.Context, tenantKey string) ([]core.RoleInheritance, error)         // 获取租户内生效的角色继承关系
+
+	// 角色冻结
+	LockRole(ctx context.Context, operatorKey, roleKey, reason string) error // 冻结角色
+	UnlockRole(ctx context.Context, roleKey string) error                    // 解冻角色
+	GetRoleLock(ctx context.Context, roleKey string) (*core.RoleLock, error) // 获取角色冻结信息，未冻结时返回 nil
+
+	// 角色共享（跨租户只读链接）
+	PublishRole(ctx context.Context, operatorKey, roleKey string) error               // 发布角色为共享角色
+	UnpublishRole(ctx context.Context, roleKey string, force bool) error              // 取消发布共享角色（存在链接时需 force 才会一并移除）
+	LinkSharedRole(ctx context.Context, operatorKey, roleKey, tenantKey string) error // 将共享角色链接到租户
+	UnlinkSharedRole(ctx context.Context, roleKey, tenantKey string) error            // 取消共享角色与租户的链接
+	ListSharedRoles(ctx context.Context) ([]*core.SharedRole, error)                  // 获取所有共享角色
+	IsRoleLinked(ctx context.Context, roleKey, tenantKey string) bool                 // 检查共享角色是否已链接到租户
 
 	// 角色用户管理
-	GetUsersWithRole(roleKey, tenantKey string) ([]string, error)           // 获取拥有指定角色的用户列表
-	GetAllGroupingPolicies(tenantKey string) ([]core.GroupingPolicy, error) // 获取指定租户的所有角色分配
+	GetUsersWithRole(ctx context.Context, roleKey, tenantKey string) ([]string, error)                          // 获取拥有指定角色的用户列表
+	GetUsersWithPermission(ctx context.Context, tenantKey string, permission core.Permission) ([]string, error) // 获取在租户内拥有指定权限的用户列表（含角色授权）
+	GetAllGroupingPolicies(ctx context.Context, tenantKey string) ([]core.GroupingPolicy, error)                // 获取指定租户的所有角色分配
+
+	// 事务回滚
+	BeginMetadataJournal() (*MetadataJournal, error)                           // 开始记录角色元数据变更
+	EndMetadataJournal(journal *MetadataJournal)                               // 停止记录角色元数据变更
+	RevertMetadataJournal(ctx context.Context, journal *MetadataJournal) error // 将日志中的角色恢复到首次写入前的元数据
 }
 
 // NewManager 创建角色权限管理器
-func NewManager(dsn string, enforcer *core.Enforcer, securityValidator *core.SecurityValidator) (Manager, error) {
-	return newRoleManager(dsn, enforcer, securityValidator)
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer, securityValidator *core.SecurityValidator, cipher *core.FieldCipher) (Manager, error) {
+	return newRoleManager(dbConn, enforcer, securityValidator, cipher)
 }
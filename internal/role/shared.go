@@ -0,0 +1,235 @@
+package role
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// PublishRole 发布角色为共享角色
+func (m *roleManager) PublishRole(ctx context.Context, operatorKey, roleKey string) error {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return err
+	}
+
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
+	if err != nil {
+		return err
+	}
+	if !isRole {
+		return fmt.Errorf("'%s' 不是一个有效的角色", roleKey)
+	}
+
+	// 系统角色不允许共享，避免系统权限通过链接扩散到其他租户
+	if hasSystemPerms, _ := m.HasSystemPermissions(ctx, roleKey); hasSystemPerms {
+		return core.ErrSystemRoleImmutable
+	}
+
+	insertSQL := `
+		INSERT INTO system_shared_roles (role_key, published_by)
+		VALUES ($1, $2)
+		ON CONFLICT (role_key) DO NOTHING
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, insertSQL, roleKey, operatorKey); err != nil {
+		return fmt.Errorf("发布共享角色失败: %v", err)
+	}
+
+	return nil
+}
+
+// UnpublishRole 取消发布共享角色
+// 仍链接到其他租户时返回 ErrSharedRoleLinked，force 为 true 时同时移除所有租户链接
+func (m *roleManager) UnpublishRole(ctx context.Context, roleKey string, force bool) error {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return err
+	}
+
+	if !force {
+		var count int
+		countSQL := `SELECT COUNT(*) FROM system_shared_role_links WHERE role_key = $1`
+		if err := m.dbConn.QueryRowCtx(ctx, &count, countSQL, roleKey); err != nil {
+			return fmt.Errorf("查询共享角色链接失败: %v", err)
+		}
+		if count > 0 {
+			return core.ErrSharedRoleLinked
+		}
+	}
+
+	if _, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_shared_role_links WHERE role_key = $1`, roleKey); err != nil {
+		return fmt.Errorf("移除共享角色链接失败: %v", err)
+	}
+	if _, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_shared_roles WHERE role_key = $1`, roleKey); err != nil {
+		return fmt.Errorf("取消发布共享角色失败: %v", err)
+	}
+
+	return m.syncSharedLinks(ctx)
+}
+
+// LinkSharedRole 将共享角色链接到租户
+func (m *roleManager) LinkSharedRole(ctx context.Context, operatorKey, roleKey, tenantKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("roleKey", roleKey),
+		core.RequireTenantArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	published, err := m.isRolePublished(ctx, roleKey)
+	if err != nil {
+		return err
+	}
+	if !published {
+		return core.ErrRoleNotShared
+	}
+
+	role, err := m.GetRole(ctx, roleKey)
+	if err != nil {
+		return err
+	}
+	if role.TenantKey == tenantKey {
+		return fmt.Errorf("角色 '%s' 本身属于租户 '%s'，无需链接", roleKey, tenantKey)
+	}
+
+	insertSQL := `
+		INSERT INTO system_shared_role_links (role_key, tenant_key, linked_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (role_key, tenant_key) DO NOTHING
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, insertSQL, roleKey, tenantKey, operatorKey); err != nil {
+		return fmt.Errorf("链接共享角色失败: %v", err)
+	}
+
+	return m.syncSharedLinks(ctx)
+}
+
+// UnlinkSharedRole 取消共享角色与租户的链接
+func (m *roleManager) UnlinkSharedRole(ctx context.Context, roleKey, tenantKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		return err
+	}
+
+	// 租户内仍有用户分配该角色时不允许取消链接，避免留下无效分配
+	users, err := m.enforcer.GetUsersWithRole(roleKey, tenantKey)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return core.ErrSharedRoleInUse
+	}
+
+	deleteSQL := `DELETE FROM system_shared_role_links WHERE role_key = $1 AND tenant_key = $2`
+	if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, roleKey, tenantKey); err != nil {
+		return fmt.Errorf("取消共享角色链接失败: %v", err)
+	}
+
+	return m.syncSharedLinks(ctx)
+}
+
+// ListSharedRoles 获取所有共享角色及其链接租户
+func (m *roleManager) ListSharedRoles(ctx context.Context) ([]*core.SharedRole, error) {
+	var records []*sharedRoleRecord
+	selectSQL := `
+		SELECT s.role_key, r.tenant_key, s.published_by, s.published_at
+		FROM system_shared_roles s
+		JOIN system_roles r ON r.role_key = s.role_key
+		ORDER BY s.published_at DESC
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return nil, fmt.Errorf("查询共享角色失败: %v", err)
+	}
+
+	m.sharedMu.RLock()
+	defer m.sharedMu.RUnlock()
+
+	sharedRoles := make([]*core.SharedRole, 0, len(records))
+	for _, record := range records {
+		sharedRole := &core.SharedRole{
+			RoleKey:         record.RoleKey,
+			SourceTenantKey: record.SourceTenantKey,
+			LinkedTenants:   []string{},
+		}
+		if record.PublishedBy.Valid {
+			sharedRole.PublishedBy = record.PublishedBy.String
+		}
+		if record.PublishedAt.Valid {
+			sharedRole.PublishedAt = record.PublishedAt.Time
+		}
+		for tenantKey, roles := range m.sharedLinks {
+			if _, linked := roles[record.RoleKey]; linked {
+				sharedRole.LinkedTenants = append(sharedRole.LinkedTenants, tenantKey)
+			}
+		}
+		sharedRoles = append(sharedRoles, sharedRole)
+	}
+
+	return sharedRoles, nil
+}
+
+// IsRoleLinked 检查共享角色是否已链接到租户
+func (m *roleManager) IsRoleLinked(ctx context.Context, roleKey, tenantKey string) bool {
+	m.sharedMu.RLock()
+	defer m.sharedMu.RUnlock()
+
+	_, linked := m.sharedLinks[tenantKey][roleKey]
+	return linked
+}
+
+// resolveSharedRoleDomains 角色权限域解析：链接到租户的共享角色需要查询其来源租户的权限
+func (m *roleManager) resolveSharedRoleDomains(roleKey, domain string) []string {
+	m.sharedMu.RLock()
+	defer m.sharedMu.RUnlock()
+
+	if sourceTenant, linked := m.sharedLinks[domain][roleKey]; linked {
+		return []string{sourceTenant}
+	}
+	return nil
+}
+
+// isRolePublished 检查角色是否已发布为共享角色
+func (m *roleManager) isRolePublished(ctx context.Context, roleKey string) (bool, error) {
+	var count int
+	countSQL := `SELECT COUNT(*) FROM system_shared_roles WHERE role_key = $1`
+	if err := m.dbConn.QueryRowCtx(ctx, &count, countSQL, roleKey); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// loadSharedLinks 从数据库加载共享角色链接到内存
+func (m *roleManager) loadSharedLinks(ctx context.Context) error {
+	var records []*sharedRoleLinkRecord
+	selectSQL := `
+		SELECT l.role_key, l.tenant_key, r.tenant_key AS source_tenant_key
+		FROM system_shared_role_links l
+		JOIN system_roles r ON r.role_key = l.role_key
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载共享角色链接失败: %v", err)
+	}
+
+	links := make(map[string]map[string]string)
+	for _, record := range records {
+		if _, exists := links[record.TenantKey]; !exists {
+			links[record.TenantKey] = make(map[string]string)
+		}
+		links[record.TenantKey][record.RoleKey] = record.SourceTenantKey
+	}
+
+	m.sharedMu.Lock()
+	m.sharedLinks = links
+	m.sharedMu.Unlock()
+
+	return nil
+}
+
+// syncSharedLinks 刷新本地共享角色链接并通知其他实例
+func (m *roleManager) syncSharedLinks(ctx context.Context) error {
+	if err := m.loadSharedLinks(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
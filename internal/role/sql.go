@@ -7,6 +7,9 @@ import (
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
+// roleMetadataColumns 查询角色元数据的列
+const roleMetadataColumns = `role_key, name, description, tenant_key, created_at, updated_at, created_by`
+
 // roleMetadata 角色元数据结构体
 type roleMetadata struct {
 	RoleKey     string         `db:"role_key"`
@@ -18,8 +21,44 @@ type roleMetadata struct {
 	CreatedBy   sql.NullString `db:"created_by"`
 }
 
-// initDB 初始化数据库，创建角色元数据表
+// sharedRoleRecord 共享角色记录
+type sharedRoleRecord struct {
+	RoleKey         string         `db:"role_key"`
+	SourceTenantKey string         `db:"tenant_key"`
+	PublishedBy     sql.NullString `db:"published_by"`
+	PublishedAt     sql.NullTime   `db:"published_at"`
+}
+
+// sharedRoleLinkRecord 共享角色链接记录
+type sharedRoleLinkRecord struct {
+	RoleKey         string `db:"role_key"`
+	TenantKey       string `db:"tenant_key"`
+	SourceTenantKey string `db:"source_tenant_key"`
+}
+
+// roleLockRecord 角色冻结记录
+type roleLockRecord struct {
+	RoleKey  string         `db:"role_key"`
+	Reason   sql.NullString `db:"reason"`
+	LockedBy sql.NullString `db:"locked_by"`
+	LockedAt sql.NullTime   `db:"locked_at"`
+}
+
+// initDB 初始化数据库，创建角色相关表
 func initDB(dbConn sqlx.SqlConn) error {
+	if err := initRolesTable(dbConn); err != nil {
+		return err
+	}
+
+	if err := initRoleLocksTable(dbConn); err != nil {
+		return err
+	}
+
+	return initSharedRolesTables(dbConn)
+}
+
+// initRolesTable 创建角色元数据表
+func initRolesTable(dbConn sqlx.SqlConn) error {
 	// 先检查表是否存在，如果存在就跳过创建
 	exists, err := tableExists(dbConn, "system_roles")
 	if err != nil {
@@ -55,6 +94,53 @@ CREATE INDEX idx_system_roles_created_at ON system_roles(created_at);
 	return nil
 }
 
+// initRoleLocksTable 创建角色冻结表
+func initRoleLocksTable(dbConn sqlx.SqlConn) error {
+	createLocksTableSQL := `
+CREATE TABLE IF NOT EXISTS system_role_locks (
+    role_key VARCHAR(255) PRIMARY KEY,
+    reason TEXT,
+    locked_by VARCHAR(255),
+    locked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+	_, err := dbConn.Exec(createLocksTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_role_locks表失败: %v", err)
+	}
+
+	return nil
+}
+
+// initSharedRolesTables 创建共享角色发布表和链接表
+func initSharedRolesTables(dbConn sqlx.SqlConn) error {
+	createSharedTablesSQL := `
+CREATE TABLE IF NOT EXISTS system_shared_roles (
+    role_key VARCHAR(255) PRIMARY KEY,
+    published_by VARCHAR(255),
+    published_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS system_shared_role_links (
+    role_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    linked_by VARCHAR(255),
+    linked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role_key, tenant_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_shared_role_links_tenant_key ON system_shared_role_links(tenant_key);
+`
+
+	_, err := dbConn.Exec(createSharedTablesSQL)
+	if err != nil {
+		return fmt.Errorf("创建共享角色表失败: %v", err)
+	}
+
+	return nil
+}
+
 // tableExists 检查表是否存在
 func tableExists(dbConn sqlx.SqlConn, tableName string) (bool, error) {
 	var exists bool
@@ -0,0 +1,119 @@
+package role
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// RenameTenant 将归属旧租户的角色和共享角色链接改写到新租户，返回改写的角色数量
+func (m *roleManager) RenameTenant(ctx context.Context, oldTenantKey, newTenantKey string) (int, error) {
+	if err := m.journalTenantRoles(ctx, oldTenantKey); err != nil {
+		return 0, err
+	}
+
+	var renamed int64
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		result, err := session.ExecCtx(ctx, `UPDATE system_roles SET tenant_key = $2, updated_at = CURRENT_TIMESTAMP WHERE tenant_key = $1`, oldTenantKey, newTenantKey)
+		if err != nil {
+			return fmt.Errorf("改写角色归属租户失败: %v", err)
+		}
+		if renamed, err = result.RowsAffected(); err != nil {
+			return err
+		}
+
+		// 新租户已链接的共享角色保留新链接，删除旧租户的重复链接
+		deleteSQL := `
+			DELETE FROM system_shared_role_links o
+			WHERE o.tenant_key = $1
+			  AND EXISTS (SELECT 1 FROM system_shared_role_links n WHERE n.tenant_key = $2 AND n.role_key = o.role_key)
+		`
+		if _, err := session.ExecCtx(ctx, deleteSQL, oldTenantKey, newTenantKey); err != nil {
+			return fmt.Errorf("清理重复的共享角色链接失败: %v", err)
+		}
+		if _, err := session.ExecCtx(ctx, `UPDATE system_shared_role_links SET tenant_key = $2 WHERE tenant_key = $1`, oldTenantKey, newTenantKey); err != nil {
+			return fmt.Errorf("改写共享角色链接失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(renamed), m.syncSharedLinks(ctx)
+}
+
+// DeleteTenantRoles 删除归属租户的所有角色（含系统角色）及其权限、继承关系、用户分配、冻结和共享状态，
+// 同时移除其他共享角色到该租户的链接，返回删除的角色键
+func (m *roleManager) DeleteTenantRoles(ctx context.Context, tenantKey string) ([]string, error) {
+	if err := core.RequireTenantArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	roles, err := m.listRoleMetadata(ctx, tenantKey)
+	if err != nil {
+		return nil, fmt.Errorf("获取租户角色失败: %v", err)
+	}
+
+	var roleKeys []string
+	for _, role := range roles {
+		if role.TenantKey != tenantKey {
+			continue
+		}
+		if err := m.enforcer.ClearPolicies(ctx, m.enforcer.RoleSubject(role.RoleKey)); err != nil {
+			return roleKeys, err
+		}
+		if err := m.clearRoleInheritance(ctx, role.RoleKey); err != nil {
+			return roleKeys, err
+		}
+		if err := m.enforcer.ClearRoleAssignments(ctx, role.RoleKey); err != nil {
+			return roleKeys, err
+		}
+		roleKeys = append(roleKeys, role.RoleKey)
+	}
+	if err := m.journalRoles(ctx, roleKeys...); err != nil {
+		return roleKeys, err
+	}
+
+	err = m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		deleteSQLs := []string{
+			`DELETE FROM system_shared_role_links WHERE tenant_key = $1 OR role_key IN (SELECT role_key FROM system_roles WHERE tenant_key = $1)`,
+			`DELETE FROM system_shared_roles WHERE role_key IN (SELECT role_key FROM system_roles WHERE tenant_key = $1)`,
+			`DELETE FROM system_role_locks WHERE role_key IN (SELECT role_key FROM system_roles WHERE tenant_key = $1)`,
+			`DELETE FROM system_roles WHERE tenant_key = $1`,
+		}
+		for _, deleteSQL := range deleteSQLs {
+			if _, err := session.ExecCtx(ctx, deleteSQL, tenantKey); err != nil {
+				return fmt.Errorf("删除租户角色元数据失败: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return roleKeys, err
+	}
+
+	return roleKeys, m.syncSharedLinks(ctx)
+}
+
+// journalTenantRoles 在批量改写前记录归属租户的角色的当前元数据（未在记录时忽略）
+func (m *roleManager) journalTenantRoles(ctx context.Context, tenantKey string) error {
+	if m.journal.Load() == nil {
+		return nil
+	}
+
+	roles, err := m.listRoleMetadata(ctx, tenantKey)
+	if err != nil {
+		return fmt.Errorf("获取租户角色失败: %v", err)
+	}
+	var roleKeys []string
+	for _, role := range roles {
+		if role.TenantKey == tenantKey {
+			roleKeys = append(roleKeys, role.RoleKey)
+		}
+	}
+	return m.journalRoles(ctx, roleKeys...)
+}
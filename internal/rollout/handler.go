@@ -0,0 +1,243 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// rolloutManager 灰度变更管理器实现
+type rolloutManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu     sync.RWMutex
+	staged []core.PolicyRollout // 进行中的灰度变更
+}
+
+// newRolloutManager 创建灰度变更管理器实现
+func newRolloutManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*rolloutManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("灰度变更管理器初始化失败: %v", err)
+	}
+
+	manager := &rolloutManager{
+		enforcer: enforcer,
+		dbConn:   dbConn,
+	}
+
+	if err := manager.loadRollouts(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新灰度变更，并在权限检查时按用户应用
+	enforcer.AddReloadHook(func() error { return manager.loadRollouts(context.Background()) })
+	enforcer.SetRolloutResolver(manager.resolveRollouts)
+
+	return manager, nil
+}
+
+// StageChange 创建角色权限的灰度变更
+func (m *rolloutManager) StageChange(ctx context.Context, operatorKey, roleKey, domain string, permission core.Permission, change core.RolloutChange, target core.RolloutTarget) (*core.PolicyRollout, error) {
+	if err := core.FirstError(
+		core.RequireArg("roleKey", roleKey),
+		core.RequireArg("domain", domain),
+		core.RequirePermissionArg("permission", permission),
+	); err != nil {
+		return nil, err
+	}
+	if change != core.RolloutChangeGrant && change != core.RolloutChangeRevoke {
+		return nil, core.NewFieldError("change", core.ConstraintOneOf)
+	}
+	if err := validateTarget(target); err != nil {
+		return nil, err
+	}
+
+	var id int64
+	insertSQL := `
+		INSERT INTO system_policy_rollouts (role_key, domain, resource, action, change_type, percentage, tenants, status, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (role_key, domain, resource, action) WHERE status = 'staged' DO NOTHING
+		RETURNING id
+	`
+	err := m.dbConn.QueryRowCtx(ctx, &id, insertSQL, roleKey, domain, string(permission.QualifiedResource()), string(permission.Action),
+		string(change), target.Percentage, strings.Join(target.Tenants, ","), string(core.RolloutStatusStaged), operatorKey)
+	if err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrRolloutConflict
+		}
+		return nil, fmt.Errorf("创建灰度变更失败: %v", err)
+	}
+
+	if err := m.sync(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.GetRollout(ctx, id)
+}
+
+// UpdateTarget 调整进行中灰度变更的范围
+func (m *rolloutManager) UpdateTarget(ctx context.Context, rolloutID int64, target core.RolloutTarget) error {
+	if err := validateTarget(target); err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE system_policy_rollouts SET percentage = $2, tenants = $3 WHERE id = $1 AND status = $4`
+	result, err := m.dbConn.ExecCtx(ctx, updateSQL, rolloutID, target.Percentage, strings.Join(target.Tenants, ","), string(core.RolloutStatusStaged))
+	if err != nil {
+		return fmt.Errorf("调整灰度范围失败: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return core.ErrRolloutNotFound
+	}
+
+	return m.sync(ctx)
+}
+
+// GetRollout 获取灰度变更
+func (m *rolloutManager) GetRollout(ctx context.Context, rolloutID int64) (*core.PolicyRollout, error) {
+	var record rolloutRecord
+	selectSQL := `
+		SELECT id, role_key, domain, resource, action, change_type, percentage, tenants, status, created_by, created_at, finished_by, finished_at
+		FROM system_policy_rollouts WHERE id = $1
+	`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, rolloutID); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrRolloutNotFound
+		}
+		return nil, fmt.Errorf("查询灰度变更失败: %v", err)
+	}
+
+	rollout := toRollout(&record)
+	return &rollout, nil
+}
+
+// ListStagedRollouts 获取进行中的灰度变更
+func (m *rolloutManager) ListStagedRollouts(ctx context.Context, roleKey string) ([]*core.PolicyRollout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rollouts := make([]*core.PolicyRollout, 0, len(m.staged))
+	for _, rollout := range m.staged {
+		if roleKey != "" && rollout.RoleKey != roleKey {
+			continue
+		}
+		copied := rollout
+		copied.Target.Tenants = append([]string{}, rollout.Target.Tenants...)
+		rollouts = append(rollouts, &copied)
+	}
+	return rollouts, nil
+}
+
+// FinishRollout 结束灰度变更
+func (m *rolloutManager) FinishRollout(ctx context.Context, operatorKey string, rolloutID int64, status core.RolloutStatus) error {
+	if status != core.RolloutStatusPromoted && status != core.RolloutStatusRolledBack {
+		return core.NewFieldError("status", core.ConstraintOneOf)
+	}
+
+	updateSQL := `
+		UPDATE system_policy_rollouts SET status = $2, finished_by = $3, finished_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $4
+	`
+	result, err := m.dbConn.ExecCtx(ctx, updateSQL, rolloutID, string(status), operatorKey, string(core.RolloutStatusStaged))
+	if err != nil {
+		return fmt.Errorf("结束灰度变更失败: %v", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return core.ErrRolloutNotFound
+	}
+
+	return m.sync(ctx)
+}
+
+// resolveRollouts 灰度变更解析：返回在指定域内对用户生效的灰度变更
+func (m *rolloutManager) resolveRollouts(userKey, domain string) []core.PolicyRollout {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var applicable []core.PolicyRollout
+	for _, rollout := range m.staged {
+		if rollout.AppliesTo(userKey, domain) {
+			applicable = append(applicable, rollout)
+		}
+	}
+	return applicable
+}
+
+// loadRollouts 从数据库加载进行中的灰度变更到内存
+func (m *rolloutManager) loadRollouts(ctx context.Context) error {
+	var records []*rolloutRecord
+	selectSQL := `
+		SELECT id, role_key, domain, resource, action, change_type, percentage, tenants, status, created_by, created_at, finished_by, finished_at
+		FROM system_policy_rollouts WHERE status = $1 ORDER BY id
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, string(core.RolloutStatusStaged)); err != nil {
+		return fmt.Errorf("加载灰度变更失败: %v", err)
+	}
+
+	staged := make([]core.PolicyRollout, 0, len(records))
+	for _, record := range records {
+		staged = append(staged, toRollout(record))
+	}
+
+	m.mu.Lock()
+	m.staged = staged
+	m.mu.Unlock()
+
+	return nil
+}
+
+// sync 刷新本地灰度变更并通知其他实例
+func (m *rolloutManager) sync(ctx context.Context) error {
+	if err := m.loadRollouts(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
+
+// validateTarget 验证灰度范围
+func validateTarget(target core.RolloutTarget) error {
+	if err := core.CheckArg(target.Percentage >= 0 && target.Percentage <= 100, "target.percentage", core.ConstraintRange); err != nil {
+		return err
+	}
+	for _, tenant := range target.Tenants {
+		if err := core.FirstError(
+			core.RequireTenantArg("tenant", tenant),
+			core.CheckArg(!strings.Contains(tenant, ","), "tenant", core.ConstraintFormat),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toRollout 转换灰度变更记录
+func toRollout(record *rolloutRecord) core.PolicyRollout {
+	rollout := core.PolicyRollout{
+		ID:         record.ID,
+		RoleKey:    record.RoleKey,
+		Domain:     record.Domain,
+		Permission: core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.Action)},
+		Change:     core.RolloutChange(record.Change),
+		Target:     core.RolloutTarget{Percentage: record.Percentage},
+		Status:     core.RolloutStatus(record.Status),
+		CreatedBy:  record.CreatedBy,
+		CreatedAt:  record.CreatedAt,
+	}
+	if record.Tenants != "" {
+		rollout.Target.Tenants = strings.Split(record.Tenants, ",")
+	}
+	if record.FinishedBy.Valid {
+		rollout.FinishedBy = record.FinishedBy.String
+	}
+	if record.FinishedAt.Valid {
+		rollout.FinishedAt = record.FinishedAt.Time
+	}
+	return rollout
+}
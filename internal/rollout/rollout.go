@@ -0,0 +1,23 @@
+package rollout
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 灰度变更管理器接口
+type Manager interface {
+	StageChange(ctx context.Context, operatorKey, roleKey, domain string, permission core.Permission, change core.RolloutChange, target core.RolloutTarget) (*core.PolicyRollout, error) // 创建角色权限的灰度变更
+	UpdateTarget(ctx context.Context, rolloutID int64, target core.RolloutTarget) error                                                                                                  // 调整灰度范围
+	GetRollout(ctx context.Context, rolloutID int64) (*core.PolicyRollout, error)                                                                                                        // 获取灰度变更
+	ListStagedRollouts(ctx context.Context, roleKey string) ([]*core.PolicyRollout, error)                                                                                               // 获取进行中的灰度变更（roleKey 为空时返回全部）
+	FinishRollout(ctx context.Context, operatorKey string, rolloutID int64, status core.RolloutStatus) error                                                                             // 结束灰度变更（全量发布或回滚）
+}
+
+// NewManager 创建灰度变更管理器
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newRolloutManager(dbConn, enforcer)
+}
@@ -0,0 +1,62 @@
+package rollout
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// rolloutRecord 灰度变更记录
+type rolloutRecord struct {
+	ID         int64          `db:"id"`
+	RoleKey    string         `db:"role_key"`
+	Domain     string         `db:"domain"`
+	Resource   string         `db:"resource"`
+	Action     string         `db:"action"`
+	Change     string         `db:"change_type"`
+	Percentage int            `db:"percentage"`
+	Tenants    string         `db:"tenants"`
+	Status     string         `db:"status"`
+	CreatedBy  string         `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	FinishedBy sql.NullString `db:"finished_by"`
+	FinishedAt sql.NullTime   `db:"finished_at"`
+}
+
+// initDB 初始化数据库，创建灰度变更表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_policy_rollouts (
+    id BIGSERIAL PRIMARY KEY,
+    role_key VARCHAR(255) NOT NULL,
+    domain VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    change_type VARCHAR(16) NOT NULL,
+    percentage INTEGER NOT NULL DEFAULT 0,
+    tenants TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_by VARCHAR(255),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+`
+	if _, err := dbConn.Exec(createTableSQL); err != nil {
+		return fmt.Errorf("创建system_policy_rollouts表失败: %v", err)
+	}
+
+	// 同一角色权限同时只允许一个进行中的灰度变更
+	createIndexSQL := `
+CREATE UNIQUE INDEX IF NOT EXISTS idx_system_policy_rollouts_staged
+    ON system_policy_rollouts (role_key, domain, resource, action)
+    WHERE status = 'staged';
+`
+	if _, err := dbConn.Exec(createIndexSQL); err != nil {
+		return fmt.Errorf("创建system_policy_rollouts索引失败: %v", err)
+	}
+
+	return nil
+}
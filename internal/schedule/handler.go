@@ -0,0 +1,281 @@
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// scheduleManager 定时权限变更管理器实现
+type scheduleManager struct {
+	dbConn sqlx.SqlConn
+}
+
+// newScheduleManager 创建定时权限变更管理器实现
+func newScheduleManager(dbConn sqlx.SqlConn) (*scheduleManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("定时权限变更管理器初始化失败: %v", err)
+	}
+	return &scheduleManager{dbConn: dbConn}, nil
+}
+
+// CreateChange 登记等待生效的定时变更
+func (m *scheduleManager) CreateChange(ctx context.Context, change core.ScheduledPermissionChange) (*core.ScheduledPermissionChange, error) {
+	if err := core.FirstError(
+		core.RequireArg("operatorKey", change.OperatorKey),
+		core.RequireArg("change.userKey", change.UserKey),
+		core.RequireArg("change.tenantKey", change.TenantKey),
+		core.CheckArg(!change.EffectiveAt.IsZero(), "effectiveAt", core.ConstraintRequired),
+	); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("生成定时变更ID失败: %v", err)
+	}
+	changeID := hex.EncodeToString(buf)
+
+	insertSQL := `
+		INSERT INTO system_scheduled_changes (change_id, action, user_key, tenant_key, resource, object_id, permission_action, operator_key, reason, environments, effective_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, insertSQL, changeID, string(change.Action), change.UserKey, change.TenantKey,
+		string(change.Permission.Resource), change.Permission.ObjectID, string(change.Permission.Action), change.OperatorKey, change.Reason,
+		strings.Join(change.Environments, ","), change.EffectiveAt, string(core.ScheduledChangePending)); err != nil {
+		return nil, fmt.Errorf("登记定时变更失败: %v", err)
+	}
+
+	return m.GetChange(ctx, changeID)
+}
+
+// GetChange 获取定时变更
+func (m *scheduleManager) GetChange(ctx context.Context, changeID string) (*core.ScheduledPermissionChange, error) {
+	if err := core.RequireArg("changeID", changeID); err != nil {
+		return nil, err
+	}
+
+	var record scheduledChangeRecord
+	selectSQL := `SELECT ` + scheduledChangeColumns + ` FROM system_scheduled_changes WHERE change_id = $1`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, changeID); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, core.ErrScheduledChangeNotFound
+		}
+		return nil, fmt.Errorf("查询定时变更失败: %v", err)
+	}
+
+	return toChange(record), nil
+}
+
+// ListChanges 按条件查询定时变更
+func (m *scheduleManager) ListChanges(ctx context.Context, filter core.ScheduledChangeFilter) ([]*core.ScheduledPermissionChange, error) {
+	var conditions []string
+	var args []any
+	add := func(column string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if filter.TenantKey != "" {
+		add("tenant_key", filter.TenantKey)
+	}
+	if filter.UserKey != "" {
+		add("user_key", filter.UserKey)
+	}
+	if filter.Status != "" {
+		add("status", string(filter.Status))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var records []scheduledChangeRecord
+	selectSQL := `SELECT ` + scheduledChangeColumns + ` FROM system_scheduled_changes` + where + ` ORDER BY effective_at, created_at`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, args...); err != nil {
+		return nil, fmt.Errorf("查询定时变更失败: %v", err)
+	}
+
+	changes := make([]*core.ScheduledPermissionChange, 0, len(records))
+	for _, record := range records {
+		changes = append(changes, toChange(record))
+	}
+	return changes, nil
+}
+
+// CancelChange 取消等待生效的变更
+func (m *scheduleManager) CancelChange(ctx context.Context, changeID, operatorKey string) (*core.ScheduledPermissionChange, error) {
+	if err := core.RequireArg("changeID", changeID); err != nil {
+		return nil, err
+	}
+
+	updateSQL := `
+		UPDATE system_scheduled_changes
+		SET status = $2, canceled_by = $3, finished_at = CURRENT_TIMESTAMP
+		WHERE change_id = $1 AND status = $4
+	`
+	result, err := m.dbConn.ExecCtx(ctx, updateSQL, changeID, string(core.ScheduledChangeCanceled), operatorKey, string(core.ScheduledChangePending))
+	if err != nil {
+		return nil, fmt.Errorf("取消定时变更失败: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("取消定时变更失败: %v", err)
+	}
+
+	change, err := m.GetChange(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, core.ErrScheduledChangeNotPending
+	}
+	return change, nil
+}
+
+// ClaimDue 认领到期的变更：在单条语句中把到期的等待变更改为执行中并返回
+// 使用 SKIP LOCKED 跳过其他实例正在认领的行，每个变更只会被一个实例认领
+func (m *scheduleManager) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*core.ScheduledPermissionChange, error) {
+	if err := core.CheckArg(limit > 0, "limit", core.ConstraintRange); err != nil {
+		return nil, err
+	}
+
+	var records []scheduledChangeRecord
+	claimSQL := `
+		UPDATE system_scheduled_changes SET status = $1
+		WHERE change_id IN (
+			SELECT change_id FROM system_scheduled_changes
+			WHERE status = $2 AND effective_at <= $3
+			ORDER BY effective_at, created_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scheduledChangeColumns
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, claimSQL, string(core.ScheduledChangeApplying),
+		string(core.ScheduledChangePending), now, limit); err != nil {
+		return nil, fmt.Errorf("认领到期定时变更失败: %v", err)
+	}
+
+	changes := make([]*core.ScheduledPermissionChange, 0, len(records))
+	for _, record := range records {
+		changes = append(changes, toChange(record))
+	}
+	return changes, nil
+}
+
+// FinishChange 记录变更的执行结果
+func (m *scheduleManager) FinishChange(ctx context.Context, changeID string, status core.ScheduledChangeStatus, errMessage string) error {
+	if err := core.RequireArg("changeID", changeID); err != nil {
+		return err
+	}
+
+	updateSQL := `
+		UPDATE system_scheduled_changes
+		SET status = $2, error = $3, finished_at = CURRENT_TIMESTAMP
+		WHERE change_id = $1
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, updateSQL, changeID, string(status), errMessage); err != nil {
+		return fmt.Errorf("记录定时变更结果失败: %v", err)
+	}
+	return nil
+}
+
+// RenameUser 将定时变更中的被授权用户和操作者迁移到新标识
+func (m *scheduleManager) RenameUser(ctx context.Context, oldKey, newKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldKey", oldKey),
+		core.RequireArg("newKey", newKey),
+	); err != nil {
+		return err
+	}
+
+	return m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		statements := []string{
+			`UPDATE system_scheduled_changes SET user_key = $2 WHERE user_key = $1`,
+			`UPDATE system_scheduled_changes SET operator_key = $2 WHERE operator_key = $1`,
+			`UPDATE system_scheduled_changes SET canceled_by = $2 WHERE canceled_by = $1`,
+		}
+		for _, statement := range statements {
+			if _, err := session.ExecCtx(ctx, statement, oldKey, newKey); err != nil {
+				return fmt.Errorf("迁移用户定时变更失败: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteUser 删除以用户为被授权用户的定时变更
+func (m *scheduleManager) DeleteUser(ctx context.Context, userKey string) error {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return err
+	}
+
+	if _, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_scheduled_changes WHERE user_key = $1`, userKey); err != nil {
+		return fmt.Errorf("删除用户定时变更失败: %v", err)
+	}
+	return nil
+}
+
+// RenameDomain 将租户的定时变更迁移到新租户
+func (m *scheduleManager) RenameDomain(ctx context.Context, oldDomain, newDomain string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldDomain", oldDomain),
+		core.RequireArg("newDomain", newDomain),
+	); err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE system_scheduled_changes SET tenant_key = $2 WHERE tenant_key = $1`
+	if _, err := m.dbConn.ExecCtx(ctx, updateSQL, oldDomain, newDomain); err != nil {
+		return fmt.Errorf("迁移租户定时变更失败: %v", err)
+	}
+	return nil
+}
+
+// DeleteDomain 删除租户的定时变更
+func (m *scheduleManager) DeleteDomain(ctx context.Context, domain string) error {
+	if err := core.RequireArg("domain", domain); err != nil {
+		return err
+	}
+
+	if _, err := m.dbConn.ExecCtx(ctx, `DELETE FROM system_scheduled_changes WHERE tenant_key = $1`, domain); err != nil {
+		return fmt.Errorf("删除租户定时变更失败: %v", err)
+	}
+	return nil
+}
+
+// toChange 转换定时变更记录
+func toChange(record scheduledChangeRecord) *core.ScheduledPermissionChange {
+	change := &core.ScheduledPermissionChange{
+		ScheduledChange: core.ScheduledChange{
+			Action:     core.ScheduledChangeAction(record.Action),
+			UserKey:    record.UserKey,
+			TenantKey:  record.TenantKey,
+			Permission: core.Permission{Resource: core.Resource(record.Resource), Action: core.Action(record.PermAction), ObjectID: record.ObjectID},
+		},
+		ID:           record.ChangeID,
+		OperatorKey:  record.OperatorKey,
+		Reason:       record.Reason,
+		Environments: []string{},
+		EffectiveAt:  record.EffectiveAt,
+		Status:       core.ScheduledChangeStatus(record.Status),
+		Error:        record.Error,
+		CanceledBy:   record.CanceledBy,
+		CreatedAt:    record.CreatedAt,
+	}
+	if record.Environments != "" {
+		change.Environments = strings.Split(record.Environments, ",")
+	}
+	if record.FinishedAt.Valid {
+		change.FinishedAt = record.FinishedAt.Time
+	}
+	return change
+}
@@ -0,0 +1,31 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 定时权限变更管理器接口（只负责变更的持久化和认领，变更由 engine 执行）
+type Manager interface {
+	CreateChange(ctx context.Context, change core.ScheduledPermissionChange) (*core.ScheduledPermissionChange, error) // 登记定时变更
+	GetChange(ctx context.Context, changeID string) (*core.ScheduledPermissionChange, error)                          // 获取定时变更，不存在时返回 ErrScheduledChangeNotFound
+	ListChanges(ctx context.Context, filter core.ScheduledChangeFilter) ([]*core.ScheduledPermissionChange, error)    // 按条件查询定时变更（按生效时间排序）
+	CancelChange(ctx context.Context, changeID, operatorKey string) (*core.ScheduledPermissionChange, error)          // 取消等待生效的变更，已执行或已取消时返回 ErrScheduledChangeNotPending
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*core.ScheduledPermissionChange, error)                // 认领到期的变更（多实例部署时每个变更只被一个实例认领）
+	FinishChange(ctx context.Context, changeID string, status core.ScheduledChangeStatus, errMessage string) error    // 记录变更的执行结果
+
+	// 用户和租户变更
+	RenameUser(ctx context.Context, oldKey, newKey string) error         // 将定时变更中的被授权用户和操作者迁移到新标识
+	DeleteUser(ctx context.Context, userKey string) error                // 删除以用户为被授权用户的定时变更
+	RenameDomain(ctx context.Context, oldDomain, newDomain string) error // 将租户的定时变更迁移到新租户
+	DeleteDomain(ctx context.Context, domain string) error               // 删除租户的定时变更
+}
+
+// NewManager 创建定时权限变更管理器
+func NewManager(dbConn sqlx.SqlConn) (Manager, error) {
+	return newScheduleManager(dbConn)
+}
@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// scheduledChangeRecord 定时变更记录
+type scheduledChangeRecord struct {
+	ChangeID     string       `db:"change_id"`
+	Action       string       `db:"action"`
+	UserKey      string       `db:"user_key"`
+	TenantKey    string       `db:"tenant_key"`
+	Resource     string       `db:"resource"`
+	ObjectID     string       `db:"object_id"`
+	PermAction   string       `db:"permission_action"`
+	OperatorKey  string       `db:"operator_key"`
+	Reason       string       `db:"reason"`
+	Environments string       `db:"environments"`
+	EffectiveAt  time.Time    `db:"effective_at"`
+	Status       string       `db:"status"`
+	Error        string       `db:"error"`
+	CanceledBy   string       `db:"canceled_by"`
+	CreatedAt    time.Time    `db:"created_at"`
+	FinishedAt   sql.NullTime `db:"finished_at"`
+}
+
+// scheduledChangeColumns 查询定时变更的列
+const scheduledChangeColumns = `change_id, action, user_key, tenant_key, resource, object_id, permission_action, operator_key, reason,
+	environments, effective_at, status, error, canceled_by, created_at, finished_at`
+
+// initDB 初始化数据库，创建定时变更表
+// 环境列表以逗号分隔存储
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_scheduled_changes (
+    change_id VARCHAR(64) PRIMARY KEY,
+    action VARCHAR(16) NOT NULL,
+    user_key VARCHAR(255) NOT NULL,
+    tenant_key VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    object_id VARCHAR(255) NOT NULL DEFAULT '',
+    permission_action VARCHAR(255) NOT NULL,
+    operator_key VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    environments TEXT NOT NULL DEFAULT '',
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    canceled_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_scheduled_changes_due ON system_scheduled_changes(status, effective_at);
+CREATE INDEX IF NOT EXISTS idx_system_scheduled_changes_tenant ON system_scheduled_changes(tenant_key, effective_at);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_scheduled_changes表失败: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// AddTenantAlias 添加租户别名（旧租户键 -> 新租户键）
+// 别名不能指向另一个别名，已被别名指向的租户也不能再作为别名，避免出现别名链
+func (m *tenantManager) AddTenantAlias(ctx context.Context, operatorKey, aliasKey, tenantKey string) error {
+	if err := core.FirstError(
+		core.RequireTenantArg("aliasKey", aliasKey),
+		core.RequireTenantArg("tenantKey", tenantKey),
+		core.CheckArg(aliasKey != tenantKey, "tenantKey", core.ConstraintDistinct),
+	); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	_, targetIsAlias := m.aliases[tenantKey]
+	aliasHasAliases := len(m.aliasesOf(aliasKey)) > 0
+	m.mu.RUnlock()
+	if targetIsAlias || aliasHasAliases {
+		return core.ErrTenantAliasChain
+	}
+
+	upsertSQL := `
+		INSERT INTO system_tenant_aliases (alias_key, tenant_key, created_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (alias_key) DO UPDATE
+		SET tenant_key = EXCLUDED.tenant_key, created_by = EXCLUDED.created_by, created_at = CURRENT_TIMESTAMP
+	`
+	if _, err := m.dbConn.ExecCtx(ctx, upsertSQL, aliasKey, tenantKey, operatorKey); err != nil {
+		return fmt.Errorf("添加租户别名失败: %v", err)
+	}
+
+	return m.syncAliases(ctx)
+}
+
+// RemoveTenantAlias 移除租户别名
+// 移除前应先迁移别名下的授权，否则这些授权将不再对新租户键生效
+func (m *tenantManager) RemoveTenantAlias(ctx context.Context, aliasKey string) error {
+	if err := core.RequireArg("aliasKey", aliasKey); err != nil {
+		return err
+	}
+
+	deleteSQL := `DELETE FROM system_tenant_aliases WHERE alias_key = $1`
+	if _, err := m.dbConn.ExecCtx(ctx, deleteSQL, aliasKey); err != nil {
+		return fmt.Errorf("移除租户别名失败: %v", err)
+	}
+
+	return m.syncAliases(ctx)
+}
+
+// GetTenantAlias 获取别名信息，不是别名时返回 nil
+func (m *tenantManager) GetTenantAlias(ctx context.Context, aliasKey string) *core.TenantAlias {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alias, ok := m.aliases[aliasKey]
+	if !ok {
+		return nil
+	}
+	result := *alias
+	return &result
+}
+
+// ListTenantAliases 获取指向租户的所有别名
+func (m *tenantManager) ListTenantAliases(ctx context.Context, tenantKey string) []core.TenantAlias {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	aliases := []core.TenantAlias{}
+	for _, aliasKey := range m.aliasesOf(tenantKey) {
+		aliases = append(aliases, *m.aliases[aliasKey])
+	}
+	return aliases
+}
+
+// ResolveTenantKey 将别名解析为规范租户键，不是别名时原样返回
+func (m *tenantManager) ResolveTenantKey(ctx context.Context, tenantKey string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.canonicalTenant(tenantKey)
+}
+
+// RewriteAliasRules 将别名下的所有权限策略和角色分配物理改写到规范租户键
+func (m *tenantManager) RewriteAliasRules(ctx context.Context, aliasKey string) (*core.DomainRewriteResult, error) {
+	alias := m.GetTenantAlias(ctx, aliasKey)
+	if alias == nil {
+		return nil, core.ErrTenantAliasNotFound
+	}
+
+	return m.enforcer.RewriteDomain(ctx, alias.AliasKey, alias.TenantKey)
+}
+
+// resolveDomainAliases 域别名解析器：返回与域等价的规范租户键及其所有别名
+func (m *tenantManager) resolveDomainAliases(domain string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	canonical := m.canonicalTenant(domain)
+	aliasKeys := m.aliasesOf(canonical)
+	if canonical == domain && len(aliasKeys) == 0 {
+		return nil
+	}
+
+	return append([]string{canonical}, aliasKeys...)
+}
+
+// canonicalTenant 解析规范租户键（调用方持有锁）
+func (m *tenantManager) canonicalTenant(tenantKey string) string {
+	if alias, ok := m.aliases[tenantKey]; ok {
+		return alias.TenantKey
+	}
+	return tenantKey
+}
+
+// aliasesOf 获取指向租户的别名键，按字典序排列（调用方持有锁）
+func (m *tenantManager) aliasesOf(tenantKey string) []string {
+	var aliasKeys []string
+	for aliasKey, alias := range m.aliases {
+		if alias.TenantKey == tenantKey {
+			aliasKeys = append(aliasKeys, aliasKey)
+		}
+	}
+	sort.Strings(aliasKeys)
+	return aliasKeys
+}
+
+// loadAliases 从数据库加载租户别名到内存
+func (m *tenantManager) loadAliases(ctx context.Context) error {
+	var records []*tenantAliasRecord
+	selectSQL := `SELECT alias_key, tenant_key, created_by, created_at FROM system_tenant_aliases`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return fmt.Errorf("加载租户别名失败: %v", err)
+	}
+
+	aliases := make(map[string]*core.TenantAlias, len(records))
+	for _, record := range records {
+		alias := &core.TenantAlias{
+			AliasKey:  record.AliasKey,
+			TenantKey: record.TenantKey,
+			CreatedBy: record.CreatedBy,
+		}
+		if record.CreatedAt.Valid {
+			alias.CreatedAt = record.CreatedAt.Time
+		}
+		aliases[record.AliasKey] = alias
+	}
+
+	m.mu.Lock()
+	m.aliases = aliases
+	m.mu.Unlock()
+
+	return nil
+}
+
+// syncAliases 刷新本地租户别名并通知其他实例
+func (m *tenantManager) syncAliases(ctx context.Context) error {
+	if err := m.loadAliases(ctx); err != nil {
+		return err
+	}
+	return m.enforcer.NotifyWatcher()
+}
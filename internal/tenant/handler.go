@@ -0,0 +1,165 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// tenantManager 租户管理器实现
+type tenantManager struct {
+	enforcer *core.Enforcer
+	dbConn   sqlx.SqlConn
+
+	mu        sync.RWMutex
+	lockdowns map[string]*core.TenantLockdown // 租户键 -> 封锁状态
+	aliases   map[string]*core.TenantAlias    // 别名 -> 租户别名
+}
+
+// newTenantManager 创建租户管理器实现
+func newTenantManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*tenantManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("租户管理器初始化失败: %v", err)
+	}
+
+	manager := &tenantManager{
+		enforcer:  enforcer,
+		dbConn:    dbConn,
+		lockdowns: make(map[string]*core.TenantLockdown),
+		aliases:   make(map[string]*core.TenantAlias),
+	}
+
+	if err := manager.loadLockdowns(context.Background()); err != nil {
+		return nil, err
+	}
+	if err := manager.loadAliases(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// 策略重新加载时刷新封锁状态，并在权限检查前拦截被封锁租户内的请求
+	enforcer.AddReloadHook(func() error { return manager.loadLockdowns(context.Background()) })
+	enforcer.AddCheckGuard(manager.checkGuard)
+
+	// 策略重新加载时刷新租户别名，并在权限检查时把别名下的授权视为规范租户的授权
+	enforcer.AddReloadHook(func() error { return manager.loadAliases(context.Background()) })
+	enforcer.SetDomainAliasResolver(manager.resolveDomainAliases)
+
+	return manager, nil
+}
+
+// RegisterTenant 注册租户
+func (m *tenantManager) RegisterTenant(ctx context.Context, operatorKey, tenantKey, name string) error {
+	if err := core.RequireTenantArg("tenantKey", tenantKey); err != nil {
+		return err
+	}
+
+	insertSQL := `
+		INSERT INTO system_tenants (tenant_key, name, created_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_key) DO NOTHING
+	`
+	result, err := m.dbConn.ExecCtx(ctx, insertSQL, tenantKey, name, operatorKey)
+	if err != nil {
+		return fmt.Errorf("注册租户失败: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("注册租户失败: %v", err)
+	}
+	if affected == 0 {
+		return core.ErrTenantAlreadyExists
+	}
+
+	return nil
+}
+
+// EnsureTenant 确保租户已注册
+func (m *tenantManager) EnsureTenant(ctx context.Context, operatorKey, tenantKey string) error {
+	err := m.RegisterTenant(ctx, operatorKey, tenantKey, tenantKey)
+	if errors.Is(err, core.ErrTenantAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+// GetTenant 获取租户信息
+func (m *tenantManager) GetTenant(ctx context.Context, tenantKey string) (*core.Tenant, error) {
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return nil, err
+	}
+
+	var record tenantRecord
+	selectSQL := `SELECT tenant_key, name, created_by, created_at FROM system_tenants WHERE tenant_key = $1`
+	if err := m.dbConn.QueryRowCtx(ctx, &record, selectSQL, tenantKey); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, fmt.Errorf("租户 '%s' 不存在", tenantKey)
+		}
+		return nil, err
+	}
+
+	return toTenant(record), nil
+}
+
+// ListTenants 获取所有已登记的租户，按租户键排序
+func (m *tenantManager) ListTenants(ctx context.Context) ([]*core.Tenant, error) {
+	var records []tenantRecord
+	selectSQL := `SELECT tenant_key, name, created_by, created_at FROM system_tenants ORDER BY tenant_key`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL); err != nil {
+		return nil, fmt.Errorf("获取租户列表失败: %v", err)
+	}
+
+	tenants := make([]*core.Tenant, 0, len(records))
+	for _, record := range records {
+		tenants = append(tenants, toTenant(record))
+	}
+	return tenants, nil
+}
+
+// DeleteTenant 删除租户登记信息、封锁状态和指向租户的别名
+func (m *tenantManager) DeleteTenant(ctx context.Context, tenantKey string) error {
+	if err := core.RequireTenantArg("tenantKey", tenantKey); err != nil {
+		return err
+	}
+
+	err := m.dbConn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		if _, err := session.ExecCtx(ctx, `DELETE FROM system_tenant_aliases WHERE tenant_key = $1`, tenantKey); err != nil {
+			return fmt.Errorf("删除租户别名失败: %v", err)
+		}
+		if _, err := session.ExecCtx(ctx, `DELETE FROM system_tenant_lockdowns WHERE tenant_key = $1`, tenantKey); err != nil {
+			return fmt.Errorf("删除租户封锁状态失败: %v", err)
+		}
+		if _, err := session.ExecCtx(ctx, `DELETE FROM system_tenants WHERE tenant_key = $1`, tenantKey); err != nil {
+			return fmt.Errorf("删除租户失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := m.loadLockdowns(ctx); err != nil {
+		return err
+	}
+	return m.syncAliases(ctx)
+}
+
+// toTenant 转换租户记录
+func toTenant(record tenantRecord) *core.Tenant {
+	tenant := &core.Tenant{Key: record.TenantKey, Registered: true}
+	if record.Name.Valid {
+		tenant.Name = record.Name.String
+	}
+	if record.CreatedBy.Valid {
+		tenant.CreatedBy = record.CreatedBy.String
+	}
+	if record.CreatedAt.Valid {
+		tenant.CreatedAt = record.CreatedAt.Time
+	}
+	return tenant
+}
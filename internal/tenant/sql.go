@@ -0,0 +1,81 @@
+package tenant
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// tenantRecord 租户记录
+type tenantRecord struct {
+	TenantKey string         `db:"tenant_key"`
+	Name      sql.NullString `db:"name"`
+	CreatedBy sql.NullString `db:"created_by"`
+	CreatedAt sql.NullTime   `db:"created_at"`
+}
+
+// tenantLockdownRecord 租户封锁记录
+type tenantLockdownRecord struct {
+	TenantKey    string       `db:"tenant_key"`
+	AllowedRoles string       `db:"allowed_roles"`
+	LockedBy     string       `db:"locked_by"`
+	LockedAt     sql.NullTime `db:"locked_at"`
+}
+
+// tenantAliasRecord 租户别名记录
+type tenantAliasRecord struct {
+	AliasKey  string       `db:"alias_key"`
+	TenantKey string       `db:"tenant_key"`
+	CreatedBy string       `db:"created_by"`
+	CreatedAt sql.NullTime `db:"created_at"`
+}
+
+// initDB 初始化数据库，创建租户表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_tenants (
+    tenant_key VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255),
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_tenants表失败: %v", err)
+	}
+
+	createLockdownTableSQL := `
+CREATE TABLE IF NOT EXISTS system_tenant_lockdowns (
+    tenant_key VARCHAR(255) PRIMARY KEY,
+    allowed_roles TEXT NOT NULL DEFAULT '',
+    locked_by VARCHAR(255) NOT NULL,
+    locked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+	_, err = dbConn.Exec(createLockdownTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_tenant_lockdowns表失败: %v", err)
+	}
+
+	createAliasTableSQL := `
+CREATE TABLE IF NOT EXISTS system_tenant_aliases (
+    alias_key VARCHAR(255) PRIMARY KEY,
+    tenant_key VARCHAR(255) NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_tenant_aliases_tenant_key ON system_tenant_aliases(tenant_key);
+`
+
+	_, err = dbConn.Exec(createAliasTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_tenant_aliases表失败: %v", err)
+	}
+
+	return nil
+}
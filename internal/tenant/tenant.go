@@ -0,0 +1,36 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// Manager 租户管理器接口
+type Manager interface {
+	RegisterTenant(ctx context.Context, operatorKey, tenantKey, name string) error // 注册租户，租户已存在时返回 ErrTenantAlreadyExists
+	EnsureTenant(ctx context.Context, operatorKey, tenantKey string) error         // 确保租户已注册（已存在时不做处理）
+	GetTenant(ctx context.Context, tenantKey string) (*core.Tenant, error)         // 获取租户信息
+	ListTenants(ctx context.Context) ([]*core.Tenant, error)                       // 获取所有已登记的租户
+	DeleteTenant(ctx context.Context, tenantKey string) error                      // 删除租户登记信息、封锁状态和指向租户的别名
+
+	// 租户封锁
+	SetTenantLockdown(ctx context.Context, operatorKey, tenantKey string, allowedRoles []string) error // 封锁租户，仅放行持有指定角色的用户
+	ClearTenantLockdown(ctx context.Context, tenantKey string) error                                   // 解除租户封锁
+	GetTenantLockdown(ctx context.Context, tenantKey string) *core.TenantLockdown                      // 获取租户封锁状态，未封锁时返回 nil
+
+	// 租户别名
+	AddTenantAlias(ctx context.Context, operatorKey, aliasKey, tenantKey string) error         // 添加租户别名（旧租户键 -> 新租户键）
+	RemoveTenantAlias(ctx context.Context, aliasKey string) error                              // 移除租户别名
+	GetTenantAlias(ctx context.Context, aliasKey string) *core.TenantAlias                     // 获取别名信息，不是别名时返回 nil
+	ListTenantAliases(ctx context.Context, tenantKey string) []core.TenantAlias                // 获取指向租户的所有别名
+	ResolveTenantKey(ctx context.Context, tenantKey string) string                             // 将别名解析为规范租户键
+	RewriteAliasRules(ctx context.Context, aliasKey string) (*core.DomainRewriteResult, error) // 将别名下的授权物理改写到规范租户键
+}
+
+// NewManager 创建租户管理器
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newTenantManager(dbConn, enforcer)
+}
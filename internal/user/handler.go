@@ -1,6 +1,9 @@
 package user
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/rezeropoint/casbinx/core"
 
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
@@ -13,60 +16,94 @@ type userManager struct {
 }
 
 // newUserManager 创建用户权限管理器实现
-func newUserManager(dsn string, enforcer *core.Enforcer) *userManager {
-	// 初始化 PostgreSQL - 使用 URL 格式的 DSN
-	dbConn := sqlx.NewSqlConn("postgres", dsn)
+func newUserManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (*userManager, error) {
+	if err := initDB(dbConn); err != nil {
+		return nil, fmt.Errorf("用户管理器初始化失败: %v", err)
+	}
 
 	return &userManager{
 		enforcer: enforcer,
 		dbConn:   dbConn,
-	}
+	}, nil
 }
 
 // GrantPermission 为用户授予权限
-func (m *userManager) GrantPermission(operatorKey, userKey, tenantKey string, permission core.Permission) error {
+func (m *userManager) GrantPermission(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission) error {
 	// 验证参数
 	if err := m.validateParams(userKey, permission); err != nil {
 		return err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		return err
 	}
 
 	// 安全检查已在engine层处理
 
 	// 调用core层添加权限
-	return m.enforcer.AddPolicy(userKey, tenantKey, permission)
+	return m.enforcer.AddPolicy(ctx, m.enforcer.UserSubject(userKey), tenantKey, permission)
 }
 
 // RevokePermission 撤销用户权限
-func (m *userManager) RevokePermission(operatorKey, userKey, tenantKey string, permission core.Permission) error {
+func (m *userManager) RevokePermission(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission) error {
 	// 验证参数
 	if err := m.validateParams(userKey, permission); err != nil {
 		return err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		return err
 	}
 
 	// 安全检查已在engine层处理
 
 	// 调用core层移除权限
-	return m.enforcer.RemovePolicy(userKey, tenantKey, permission)
+	return m.enforcer.RemovePolicy(ctx, m.enforcer.UserSubject(userKey), tenantKey, permission)
+}
+
+// GrantPermissions 批量为用户授予权限，已拥有的权限会被跳过
+func (m *userManager) GrantPermissions(ctx context.Context, operatorKey, userKey, tenantKey string, permissions []core.Permission) error {
+	if err := m.validateBatchParams(ctx, userKey, permissions); err != nil {
+		return err
+	}
+
+	return m.enforcer.AddPolicies(ctx, m.enforcer.UserSubject(userKey), tenantKey, permissions)
+}
+
+// RevokePermissions 批量撤销用户权限，未拥有的权限会被跳过
+func (m *userManager) RevokePermissions(ctx context.Context, operatorKey, userKey, tenantKey string, permissions []core.Permission) error {
+	if err := m.validateBatchParams(ctx, userKey, permissions); err != nil {
+		return err
+	}
+
+	return m.enforcer.RemovePolicies(ctx, m.enforcer.UserSubject(userKey), tenantKey, permissions)
+}
+
+// validateBatchParams 验证批量授权参数
+func (m *userManager) validateBatchParams(ctx context.Context, userKey string, permissions []core.Permission) error {
+	if err := core.RequireItems("permissions", permissions); err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if err := m.validateParams(userKey, permission); err != nil {
+			return err
+		}
+	}
+
+	// 验证userKey不是角色
+	return m.validateNotRole(ctx, userKey)
 }
 
 // GetDirectPermissions 获取用户直接权限（不包括角色权限）
-func (m *userManager) GetDirectPermissions(userKey, tenantKey string) ([]core.Permission, error) {
-	if userKey == "" {
-		return nil, core.ErrInvalidParameter
+func (m *userManager) GetDirectPermissions(ctx context.Context, userKey, tenantKey string) ([]core.Permission, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		// 如果是角色，返回空权限列表而不是错误
 		return []core.Permission{}, nil
 	}
@@ -76,9 +113,9 @@ func (m *userManager) GetDirectPermissions(userKey, tenantKey string) ([]core.Pe
 }
 
 // GetEffectivePermissions 获取用户有效权限（包括角色继承）
-func (m *userManager) GetEffectivePermissions(userKey, tenantKey string) ([]core.Permission, error) {
-	if userKey == "" {
-		return nil, core.ErrInvalidParameter
+func (m *userManager) GetEffectivePermissions(ctx context.Context, userKey, tenantKey string) ([]core.Permission, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
 	}
 
 	// 获取隐式权限（包括通过角色继承的权限）
@@ -86,19 +123,22 @@ func (m *userManager) GetEffectivePermissions(userKey, tenantKey string) ([]core
 }
 
 // AssignRole 为用户分配角色
-func (m *userManager) AssignRole(operatorKey, userKey, roleKey, tenantKey string) error {
+func (m *userManager) AssignRole(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string) error {
 	// 验证参数
-	if userKey == "" || roleKey == "" {
-		return core.ErrInvalidParameter
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("roleKey", roleKey),
+	); err != nil {
+		return err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		return err
 	}
 
 	// 验证角色存在
-	if err := m.validateRoleExists(roleKey); err != nil {
+	if err := m.validateRoleExists(ctx, roleKey); err != nil {
 		return err
 	}
 
@@ -106,18 +146,62 @@ func (m *userManager) AssignRole(operatorKey, userKey, roleKey, tenantKey string
 	// 安全控制通过权限级别来实现，角色只是权限的容器
 
 	// 调用core层分配角色
-	return m.enforcer.AddGroupingPolicy(userKey, roleKey, tenantKey)
+	return m.enforcer.AddGroupingPolicy(ctx, userKey, roleKey, tenantKey)
+}
+
+// AssignRoles 批量分配角色
+// 逐项校验参数，未通过校验的分配跳过并在返回的错误切片对应位置给出原因，其余分配在单个事务中写入
+func (m *userManager) AssignRoles(ctx context.Context, operatorKey string, assignments []core.GroupingPolicy) ([]error, error) {
+	failures := make([]error, len(assignments))
+	valid := make([]core.GroupingPolicy, 0, len(assignments))
+	checkedRoles := make(map[string]error)
+	for i, assignment := range assignments {
+		if err := core.FirstError(
+			core.RequireArg("userKey", assignment.UserKey),
+			core.RequireArg("roleKey", assignment.RoleKey),
+		); err != nil {
+			failures[i] = err
+			continue
+		}
+
+		// 验证userKey不是角色
+		if err := m.validateNotRole(ctx, assignment.UserKey); err != nil {
+			failures[i] = err
+			continue
+		}
+
+		// 验证角色存在（同一角色只查询一次）
+		roleErr, checked := checkedRoles[assignment.RoleKey]
+		if !checked {
+			roleErr = m.validateRoleExists(ctx, assignment.RoleKey)
+			checkedRoles[assignment.RoleKey] = roleErr
+		}
+		if roleErr != nil {
+			failures[i] = roleErr
+			continue
+		}
+
+		valid = append(valid, assignment)
+	}
+
+	if err := m.enforcer.AddGroupingPolicies(ctx, valid); err != nil {
+		return failures, err
+	}
+	return failures, nil
 }
 
 // RemoveRole 移除用户角色
-func (m *userManager) RemoveRole(operatorKey, userKey, roleKey, tenantKey string) error {
+func (m *userManager) RemoveRole(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string) error {
 	// 验证参数
-	if userKey == "" || roleKey == "" {
-		return core.ErrInvalidParameter
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("roleKey", roleKey),
+	); err != nil {
+		return err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		return err
 	}
 
@@ -125,13 +209,13 @@ func (m *userManager) RemoveRole(operatorKey, userKey, roleKey, tenantKey string
 	// 安全控制通过权限级别来实现
 
 	// 调用core层移除角色
-	return m.enforcer.RemoveGroupingPolicy(userKey, roleKey, tenantKey)
+	return m.enforcer.RemoveGroupingPolicy(ctx, userKey, roleKey, tenantKey)
 }
 
 // GetUserRoles 获取用户角色
-func (m *userManager) GetUserRoles(userKey, tenantKey string) ([]string, error) {
-	if userKey == "" {
-		return nil, core.ErrInvalidParameter
+func (m *userManager) GetUserRoles(ctx context.Context, userKey, tenantKey string) ([]string, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
 	}
 	// 获取租户域中的角色
 	tenantRoles, err := m.enforcer.GetRolesForUser(userKey, tenantKey)
@@ -167,38 +251,38 @@ func (m *userManager) GetUserRoles(userKey, tenantKey string) ([]string, error)
 }
 
 // ClearUserPermissions 清除用户的所有直接权限
-func (m *userManager) ClearUserPermissions(operatorKey, userKey string) error {
-	if userKey == "" {
-		return core.ErrInvalidParameter
+func (m *userManager) ClearUserPermissions(ctx context.Context, operatorKey, userKey string) error {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		return err
 	}
 
-	return m.enforcer.ClearPolicies(userKey)
+	return m.enforcer.ClearPolicies(ctx, m.enforcer.UserSubject(userKey))
 }
 
 // ClearUserRoles 清除用户的所有角色分配
-func (m *userManager) ClearUserRoles(operatorKey, userKey string) error {
-	if userKey == "" {
-		return core.ErrInvalidParameter
+func (m *userManager) ClearUserRoles(ctx context.Context, operatorKey, userKey string) error {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return err
 	}
 
 	// 验证userKey不是角色
-	if err := m.validateNotRole(userKey); err != nil {
+	if err := m.validateNotRole(ctx, userKey); err != nil {
 		return err
 	}
 
 	// 安全检查已在engine层处理
 
-	return m.enforcer.ClearUserRoles(userKey)
+	return m.enforcer.ClearUserRoles(ctx, userKey)
 }
 
 // GetUserPermissionsByResource 获取用户在指定资源上的权限
-func (m *userManager) GetUserPermissionsByResource(userKey, tenantKey, resource string) ([]core.Permission, error) {
-	permissions, err := m.GetEffectivePermissions(userKey, tenantKey)
+func (m *userManager) GetUserPermissionsByResource(ctx context.Context, userKey, tenantKey, resource string) ([]core.Permission, error) {
+	permissions, err := m.GetEffectivePermissions(ctx, userKey, tenantKey)
 	if err != nil {
 		return nil, err
 	}
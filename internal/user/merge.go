@@ -0,0 +1,179 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// MergeUsers 将源用户的直接授权和角色分配合并到目标用户
+// 目标用户已拥有的授权或分配视为重复，只从源用户移除；合并完成后写入合并记录
+func (m *userManager) MergeUsers(ctx context.Context, operatorKey, fromUserKey, toUserKey string) (*core.UserMergeResult, error) {
+	if err := core.FirstError(
+		core.RequireArg("fromUserKey", fromUserKey),
+		core.RequireArg("toUserKey", toUserKey),
+		core.CheckArg(fromUserKey != toUserKey, "toUserKey", core.ConstraintDistinct),
+	); err != nil {
+		return nil, err
+	}
+
+	// 验证双方都不是角色
+	if err := m.validateNotRole(ctx, fromUserKey); err != nil {
+		return nil, err
+	}
+	if err := m.validateNotRole(ctx, toUserKey); err != nil {
+		return nil, err
+	}
+
+	result := &core.UserMergeResult{
+		FromUserKey: fromUserKey,
+		ToUserKey:   toUserKey,
+		MergedBy:    operatorKey,
+	}
+
+	// 1. 迁移直接授权
+	policies, err := m.enforcer.GetPolicies(m.enforcer.UserSubject(fromUserKey), "")
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		permission := core.Permission{Resource: policy.Resource, Action: policy.Action}
+
+		exists, err := m.enforcer.HasPolicy(m.enforcer.UserSubject(toUserKey), policy.Domain, permission)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result.DuplicatePermissions = append(result.DuplicatePermissions, policy)
+		} else {
+			if err := m.enforcer.AddPolicy(ctx, m.enforcer.UserSubject(toUserKey), policy.Domain, permission); err != nil {
+				return nil, fmt.Errorf("迁移授权 %s 失败: %w", permission, err)
+			}
+			result.MovedPermissions = append(result.MovedPermissions, policy)
+		}
+
+		if err := m.enforcer.RemovePolicy(ctx, m.enforcer.UserSubject(fromUserKey), policy.Domain, permission); err != nil {
+			return nil, fmt.Errorf("移除源用户授权 %s 失败: %w", permission, err)
+		}
+	}
+
+	// 2. 迁移角色分配
+	groupings, err := m.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for _, grouping := range groupings {
+		if grouping.UserKey != fromUserKey {
+			continue
+		}
+
+		assigned, err := m.enforcer.IsRoleAssigned(toUserKey, grouping.RoleKey, grouping.TenantKey)
+		if err != nil {
+			return nil, err
+		}
+		if assigned {
+			result.DuplicateRoles = append(result.DuplicateRoles, grouping)
+		} else {
+			if err := m.enforcer.AddGroupingPolicy(ctx, toUserKey, grouping.RoleKey, grouping.TenantKey); err != nil {
+				return nil, fmt.Errorf("迁移角色 %s 失败: %w", grouping.RoleKey, err)
+			}
+			result.MovedRoles = append(result.MovedRoles, grouping)
+		}
+
+		if err := m.enforcer.RemoveGroupingPolicy(ctx, fromUserKey, grouping.RoleKey, grouping.TenantKey); err != nil {
+			return nil, fmt.Errorf("移除源用户角色 %s 失败: %w", grouping.RoleKey, err)
+		}
+	}
+
+	// 3. 写入合并记录
+	insertSQL := `
+		INSERT INTO system_user_merges (from_user_key, to_user_key, moved_permissions, moved_roles, duplicate_permissions, duplicate_roles, merged_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = m.dbConn.ExecCtx(ctx, insertSQL, fromUserKey, toUserKey,
+		len(result.MovedPermissions), len(result.MovedRoles),
+		len(result.DuplicatePermissions), len(result.DuplicateRoles), operatorKey)
+	if err != nil {
+		return nil, fmt.Errorf("写入用户合并记录失败: %v", err)
+	}
+	result.MergedAt = time.Now()
+
+	return result, nil
+}
+
+// ListMerges 获取用户作为源、目标或操作者的合并记录（按时间顺序）
+func (m *userManager) ListMerges(ctx context.Context, userKey string) ([]core.UserMergeRecord, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return nil, err
+	}
+
+	var records []mergeRecord
+	selectSQL := `
+		SELECT from_user_key, to_user_key, moved_permissions, moved_roles, duplicate_permissions, duplicate_roles, merged_by, merged_at
+		FROM system_user_merges
+		WHERE from_user_key = $1 OR to_user_key = $1 OR merged_by = $1
+		ORDER BY merged_at, id
+	`
+	if err := m.dbConn.QueryRowsCtx(ctx, &records, selectSQL, userKey); err != nil {
+		return nil, fmt.Errorf("查询用户合并记录失败: %v", err)
+	}
+
+	merges := make([]core.UserMergeRecord, 0, len(records))
+	for _, record := range records {
+		merges = append(merges, core.UserMergeRecord(record))
+	}
+	return merges, nil
+}
+
+// EraseMerges 删除用户作为源、目标或操作者的合并记录
+// retainSince 为零值时删除全部记录；否则保留 retainSince 之后的记录，pseudonym 非空时将其中的用户标识替换为假名
+func (m *userManager) EraseMerges(ctx context.Context, userKey string, retainSince time.Time, pseudonym string) (int, int, error) {
+	if err := core.RequireArg("userKey", userKey); err != nil {
+		return 0, 0, err
+	}
+
+	deleteSQL := `DELETE FROM system_user_merges WHERE (from_user_key = $1 OR to_user_key = $1 OR merged_by = $1)`
+	args := []any{userKey}
+	if !retainSince.IsZero() {
+		deleteSQL += ` AND merged_at < $2`
+		args = append(args, retainSince)
+	}
+	result, err := m.dbConn.ExecCtx(ctx, deleteSQL, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("删除用户合并记录失败: %v", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("删除用户合并记录失败: %v", err)
+	}
+	if retainSince.IsZero() {
+		return int(deleted), 0, nil
+	}
+
+	var retained int64
+	if pseudonym != "" {
+		updateSQL := `
+			UPDATE system_user_merges SET
+				from_user_key = CASE WHEN from_user_key = $1 THEN $2 ELSE from_user_key END,
+				to_user_key = CASE WHEN to_user_key = $1 THEN $2 ELSE to_user_key END,
+				merged_by = CASE WHEN merged_by = $1 THEN $2 ELSE merged_by END
+			WHERE from_user_key = $1 OR to_user_key = $1 OR merged_by = $1
+		`
+		result, err := m.dbConn.ExecCtx(ctx, updateSQL, userKey, pseudonym)
+		if err != nil {
+			return 0, 0, fmt.Errorf("替换合并记录用户标识失败: %v", err)
+		}
+		if retained, err = result.RowsAffected(); err != nil {
+			return 0, 0, fmt.Errorf("替换合并记录用户标识失败: %v", err)
+		}
+	} else {
+		countSQL := `SELECT COUNT(*) FROM system_user_merges WHERE from_user_key = $1 OR to_user_key = $1 OR merged_by = $1`
+		if err := m.dbConn.QueryRowCtx(ctx, &retained, countSQL, userKey); err != nil {
+			return 0, 0, fmt.Errorf("统计用户合并记录失败: %v", err)
+		}
+	}
+
+	return int(deleted), int(retained), nil
+}
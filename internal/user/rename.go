@@ -0,0 +1,66 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// RenameUser 修改用户标识，改写该用户的所有权限策略和角色分配
+// 新标识已有任何策略或角色分配时返回 ErrUserKeyConflict
+func (m *userManager) RenameUser(ctx context.Context, oldKey, newKey string) error {
+	if err := core.FirstError(
+		core.RequireArg("oldKey", oldKey),
+		core.RequireArg("newKey", newKey),
+		core.CheckArg(oldKey != newKey, "newKey", core.ConstraintDistinct),
+	); err != nil {
+		return err
+	}
+
+	// 验证新旧标识都不是角色
+	if err := m.validateNotRole(ctx, oldKey); err != nil {
+		return err
+	}
+	if err := m.validateNotRole(ctx, newKey); err != nil {
+		return err
+	}
+
+	// 冲突检查：新标识不能已有任何策略或角色分配
+	inUse, err := m.IsSubjectInUse(ctx, newKey)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return core.ErrUserKeyConflict
+	}
+
+	if _, _, err := m.enforcer.RenameSubject(ctx, oldKey, newKey); err != nil {
+		return fmt.Errorf("改写用户策略失败: %w", err)
+	}
+
+	return nil
+}
+
+// IsSubjectInUse 检查主体是否已有权限策略或角色分配
+func (m *userManager) IsSubjectInUse(ctx context.Context, subject string) (bool, error) {
+	policies, err := m.enforcer.GetPolicies(m.enforcer.UserSubject(subject), "")
+	if err != nil {
+		return false, err
+	}
+	if len(policies) > 0 {
+		return true, nil
+	}
+
+	groupings, err := m.enforcer.GetGroupingPolicies()
+	if err != nil {
+		return false, err
+	}
+	for _, grouping := range groupings {
+		if grouping.UserKey == subject {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
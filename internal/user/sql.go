@@ -0,0 +1,47 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// mergeRecord 用户合并记录
+type mergeRecord struct {
+	FromUserKey          string    `db:"from_user_key"`
+	ToUserKey            string    `db:"to_user_key"`
+	MovedPermissions     int       `db:"moved_permissions"`
+	MovedRoles           int       `db:"moved_roles"`
+	DuplicatePermissions int       `db:"duplicate_permissions"`
+	DuplicateRoles       int       `db:"duplicate_roles"`
+	MergedBy             string    `db:"merged_by"`
+	MergedAt             time.Time `db:"merged_at"`
+}
+
+// initDB 初始化数据库，创建用户合并记录表
+func initDB(dbConn sqlx.SqlConn) error {
+	createTableSQL := `
+CREATE TABLE IF NOT EXISTS system_user_merges (
+    id BIGSERIAL PRIMARY KEY,
+    from_user_key VARCHAR(255) NOT NULL,
+    to_user_key VARCHAR(255) NOT NULL,
+    moved_permissions INT NOT NULL DEFAULT 0,
+    moved_roles INT NOT NULL DEFAULT 0,
+    duplicate_permissions INT NOT NULL DEFAULT 0,
+    duplicate_roles INT NOT NULL DEFAULT 0,
+    merged_by VARCHAR(255) NOT NULL,
+    merged_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_user_merges_from ON system_user_merges(from_user_key);
+CREATE INDEX IF NOT EXISTS idx_system_user_merges_to ON system_user_merges(to_user_key);
+`
+
+	_, err := dbConn.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("创建system_user_merges表失败: %v", err)
+	}
+
+	return nil
+}
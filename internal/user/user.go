@@ -1,28 +1,44 @@
 package user
 
 import (
+	"context"
+	"time"
+
 	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // Manager 用户权限管理器接口
 type Manager interface {
 	// 权限管理
-	GrantPermission(operatorKey, userKey, tenantKey string, permission core.Permission) error    // 授予用户权限
-	RevokePermission(operatorKey, userKey, tenantKey string, permission core.Permission) error   // 撤销用户权限
-	GetDirectPermissions(userKey, tenantKey string) ([]core.Permission, error)                   // 获取用户直接权限
-	GetEffectivePermissions(userKey, tenantKey string) ([]core.Permission, error)                // 获取用户有效权限(含角色继承)
-	ClearUserPermissions(operatorKey, userKey string) error                                      // 清除用户所有权限
-	GetUserPermissionsByResource(userKey, tenantKey, resource string) ([]core.Permission, error) // 获取用户对特定资源的权限
+	GrantPermission(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission) error      // 授予用户权限
+	RevokePermission(ctx context.Context, operatorKey, userKey, tenantKey string, permission core.Permission) error     // 撤销用户权限
+	GrantPermissions(ctx context.Context, operatorKey, userKey, tenantKey string, permissions []core.Permission) error  // 批量授予用户权限（单个事务）
+	RevokePermissions(ctx context.Context, operatorKey, userKey, tenantKey string, permissions []core.Permission) error // 批量撤销用户权限（单个事务）
+	GetDirectPermissions(ctx context.Context, userKey, tenantKey string) ([]core.Permission, error)                     // 获取用户直接权限
+	GetEffectivePermissions(ctx context.Context, userKey, tenantKey string) ([]core.Permission, error)                  // 获取用户有效权限(含角色继承)
+	ClearUserPermissions(ctx context.Context, operatorKey, userKey string) error                                        // 清除用户所有权限
+	GetUserPermissionsByResource(ctx context.Context, userKey, tenantKey, resource string) ([]core.Permission, error)   // 获取用户对特定资源的权限
 
 	// 角色分配
-	AssignRole(operatorKey, userKey, roleKey, tenantKey string) error // 为用户分配角色
-	RemoveRole(operatorKey, userKey, roleKey, tenantKey string) error // 移除用户角色
-	GetUserRoles(userKey, tenantKey string) ([]string, error)         // 获取用户角色列表
-	ClearUserRoles(operatorKey, userKey string) error                 // 清除用户所有角色分配
+	AssignRole(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string) error                   // 为用户分配角色
+	AssignRoles(ctx context.Context, operatorKey string, assignments []core.GroupingPolicy) ([]error, error) // 批量分配角色（单个事务），返回每项的校验错误
+	RemoveRole(ctx context.Context, operatorKey, userKey, roleKey, tenantKey string) error                   // 移除用户角色
+	GetUserRoles(ctx context.Context, userKey, tenantKey string) ([]string, error)                           // 获取用户角色列表
+	ClearUserRoles(ctx context.Context, operatorKey, userKey string) error                                   // 清除用户所有角色分配
+
+	// 账号合并
+	MergeUsers(ctx context.Context, operatorKey, fromUserKey, toUserKey string) (*core.UserMergeResult, error) // 合并用户的直接授权和角色分配
+	RenameUser(ctx context.Context, oldKey, newKey string) error                                               // 修改用户标识并改写所有相关策略
+	IsSubjectInUse(ctx context.Context, subject string) (bool, error)                                          // 检查主体是否已有权限策略或角色分配
 
+	// 用户数据（导出与删除）
+	ListMerges(ctx context.Context, userKey string) ([]core.UserMergeRecord, error)                             // 获取用户作为源、目标或操作者的合并记录
+	EraseMerges(ctx context.Context, userKey string, retainSince time.Time, pseudonym string) (int, int, error) // 删除用户的合并记录，retainSince 之后的记录保留（pseudonym 非空时替换用户标识），返回删除和保留的条数
 }
 
 // NewManager 创建用户权限管理器
-func NewManager(dsn string, enforcer *core.Enforcer) Manager {
-	return newUserManager(dsn, enforcer)
+func NewManager(dbConn sqlx.SqlConn, enforcer *core.Enforcer) (Manager, error) {
+	return newUserManager(dbConn, enforcer)
 }
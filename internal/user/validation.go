@@ -1,22 +1,32 @@
 package user
 
 import (
-	"github.com/rezeropoint/casbinx/core"
+	"context"
 	"fmt"
+	"github.com/rezeropoint/casbinx/core"
 )
 
 // validateParams 验证基本参数
 func (m *userManager) validateParams(userKey string, permission core.Permission) error {
-	if userKey == "" || permission.Resource == "" || permission.Action == "" {
-		return core.ErrInvalidParameter
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("permission.resource", permission.Resource),
+		core.RequireArg("permission.action", permission.Action),
+	); err != nil {
+		return err
 	}
 	return nil
 }
 
 // validateNotRole 验证主体不是角色
-func (m *userManager) validateNotRole(subject string) error {
+// 启用主体命名空间后用户与角色不会冲突，无需校验
+func (m *userManager) validateNotRole(ctx context.Context, subject string) error {
+	if m.enforcer.SubjectNamespacesEnabled() {
+		return nil
+	}
+
 	// 首先检查是否在 roles 表中存在（最准确的方法）
-	isRole, err := m.isRoleExistsInDB(subject)
+	isRole, err := m.isRoleExistsInDB(ctx, subject)
 	if err != nil {
 		return err
 	}
@@ -39,9 +49,9 @@ func (m *userManager) validateNotRole(subject string) error {
 }
 
 // validateRoleExists 验证角色存在
-func (m *userManager) validateRoleExists(roleKey string) error {
+func (m *userManager) validateRoleExists(ctx context.Context, roleKey string) error {
 	// 首先检查是否在 roles 表中存在（最准确的方法）
-	isRole, err := m.isRoleExistsInDB(roleKey)
+	isRole, err := m.isRoleExistsInDB(ctx, roleKey)
 	if err != nil {
 		return err
 	}
@@ -51,7 +61,7 @@ func (m *userManager) validateRoleExists(roleKey string) error {
 	}
 
 	// 兼容性检查：检查自定义角色是否存在（通过检查是否有权限策略或被分配，处理旧数据）
-	policies, err := m.enforcer.GetPolicies(roleKey, "")
+	policies, err := m.enforcer.GetPolicies(m.enforcer.RoleSubject(roleKey), "")
 	if err != nil {
 		return err
 	}
@@ -74,10 +84,10 @@ func (m *userManager) validateRoleExists(roleKey string) error {
 }
 
 // isRoleExistsInDB 检查角色是否在数据库中存在
-func (m *userManager) isRoleExistsInDB(roleKey string) (bool, error) {
+func (m *userManager) isRoleExistsInDB(ctx context.Context, roleKey string) (bool, error) {
 	var count int
 	countSQL := `SELECT COUNT(*) FROM system_roles WHERE role_key = $1`
-	err := m.dbConn.QueryRow(&count, countSQL, roleKey)
+	err := m.dbConn.QueryRowCtx(ctx, &count, countSQL, roleKey)
 	if err != nil {
 		return false, err
 	}
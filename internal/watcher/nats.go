@@ -0,0 +1,170 @@
+package watcher
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// natsDialTimeout 建立 NATS 连接和确认订阅的超时时长
+const natsDialTimeout = 5 * time.Second
+
+// natsWatcher 基于 NATS 发布订阅的 Watcher
+// 断线后由 NATS 客户端按退避重连并恢复订阅，重连成功后以 core.WatcherResyncMessage 触发一次回调，弥补断线期间可能错过的通知
+type natsWatcher struct {
+	config     core.NATSWatcherConfig
+	instanceID string
+	signer     *signer          // 通知签名和验证
+	state      *connectionState // 连接状态
+
+	callbackMu sync.Mutex
+	callback   func(string)
+
+	conn    *nats.Conn
+	closing atomic.Bool
+	done    chan struct{} // 连接关闭且回调执行完毕后关闭
+}
+
+// newNATSWatcher 创建 NATS Watcher，并确认可以建立连接和订阅
+func newNATSWatcher(config core.NATSWatcherConfig, signer *signer, state *connectionState) (*natsWatcher, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("NATS Watcher 缺少服务地址")
+	}
+	if config.Subject == "" {
+		config.Subject = core.DefaultWatcherChannel
+	}
+	instanceID, err := newInstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &natsWatcher{
+		config:     config,
+		instanceID: instanceID,
+		signer:     signer,
+		state:      state,
+		done:       make(chan struct{}),
+	}
+
+	options := []nats.Option{
+		nats.Name("casbinx"),
+		nats.Timeout(natsDialTimeout),
+		nats.MaxReconnects(-1),
+		nats.CustomReconnectDelay(natsReconnectDelay),
+		nats.DisconnectErrHandler(w.disconnected),
+		nats.ReconnectHandler(w.reconnected),
+		nats.ClosedHandler(func(*nats.Conn) { close(w.done) }),
+	}
+	if config.Username != "" {
+		options = append(options, nats.UserInfo(config.Username, config.Password))
+	}
+	if config.Token != "" {
+		options = append(options, nats.Token(config.Token))
+	}
+
+	conn, err := nats.Connect(config.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("NATS Watcher 连接 %s 失败: %v", config.URL, err)
+	}
+	w.conn = conn
+
+	if _, err := conn.Subscribe(config.Subject, w.receive); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("NATS Watcher 订阅失败: %v", err)
+	}
+	// Flush 返回表示 SUB 已被服务端接受
+	if err := conn.FlushTimeout(natsDialTimeout); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("NATS Watcher 订阅失败: %v", err)
+	}
+	return w, nil
+}
+
+// natsReconnectDelay 第 attempts 次重连前的退避时长
+func natsReconnectDelay(attempts int) time.Duration {
+	delay := minReconnectDelay
+	for i := 1; i < attempts && delay < maxReconnectDelay; i++ {
+		delay = nextDelay(delay)
+	}
+	return delay
+}
+
+// SetUpdateCallback 设置收到通知时的回调
+func (w *natsWatcher) SetUpdateCallback(callback func(string)) error {
+	w.callbackMu.Lock()
+	defer w.callbackMu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update 发布通知到主题，断线期间返回错误
+func (w *natsWatcher) Update() error {
+	message, err := w.signer.sign(w.instanceID)
+	if err != nil {
+		return err
+	}
+
+	if !w.conn.IsConnected() {
+		return fmt.Errorf("NATS Watcher 未连接，通知未发送")
+	}
+	if err := w.conn.Publish(w.config.Subject, []byte(message)); err != nil {
+		return fmt.Errorf("NATS Watcher 发送通知失败: %v", err)
+	}
+	return nil
+}
+
+// Stats 获取连接状态
+func (w *natsWatcher) Stats() core.WatcherStats {
+	return w.state.snapshot()
+}
+
+// Close 断开连接并停止接收，等待正在执行的回调结束
+func (w *natsWatcher) Close() {
+	if w.closing.CompareAndSwap(false, true) {
+		w.conn.Close()
+	}
+	<-w.done
+}
+
+// receive 处理收到的通知
+func (w *natsWatcher) receive(msg *nats.Msg) {
+	message, ok := w.signer.verify(string(msg.Data))
+	if !ok || (w.config.IgnoreSelf && message == w.instanceID) {
+		return
+	}
+	w.notify(message)
+}
+
+// disconnected 记录连接断开（Close 主动断开时忽略）
+func (w *natsWatcher) disconnected(_ *nats.Conn, err error) {
+	if w.closing.Load() {
+		return
+	}
+	if err == nil {
+		err = nats.ErrConnectionClosed
+	}
+	w.state.down(err)
+}
+
+// reconnected 记录连接恢复，断线期间可能错过通知，重连后立即重新加载
+func (w *natsWatcher) reconnected(*nats.Conn) {
+	if w.state.up() {
+		w.notify(core.WatcherResyncMessage)
+	}
+}
+
+// notify 调用更新回调
+func (w *natsWatcher) notify(payload string) {
+	w.callbackMu.Lock()
+	callback := w.callback
+	w.callbackMu.Unlock()
+
+	if callback != nil {
+		callback(payload)
+	}
+}
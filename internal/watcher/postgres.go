@@ -0,0 +1,224 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// postgresWatcher 基于 PostgreSQL LISTEN/NOTIFY 的 Watcher
+// 监听使用独占连接，断线后按退避重连；重连成功后以 core.WatcherResyncMessage 触发一次回调，弥补断线期间可能错过的通知
+type postgresWatcher struct {
+	config      core.PostgresWatcherConfig
+	credentials core.DBCredentialsProvider // 连接密码回调（为空时使用连接字符串中的密码）
+	instanceID  string
+	signer      *signer          // 通知签名和验证
+	state       *connectionState // 连接状态
+
+	callbackMu sync.Mutex
+	callback   func(string)
+
+	notifierMu sync.Mutex
+	notifier   *pgx.Conn // 发送通知的连接，出错后丢弃并在下次通知时重建
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newPostgresWatcher 创建 PostgreSQL LISTEN/NOTIFY Watcher，并确认可以建立监听连接
+func newPostgresWatcher(config core.PostgresWatcherConfig, credentials core.DBCredentialsProvider, signer *signer, state *connectionState) (*postgresWatcher, error) {
+	if config.Dsn == "" {
+		return nil, fmt.Errorf("Postgres Watcher 缺少连接字符串")
+	}
+	if config.Channel == "" {
+		config.Channel = core.DefaultWatcherChannel
+	}
+	instanceID, err := newInstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &postgresWatcher{
+		config:      config,
+		credentials: credentials,
+		instanceID:  instanceID,
+		signer:      signer,
+		state:       state,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+
+	listener, err := w.listen()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go w.run(listener)
+	return w, nil
+}
+
+// SetUpdateCallback 设置收到通知时的回调
+func (w *postgresWatcher) SetUpdateCallback(callback func(string)) error {
+	w.callbackMu.Lock()
+	defer w.callbackMu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update 通过 pg_notify 通知其他实例
+func (w *postgresWatcher) Update() error {
+	message, err := w.signer.sign(w.instanceID)
+	if err != nil {
+		return err
+	}
+
+	w.notifierMu.Lock()
+	defer w.notifierMu.Unlock()
+
+	if w.notifier == nil {
+		conn, err := w.connect()
+		if err != nil {
+			return fmt.Errorf("Postgres Watcher 连接失败: %v", err)
+		}
+		w.notifier = conn
+	}
+
+	if _, err := w.notifier.Exec(w.ctx, "SELECT pg_notify($1, $2)", w.config.Channel, message); err != nil {
+		_ = w.notifier.Close(context.Background())
+		w.notifier = nil
+		return fmt.Errorf("Postgres Watcher 发送通知失败: %v", err)
+	}
+	return nil
+}
+
+// Stats 获取监听连接的状态
+func (w *postgresWatcher) Stats() core.WatcherStats {
+	return w.state.snapshot()
+}
+
+// Close 停止监听并关闭连接
+func (w *postgresWatcher) Close() {
+	w.cancel()
+	<-w.done
+
+	w.notifierMu.Lock()
+	defer w.notifierMu.Unlock()
+	if w.notifier != nil {
+		_ = w.notifier.Close(context.Background())
+		w.notifier = nil
+	}
+}
+
+// listen 建立监听连接并执行 LISTEN
+func (w *postgresWatcher) listen() (*pgx.Conn, error) {
+	conn, err := w.connect()
+	if err != nil {
+		return nil, fmt.Errorf("Postgres Watcher 连接失败: %v", err)
+	}
+	if _, err := conn.Exec(w.ctx, "LISTEN "+pgx.Identifier{w.config.Channel}.Sanitize()); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, fmt.Errorf("Postgres Watcher 监听频道 %s 失败: %v", w.config.Channel, err)
+	}
+	return conn, nil
+}
+
+// connect 建立连接，配置了凭据回调时使用回调返回的密码
+func (w *postgresWatcher) connect() (*pgx.Conn, error) {
+	if w.credentials == nil {
+		return pgx.Connect(w.ctx, w.config.Dsn)
+	}
+
+	config, err := pgx.ParseConfig(w.config.Dsn)
+	if err != nil {
+		return nil, err
+	}
+	password, err := w.credentials(w.ctx, core.DBEndpoint{
+		Host:     config.Host,
+		Port:     config.Port,
+		User:     config.User,
+		Database: config.Database,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库凭据失败: %v", err)
+	}
+	config.Password = password
+	return pgx.ConnectConfig(w.ctx, config)
+}
+
+// run 接收通知直到 Close，连接断开时按退避重连
+func (w *postgresWatcher) run(listener *pgx.Conn) {
+	defer close(w.done)
+
+	for {
+		// 连接出错或 Close 时返回
+		err := w.receive(listener)
+		_ = listener.Close(context.Background())
+		if w.ctx.Err() != nil {
+			return
+		}
+		w.state.down(err)
+
+		listener = w.reconnect()
+		if listener == nil {
+			return
+		}
+
+		// 断线期间可能错过通知，重连后立即重新加载
+		w.state.up()
+		w.notify(core.WatcherResyncMessage)
+	}
+}
+
+// reconnect 按退避重建监听连接，Close 后返回 nil
+func (w *postgresWatcher) reconnect() *pgx.Conn {
+	delay := minReconnectDelay
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		listener, err := w.listen()
+		if err == nil {
+			return listener
+		}
+		w.state.down(err)
+		delay = nextDelay(delay)
+	}
+}
+
+// receive 在监听连接上循环等待通知，连接出错或 Close 时返回
+func (w *postgresWatcher) receive(listener *pgx.Conn) error {
+	for {
+		notification, err := listener.WaitForNotification(w.ctx)
+		if err != nil {
+			return err
+		}
+		payload, ok := w.signer.verify(notification.Payload)
+		if !ok || (w.config.IgnoreSelf && payload == w.instanceID) {
+			continue
+		}
+		w.notify(payload)
+	}
+}
+
+// notify 调用更新回调
+func (w *postgresWatcher) notify(payload string) {
+	w.callbackMu.Lock()
+	callback := w.callback
+	w.callbackMu.Unlock()
+
+	if callback != nil {
+		callback(payload)
+	}
+}
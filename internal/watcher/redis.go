@@ -0,0 +1,226 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultRedisChannel Redis Watcher 默认频道，与 casbin/redis-watcher 的默认值一致
+const defaultRedisChannel = "/casbin"
+
+// defaultRedisHealthCheckInterval 订阅连接的默认心跳间隔
+const defaultRedisHealthCheckInterval = 15 * time.Second
+
+// redisMessage 通知内容，与 casbin/redis-watcher 的消息格式兼容，
+// 未启用签名时升级前后的实例可以互相通知
+type redisMessage struct {
+	Method string
+	ID     string
+}
+
+// redisWatcher 基于 Redis 发布订阅的 Watcher
+// 订阅连接出错或心跳无响应时按退避重新订阅，重新订阅成功后以 core.WatcherResyncMessage 触发一次回调，
+// 弥补断线期间可能错过的通知
+type redisWatcher struct {
+	config     core.RedisWatcherConfig
+	instanceID string
+	signer     *signer          // 通知签名和验证
+	state      *connectionState // 连接状态
+
+	client   *redis.Client
+	pubsubMu sync.Mutex
+	pubsub   *redis.PubSub // 当前订阅，重新订阅时替换
+
+	callbackMu sync.Mutex
+	callback   func(string)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newRedisWatcher 创建 Redis 发布订阅 Watcher，并确认可以建立订阅
+func newRedisWatcher(config core.RedisWatcherConfig, signer *signer, state *connectionState) (*redisWatcher, error) {
+	if config.Channel == "" {
+		config.Channel = defaultRedisChannel
+	}
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = defaultRedisHealthCheckInterval
+	}
+	instanceID, err := newInstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Network:  config.Network,
+		Addr:     config.Addr,
+		Password: config.Password,
+		DB:       config.DB,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &redisWatcher{
+		config:     config,
+		instanceID: instanceID,
+		signer:     signer,
+		state:      state,
+		client:     client,
+		pubsub:     client.Subscribe(ctx, config.Channel),
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	// 等待订阅确认，连接或认证失败时尽早返回错误
+	if _, err := w.pubsub.Receive(ctx); err != nil {
+		cancel()
+		_ = w.pubsub.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("创建 Redis Watcher 失败: %v", err)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// SetUpdateCallback 设置收到通知时的回调
+func (w *redisWatcher) SetUpdateCallback(callback func(string)) error {
+	w.callbackMu.Lock()
+	defer w.callbackMu.Unlock()
+	w.callback = callback
+	return nil
+}
+
+// Update 发布通知到频道
+func (w *redisWatcher) Update() error {
+	payload, _ := json.Marshal(redisMessage{Method: "Update", ID: w.instanceID})
+	message, err := w.signer.sign(string(payload))
+	if err != nil {
+		return err
+	}
+	if err := w.client.Publish(w.ctx, w.config.Channel, message).Err(); err != nil {
+		return fmt.Errorf("Redis Watcher 发送通知失败: %v", err)
+	}
+	return nil
+}
+
+// Stats 获取连接状态
+func (w *redisWatcher) Stats() core.WatcherStats {
+	return w.state.snapshot()
+}
+
+// Close 取消订阅并关闭连接
+func (w *redisWatcher) Close() {
+	w.cancel()
+	w.pubsubMu.Lock()
+	_ = w.pubsub.Close()
+	w.pubsubMu.Unlock()
+	<-w.done
+	_ = w.client.Close()
+}
+
+// run 接收消息直到 Close
+// 空闲超过心跳间隔时发送 PING，下一个间隔内仍没有任何响应视为连接失效（如 Redis 重启后的半开连接）；
+// 接收出错或心跳无响应时按退避重新订阅，重新订阅成功后触发一次强制重新加载
+func (w *redisWatcher) run() {
+	defer close(w.done)
+
+	delay := minReconnectDelay
+	pinged := false
+	for {
+		pubsub := w.subscription()
+		received, err := pubsub.ReceiveTimeout(w.ctx, w.config.HealthCheckInterval)
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return
+			}
+			if isTimeout(err) && !pinged {
+				// PING 的响应作为 *redis.Pong 收到
+				if err = pubsub.Ping(w.ctx); err == nil {
+					pinged = true
+					continue
+				}
+			}
+			pinged = false
+			w.state.down(err)
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = nextDelay(delay)
+			w.resubscribe()
+			continue
+		}
+
+		pinged = false
+		switch received := received.(type) {
+		case *redis.Subscription:
+			// 断线期间可能错过通知，重新订阅后立即重新加载
+			if w.state.up() {
+				delay = minReconnectDelay
+				w.notify(core.WatcherResyncMessage)
+			}
+		case *redis.Message:
+			w.receive(received.Payload)
+		}
+	}
+}
+
+// subscription 获取当前订阅
+func (w *redisWatcher) subscription() *redis.PubSub {
+	w.pubsubMu.Lock()
+	defer w.pubsubMu.Unlock()
+	return w.pubsub
+}
+
+// resubscribe 关闭当前订阅并重新订阅频道，放弃可能已失效的连接；连接失败时由下一次接收按 go-redis 的逻辑重连
+// Close 之后不再订阅
+func (w *redisWatcher) resubscribe() {
+	w.pubsubMu.Lock()
+	defer w.pubsubMu.Unlock()
+	if w.ctx.Err() != nil {
+		return
+	}
+	_ = w.pubsub.Close()
+	w.pubsub = w.client.Subscribe(w.ctx, w.config.Channel)
+}
+
+// isTimeout 是否为读取超时（订阅连接空闲）
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// receive 验证并处理一条通知，无法解析的内容（如其他客户端的消息）仍触发重新加载
+func (w *redisWatcher) receive(message string) {
+	payload, ok := w.signer.verify(message)
+	if !ok {
+		return
+	}
+	var decoded redisMessage
+	if err := json.Unmarshal([]byte(payload), &decoded); err == nil && w.config.IgnoreSelf && decoded.ID == w.instanceID {
+		return
+	}
+	w.notify(payload)
+}
+
+// notify 调用更新回调
+func (w *redisWatcher) notify(payload string) {
+	w.callbackMu.Lock()
+	callback := w.callback
+	w.callbackMu.Unlock()
+
+	if callback != nil {
+		callback(payload)
+	}
+}
@@ -0,0 +1,120 @@
+package watcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// signedMessagePrefix 签名通知的前缀，格式为 sig:v1:<密钥ID>:<Unix 毫秒时间戳>:<base64url(HMAC-SHA256)>:<通知内容>
+const signedMessagePrefix = "sig:v1:"
+
+// defaultSignatureMaxAge 签名通知的默认有效期
+const defaultSignatureMaxAge = time.Minute
+
+// minSigningKeyLength 签名密钥的最小长度
+const minSigningKeyLength = 32
+
+// signer 同步通知的签名和验证
+// 未启用签名时通知原样收发；启用后使用当前密钥签名，验证时按通知中的密钥ID取密钥，
+// 因此密钥轮换期间新旧密钥签名的通知都能通过验证
+type signer struct {
+	config  core.WatcherSigningConfig
+	metrics core.MetricsRecorder
+	logger  core.Logger
+}
+
+// newSigner 创建签名器
+func newSigner(config core.WatcherSigningConfig, metrics core.MetricsRecorder, logger core.Logger) *signer {
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultSignatureMaxAge
+	}
+	if metrics == nil {
+		metrics = core.NopMetrics()
+	}
+	if logger == nil {
+		logger = core.DefaultLogger()
+	}
+	return &signer{config: config, metrics: metrics, logger: logger}
+}
+
+// sign 签名通知内容，未启用签名时原样返回
+func (s *signer) sign(payload string) (string, error) {
+	if !s.config.Enabled() {
+		return payload, nil
+	}
+
+	keyID, key, err := s.config.Keys.CurrentKey()
+	if err != nil {
+		return "", fmt.Errorf("获取同步通知签名密钥失败: %v", err)
+	}
+	if strings.Contains(keyID, ":") {
+		return "", fmt.Errorf("同步通知签名密钥ID不能包含 ':'")
+	}
+	if len(key) < minSigningKeyLength {
+		return "", fmt.Errorf("同步通知签名密钥长度至少为 %d 字节", minSigningKeyLength)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := signature(key, keyID, timestamp, payload)
+	return signedMessagePrefix + keyID + ":" + timestamp + ":" + mac + ":" + payload, nil
+}
+
+// verify 验证收到的通知并返回通知内容，ok 为 false 时通知应被丢弃（已记录指标和日志）
+func (s *signer) verify(message string) (payload string, ok bool) {
+	if !s.config.Enabled() {
+		return message, true
+	}
+
+	rest, signed := strings.CutPrefix(message, signedMessagePrefix)
+	if !signed {
+		if s.config.AcceptUnsigned {
+			return message, true
+		}
+		return "", s.reject(core.WatcherRejectUnsigned, "")
+	}
+	parts := strings.SplitN(rest, ":", 4)
+	if len(parts) != 4 {
+		return "", s.reject(core.WatcherRejectUnsigned, "")
+	}
+	keyID, timestamp, mac, payload := parts[0], parts[1], parts[2], parts[3]
+
+	key, err := s.config.Keys.Key(keyID)
+	if err != nil || len(key) < minSigningKeyLength {
+		return "", s.reject(core.WatcherRejectUnknownKey, keyID)
+	}
+	if !hmac.Equal([]byte(mac), []byte(signature(key, keyID, timestamp, payload))) {
+		return "", s.reject(core.WatcherRejectBadSignature, keyID)
+	}
+
+	// 签名通过后再检查时间，过期的通知可能是截获后的重放
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", s.reject(core.WatcherRejectUnsigned, keyID)
+	}
+	age := time.Since(time.UnixMilli(sentAt))
+	if age > s.config.MaxAge || age < -s.config.MaxAge {
+		return "", s.reject(core.WatcherRejectExpired, keyID)
+	}
+	return payload, true
+}
+
+// reject 记录被拒绝的通知，始终返回 false
+func (s *signer) reject(reason, keyID string) bool {
+	s.metrics.ObserveWatcherRejected(reason)
+	s.logger.Warn("丢弃未通过验证的同步通知", "reason", reason, "keyId", keyID)
+	return false
+}
+
+// signature 计算签名，覆盖版本、密钥ID、时间戳和通知内容
+func signature(key []byte, keyID, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("v1:" + keyID + ":" + timestamp + ":" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
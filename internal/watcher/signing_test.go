@@ -0,0 +1,207 @@
+package watcher
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// rejectRecorder 记录被拒绝通知的原因
+type rejectRecorder struct {
+	core.MetricsRecorder
+	reasons []string
+}
+
+func (r *rejectRecorder) ObserveWatcherRejected(reason string) {
+	r.reasons = append(r.reasons, reason)
+}
+
+var (
+	oldSigningKey = bytes.Repeat([]byte("o"), minSigningKeyLength)
+	newSigningKey = bytes.Repeat([]byte("n"), minSigningKeyLength)
+)
+
+// signedWith 使用指定密钥和时间签名通知，用于构造轮换前的通知或过期通知
+func signedWith(keyID string, key []byte, sentAt time.Time, payload string) string {
+	timestamp := strconv.FormatInt(sentAt.UnixMilli(), 10)
+	return signedMessagePrefix + keyID + ":" + timestamp + ":" + signature(key, keyID, timestamp, payload) + ":" + payload
+}
+
+func TestSignerVerify(t *testing.T) {
+	rotated := core.StaticKeyProvider{
+		CurrentKeyID: "k2",
+		Keys:         map[string][]byte{"k1": oldSigningKey, "k2": newSigningKey},
+	}
+	current := core.StaticKeyProvider{
+		CurrentKeyID: "k2",
+		Keys:         map[string][]byte{"k2": newSigningKey},
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		config      core.WatcherSigningConfig
+		message     string
+		wantPayload string
+		wantOK      bool
+		wantReason  string
+	}{
+		{
+			name:        "签名未启用时原样接收",
+			config:      core.WatcherSigningConfig{},
+			message:     "reload",
+			wantPayload: "reload",
+			wantOK:      true,
+		},
+		{
+			name:        "当前密钥签名",
+			config:      core.WatcherSigningConfig{Keys: rotated},
+			message:     signedWith("k2", newSigningKey, now, "reload"),
+			wantPayload: "reload",
+			wantOK:      true,
+		},
+		{
+			name:        "轮换期间旧密钥签名",
+			config:      core.WatcherSigningConfig{Keys: rotated},
+			message:     signedWith("k1", oldSigningKey, now, "reload"),
+			wantPayload: "reload",
+			wantOK:      true,
+		},
+		{
+			name:       "旧密钥移除后拒绝",
+			config:     core.WatcherSigningConfig{Keys: current},
+			message:    signedWith("k1", oldSigningKey, now, "reload"),
+			wantReason: core.WatcherRejectUnknownKey,
+		},
+		{
+			name:       "超出有效期",
+			config:     core.WatcherSigningConfig{Keys: current, MaxAge: time.Minute},
+			message:    signedWith("k2", newSigningKey, now.Add(-2*time.Minute), "reload"),
+			wantReason: core.WatcherRejectExpired,
+		},
+		{
+			name:       "超出有效期的未来时间",
+			config:     core.WatcherSigningConfig{Keys: current, MaxAge: time.Minute},
+			message:    signedWith("k2", newSigningKey, now.Add(2*time.Minute), "reload"),
+			wantReason: core.WatcherRejectExpired,
+		},
+		{
+			name:       "伪造签名",
+			config:     core.WatcherSigningConfig{Keys: current},
+			message:    signedWith("k2", bytes.Repeat([]byte("x"), minSigningKeyLength), now, "reload"),
+			wantReason: core.WatcherRejectBadSignature,
+		},
+		{
+			name:       "篡改通知内容",
+			config:     core.WatcherSigningConfig{Keys: current},
+			message:    strings.TrimSuffix(signedWith("k2", newSigningKey, now, "reload"), "reload") + "forged",
+			wantReason: core.WatcherRejectBadSignature,
+		},
+		{
+			name:       "未签名通知",
+			config:     core.WatcherSigningConfig{Keys: current},
+			message:    "reload",
+			wantReason: core.WatcherRejectUnsigned,
+		},
+		{
+			name:       "签名格式无效",
+			config:     core.WatcherSigningConfig{Keys: current},
+			message:    signedMessagePrefix + "k2:reload",
+			wantReason: core.WatcherRejectUnsigned,
+		},
+		{
+			name:        "AcceptUnsigned 接收未签名通知",
+			config:      core.WatcherSigningConfig{Keys: current, AcceptUnsigned: true},
+			message:     "reload",
+			wantPayload: "reload",
+			wantOK:      true,
+		},
+		{
+			name:       "AcceptUnsigned 仍验证已签名通知",
+			config:     core.WatcherSigningConfig{Keys: current, AcceptUnsigned: true},
+			message:    signedWith("k2", bytes.Repeat([]byte("x"), minSigningKeyLength), now, "reload"),
+			wantReason: core.WatcherRejectBadSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &rejectRecorder{MetricsRecorder: core.NopMetrics()}
+			s := newSigner(tt.config, metrics, nil)
+
+			payload, ok := s.verify(tt.message)
+			if ok != tt.wantOK || payload != tt.wantPayload {
+				t.Fatalf("verify() = (%q, %v), want (%q, %v)", payload, ok, tt.wantPayload, tt.wantOK)
+			}
+
+			var wantReasons []string
+			if tt.wantReason != "" {
+				wantReasons = []string{tt.wantReason}
+			}
+			if strings.Join(metrics.reasons, ",") != strings.Join(wantReasons, ",") {
+				t.Fatalf("rejected reasons = %v, want %v", metrics.reasons, wantReasons)
+			}
+		})
+	}
+}
+
+func TestSignerSign(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  core.WatcherSigningConfig
+		wantErr bool
+		signed  bool
+	}{
+		{
+			name:   "签名未启用时原样发送",
+			config: core.WatcherSigningConfig{},
+		},
+		{
+			name:   "使用当前密钥签名",
+			config: core.WatcherSigningConfig{Keys: core.StaticKeyProvider{CurrentKeyID: "k2", Keys: map[string][]byte{"k2": newSigningKey}}},
+			signed: true,
+		},
+		{
+			name:    "当前密钥不存在",
+			config:  core.WatcherSigningConfig{Keys: core.StaticKeyProvider{CurrentKeyID: "k3", Keys: map[string][]byte{"k2": newSigningKey}}},
+			wantErr: true,
+		},
+		{
+			name:    "密钥过短",
+			config:  core.WatcherSigningConfig{Keys: core.StaticKeyProvider{CurrentKeyID: "k2", Keys: map[string][]byte{"k2": []byte("short")}}},
+			wantErr: true,
+		},
+		{
+			name:    "密钥ID包含分隔符",
+			config:  core.WatcherSigningConfig{Keys: core.StaticKeyProvider{CurrentKeyID: "k:2", Keys: map[string][]byte{"k:2": newSigningKey}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSigner(tt.config, nil, nil)
+
+			message, err := s.sign("reload")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sign() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if strings.HasPrefix(message, signedMessagePrefix) != tt.signed {
+				t.Fatalf("sign() = %q, signed %v", message, tt.signed)
+			}
+
+			// 签名后的通知能被同一配置验证
+			payload, ok := s.verify(message)
+			if !ok || payload != "reload" {
+				t.Fatalf("verify(sign()) = (%q, %v), want (%q, true)", payload, ok, "reload")
+			}
+		})
+	}
+}
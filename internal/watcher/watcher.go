@@ -0,0 +1,144 @@
+package watcher
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// 断线重连的退避时长
+const (
+	minReconnectDelay = 100 * time.Millisecond
+	maxReconnectDelay = 10 * time.Second
+)
+
+// NewWatcher 按 Config.Watcher.Type 创建内置 Watcher
+// dsn 为 Config.Dsn，Postgres Watcher 未单独配置连接字符串时使用；credentials 不为空时 Postgres Watcher 通过回调获取连接密码；
+// 启用签名时未通过验证的通知记录到 metrics 和 logger 后丢弃；断线和重连同样记录到 metrics 和 logger，并通过 Stats 报告
+func NewWatcher(config core.WatcherConfig, dsn string, credentials core.DBCredentialsProvider, metrics core.MetricsRecorder, logger core.Logger) (core.Watcher, error) {
+	signer := newSigner(config.Signing, metrics, logger)
+	switch config.Type {
+	case "", core.WatcherTypeRedis:
+		return newRedisWatcher(config.Redis, signer, newConnectionState(core.WatcherTypeRedis, metrics, logger))
+	case core.WatcherTypePostgres:
+		if config.Postgres.Dsn == "" {
+			config.Postgres.Dsn = dsn
+		}
+		return newPostgresWatcher(config.Postgres, credentials, signer, newConnectionState(core.WatcherTypePostgres, metrics, logger))
+	case core.WatcherTypeNATS:
+		return newNATSWatcher(config.NATS, signer, newConnectionState(core.WatcherTypeNATS, metrics, logger))
+	default:
+		return nil, fmt.Errorf("不支持的 Watcher 类型: %s，仅支持 'redis'、'postgres'、'nats'", config.Type)
+	}
+}
+
+// Validate 校验内置 Watcher 的配置（在连接数据库之前尽早发现配置错误）
+func Validate(config core.WatcherConfig) error {
+	switch config.Type {
+	case "", core.WatcherTypeRedis:
+		if config.Redis.Addr == "" {
+			return fmt.Errorf("config.Watcher.Redis.Addr 未设置（单实例部署可设置 config.Watcher.Disabled）")
+		}
+	case core.WatcherTypePostgres:
+	case core.WatcherTypeNATS:
+		if config.NATS.URL == "" {
+			return fmt.Errorf("config.Watcher.NATS.URL 未设置")
+		}
+	default:
+		return fmt.Errorf("不支持的 Watcher 类型: %s，仅支持 'redis'、'postgres'、'nats'", config.Type)
+	}
+	if config.Signing.MaxAge < 0 {
+		return fmt.Errorf("config.Watcher.Signing.MaxAge 不能为负数")
+	}
+	return nil
+}
+
+// newInstanceID 生成实例标识，作为通知内容用于识别自己发布的消息（启用签名时为签名的内容）
+func newInstanceID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成 Watcher 实例标识失败: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// nextDelay 计算下一次重连的退避时长
+func nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay > maxReconnectDelay {
+		return maxReconnectDelay
+	}
+	return delay
+}
+
+// connectionState 内置 Watcher 的连接状态，断线和重连时更新统计并记录指标和日志
+type connectionState struct {
+	metrics core.MetricsRecorder
+	logger  core.Logger
+
+	mu    sync.Mutex
+	stats core.WatcherStats
+}
+
+// newConnectionState 创建连接状态，Watcher 创建成功时已处于连接状态
+func newConnectionState(watcherType core.WatcherType, metrics core.MetricsRecorder, logger core.Logger) *connectionState {
+	if metrics == nil {
+		metrics = core.NopMetrics()
+	}
+	if logger == nil {
+		logger = core.DefaultLogger()
+	}
+	return &connectionState{
+		metrics: metrics,
+		logger:  logger,
+		stats:   core.WatcherStats{Type: watcherType, Connected: true},
+	}
+}
+
+// down 记录一次连接错误：处于连接状态时计为断线，已断线时计为一次重连失败
+func (s *connectionState) down(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.stats.LastError = err.Error()
+	}
+	if s.stats.Connected {
+		s.stats.Connected = false
+		s.stats.Disconnects++
+		s.stats.LastDisconnectAt = time.Now()
+		s.metrics.ObserveWatcherConnection(s.stats.Type, core.WatcherEventDisconnected)
+		s.logger.Warn("Watcher 连接断开，策略同步通知暂停，将按退避重连", "watcher", s.stats.Type, "error", err)
+		return
+	}
+	s.stats.ReconnectFailures++
+	s.metrics.ObserveWatcherConnection(s.stats.Type, core.WatcherEventReconnectFailed)
+	s.logger.Debug("Watcher 重连失败", "watcher", s.stats.Type, "error", err)
+}
+
+// up 记录连接恢复，返回是否从断线状态恢复（需要弥补断线期间错过的通知）
+func (s *connectionState) up() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stats.Connected {
+		return false
+	}
+	s.stats.Connected = true
+	s.stats.Reconnects++
+	s.stats.LastReconnectAt = time.Now()
+	s.metrics.ObserveWatcherConnection(s.stats.Type, core.WatcherEventReconnected)
+	s.logger.Info("Watcher 重连成功，重新加载策略以弥补断线期间的通知", "watcher", s.stats.Type, "disconnectedFor", s.stats.LastReconnectAt.Sub(s.stats.LastDisconnectAt))
+	return true
+}
+
+// snapshot 获取连接状态
+func (s *connectionState) snapshot() core.WatcherStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
@@ -0,0 +1,304 @@
+// Package middleware 提供 HTTP 鉴权中间件
+//
+// 中间件从请求中提取用户和租户（提取方式可配置），按路由和请求方法推导出需要的权限并检查，
+// 未通过时返回带 core.Error 错误码的 JSON 响应。中间件基于 net/http 实现，
+// echo 可通过 echo.WrapMiddleware 直接使用，gin 等框架可在自己的中间件中调用 Authorize 和 WriteError。
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// Checker 权限检查接口，engine.CasbinX 和 offline.Checker 之外的实现也可以接入
+type Checker interface {
+	CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error)
+}
+
+// Extractor 从请求中提取标识，返回空字符串表示请求中没有该标识
+type Extractor func(r *http.Request) string
+
+// PermissionMapper 推导请求需要的权限
+type PermissionMapper func(r *http.Request) (core.Permission, error)
+
+// ErrorHandler 鉴权未通过时写入响应
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// contextKey 请求上下文的键
+type contextKey string
+
+const (
+	userKeyContextKey   contextKey = "casbinx.userKey"   // 用户标识
+	tenantKeyContextKey contextKey = "casbinx.tenantKey" // 租户标识
+)
+
+// WithUserKey 将用户标识写入上下文，供认证中间件在鉴权前调用（默认的用户提取方式从此处读取）
+func WithUserKey(ctx context.Context, userKey string) context.Context {
+	return context.WithValue(ctx, userKeyContextKey, userKey)
+}
+
+// WithTenantKey 将租户标识写入上下文（默认的租户提取方式从此处读取）
+func WithTenantKey(ctx context.Context, tenantKey string) context.Context {
+	return context.WithValue(ctx, tenantKeyContextKey, tenantKey)
+}
+
+// UserKeyFromContext 从上下文读取 WithUserKey 写入的用户标识
+func UserKeyFromContext(ctx context.Context) string {
+	userKey, _ := ctx.Value(userKeyContextKey).(string)
+	return userKey
+}
+
+// TenantKeyFromContext 从上下文读取 WithTenantKey 写入的租户标识
+func TenantKeyFromContext(ctx context.Context) string {
+	tenantKey, _ := ctx.Value(tenantKeyContextKey).(string)
+	return tenantKey
+}
+
+// FromContext 从请求上下文的指定键读取字符串
+func FromContext(key any) Extractor {
+	return func(r *http.Request) string {
+		value, _ := r.Context().Value(key).(string)
+		return value
+	}
+}
+
+// FromHeader 从请求头读取（只应在网关已认证并覆盖该请求头时使用）
+func FromHeader(name string) Extractor {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(name))
+	}
+}
+
+// FromPathValue 从路由参数读取（net/http 的 {name} 通配符）
+func FromPathValue(name string) Extractor {
+	return func(r *http.Request) string {
+		return r.PathValue(name)
+	}
+}
+
+// FromQuery 从查询参数读取
+func FromQuery(name string) Extractor {
+	return func(r *http.Request) string {
+		return r.URL.Query().Get(name)
+	}
+}
+
+// Static 固定值，如单租户服务的租户标识
+func Static(value string) Extractor {
+	return func(*http.Request) string {
+		return value
+	}
+}
+
+// FirstOf 依次尝试多个提取方式，返回第一个非空结果
+func FirstOf(extractors ...Extractor) Extractor {
+	return func(r *http.Request) string {
+		for _, extract := range extractors {
+			if value := extract(r); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+}
+
+// ActionForMethod 请求方法对应的操作：GET/HEAD/OPTIONS 为读取，POST/PUT/PATCH 为写入，DELETE 为删除
+func ActionForMethod(method string) (core.Action, bool) {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return core.ActionRead, true
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return core.ActionWrite, true
+	case http.MethodDelete:
+		return core.ActionDelete, true
+	default:
+		return "", false
+	}
+}
+
+// RoutePermission 按路径和请求方法推导权限：去掉 prefix 后第一段为资源，第二段（如有）为对象ID，操作由 ActionForMethod 决定
+// 如 prefix 为 /api 时，GET /api/documents 需要 documents:read，DELETE /api/documents/123 需要对象 123 的 documents:delete
+// （类型级授权覆盖该类资源的所有对象）
+func RoutePermission(prefix string) PermissionMapper {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(r *http.Request) (core.Permission, error) {
+		action, ok := ActionForMethod(r.Method)
+		if !ok {
+			return core.Permission{}, core.NewFieldError("method", core.ConstraintOneOf)
+		}
+		path, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok {
+			return core.Permission{}, core.NewFieldError("path", core.ConstraintFormat)
+		}
+
+		segments := strings.Split(strings.Trim(path, "/"), "/")
+		if segments[0] == "" {
+			return core.Permission{}, core.NewFieldError("path", core.ConstraintRequired)
+		}
+		permission := core.Permission{Resource: core.Resource(segments[0]), Action: action}
+		if len(segments) > 1 {
+			permission.ObjectID = segments[1]
+		}
+		return permission, nil
+	}
+}
+
+// StaticPermission 固定权限，用于按路由单独声明需要的权限
+func StaticPermission(permission core.Permission) PermissionMapper {
+	return func(*http.Request) (core.Permission, error) {
+		return permission, nil
+	}
+}
+
+// Authorizer HTTP 鉴权器
+type Authorizer struct {
+	checker    Checker
+	user       Extractor
+	tenant     Extractor
+	permission PermissionMapper
+	onError    ErrorHandler
+}
+
+// Option 鉴权器选项
+type Option func(*Authorizer)
+
+// WithUserExtractor 指定用户标识的提取方式，默认读取 WithUserKey 写入上下文的值
+func WithUserExtractor(extractor Extractor) Option {
+	return func(a *Authorizer) {
+		if extractor != nil {
+			a.user = extractor
+		}
+	}
+}
+
+// WithTenantExtractor 指定租户标识的提取方式，默认读取 WithTenantKey 写入上下文的值
+func WithTenantExtractor(extractor Extractor) Option {
+	return func(a *Authorizer) {
+		if extractor != nil {
+			a.tenant = extractor
+		}
+	}
+}
+
+// WithPermissionMapper 指定权限的推导方式，默认 RoutePermission("")
+func WithPermissionMapper(mapper PermissionMapper) Option {
+	return func(a *Authorizer) {
+		if mapper != nil {
+			a.permission = mapper
+		}
+	}
+}
+
+// WithErrorHandler 指定鉴权未通过时的响应，默认 WriteError
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(a *Authorizer) {
+		if handler != nil {
+			a.onError = handler
+		}
+	}
+}
+
+// New 创建鉴权器
+func New(checker Checker, opts ...Option) *Authorizer {
+	a := &Authorizer{
+		checker:    checker,
+		user:       func(r *http.Request) string { return UserKeyFromContext(r.Context()) },
+		tenant:     func(r *http.Request) string { return TenantKeyFromContext(r.Context()) },
+		permission: RoutePermission(""),
+		onError:    func(w http.ResponseWriter, _ *http.Request, err error) { WriteError(w, err) },
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Authorize 检查请求是否有权访问：缺少用户返回 ErrUnauthenticated，缺少租户或无法推导权限返回 *core.FieldError（ErrInvalidParameter），
+// 没有权限返回 ErrPermissionDenied，检查出错时返回原错误
+func (a *Authorizer) Authorize(r *http.Request) error {
+	userKey := a.user(r)
+	if userKey == "" {
+		return core.ErrUnauthenticated
+	}
+	tenantKey := a.tenant(r)
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return err
+	}
+	permission, err := a.permission(r)
+	if err != nil {
+		return err
+	}
+	if err := core.RequirePermissionArg("permission", permission); err != nil {
+		return err
+	}
+
+	allowed, err := a.checker.CheckPermission(userKey, tenantKey, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return core.ErrPermissionDenied
+	}
+	return nil
+}
+
+// Middleware net/http 中间件，鉴权通过后调用 next
+func (a *Authorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := a.Authorize(r); err != nil {
+			a.onError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require 使用固定权限代替推导出的权限，用于单个路由
+//
+//	mux.Handle("POST /reports/export", authz.Require(core.Permission{Resource: "report", Action: "export"})(exportHandler))
+func (a *Authorizer) Require(permission core.Permission) func(http.Handler) http.Handler {
+	route := *a
+	route.permission = StaticPermission(permission)
+	return route.Middleware
+}
+
+// errorResponse 错误响应，参数校验错误附带参数名和约束
+type errorResponse struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Field      string `json:"field,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// WriteError 将鉴权错误写入 JSON 响应：缺少用户返回 401，参数错误返回 400，
+// 内部错误返回 500（不暴露错误详情），其余 core.Error（包括 ErrPermissionDenied）返回 403
+func WriteError(w http.ResponseWriter, err error) {
+	var coreErr core.Error
+	var fieldErr *core.FieldError
+	switch {
+	case errors.As(err, &fieldErr):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: fieldErr.Error(), Field: fieldErr.Field, Constraint: fieldErr.Constraint})
+	case errors.Is(err, core.ErrUnauthenticated):
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Code: core.ErrUnauthenticated.Code, Message: core.ErrUnauthenticated.Message})
+	case errors.Is(err, core.ErrInvalidParameter):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: core.ErrInvalidParameter.Message})
+	case errors.Is(err, core.ErrInternal):
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Code: core.ErrInternal.Code, Message: core.ErrInternal.Message})
+	case errors.As(err, &coreErr):
+		writeJSON(w, http.StatusForbidden, errorResponse{Code: coreErr.Code, Message: coreErr.Message})
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Code: core.ErrInternal.Code, Message: core.ErrInternal.Message})
+	}
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// TagName 权限标注的结构体标签名
+const TagName = "casbinx"
+
+// PathParam 按名称读取路由参数
+type PathParam func(r *http.Request, name string) string
+
+// PermissionTag 请求结构体上声明的权限
+//
+// 在请求结构体的任意字段（通常是空白字段）上声明：
+//
+//	type DeleteOrderReq struct {
+//		_  struct{} `casbinx:"resource=order,action=delete,object=id"`
+//		ID string   `path:"id"`
+//	}
+//
+// resource 必填；action 省略时按请求方法推导（见 ActionForMethod）；object 为路由参数名，声明后检查对象级权限
+type PermissionTag struct {
+	Resource    core.Resource // 资源
+	Action      core.Action   // 操作，为空表示按请求方法推导
+	ObjectParam string        // 对象ID所在的路由参数名，为空表示类型级权限
+}
+
+// ParsePermissionTag 解析请求结构体（或其指针）上的权限标注
+// 没有标注、标注多于一处或标注格式无效时返回错误，应在注册路由时调用，让标注错误在启动时暴露
+func ParsePermissionTag(req any) (PermissionTag, error) {
+	t := reflect.TypeOf(req)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return PermissionTag{}, fmt.Errorf("权限标注只支持结构体，得到 %v", t)
+	}
+
+	var tag PermissionTag
+	found := false
+	for i := 0; i < t.NumField(); i++ {
+		value, ok := t.Field(i).Tag.Lookup(TagName)
+		if !ok {
+			continue
+		}
+		if found {
+			return PermissionTag{}, fmt.Errorf("%s 声明了多个 %s 标签", t.Name(), TagName)
+		}
+		found = true
+
+		parsed, err := parseTagValue(value)
+		if err != nil {
+			return PermissionTag{}, fmt.Errorf("%s.%s 的 %s 标签无效: %v", t.Name(), t.Field(i).Name, TagName, err)
+		}
+		tag = parsed
+	}
+	if !found {
+		return PermissionTag{}, fmt.Errorf("%s 没有声明 %s 标签", t.Name(), TagName)
+	}
+	return tag, nil
+}
+
+// parseTagValue 解析 "resource=order,action=write,object=id" 格式的标签值
+func parseTagValue(value string) (PermissionTag, error) {
+	var tag PermissionTag
+	for _, part := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		val = strings.TrimSpace(val)
+		if !ok || val == "" {
+			return PermissionTag{}, fmt.Errorf("%q 不是 key=value 格式", part)
+		}
+		switch strings.TrimSpace(key) {
+		case "resource":
+			tag.Resource = core.Resource(val)
+		case "action":
+			tag.Action = core.Action(val)
+		case "object":
+			tag.ObjectParam = val
+		default:
+			return PermissionTag{}, fmt.Errorf("不支持的键 %q", key)
+		}
+	}
+	if tag.Resource == "" {
+		return PermissionTag{}, fmt.Errorf("缺少 resource")
+	}
+	return tag, nil
+}
+
+// Mapper 生成按标注推导权限的方式，对象ID通过 param 读取路由参数
+func (t PermissionTag) Mapper(param PathParam) PermissionMapper {
+	return func(r *http.Request) (core.Permission, error) {
+		permission := core.Permission{Resource: t.Resource, Action: t.Action}
+		if permission.Action == "" {
+			action, ok := ActionForMethod(r.Method)
+			if !ok {
+				return core.Permission{}, core.NewFieldError("method", core.ConstraintOneOf)
+			}
+			permission.Action = action
+		}
+		if t.ObjectParam != "" {
+			permission.ObjectID = param(r, t.ObjectParam)
+			if permission.ObjectID == "" {
+				return core.Permission{}, core.NewFieldError(t.ObjectParam, core.ConstraintRequired)
+			}
+		}
+		return permission, nil
+	}
+}
+
+// TaggedPermission 按请求结构体的权限标注推导权限，对象ID读取 net/http 的路由参数
+func TaggedPermission(req any) (PermissionMapper, error) {
+	tag, err := ParsePermissionTag(req)
+	if err != nil {
+		return nil, err
+	}
+	return tag.Mapper(func(r *http.Request, name string) string { return r.PathValue(name) }), nil
+}
+
+// RequireTagged 使用请求结构体上标注的权限，用于单个路由；标注无效时返回错误
+//
+//	requireDelete, err := authz.RequireTagged(DeleteOrderReq{})
+//	mux.Handle("DELETE /orders/{id}", requireDelete(deleteHandler))
+func (a *Authorizer) RequireTagged(req any) (func(http.Handler) http.Handler, error) {
+	mapper, err := TaggedPermission(req)
+	if err != nil {
+		return nil, err
+	}
+	return a.RequireMapper(mapper), nil
+}
+
+// RequireMapper 使用指定的权限推导方式代替默认方式，用于单个路由
+func (a *Authorizer) RequireMapper(mapper PermissionMapper) func(http.Handler) http.Handler {
+	route := *a
+	route.permission = mapper
+	return route.Middleware
+}
@@ -0,0 +1,201 @@
+// Package notifier 将 CasbinX 的敏感事件推送到 Slack 或 Microsoft Teams
+//
+// Notifier 按事件类别过滤、使用 text/template 格式化消息，并对投递做固定窗口限流，
+// 通过 engine.WithSecurityEventHook(n.Notify) 接入引擎。
+// 引擎之外的事件（如应用自身的应急提权流程）也可以直接调用 Notify 上报。
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// Platform 消息平台
+type Platform string
+
+// 支持的消息平台
+const (
+	PlatformSlack Platform = "slack" // Slack Incoming Webhook
+	PlatformTeams Platform = "teams" // Microsoft Teams Incoming Webhook
+)
+
+// 默认值
+const (
+	DefaultRateLimit    = 10          // 默认每个限流窗口最多投递的消息数
+	DefaultRateInterval = time.Minute // 默认限流窗口
+)
+
+// DefaultTemplate 默认消息模板，数据为 core.SecurityEvent
+const DefaultTemplate = `[CasbinX] {{.Class}} {{.Action}}{{if .TenantKey}} 租户 {{.TenantKey}}{{end}}{{if .Target}} 对象 {{.Target}}{{end}}` +
+	`{{if .OperatorKey}} 操作者 {{.OperatorKey}}{{end}}{{if .Detail}}：{{.Detail}}{{end}}`
+
+// Config 通知配置
+type Config struct {
+	// Platform 消息平台，slack 或 teams
+	Platform Platform `json:"platform"`
+
+	// WebhookURL Incoming Webhook 地址
+	WebhookURL string `json:"webhookUrl"`
+
+	// Classes 需要通知的事件类别，为空时通知所有类别
+	Classes []core.SecurityEventClass `json:"classes"`
+
+	// Template 消息模板（text/template，数据为 core.SecurityEvent），为空时使用 DefaultTemplate
+	Template string `json:"template"`
+
+	// RateLimit 每个限流窗口最多投递的消息数，默认 10；超出的事件被丢弃，丢弃数量附在窗口后的第一条消息中
+	RateLimit int `json:"rateLimit"`
+
+	// RateInterval 限流窗口，默认 1m
+	RateInterval time.Duration `json:"rateInterval"`
+}
+
+// Notifier 敏感事件通知器，可并发使用
+type Notifier struct {
+	config   Config
+	classes  map[core.SecurityEventClass]bool
+	template *template.Template
+	client   *http.Client
+
+	mu          sync.Mutex
+	windowStart time.Time
+	sent        int
+	suppressed  int
+}
+
+// New 创建通知器
+// client 为空时使用超时为 core.DefaultWebhookTimeout 的默认客户端
+func New(config Config, client *http.Client) (*Notifier, error) {
+	if config.Platform != PlatformSlack && config.Platform != PlatformTeams {
+		return nil, fmt.Errorf("不支持的消息平台: %s，仅支持 slack、teams", config.Platform)
+	}
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("通知缺少 Webhook 地址")
+	}
+	if config.Template == "" {
+		config.Template = DefaultTemplate
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = DefaultRateLimit
+	}
+	if config.RateInterval <= 0 {
+		config.RateInterval = DefaultRateInterval
+	}
+	if client == nil {
+		client = &http.Client{Timeout: core.DefaultWebhookTimeout}
+	}
+
+	tmpl, err := template.New("notifier").Parse(config.Template)
+	if err != nil {
+		return nil, fmt.Errorf("解析消息模板失败: %v", err)
+	}
+
+	var classes map[core.SecurityEventClass]bool
+	if len(config.Classes) > 0 {
+		classes = make(map[core.SecurityEventClass]bool, len(config.Classes))
+		for _, class := range config.Classes {
+			classes[class] = true
+		}
+	}
+
+	return &Notifier{
+		config:   config,
+		classes:  classes,
+		template: tmpl,
+		client:   client,
+	}, nil
+}
+
+// Notify 格式化并投递事件，签名与 core.SecurityEventHook 一致
+// 未订阅的类别和被限流的事件直接返回 nil；非 2xx 响应视为投递失败
+func (n *Notifier) Notify(ctx context.Context, event core.SecurityEvent) error {
+	if n.classes != nil && !n.classes[event.Class] {
+		return nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	suppressed, ok := n.acquire(event.Time)
+	if !ok {
+		return nil
+	}
+
+	var text strings.Builder
+	if err := n.template.Execute(&text, event); err != nil {
+		return fmt.Errorf("渲染消息模板失败: %v", err)
+	}
+	if suppressed > 0 {
+		fmt.Fprintf(&text, "\n（此前有 %d 条事件因限流未发送）", suppressed)
+	}
+
+	return n.post(ctx, text.String())
+}
+
+// acquire 占用限流窗口内的一次投递，返回上个窗口被丢弃的事件数
+func (n *Notifier) acquire(now time.Time) (int, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if now.Sub(n.windowStart) >= n.config.RateInterval {
+		n.windowStart = now
+		n.sent = 0
+	}
+	if n.sent >= n.config.RateLimit {
+		n.suppressed++
+		return 0, false
+	}
+	n.sent++
+
+	suppressed := n.suppressed
+	n.suppressed = 0
+	return suppressed, true
+}
+
+// post 按平台格式投递消息
+func (n *Notifier) post(ctx context.Context, text string) error {
+	body, err := json.Marshal(n.payload(text))
+	if err != nil {
+		return fmt.Errorf("序列化通知消息失败: %v", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建 Webhook 请求失败: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("投递通知失败: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("投递通知失败: Webhook 返回状态码 %d", response.StatusCode)
+	}
+	return nil
+}
+
+// payload 生成平台消息体
+func (n *Notifier) payload(text string) any {
+	if n.config.Platform == PlatformTeams {
+		// Teams Incoming Webhook 的 MessageCard 格式
+		return map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  "CasbinX 敏感事件",
+			"text":     text,
+		}
+	}
+	return map[string]string{"text": text}
+}
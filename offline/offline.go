@@ -0,0 +1,190 @@
+// Package offline 提供脱离数据库的权限校验能力
+//
+// 典型用法是在 CI 中加载导出的策略快照（CSV 格式，与 Casbin 文件适配器兼容），
+// 对照"黄金访问矩阵"逐条校验，及时发现意外的权限变化。
+// 快照只包含权限策略和角色分配，不包含环境标记、共享角色链接和租户封锁等数据库侧状态。
+package offline
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rezeropoint/casbinx/core"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	fileadapter "github.com/casbin/casbin/v2/persist/file-adapter"
+)
+
+// DefaultModel 默认 Casbin 模型，与引擎内置模型一致
+var DefaultModel = core.DefaultModelText
+
+// Checker 离线权限检查器
+type Checker struct {
+	enforcer *core.Enforcer
+}
+
+// Expectation 访问矩阵中的一条期望
+type Expectation struct {
+	UserKey    string          `json:"userKey"`    // 用户标识
+	TenantKey  string          `json:"tenantKey"`  // 租户标识
+	Permission core.Permission `json:"permission"` // 被检查的权限
+	Allowed    bool            `json:"allowed"`    // 期望的检查结果
+}
+
+// Mismatch 与期望不符的检查结果
+type Mismatch struct {
+	Expectation
+	Actual bool `json:"actual"` // 实际的检查结果
+}
+
+// LoadPoliciesFromFile 从策略快照文件创建离线检查器
+// modelPath 为空时使用 DefaultModel
+func LoadPoliciesFromFile(modelPath, policyPath string) (*Checker, error) {
+	if err := core.RequireArg("policyPath", policyPath); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(policyPath); err != nil {
+		return nil, fmt.Errorf("策略快照文件不存在: %v", err)
+	}
+
+	var m model.Model
+	var err error
+	if modelPath == "" {
+		m, err = model.NewModelFromString(DefaultModel)
+	} else {
+		m, err = model.NewModelFromFile(modelPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("加载Casbin模型失败: %v", err)
+	}
+
+	casbinEnforcer, err := casbin.NewEnforcer(m, fileadapter.NewAdapter(policyPath))
+	if err != nil {
+		return nil, fmt.Errorf("创建Casbin执行器失败: %v", err)
+	}
+	// 离线检查器只读，禁止回写快照文件
+	casbinEnforcer.EnableAutoSave(false)
+
+	enforcer, err := core.NewEnforcer(casbinEnforcer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checker{enforcer: enforcer}, nil
+}
+
+// NewChecker 使用内存中的策略创建离线检查器（不依赖任何文件或数据库）
+func NewChecker(policies []core.Policy, groupings []core.GroupingPolicy) (*Checker, error) {
+	m, err := model.NewModelFromString(DefaultModel)
+	if err != nil {
+		return nil, fmt.Errorf("加载Casbin模型失败: %v", err)
+	}
+
+	casbinEnforcer, err := casbin.NewEnforcer(m)
+	if err != nil {
+		return nil, fmt.Errorf("创建Casbin执行器失败: %v", err)
+	}
+
+	enforcer, err := core.NewEnforcer(casbinEnforcer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 离线执行器不带适配器，规则只写入内存
+	ctx := context.Background()
+	for _, policy := range policies {
+		if err := enforcer.AddPolicy(ctx, policy.Subject, policy.Domain, core.Permission{Resource: policy.Resource, Action: policy.Action}); err != nil {
+			return nil, fmt.Errorf("写入策略失败: %v", err)
+		}
+	}
+	for _, grouping := range groupings {
+		if err := enforcer.AddGroupingPolicy(ctx, grouping.UserKey, grouping.RoleKey, grouping.TenantKey); err != nil {
+			return nil, fmt.Errorf("写入角色分配失败: %v", err)
+		}
+	}
+
+	return &Checker{enforcer: enforcer}, nil
+}
+
+// CheckOffline 离线检查用户权限（含角色继承），规则与在线 CheckPermission 一致
+func (c *Checker) CheckOffline(userKey, tenantKey string, permission core.Permission) (bool, error) {
+	return c.enforcer.CheckPermission(context.Background(), userKey, tenantKey, permission)
+}
+
+// Verify 按访问矩阵逐条校验，返回所有与期望不符的结果
+func (c *Checker) Verify(expectations []Expectation) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, expectation := range expectations {
+		actual, err := c.CheckOffline(expectation.UserKey, expectation.TenantKey, expectation.Permission)
+		if err != nil {
+			return nil, fmt.Errorf("检查 %s@%s %s 失败: %w", expectation.UserKey, expectation.TenantKey, expectation.Permission, err)
+		}
+		if actual != expectation.Allowed {
+			mismatches = append(mismatches, Mismatch{Expectation: expectation, Actual: actual})
+		}
+	}
+	return mismatches, nil
+}
+
+// LoadExpectationsFromFile 从 JSON 文件加载访问矩阵（Expectation 数组）
+func LoadExpectationsFromFile(path string) ([]Expectation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取访问矩阵失败: %v", err)
+	}
+
+	var expectations []Expectation
+	if err := json.Unmarshal(data, &expectations); err != nil {
+		return nil, fmt.Errorf("解析访问矩阵失败: %v", err)
+	}
+	return expectations, nil
+}
+
+// WritePolicies 以 Casbin 文件适配器格式写出策略快照
+func WritePolicies(w io.Writer, policies []core.Policy, groupings []core.GroupingPolicy) error {
+	writer := NewPolicyWriter(w)
+	for _, policy := range policies {
+		if err := writer.WritePolicy(policy); err != nil {
+			return err
+		}
+	}
+	for _, grouping := range groupings {
+		if err := writer.WriteGrouping(grouping); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// PolicyWriter 逐条写出 Casbin 文件适配器格式的策略快照，用于流式导出
+// 写入经过缓冲，结束时需要调用 Flush
+type PolicyWriter struct {
+	buf *bufio.Writer
+}
+
+// NewPolicyWriter 创建策略快照写出器
+func NewPolicyWriter(w io.Writer) *PolicyWriter {
+	return &PolicyWriter{buf: bufio.NewWriter(w)}
+}
+
+// WritePolicy 写出一条权限策略
+func (w *PolicyWriter) WritePolicy(policy core.Policy) error {
+	_, err := fmt.Fprintf(w.buf, "p, %s, %s, %s, %s\n", policy.Subject, policy.Domain, policy.Resource, policy.Action)
+	return err
+}
+
+// WriteGrouping 写出一条角色分配
+func (w *PolicyWriter) WriteGrouping(grouping core.GroupingPolicy) error {
+	_, err := fmt.Fprintf(w.buf, "g, %s, %s, %s\n", grouping.UserKey, grouping.RoleKey, grouping.TenantKey)
+	return err
+}
+
+// Flush 写出缓冲中的内容
+func (w *PolicyWriter) Flush() error {
+	return w.buf.Flush()
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/rezeropoint/casbinx/apitoken"
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/middleware"
+)
+
+// Authenticator 识别请求的操作者，无法识别时返回 core.ErrUnauthenticated，调用方不能调用该接口时返回 core.ErrPermissionDenied
+// 返回的操作者作为 operatorKey 传给引擎，请求体中不接受操作者参数；接口需要的作用域由 RequiredScope 读取
+type Authenticator func(r *http.Request) (operatorKey string, err error)
+
+// scopeKey 请求上下文中接口所需作用域的键
+type scopeKey struct{}
+
+// withRequiredScope 将接口需要的作用域写入请求上下文
+func withRequiredScope(r *http.Request, scope apitoken.Scope) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), scopeKey{}, scope))
+}
+
+// RequiredScope 读取处理器为当前接口声明的令牌作用域，供自定义 Authenticator 按作用域限制调用方
+func RequiredScope(r *http.Request) apitoken.Scope {
+	scope, _ := r.Context().Value(scopeKey{}).(apitoken.Scope)
+	return scope
+}
+
+// serverScopes 策略管理服务提供的方法分组
+var serverScopes = []apitoken.Scope{
+	apitoken.ScopePermissionChecker,
+	apitoken.ScopeUserPermissionRead,
+	apitoken.ScopeUserPermissionWrite,
+	apitoken.ScopeRoleRead,
+	apitoken.ScopeRoleWrite,
+	apitoken.ScopeAuditRead,
+}
+
+// TokenConfig API 令牌配置
+type TokenConfig struct {
+	Name        string           `json:"name"`        // 调用方名称，用于日志
+	Token       string           `json:"token"`       // 令牌明文，调用方通过 Authorization: Bearer <token> 携带
+	OperatorKey string           `json:"operatorKey"` // 令牌代表的操作者（引擎按其权限校验变更）
+	Scopes      []apitoken.Scope `json:"scopes"`      // 允许调用的方法分组，如只读令牌只配置 *.read
+}
+
+// validateTokens 校验令牌配置：令牌和操作者非空，令牌不重复，作用域必须是已知分组
+func validateTokens(tokens []TokenConfig) error {
+	seen := make(map[string]bool, len(tokens))
+	for i, token := range tokens {
+		if token.Token == "" {
+			return fmt.Errorf("tokens[%d] 令牌不能为空", i)
+		}
+		if token.OperatorKey == "" {
+			return fmt.Errorf("tokens[%d] 操作者不能为空", i)
+		}
+		if seen[token.Token] {
+			return fmt.Errorf("tokens[%d] 令牌重复", i)
+		}
+		seen[token.Token] = true
+
+		if err := apitoken.ValidateScopes(token.Scopes, serverScopes...); err != nil {
+			return fmt.Errorf("tokens[%d] %v", i, err)
+		}
+	}
+	return nil
+}
+
+// TokenAuthenticator 按 Authorization: Bearer <token> 识别操作者，令牌缺少接口所需的作用域时拒绝
+func TokenAuthenticator(tokens []TokenConfig) Authenticator {
+	credentials := make([]apitoken.Credential, 0, len(tokens))
+	for _, token := range tokens {
+		credentials = append(credentials, apitoken.Credential{Name: token.Name, Token: token.Token, Subject: token.OperatorKey, Scopes: token.Scopes})
+	}
+	set := apitoken.NewSet(credentials)
+
+	return func(r *http.Request) (string, error) {
+		token := set.Lookup(r)
+		if token == nil || token.Subject == "" {
+			return "", core.ErrUnauthenticated
+		}
+		if scope := RequiredScope(r); !token.Allows(scope) {
+			return "", fmt.Errorf("%w: 令牌 %s 缺少作用域: %s", core.ErrPermissionDenied, token.Name, scope)
+		}
+		return token.Subject, nil
+	}
+}
+
+// HeaderAuthenticator 从请求头读取操作者（只应在网关已认证并覆盖该请求头时使用）
+func HeaderAuthenticator(name string) Authenticator {
+	return fromExtractor(middleware.FromHeader(name))
+}
+
+// ContextAuthenticator 读取 middleware.WithUserKey 写入上下文的用户，用于将处理器挂在应用自己的认证中间件之后
+func ContextAuthenticator() Authenticator {
+	return fromExtractor(func(r *http.Request) string {
+		return middleware.UserKeyFromContext(r.Context())
+	})
+}
+
+// fromExtractor 将标识提取方式转换为 Authenticator
+func fromExtractor(extract middleware.Extractor) Authenticator {
+	return func(r *http.Request) (string, error) {
+		if operatorKey := extract(r); operatorKey != "" {
+			return operatorKey, nil
+		}
+		return "", core.ErrUnauthenticated
+	}
+}
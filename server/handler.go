@@ -0,0 +1,486 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rezeropoint/casbinx/apitoken"
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/engine"
+)
+
+// maxRequestBody 请求体大小上限
+const maxRequestBody = 1 << 20
+
+// CheckRequest 权限检查请求
+type CheckRequest struct {
+	UserKey    string          `json:"userKey"`    // 用户标识
+	TenantKey  string          `json:"tenantKey"`  // 租户标识
+	Permission core.Permission `json:"permission"` // 被检查的权限
+}
+
+// CheckResponse 权限检查结果
+type CheckResponse struct {
+	Allowed  bool   `json:"allowed"`  // 是否允许
+	Revision uint64 `json:"revision"` // 检查时的策略修订号
+}
+
+// BatchCheckRequest 批量权限检查请求
+type BatchCheckRequest struct {
+	UserKey     string            `json:"userKey"`     // 用户标识
+	TenantKey   string            `json:"tenantKey"`   // 租户标识
+	Permissions []core.Permission `json:"permissions"` // 被检查的权限列表
+}
+
+// BatchCheckResponse 批量权限检查结果，顺序与请求中的权限一致
+type BatchCheckResponse struct {
+	Results  []bool `json:"results"`
+	Revision uint64 `json:"revision"` // 检查时的策略修订号
+}
+
+// RoleRequest 创建或更新角色的请求
+type RoleRequest struct {
+	RoleKey     string            `json:"roleKey"`     // 角色标识（仅创建时使用，更新时取路径参数）
+	Name        string            `json:"name"`        // 角色名称
+	Description string            `json:"description"` // 角色描述
+	Permissions []core.Permission `json:"permissions"` // 角色权限
+}
+
+// PermissionsRequest 授予、撤销或设置权限的请求
+type PermissionsRequest struct {
+	Permissions []core.Permission `json:"permissions"`
+}
+
+// errorResponse 错误响应，参数校验错误附带参数名和约束
+type errorResponse struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Field      string `json:"field,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// NewHandler 创建策略管理服务的 HTTP 处理器，authenticate 为空时拒绝所有请求（健康检查除外）
+//
+//	POST   /v1/check                                           权限检查
+//	POST   /v1/check/batch                                     批量权限检查
+//	GET    /v1/tenants/{tenantKey}/roles                       租户内的角色列表
+//	POST   /v1/tenants/{tenantKey}/roles                       创建角色
+//	GET    /v1/roles/{roleKey}                                 角色详情
+//	PUT    /v1/roles/{roleKey}                                 更新角色
+//	DELETE /v1/roles/{roleKey}                                 删除角色
+//	PUT    /v1/roles/{roleKey}/permissions                     设置角色权限（覆盖）
+//	GET    /v1/tenants/{tenantKey}/users/{userKey}/permissions 用户有效权限
+//	POST   /v1/tenants/{tenantKey}/users/{userKey}/permissions 授予用户权限
+//	POST   /v1/tenants/{tenantKey}/users/{userKey}/permissions/revoke 撤销用户权限
+//	GET    /v1/tenants/{tenantKey}/users/{userKey}/roles       用户角色
+//	PUT    /v1/tenants/{tenantKey}/users/{userKey}/roles/{roleKey} 分配角色
+//	DELETE /v1/tenants/{tenantKey}/users/{userKey}/roles/{roleKey} 移除角色
+//	GET    /v1/audit                                           审计记录（参数 tenantKey、userKey、since、until、cursor、limit）
+//	GET    /healthz                                            健康检查
+//
+// 每个接口声明需要的令牌作用域（见 RequiredScope）：权限检查需要 PermissionChecker，用户权限和角色的查询、变更
+// 分别需要 UserPermissionAdmin.read、UserPermissionAdmin.write，角色的查询、变更分别需要 RoleAdministrator.read、
+// RoleAdministrator.write，审计记录需要 AuditLog.read
+func NewHandler(client engine.CasbinX, authenticate Authenticator) http.Handler {
+	if authenticate == nil {
+		authenticate = func(*http.Request) (string, error) { return "", core.ErrUnauthenticated }
+	}
+	h := &handler{client: client, authenticate: authenticate}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/check", h.operator(apitoken.ScopePermissionChecker, h.check))
+	mux.HandleFunc("POST /v1/check/batch", h.operator(apitoken.ScopePermissionChecker, h.checkBatch))
+	mux.HandleFunc("GET /v1/tenants/{tenantKey}/roles", h.operator(apitoken.ScopeRoleRead, h.listRoles))
+	mux.HandleFunc("POST /v1/tenants/{tenantKey}/roles", h.operator(apitoken.ScopeRoleWrite, h.createRole))
+	mux.HandleFunc("GET /v1/roles/{roleKey}", h.operator(apitoken.ScopeRoleRead, h.getRole))
+	mux.HandleFunc("PUT /v1/roles/{roleKey}", h.operator(apitoken.ScopeRoleWrite, h.updateRole))
+	mux.HandleFunc("DELETE /v1/roles/{roleKey}", h.operator(apitoken.ScopeRoleWrite, h.deleteRole))
+	mux.HandleFunc("PUT /v1/roles/{roleKey}/permissions", h.operator(apitoken.ScopeRoleWrite, h.setRolePermissions))
+	mux.HandleFunc("GET /v1/tenants/{tenantKey}/users/{userKey}/permissions", h.operator(apitoken.ScopeUserPermissionRead, h.userPermissions))
+	mux.HandleFunc("POST /v1/tenants/{tenantKey}/users/{userKey}/permissions", h.operator(apitoken.ScopeUserPermissionWrite, h.grantPermissions))
+	mux.HandleFunc("POST /v1/tenants/{tenantKey}/users/{userKey}/permissions/revoke", h.operator(apitoken.ScopeUserPermissionWrite, h.revokePermissions))
+	mux.HandleFunc("GET /v1/tenants/{tenantKey}/users/{userKey}/roles", h.operator(apitoken.ScopeUserPermissionRead, h.userRoles))
+	mux.HandleFunc("PUT /v1/tenants/{tenantKey}/users/{userKey}/roles/{roleKey}", h.operator(apitoken.ScopeUserPermissionWrite, h.assignRole))
+	mux.HandleFunc("DELETE /v1/tenants/{tenantKey}/users/{userKey}/roles/{roleKey}", h.operator(apitoken.ScopeUserPermissionWrite, h.removeRole))
+	mux.HandleFunc("GET /v1/audit", h.operator(apitoken.ScopeAuditRead, h.audit))
+	mux.HandleFunc("GET /healthz", h.healthz)
+	return mux
+}
+
+// handler 策略管理服务 HTTP 处理器实现
+type handler struct {
+	client       engine.CasbinX
+	authenticate Authenticator
+}
+
+// operatorHandler 已识别操作者的处理函数，client 绑定了请求上下文
+type operatorHandler func(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string)
+
+// operator 声明接口需要的令牌作用域，识别操作者后调用处理函数
+func (h *handler) operator(scope apitoken.Scope, next operatorHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = withRequiredScope(r, scope)
+		operatorKey, err := h.authenticate(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		next(w, r, h.client.WithContext(r.Context()), operatorKey)
+	}
+}
+
+// check 权限检查，查询权限由引擎按 SelfQuery 配置校验（检查他人的权限需要租户内的 user:read 权限）
+func (h *handler) check(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req CheckRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := core.FirstError(
+		core.RequireArg("userKey", req.UserKey),
+		core.RequireArg("tenantKey", req.TenantKey),
+		core.RequirePermissionArg("permission", req.Permission),
+	); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := client.AuthorizeUserQuery(operatorKey, req.UserKey, req.TenantKey); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := client.CheckPermissionVersioned(req.UserKey, req.TenantKey, req.Permission)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, CheckResponse{Allowed: result.Allowed, Revision: result.Revision})
+}
+
+// checkBatch 批量权限检查
+func (h *handler) checkBatch(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req BatchCheckRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := core.FirstError(
+		core.RequireArg("userKey", req.UserKey),
+		core.RequireArg("tenantKey", req.TenantKey),
+		core.RequireItems("permissions", req.Permissions),
+	); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := client.AuthorizeUserQuery(operatorKey, req.UserKey, req.TenantKey); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	revision := client.GetPolicyRevision()
+	results, err := client.CheckMultiplePermissions(req.UserKey, req.TenantKey, req.Permissions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, BatchCheckResponse{Results: results, Revision: revision})
+}
+
+// listRoles 租户内的角色列表，需要租户内的 role:read 权限
+func (h *handler) listRoles(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	tenantKey := r.PathValue("tenantKey")
+	if err := authorize(client, operatorKey, tenantKey, core.Permission{Resource: core.ResourceRole, Action: core.ActionRead}); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	roles, err := client.ListRoles(tenantKey, nil)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(roles))
+}
+
+// createRole 创建角色，需要租户内的 role:write 权限
+func (h *handler) createRole(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req RoleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	tenantKey := r.PathValue("tenantKey")
+	if err := authorize(client, operatorKey, tenantKey, core.Permission{Resource: core.ResourceRole, Action: core.ActionWrite}); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := client.CreateRole(operatorKey, req.RoleKey, req.Name, req.Description, tenantKey, req.Permissions); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.writeRole(w, client, req.RoleKey, http.StatusCreated)
+}
+
+// getRole 角色详情，需要角色所属租户的 role:read 权限
+func (h *handler) getRole(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	role, err := authorizeRole(client, operatorKey, r.PathValue("roleKey"), core.ActionRead)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, role)
+}
+
+// updateRole 更新角色名称、描述和权限，需要角色所属租户的 role:write 权限
+func (h *handler) updateRole(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req RoleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	// 角色归属租户不可修改，沿用现有值；授予的权限再由引擎按操作者权限校验
+	roleKey := r.PathValue("roleKey")
+	role, err := authorizeRole(client, operatorKey, roleKey, core.ActionWrite)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := client.UpdateRole(operatorKey, roleKey, req.Name, req.Description, role.TenantKey, req.Permissions); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.writeRole(w, client, roleKey, http.StatusOK)
+}
+
+// deleteRole 删除角色，需要角色所属租户的 role:delete 权限
+func (h *handler) deleteRole(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	roleKey := r.PathValue("roleKey")
+	if _, err := authorizeRole(client, operatorKey, roleKey, core.ActionDelete); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := client.DeleteRole(roleKey); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setRolePermissions 设置角色权限（覆盖），需要角色所属租户的 role:write 权限
+func (h *handler) setRolePermissions(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req PermissionsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	roleKey := r.PathValue("roleKey")
+	if _, err := authorizeRole(client, operatorKey, roleKey, core.ActionWrite); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := client.SetRolePermissions(operatorKey, roleKey, req.Permissions); err != nil {
+		writeError(w, err)
+		return
+	}
+	h.writeRole(w, client, roleKey, http.StatusOK)
+}
+
+// userPermissions 用户在租户内的有效权限
+func (h *handler) userPermissions(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	permissions, err := client.GetEffectivePermissionsSecure(operatorKey, r.PathValue("userKey"), r.PathValue("tenantKey"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(permissions))
+}
+
+// grantPermissions 授予用户权限（单个事务）
+func (h *handler) grantPermissions(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req PermissionsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := client.GrantPermissions(operatorKey, r.PathValue("userKey"), r.PathValue("tenantKey"), req.Permissions); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokePermissions 撤销用户权限（单个事务）
+func (h *handler) revokePermissions(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	var req PermissionsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := client.RevokePermissions(operatorKey, r.PathValue("userKey"), r.PathValue("tenantKey"), req.Permissions); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userRoles 用户在租户内的角色
+func (h *handler) userRoles(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	roles, err := client.GetUserRolesSecure(operatorKey, r.PathValue("userKey"), r.PathValue("tenantKey"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(roles))
+}
+
+// assignRole 为用户分配角色
+func (h *handler) assignRole(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	if err := client.AssignRole(operatorKey, r.PathValue("userKey"), r.PathValue("roleKey"), r.PathValue("tenantKey")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeRole 移除用户角色
+func (h *handler) removeRole(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	if err := client.RemoveRole(operatorKey, r.PathValue("userKey"), r.PathValue("roleKey"), r.PathValue("tenantKey")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// audit 分页查询审计记录，时间参数为 RFC 3339
+func (h *handler) audit(w http.ResponseWriter, r *http.Request, client engine.CasbinX, operatorKey string) {
+	query := r.URL.Query()
+	filter := core.AuditFilter{
+		TenantKey: query.Get("tenantKey"),
+		UserKey:   query.Get("userKey"),
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, &core.FieldError{Field: name, Constraint: core.ConstraintFormat, Detail: "应为 RFC3339 时间"})
+			return
+		}
+		*target = parsed
+	}
+	page := core.PageRequest{Cursor: core.Cursor(query.Get("cursor"))}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, core.NewFieldError("limit", core.ConstraintFormat))
+			return
+		}
+		page.Limit = limit
+	}
+
+	result, err := client.ListAuditEvents(operatorKey, filter, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// healthz 健康检查（不需要认证）
+func (h *handler) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// writeRole 变更成功后返回角色的最新状态
+func (h *handler) writeRole(w http.ResponseWriter, client engine.CasbinX, roleKey string, status int) {
+	role, err := client.GetRole(roleKey)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, status, role)
+}
+
+// authorize 校验操作者在租户内拥有指定权限，用于引擎方法没有操作者参数的接口
+func authorize(client engine.CasbinX, operatorKey, tenantKey string, permission core.Permission) error {
+	if err := core.RequireArg("tenantKey", tenantKey); err != nil {
+		return err
+	}
+	allowed, err := client.CheckPermission(operatorKey, tenantKey, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return core.ErrPermissionDenied
+	}
+	return nil
+}
+
+// authorizeRole 读取角色并校验操作者在角色所属租户（全局角色为全局域）内拥有 role 资源的指定操作
+func authorizeRole(client engine.CasbinX, operatorKey, roleKey string, action core.Action) (*core.Role, error) {
+	if err := core.RequireArg("roleKey", roleKey); err != nil {
+		return nil, err
+	}
+	role, err := client.GetRole(roleKey)
+	if err != nil {
+		return nil, err
+	}
+	tenantKey := role.TenantKey
+	if tenantKey == "" {
+		tenantKey = "*"
+	}
+	if err := authorize(client, operatorKey, tenantKey, core.Permission{Resource: core.ResourceRole, Action: action}); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// decodeRequest 解析 JSON 请求体，失败时写入错误响应并返回 false
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+// writeError 将错误转换为响应：未认证返回 401，无权限返回 403，角色不存在返回 404，参数错误返回 400，
+// 其余 core.Error 返回 422，未知错误返回 500
+func writeError(w http.ResponseWriter, err error) {
+	var coreErr core.Error
+	var fieldErr *core.FieldError
+	switch {
+	case errors.As(err, &fieldErr):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: err.Error(), Field: fieldErr.Field, Constraint: fieldErr.Constraint})
+	case errors.Is(err, core.ErrUnauthenticated):
+		w.Header().Set("WWW-Authenticate", `Bearer realm="casbinx-server"`)
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Code: core.ErrUnauthenticated.Code, Message: core.ErrUnauthenticated.Message})
+	case errors.Is(err, core.ErrPermissionDenied):
+		writeJSON(w, http.StatusForbidden, errorResponse{Code: core.ErrPermissionDenied.Code, Message: err.Error()})
+	case errors.Is(err, core.ErrRoleNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse{Code: core.ErrRoleNotFound.Code, Message: err.Error()})
+	case errors.Is(err, core.ErrInvalidParameter), errors.Is(err, core.ErrInvalidCursor):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: err.Error()})
+	case errors.Is(err, core.ErrInternal):
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Code: core.ErrInternal.Code, Message: err.Error()})
+	case errors.As(err, &coreErr):
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Code: coreErr.Code, Message: err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Code: core.ErrInternal.Code, Message: err.Error()})
+	}
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// nonNil 空列表序列化为 [] 而不是 null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
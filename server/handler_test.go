@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rezeropoint/casbinx/apitoken"
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/engine"
+)
+
+// fakeClient 按 "操作者|租户|权限" 返回检查结果的引擎，只实现处理器用到的方法
+type fakeClient struct {
+	engine.CasbinX
+	granted map[string]bool
+	roles   map[string]*core.Role
+	writes  []string // 调用过的变更方法
+	query   func(operatorKey, userKey, tenantKey string) error
+}
+
+func (c *fakeClient) WithContext(context.Context) engine.CasbinX { return c }
+
+func (c *fakeClient) CheckPermission(userKey, tenantKey string, permission core.Permission) (bool, error) {
+	return c.granted[userKey+"|"+tenantKey+"|"+permission.String()], nil
+}
+
+func (c *fakeClient) CheckPermissionVersioned(userKey, tenantKey string, permission core.Permission) (*core.CheckResult, error) {
+	allowed, err := c.CheckPermission(userKey, tenantKey, permission)
+	return &core.CheckResult{Allowed: allowed}, err
+}
+
+func (c *fakeClient) AuthorizeUserQuery(operatorKey, userKey, tenantKey string) error {
+	return c.query(operatorKey, userKey, tenantKey)
+}
+
+func (c *fakeClient) GetRole(roleKey string) (*core.Role, error) {
+	role, ok := c.roles[roleKey]
+	if !ok {
+		return nil, core.ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (c *fakeClient) CreateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) error {
+	c.writes = append(c.writes, "CreateRole")
+	c.roles[roleKey] = &core.Role{Key: roleKey, Name: roleName, TenantKey: tenantKey, Permissions: permissions}
+	return nil
+}
+
+func (c *fakeClient) UpdateRole(operatorKey, roleKey, roleName, description, tenantKey string, permissions []core.Permission, opts ...core.MutationOption) error {
+	c.writes = append(c.writes, "UpdateRole")
+	return nil
+}
+
+func (c *fakeClient) SetRolePermissions(operatorKey, roleKey string, permissions []core.Permission, opts ...core.MutationOption) error {
+	c.writes = append(c.writes, "SetRolePermissions")
+	return nil
+}
+
+// serve 以 operatorKey 身份发送请求
+func serve(client engine.CasbinX, operatorKey, method, path, body string) *httptest.ResponseRecorder {
+	authenticate := func(*http.Request) (string, error) { return operatorKey, nil }
+	recorder := httptest.NewRecorder()
+	NewHandler(client, authenticate).ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+func TestRoleWritesRequireRoleWrite(t *testing.T) {
+	roleWrite := core.Permission{Resource: core.ResourceRole, Action: core.ActionWrite}.String()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		granted    map[string]bool
+		wantStatus int
+		wantWrites int
+	}{
+		{name: "创建角色缺少 role:write", method: http.MethodPost, path: "/v1/tenants/t1/roles", body: `{"roleKey":"editor","name":"编辑"}`, wantStatus: http.StatusForbidden},
+		{name: "更新角色缺少 role:write", method: http.MethodPut, path: "/v1/roles/viewer", body: `{"name":"查看"}`, wantStatus: http.StatusForbidden},
+		{name: "设置角色权限缺少 role:write", method: http.MethodPut, path: "/v1/roles/viewer/permissions", body: `{"permissions":[]}`, wantStatus: http.StatusForbidden},
+		{name: "其他租户的 role:write 不能更新角色", method: http.MethodPut, path: "/v1/roles/viewer", body: `{"name":"查看"}`, granted: map[string]bool{"op|t2|" + roleWrite: true}, wantStatus: http.StatusForbidden},
+		{name: "创建角色", method: http.MethodPost, path: "/v1/tenants/t1/roles", body: `{"roleKey":"editor","name":"编辑"}`, granted: map[string]bool{"op|t1|" + roleWrite: true}, wantStatus: http.StatusCreated, wantWrites: 1},
+		{name: "更新角色", method: http.MethodPut, path: "/v1/roles/viewer", body: `{"name":"查看"}`, granted: map[string]bool{"op|t1|" + roleWrite: true}, wantStatus: http.StatusOK, wantWrites: 1},
+		{name: "设置角色权限", method: http.MethodPut, path: "/v1/roles/viewer/permissions", body: `{"permissions":[]}`, granted: map[string]bool{"op|t1|" + roleWrite: true}, wantStatus: http.StatusOK, wantWrites: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{
+				granted: tt.granted,
+				roles:   map[string]*core.Role{"viewer": {Key: "viewer", TenantKey: "t1"}},
+			}
+
+			recorder := serve(client, "op", tt.method, tt.path, tt.body)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if len(client.writes) != tt.wantWrites {
+				t.Fatalf("writes = %v, want %d", client.writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestCheckUsesEngineQueryAuthorization(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{name: "权限检查", path: "/v1/check", body: `{"userKey":"alice","tenantKey":"t1","permission":{"resource":"user","action":"read"}}`},
+		{name: "批量权限检查", path: "/v1/check/batch", body: `{"userKey":"alice","tenantKey":"t1","permissions":[{"resource":"user","action":"read"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 引擎不允许自查（如 SelfQuery 关闭了自查）时，查询自己也被拒绝
+			var got []string
+			client := &fakeClient{query: func(operatorKey, userKey, tenantKey string) error {
+				got = []string{operatorKey, userKey, tenantKey}
+				return core.ErrPermissionDenied
+			}}
+
+			recorder := serve(client, "alice", http.MethodPost, tt.path, tt.body)
+			if recorder.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d, body = %s", recorder.Code, http.StatusForbidden, recorder.Body)
+			}
+			if strings.Join(got, "|") != "alice|alice|t1" {
+				t.Fatalf("AuthorizeUserQuery args = %v, want [alice alice t1]", got)
+			}
+		})
+	}
+}
+
+func TestTokenScopes(t *testing.T) {
+	tokens := []TokenConfig{
+		{Name: "reader", Token: "read-token", OperatorKey: "op", Scopes: []apitoken.Scope{apitoken.ScopeRoleRead, apitoken.ScopeUserPermissionRead}},
+		{Name: "admin", Token: "admin-token", OperatorKey: "op", Scopes: []apitoken.Scope{apitoken.ScopeAll}},
+	}
+	granted := map[string]bool{}
+	for _, action := range []core.Action{core.ActionRead, core.ActionWrite, core.ActionDelete} {
+		granted["op|t1|"+core.Permission{Resource: core.ResourceRole, Action: action}.String()] = true
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "只读令牌查询角色", token: "read-token", method: http.MethodGet, path: "/v1/roles/viewer", wantStatus: http.StatusOK},
+		{name: "只读令牌不能创建角色", token: "read-token", method: http.MethodPost, path: "/v1/tenants/t1/roles", body: `{"roleKey":"editor"}`, wantStatus: http.StatusForbidden},
+		{name: "只读令牌不能更新角色", token: "read-token", method: http.MethodPut, path: "/v1/roles/viewer", body: `{"name":"查看"}`, wantStatus: http.StatusForbidden},
+		{name: "只读令牌不能删除角色", token: "read-token", method: http.MethodDelete, path: "/v1/roles/viewer", wantStatus: http.StatusForbidden},
+		{name: "只读令牌不能授权", token: "read-token", method: http.MethodPost, path: "/v1/tenants/t1/users/alice/permissions", body: `{"permissions":[]}`, wantStatus: http.StatusForbidden},
+		{name: "只读令牌不能分配角色", token: "read-token", method: http.MethodPut, path: "/v1/tenants/t1/users/alice/roles/viewer", wantStatus: http.StatusForbidden},
+		{name: "只读令牌不能检查权限", token: "read-token", method: http.MethodPost, path: "/v1/check", body: `{}`, wantStatus: http.StatusForbidden},
+		{name: "全部作用域的令牌更新角色", token: "admin-token", method: http.MethodPut, path: "/v1/roles/viewer", body: `{"name":"查看"}`, wantStatus: http.StatusOK},
+		{name: "未知令牌", token: "unknown", method: http.MethodGet, path: "/v1/roles/viewer", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{
+				granted: granted,
+				roles:   map[string]*core.Role{"viewer": {Key: "viewer", TenantKey: "t1"}},
+			}
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			request.Header.Set("Authorization", "Bearer "+tt.token)
+			recorder := httptest.NewRecorder()
+			NewHandler(client, TokenAuthenticator(tokens)).ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantStatus != http.StatusOK && len(client.writes) != 0 {
+				t.Fatalf("writes = %v, want none", client.writes)
+			}
+		})
+	}
+}
+
+func TestValidateTokenScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []apitoken.Scope
+		wantErr bool
+	}{
+		{name: "已知作用域", scopes: []apitoken.Scope{apitoken.ScopeRoleRead, apitoken.ScopeAuditRead}},
+		{name: "全部作用域", scopes: []apitoken.Scope{apitoken.ScopeAll}},
+		{name: "缺少作用域", scopes: nil, wantErr: true},
+		{name: "服务不提供的作用域", scopes: []apitoken.Scope{apitoken.ScopePolicyExport}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTokens([]TokenConfig{{Token: "token", OperatorKey: "op", Scopes: tt.scopes}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
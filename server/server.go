@@ -0,0 +1,77 @@
+// Package server 将 CasbinX 引擎以独立的策略管理服务部署
+//
+// 通过 REST/JSON 接口提供角色管理、授权与撤销、角色分配、权限检查和审计查询，
+// 使非 Go 服务和管理后台无需链接 Go 库即可管理策略。
+// 每个请求先由 Authenticator 识别操作者并校验令牌作用域，变更接口以操作者身份调用引擎，沿用引擎的提权校验；
+// 角色的查询、创建、修改和删除还由服务按操作者在角色所属租户的 role 权限校验。
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// DefaultListenAddr 默认监听地址
+const DefaultListenAddr = "127.0.0.1:8282"
+
+// DefaultShutdownTimeout 默认优雅退出等待时长
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Config 策略管理服务配置文件
+type Config struct {
+	// Listen 监听地址，默认 127.0.0.1:8282
+	Listen string `json:"listen"`
+
+	// Tokens API 令牌，每个令牌对应一个操作者，只能调用作用域内的接口；为空时使用 TrustedHeader 识别操作者
+	Tokens []TokenConfig `json:"tokens"`
+
+	// TrustedHeader 从请求头读取操作者（如网关认证后写入的 X-User-Key），仅在网关覆盖该请求头时使用
+	TrustedHeader string `json:"trustedHeader"`
+
+	// ShutdownTimeout 收到退出信号后等待进行中请求完成的时长，默认 10s
+	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
+
+	// Casbinx 引擎配置
+	Casbinx core.Config `json:"casbinx"`
+}
+
+// LoadConfig 从 JSON 文件加载配置并填充默认值
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
+
+	if err := validateTokens(config.Tokens); err != nil {
+		return nil, fmt.Errorf("令牌配置无效: %v", err)
+	}
+	if len(config.Tokens) == 0 && config.TrustedHeader == "" {
+		return nil, fmt.Errorf("tokens 和 trustedHeader 至少需要配置一个")
+	}
+
+	if config.Listen == "" {
+		config.Listen = DefaultListenAddr
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &config, nil
+}
+
+// Authenticator 按配置创建操作者识别方式，配置了令牌时优先使用令牌
+func (c *Config) Authenticator() Authenticator {
+	if len(c.Tokens) > 0 {
+		return TokenAuthenticator(c.Tokens)
+	}
+	return HeaderAuthenticator(c.TrustedHeader)
+}
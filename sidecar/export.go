@@ -0,0 +1,139 @@
+package sidecar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// exportFlushLines 流式导出时每写出多少行刷新一次响应
+const exportFlushLines = 500
+
+// 导出记录类型
+const (
+	ExportKindPolicy   = "policy"   // 权限策略
+	ExportKindGrouping = "grouping" // 角色分配或角色继承
+	ExportKindAudit    = "audit"    // 审计记录
+	ExportKindError    = "error"    // 导出中途失败，之后不再有记录
+)
+
+// ExportRecord 流式导出响应中的一行（NDJSON），按 Kind 填写对应字段
+type ExportRecord struct {
+	Kind     string                 `json:"kind"`               // 记录类型
+	Policy   *core.Policy           `json:"policy,omitempty"`   // 权限策略
+	Grouping *core.GroupingPolicy   `json:"grouping,omitempty"` // 角色分配或角色继承
+	Change   *core.PermissionChange `json:"change,omitempty"`   // 审计记录
+	Code     string                 `json:"code,omitempty"`     // 错误码
+	Message  string                 `json:"message,omitempty"`  // 错误信息
+}
+
+// exportPolicies 流式导出租户的权限策略和角色分配（参数 tenantKey，为空时导出所有租户）
+func (h *handler) exportPolicies(w http.ResponseWriter, r *http.Request) {
+	tenantKey := r.URL.Query().Get("tenantKey")
+	client := h.client.WithContext(r.Context())
+
+	stream := newExportStream(w, r)
+	err := client.StreamPolicies(tenantKey, func(policy core.Policy) error {
+		return stream.write(ExportRecord{Kind: ExportKindPolicy, Policy: &policy})
+	})
+	if err == nil {
+		err = client.StreamGroupingPolicies(tenantKey, func(grouping core.GroupingPolicy) error {
+			return stream.write(ExportRecord{Kind: ExportKindGrouping, Grouping: &grouping})
+		})
+	}
+	stream.close(err)
+}
+
+// exportAudit 流式导出审计记录
+// 参数 operatorKey（必填，按其权限校验）、tenantKey、userKey、since、until（RFC 3339）
+func (h *handler) exportAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	operatorKey := query.Get("operatorKey")
+	filter := core.AuditFilter{
+		TenantKey: query.Get("tenantKey"),
+		UserKey:   query.Get("userKey"),
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, &core.FieldError{Field: name, Constraint: core.ConstraintFormat, Detail: "应为 RFC3339 时间"})
+			return
+		}
+		*target = parsed
+	}
+	if err := core.RequireArg("operatorKey", operatorKey); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	stream := newExportStream(w, r)
+	err := h.client.WithContext(r.Context()).StreamAuditEvents(operatorKey, filter, func(change core.PermissionChange) error {
+		return stream.write(ExportRecord{Kind: ExportKindAudit, Change: &change})
+	})
+	stream.close(err)
+}
+
+// exportStream NDJSON 流式响应，第一条记录写出前发生的错误按普通错误响应返回
+// 写出阻塞（客户端读取较慢）时导出随之暂停；客户端断开后写出失败，导出停止
+type exportStream struct {
+	w          http.ResponseWriter
+	r          *http.Request
+	encoder    *json.Encoder
+	controller *http.ResponseController
+	lines      int
+}
+
+// newExportStream 创建流式响应
+func newExportStream(w http.ResponseWriter, r *http.Request) *exportStream {
+	return &exportStream{w: w, r: r, encoder: json.NewEncoder(w), controller: http.NewResponseController(w)}
+}
+
+// write 写出一行记录，每 exportFlushLines 行刷新一次
+func (s *exportStream) write(record ExportRecord) error {
+	if err := s.r.Context().Err(); err != nil {
+		return err
+	}
+	if s.lines == 0 {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+	}
+	if err := s.encoder.Encode(record); err != nil {
+		return err
+	}
+	s.lines++
+	if s.lines%exportFlushLines == 0 {
+		if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+// close 结束流式响应，中途失败时追加一条错误记录
+func (s *exportStream) close(err error) {
+	if err != nil && s.lines == 0 {
+		writeError(s.w, err)
+		return
+	}
+	if s.lines == 0 {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+		return
+	}
+	if err != nil && s.r.Context().Err() == nil {
+		record := ExportRecord{Kind: ExportKindError, Code: core.ErrInternal.Code, Message: err.Error()}
+		var coreErr core.Error
+		if errors.As(err, &coreErr) {
+			record.Code = coreErr.Code
+		}
+		_ = s.encoder.Encode(record)
+	}
+	_ = s.controller.Flush()
+}
@@ -0,0 +1,211 @@
+package sidecar
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rezeropoint/casbinx/core"
+	"github.com/rezeropoint/casbinx/engine"
+)
+
+// maxRequestBody 请求体大小上限
+const maxRequestBody = 1 << 20
+
+// CheckRequest 单个权限检查请求
+type CheckRequest struct {
+	UserKey    string          `json:"userKey"`    // 用户标识
+	TenantKey  string          `json:"tenantKey"`  // 租户标识
+	Permission core.Permission `json:"permission"` // 被检查的权限
+}
+
+// CheckResponse 单个权限检查结果
+type CheckResponse struct {
+	Allowed  bool   `json:"allowed"`  // 是否允许
+	Revision uint64 `json:"revision"` // 检查时 sidecar 的策略修订号
+}
+
+// BatchCheckRequest 批量权限检查请求
+type BatchCheckRequest struct {
+	UserKey     string            `json:"userKey"`     // 用户标识
+	TenantKey   string            `json:"tenantKey"`   // 租户标识
+	Permissions []core.Permission `json:"permissions"` // 被检查的权限列表
+}
+
+// BatchCheckResponse 批量权限检查结果，顺序与请求中的权限一致
+type BatchCheckResponse struct {
+	Results  []bool `json:"results"`
+	Revision uint64 `json:"revision"` // 检查时 sidecar 的策略修订号
+}
+
+// Manifest 用户在租户内的权限清单（供前端或其他语言的应用一次性获取后本地判断）
+type Manifest struct {
+	UserKey     string            `json:"userKey"`     // 用户标识
+	TenantKey   string            `json:"tenantKey"`   // 租户标识
+	Roles       []string          `json:"roles"`       // 用户在租户内的角色
+	Permissions []core.Permission `json:"permissions"` // 用户在租户内的有效权限（含角色继承）
+	Revision    uint64            `json:"revision"`    // 生成清单时 sidecar 的策略修订号，变化后应重新获取
+	Stale       bool              `json:"stale"`       // 实时查询失败时返回最近一次的清单，此时为 true
+}
+
+// errorResponse 错误响应，参数校验错误附带参数名和约束
+type errorResponse struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Field      string `json:"field,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// NewHandler 创建 sidecar 的 HTTP 处理器
+//
+//	POST /v1/check        单个权限检查
+//	POST /v1/check/batch  批量权限检查
+//	GET  /v1/manifest     权限清单（参数 userKey、tenantKey）
+//	GET  /v1/export/policies  流式导出权限策略和角色分配（NDJSON，参数 tenantKey）
+//	GET  /v1/export/audit     流式导出审计记录（NDJSON，参数 operatorKey、tenantKey、userKey、since、until）
+//	GET  /healthz         健康检查
+//
+// 配置了 tokens 时，除健康检查外的接口都要求携带具有对应作用域的 API 令牌
+func NewHandler(client engine.CasbinX, tokens []TokenConfig) http.Handler {
+	h := &handler{client: client}
+	auth := newTokenAuth(tokens)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/check", auth.require(ScopePermissionChecker, h.check))
+	mux.HandleFunc("POST /v1/check/batch", auth.require(ScopePermissionChecker, h.checkBatch))
+	mux.HandleFunc("GET /v1/manifest", auth.require(ScopeUserPermissionRead, h.manifest))
+	mux.HandleFunc("GET /v1/export/policies", auth.require(ScopePolicyExport, h.exportPolicies))
+	mux.HandleFunc("GET /v1/export/audit", auth.require(ScopeAuditRead, h.exportAudit))
+	mux.HandleFunc("GET /healthz", h.healthz)
+	return mux
+}
+
+// handler sidecar HTTP 处理器实现
+type handler struct {
+	client engine.CasbinX
+}
+
+// check 单个权限检查
+func (h *handler) check(w http.ResponseWriter, r *http.Request) {
+	var req CheckRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := core.FirstError(
+		core.RequireArg("userKey", req.UserKey),
+		core.RequireArg("tenantKey", req.TenantKey),
+		core.RequirePermissionArg("permission", req.Permission),
+	); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := h.client.CheckPermissionVersioned(req.UserKey, req.TenantKey, req.Permission)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CheckResponse{Allowed: result.Allowed, Revision: result.Revision})
+}
+
+// checkBatch 批量权限检查
+func (h *handler) checkBatch(w http.ResponseWriter, r *http.Request) {
+	var req BatchCheckRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := core.FirstError(
+		core.RequireArg("userKey", req.UserKey),
+		core.RequireArg("tenantKey", req.TenantKey),
+		core.RequireItems("permissions", req.Permissions),
+	); err != nil {
+		writeError(w, err)
+		return
+	}
+	for _, permission := range req.Permissions {
+		if err := core.RequirePermissionArg("permissions", permission); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	revision := h.client.GetPolicyRevision()
+	results, err := h.client.CheckMultiplePermissions(req.UserKey, req.TenantKey, req.Permissions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, BatchCheckResponse{Results: results, Revision: revision})
+}
+
+// manifest 获取用户在租户内的角色和有效权限
+func (h *handler) manifest(w http.ResponseWriter, r *http.Request) {
+	userKey := r.URL.Query().Get("userKey")
+	tenantKey := r.URL.Query().Get("tenantKey")
+	if err := core.FirstError(
+		core.RequireArg("userKey", userKey),
+		core.RequireArg("tenantKey", tenantKey),
+	); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// 以用户自身作为操作者查询，与用户查询自己的权限语义一致；存储不可用时返回最近一次的清单并标记为过期
+	manifest, err := h.client.GetUserPermissionManifestCached(userKey, userKey, tenantKey)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Manifest{
+		UserKey:     manifest.UserKey,
+		TenantKey:   manifest.TenantKey,
+		Roles:       manifest.Roles,
+		Permissions: manifest.Permissions,
+		Revision:    manifest.Revision,
+		Stale:       manifest.Stale,
+	})
+}
+
+// healthz 健康检查
+func (h *handler) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// decodeRequest 解析 JSON 请求体，失败时写入错误响应并返回 false
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+// writeError 将错误转换为响应，参数错误返回 400，其余 core.Error 返回 422，未知错误返回 500
+func writeError(w http.ResponseWriter, err error) {
+	var coreErr core.Error
+	var fieldErr *core.FieldError
+	switch {
+	case errors.As(err, &fieldErr):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: err.Error(), Field: fieldErr.Field, Constraint: fieldErr.Constraint})
+	case errors.Is(err, core.ErrInvalidParameter), errors.Is(err, core.ErrInvalidCursor):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Code: core.ErrInvalidParameter.Code, Message: err.Error()})
+	case errors.Is(err, core.ErrInternal):
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Code: core.ErrInternal.Code, Message: err.Error()})
+	case errors.As(err, &coreErr):
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Code: coreErr.Code, Message: err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Code: core.ErrInternal.Code, Message: err.Error()})
+	}
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,63 @@
+// Package sidecar 将 CasbinX 引擎以 sidecar 形式部署
+//
+// 与业务进程运行在同一 Pod 中，通过本地回环地址上的 HTTP 接口提供权限检查和权限清单查询，
+// 使非 Go 应用也能直接使用 casbinx 的检查语义，而无需经过中心化的权限服务。
+// sidecar 只暴露只读接口，权限管理仍由 Go 服务通过 engine 包完成。
+package sidecar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// DefaultListenAddr 默认监听地址，只接受本机连接
+const DefaultListenAddr = "127.0.0.1:8181"
+
+// DefaultShutdownTimeout 默认优雅退出等待时长
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Config sidecar 配置文件
+type Config struct {
+	// Listen 监听地址，默认 127.0.0.1:8181
+	// 未配置 Tokens 时接口不做调用方鉴权，应只监听回环地址或 Pod 内部地址
+	Listen string `json:"listen"`
+
+	// Tokens API 令牌，配置后调用方必须携带令牌，且只能调用令牌作用域内的接口
+	Tokens []TokenConfig `json:"tokens"`
+
+	// ShutdownTimeout 收到退出信号后等待进行中请求完成的时长，默认 10s
+	ShutdownTimeout time.Duration `json:"shutdownTimeout"`
+
+	// Casbinx 引擎配置
+	Casbinx core.Config `json:"casbinx"`
+}
+
+// LoadConfig 从 JSON 文件加载配置并填充默认值
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
+
+	if err := validateTokens(config.Tokens); err != nil {
+		return nil, fmt.Errorf("令牌配置无效: %v", err)
+	}
+
+	if config.Listen == "" {
+		config.Listen = DefaultListenAddr
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return &config, nil
+}
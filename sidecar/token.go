@@ -0,0 +1,92 @@
+package sidecar
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/rezeropoint/casbinx/apitoken"
+	"github.com/rezeropoint/casbinx/core"
+)
+
+// Scope API 令牌的作用域，对应 engine 包中的方法分组（与策略管理服务共用 apitoken 的定义）
+type Scope = apitoken.Scope
+
+const (
+	// ScopePermissionChecker 权限检查（engine.PermissionChecker），允许调用 /v1/check 和 /v1/check/batch
+	ScopePermissionChecker = apitoken.ScopePermissionChecker
+	// ScopeUserPermissionRead 用户权限查询（engine.UserPermissionAdmin 的只读方法），允许调用 /v1/manifest
+	ScopeUserPermissionRead = apitoken.ScopeUserPermissionRead
+	// ScopePolicyExport 策略导出（engine.CasbinX 的流式导出方法），允许调用 /v1/export/policies
+	ScopePolicyExport = apitoken.ScopePolicyExport
+	// ScopeAuditRead 审计日志查询（engine.CasbinX 的审计方法），允许调用 /v1/export/audit
+	ScopeAuditRead = apitoken.ScopeAuditRead
+	// ScopeAll 全部方法分组
+	ScopeAll = apitoken.ScopeAll
+)
+
+// sidecarScopes sidecar 提供的方法分组
+var sidecarScopes = []Scope{ScopePermissionChecker, ScopeUserPermissionRead, ScopePolicyExport, ScopeAuditRead}
+
+// TokenConfig API 令牌配置
+type TokenConfig struct {
+	Name   string  `json:"name"`   // 调用方名称，用于错误信息
+	Token  string  `json:"token"`  // 令牌明文，调用方通过 Authorization: Bearer <token> 携带
+	Scopes []Scope `json:"scopes"` // 允许调用的方法分组
+}
+
+// validateTokens 校验令牌配置：令牌非空且不重复，作用域必须是已知分组
+func validateTokens(tokens []TokenConfig) error {
+	seen := make(map[string]bool, len(tokens))
+	for i, token := range tokens {
+		if token.Token == "" {
+			return fmt.Errorf("tokens[%d] 令牌不能为空", i)
+		}
+		if seen[token.Token] {
+			return fmt.Errorf("tokens[%d] 令牌重复", i)
+		}
+		seen[token.Token] = true
+
+		if err := apitoken.ValidateScopes(token.Scopes, sidecarScopes...); err != nil {
+			return fmt.Errorf("tokens[%d] %v", i, err)
+		}
+	}
+	return nil
+}
+
+// tokenAuth 令牌鉴权，未配置令牌时不做鉴权（仅适用于回环地址）
+type tokenAuth struct {
+	tokens *apitoken.Set
+}
+
+// newTokenAuth 根据配置创建令牌鉴权
+func newTokenAuth(tokens []TokenConfig) *tokenAuth {
+	credentials := make([]apitoken.Credential, 0, len(tokens))
+	for _, token := range tokens {
+		credentials = append(credentials, apitoken.Credential{Name: token.Name, Token: token.Token, Scopes: token.Scopes})
+	}
+	return &tokenAuth{tokens: apitoken.NewSet(credentials)}
+}
+
+// require 包装处理函数，要求请求携带具有指定作用域的令牌
+// 缺少或无法识别令牌返回 401，令牌作用域不足返回 403
+func (a *tokenAuth) require(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	if a.tokens.Len() == 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := a.tokens.Lookup(r)
+		if token == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="casbinx-sidecar"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Code: core.ErrPermissionDenied.Code, Message: "缺少或无效的 API 令牌"})
+			return
+		}
+		if !token.Allows(scope) {
+			writeJSON(w, http.StatusForbidden, errorResponse{
+				Code:    core.ErrPermissionDenied.Code,
+				Message: fmt.Sprintf("令牌 %s 缺少作用域: %s", token.Name, scope),
+			})
+			return
+		}
+		next(w, r)
+	}
+}